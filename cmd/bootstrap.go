@@ -6,7 +6,9 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -46,7 +48,9 @@ global MCP in run mode. Use "taskwing doctor --fix" for explicit repair flows.
 
 Requires an LLM API key (set via 'taskwing config set' or provider-specific env var).
 
-Use --skip-analyze for CI/testing (deterministic, no LLM).`,
+Use --no-analyze for CI or offline setup: creates the project store and AI
+configs without an API key and skips LLM analysis.
+Use --json for a machine-readable summary on stdout (progress goes to stderr).`,
 	RunE: runBootstrap,
 }
 
@@ -57,14 +61,20 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	// PHASE 0: Parse and Validate Flags
 	// ═══════════════════════════════════════════════════════════════════════
 	onlyAgents, _ := cmd.Flags().GetStringSlice("only-agents")
+	includeDirs, _ := cmd.Flags().GetStringSlice("include-dir")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude-dir")
 	flags := bootstrap.Flags{
 		Preview:     getBoolFlag(cmd, "preview"),
 		SkipInit:    getBoolFlag(cmd, "skip-init"),
 		SkipIndex:   getBoolFlag(cmd, "skip-index"),
-		SkipAnalyze: getBoolFlag(cmd, "skip-analyze"),
+		SkipAnalyze: getBoolFlag(cmd, "no-analyze") || getBoolFlag(cmd, "skip-analyze"),
 		Force:       getBoolFlag(cmd, "force"),
 		Resume:      getBoolFlag(cmd, "resume"),
+		Since:       getStringFlag(cmd, "since"),
 		OnlyAgents:  onlyAgents,
+		GitSince:    getStringFlag(cmd, "git-since"),
+		IncludeDirs: includeDirs,
+		ExcludeDirs: excludeDirs,
 		Trace:       getBoolFlag(cmd, "trace"),
 		TraceStdout: getBoolFlag(cmd, "trace-stdout"),
 		TraceFile:   getStringFlag(cmd, "trace-file"),
@@ -78,6 +88,13 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid flags: %w", err)
 	}
 
+	// --json: stdout carries only the run summary; human output goes to stderr
+	start := time.Now()
+	var out io.Writer = os.Stdout
+	if isJSON() {
+		out = os.Stderr
+	}
+
 	// Handle --timeout flag: set TASKWING_LLM_TIMEOUT env var to override default
 	// This must be done before LLM client creation to ensure the timeout is picked up
 	if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout > 0 {
@@ -156,23 +173,26 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 
 	// Handle error mode early (before any output)
 	if plan.Mode == bootstrap.ModeError {
-		fmt.Print(bootstrap.FormatPlanSummary(plan, flags.Quiet))
+		fmt.Fprint(out, bootstrap.FormatPlanSummary(plan, flags.Quiet))
 		return plan.Error
 	}
 
 	// Handle NoOp mode early
 	if plan.Mode == bootstrap.ModeNoOp {
-		fmt.Print(bootstrap.FormatPlanSummary(plan, flags.Quiet))
+		fmt.Fprint(out, bootstrap.FormatPlanSummary(plan, flags.Quiet))
 		if !flags.Quiet {
-			fmt.Println("\n✅ Nothing to do - configuration is up to date.")
+			fmt.Fprintln(out, "\n✅ Nothing to do - configuration is up to date.")
+		}
+		if isJSON() {
+			return writeBootstrapSummary(os.Stdout, bootstrap.NewRunSummary(bootstrap.NodeCounts{}, bootstrap.NodeCounts{}, false, nil, time.Since(start)))
 		}
 		return nil
 	}
 
 	// Handle preview mode
 	if flags.Preview {
-		fmt.Print(bootstrap.FormatPlanSummary(plan, flags.Quiet))
-		fmt.Println("\n💡 Preview mode - no changes made.")
+		fmt.Fprint(out, bootstrap.FormatPlanSummary(plan, flags.Quiet))
+		fmt.Fprintln(out, "\n💡 Preview mode - no changes made.")
 		return nil
 	}
 
@@ -185,7 +205,7 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	if plan.RequiresLLMConfig {
 		llmCfg, err = getLLMConfigForRole(cmd, llm.RoleBootstrap)
 		if err != nil {
-			return fmt.Errorf("TaskWing requires an LLM API key to analyze your architecture.\nConfigure via 'taskwing config set' or set a provider-specific env var (e.g. TASKWING_API_KEY, OPENAI_API_KEY, ANTHROPIC_API_KEY, GOOGLE_API_KEY, BEDROCK_API_KEY).\nUse --skip-analyze for CI/testing without LLM: %w", err)
+			return fmt.Errorf("TaskWing requires an LLM API key to analyze your architecture.\nConfigure via 'taskwing config set' or set a provider-specific env var (e.g. TASKWING_API_KEY, OPENAI_API_KEY, ANTHROPIC_API_KEY, GOOGLE_API_KEY, BEDROCK_API_KEY).\nUse --no-analyze for CI/offline setup without LLM analysis: %w", err)
 		}
 	}
 
@@ -196,46 +216,133 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	}
 	svc := bootstrap.NewService(cwd, storePath, llmCfg)
 	svc.SetVersion(version)
+	svc.SetOutput(out)
+	gitHistory, _ := core.ParseGitHistoryWindow(flags.GitSince) // Validated with the other flags
+	svc.SetGitHistory(gitHistory)
+	svc.SetDirScope(flags.IncludeDirs, flags.ExcludeDirs)
+
+	var nodesBefore bootstrap.NodeCounts
+	if isJSON() {
+		// Best-effort: a store that can't be read counts as empty
+		nodesBefore, _ = svc.CountNodes()
+	}
 
 	// Prompt for repo selection in multi-repo workspaces.
 	// This must happen before the action loop because ActionInitProject may not
 	// be in the plan (e.g., ModeRun), but ActionLLMAnalyze still needs SelectedRepos.
 	if plan.RequiresRepoSelection && slices.Contains(plan.Actions, bootstrap.ActionLLMAnalyze) {
 		if ui.IsInteractive() {
-			fmt.Println()
-			fmt.Printf("📦 Found %d repositories\n\n", len(plan.DetectedRepos))
-			plan.SelectedRepos = promptRepoSelection(plan.DetectedRepos)
+			fmt.Fprintln(out)
+			fmt.Fprintf(out, "📦 Found %d repositories\n\n", len(plan.DetectedRepos))
+			plan.SelectedRepos = promptRepoSelection(out, plan.DetectedRepos)
 		} else {
 			plan.SelectedRepos = plan.DetectedRepos
 			if !flags.Quiet {
-				fmt.Printf("📦 Non-interactive mode: bootstrapping all %d repositories\n", len(plan.DetectedRepos))
+				fmt.Fprintf(out, "📦 Non-interactive mode: bootstrapping all %d repositories\n", len(plan.DetectedRepos))
 			}
 		}
 	}
 
 	// Show plan summary AFTER repo selection so it reflects the chosen scope
-	fmt.Print(bootstrap.FormatPlanSummary(plan, flags.Quiet))
+	fmt.Fprint(out, bootstrap.FormatPlanSummary(plan, flags.Quiet))
 
 	// Execute actions in order
 	for _, action := range plan.Actions {
-		if err := executeAction(cmd.Context(), action, svc, cwd, flags, plan, llmCfg); err != nil {
+		if err := executeAction(cmd.Context(), out, action, svc, cwd, flags, plan, llmCfg); err != nil {
+			return err
+		}
+	}
+
+	// Regenerate managed AI configs left behind by an older TaskWing version
+	var refreshed []string
+	if !flags.SkipInit {
+		refreshed, err = svc.RefreshOutdatedAIConfigs(flags.Verbose)
+		if err != nil {
+			return fmt.Errorf("refresh outdated AI configs: %w", err)
+		}
+		if len(refreshed) > 0 && !flags.Quiet {
+			fmt.Fprintf(out, "✓ Refreshed outdated AI configurations: %s\n", strings.Join(refreshed, ", "))
+		}
+		if err := offerRemovedAIPruning(out, svc, flags); err != nil {
 			return err
 		}
 	}
 
 	// Final success message
 	if !flags.Quiet {
-		fmt.Println()
-		fmt.Println("✅ Bootstrap complete!")
-		printPostBootstrapSummary()
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "✅ Bootstrap complete!")
+		printPostBootstrapSummary(out)
 	}
 
+	if isJSON() {
+		nodesAfter, err := svc.CountNodes()
+		if err != nil {
+			return fmt.Errorf("count knowledge nodes: %w", err)
+		}
+		structureCreated := slices.Contains(plan.Actions, bootstrap.ActionInitProject)
+		summary := bootstrap.NewRunSummary(nodesBefore, nodesAfter, structureCreated, configuredAIs(plan, refreshed), time.Since(start))
+		return writeBootstrapSummary(os.Stdout, summary)
+	}
 	return nil
 }
 
+// offerRemovedAIPruning asks to delete managed configs left behind for AIs
+// TaskWing no longer supports. Non-interactive runs only report them.
+func offerRemovedAIPruning(out io.Writer, svc *bootstrap.Service, flags bootstrap.Flags) error {
+	stale := svc.RemovedAIConfigs()
+	if len(stale) == 0 {
+		return nil
+	}
+	if !ui.IsInteractive() || isJSON() || flags.Quiet {
+		if !flags.Quiet {
+			fmt.Fprintf(out, "⚠️  Found TaskWing configs for unsupported AIs: %s (run bootstrap interactively to remove)\n", strings.Join(stale, ", "))
+		}
+		return nil
+	}
+	prompt := fmt.Sprintf("🧹 TaskWing no longer supports %s. Remove their managed configs? [y/N]: ", strings.Join(stale, ", "))
+	if !confirmOrAbort(prompt) {
+		return nil
+	}
+	pruned, err := svc.PruneRemovedAIConfigs(flags.Verbose)
+	if err != nil {
+		return fmt.Errorf("remove unsupported AI configs: %w", err)
+	}
+	fmt.Fprintf(out, "✓ Removed configs for unsupported AIs: %s\n", strings.Join(pruned, ", "))
+	return nil
+}
+
+// configuredAIs lists the AI integrations a bootstrap run wrote: those set up
+// or repaired by the plan's actions plus outdated configs that were refreshed.
+func configuredAIs(plan *bootstrap.Plan, refreshed []string) []string {
+	var ais []string
+	switch {
+	case len(plan.SelectedAIs) > 0:
+		ais = append(ais, plan.SelectedAIs...)
+	case slices.Contains(plan.Actions, bootstrap.ActionGenerateAIConfigs):
+		ais = append(ais, plan.AIsNeedingRepair...)
+	}
+	for _, ai := range refreshed {
+		if !slices.Contains(ais, ai) {
+			ais = append(ais, ai)
+		}
+	}
+	return ais
+}
+
+// writeBootstrapSummary writes the --json run summary to w.
+func writeBootstrapSummary(w io.Writer, summary bootstrap.RunSummary) error {
+	output, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
+
 // printPostBootstrapSummary shows a compact knowledge summary after bootstrap
 // so users immediately see what was extracted from their codebase.
-func printPostBootstrapSummary() {
+func printPostBootstrapSummary(out io.Writer) {
 	repo, err := openRepo()
 	if err != nil {
 		return // non-fatal: skip summary if repo can't be opened
@@ -278,33 +385,33 @@ func printPostBootstrapSummary() {
 		}
 	}
 
-	fmt.Printf("\n   Knowledge: %d nodes (%s)\n", len(nodes), strings.Join(stats, ", "))
-	fmt.Println("   Run 'taskwing knowledge' to explore, or use the ask MCP tool in your AI tool.")
+	fmt.Fprintf(out, "\n   Knowledge: %d nodes (%s)\n", len(nodes), strings.Join(stats, ", "))
+	fmt.Fprintln(out, "   Run 'taskwing knowledge' to explore, or use the ask MCP tool in your AI tool.")
 }
 
 // executeAction executes a single bootstrap action.
-func executeAction(ctx context.Context, action bootstrap.Action, svc *bootstrap.Service, cwd string, flags bootstrap.Flags, plan *bootstrap.Plan, llmCfg llm.Config) error {
+func executeAction(ctx context.Context, out io.Writer, action bootstrap.Action, svc *bootstrap.Service, cwd string, flags bootstrap.Flags, plan *bootstrap.Plan, llmCfg llm.Config) error {
 	switch action {
 	case bootstrap.ActionInitProject:
-		if err := executeInitProject(svc, flags, plan); err != nil {
+		if err := executeInitProject(out, svc, flags, plan); err != nil {
 			return err
 		}
 		return nil
 
 	case bootstrap.ActionGenerateAIConfigs:
-		return executeGenerateAIConfigs(svc, flags, plan)
+		return executeGenerateAIConfigs(out, svc, flags, plan)
 
 	case bootstrap.ActionInstallMCP:
-		return executeInstallMCP(cwd, flags, plan)
+		return executeInstallMCP(out, cwd, flags, plan)
 
 	case bootstrap.ActionIndexCode:
-		return executeIndexCode(ctx, cwd, flags)
+		return executeIndexCode(ctx, out, cwd, flags)
 
 	case bootstrap.ActionExtractMetadata:
 		return executeExtractMetadata(ctx, svc, flags)
 
 	case bootstrap.ActionLLMAnalyze:
-		return executeLLMAnalyze(ctx, svc, cwd, flags, llmCfg, plan)
+		return executeLLMAnalyze(ctx, out, svc, cwd, flags, llmCfg, plan)
 
 	default:
 		return fmt.Errorf("unknown action: %s", action)
@@ -312,7 +419,7 @@ func executeAction(ctx context.Context, action bootstrap.Action, svc *bootstrap.
 }
 
 // executeInitProject handles project initialization with user prompts.
-func executeInitProject(svc *bootstrap.Service, flags bootstrap.Flags, plan *bootstrap.Plan) error {
+func executeInitProject(out io.Writer, svc *bootstrap.Service, flags bootstrap.Flags, plan *bootstrap.Plan) error {
 	var selectedAIs []string
 
 	if plan.RequiresUserInput {
@@ -326,9 +433,9 @@ func executeInitProject(svc *bootstrap.Service, flags bootstrap.Flags, plan *boo
 			}
 			if !flags.Quiet {
 				if len(selectedAIs) > 0 {
-					fmt.Printf("🤖 Non-interactive mode: configuring AI integrations for %s\n", strings.Join(selectedAIs, ", "))
+					fmt.Fprintf(out, "🤖 Non-interactive mode: configuring AI integrations for %s\n", strings.Join(selectedAIs, ", "))
 				} else {
-					fmt.Println("🤖 Non-interactive mode: no AI assistant selected; initializing project memory only")
+					fmt.Fprintln(out, "🤖 Non-interactive mode: no AI assistant selected; initializing project memory only")
 				}
 			}
 		} else {
@@ -336,43 +443,43 @@ func executeInitProject(svc *bootstrap.Service, flags bootstrap.Flags, plan *boo
 			switch plan.Mode {
 			case bootstrap.ModeFirstTime:
 				if len(plan.SuggestedAIs) > 0 {
-					fmt.Println("📋 Setting up local project")
-					fmt.Printf("🔍 Detected global config for: %s\n", strings.Join(plan.SuggestedAIs, ", "))
+					fmt.Fprintln(out, "📋 Setting up local project")
+					fmt.Fprintf(out, "🔍 Detected global config for: %s\n", strings.Join(plan.SuggestedAIs, ", "))
 				} else {
-					fmt.Println("🚀 First time setup")
+					fmt.Fprintln(out, "🚀 First time setup")
 				}
-				fmt.Println()
-				fmt.Println("🤖 Which AI assistant(s) do you use?")
-				fmt.Println()
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "🤖 Which AI assistant(s) do you use?")
+				fmt.Fprintln(out)
 				selectedAIs = promptAISelection(plan.SuggestedAIs...)
 
 			case bootstrap.ModeRepair:
 				if len(plan.AIsNeedingRepair) > 0 {
-					fmt.Println("🔧 Restoring missing AI configurations")
-					fmt.Printf("   Missing: %s\n", strings.Join(plan.AIsNeedingRepair, ", "))
-					fmt.Print("   Restore? [Y/n]: ")
+					fmt.Fprintln(out, "🔧 Restoring missing AI configurations")
+					fmt.Fprintf(out, "   Missing: %s\n", strings.Join(plan.AIsNeedingRepair, ", "))
+					fmt.Fprint(out, "   Restore? [Y/n]: ")
 					var input string
 					_, _ = fmt.Scanln(&input)
 					input = strings.TrimSpace(strings.ToLower(input))
 					if input == "" || input == "y" || input == "yes" {
 						selectedAIs = plan.AIsNeedingRepair
 					} else {
-						fmt.Println()
-						fmt.Println("🤖 Which AI assistant(s) do you want to set up?")
+						fmt.Fprintln(out)
+						fmt.Fprintln(out, "🤖 Which AI assistant(s) do you want to set up?")
 						selectedAIs = promptAISelection(plan.SuggestedAIs...)
 					}
 				}
 
 			case bootstrap.ModeReconfigure:
-				fmt.Println("🔧 No AI configurations found - let's set them up")
-				fmt.Println()
-				fmt.Println("🤖 Which AI assistant(s) do you use?")
-				fmt.Println()
+				fmt.Fprintln(out, "🔧 No AI configurations found - let's set them up")
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "🤖 Which AI assistant(s) do you use?")
+				fmt.Fprintln(out)
 				selectedAIs = promptAISelection()
 			}
 		}
 		if len(selectedAIs) == 0 && !flags.Quiet {
-			fmt.Println("\n⚠️  No AI assistants selected - continuing with local project initialization only")
+			fmt.Fprintln(out, "\n⚠️  No AI assistants selected - continuing with local project initialization only")
 		}
 	}
 
@@ -384,13 +491,13 @@ func executeInitProject(svc *bootstrap.Service, flags bootstrap.Flags, plan *boo
 		return fmt.Errorf("initialization failed: %w", err)
 	}
 
-	fmt.Println("✓ Project initialized")
+	fmt.Fprintln(out, "✓ Project initialized")
 	return nil
 }
 
 // executeGenerateAIConfigs generates AI slash commands and hooks.
 // This runs standalone when ActionInitProject isn't in the plan (e.g., ModeRepair with healthy project).
-func executeGenerateAIConfigs(svc *bootstrap.Service, flags bootstrap.Flags, plan *bootstrap.Plan) error {
+func executeGenerateAIConfigs(out io.Writer, svc *bootstrap.Service, flags bootstrap.Flags, plan *bootstrap.Plan) error {
 	// Determine which AIs to configure
 	var targetAIs []string
 	if len(plan.SelectedAIs) > 0 {
@@ -412,13 +519,13 @@ func executeGenerateAIConfigs(svc *bootstrap.Service, flags bootstrap.Flags, pla
 	}
 
 	if !flags.Quiet {
-		fmt.Printf("✓ AI configurations updated: %s\n", strings.Join(targetAIs, ", "))
+		fmt.Fprintf(out, "✓ AI configurations updated: %s\n", strings.Join(targetAIs, ", "))
 	}
 	return nil
 }
 
 // executeInstallMCP registers MCP servers with AI CLIs.
-func executeInstallMCP(cwd string, flags bootstrap.Flags, plan *bootstrap.Plan) error {
+func executeInstallMCP(out io.Writer, cwd string, flags bootstrap.Flags, plan *bootstrap.Plan) error {
 	// Determine which AIs need MCP registration
 	var targetAIs []string
 	if len(plan.SelectedAIs) > 0 {
@@ -448,26 +555,26 @@ func executeInstallMCP(cwd string, flags bootstrap.Flags, plan *bootstrap.Plan)
 
 	if len(aisNeedingRegistration) == 0 {
 		if !flags.Quiet && len(existingGlobalAIs) > 0 {
-			fmt.Printf("✓ MCP already configured globally for: %s\n", strings.Join(existingGlobalAIs, ", "))
+			fmt.Fprintf(out, "✓ MCP already configured globally for: %s\n", strings.Join(existingGlobalAIs, ", "))
 		}
 		return nil
 	}
 
 	if !flags.Quiet {
-		fmt.Printf("🔌 Installing MCP servers for: %s\n", strings.Join(aisNeedingRegistration, ", "))
+		fmt.Fprintf(out, "🔌 Installing MCP servers for: %s\n", strings.Join(aisNeedingRegistration, ", "))
 	}
 
-	installMCPServers(cwd, aisNeedingRegistration)
+	installMCPServers(out, cwd, aisNeedingRegistration)
 
 	if !flags.Quiet {
-		fmt.Println("✓ MCP servers installed")
+		fmt.Fprintln(out, "✓ MCP servers installed")
 	}
 	return nil
 }
 
 // executeIndexCode runs code symbol indexing.
-func executeIndexCode(ctx context.Context, cwd string, flags bootstrap.Flags) error {
-	if err := runCodeIndexing(ctx, cwd, flags.Force, flags.Quiet); err != nil {
+func executeIndexCode(ctx context.Context, out io.Writer, cwd string, flags bootstrap.Flags) error {
+	if err := runCodeIndexing(ctx, out, cwd, flags.Force, flags.Quiet); err != nil {
 		// Non-fatal: log and continue
 		if !flags.Quiet {
 			fmt.Fprintf(os.Stderr, "⚠️  Code indexing failed: %v\n", err)
@@ -492,7 +599,7 @@ func executeExtractMetadata(ctx context.Context, svc *bootstrap.Service, flags b
 }
 
 // executeLLMAnalyze runs LLM-powered deep analysis.
-func executeLLMAnalyze(ctx context.Context, svc *bootstrap.Service, cwd string, flags bootstrap.Flags, llmCfg llm.Config, plan *bootstrap.Plan) error {
+func executeLLMAnalyze(ctx context.Context, out io.Writer, svc *bootstrap.Service, cwd string, flags bootstrap.Flags, llmCfg llm.Config, plan *bootstrap.Plan) error {
 	// Detect workspace type
 	ws, err := project.DetectWorkspace(cwd)
 	if err != nil {
@@ -505,11 +612,53 @@ func executeLLMAnalyze(ctx context.Context, svc *bootstrap.Service, cwd string,
 		if len(plan.SelectedRepos) > 0 {
 			ws.Services = plan.SelectedRepos
 		}
-		return runMultiRepoBootstrap(ctx, svc, ws, flags.Preview)
+		return runMultiRepoBootstrap(ctx, out, svc, ws, flags.Preview)
+	}
+
+	// Incremental mode: analyze only files changed since the given ref
+	if flags.Since != "" {
+		return runIncrementalBootstrap(ctx, out, svc, cwd, llmCfg, flags)
+	}
+
+	// Interrupted bootstrap: re-run only the agents that did not complete
+	if plan.ResumeAnalysis {
+		flags.Resume = true
 	}
 
 	// Run agent TUI flow with LLM analysis
-	return runAgentTUI(ctx, svc, cwd, llmCfg, flags)
+	return runAgentTUI(ctx, out, svc, cwd, llmCfg, flags)
+}
+
+// runIncrementalBootstrap analyzes files changed since --since and merges the
+// findings into existing knowledge instead of re-analyzing the whole project.
+func runIncrementalBootstrap(ctx context.Context, out io.Writer, svc *bootstrap.Service, cwd string, llmCfg llm.Config, flags bootstrap.Flags) error {
+	scope, err := svc.ResolveIncrementalScope(flags.Since)
+	if err != nil {
+		return fmt.Errorf("incremental bootstrap: %w", err)
+	}
+
+	runner := bootstrap.NewRunner(llmCfg, cwd)
+	defer runner.Close()
+
+	if !flags.Quiet {
+		fmt.Fprintf(out, "\n🔄 Incremental bootstrap: %d file(s) changed since %s\n", len(scope.ChangedFiles), shortRef(scope.BaseRef))
+	}
+	results, err := svc.RunIncremental(ctx, runner, scope, flags.Quiet)
+	if err != nil {
+		return err
+	}
+	if results == nil && !flags.Quiet {
+		fmt.Fprintln(out, "   No analyzable changes - analysis skipped.")
+	}
+	return nil
+}
+
+// shortRef abbreviates a full commit SHA for display.
+func shortRef(ref string) string {
+	if len(ref) == 40 {
+		return ref[:8]
+	}
+	return ref
 }
 
 // Helper functions for flag parsing
@@ -528,9 +677,15 @@ func init() {
 	bootstrapCmd.Flags().Bool("skip-init", false, "Skip initialization prompt")
 	bootstrapCmd.Flags().Bool("skip-index", false, "Skip code indexing (symbol extraction)")
 	bootstrapCmd.Flags().Bool("force", false, "Force indexing even for large codebases (>5000 files)")
-	bootstrapCmd.Flags().Bool("skip-analyze", false, "Skip LLM analysis (for CI/testing)")
+	bootstrapCmd.Flags().Bool("no-analyze", false, "Set up project store and AI configs without LLM analysis (no API key needed)")
+	bootstrapCmd.Flags().Bool("skip-analyze", false, "Alias for --no-analyze")
 	bootstrapCmd.Flags().Bool("resume", false, "Resume from last checkpoint (skip completed agents)")
+	bootstrapCmd.Flags().String("since", "", "Analyze only files changed since a git ref (bare --since uses the last bootstrap)")
+	bootstrapCmd.Flags().Lookup("since").NoOptDefVal = bootstrap.SinceLastBootstrap
 	bootstrapCmd.Flags().StringSlice("only-agents", nil, "Run only specified agents (e.g., --only-agents=code,doc)")
+	bootstrapCmd.Flags().String("git-since", "", "Limit git history analysis to a time window (e.g., 90d, 12w, 720h) or a commit count (e.g., 500)")
+	bootstrapCmd.Flags().StringSlice("include-dir", nil, "Limit doc analysis to these directories, relative to the project (repeatable)")
+	bootstrapCmd.Flags().StringSlice("exclude-dir", nil, "Skip these directories during doc analysis, relative to the project (repeatable)")
 	bootstrapCmd.Flags().Bool("trace", false, "Emit JSON event stream to stderr")
 	bootstrapCmd.Flags().String("trace-file", "", "Write JSON event stream to file (default: ~/.taskwing/projects/<slug>/logs/bootstrap.trace.jsonl)")
 	bootstrapCmd.Flags().Bool("trace-stdout", false, "Emit JSON event stream to stderr (overrides trace file)")
@@ -538,17 +693,16 @@ func init() {
 	bootstrapCmd.Flags().Duration("timeout", 0, "LLM request timeout (e.g., 5m, 10m). Overrides TASKWING_LLM_TIMEOUT env var. Default: 5m")
 
 	// Hide internal flags from main help (documented in CLAUDE.md / finetune docs)
-	_ = bootstrapCmd.Flags().MarkHidden("skip-analyze")
+	_ = bootstrapCmd.Flags().MarkHidden("skip-analyze") // Kept for existing CI scripts
 }
 
 // runAgentTUI handles the interactive UI part, delegating work to the service
 // runBatchBootstrap uses the OpenAI Batch API for 50% cost reduction.
 // Batchable agents have their prompts collected and submitted as a single batch.
-func runAgentTUI(ctx context.Context, svc *bootstrap.Service, cwd string, llmCfg llm.Config, flags bootstrap.Flags) error {
-	fmt.Println("")
-	ui.RenderPageHeader("TaskWing Bootstrap", fmt.Sprintf("Using: %s (%s)", llmCfg.Model, llmCfg.Provider))
+func runAgentTUI(ctx context.Context, out io.Writer, svc *bootstrap.Service, cwd string, llmCfg llm.Config, flags bootstrap.Flags) error {
+	fmt.Fprintln(out, "")
+	ui.RenderPageHeaderTo(out, "TaskWing Bootstrap", fmt.Sprintf("Using: %s (%s)", llmCfg.Model, llmCfg.Provider))
 
-	projectName := filepath.Base(cwd)
 	allAgents := bootstrap.NewDefaultAgents(llmCfg, cwd, nil)
 	defer core.CloseAgents(allAgents)
 
@@ -567,23 +721,18 @@ func runAgentTUI(ctx context.Context, svc *bootstrap.Service, cwd string, llmCfg
 
 	// Show skipped agents
 	if len(skippedAgents) > 0 && !flags.Quiet {
-		fmt.Printf("⏭️  Skipping completed agents: %s\n", strings.Join(skippedAgents, ", "))
+		fmt.Fprintf(out, "⏭️  Skipping completed agents: %s\n", strings.Join(skippedAgents, ", "))
 	}
 
 	// If all agents were skipped, nothing to do
 	if len(agentsList) == 0 {
 		if !flags.Quiet {
-			fmt.Println("✅ All agents already completed. Use 'bootstrap' without --resume to re-run.")
+			fmt.Fprintln(out, "✅ All agents already completed. Use 'bootstrap' without --resume to re-run.")
 		}
 		return nil
 	}
 
-	input := core.Input{
-		BasePath:    cwd,
-		ProjectName: projectName,
-		Mode:        core.ModeBootstrap,
-		Verbose:     flags.Verbose || flags.Debug,
-	}
+	input := svc.AgentInput(cwd, flags.Verbose || flags.Debug)
 
 	stream := core.NewStreamingOutput(100)
 	defer stream.Close()
@@ -593,7 +742,7 @@ func runAgentTUI(ctx context.Context, svc *bootstrap.Service, cwd string, llmCfg
 
 	// Run TUI
 	tuiModel := ui.NewBootstrapModel(ctx, input, agentsList, stream)
-	programOptions := []tea.ProgramOption{}
+	programOptions := []tea.ProgramOption{tea.WithOutput(out)}
 	if !ui.IsInteractive() {
 		// Headless fallback for CI/non-TTY environments.
 		programOptions = append(programOptions, tea.WithInput(nil), tea.WithoutRenderer())
@@ -606,28 +755,14 @@ func runAgentTUI(ctx context.Context, svc *bootstrap.Service, cwd string, llmCfg
 
 	bootstrapModel, ok := finalModel.(ui.BootstrapModel)
 	if !ok || (bootstrapModel.Quitting && len(bootstrapModel.Results) < len(agentsList)) {
-		fmt.Println("\n⚠️  Bootstrap cancelled.")
+		fmt.Fprintln(out, "\n⚠️  Bootstrap cancelled.")
 		return nil
 	}
 
-	// Update checkpoint state for completed agents
-	if repo != nil {
-		store := repo.GetDB()
-		if store != nil {
-			updateAgentCheckpoints(bootstrapModel.Agents, store)
-		}
-	}
-
-	// Check failures
-	if err := checkAgentFailures(bootstrapModel.Agents); err != nil {
-		return err
-	}
-
-	// Delegate processing/saving to service
-	allFindings := core.AggregateFindings(bootstrapModel.Results)
-	allRelationships := core.AggregateRelationships(bootstrapModel.Results)
-
-	return svc.ProcessAndSaveResults(ctx, bootstrapModel.Results, allFindings, allRelationships, flags.Preview, viper.GetBool("quiet"))
+	// Save findings from successful agents and checkpoint every agent, so a
+	// re-run resumes only the agents that failed
+	failures := agentFailures(bootstrapModel.Agents)
+	return svc.SaveAnalysis(ctx, bootstrapModel.Results, failures, flags.Preview, viper.GetBool("quiet"))
 }
 
 // filterAgents filters agents based on resume state and --only-agents flag.
@@ -672,63 +807,38 @@ func filterAgents(agents []core.Agent, flags bootstrap.Flags, repo *memory.Repos
 	return filtered, skipped
 }
 
-// updateAgentCheckpoints updates the bootstrap_state table based on agent results.
-func updateAgentCheckpoints(agents []*ui.AgentState, store *memory.SQLiteStore) {
-	for _, agent := range agents {
-		state := &memory.BootstrapState{
-			Component: agent.Name,
-		}
-
-		switch agent.Status {
-		case ui.StatusDone:
-			state.Status = memory.BootstrapStatusCompleted
-			if agent.Result != nil {
-				state.Metadata = map[string]any{
-					"findings_count": len(agent.Result.Findings),
-					"duration_ms":    agent.Result.Duration.Milliseconds(),
-				}
-			}
-		case ui.StatusError:
-			state.Status = memory.BootstrapStatusFailed
-			if agent.Err != nil {
-				state.ErrorMessage = agent.Err.Error()
-			}
-		default:
-			state.Status = memory.BootstrapStatusPending
-		}
-
-		_ = store.SetBootstrapState(state)
-	}
-}
-
 // runMultiRepoBootstrap uses the service to analyze multiple repos
-func runMultiRepoBootstrap(ctx context.Context, svc *bootstrap.Service, ws *project.WorkspaceInfo, preview bool) error {
-	fmt.Println("")
-	ui.RenderPageHeader("TaskWing Multi-Repo Bootstrap", fmt.Sprintf("Workspace: %s | Services: %d", ws.Name, ws.ServiceCount()))
+func runMultiRepoBootstrap(ctx context.Context, out io.Writer, svc *bootstrap.Service, ws *project.WorkspaceInfo, preview bool) error {
+	fmt.Fprintln(out, "")
+	ui.RenderPageHeaderTo(out, "TaskWing Multi-Repo Bootstrap", fmt.Sprintf("Workspace: %s | Services: %d", ws.Name, ws.ServiceCount()))
 
-	fmt.Printf("📦 Analyzing %d services...\n", ws.ServiceCount())
+	fmt.Fprintf(out, "📦 Analyzing %d services...\n", ws.ServiceCount())
 
-	findings, relationships, errs, err := svc.RunMultiRepoAnalysis(ctx, ws, func(name, status string) {
-		fmt.Printf("  %s: %s\n", name, status)
+	analyses, errs, err := svc.RunMultiRepoAnalysis(ctx, ws, func(name, status string) {
+		fmt.Fprintf(out, "  %s: %s\n", name, status)
 	})
 	if err != nil {
 		return err
 	}
 
 	if len(errs) > 0 {
-		fmt.Println("\n⚠️  Some services had errors:")
+		fmt.Fprintln(out, "\n⚠️  Some services had errors:")
 		for _, e := range errs {
-			fmt.Printf("   - %s\n", e)
+			fmt.Fprintf(out, "   - %s\n", e)
 		}
 	}
 
 	if preview {
-		fmt.Printf("\n📊 Preview: %d findings from %d services\n", len(findings), ws.ServiceCount()-len(errs))
-		fmt.Println("💡 This was a preview. Run 'taskwing bootstrap' to save to memory.")
+		findings := 0
+		for _, a := range analyses {
+			findings += len(a.Findings)
+		}
+		fmt.Fprintf(out, "\n📊 Preview: %d findings from %d services\n", findings, ws.ServiceCount()-len(errs))
+		fmt.Fprintln(out, "💡 This was a preview. Run 'taskwing bootstrap' to save to memory.")
 		return nil
 	}
 
-	if err := svc.IngestDirectly(ctx, findings, relationships, viper.GetBool("quiet")); err != nil {
+	if err := svc.IngestServices(ctx, analyses, viper.GetBool("quiet")); err != nil {
 		return err
 	}
 
@@ -738,21 +848,21 @@ func runMultiRepoBootstrap(ctx context.Context, svc *bootstrap.Service, ws *proj
 
 // promptRepoSelection prompts the user to select which repositories to bootstrap.
 // Returns all repos on error or cancel to avoid silent no-op.
-func promptRepoSelection(repos []string) []string {
+func promptRepoSelection(out io.Writer, repos []string) []string {
 	selected, err := ui.PromptRepoSelection(repos)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "⚠️  Repo selection failed: %v — analyzing all repositories\n", err)
 		return repos
 	}
 	if selected == nil {
-		fmt.Println("⚠️  Selection cancelled — analyzing all repositories")
+		fmt.Fprintln(out, "⚠️  Selection cancelled — analyzing all repositories")
 		return repos
 	}
 	return selected
 }
 
 // installMCPServers handles the binary installation calls (kept in CLI layer)
-func installMCPServers(basePath string, selectedAIs []string) {
+func installMCPServers(out io.Writer, basePath string, selectedAIs []string) {
 	binPath, _ := os.Executable()
 	if absPath, err := filepath.Abs(binPath); err == nil {
 		binPath = filepath.Clean(absPath)
@@ -760,21 +870,21 @@ func installMCPServers(basePath string, selectedAIs []string) {
 	for _, ai := range selectedAIs {
 		switch ai {
 		case "claude":
-			installClaude(binPath, basePath)
+			installClaude(out, binPath, basePath)
 		case "gemini":
-			if err := installGeminiCLI(binPath, basePath); err != nil {
-				fmt.Printf("⚠️  Gemini MCP install failed: %v\n", err)
+			if err := installGeminiCLI(out, binPath, basePath); err != nil {
+				fmt.Fprintf(out, "⚠️  Gemini MCP install failed: %v\n", err)
 			}
 		case "codex":
-			installCodexGlobal(binPath, basePath)
+			installCodexGlobal(out, binPath, basePath)
 		case "cursor":
-			installLocalMCP(basePath, ".cursor", "mcp.json", binPath)
+			installLocalMCP(out, basePath, ".cursor", "mcp.json", binPath)
 		case "copilot":
-			installCopilot(binPath, basePath)
+			installCopilot(out, binPath, basePath)
 		case "opencode":
 			// OpenCode: creates opencode.json at project root
 			// During development, use taskwing-local-dev-mcp for testing changes
-			if err := installOpenCode(binPath, basePath); err != nil {
+			if err := installOpenCode(out, binPath, basePath); err != nil {
 				fmt.Fprintf(os.Stderr, "⚠️  OpenCode MCP installation failed: %v\n", err)
 			}
 		}
@@ -834,30 +944,32 @@ func setupTrace(stream *core.StreamingOutput, trace bool, traceFile string, trac
 	return cleanup
 }
 
-func checkAgentFailures(agents []*ui.AgentState) error {
+// agentFailures reports errored agents and returns their errors keyed by name.
+func agentFailures(agents []*ui.AgentState) map[string]error {
+	failures := make(map[string]error)
 	var failedAgents []string
 	for _, state := range agents {
 		if state.Status == ui.StatusError || state.Err != nil {
-			errMsg := "unknown error"
-			if state.Err != nil {
-				errMsg = state.Err.Error()
+			err := state.Err
+			if err == nil {
+				err = errors.New("unknown error")
 			}
-			failedAgents = append(failedAgents, fmt.Sprintf("%s: %s", state.Name, errMsg))
+			failures[state.Name] = err
+			failedAgents = append(failedAgents, fmt.Sprintf("%s: %s", state.Name, err.Error()))
 		}
 	}
 	if len(failedAgents) > 0 {
-		fmt.Fprintln(os.Stderr, "\n✗ Bootstrap failed. Some agents errored:")
+		fmt.Fprintln(os.Stderr, "\n✗ Some agents errored (successful findings are still saved):")
 		for _, line := range failedAgents {
 			fmt.Fprintf(os.Stderr, "  - %s\n", line)
 		}
-		return fmt.Errorf("bootstrap failed: %d agent(s) errored", len(failedAgents))
 	}
-	return nil
+	return failures
 }
 
 // runCodeIndexing runs the code intelligence indexer on the codebase.
 // This extracts symbols (functions, types, etc.) for enhanced search and MCP ask.
-func runCodeIndexing(ctx context.Context, basePath string, forceIndex, isQuiet bool) error {
+func runCodeIndexing(ctx context.Context, out io.Writer, basePath string, forceIndex, isQuiet bool) error {
 	// Open repository to get database handle
 	repo, err := openRepo()
 	if err != nil {
@@ -877,8 +989,10 @@ func runCodeIndexing(ctx context.Context, basePath string, forceIndex, isQuiet b
 
 	// Create code intelligence repository and indexer
 	codeRepo := codeintel.NewRepository(db)
-	config := codeintel.DefaultIndexerConfig()
-	indexer := codeintel.NewIndexer(codeRepo, config)
+	indexerCfg := codeintel.DefaultIndexerConfig()
+	indexerCfg.ExcludeFilePatterns = config.LoadExcludeFilePatterns()
+	indexerCfg.GitBlame = config.LoadIndexGitBlame()
+	indexer := codeintel.NewIndexer(codeRepo, indexerCfg)
 
 	// Count files first for safety check
 	fileCount, err := indexer.CountSupportedFiles(basePath)
@@ -892,25 +1006,25 @@ func runCodeIndexing(ctx context.Context, basePath string, forceIndex, isQuiet b
 	// Large codebase safety check
 	const maxFilesWithoutForce = 5000
 	if fileCount > maxFilesWithoutForce && !forceIndex {
-		fmt.Println()
-		fmt.Printf("⚠️  Large codebase detected: %d files to index\n", fileCount)
-		fmt.Printf("   This may take a while and consume resources.\n")
-		fmt.Printf("   Run with --force to proceed, or use --skip-index to bypass.\n")
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "⚠️  Large codebase detected: %d files to index\n", fileCount)
+		fmt.Fprintf(out, "   This may take a while and consume resources.\n")
+		fmt.Fprintf(out, "   Run with --force to proceed, or use --skip-index to bypass.\n")
 		return nil // Not an error, just skip
 	}
 
 	// Print header
 	if !isQuiet {
-		fmt.Println()
-		fmt.Println("📇 Code Intelligence Indexing")
-		fmt.Println("────────────────────────────")
-		fmt.Printf("   🔍 Scanning %d source files...\n", fileCount)
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "📇 Code Intelligence Indexing")
+		fmt.Fprintln(out, "────────────────────────────")
+		fmt.Fprintf(out, "   🔍 Scanning %d source files...\n", fileCount)
 	}
 
 	// Configure progress callback with more detail
 	var lastUpdate time.Time
 	if !isQuiet {
-		config.OnProgress = func(stats codeintel.IndexStats) {
+		indexerCfg.OnProgress = func(stats codeintel.IndexStats) {
 			// Throttle updates to avoid flickering
 			if time.Since(lastUpdate) < 100*time.Millisecond {
 				return
@@ -925,7 +1039,7 @@ func runCodeIndexing(ctx context.Context, basePath string, forceIndex, isQuiet b
 	}
 
 	// Re-create indexer with updated config (for progress callback)
-	indexer = codeintel.NewIndexer(codeRepo, config)
+	indexer = codeintel.NewIndexer(codeRepo, indexerCfg)
 
 	// Run indexing
 	start := time.Now()
@@ -950,13 +1064,13 @@ func runCodeIndexing(ctx context.Context, basePath string, forceIndex, isQuiet b
 	if !isQuiet {
 		fmt.Fprintf(os.Stderr, "\r                                                        \n")
 		duration := time.Since(start)
-		fmt.Printf("   ✅ Indexed %d updates, pruned %d files in %v\n",
+		fmt.Fprintf(out, "   ✅ Indexed %d updates, pruned %d files in %v\n",
 			stats.FilesIndexed, prunedCount, duration.Round(time.Millisecond))
 		if stats.RelationsFound > 0 {
-			fmt.Printf("   🔗 Discovered %d call relationships\n", stats.RelationsFound)
+			fmt.Fprintf(out, "   🔗 Discovered %d call relationships\n", stats.RelationsFound)
 		}
 		if len(stats.Errors) > 0 {
-			fmt.Printf("   ⚠️  %d files skipped (parse errors)\n", len(stats.Errors))
+			fmt.Fprintf(out, "   ⚠️  %d files skipped (parse errors)\n", len(stats.Errors))
 		}
 	}
 
@@ -211,15 +211,25 @@ func getLLMConfig(cmd *cobra.Command) (llm.Config, error) {
 	if err != nil {
 		return llm.Config{}, err
 	}
+	agentTimeout, err := config.ResolveAgentTimeout()
+	if err != nil {
+		return llm.Config{}, err
+	}
+	requestsPerMinute, err := config.ResolveRequestsPerMinute()
+	if err != nil {
+		return llm.Config{}, err
+	}
 
 	return llm.Config{
-		Provider:       llmProvider,
-		Model:          model,
-		EmbeddingModel: embeddingModel,
-		APIKey:         apiKey,
-		BaseURL:        baseURL,
-		ThinkingBudget: thinkingBudget,
-		Timeout:        timeout,
+		Provider:          llmProvider,
+		Model:             model,
+		EmbeddingModel:    embeddingModel,
+		APIKey:            apiKey,
+		BaseURL:           baseURL,
+		ThinkingBudget:    thinkingBudget,
+		Timeout:           timeout,
+		AgentTimeout:      agentTimeout,
+		RequestsPerMinute: requestsPerMinute,
 	}, nil
 }
 
@@ -28,6 +28,7 @@ Checks:
   • MCP server registration for AI tools
   • Hooks configuration for autonomous execution
   • Active plan and task status
+  • Memory integrity (orphaned tasks/phases, search index sync)
   • Session state
 
 Use this to troubleshoot issues or verify setup after bootstrap.
@@ -87,10 +88,13 @@ func evaluateDoctorState(cwd string) ([]DoctorCheck, map[string]bootstrap.Integr
 	// Check 2: Active plan
 	checks = append(checks, checkActivePlan())
 
-	// Check 3: Session state
+	// Check 3: Memory integrity (orphans, duplicate active plans, FTS sync)
+	checks = append(checks, checkMemoryIntegrity())
+
+	// Check 4: Session state
 	checks = append(checks, checkSession())
 
-	// Check 4: Shared integration evaluator (source of truth for bootstrap + doctor repair)
+	// Check 5: Shared integration evaluator (source of truth for bootstrap + doctor repair)
 	globalMap := makeGlobalMCPMap(detectExistingMCPConfigs())
 	reports := bootstrap.EvaluateIntegrations(cwd, globalMap)
 	checks = append(checks, checksFromIntegrationReports(reports)...)
@@ -395,9 +399,9 @@ func applyRepairPrimitive(primitive, aiName, cwd, binPath string, init *bootstra
 		}
 		return init.InstallHooksConfig("opencode", viper.GetBool("verbose"))
 	case "repairLocalMCP":
-		return installMCPForTarget(aiName, binPath, cwd)
+		return installMCPForTarget(os.Stdout, aiName, binPath, cwd)
 	case "repairGlobalMCP":
-		return installMCPForTarget(aiName, binPath, cwd)
+		return installMCPForTarget(os.Stdout, aiName, binPath, cwd)
 	default:
 		return fmt.Errorf("unknown repair primitive: %s", primitive)
 	}
@@ -488,6 +492,56 @@ func checkActivePlan() DoctorCheck {
 	}
 }
 
+func checkMemoryIntegrity() DoctorCheck {
+	repo, err := openRepo()
+	if err != nil {
+		return DoctorCheck{
+			Name:    "Memory Integrity",
+			Status:  "warn",
+			Message: "Could not open project memory",
+			Hint:    "Run: taskwing bootstrap",
+		}
+	}
+	defer func() { _ = repo.Close() }()
+
+	issues, err := repo.Check()
+	if err != nil {
+		return DoctorCheck{
+			Name:    "Memory Integrity",
+			Status:  "warn",
+			Message: fmt.Sprintf("Integrity check failed: %v", err),
+		}
+	}
+	if len(issues) == 0 {
+		return DoctorCheck{
+			Name:    "Memory Integrity",
+			Status:  "ok",
+			Message: "No orphaned records, search index in sync",
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Type]++
+	}
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = fmt.Sprintf("%d %s", counts[t], t)
+	}
+
+	return DoctorCheck{
+		Name:    "Memory Integrity",
+		Status:  "fail",
+		Message: fmt.Sprintf("%d issue(s): %s", len(issues), strings.Join(parts, ", ")),
+		Hint:    "Run: taskwing memory check (details), then taskwing memory repair",
+	}
+}
+
 func checkSession() DoctorCheck {
 	session, err := loadHookSession()
 	if err != nil {
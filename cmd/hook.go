@@ -383,23 +383,10 @@ func buildTaskContext(repo *memory.Repository, nextTask *task.Task, plan *task.P
 		return task.FormatRichContext(ctx, nextTask, plan, nil)
 	}
 
-	// Combine unified context with task-specific formatting
+	// The unified context already carries the relevant knowledge, so the
+	// task section is formatted without a second recall
 	projectCtx := pc.FormatCompact()
-
-	// Create search adapter backed by the already-retrieved nodes
-	searchFn := func(_ context.Context, _ string, _ int) ([]task.AskResult, error) {
-		var results []task.AskResult
-		for _, sn := range pc.RelevantNodes {
-			results = append(results, task.AskResult{
-				Summary: sn.Node.Summary,
-				Type:    sn.Node.Type,
-				Content: sn.Node.Text(),
-			})
-		}
-		return results, nil
-	}
-
-	richCtx := task.FormatRichContext(ctx, nextTask, plan, searchFn)
+	richCtx := task.FormatRichContext(ctx, nextTask, plan, nil)
 	if projectCtx != "" {
 		return projectCtx + "\n\n" + richCtx
 	}
@@ -710,7 +697,7 @@ func evaluateTaskPolicy(ctx context.Context, t *task.Task, planGoal, sessionID,
 
 	// Try to load policy engine
 	policiesDir := policy.GetPoliciesPath(workDir)
-	engine, err := policy.NewEngine(policy.EngineConfig{
+	engine, err := policy.NewEngine(ctx, policy.EngineConfig{
 		WorkDir:     workDir,
 		PoliciesDir: policiesDir,
 	})
@@ -4,10 +4,15 @@ Copyright © 2025 Joseph Goksu josephgoksu@gmail.com
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/josephgoksu/TaskWing/internal/app"
 	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/knowledge"
+	"github.com/josephgoksu/TaskWing/internal/llm"
 	"github.com/josephgoksu/TaskWing/internal/memory"
 	"github.com/josephgoksu/TaskWing/internal/ui"
 	"github.com/spf13/cobra"
@@ -50,6 +55,53 @@ func init() {
 	knowledgeCmd.Flags().StringVarP(&knowledgeTypeFlag, "type", "t", "", "Filter by node type (decision, feature, constraint, pattern, plan, note, metadata, documentation)")
 	knowledgeCmd.Flags().StringVarP(&knowledgeWorkspaceFlag, "workspace", "w", "", "Filter by workspace name (e.g., 'osprey', 'api'). Includes root nodes by default.")
 	knowledgeCmd.Flags().BoolVar(&knowledgeAllFlag, "all", false, "Show all workspaces")
+	knowledgeCmd.AddCommand(knowledgeGraphCmd)
+}
+
+var knowledgeGraphCmd = &cobra.Command{
+	Use:          "graph",
+	Short:        "Export the feature dependency graph as Mermaid",
+	SilenceUsage: true,
+	Long: `Export features and their depends_on, extends, and relates_to relations
+as a Mermaid flowchart. Dependency cycles among features are highlighted
+in the chart and reported on stderr.
+
+Examples:
+  taskwing knowledge graph > features.mmd
+  taskwing knowledge graph --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo, err := openRepoOrHandleMissingMemory()
+		if err != nil {
+			return err
+		}
+		if repo == nil {
+			return nil
+		}
+		defer func() { _ = repo.Close() }()
+
+		graph, err := knowledge.NewService(repo, llm.Config{}).FeatureGraph(context.Background())
+		if err != nil {
+			return err
+		}
+		if isJSON() {
+			return printJSON(graph)
+		}
+
+		fmt.Print(graph.Mermaid())
+		summaries := make(map[string]string, len(graph.Nodes))
+		for _, n := range graph.Nodes {
+			summaries[n.ID] = n.Summary
+		}
+		for _, cycle := range graph.Cycles {
+			names := make([]string, len(cycle))
+			for i, id := range cycle {
+				names[i] = fmt.Sprintf("%s (%s)", summaries[id], id)
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  Dependency cycle: %s\n", strings.Join(names, ", "))
+		}
+		return nil
+	},
 }
 
 func runKnowledge(cmd *cobra.Command, args []string) error {
@@ -6,6 +6,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -75,7 +76,7 @@ Examples:
 		switch target {
 		case "all":
 			for _, ai := range bootstrap.ValidAINames() {
-				if err := installMCPForTarget(ai, binPath, cwd); err != nil {
+				if err := installMCPForTarget(os.Stdout, ai, binPath, cwd); err != nil {
 					fmt.Printf("⚠️  %s install failed: %v\n", ai, err)
 				}
 			}
@@ -84,7 +85,7 @@ Examples:
 				fmt.Printf("Unknown editor: %s\n", target)
 				os.Exit(1)
 			}
-			if err := installMCPForTarget(target, binPath, cwd); err != nil {
+			if err := installMCPForTarget(os.Stdout, target, binPath, cwd); err != nil {
 				fmt.Printf("❌ Failed to install for %s: %v\n", target, err)
 				os.Exit(1)
 			}
@@ -92,27 +93,27 @@ Examples:
 	},
 }
 
-func installMCPForTarget(target, binPath, cwd string) error {
+func installMCPForTarget(w io.Writer, target, binPath, cwd string) error {
 	switch target {
 	case "cursor":
-		installLocalMCP(cwd, ".cursor", "mcp.json", binPath)
+		installLocalMCP(w, cwd, ".cursor", "mcp.json", binPath)
 		return nil
 	case "claude":
-		installClaude(binPath, cwd)
+		installClaude(w, binPath, cwd)
 		return nil
 	case "claude-desktop":
-		installClaudeDesktop(binPath, cwd)
+		installClaudeDesktop(w, binPath, cwd)
 		return nil
 	case "codex":
-		installCodexGlobal(binPath, cwd)
+		installCodexGlobal(w, binPath, cwd)
 		return nil
 	case "gemini":
-		return installGeminiCLI(binPath, cwd)
+		return installGeminiCLI(w, binPath, cwd)
 	case "copilot":
-		installCopilot(binPath, cwd)
+		installCopilot(w, binPath, cwd)
 		return nil
 	case "opencode":
-		return installOpenCode(binPath, cwd)
+		return installOpenCode(w, binPath, cwd)
 	default:
 		return fmt.Errorf("unsupported target")
 	}
@@ -320,7 +321,7 @@ func upsertVSCodeMCPServer(configPath, serverName string, serverCfg VSCodeMCPSer
 	return writeJSONFile(configPath, config)
 }
 
-func installLocalMCP(projectDir, configDirName, configFileName, binPath string) {
+func installLocalMCP(w io.Writer, projectDir, configDirName, configFileName, binPath string) {
 	configPath := filepath.Join(projectDir, configDirName, configFileName)
 	serverName := mcpServerName(projectDir)
 
@@ -329,24 +330,24 @@ func installLocalMCP(projectDir, configDirName, configFileName, binPath string)
 		Args:    []string{"mcp"},
 	})
 	if err != nil {
-		fmt.Printf("❌ Failed to install for %s: %v\n", configDirName, err)
+		fmt.Fprintf(w, "❌ Failed to install for %s: %v\n", configDirName, err)
 		return
 	}
-	fmt.Printf("✅ Installed for %s as '%s' in %s\n", strings.TrimPrefix(configDirName, "."), serverName, configPath)
+	fmt.Fprintf(w, "✅ Installed for %s as '%s' in %s\n", strings.TrimPrefix(configDirName, "."), serverName, configPath)
 }
 
-func installClaude(binPath, projectDir string) {
+func installClaude(w io.Writer, binPath, projectDir string) {
 	// Install for Claude Code CLI only
 	// Claude Desktop is skipped by default (use 'taskwing mcp install claude-desktop' if needed)
-	installClaudeCodeCLI(binPath, projectDir)
+	installClaudeCodeCLI(w, binPath, projectDir)
 }
 
-func installClaudeCodeCLI(binPath, projectDir string) {
+func installClaudeCodeCLI(w io.Writer, binPath, projectDir string) {
 	// Check if claude CLI is available
 	_, err := exec.LookPath("claude")
 	if err != nil {
 		if viper.GetBool("verbose") {
-			fmt.Println("ℹ️  Claude Code CLI not found (skipping CLI config)")
+			fmt.Fprintln(w, "ℹ️  Claude Code CLI not found (skipping CLI config)")
 		}
 		return
 	}
@@ -354,11 +355,11 @@ func installClaudeCodeCLI(binPath, projectDir string) {
 	serverName := mcpServerName(projectDir)
 	legacyName := legacyServerName(projectDir)
 
-	fmt.Println("👉 Configuring Claude Code CLI...")
+	fmt.Fprintln(w, "👉 Configuring Claude Code CLI...")
 
 	if viper.GetBool("preview") {
-		fmt.Printf("[PREVIEW] Would run: claude mcp remove %s && claude mcp remove %s && claude mcp add --transport stdio %s -- %s mcp\n", legacyName, serverName, serverName, binPath)
-		fmt.Printf("✅ Would install for Claude Code as '%s'\n", serverName)
+		fmt.Fprintf(w, "[PREVIEW] Would run: claude mcp remove %s && claude mcp remove %s && claude mcp add --transport stdio %s -- %s mcp\n", legacyName, serverName, serverName, binPath)
+		fmt.Fprintf(w, "✅ Would install for Claude Code as '%s'\n", serverName)
 		return
 	}
 
@@ -382,13 +383,13 @@ func installClaudeCodeCLI(binPath, projectDir string) {
 	}
 
 	if err := cmd.Run(); err != nil {
-		fmt.Printf("⚠️  Failed to run 'claude mcp add': %v\n", err)
+		fmt.Fprintf(w, "⚠️  Failed to run 'claude mcp add': %v\n", err)
 	} else {
-		fmt.Printf("✅ Installed for Claude Code as '%s'\n", serverName)
+		fmt.Fprintf(w, "✅ Installed for Claude Code as '%s'\n", serverName)
 	}
 }
 
-func installClaudeDesktop(binPath, projectDir string) {
+func installClaudeDesktop(w io.Writer, binPath, projectDir string) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return
@@ -401,7 +402,7 @@ func installClaudeDesktop(binPath, projectDir string) {
 		return
 	}
 
-	fmt.Println("👉 Configuring Claude Desktop App...")
+	fmt.Fprintln(w, "👉 Configuring Claude Desktop App...")
 
 	serverName := mcpServerName(projectDir)
 	legacyName := legacyServerName(projectDir)
@@ -415,21 +416,21 @@ func installClaudeDesktop(binPath, projectDir string) {
 		Env:     map[string]string{},
 	})
 	if err != nil {
-		fmt.Printf("⚠️  Failed to configure Claude Desktop: %v\n", err)
+		fmt.Fprintf(w, "⚠️  Failed to configure Claude Desktop: %v\n", err)
 		return
 	}
-	fmt.Printf("✅ Installed for Claude Desktop as '%s' in %s\n", serverName, configPath)
-	fmt.Println("   (You may need to restart Claude Desktop to see the changes)")
+	fmt.Fprintf(w, "✅ Installed for Claude Desktop as '%s' in %s\n", serverName, configPath)
+	fmt.Fprintln(w, "   (You may need to restart Claude Desktop to see the changes)")
 }
 
 // installCopilot configures MCP for GitHub Copilot in VS Code
 // Uses .vscode/mcp.json with VS Code's MCP format
 // See: https://code.visualstudio.com/docs/copilot/customization/mcp-servers
-func installCopilot(binPath, projectDir string) {
+func installCopilot(w io.Writer, binPath, projectDir string) {
 	configPath := filepath.Join(projectDir, ".vscode", "mcp.json")
 	serverName := mcpServerName(projectDir)
 
-	fmt.Println("👉 Configuring GitHub Copilot (VS Code)...")
+	fmt.Fprintln(w, "👉 Configuring GitHub Copilot (VS Code)...")
 
 	err := upsertVSCodeMCPServer(configPath, serverName, VSCodeMCPServerConfig{
 		Type:    "stdio",
@@ -437,14 +438,14 @@ func installCopilot(binPath, projectDir string) {
 		Args:    []string{"mcp"},
 	})
 	if err != nil {
-		fmt.Printf("❌ Failed to install for Copilot: %v\n", err)
+		fmt.Fprintf(w, "❌ Failed to install for Copilot: %v\n", err)
 		return
 	}
-	fmt.Printf("✅ Installed for GitHub Copilot as '%s' in %s\n", serverName, configPath)
-	fmt.Println("   (Reload VS Code window to activate)")
+	fmt.Fprintf(w, "✅ Installed for GitHub Copilot as '%s' in %s\n", serverName, configPath)
+	fmt.Fprintln(w, "   (Reload VS Code window to activate)")
 }
 
-func installGeminiCLI(binPath, projectDir string) error {
+func installGeminiCLI(w io.Writer, binPath, projectDir string) error {
 	// Check if gemini CLI is available
 	geminiPath, err := exec.LookPath("gemini")
 	if err != nil {
@@ -455,17 +456,17 @@ func installGeminiCLI(binPath, projectDir string) error {
 	versionCmd := exec.Command(geminiPath, "--version")
 	versionOut, versionErr := versionCmd.Output()
 	if versionErr != nil {
-		fmt.Printf("⚠️  Could not determine gemini version: %v\n", versionErr)
+		fmt.Fprintf(w, "⚠️  Could not determine gemini version: %v\n", versionErr)
 	} else if viper.GetBool("verbose") {
-		fmt.Printf("   gemini version: %s\n", strings.TrimSpace(string(versionOut)))
+		fmt.Fprintf(w, "   gemini version: %s\n", strings.TrimSpace(string(versionOut)))
 	}
 
 	serverName := mcpServerName(projectDir)
 	legacyName := legacyServerName(projectDir)
-	fmt.Println("👉 Configuring Gemini CLI...")
+	fmt.Fprintln(w, "👉 Configuring Gemini CLI...")
 
 	if viper.GetBool("preview") {
-		fmt.Printf("[PREVIEW] Would run: gemini mcp remove -s project %s && gemini mcp add -s project %s %s mcp\n", legacyName, serverName, binPath)
+		fmt.Fprintf(w, "[PREVIEW] Would run: gemini mcp remove -s project %s && gemini mcp add -s project %s %s mcp\n", legacyName, serverName, binPath)
 		return nil
 	}
 
@@ -500,26 +501,26 @@ func installGeminiCLI(binPath, projectDir string) error {
 		return fmt.Errorf("'gemini mcp add' failed: %w", err)
 	}
 
-	fmt.Printf("✅ Installed for Gemini as '%s'\n", serverName)
+	fmt.Fprintf(w, "✅ Installed for Gemini as '%s'\n", serverName)
 	return nil
 }
 
-func installCodexGlobal(binPath, projectDir string) {
+func installCodexGlobal(w io.Writer, binPath, projectDir string) {
 	// Check if codex CLI is available
 	_, err := exec.LookPath("codex")
 	if err != nil {
-		fmt.Println("❌ 'codex' CLI not found in PATH.")
-		fmt.Println("   Please install the OpenAI Codex CLI first to use this integration.")
-		fmt.Println("   See: https://developers.openai.com/codex/mcp/")
+		fmt.Fprintln(w, "❌ 'codex' CLI not found in PATH.")
+		fmt.Fprintln(w, "   Please install the OpenAI Codex CLI first to use this integration.")
+		fmt.Fprintln(w, "   See: https://developers.openai.com/codex/mcp/")
 		return
 	}
 
 	serverName := mcpServerName(projectDir)
 	legacyName := legacyServerName(projectDir)
-	fmt.Println("👉 Configuring OpenAI Codex...")
+	fmt.Fprintln(w, "👉 Configuring OpenAI Codex...")
 
 	if viper.GetBool("preview") {
-		fmt.Printf("[PREVIEW] Would run: codex mcp remove %s && codex mcp add %s -- %s mcp\n", legacyName, serverName, binPath)
+		fmt.Fprintf(w, "[PREVIEW] Would run: codex mcp remove %s && codex mcp add %s -- %s mcp\n", legacyName, serverName, binPath)
 		return
 	}
 
@@ -544,9 +545,9 @@ func installCodexGlobal(binPath, projectDir string) {
 	}
 
 	if err := cmd.Run(); err != nil {
-		fmt.Printf("⚠️  Failed to run 'codex mcp add': %v\n", err)
+		fmt.Fprintf(w, "⚠️  Failed to run 'codex mcp add': %v\n", err)
 	} else {
-		fmt.Printf("✅ Installed for Codex as '%s'\n", serverName)
+		fmt.Fprintf(w, "✅ Installed for Codex as '%s'\n", serverName)
 	}
 }
 
@@ -557,11 +558,11 @@ func installCodexGlobal(binPath, projectDir string) {
 // - Command is an array, not a string
 // - Type must be "local" for command execution
 // See: https://opencode.ai/docs/mcp-servers/
-func installOpenCode(binPath, projectDir string) error {
+func installOpenCode(w io.Writer, binPath, projectDir string) error {
 	configPath := filepath.Join(projectDir, "opencode.json")
 	serverName := mcpServerName(projectDir)
 
-	fmt.Println("👉 Configuring OpenCode...")
+	fmt.Fprintln(w, "👉 Configuring OpenCode...")
 
 	// Create MCP config (opencode.json)
 	if err := upsertOpenCodeMCPServer(configPath, serverName, OpenCodeMCPServerConfig{
@@ -571,23 +572,26 @@ func installOpenCode(binPath, projectDir string) error {
 	}); err != nil {
 		return err
 	}
+	fmt.Fprintf(w, "✅ Installed for OpenCode as '%s' in %s\n", serverName, configPath)
+	fmt.Fprintln(w, "   (opencode.json is at project root per OpenCode spec)")
 
 	// Create skills and plugins using the initializer
 	init := bootstrap.NewInitializer(projectDir)
+	init.SetOutput(w)
 	verbose := viper.GetBool("verbose")
 
 	// Create slash commands (.opencode/commands/)
 	if err := init.CreateSlashCommands("opencode", verbose); err != nil {
-		fmt.Printf("⚠️  Failed to create commands: %v\n", err)
+		fmt.Fprintf(w, "⚠️  Failed to create commands: %v\n", err)
 	} else {
-		fmt.Println("✅ Created OpenCode commands in .opencode/commands/")
+		fmt.Fprintln(w, "✅ Created OpenCode commands in .opencode/commands/")
 	}
 
 	// Create hooks plugin (.opencode/plugins/)
 	if err := init.InstallHooksConfig("opencode", verbose); err != nil {
-		fmt.Printf("⚠️  Failed to create plugin: %v\n", err)
+		fmt.Fprintf(w, "⚠️  Failed to create plugin: %v\n", err)
 	} else {
-		fmt.Println("✅ Created OpenCode plugin in .opencode/plugins/")
+		fmt.Fprintln(w, "✅ Created OpenCode plugin in .opencode/plugins/")
 	}
 
 	return nil
@@ -655,8 +659,5 @@ func upsertOpenCodeMCPServer(configPath, serverName string, serverCfg OpenCodeMC
 	if err := writeJSONFile(configPath, config); err != nil {
 		return fmt.Errorf("write opencode.json: %w", err)
 	}
-
-	fmt.Printf("✅ Installed for OpenCode as '%s' in %s\n", serverName, configPath)
-	fmt.Println("   (opencode.json is at project root per OpenCode spec)")
 	return nil
 }
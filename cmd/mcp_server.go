@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/josephgoksu/TaskWing/internal/app"
@@ -16,6 +17,7 @@ import (
 	"github.com/josephgoksu/TaskWing/internal/llm"
 	mcppresenter "github.com/josephgoksu/TaskWing/internal/mcp"
 	"github.com/josephgoksu/TaskWing/internal/memory"
+	"github.com/josephgoksu/TaskWing/internal/task"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -36,6 +38,10 @@ The MCP server provides the ask tool that gives AI tools access to:
 Example usage with Claude Code:
   taskwing mcp
 
+Use --read-only in shared or CI contexts: ask, code, debug and policy keep
+working, while remember and any task or plan action that changes project
+state return an error.
+
 The server will run until the client disconnects.`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -46,12 +52,14 @@ The server will run until the client disconnects.`,
 		if len(args) > 0 {
 			return fmt.Errorf("unknown command %q for %q\nRun '%s --help' for usage", args[0], cmd.CommandPath(), cmd.Root().Name())
 		}
-		return runMCPServer(cmd.Context())
+		readOnly, _ := cmd.Flags().GetBool("read-only")
+		return runMCPServer(cmd.Context(), mcppresenter.ServerOptions{ReadOnly: readOnly})
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(mcpCmd)
+	mcpCmd.Flags().Bool("read-only", false, "Reject tool calls that change project state")
 	// NOTE: SSE transport (--port) intentionally removed. Stdio is the standard.
 }
 
@@ -68,27 +76,26 @@ func mcpMarkdownResponse(markdown string) (*mcpsdk.CallToolResultFor[any], error
 // Per MCP spec: tool errors should be returned in the result (not as protocol errors)
 // so the LLM can see them and self-correct. Use mcppresenter.FormatError for formatting.
 func mcpErrorResponse(err error) (*mcpsdk.CallToolResultFor[any], error) {
-	return &mcpsdk.CallToolResultFor[any]{
-		Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: mcppresenter.FormatError(err.Error())}},
-		IsError: true,
-	}, nil
+	return mcpToolErrorResponse(mcppresenter.ErrorCodeOf(err), err.Error(), "")
 }
 
 // mcpValidationErrorResponse wraps a validation error with IsError=true.
 // Use for input validation failures (missing required fields, invalid values).
 func mcpValidationErrorResponse(field, message string) (*mcpsdk.CallToolResultFor[any], error) {
-	return &mcpsdk.CallToolResultFor[any]{
-		Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: mcppresenter.FormatValidationError(field, message)}},
-		IsError: true,
-	}, nil
+	return mcpToolErrorResponse(mcppresenter.ErrorCodeInvalidInput, fmt.Sprintf("%s: %s", field, message), mcppresenter.FormatValidationError(field, message))
 }
 
-// mcpFormattedErrorResponse wraps pre-formatted error text with IsError=true.
-// Use when error message is already formatted (e.g., from presenter functions).
-func mcpFormattedErrorResponse(formattedError string) (*mcpsdk.CallToolResultFor[any], error) {
+// mcpToolErrorResponse returns a failed tool result with IsError=true. The
+// Markdown (FormatError(message) when empty) is for display; the structured
+// {"error": {"code", "message"}} envelope lets clients branch on the code.
+func mcpToolErrorResponse(code mcppresenter.ErrorCode, message, markdown string) (*mcpsdk.CallToolResultFor[any], error) {
+	if markdown == "" {
+		markdown = mcppresenter.FormatError(message)
+	}
 	return &mcpsdk.CallToolResultFor[any]{
-		Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: formattedError}},
-		IsError: true,
+		Content:           []mcpsdk.Content{&mcpsdk.TextContent{Text: markdown}},
+		StructuredContent: map[string]any{"error": mcppresenter.NewToolError(code, message)},
+		IsError:           true,
 	}, nil
 }
 
@@ -130,10 +137,14 @@ func initMCPRepository() (*memory.Repository, error) {
 	return repo, nil
 }
 
-func runMCPServer(ctx context.Context) error {
+func runMCPServer(ctx context.Context, opts mcppresenter.ServerOptions) error {
 	// NOTE: MCP uses stdio transport. stdout MUST be pure JSON-RPC.
 	// All status/debug output goes to stderr only.
 	fmt.Fprintln(os.Stderr, "TaskWing MCP Server starting...")
+	if opts.ReadOnly {
+		fmt.Fprintln(os.Stderr, "Read-only mode: mutating tool calls are disabled")
+	}
+	mcppresenter.SetPresenterConfig(config.LoadPresenterConfig())
 
 	// Initialize memory repository with fallback paths
 	// Project-scoped only (fail-fast if no .taskwing)
@@ -175,7 +186,7 @@ func runMCPServer(ctx context.Context) error {
 	// Register ask tool - retrieves stored codebase knowledge for AI context
 	tool := &mcpsdk.Tool{
 		Name:        "ask",
-		Description: "Search project knowledge: decisions, patterns, constraints, and code symbols. Returns an AI-synthesized answer and relevant context by default. Use {\"query\":\"search term\"} for semantic search. Use {\"all\":true} for a compact knowledge summary (no LLM calls, instant). Use {\"all\":true, \"detail\":\"full\", \"page\":1} for full detail with pagination. Use {\"query\":\"auth\", \"detail\":\"full\"} for full detail on matching nodes only.",
+		Description: "Search project knowledge: decisions, patterns, constraints, and code symbols. Returns an AI-synthesized answer and relevant context by default. Use {\"query\":\"search term\"} for semantic search. Use {\"all\":true} for a compact knowledge summary (no LLM calls, instant). Use {\"all\":true, \"detail\":\"full\", \"page\":1} for full detail with pagination. Use {\"query\":\"auth\", \"detail\":\"full\"} for full detail on matching nodes only. Use {\"query\":\"auth\", \"related\":true} to also recall nodes linked to each match (optionally \"relation\":\"depends_on\"). Use {\"query\":\"auth\", \"by_feature\":true} to group matches under their linked feature. Add \"debug\":true to show why each match was recalled with its FTS and vector scores.",
 	}
 
 	mcpsdk.AddTool(server, tool, func(ctx context.Context, session *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[mcppresenter.ProjectContextParams]) (*mcpsdk.CallToolResultFor[any], error) {
@@ -196,15 +207,21 @@ func runMCPServer(ctx context.Context) error {
 			return mcpMarkdownResponse(mcppresenter.FormatKnowledgeSummary(nodes))
 		}
 
-		return handleNodeContext(ctx, repo, params.Arguments)
+		return handleNodeContext(ctx, repo, params.Arguments, !opts.ReadOnly)
 	})
 
 	// Register remember tool - add knowledge to project memory
 	rememberTool := &mcpsdk.Tool{
 		Name:        "remember",
-		Description: "Add knowledge to project memory. Use this to persist decisions, patterns, or insights discovered during the session. Content will be classified automatically using AI. Use {\"global\":true} to store in global knowledge (~/.taskwing/knowledge/) for cross-project persistence.",
+		Description: "Add knowledge to project memory. Use this to persist decisions, patterns, or insights discovered during the session. Content will be classified automatically using AI. Use {\"global\":true} to store in global knowledge (~/.taskwing/knowledge/) for cross-project persistence. Use {\"tags\":[\"security\"]} to group related knowledge.",
+	}
+	if opts.ReadOnly {
+		rememberTool.Description = "Disabled: this server is read-only. " + rememberTool.Description
 	}
 	mcpsdk.AddTool(server, rememberTool, func(ctx context.Context, session *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[mcppresenter.RememberParams]) (*mcpsdk.CallToolResultFor[any], error) {
+		if err := opts.Check("remember", true); err != nil {
+			return mcpErrorResponse(err)
+		}
 		return handleRemember(ctx, repo, params.Arguments)
 	})
 
@@ -216,10 +233,21 @@ func runMCPServer(ctx context.Context) error {
 		Description: `Unified code intelligence tool. Use action parameter to select operation:
 - find: Locate symbols by name, ID, or file path
 - search: Hybrid semantic + lexical code search
+- search_sig: Find functions by signature shape when the name is unknown; query lists types in order (e.g. "context.Context, string")
 - explain: Deep dive into a symbol with call graph and AI explanation
+- explain_file: Summarize a whole file (file_path): symbols, most-called functions, imports, and AI explanation
+- imports: List a file's imports (file_path), split into internal packages and external stdlib/third-party ones
+- tree: List the symbols of a directory (file_path, e.g. "internal/memory") grouped by file, without subdirectories
 - callers: Get call graph relationships (who calls it, what it calls)
-- impact: Analyze change impact via recursive call graph traversal
-- simplify: Reduce code complexity while preserving behavior`,
+- usages: Every use of a symbol, including non-call references such as struct fields of its type
+- implementations: Types implementing an interface (query or symbol_id), including implementations of interfaces that embed it and types embedding an implementation; max_depth bounds the embedding hops (default 3)
+- impact: Analyze change impact via recursive call graph traversal (exclude_vendored skips vendor/, node_modules/, and generated code)
+- simplify: Reduce code complexity while preserving behavior; lists the callers to re-test (query or symbol_id narrows to one symbol) and warns on high fan-in
+- changed: List symbols changed between two git refs (base, default main; head, default HEAD) with their impact
+
+Set include_private=false on search or search_sig to return only exported symbols. Set min_score (0-1) on search to drop weak matches, language (e.g. "go", "typescript") to search one language, and debug=true to see why each result matched.
+
+Output is compact by default (5 items per list, 20-line snippets); set verbose=true on explain, callers, usages, implementations, or impact to show everything.`,
 	}
 	mcpsdk.AddTool(server, codeTool, func(ctx context.Context, session *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[mcppresenter.CodeToolParams]) (*mcpsdk.CallToolResultFor[any], error) {
 		result, err := mcppresenter.HandleCodeTool(ctx, repo, params.Arguments)
@@ -227,29 +255,40 @@ func runMCPServer(ctx context.Context) error {
 			return mcpErrorResponse(err)
 		}
 		if result.Error != "" {
-			return mcpFormattedErrorResponse(mcppresenter.FormatError(result.Error))
+			return mcpToolErrorResponse(result.ErrorCode, result.Error, result.Content)
 		}
 		return mcpMarkdownResponse(result.Content)
 	})
 
-	// Register unified 'task' tool for lifecycle actions (next/current/start/complete)
+	// Register unified 'task' tool for lifecycle actions (next/current/start/complete/skip/reorder/list/dependencies/split)
 	taskTool := &mcpsdk.Tool{
 		Name: "task",
 		Description: `Unified task lifecycle tool. Use action parameter to select operation:
 - next: Get next pending task from plan (use auto_start=true to claim immediately)
-- current: Get current in-progress task for session
+- current: Get current in-progress task for session, with its context summary and related code symbols
 - start: Claim a specific task by ID
 - complete: Mark task as completed with summary
 - skip: Skip a task that's irrelevant or overlapping (use summary for reason)
+- reorder: Set the priority order of a plan's tasks (most urgent first)
+- list: List a plan's tasks as a table (format=json for structured output)
+- dependencies: Show the tasks a task depends on (upstream) and the tasks that depend on it (downstream)
+- split: Break a pending task into subtasks; the original then depends on them
 
 REQUIRED FIELDS BY ACTION:
-- next: session_id (auto-inferred from hook session if omitted)
+- next: session_id (auto-inferred from hook session if omitted), agent (optional; only tasks assigned to it, "unassigned" for none)
 - current: session_id (auto-inferred from hook session if omitted)
 - start: task_id (required), session_id (auto-inferred from hook session if omitted)
-- complete: task_id (required)
-- skip: task_id (required), summary (optional skip reason)`,
+- complete: task_id (required), verify_criteria (optional; checks each acceptance criterion against files_modified and records pass/unknown)
+- skip: task_id (required), summary (optional skip reason)
+- reorder: task_ids (required, every task in the plan), plan_id (defaults to active plan)
+- list: none required; optional plan_id, status, phase, sort (priority|created), limit, format (markdown|json)
+- dependencies: task_id (required)
+- split: task_id (required), subtasks (required, at least two titles)`,
 	}
 	mcpsdk.AddTool(server, taskTool, func(ctx context.Context, session *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[mcppresenter.TaskToolParams]) (*mcpsdk.CallToolResultFor[any], error) {
+		if err := opts.Check("task "+string(params.Arguments.Action), params.Arguments.Mutates()); err != nil {
+			return mcpErrorResponse(err)
+		}
 		defaultSessionID := ""
 		if session != nil {
 			if sid := strings.TrimSpace(session.ID()); sid != "" {
@@ -268,7 +307,17 @@ REQUIRED FIELDS BY ACTION:
 			return mcpErrorResponse(err)
 		}
 		if result.Error != "" {
-			return mcpFormattedErrorResponse(mcppresenter.FormatError(result.Error))
+			return mcpToolErrorResponse(result.ErrorCode, result.Error, result.Content)
+		}
+		if result.Action == string(mcppresenter.TaskActionList) && strings.EqualFold(params.Arguments.Format, "json") {
+			tasks := result.Tasks
+			if tasks == nil {
+				tasks = []task.Task{}
+			}
+			return &mcpsdk.CallToolResultFor[any]{
+				Content:           []mcpsdk.Content{&mcpsdk.TextContent{Text: result.Content}},
+				StructuredContent: map[string]any{"tasks": tasks},
+			}, nil
 		}
 		return mcpMarkdownResponse(result.Content)
 	})
@@ -282,24 +331,30 @@ REQUIRED FIELDS BY ACTION:
 - expand: Expand a phase into detailed tasks
 - generate: Create plan with tasks from enriched goal
 - finalize: Finalize interactive plan after all phases are expanded
-- audit: Verify completed plan with build/test/semantic checks (auto-fixes failures)
+- audit: Verify completed plan by running the project's build and test commands (audit.build_command/audit.test_command, else detected from go.mod, Cargo.toml or package.json)
+- merge: Move another plan's phases and tasks into this plan and delete the other plan
+- list: List plans, newest first; nothing required, optional status, include_archived, sort (created|updated), limit, offset
 
 REQUIRED FIELDS BY ACTION:
 - clarify (first call): goal (required)
 - clarify (follow-up): clarify_session_id (required), answers (required unless auto_answer=true)
 - decompose: enriched_goal (required), plan_id (optional to continue existing draft)
-- expand: plan_id (required), plus either phase_id or phase_index
-- generate: goal (required), enriched_goal (required), clarify_session_id (required)
+- expand: plan_id (required), plus either phase_id or phase_index; feedback (optional, regenerates an expanded phase)
+- generate: goal (required), enriched_goal (required), clarify_session_id (required), dry_run (optional preview, nothing saved), stream (optional, save tasks as they are generated), normalize_priorities (optional, evenly spaced priorities that respect dependencies), test_first (optional, a failing-test task before each implementation task), merge_duplicates (optional, fold near-duplicate tasks into the task they repeat), phase_id (optional, add the tasks to an existing decomposed phase instead of creating a plan)
 - finalize: plan_id (required)
-- audit: none required (defaults to active plan)`,
+- audit: none required (defaults to active plan); force (optional, re-audit even if tracked files are unchanged since the last successful audit), junit (optional, return the report as JUnit XML for CI)
+- merge: plan_id (required, plan to keep), source_plan_id (required, plan to fold in)`,
 	}
 	mcpsdk.AddTool(server, planTool, func(ctx context.Context, session *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[mcppresenter.PlanToolParams]) (*mcpsdk.CallToolResultFor[any], error) {
+		if err := opts.Check("plan "+string(params.Arguments.Action), params.Arguments.Mutates()); err != nil {
+			return mcpErrorResponse(err)
+		}
 		result, err := mcppresenter.HandlePlanTool(ctx, repo, params.Arguments)
 		if err != nil {
 			return mcpErrorResponse(err)
 		}
 		if result.Error != "" {
-			return mcpFormattedErrorResponse(mcppresenter.FormatError(result.Error))
+			return mcpToolErrorResponse(result.ErrorCode, result.Error, result.Content)
 		}
 		return mcpMarkdownResponse(result.Content)
 	})
@@ -319,11 +374,58 @@ REQUIRED FIELDS BY ACTION:
 			return mcpErrorResponse(err)
 		}
 		if result.Error != "" {
-			return mcpFormattedErrorResponse(mcppresenter.FormatError(result.Error))
+			return mcpToolErrorResponse(result.ErrorCode, result.Error, result.Content)
 		}
 		return mcpMarkdownResponse(result.Content)
 	})
 
+	// Register 'policy' tool - explains policy decisions for files
+	policyTool := &mcpsdk.Tool{
+		Name: "policy",
+		Description: `Inspect policy-as-code guardrails. Use action parameter to select operation:
+- list: List loaded policies and their source (local policies directory or OPA bundle)
+- why: Explain which deny rules block changes to a file, with the rule source and a suggested remediation
+
+REQUIRED FIELDS BY ACTION:
+- list: none
+- why: file (required, project-relative path)`,
+	}
+	mcpsdk.AddTool(server, policyTool, func(ctx context.Context, session *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[mcppresenter.PolicyToolParams]) (*mcpsdk.CallToolResultFor[any], error) {
+		result, err := mcppresenter.HandlePolicyTool(ctx, params.Arguments)
+		if err != nil {
+			return mcpErrorResponse(err)
+		}
+		if result.Error != "" {
+			return mcpToolErrorResponse(result.ErrorCode, result.Error, result.Content)
+		}
+		return mcpMarkdownResponse(result.Content)
+	})
+
+	// Register 'info' tool - version, capabilities and project state; doubles as a ping
+	enabledTools := []string{tool.Name, codeTool.Name, taskTool.Name, planTool.Name, debugTool.Name, policyTool.Name, "info"}
+	if !opts.ReadOnly {
+		enabledTools = append(enabledTools, rememberTool.Name)
+	}
+	sort.Strings(enabledTools)
+	infoTool := &mcpsdk.Tool{
+		Name:        "info",
+		Description: "Report the server version, read-only mode, enabled tools, whether a code index exists, and the active plan. Makes no LLM calls, so it is safe to use as a ping.",
+	}
+	mcpsdk.AddTool(server, infoTool, func(ctx context.Context, session *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[mcppresenter.InfoToolParams]) (*mcpsdk.CallToolResultFor[any], error) {
+		result, err := mcppresenter.HandleInfoTool(ctx, repo, mcppresenter.ServerInfo{
+			Version:  version,
+			Tools:    enabledTools,
+			ReadOnly: opts.ReadOnly,
+		})
+		if err != nil {
+			return mcpErrorResponse(err)
+		}
+		return &mcpsdk.CallToolResultFor[any]{
+			Content:           []mcpsdk.Content{&mcpsdk.TextContent{Text: result.Content}},
+			StructuredContent: result,
+		}, nil
+	})
+
 	// Run the server (stdio transport only)
 	if err := server.Run(ctx, mcpsdk.NewStdioTransport()); err != nil {
 		return fmt.Errorf("MCP server failed: %w", err)
@@ -335,7 +437,9 @@ REQUIRED FIELDS BY ACTION:
 // handleNodeContext returns context using the knowledge.Service (same as CLI).
 // This ensures MCP and CLI use identical search logic with zero drift.
 // Uses the app.AskApp for all business logic - single source of truth.
-func handleNodeContext(ctx context.Context, repo *memory.Repository, params mcppresenter.ProjectContextParams) (*mcpsdk.CallToolResultFor[any], error) {
+// recordAccess counts the results toward their access boost; it is off in
+// read-only mode.
+func handleNodeContext(ctx context.Context, repo *memory.Repository, params mcppresenter.ProjectContextParams, recordAccess bool) (*mcpsdk.CallToolResultFor[any], error) {
 	// Create app context with query role - respects llm.models.query config (same as CLI)
 	appCtx := app.NewContextForRole(repo, llm.RoleQuery)
 	askApp := app.NewAskApp(appCtx)
@@ -372,6 +476,11 @@ func handleNodeContext(ctx context.Context, repo *memory.Repository, params mcpp
 		IncludeSymbols: true,          // Include code symbols alongside knowledge
 		Workspace:      workspace,
 		IncludeRoot:    true, // Always include root knowledge when filtering by workspace
+		ExpandRelated:  params.Related,
+		Relation:       strings.TrimSpace(params.Relation),
+		GroupByFeature: params.ByFeature,
+		RecordAccess:   recordAccess,
+		Debug:          params.Debug,
 	})
 	if err != nil {
 		return mcpErrorResponse(fmt.Errorf("search failed: %w", err))
@@ -430,6 +539,7 @@ func handleRemember(ctx context.Context, repo *memory.Repository, params mcppres
 
 	result, err := memoryApp.Add(ctx, content, app.AddOptions{
 		Type: params.Type,
+		Tags: params.Tags,
 	})
 	if err != nil {
 		return mcpErrorResponse(fmt.Errorf("failed to add knowledge: %w", err))
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/josephgoksu/TaskWing/internal/app"
 	"github.com/josephgoksu/TaskWing/internal/codeintel"
 	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/knowledge"
@@ -36,6 +37,7 @@ Examples:
   taskwing memory rebuild             # Rebuild the index cache
   taskwing memory generate-embeddings # Backfill missing embeddings
   taskwing memory export              # Generate comprehensive ARCHITECTURE.md
+  taskwing memory stats               # Show knowledge and symbol counts
   taskwing memory reset               # Wipe all project memory and start fresh`,
 }
 
@@ -87,9 +89,9 @@ var memoryCheckCmd = &cobra.Command{
 	Long: `Validate the integrity of the project memory.
 
 Checks for:
-  • Missing markdown files
-  • Orphan edges (relationships to non-existent features)
-  • Index cache staleness
+  • Orphaned phases and tasks (plan or phase no longer exists)
+  • More than one active plan
+  • Full-text search indexes out of sync
   • Embedding dimension consistency
   • Symbol index health (language breakdown, stale files)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -225,6 +227,9 @@ Checks for:
 		fmt.Printf("Found %d issues:\n\n", len(issues))
 		for i, issue := range issues {
 			fmt.Printf("%d. [%s] %s\n", i+1, issue.Type, issue.Message)
+			if issue.Hint != "" {
+				fmt.Printf("   → %s\n", issue.Hint)
+			}
 		}
 
 		fmt.Println("\nRun 'taskwing memory repair' to fix these issues.")
@@ -239,9 +244,10 @@ var memoryRepairCmd = &cobra.Command{
 	Long: `Attempt to fix integrity issues in project memory.
 
 Actions:
-  • Regenerate missing markdown files from SQLite data
-  • Remove orphan edges
-  • Rebuild the index cache`,
+  • Remove phases and tasks whose plan no longer exists
+  • Detach tasks from deleted phases
+  • Keep only the most recently updated active plan
+  • Rebuild the full-text search indexes`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		memoryPath, err := config.GetMemoryBasePath()
 		if err != nil {
@@ -408,6 +414,70 @@ Requires an API key for the configured provider (OpenAI/Gemini) or a local Ollam
 	},
 }
 
+// memory generate-symbol-embeddings command
+var memoryGenerateSymbolEmbeddingsCmd = &cobra.Command{
+	Use:   "generate-symbol-embeddings",
+	Short: "Generate embeddings for code symbols without them",
+	Long: `Backfill embeddings for indexed code symbols that don't have them.
+
+Symbols indexed without an API key have no vectors, so code search falls back
+to keyword matching. Requires the same embedding setup as generate-embeddings.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ui.RenderPageHeader("TaskWing Symbol Embeddings", "Generating missing vectors")
+		llmCfg, err := config.LoadLLMConfig()
+		if err != nil {
+			return fmt.Errorf("load llm config: %w", err)
+		}
+		if llmCfg.Provider == llm.ProviderAnthropic {
+			return fmt.Errorf("embedding generation is not supported for provider %q; use openai, gemini, or ollama", llmCfg.Provider)
+		}
+		if llmCfg.APIKey == "" && llmCfg.Provider != llm.ProviderOllama {
+			return fmt.Errorf("API key required for embedding generation with provider %q", llmCfg.Provider)
+		}
+
+		memoryPath, err := config.GetMemoryBasePath()
+		if err != nil {
+			return fmt.Errorf("get memory path: %w", err)
+		}
+		repo, err := memory.NewDefaultRepository(memoryPath)
+		if err != nil {
+			return fmt.Errorf("open memory repo: %w", err)
+		}
+		defer func() { _ = repo.Close() }()
+
+		db := repo.GetDB()
+		if db == nil || db.DB() == nil {
+			return fmt.Errorf("symbol index is not available")
+		}
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		qs := codeintel.NewQueryService(codeintel.NewRepository(db.DB()), llmCfg)
+
+		quiet := viper.GetBool("quiet")
+		result, err := qs.BackfillEmbeddings(context.Background(), codeintel.BackfillOptions{
+			Concurrency: concurrency,
+			OnProgress: func(done, total int) {
+				if !quiet {
+					fmt.Printf("  %d/%d symbols\n", done, total)
+				}
+			},
+		})
+		if result != nil && result.Total == 0 {
+			fmt.Println("✓ All symbols already have embeddings")
+			return nil
+		}
+		if result != nil {
+			for _, e := range result.Errors {
+				fmt.Printf("  ✗ %s\n", e)
+			}
+			fmt.Printf("\n✓ Generated %d/%d symbol embeddings\n", result.Embedded, result.Total)
+		}
+		if err != nil {
+			return fmt.Errorf("backfill symbol embeddings: %w", err)
+		}
+		return nil
+	},
+}
+
 // memory export command
 var memoryExportCmd = &cobra.Command{
 	Use:   "export",
@@ -555,6 +625,48 @@ WARNING: This can be expensive if you have many nodes and are using a paid API.`
 	},
 }
 
+// memory stats command
+var memoryStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show knowledge and code index counts",
+	Long: `Show the size of the project memory.
+
+Reports:
+  • Knowledge nodes by type
+  • Indexed code symbols, relations and files
+  • Database size
+
+Examples:
+  taskwing memory stats         # Show counts
+  taskwing memory stats --json  # Machine-readable output`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		memoryPath, err := config.GetMemoryBasePath()
+		if err != nil {
+			return fmt.Errorf("get memory path: %w", err)
+		}
+		repo, err := memory.NewDefaultRepository(memoryPath)
+		if err != nil {
+			return fmt.Errorf("open memory repo: %w", err)
+		}
+		defer func() { _ = repo.Close() }()
+
+		stats, err := app.NewMemoryApp(app.NewContextWithConfig(repo, llm.Config{})).Stats(context.Background())
+		if err != nil {
+			return fmt.Errorf("memory stats: %w", err)
+		}
+
+		if viper.GetBool("json") {
+			output, _ := json.MarshalIndent(stats, "", "  ")
+			fmt.Println(string(output))
+			return nil
+		}
+
+		ui.RenderPageHeader("TaskWing Memory Stats", memoryPath)
+		ui.RenderMemoryStats(stats)
+		return nil
+	},
+}
+
 // memory inspect command
 var memoryInspectCmd = &cobra.Command{
 	Use:   "inspect <query>",
@@ -1063,14 +1175,17 @@ func init() {
 	memoryCmd.AddCommand(memoryRepairCmd)
 	memoryCmd.AddCommand(memoryRebuildCmd)
 	memoryCmd.AddCommand(memoryGenerateEmbeddingsCmd)
+	memoryCmd.AddCommand(memoryGenerateSymbolEmbeddingsCmd)
 	memoryCmd.AddCommand(memoryRebuildEmbeddingsCmd)
 	memoryCmd.AddCommand(memoryResetCmd)
 	memoryCmd.AddCommand(memoryExportCmd)
 	memoryCmd.AddCommand(memoryInspectCmd)
+	memoryCmd.AddCommand(memoryStatsCmd)
 	memoryCmd.AddCommand(memoryBackfillWorkspaceCmd)
 
 	memoryResetCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 	memoryRebuildEmbeddingsCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	memoryGenerateSymbolEmbeddingsCmd.Flags().Int("concurrency", 4, "Maximum embedding requests in flight")
 	memoryExportCmd.Flags().StringP("name", "n", "", "Project name for the document header")
 	memoryInspectCmd.Flags().IntP("limit", "n", 10, "Maximum number of results")
 	memoryInspectCmd.Flags().BoolP("verbose", "v", false, "Show detailed scores and embedding dimensions")
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/josephgoksu/TaskWing/internal/app"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var recallCmd = &cobra.Command{
+	Use:          "recall <query>",
+	Short:        "Search project knowledge with type and tag filters",
+	SilenceUsage: true,
+	Long: `Recall project knowledge from the CLI without going through MCP.
+
+Runs the same retrieval pipeline as the MCP ask tool over knowledge nodes
+only (no code symbols), narrowed by node type and tag.
+
+Examples:
+  taskwing recall "error handling"
+  taskwing recall "storage" --types decision,constraint
+  taskwing recall "auth" --tags security --limit 10
+  taskwing recall "why sqlite" --answer`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRecall,
+}
+
+func init() {
+	rootCmd.AddCommand(recallCmd)
+	recallCmd.Flags().StringSlice("types", nil, "Only return these node types (e.g. decision,pattern)")
+	recallCmd.Flags().StringSlice("tags", nil, "Only return nodes carrying one of these tags")
+	recallCmd.Flags().IntP("limit", "l", 5, "Max knowledge results")
+	recallCmd.Flags().BoolP("answer", "a", false, "Generate a RAG answer (uses LLM, slower)")
+}
+
+func runRecall(cmd *cobra.Command, args []string) error {
+	types, _ := cmd.Flags().GetStringSlice("types")
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	limit, _ := cmd.Flags().GetInt("limit")
+	generateAnswer, _ := cmd.Flags().GetBool("answer")
+
+	opts, err := app.RecallOptions{Limit: limit, Types: types, Tags: tags, Answer: generateAnswer}.AskOptions()
+	if err != nil {
+		return err
+	}
+
+	repo, err := openRepoOrHandleMissingMemory()
+	if err != nil {
+		return err
+	}
+	if repo == nil {
+		return nil
+	}
+	defer func() { _ = repo.Close() }()
+
+	cfg, err := getLLMConfigForRole(cmd, llm.RoleQuery)
+	if err != nil {
+		return fmt.Errorf("llm config: %w", err)
+	}
+
+	if generateAnswer && isJSON() {
+		opts.StreamWriter = os.Stdout
+	}
+
+	var spin *ui.Spinner
+	if !isJSON() {
+		if generateAnswer {
+			spin = ui.NewSpinner("Generating answer...")
+		} else {
+			spin = ui.NewSpinner("Searching knowledge...")
+		}
+		spin.Start()
+	}
+
+	result, err := app.NewAskApp(app.NewContextWithConfig(repo, cfg)).Query(cmd.Context(), args[0], opts)
+	if spin != nil {
+		spin.Stop()
+	}
+	if err != nil {
+		return fmt.Errorf("recall failed: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+	if !isQuiet() {
+		ui.RenderAskResult(result, viper.GetBool("verbose"))
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/josephgoksu/TaskWing/internal/app"
+	"github.com/josephgoksu/TaskWing/internal/llm"
 	"github.com/josephgoksu/TaskWing/internal/task"
 	"github.com/josephgoksu/TaskWing/internal/ui"
 	"github.com/josephgoksu/TaskWing/internal/utils"
@@ -60,7 +61,7 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 	scopeFilter, _ := cmd.Flags().GetString("scope")
 	includeArchived, _ := cmd.Flags().GetBool("include-archived")
 
-	plans, err := repo.ListPlans()
+	plans, err := repo.ListPlans(task.PlanFilter{IncludeArchived: true})
 	if err != nil {
 		return fmt.Errorf("failed to list plans: %w", err)
 	}
@@ -122,19 +123,19 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 	// Handle JSON output
 	if isJSON() {
 		type taskJSON struct {
-			ID                  string   `json:"id"`
-			PlanID              string   `json:"plan_id"`
-			PlanStatus          string   `json:"plan_status"`
-			Title               string   `json:"title"`
-			Description         string   `json:"description"`
-			Status              string   `json:"status"`
-			Priority            int      `json:"priority"`
-			Agent               string   `json:"assigned_agent"`
-			Acceptance          []string `json:"acceptance_criteria"`
-			Validation          []string `json:"validation_steps"`
-			Scope               string   `json:"scope"`
-			Keywords            []string `json:"keywords"`
-			SuggestedAskQueries []string `json:"suggestedAskQueries"`
+			ID                  string                `json:"id"`
+			PlanID              string                `json:"plan_id"`
+			PlanStatus          string                `json:"plan_status"`
+			Title               string                `json:"title"`
+			Description         string                `json:"description"`
+			Status              string                `json:"status"`
+			Priority            int                   `json:"priority"`
+			Agent               string                `json:"assigned_agent"`
+			Acceptance          []string              `json:"acceptance_criteria"`
+			Validation          []task.ValidationStep `json:"validation_steps"`
+			Scope               string                `json:"scope"`
+			Keywords            []string              `json:"keywords"`
+			SuggestedAskQueries []string              `json:"suggestedAskQueries"`
 		}
 		var jsonTasks []taskJSON
 		for _, tp := range allTasks {
@@ -363,7 +364,11 @@ Examples:
 		if len(t.ValidationSteps) > 0 {
 			fmt.Println("\nValidation Steps:")
 			for _, v := range t.ValidationSteps {
-				fmt.Printf("  - %s\n", v)
+				if v.IsManual() {
+					fmt.Printf("  - [manual] %s\n", v.Text)
+				} else {
+					fmt.Printf("  - %s\n", v.Text)
+				}
 			}
 		}
 		return nil
@@ -413,6 +418,7 @@ var taskUpdateCmd = &cobra.Command{
 var (
 	taskCompleteSummary string
 	taskCompleteFiles   []string
+	taskCompleteVerify  bool
 )
 
 var taskCompleteCmd = &cobra.Command{
@@ -441,13 +447,21 @@ func runTaskComplete(cmd *cobra.Command, args []string) error {
 
 	// Use TaskApp for complete - single source of truth
 	appCtx := app.NewContext(repo)
+	if taskCompleteVerify {
+		cfg, err := getLLMConfigForRole(cmd, llm.RoleQuery)
+		if err != nil {
+			return fmt.Errorf("llm config: %w", err)
+		}
+		appCtx = app.NewContextWithConfig(repo, cfg)
+	}
 	taskApp := app.NewTaskApp(appCtx)
 
 	ctx := context.Background()
 	result, err := taskApp.Complete(ctx, app.TaskCompleteOptions{
-		TaskID:        taskID,
-		Summary:       taskCompleteSummary,
-		FilesModified: taskCompleteFiles,
+		TaskID:         taskID,
+		Summary:        taskCompleteSummary,
+		FilesModified:  taskCompleteFiles,
+		VerifyCriteria: taskCompleteVerify,
 	})
 	if err != nil {
 		return fmt.Errorf("complete task: %w", err)
@@ -472,6 +486,15 @@ func runTaskComplete(cmd *cobra.Command, args []string) error {
 		if result.PRCreated {
 			fmt.Printf("  PR: %s\n", result.PRURL)
 		}
+		if result.Task != nil {
+			for _, v := range result.Task.CriteriaVerification {
+				mark := "?"
+				if v.Status == task.CriterionPass {
+					mark = "✓"
+				}
+				fmt.Printf("  %s %s\n", mark, v.Criterion)
+			}
+		}
 	}
 	return nil
 }
@@ -557,6 +580,7 @@ var (
 	taskNextAutoStart         bool
 	taskNextCreateBranch      bool
 	taskNextSkipUnpushedCheck bool
+	taskNextAgent             string
 )
 
 func runTaskNext(cmd *cobra.Command, args []string) error {
@@ -583,6 +607,7 @@ func runTaskNext(cmd *cobra.Command, args []string) error {
 		AutoStart:         taskNextAutoStart,
 		CreateBranch:      taskNextCreateBranch,
 		SkipUnpushedCheck: taskNextSkipUnpushedCheck,
+		Agent:             taskNextAgent,
 	})
 	if err != nil {
 		return err
@@ -699,6 +724,13 @@ func runTaskCurrent(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n📝 %s\n", result.Task.Description)
 	}
 
+	if len(result.Symbols) > 0 {
+		fmt.Println("\n🔗 Related code:")
+		for _, sym := range result.Symbols {
+			fmt.Printf("   %s (%s) — %s:%d\n", sym.Name, sym.Kind, sym.FilePath, sym.StartLine)
+		}
+	}
+
 	return nil
 }
 
@@ -767,6 +799,53 @@ func runTaskStart(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// taskRefreshCmd re-binds a task's context summary to current knowledge
+var taskRefreshCmd = &cobra.Command{
+	Use:   "refresh [task-id]",
+	Short: "Refresh a task's context summary from current knowledge",
+	Long: `Re-run a task's suggested ask queries and replace its context summary.
+
+The summary is captured when the task is created and goes stale as project
+knowledge changes. Refresh it before starting a task that has waited a while.
+
+Examples:
+  taskwing task refresh task-abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskRefresh,
+}
+
+func runTaskRefresh(cmd *cobra.Command, args []string) error {
+	repo, err := openRepoOrHandleMissingMemory()
+	if err != nil {
+		return err
+	}
+	if repo == nil {
+		return nil
+	}
+	defer func() { _ = repo.Close() }()
+
+	taskApp := app.NewTaskApp(app.NewContext(repo))
+	result, err := taskApp.RefreshContext(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	if !result.Success {
+		fmt.Printf("⚠️  %s\n", result.Message)
+		return nil
+	}
+
+	if !isQuiet() {
+		fmt.Printf("✓ %s\n", result.Message)
+		fmt.Printf("  Task: %s (%s)\n", result.Task.Title, result.Task.ID)
+	}
+	return nil
+}
+
 // taskAddCmd creates a new task and links it to a plan
 var taskAddCmd = &cobra.Command{
 	Use:   "add [title]",
@@ -879,6 +958,7 @@ func init() {
 	taskCmd.AddCommand(taskNextCmd)
 	taskCmd.AddCommand(taskCurrentCmd)
 	taskCmd.AddCommand(taskStartCmd)
+	taskCmd.AddCommand(taskRefreshCmd)
 	taskCmd.AddCommand(taskAddCmd)
 
 	// Task add flags
@@ -899,6 +979,7 @@ func init() {
 	// Task complete flags
 	taskCompleteCmd.Flags().StringVar(&taskCompleteSummary, "summary", "", "Summary of what was accomplished")
 	taskCompleteCmd.Flags().StringSliceVar(&taskCompleteFiles, "files", nil, "Files that were modified (comma-separated)")
+	taskCompleteCmd.Flags().BoolVar(&taskCompleteVerify, "verify-criteria", false, "Check each acceptance criterion against the modified files (uses LLM)")
 
 	// Task next flags
 	taskNextCmd.Flags().StringVar(&taskNextPlanID, "plan", "", "Specific plan ID (defaults to active plan)")
@@ -906,6 +987,7 @@ func init() {
 	taskNextCmd.Flags().BoolVar(&taskNextAutoStart, "auto-start", false, "Automatically claim the task")
 	taskNextCmd.Flags().BoolVar(&taskNextCreateBranch, "create-branch", true, "Create a new git branch for this plan")
 	taskNextCmd.Flags().BoolVar(&taskNextSkipUnpushedCheck, "skip-unpushed-check", false, "Proceed despite unpushed commits (only with --create-branch)")
+	taskNextCmd.Flags().StringVar(&taskNextAgent, "agent", "", "Only tasks assigned to this agent (\"unassigned\" for tasks with no agent)")
 
 	// Task current flags
 	taskCurrentCmd.Flags().StringVar(&taskCurrentSessionID, "session", "", "Session ID to look up")
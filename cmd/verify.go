@@ -0,0 +1,91 @@
+/*
+Copyright © 2025 Joseph Goksu josephgoksu@gmail.com
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/knowledge"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var verifyRejectFlag bool
+
+var verifyCmd = &cobra.Command{
+	Use:          "verify [node-id]",
+	Short:        "Review findings awaiting verification",
+	SilenceUsage: true,
+	Long: `Review knowledge findings that are still pending verification.
+
+Without arguments, lists pending findings (lowest confidence first).
+With a node ID, marks that finding as verified, or rejected with --reject.
+Unverified low-confidence findings rank lower in recall; rejected findings
+are excluded from recall entirely.
+
+Examples:
+  taskwing verify
+  taskwing verify n-abc123
+  taskwing verify n-abc123 --reject`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().BoolVar(&verifyRejectFlag, "reject", false, "Mark the finding as rejected instead of verified")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	repo, err := openRepoOrHandleMissingMemory()
+	if err != nil {
+		return err
+	}
+	if repo == nil {
+		return nil
+	}
+	defer func() { _ = repo.Close() }()
+
+	// Verification is a pure state change; no LLM calls are made
+	ks := knowledge.NewService(repo, llm.Config{})
+
+	if len(args) == 0 {
+		pending, err := ks.ListPendingVerification()
+		if err != nil {
+			return err
+		}
+		if isJSON() {
+			return printJSON(pending)
+		}
+		if len(pending) == 0 {
+			cmd.Println("No findings pending verification.")
+			return nil
+		}
+		for _, n := range pending {
+			cmd.Printf("%s  [%s] %s (confidence %.2f)\n", n.ID, n.Type, n.Summary, n.ConfidenceScore)
+		}
+		if !isQuiet() {
+			cmd.Printf("\n%d pending. Run 'taskwing verify <node-id>' to verify, or add --reject.\n", len(pending))
+		}
+		return nil
+	}
+
+	nodeID := args[0]
+	status := string(core.VerificationStatusVerified)
+	if verifyRejectFlag {
+		status = string(core.VerificationStatusRejected)
+	}
+	if err := ks.Verify(nodeID, status); err != nil {
+		return fmt.Errorf("verify %s: %w", nodeID, err)
+	}
+
+	if isJSON() {
+		return printJSON(map[string]string{"id": nodeID, "verificationStatus": status})
+	}
+	if !isQuiet() {
+		fmt.Printf("✓ Marked %s as %s\n", nodeID, status)
+	}
+	return nil
+}
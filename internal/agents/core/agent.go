@@ -5,6 +5,9 @@ package core
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -65,7 +68,60 @@ type Input struct {
 	ExistingContext map[string]any // Context from previous agents
 	MaxTokens       int
 	Verbose         bool
-	Workspace       string // Workspace/service name for monorepo support ('root' for global, service name for scoped)
+	Workspace       string           // Workspace/service name for monorepo support ('root' for global, service name for scoped)
+	IncludeDirs     []string         // If set, doc gathering is limited to these directories (relative to BasePath)
+	ExcludeDirs     []string         // Directories (relative to BasePath) skipped during doc gathering
+	GitHistory      GitHistoryWindow // Limits the commits the git agent reads; zero = default
+}
+
+// GitHistoryWindow limits how much git history the git agent reads. The zero
+// value reads the default number of most recent commits.
+type GitHistoryWindow struct {
+	Since      time.Duration // Only commits newer than this
+	MaxCommits int           // At most this many commits
+}
+
+// IsZero reports whether the window leaves the default history in place.
+func (w GitHistoryWindow) IsZero() bool {
+	return w.Since <= 0 && w.MaxCommits <= 0
+}
+
+// ParseGitHistoryWindow parses a --git-since value: a commit count ("500") or
+// a duration, either Go syntax ("720h") or days/weeks ("90d", "12w"). An empty
+// value is the zero window.
+func ParseGitHistoryWindow(s string) (GitHistoryWindow, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return GitHistoryWindow{}, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 {
+			return GitHistoryWindow{}, fmt.Errorf("git history commit count must be positive, got %d", n)
+		}
+		return GitHistoryWindow{MaxCommits: n}, nil
+	}
+
+	var d time.Duration
+	var err error
+	switch unit := s[len(s)-1]; unit {
+	case 'd', 'w':
+		var n int
+		if n, err = strconv.Atoi(s[:len(s)-1]); err == nil {
+			d = time.Duration(n) * 24 * time.Hour
+			if unit == 'w' {
+				d *= 7
+			}
+		}
+	default:
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil {
+		return GitHistoryWindow{}, fmt.Errorf("invalid git history window %q: want a commit count (500) or a duration (90d, 12w, 720h)", s)
+	}
+	if d <= 0 {
+		return GitHistoryWindow{}, fmt.Errorf("git history window must be positive, got %q", s)
+	}
+	return GitHistoryWindow{Since: d}, nil
 }
 
 // Output captures the results of an agent's analysis.
@@ -6,9 +6,11 @@ package core
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/cloudwego/eino/schema"
+	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/llm"
 )
 
@@ -47,6 +49,32 @@ func (b *BaseAgent) CreateCloseableChatModel(ctx context.Context) (*llm.Closeabl
 	return chatModel, nil
 }
 
+// TimeoutOption returns the chain option bounding each LLM invocation by the
+// configured agent timeout (llm.agent_timeout). Zero leaves calls unbounded.
+func (b *BaseAgent) TimeoutOption() ChainOption {
+	return WithTimeout(b.llmConfig.AgentTimeout)
+}
+
+// TimeoutContext bounds ctx by the configured agent timeout (llm.agent_timeout)
+// for LLM calls made outside a DeterministicChain, such as streams. Zero
+// leaves ctx unbounded. Callers must call the returned cancel.
+func (b *BaseAgent) TimeoutContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.llmConfig.AgentTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, b.llmConfig.AgentTimeout)
+}
+
+// PromptTemplate returns the agent's prompt template, preferring a project override
+// at .taskwing/prompts/<name>.tmpl. Invalid overrides are logged and the built-in is used.
+func (b *BaseAgent) PromptTemplate(basePath, builtin string) string {
+	tmpl, err := config.LoadPromptTemplate(basePath, b.name, builtin)
+	if err != nil {
+		slog.Warn("ignoring prompt override", "agent", b.name, "error", err)
+	}
+	return tmpl
+}
+
 // Generate sends messages to the LLM and returns the response content.
 func (b *BaseAgent) Generate(ctx context.Context, messages []*schema.Message) (string, error) {
 	chatModel, err := b.CreateCloseableChatModel(ctx)
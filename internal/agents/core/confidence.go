@@ -0,0 +1,129 @@
+package core
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Confidence labels attached to findings.
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// ConfidenceCalibration holds the thresholds and mappings used to turn
+// LLM-reported confidence (numbers, labels, or severities) into scores and labels.
+//
+// Thresholds can be tuned via .taskwing.yaml:
+//
+//	agents:
+//	  confidence:
+//	    high_threshold: 0.85
+//	    medium_threshold: 0.6
+type ConfidenceCalibration struct {
+	HighThreshold   float64 // Scores >= this are "high"
+	MediumThreshold float64 // Scores >= this (and below high) are "medium"
+
+	LabelScores    map[string]float64 // Score assigned to a label-only confidence
+	DefaultScore   float64            // Score when confidence is missing or unrecognized
+	SeverityScores map[string]float64 // Score implied by a constraint severity
+}
+
+// DefaultConfidenceCalibration returns the built-in calibration.
+func DefaultConfidenceCalibration() ConfidenceCalibration {
+	return ConfidenceCalibration{
+		HighThreshold:   0.8,
+		MediumThreshold: 0.5,
+		LabelScores: map[string]float64{
+			ConfidenceHigh:   0.9,
+			ConfidenceMedium: 0.7,
+			ConfidenceLow:    0.4,
+		},
+		DefaultScore: 0.5,
+		SeverityScores: map[string]float64{
+			"critical": 0.95,
+			"high":     0.85,
+			"medium":   0.7,
+		},
+	}
+}
+
+var (
+	calibrationMu sync.RWMutex
+	calibration   *ConfidenceCalibration // nil = defaults with config overrides
+)
+
+// SetConfidenceCalibration replaces the active calibration. Pass nil to restore
+// the defaults (with .taskwing.yaml overrides).
+func SetConfidenceCalibration(c *ConfidenceCalibration) {
+	calibrationMu.Lock()
+	defer calibrationMu.Unlock()
+	calibration = c
+}
+
+// ActiveConfidenceCalibration returns the calibration used by all agents.
+func ActiveConfidenceCalibration() ConfidenceCalibration {
+	calibrationMu.RLock()
+	c := calibration
+	calibrationMu.RUnlock()
+	if c != nil {
+		return *c
+	}
+
+	cal := DefaultConfidenceCalibration()
+	if viper.IsSet("agents.confidence.high_threshold") {
+		cal.HighThreshold = viper.GetFloat64("agents.confidence.high_threshold")
+	}
+	if viper.IsSet("agents.confidence.medium_threshold") {
+		cal.MediumThreshold = viper.GetFloat64("agents.confidence.medium_threshold")
+	}
+	return cal
+}
+
+// Label converts a numeric confidence score to a label.
+func (c ConfidenceCalibration) Label(score float64) string {
+	switch {
+	case score >= c.HighThreshold:
+		return ConfidenceHigh
+	case score >= c.MediumThreshold:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
+
+// Score converts a confidence label to a numeric score.
+func (c ConfidenceCalibration) Score(label string) float64 {
+	if score, ok := c.LabelScores[strings.ToLower(strings.TrimSpace(label))]; ok {
+		return score
+	}
+	return c.DefaultScore
+}
+
+// SeverityScore returns the score implied by a severity ("critical", "high", ...).
+// The second return value is false for unknown severities.
+func (c ConfidenceCalibration) SeverityScore(severity string) (float64, bool) {
+	score, ok := c.SeverityScores[strings.ToLower(strings.TrimSpace(severity))]
+	return score, ok
+}
+
+// ConfidenceLabelFromScore converts numeric confidence to label.
+func ConfidenceLabelFromScore(score float64) string {
+	return ActiveConfidenceCalibration().Label(score)
+}
+
+// ConfidenceScoreFromLabel converts label to numeric confidence.
+func ConfidenceScoreFromLabel(label string) float64 {
+	return ActiveConfidenceCalibration().Score(label)
+}
+
+// ConfidenceScoreFromSeverity returns the score for a severity, or fallback if unknown.
+func ConfidenceScoreFromSeverity(severity string, fallback float64) float64 {
+	if score, ok := ActiveConfidenceCalibration().SeverityScore(severity); ok {
+		return score
+	}
+	return fallback
+}
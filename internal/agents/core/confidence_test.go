@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestConfidenceLabelFromScore_Boundaries(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{1.0, ConfidenceHigh},
+		{0.8, ConfidenceHigh},
+		{0.79, ConfidenceMedium},
+		{0.7, ConfidenceMedium},
+		{0.5, ConfidenceMedium},
+		{0.49, ConfidenceLow},
+		{0, ConfidenceLow},
+	}
+	for _, tt := range tests {
+		if got := ConfidenceLabelFromScore(tt.score); got != tt.want {
+			t.Errorf("ConfidenceLabelFromScore(%v) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestConfidenceScoreFromSeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     float64
+	}{
+		{"critical", 0.95},
+		{"high", 0.85},
+		{"Medium", 0.7},
+		{"low", 0.5}, // unmapped: fallback
+		{"", 0.5},
+	}
+	for _, tt := range tests {
+		if got := ConfidenceScoreFromSeverity(tt.severity, 0.5); got != tt.want {
+			t.Errorf("ConfidenceScoreFromSeverity(%q) = %v, want %v", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestConfidenceCalibration_Overrides(t *testing.T) {
+	viper.Set("agents.confidence.high_threshold", 0.9)
+	t.Cleanup(func() { viper.Set("agents.confidence.high_threshold", nil) })
+
+	if got := ConfidenceLabelFromScore(0.85); got != ConfidenceMedium {
+		t.Errorf("with high_threshold=0.9, 0.85 should be medium, got %q", got)
+	}
+
+	custom := DefaultConfidenceCalibration()
+	custom.MediumThreshold = 0.75
+	SetConfidenceCalibration(&custom)
+	t.Cleanup(func() { SetConfidenceCalibration(nil) })
+
+	if got := ConfidenceLabelFromScore(0.7); got != ConfidenceLow {
+		t.Errorf("with medium_threshold=0.75, 0.7 should be low, got %q", got)
+	}
+	if score, label := ParseConfidence(nil); score != custom.DefaultScore || label != ConfidenceLow {
+		t.Errorf("ParseConfidence(nil) = %v, %q", score, label)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"reflect"
 	"strings"
 	"text/template"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
+	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/spf13/viper"
 )
 
@@ -32,18 +34,33 @@ const (
 	JitterFactor = 0.5
 )
 
+// ErrInvocationTimeout is returned when a chain invocation exceeds its WithTimeout deadline.
+var ErrInvocationTimeout = errors.New("agent invocation timed out")
+
 // DeterministicChain is a reusable pipeline: Map -> Template -> Model -> Parser -> Output
 type DeterministicChain[T any] struct {
 	chain      compose.Runnable[map[string]any, T]
+	chatModel  model.BaseChatModel // Used directly for the reformat call
 	name       string
+	timeout    time.Duration
 	renderFunc func(ctx context.Context, input map[string]any) ([]*schema.Message, error)
+	schema     *ResponseSchema
 }
 
+// retryBackoff returns the delay before a retry attempt. Overridden in tests.
+var retryBackoff = calculateBackoffWithJitter
+
+// unparsedOutputKey is the context key under which Invoke collects the last
+// response the parser rejected, for the reformat call.
+type unparsedOutputKey struct{}
+
 // ChainOption configures optional DeterministicChain behavior.
 type ChainOption func(*chainConfig)
 
 type chainConfig struct {
 	systemPrompt string
+	timeout      time.Duration
+	schema       *ResponseSchema
 }
 
 // WithSystemPrompt sets a stable system message prepended before the user template.
@@ -55,6 +72,22 @@ func WithSystemPrompt(prompt string) ChainOption {
 	}
 }
 
+// WithTimeout bounds each Invoke call, retries included. Zero means no deadline.
+func WithTimeout(d time.Duration) ChainOption {
+	return func(c *chainConfig) {
+		c.timeout = d
+	}
+}
+
+// WithResponseSchema validates each raw response against schema before it is
+// unmarshaled. Responses that do not match fail with ErrSchemaValidation and
+// are retried like JSON parse errors.
+func WithResponseSchema(s *ResponseSchema) ChainOption {
+	return func(c *chainConfig) {
+		c.schema = s
+	}
+}
+
 // NewDeterministicChain creates a standardized Eino chain for deterministic tasks.
 func NewDeterministicChain[T any](
 	ctx context.Context,
@@ -74,8 +107,23 @@ func NewDeterministicChain[T any](
 	if err != nil {
 		return nil, fmt.Errorf("parse template: %w", err)
 	}
+	requiredVars, err := config.TemplateVars(templateStr)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
 
 	templateFunc := func(ctx context.Context, input map[string]any) ([]*schema.Message, error) {
+		// Missing map keys render as "<no value>"; fail loudly instead
+		var missing []string
+		for _, v := range requiredVars {
+			if _, ok := input[v]; !ok {
+				missing = append(missing, v)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("template %s: missing input variables: %s", name, strings.Join(missing, ", "))
+		}
+
 		var buf bytes.Buffer
 		if err := tmpl.Execute(&buf, input); err != nil {
 			return nil, fmt.Errorf("execute template: %w", err)
@@ -95,9 +143,17 @@ func NewDeterministicChain[T any](
 		return chatModel.Generate(ctx, input)
 	}
 
-	// 3. Parser function (wrapping our generic ParseJSONResponse)
+	// 3. Parser function (wrapping our generic ParseJSONResponse). Rejected
+	// output is handed back to Invoke, which may ask for a reformat once the
+	// retries are exhausted.
 	parserFunc := func(ctx context.Context, output *schema.Message) (T, error) {
-		return ParseJSONResponse[T](output.Content)
+		parsed, err := ParseValidatedJSONResponse[T](output.Content, cfg.schema)
+		if err != nil && (isJSONParseError(err) || isSchemaError(err)) {
+			if unparsed, ok := ctx.Value(unparsedOutputKey{}).(*string); ok {
+				*unparsed = output.Content
+			}
+		}
+		return parsed, err
 	}
 
 	// 4. Chain Construction using Graph
@@ -119,11 +175,53 @@ func NewDeterministicChain[T any](
 
 	return &DeterministicChain[T]{
 		chain:      compiledChain,
+		chatModel:  chatModel,
 		name:       name,
+		timeout:    cfg.timeout,
 		renderFunc: templateFunc,
+		schema:     cfg.schema,
 	}, nil
 }
 
+// reformatPrompt asks the model to repair its own unparseable output. It
+// carries only that output and the expected shape, not the original prompt,
+// so the call stays small.
+const reformatPrompt = `Your previous response could not be parsed: %v
+
+Rewrite it as valid JSON with the same content, matching this shape (keys marked ? are optional):
+%s
+
+Respond with ONLY the JSON: no prose, no markdown code fences.
+
+Previous response:
+%s`
+
+// reformatResponse re-sends raw to the model asking it to fix the JSON and
+// parses the reply. ok is false when the call fails or the reply still does
+// not parse; the caller then reports the original parse error.
+func reformatResponse[T any](ctx context.Context, name string, chatModel model.BaseChatModel, raw string, parseErr error, s *ResponseSchema) (T, bool) {
+	var zero T
+	shape := s.String()
+	if s == nil {
+		shape = describeJSONType(reflect.TypeFor[T]())
+	}
+	reply, err := chatModel.Generate(ctx, []*schema.Message{schema.UserMessage(fmt.Sprintf(reformatPrompt, parseErr, shape, raw))})
+	if err != nil || reply == nil {
+		if shouldLogRetryDetails() {
+			log.Printf("[eino] chain=%s reformat call failed: %v", name, err)
+		}
+		return zero, false
+	}
+	fixed, err := ParseValidatedJSONResponse[T](reply.Content, s)
+	if err != nil {
+		if shouldLogRetryDetails() {
+			log.Printf("[eino] chain=%s reformat reply still unparseable: %v", name, err)
+		}
+		return zero, false
+	}
+	return fixed, true
+}
+
 // RenderMessages renders the prompt template into messages without calling the LLM.
 // Used by the batch API path to collect prompts for batch submission.
 func (c *DeterministicChain[T]) RenderMessages(ctx context.Context, input map[string]any) ([]*schema.Message, error) {
@@ -136,15 +234,21 @@ func (c *DeterministicChain[T]) RenderMessages(ctx context.Context, input map[st
 // ParseResponse parses raw LLM response text into the chain's output type.
 // Used by the batch API path to parse batch results without re-running the chain.
 func (c *DeterministicChain[T]) ParseResponse(raw string) (T, error) {
-	return ParseJSONResponse[T](raw)
+	return ParseValidatedJSONResponse[T](raw, c.schema)
 }
 
 // Invoke executes the chain with manual timing and retry logic for transient failures.
 // Retry policy:
 // - Timeout errors (context deadline, HTTP timeout): exponential backoff with jitter, up to MaxRetries attempts
-// - JSON parse errors: exponential backoff, up to MaxRetries attempts
+// - JSON parse and schema validation errors: exponential backoff, up to MaxRetries attempts
 // - Rate limit errors: exponential backoff with longer initial delay
 // - Permanent errors (invalid request, auth): no retry
+//
+// When every attempt fails to parse, the last output is sent back once, alone,
+// with the expected shape and a request to fix the JSON.
+//
+// With WithTimeout set, the whole call runs under a derived deadline and
+// returns an error wrapping ErrInvocationTimeout once it passes.
 func (c *DeterministicChain[T]) Invoke(ctx context.Context, input map[string]any) (T, string, time.Duration, error) {
 	start := time.Now()
 
@@ -152,11 +256,27 @@ func (c *DeterministicChain[T]) Invoke(ctx context.Context, input map[string]any
 	var err error
 	var lastErr error
 
+	parent := ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	// timedOut reports whether our own deadline (not the caller's) has passed
+	timedOut := func() bool {
+		return c.timeout > 0 && ctx.Err() != nil && parent.Err() == nil
+	}
+	timeoutErr := func() error {
+		return fmt.Errorf("chain %s: %w after %v", c.name, ErrInvocationTimeout, c.timeout)
+	}
+	var unparsed string
+	ctx = context.WithValue(ctx, unparsedOutputKey{}, &unparsed)
+
 	// Retry loop for handling transient LLM failures
 	for attempt := 0; attempt <= MaxRetries; attempt++ {
 		if attempt > 0 {
 			// Calculate exponential backoff with jitter
-			delay := calculateBackoffWithJitter(attempt)
+			delay := retryBackoff(attempt)
 			errType := classifyError(lastErr)
 
 			if shouldLogRetryDetails() {
@@ -166,6 +286,9 @@ func (c *DeterministicChain[T]) Invoke(ctx context.Context, input map[string]any
 
 			select {
 			case <-ctx.Done():
+				if timedOut() {
+					return output, "", time.Since(start), timeoutErr()
+				}
 				return output, "", time.Since(start), ctx.Err()
 			case <-time.After(delay):
 			}
@@ -186,6 +309,11 @@ func (c *DeterministicChain[T]) Invoke(ctx context.Context, input map[string]any
 		// Always capture the last error for reporting
 		lastErr = err
 
+		// Retrying past our own deadline cannot succeed
+		if timedOut() {
+			return output, "", time.Since(start), timeoutErr()
+		}
+
 		// Check if error is retryable (timeout, JSON parse, rate limit, network)
 		if isRetryableError(err) {
 			continue // Retry
@@ -196,7 +324,12 @@ func (c *DeterministicChain[T]) Invoke(ctx context.Context, input map[string]any
 		return output, "", duration, err
 	}
 
-	// All retries exhausted
+	// All retries exhausted: output that never parsed gets one reformat call
+	if unparsed != "" && (isJSONParseError(lastErr) || isSchemaError(lastErr)) {
+		if fixed, ok := reformatResponse[T](ctx, c.name, c.chatModel, unparsed, lastErr, c.schema); ok {
+			return fixed, "", time.Since(start), nil
+		}
+	}
 	duration := time.Since(start)
 	if shouldLogRetryDetails() {
 		log.Printf("[eino] chain=%s exhausted all %d retries, total_duration=%v, last_error=%v",
@@ -246,6 +379,9 @@ func classifyError(err error) string {
 	if isJSONParseError(err) {
 		return "json_parse"
 	}
+	if isSchemaError(err) {
+		return "schema"
+	}
 	if isNetworkError(err) {
 		return "network"
 	}
@@ -253,13 +389,13 @@ func classifyError(err error) string {
 }
 
 // isRetryableError checks if the error should trigger a retry.
-// Retries: timeout, JSON parse, rate limit, and transient network errors.
+// Retries: timeout, JSON parse, schema validation, rate limit, and transient network errors.
 // Does NOT retry: permanent errors (auth failures, invalid requests).
 func isRetryableError(err error) bool {
 	if err == nil {
 		return false
 	}
-	return isTimeoutError(err) || isJSONParseError(err) || isRateLimitError(err) || isNetworkError(err)
+	return isTimeoutError(err) || isJSONParseError(err) || isSchemaError(err) || isRateLimitError(err) || isNetworkError(err)
 }
 
 // isTimeoutError checks for context deadline exceeded and HTTP timeout errors.
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestDeterministicChain_MissingTemplateVariable(t *testing.T) {
+	chain, err := NewDeterministicChain[map[string]any](
+		context.Background(),
+		"test",
+		nil,
+		"Project: {{.ProjectName}}\n{{.Missing}}",
+	)
+	if err != nil {
+		t.Fatalf("NewDeterministicChain: %v", err)
+	}
+
+	_, err = chain.RenderMessages(context.Background(), map[string]any{"ProjectName": "demo"})
+	if err == nil {
+		t.Fatal("expected error for missing template variable")
+	}
+	if !strings.Contains(err.Error(), "Missing") {
+		t.Errorf("error should name the missing variable, got %v", err)
+	}
+
+	msgs, err := chain.RenderMessages(context.Background(), map[string]any{"ProjectName": "demo", "Missing": "present"})
+	if err != nil {
+		t.Fatalf("RenderMessages: %v", err)
+	}
+	if !strings.Contains(msgs[len(msgs)-1].Content, "present") {
+		t.Errorf("unexpected prompt: %q", msgs[len(msgs)-1].Content)
+	}
+}
+
+// blockingChatModel never answers; it waits until the request context ends.
+type blockingChatModel struct{}
+
+func (blockingChatModel) Generate(ctx context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingChatModel) Stream(ctx context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestDeterministicChain_InvokeTimeout(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	chain, err := NewDeterministicChain[map[string]any](
+		context.Background(),
+		"blocking",
+		blockingChatModel{},
+		"Say {{.Word}}",
+		WithTimeout(timeout),
+	)
+	if err != nil {
+		t.Fatalf("NewDeterministicChain: %v", err)
+	}
+
+	start := time.Now()
+	_, _, _, err = chain.Invoke(context.Background(), map[string]any{"Word": "hi"})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrInvocationTimeout) {
+		t.Fatalf("expected ErrInvocationTimeout, got %v", err)
+	}
+	if elapsed > timeout+500*time.Millisecond {
+		t.Errorf("Invoke returned after %v, want about %v", elapsed, timeout)
+	}
+}
+
+// scriptedChatModel answers each Generate call with the next reply and
+// records the messages it was sent.
+type scriptedChatModel struct {
+	replies []string
+	calls   [][]*schema.Message
+}
+
+func (m *scriptedChatModel) Generate(_ context.Context, msgs []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	m.calls = append(m.calls, msgs)
+	if len(m.calls) > len(m.replies) {
+		return nil, errors.New("unexpected call")
+	}
+	return schema.AssistantMessage(m.replies[len(m.calls)-1], nil), nil
+}
+
+func (m *scriptedChatModel) Stream(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not supported")
+}
+
+func TestDeterministicChain_ReformatsUnparseableOutput(t *testing.T) {
+	backoff := retryBackoff
+	retryBackoff = func(int) time.Duration { return 0 }
+	t.Cleanup(func() { retryBackoff = backoff })
+
+	const prose = "Sure! Here is the answer: {answer: 'ok'} Let me know if you need more."
+	newChain := func(chatModel model.BaseChatModel) *DeterministicChain[struct {
+		Answer string `json:"answer"`
+	}] {
+		chain, err := NewDeterministicChain[struct {
+			Answer string `json:"answer"`
+		}](context.Background(), "reformat", chatModel, "Answer about {{.Topic}}")
+		if err != nil {
+			t.Fatalf("NewDeterministicChain: %v", err)
+		}
+		return chain
+	}
+	input := map[string]any{"Topic": "deployment pipelines"}
+
+	// A retry that parses needs no reformat call
+	chatModel := &scriptedChatModel{replies: []string{prose, `{"answer": "retried"}`}}
+	out, _, _, err := newChain(chatModel).Invoke(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if out.Answer != "retried" || len(chatModel.calls) != 2 {
+		t.Fatalf("answer = %q after %d calls, want the retried answer after 2", out.Answer, len(chatModel.calls))
+	}
+
+	// Every attempt unparseable: one reformat call after the retries
+	replies := make([]string, MaxRetries+1, MaxRetries+2)
+	for i := range replies {
+		replies[i] = prose
+	}
+	chatModel = &scriptedChatModel{replies: append(replies, `{"answer": "ok"}`)}
+	out, _, _, err = newChain(chatModel).Invoke(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if out.Answer != "ok" {
+		t.Errorf("answer = %q, want ok", out.Answer)
+	}
+	if len(chatModel.calls) != MaxRetries+2 {
+		t.Fatalf("model called %d times, want %d attempts then one reformat call", len(chatModel.calls), MaxRetries+1)
+	}
+	reformat := chatModel.calls[MaxRetries+1]
+	if len(reformat) != 1 || !strings.Contains(reformat[0].Content, prose) || !strings.Contains(reformat[0].Content, "ONLY the JSON") {
+		t.Errorf("reformat call should carry only the previous output and the fix request, got %+v", reformat)
+	}
+	if !strings.Contains(reformat[0].Content, `{"answer": string}`) {
+		t.Errorf("reformat call should state the expected shape, got %q", reformat[0].Content)
+	}
+	if strings.Contains(reformat[0].Content, "deployment pipelines") {
+		t.Error("reformat call should not resend the original prompt")
+	}
+}
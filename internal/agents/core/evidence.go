@@ -0,0 +1,65 @@
+package core
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EvidenceMode decides what analysis agents do with findings that cite no
+// file. Set it via .taskwing.yaml:
+//
+//	agents:
+//	  evidence:
+//	    mode: drop # off (default), downrank or drop
+type EvidenceMode string
+
+const (
+	EvidenceModeOff      EvidenceMode = "off"      // Keep every finding as reported
+	EvidenceModeDownrank EvidenceMode = "downrank" // Halve the confidence of unsupported findings
+	EvidenceModeDrop     EvidenceMode = "drop"     // Discard unsupported findings
+)
+
+// evidenceDownrankFactor scales the confidence of findings without evidence
+// in EvidenceModeDownrank.
+const evidenceDownrankFactor = 0.5
+
+// ActiveEvidenceMode returns the configured evidence mode. Unknown values
+// fall back to EvidenceModeOff.
+func ActiveEvidenceMode() EvidenceMode {
+	switch mode := EvidenceMode(strings.ToLower(strings.TrimSpace(viper.GetString("agents.evidence.mode")))); mode {
+	case EvidenceModeDownrank, EvidenceModeDrop:
+		return mode
+	default:
+		return EvidenceModeOff
+	}
+}
+
+// ApplyEvidenceMode drops or down-ranks findings without file evidence
+// according to mode and returns the findings to keep.
+func ApplyEvidenceMode(findings []Finding, mode EvidenceMode) []Finding {
+	if mode != EvidenceModeDownrank && mode != EvidenceModeDrop {
+		return findings
+	}
+
+	kept := findings[:0]
+	var unsupported int
+	for _, f := range findings {
+		if f.HasEvidence() {
+			kept = append(kept, f)
+			continue
+		}
+		unsupported++
+		if mode == EvidenceModeDrop {
+			continue
+		}
+		f.ConfidenceScore *= evidenceDownrankFactor
+		f.Confidence = ConfidenceLabelFromScore(f.ConfidenceScore)
+		kept = append(kept, f)
+	}
+	if unsupported > 0 {
+		slog.Debug("findings without file evidence", "mode", mode, "count", unsupported)
+	}
+	return kept
+}
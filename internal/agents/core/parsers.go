@@ -45,7 +45,8 @@ func ParseConfidence(raw any) (float64, string) {
 	case string:
 		return ConfidenceScoreFromLabel(v), v
 	default:
-		return 0.5, "medium"
+		score := ActiveConfidenceCalibration().DefaultScore
+		return score, ConfidenceLabelFromScore(score)
 	}
 }
 
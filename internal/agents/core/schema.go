@@ -0,0 +1,286 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/utils"
+)
+
+// ErrSchemaValidation is returned when an LLM response parses as JSON but does
+// not have the shape the agent expects. Chains treat it as retryable.
+var ErrSchemaValidation = errors.New("response does not match schema")
+
+// maxSchemaProblems caps how many violations are listed in a validation error.
+const maxSchemaProblems = 5
+
+// JSON value types accepted by ResponseSchema.Types.
+const (
+	JSONObject  = "object"
+	JSONArray   = "array"
+	JSONString  = "string"
+	JSONNumber  = "number"
+	JSONBoolean = "boolean"
+	JSONNull    = "null"
+)
+
+// ResponseSchema is a minimal JSON schema for agent responses: allowed value
+// types, object properties with required keys, and array items. Keys not
+// listed in Properties are allowed and not checked.
+type ResponseSchema struct {
+	Types      []string // Allowed JSON types; empty allows any
+	Properties map[string]*ResponseSchema
+	Required   []string
+	Items      *ResponseSchema
+}
+
+// ObjectSchema describes an object with the given properties, of which the
+// named keys are required.
+func ObjectSchema(properties map[string]*ResponseSchema, required ...string) *ResponseSchema {
+	return &ResponseSchema{Types: []string{JSONObject}, Properties: properties, Required: required}
+}
+
+// ArraySchema describes an array whose elements match items.
+func ArraySchema(items *ResponseSchema) *ResponseSchema {
+	return &ResponseSchema{Types: []string{JSONArray}, Items: items}
+}
+
+// TypeSchema describes a scalar value of one of the given types.
+func TypeSchema(types ...string) *ResponseSchema {
+	return &ResponseSchema{Types: types}
+}
+
+// EvidenceSchema matches EvidenceJSON entries.
+var EvidenceSchema = ObjectSchema(map[string]*ResponseSchema{
+	"file_path":  TypeSchema(JSONString),
+	"start_line": TypeSchema(JSONNumber),
+	"end_line":   TypeSchema(JSONNumber),
+	"snippet":    TypeSchema(JSONString),
+}, "file_path")
+
+// ConfidenceSchema matches confidence values accepted by ParseConfidence.
+var ConfidenceSchema = TypeSchema(JSONNumber, JSONString)
+
+// Validate checks a decoded JSON value (as produced by encoding/json into any)
+// against the schema. The error wraps ErrSchemaValidation and names the
+// offending paths.
+func (s *ResponseSchema) Validate(value any) error {
+	var problems []string
+	s.validate(value, "$", &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	if len(problems) > maxSchemaProblems {
+		problems = append(problems[:maxSchemaProblems], fmt.Sprintf("and %d more", len(problems)-maxSchemaProblems))
+	}
+	return fmt.Errorf("%w: %s", ErrSchemaValidation, strings.Join(problems, "; "))
+}
+
+func (s *ResponseSchema) validate(value any, path string, problems *[]string) {
+	if s == nil {
+		return
+	}
+	if typ := jsonType(value); len(s.Types) > 0 && !slices.Contains(s.Types, typ) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Types, " or "), typ))
+		return
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: required field missing", path, key))
+			}
+		}
+		for key, prop := range s.Properties {
+			if child, ok := v[key]; ok {
+				prop.validate(child, path+"."+key, problems)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	}
+}
+
+// String renders the schema compactly for prompts, e.g.
+// {"title": string, "evidence"?: [{"file_path": string}]}. Keys marked ? are
+// optional. A nil schema renders as "any".
+func (s *ResponseSchema) String() string {
+	var b strings.Builder
+	s.describe(&b)
+	return b.String()
+}
+
+func (s *ResponseSchema) describe(b *strings.Builder) {
+	switch {
+	case s == nil:
+		b.WriteString("any")
+	case len(s.Properties) > 0 || len(s.Required) > 0:
+		keys := slices.Clone(s.Required)
+		for key := range s.Properties {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		b.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(b, "%q", key)
+			if !slices.Contains(s.Required, key) {
+				b.WriteString("?")
+			}
+			b.WriteString(": ")
+			s.Properties[key].describe(b)
+		}
+		b.WriteString("}")
+	case s.Items != nil:
+		b.WriteString("[")
+		s.Items.describe(b)
+		b.WriteString("]")
+	case len(s.Types) > 0:
+		b.WriteString(strings.Join(s.Types, " | "))
+	default:
+		b.WriteString("any")
+	}
+}
+
+// describeJSONType renders the JSON shape encoding/json gives t, in the
+// notation of ResponseSchema.String. It describes chains without a schema;
+// omitempty fields are marked optional.
+func describeJSONType(t reflect.Type) string {
+	var b strings.Builder
+	describeGoType(&b, t, 0)
+	return b.String()
+}
+
+// maxDescribeDepth stops describeJSONType on recursive types.
+const maxDescribeDepth = 8
+
+func describeGoType(b *strings.Builder, t reflect.Type, depth int) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if depth > maxDescribeDepth {
+		b.WriteString("any")
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		b.WriteString("{")
+		written := 0
+		describeGoFields(b, t, depth, &written)
+		b.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b.WriteString(JSONString) // []byte encodes as base64
+			return
+		}
+		b.WriteString("[")
+		describeGoType(b, t.Elem(), depth+1)
+		b.WriteString("]")
+	case reflect.Map:
+		b.WriteString(JSONObject)
+	case reflect.String:
+		b.WriteString(JSONString)
+	case reflect.Bool:
+		b.WriteString(JSONBoolean)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		b.WriteString(JSONNumber)
+	default:
+		b.WriteString("any")
+	}
+}
+
+// describeGoFields writes the JSON keys of a struct's fields, flattening
+// embedded structs the way encoding/json does.
+func describeGoFields(b *strings.Builder, t reflect.Type, depth int, written *int) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				describeGoFields(b, ft, depth, written)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if *written > 0 {
+			b.WriteString(", ")
+		}
+		*written++
+		fmt.Fprintf(b, "%q", name)
+		if strings.Contains(opts, "omitempty") {
+			b.WriteString("?")
+		}
+		b.WriteString(": ")
+		describeGoType(b, f.Type, depth+1)
+	}
+}
+
+// jsonType names the JSON type of a value decoded by encoding/json.
+func jsonType(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return JSONObject
+	case []any:
+		return JSONArray
+	case string:
+		return JSONString
+	case float64:
+		return JSONNumber
+	case bool:
+		return JSONBoolean
+	case nil:
+		return JSONNull
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// ParseValidatedJSONResponse is ParseJSONResponse with the raw response first
+// checked against schema. A nil schema skips validation.
+func ParseValidatedJSONResponse[T any](response string, schema *ResponseSchema) (T, error) {
+	if schema != nil {
+		raw, err := utils.ExtractAndParseJSON[any](response)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		if err := schema.Validate(raw); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+	return ParseJSONResponse[T](response)
+}
+
+// isSchemaError checks for schema validation failures. The string check covers
+// errors whose wrapping was lost inside the chain graph.
+func isSchemaError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrSchemaValidation) || strings.Contains(err.Error(), ErrSchemaValidation.Error())
+}
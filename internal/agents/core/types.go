@@ -139,29 +139,3 @@ func AggregateRelationships(outputs []Output) []Relationship {
 	}
 	return all
 }
-
-// ConfidenceLabelFromScore converts numeric confidence to label.
-func ConfidenceLabelFromScore(score float64) string {
-	switch {
-	case score >= 0.8:
-		return "high"
-	case score >= 0.5:
-		return "medium"
-	default:
-		return "low"
-	}
-}
-
-// ConfidenceScoreFromLabel converts label to numeric confidence.
-func ConfidenceScoreFromLabel(label string) float64 {
-	switch label {
-	case "high":
-		return 0.9
-	case "medium":
-		return 0.7
-	case "low":
-		return 0.4
-	default:
-		return 0.5
-	}
-}
@@ -147,8 +147,34 @@ type reactParseResult struct {
 	} `json:"patterns"`
 }
 
+// reactResponseSchema is the shape reactParseResult expects: decisions and
+// patterns need the fields their findings are built from.
+var reactResponseSchema = core.ObjectSchema(map[string]*core.ResponseSchema{
+	"decisions": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"title":         core.TypeSchema(core.JSONString),
+		"component":     core.TypeSchema(core.JSONString),
+		"what":          core.TypeSchema(core.JSONString),
+		"why":           core.TypeSchema(core.JSONString),
+		"tradeoffs":     core.TypeSchema(core.JSONString),
+		"confidence":    core.ConfidenceSchema,
+		"evidence":      core.ArraySchema(core.EvidenceSchema),
+		"debt_reason":   core.TypeSchema(core.JSONString),
+		"refactor_hint": core.TypeSchema(core.JSONString),
+	}, "title", "what")),
+	"patterns": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"name":          core.TypeSchema(core.JSONString),
+		"context":       core.TypeSchema(core.JSONString),
+		"solution":      core.TypeSchema(core.JSONString),
+		"consequences":  core.TypeSchema(core.JSONString),
+		"confidence":    core.ConfidenceSchema,
+		"evidence":      core.ArraySchema(core.EvidenceSchema),
+		"debt_reason":   core.TypeSchema(core.JSONString),
+		"refactor_hint": core.TypeSchema(core.JSONString),
+	}, "name")),
+})
+
 func (a *ReactAgent) parseFindings(response string) ([]core.Finding, error) {
-	parsed, err := core.ParseJSONResponse[reactParseResult](response)
+	parsed, err := core.ParseValidatedJSONResponse[reactParseResult](response, reactResponseSchema)
 	if err != nil {
 		return nil, err
 	}
@@ -174,7 +200,7 @@ func (a *ReactAgent) parseFindings(response string) ([]core.Finding, error) {
 		))
 	}
 
-	return findings, nil
+	return core.ApplyEvidenceMode(findings, core.ActiveEvidenceMode()), nil
 }
 
 func init() {
@@ -46,6 +46,11 @@ func (a *CodeAgent) Close() error {
 // Run executes the agent using chunked processing with deduplication.
 // For large codebases, splits files into chunks, analyzes each, then merges results.
 func (a *CodeAgent) Run(ctx context.Context, input core.Input) (core.Output, error) {
+	basePath := input.BasePath
+	if basePath == "" {
+		basePath = a.basePath
+	}
+
 	// Initialize chain (lazy)
 	if a.chain == nil {
 		chatModel, err := a.CreateCloseableChatModel(ctx)
@@ -57,7 +62,8 @@ func (a *CodeAgent) Run(ctx context.Context, input core.Input) (core.Output, err
 			ctx,
 			a.Name(),
 			chatModel.BaseChatModel,
-			config.PromptTemplateCodeAgent,
+			a.PromptTemplate(basePath, config.PromptTemplateCodeAgent),
+			a.TimeoutOption(),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -65,11 +71,6 @@ func (a *CodeAgent) Run(ctx context.Context, input core.Input) (core.Output, err
 		a.chain = chain
 	}
 
-	basePath := input.BasePath
-	if basePath == "" {
-		basePath = a.basePath
-	}
-
 	isIncremental := input.Mode == core.ModeWatch && len(input.ChangedFiles) > 0
 
 	// Format existing knowledge context (used by all analysis paths)
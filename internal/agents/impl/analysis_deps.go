@@ -46,19 +46,27 @@ func (a *DepsAgent) Close() error {
 
 // Run executes the agent using Eino DeterministicChain.
 func (a *DepsAgent) Run(ctx context.Context, input core.Input) (core.Output, error) {
-	// Quick pre-check: skip ReAct entirely if no dependency files exist.
-	// This avoids wasting 20 tool calls exploring an empty project.
-	if !hasAnyDependencyFile(input.BasePath) {
-		return core.Output{AgentName: a.Name(), Error: fmt.Errorf("no dependency files found")}, nil
-	}
+	// Watch mode: re-analyze only the changed manifests; the full analysis
+	// below would re-derive findings for manifests that did not change
+	var manifests []string
+	if input.Mode == core.ModeWatch && len(input.ChangedFiles) > 0 {
+		manifests = filterDependencyFiles(input.ChangedFiles)
+		if len(manifests) == 0 {
+			return core.Output{AgentName: a.Name()}, nil
+		}
+	} else {
+		// Quick pre-check: skip ReAct entirely if no dependency files exist.
+		// This avoids wasting 20 tool calls exploring an empty project.
+		if !hasAnyDependencyFile(input.BasePath) {
+			return core.Output{AgentName: a.Name(), Error: fmt.Errorf("no dependency files found")}, nil
+		}
 
-	// ReAct mode: attempt tool-calling exploration for richer findings.
-	// Tried BEFORE chain init to avoid wasting an LLM connection if ReAct succeeds.
-	{
+		// ReAct mode: attempt tool-calling exploration for richer findings.
+		// Tried BEFORE chain init to avoid wasting an LLM connection if ReAct succeeds.
 		userMsg := fmt.Sprintf("Analyze the dependencies for project %q. Start by listing the root directory to find dependency manifests (package.json, go.mod, Cargo.toml, etc.).", input.ProjectName)
 		raw, duration, err := runReactMode(ctx, a.LLMConfig(), input.BasePath, config.SystemPromptDepsReactAgent, userMsg, 20)
 		if err == nil && raw != "" {
-			parsed, parseErr := core.ParseJSONResponse[depsTechDecisionsResponse](raw)
+			parsed, parseErr := core.ParseValidatedJSONResponse[depsTechDecisionsResponse](raw, depsResponseSchema)
 			if parseErr == nil {
 				findings := a.parseFindings(parsed)
 				if len(findings) >= reactMinFindingsDeps {
@@ -86,7 +94,9 @@ func (a *DepsAgent) Run(ctx context.Context, input core.Input) (core.Output, err
 			ctx,
 			a.Name(),
 			chatModel.BaseChatModel,
-			config.PromptTemplateDepsAgent,
+			a.PromptTemplate(input.BasePath, config.PromptTemplateDepsAgent),
+			a.TimeoutOption(),
+			core.WithResponseSchema(depsResponseSchema),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -98,7 +108,13 @@ func (a *DepsAgent) Run(ctx context.Context, input core.Input) (core.Output, err
 	limit := llm.GetMaxInputTokens(a.LLMConfig().Model)
 	budget := tools.NewSafeContextBudget(int(float64(limit) * 0.7))
 
-	depsInfo, filesRead := gatherDepsWithTracking(input.BasePath, budget)
+	var depsInfo string
+	var filesRead []core.FileRead
+	if len(manifests) > 0 {
+		depsInfo, filesRead = gatherDepsFiles(input.BasePath, manifests, budget)
+	} else {
+		depsInfo, filesRead = gatherDepsWithTracking(input.BasePath, budget)
+	}
 	if depsInfo == "" {
 		return core.Output{AgentName: a.Name(), Error: fmt.Errorf("no dependency files found")}, nil
 	}
@@ -153,7 +169,8 @@ func (a *DepsAgent) PrepareForBatch(ctx context.Context, input core.Input) ([]co
 		}
 		a.modelCloser = chatModel
 		chain, err := core.NewDeterministicChain[depsTechDecisionsResponse](
-			ctx, a.Name(), chatModel.BaseChatModel, config.PromptTemplateDepsAgent,
+			ctx, a.Name(), chatModel.BaseChatModel, a.PromptTemplate(input.BasePath, config.PromptTemplateDepsAgent), a.TimeoutOption(),
+			core.WithResponseSchema(depsResponseSchema),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("create chain: %w", err)
@@ -180,7 +197,7 @@ func (a *DepsAgent) PrepareForBatch(ctx context.Context, input core.Input) ([]co
 
 // ParseBatchResult parses raw LLM response text into findings.
 func (a *DepsAgent) ParseBatchResult(raw string) (core.Output, error) {
-	parsed, err := core.ParseJSONResponse[depsTechDecisionsResponse](raw)
+	parsed, err := core.ParseValidatedJSONResponse[depsTechDecisionsResponse](raw, depsResponseSchema)
 	if err != nil {
 		return core.Output{AgentName: a.Name()}, fmt.Errorf("parse batch result: %w", err)
 	}
@@ -199,6 +216,19 @@ type depsTechDecisionsResponse struct {
 	} `json:"tech_decisions"`
 }
 
+// depsResponseSchema is the shape depsTechDecisionsResponse expects: each
+// decision needs the title and description its finding is built from.
+var depsResponseSchema = core.ObjectSchema(map[string]*core.ResponseSchema{
+	"tech_decisions": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"title":      core.TypeSchema(core.JSONString),
+		"category":   core.TypeSchema(core.JSONString),
+		"what":       core.TypeSchema(core.JSONString),
+		"why":        core.TypeSchema(core.JSONString),
+		"confidence": core.ConfidenceSchema,
+		"evidence":   core.ArraySchema(core.EvidenceSchema),
+	}, "title", "what")),
+})
+
 func (a *DepsAgent) parseFindings(parsed depsTechDecisionsResponse) []core.Finding {
 	var findings []core.Finding
 	for _, d := range parsed.TechDecisions {
@@ -218,7 +248,7 @@ func (a *DepsAgent) parseFindings(parsed depsTechDecisionsResponse) []core.Findi
 			map[string]any{"component": component},
 		))
 	}
-	return findings
+	return core.ApplyEvidenceMode(findings, core.ActiveEvidenceMode())
 }
 
 // gatherDepsWithTracking collects dependency file contents and tracks which files were read.
@@ -309,6 +339,49 @@ func gatherDepsWithTracking(basePath string, budget *tools.ContextBudget) (strin
 	return sb.String(), filesRead
 }
 
+// gatherDepsFiles collects the contents of the given project-relative
+// dependency manifests, e.g. the ones changed since the last bootstrap.
+func gatherDepsFiles(basePath string, files []string, budget *tools.ContextBudget) (string, []core.FileRead) {
+	var sb strings.Builder
+	var filesRead []core.FileRead
+	for _, file := range files {
+		if budget.IsExhausted() {
+			break
+		}
+		fullPath, err := utils.SafeJoin(basePath, file)
+		if err != nil {
+			continue
+		}
+		content, err := readFileWithLimit(fullPath, 3000)
+		if err != nil {
+			continue // Deleted manifests have nothing left to analyze
+		}
+		formatted := fmt.Sprintf("## %s\n```\n%s\n```\n\n", file, string(content))
+		if !budget.TryReserve(llm.EstimateTokens(formatted)) {
+			break
+		}
+		sb.WriteString(formatted)
+		filesRead = append(filesRead, core.FileRead{
+			Path:       file,
+			Characters: len(content),
+			Lines:      strings.Count(string(content), "\n") + 1,
+			Truncated:  len(content) == 3000,
+		})
+	}
+	return sb.String(), filesRead
+}
+
+// filterDependencyFiles keeps the changed files that are dependency manifests.
+func filterDependencyFiles(files []string) []string {
+	var filtered []string
+	for _, f := range files {
+		if utils.IsDependencyFile(filepath.Base(f)) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
 // readFileWithLimit reads a file up to maxBytes, returning the content read.
 func readFileWithLimit(path string, maxBytes int) ([]byte, error) {
 	f, err := os.Open(path)
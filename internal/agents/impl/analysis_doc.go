@@ -10,26 +10,75 @@ import (
 	"io"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/josephgoksu/TaskWing/internal/agents/core"
 	"github.com/josephgoksu/TaskWing/internal/agents/tools"
 	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/utils"
+	"github.com/spf13/viper"
 )
 
+// DocBatchConfig controls how large documentation trees are split across chain calls.
+// Batching is off by default: docs are read once under the run's shared
+// context budget. Batched docs bypass that budget, so projects opt in via
+// .taskwing.yaml (200000 bytes is about 50k tokens per batch):
+//
+//	bootstrap:
+//	  docs:
+//	    batch_bytes: 200000
+//	    concurrency: 3
+type DocBatchConfig struct {
+	MaxBatchBytes  int // Byte budget per chain invocation (<= 0 disables batching)
+	MaxConcurrency int // Max chain invocations in flight at once
+}
+
+// DefaultDocBatchConfig returns batching defaults: batching disabled, and
+// three batches in flight once it is enabled.
+func DefaultDocBatchConfig() DocBatchConfig {
+	return DocBatchConfig{
+		MaxBatchBytes:  0,
+		MaxConcurrency: 3,
+	}
+}
+
+// docChain is the subset of DeterministicChain used by DocAgent.
+type docChain interface {
+	Invoke(ctx context.Context, input map[string]any) (docAnalysisResponse, string, time.Duration, error)
+}
+
 // DocAgent analyzes documentation files to extract product features.
 // Call Close() when done to release resources.
 type DocAgent struct {
 	core.BaseAgent
-	chain       *core.DeterministicChain[docAnalysisResponse]
+	chain       docChain
 	modelCloser io.Closer
+	batchConfig DocBatchConfig
 }
 
 // NewDocAgent creates a new documentation analysis agent.
 func NewDocAgent(cfg llm.Config) *DocAgent {
+	batchConfig := DefaultDocBatchConfig()
+	if v := viper.GetInt("bootstrap.docs.batch_bytes"); v != 0 {
+		batchConfig.MaxBatchBytes = v
+	}
+	if v := viper.GetInt("bootstrap.docs.concurrency"); v > 0 {
+		batchConfig.MaxConcurrency = v
+	}
 	return &DocAgent{
-		BaseAgent: core.NewBaseAgent("doc", "Analyzes documentation to extract product features", cfg),
+		BaseAgent:   core.NewBaseAgent("doc", "Analyzes documentation to extract product features", cfg),
+		batchConfig: batchConfig,
+	}
+}
+
+// SetBatchConfig overrides documentation batching. A non-positive MaxBatchBytes
+// disables batching; a non-positive MaxConcurrency keeps the current limit.
+func (a *DocAgent) SetBatchConfig(cfg DocBatchConfig) {
+	a.batchConfig.MaxBatchBytes = cfg.MaxBatchBytes
+	if cfg.MaxConcurrency > 0 {
+		a.batchConfig.MaxConcurrency = cfg.MaxConcurrency
 	}
 }
 
@@ -49,7 +98,7 @@ func (a *DocAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 		userMsg := fmt.Sprintf("Analyze the documentation for project %q. Start by listing the root directory to find documentation files.", input.ProjectName)
 		raw, duration, err := runReactMode(ctx, a.LLMConfig(), input.BasePath, config.SystemPromptDocReactAgent, userMsg, 15)
 		if err == nil && raw != "" {
-			parsed, parseErr := core.ParseJSONResponse[docAnalysisResponse](raw)
+			parsed, parseErr := core.ParseValidatedJSONResponse[docAnalysisResponse](raw, docResponseSchema)
 			if parseErr == nil {
 				findings, relationships := a.parseFindings(parsed)
 				if len(findings) >= reactMinFindingsDoc {
@@ -77,7 +126,9 @@ func (a *DocAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 			ctx,
 			a.Name(),
 			chatModel.BaseChatModel,
-			config.PromptTemplateDocAgent,
+			a.PromptTemplate(input.BasePath, config.PromptTemplateDocAgent),
+			a.TimeoutOption(),
+			core.WithResponseSchema(docResponseSchema),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -91,10 +142,11 @@ func (a *DocAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 
 	gatherer := tools.NewContextGatherer(input.BasePath)
 	gatherer.SetBudget(budget)
+	gatherer.SetDirScope(input.IncludeDirs, input.ExcludeDirs)
 
-	// Watch mode: simple single-pass for changed markdown files
+	// Watch mode: simple single-pass for changed doc files
 	if input.Mode == core.ModeWatch && len(input.ChangedFiles) > 0 {
-		docContent := gatherer.GatherSpecificFiles(filterMarkdown(input.ChangedFiles))
+		docContent := gatherer.GatherSpecificFiles(filterDocFiles(input.ChangedFiles))
 		if docContent == "" {
 			return core.Output{AgentName: a.Name()}, nil
 		}
@@ -124,9 +176,27 @@ func (a *DocAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 
 	results := make(chan result, 2)
 
+	// Batched mode reads docs without the shared budget and splits them per batch instead.
+	batching := a.batchConfig.MaxBatchBytes > 0
+	docsGatherer := gatherer
+	if batching {
+		docsGatherer = tools.NewContextGatherer(input.BasePath)
+		docsGatherer.SetDirScope(input.IncludeDirs, input.ExcludeDirs)
+	}
+
 	// 1. General Docs
 	go func() {
-		content := gatherer.GatherMarkdownDocs()
+		if batching {
+			sections := docsGatherer.GatherMarkdownDocSections()
+			if len(sections) == 0 {
+				results <- result{}
+				return
+			}
+			p, d, err := a.analyzeDocBatches(ctx, input.ProjectName, batchDocSections(sections, a.batchConfig.MaxBatchBytes))
+			results <- result{parsed: p, duration: d, err: err}
+			return
+		}
+		content := docsGatherer.GatherMarkdownDocs()
 		if content == "" {
 			results <- result{}
 			return
@@ -188,6 +258,11 @@ func (a *DocAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 	}
 
 	findings, relationships := a.parseFindings(combinedParsed)
+	if batching {
+		deduplicator := tools.NewFindingDeduplicator()
+		findings = deduplicator.DeduplicateFindings(findings)
+		relationships = deduplicator.DeduplicateRelationships(relationships)
+	}
 
 	// Warn if no findings were produced - helps diagnose silent failures
 	if len(findings) == 0 {
@@ -202,11 +277,92 @@ func (a *DocAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 
 	// Add coverage stats from context gathering
 	toolsCoverage := gatherer.GetCoverage()
+	if docsGatherer != gatherer {
+		docsCoverage := docsGatherer.GetCoverage()
+		toolsCoverage.FilesRead = append(toolsCoverage.FilesRead, docsCoverage.FilesRead...)
+		toolsCoverage.FilesSkipped = append(toolsCoverage.FilesSkipped, docsCoverage.FilesSkipped...)
+	}
 	output.Coverage = convertToolsCoverage(toolsCoverage)
 
 	return output, nil
 }
 
+// analyzeDocBatches runs the chain once per batch with bounded concurrency and
+// merges the parsed responses. Fails only if every batch fails.
+func (a *DocAgent) analyzeDocBatches(ctx context.Context, projectName string, batches []string) (docAnalysisResponse, time.Duration, error) {
+	type batchResult struct {
+		parsed   docAnalysisResponse
+		duration time.Duration
+		err      error
+	}
+
+	concurrency := max(1, a.batchConfig.MaxConcurrency)
+	sem := make(chan struct{}, concurrency)
+	results := make([]batchResult, len(batches))
+
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = batchResult{err: ctx.Err()}
+				return
+			}
+			defer func() { <-sem }()
+
+			content := fmt.Sprintf("FOCUS: PRODUCT FEATURES & ARCHITECTURE (Batch %d/%d)\n\n%s", i+1, len(batches), batch)
+			p, _, d, err := a.chain.Invoke(ctx, map[string]any{"ProjectName": projectName, "DocContent": content})
+			results[i] = batchResult{parsed: p, duration: d, err: err}
+		}(i, batch)
+	}
+	wg.Wait()
+
+	var merged docAnalysisResponse
+	var maxDuration time.Duration
+	var errs []string
+	for i, res := range results {
+		if res.err != nil {
+			errs = append(errs, fmt.Sprintf("batch %d: %v", i+1, res.err))
+			continue
+		}
+		maxDuration = max(maxDuration, res.duration)
+		merged.Features = append(merged.Features, res.parsed.Features...)
+		merged.Decisions = append(merged.Decisions, res.parsed.Decisions...)
+		merged.Constraints = append(merged.Constraints, res.parsed.Constraints...)
+		merged.Workflows = append(merged.Workflows, res.parsed.Workflows...)
+		merged.Relationships = append(merged.Relationships, res.parsed.Relationships...)
+	}
+
+	if len(errs) == len(batches) {
+		return merged, maxDuration, fmt.Errorf("all %d doc batches failed: %s", len(batches), strings.Join(errs, "; "))
+	}
+	if len(errs) > 0 {
+		slog.Debug("[doc] some doc batches failed", "failed", len(errs), "total", len(batches), "errors", strings.Join(errs, "; "))
+	}
+	return merged, maxDuration, nil
+}
+
+// batchDocSections groups formatted doc sections into batches under maxBytes.
+// A single section larger than maxBytes gets a batch of its own.
+func batchDocSections(sections []string, maxBytes int) []string {
+	var batches []string
+	var current strings.Builder
+	for _, section := range sections {
+		if current.Len() > 0 && current.Len()+len(section) > maxBytes {
+			batches = append(batches, current.String())
+			current.Reset()
+		}
+		current.WriteString(section)
+	}
+	if current.Len() > 0 {
+		batches = append(batches, current.String())
+	}
+	return batches
+}
+
 type docAnalysisResponse struct {
 	Features []struct {
 		Name        string              `json:"name"`
@@ -247,6 +403,42 @@ type docAnalysisResponse struct {
 	} `json:"relationships"`
 }
 
+// docResponseSchema is the shape docAnalysisResponse expects. Every category
+// is optional, but each entry must carry the fields its finding is built from.
+var docResponseSchema = core.ObjectSchema(map[string]*core.ResponseSchema{
+	"features": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"name":        core.TypeSchema(core.JSONString),
+		"description": core.TypeSchema(core.JSONString),
+		"confidence":  core.ConfidenceSchema,
+		"evidence":    core.ArraySchema(core.EvidenceSchema),
+	}, "name", "description")),
+	"decisions": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"title":      core.TypeSchema(core.JSONString),
+		"summary":    core.TypeSchema(core.JSONString),
+		"confidence": core.ConfidenceSchema,
+		"evidence":   core.ArraySchema(core.EvidenceSchema),
+	}, "title", "summary")),
+	"constraints": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"rule":       core.TypeSchema(core.JSONString),
+		"reason":     core.TypeSchema(core.JSONString),
+		"severity":   core.TypeSchema(core.JSONString),
+		"confidence": core.ConfidenceSchema,
+		"evidence":   core.ArraySchema(core.EvidenceSchema),
+	}, "rule")),
+	"workflows": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"name":       core.TypeSchema(core.JSONString),
+		"steps":      core.TypeSchema(core.JSONString),
+		"trigger":    core.TypeSchema(core.JSONString),
+		"confidence": core.ConfidenceSchema,
+		"evidence":   core.ArraySchema(core.EvidenceSchema),
+	}, "name")),
+	"relationships": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"from":     core.TypeSchema(core.JSONString),
+		"to":       core.TypeSchema(core.JSONString),
+		"relation": core.TypeSchema(core.JSONString),
+	}, "from", "to", "relation")),
+})
+
 func (a *DocAgent) parseFindings(parsed docAnalysisResponse) ([]core.Finding, []core.Relationship) {
 	var findings []core.Finding
 
@@ -316,15 +508,9 @@ func (a *DocAgent) parseFindings(parsed docAnalysisResponse) ([]core.Finding, []
 			evidence = []core.Evidence{{FilePath: c.SourceFile}}
 		}
 		confidenceScore, _ := core.ParseConfidence(c.Confidence)
-		if confidenceScore == 0.5 && c.Severity != "" {
-			switch c.Severity {
-			case "critical":
-				confidenceScore = 0.95
-			case "high":
-				confidenceScore = 0.85
-			case "medium":
-				confidenceScore = 0.7
-			}
+		// No explicit confidence: derive it from the constraint's severity
+		if confidenceScore == core.ActiveConfidenceCalibration().DefaultScore && c.Severity != "" {
+			confidenceScore = core.ConfidenceScoreFromSeverity(c.Severity, confidenceScore)
 		}
 		findings = append(findings, core.Finding{
 			Type:               core.FindingTypeConstraint,
@@ -352,13 +538,14 @@ func (a *DocAgent) parseFindings(parsed docAnalysisResponse) ([]core.Finding, []
 		}
 	}
 
-	return findings, relationships
+	return core.ApplyEvidenceMode(findings, core.ActiveEvidenceMode()), relationships
 }
 
-func filterMarkdown(files []string) []string {
+// filterDocFiles keeps the changed files the doc agent covers.
+func filterDocFiles(files []string) []string {
 	var filtered []string
 	for _, f := range files {
-		if strings.HasSuffix(strings.ToLower(f), ".md") {
+		if utils.IsDocFile(f) {
 			filtered = append(filtered, f)
 		}
 	}
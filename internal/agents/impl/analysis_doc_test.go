@@ -0,0 +1,271 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/spf13/viper"
+)
+
+// fakeDocChain returns the same feature and relationship for every batch.
+type fakeDocChain struct {
+	calls atomic.Int32
+}
+
+func (f *fakeDocChain) Invoke(_ context.Context, input map[string]any) (docAnalysisResponse, string, time.Duration, error) {
+	f.calls.Add(1)
+	var resp docAnalysisResponse
+	if !strings.Contains(input["DocContent"].(string), "PRODUCT FEATURES") {
+		return resp, "", 0, nil
+	}
+	resp.Features = append(resp.Features, struct {
+		Name        string              `json:"name"`
+		Description string              `json:"description"`
+		Confidence  any                 `json:"confidence"`
+		Evidence    []core.EvidenceJSON `json:"evidence"`
+		SourceFile  string              `json:"source_file"`
+	}{Name: "Knowledge Graph Storage", Description: "Stores project knowledge in SQLite", Confidence: 0.9})
+	resp.Relationships = append(resp.Relationships, struct {
+		From     string `json:"from"`
+		To       string `json:"to"`
+		Relation string `json:"relation"`
+		Reason   string `json:"reason"`
+	}{From: "Knowledge Graph Storage", To: "SQLite", Relation: "depends_on"})
+	return resp, "", time.Millisecond, nil
+}
+
+func TestDocAgent_BatchesLargeDocTrees(t *testing.T) {
+	dir := t.TempDir()
+	docsDir := filepath.Join(dir, "docs")
+	if err := os.MkdirAll(docsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 12; i++ {
+		content := fmt.Sprintf("# Doc %d\n\n%s\n", i, strings.Repeat("knowledge graph storage details ", 20))
+		if err := os.WriteFile(filepath.Join(docsDir, fmt.Sprintf("doc%02d.md", i)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain := &fakeDocChain{}
+	agent := NewDocAgent(llm.Config{})
+	agent.chain = chain
+	agent.SetBatchConfig(DocBatchConfig{MaxBatchBytes: 1500, MaxConcurrency: 2})
+
+	output, err := agent.Run(context.Background(), core.Input{BasePath: dir, ProjectName: "test"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if output.Error != nil {
+		t.Fatalf("output error: %v", output.Error)
+	}
+
+	if calls := chain.calls.Load(); calls < 3 {
+		t.Errorf("expected multiple chain invocations for batched docs, got %d", calls)
+	}
+	if len(output.Findings) != 1 {
+		t.Errorf("expected duplicate findings to merge into 1, got %d", len(output.Findings))
+	}
+	if len(output.Relationships) != 1 {
+		t.Errorf("expected duplicate relationships to merge into 1, got %d", len(output.Relationships))
+	}
+	if len(output.Coverage.FilesRead) != 12 {
+		t.Errorf("expected coverage for all 12 docs, got %d", len(output.Coverage.FilesRead))
+	}
+
+	// Batching is opt-in: by default the docs are analyzed in one call
+	unbatchedChain := &fakeDocChain{}
+	unbatched := NewDocAgent(llm.Config{})
+	unbatched.chain = unbatchedChain
+	if _, err := unbatched.Run(context.Background(), core.Input{BasePath: dir, ProjectName: "test"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if calls := unbatchedChain.calls.Load(); calls != 1 {
+		t.Errorf("default agent made %d chain invocations, want 1", calls)
+	}
+}
+
+func TestBatchDocSections(t *testing.T) {
+	sections := []string{strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 200)}
+	batches := batchDocSections(sections, 100)
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(batches))
+	}
+	if batches[1] != sections[2] {
+		t.Error("oversized section should get its own batch")
+	}
+}
+
+func TestDocAgent_PromptOverride(t *testing.T) {
+	const marker = "CUSTOM-DOC-PROMPT-7f3a"
+
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompts = append(prompts, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"1","object":"chat.completion","model":"test","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"{\"features\":[]}"}}]}`)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Project\n\nDocs.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	promptsDir := filepath.Join(dir, config.PromptOverridesDir)
+	if err := os.MkdirAll(promptsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	override := marker + " Analyze {{.ProjectName}}:\n{{.DocContent}}\nRespond with JSON."
+	if err := os.WriteFile(filepath.Join(promptsDir, "doc.tmpl"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	agent := NewDocAgent(llm.Config{Provider: llm.ProviderOpenAI, Model: "test", APIKey: "test", BaseURL: server.URL})
+	defer func() { _ = agent.Close() }()
+
+	output, err := agent.Run(context.Background(), core.Input{
+		BasePath:     dir,
+		ProjectName:  "test",
+		Mode:         core.ModeWatch,
+		ChangedFiles: []string{"README.md"},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if output.Error != nil {
+		t.Fatalf("output error: %v", output.Error)
+	}
+	if len(prompts) != 1 {
+		t.Fatalf("expected 1 LLM request, got %d", len(prompts))
+	}
+	if !strings.Contains(prompts[0], marker) {
+		t.Errorf("rendered prompt does not use the override:\n%s", prompts[0])
+	}
+}
+
+func TestDocResponseSchema_RejectsMissingFields(t *testing.T) {
+	valid := `{"features": [{"name": "Search", "description": "Finds things", "confidence": "high",
+		"evidence": [{"file_path": "README.md", "start_line": 1, "end_line": 2, "snippet": "x"}]}]}`
+	if _, err := core.ParseValidatedJSONResponse[docAnalysisResponse](valid, docResponseSchema); err != nil {
+		t.Fatalf("valid response rejected: %v", err)
+	}
+
+	// Parses into docAnalysisResponse, but the feature has no name and the
+	// confidence is an object ParseConfidence would silently default
+	invalid := "```json\n" + `{"features": [{"description": "Finds things", "confidence": {"value": 0.9}}],
+		"relationships": [{"from": "Search"}]}` + "\n```"
+	if _, err := core.ParseJSONResponse[docAnalysisResponse](invalid); err != nil {
+		t.Fatalf("response should unmarshal without a schema: %v", err)
+	}
+	_, err := core.ParseValidatedJSONResponse[docAnalysisResponse](invalid, docResponseSchema)
+	if !errors.Is(err, core.ErrSchemaValidation) {
+		t.Fatalf("expected ErrSchemaValidation, got %v", err)
+	}
+	for _, want := range []string{"$.features[0].name", "$.features[0].confidence", "$.relationships[0].to"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error should name %s, got %v", want, err)
+		}
+	}
+}
+
+func TestAgentResponseSchemas_RejectMissingFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func(string) error
+		valid   string
+		invalid string
+		field   string
+	}{
+		{
+			name: "deps",
+			parse: func(raw string) error {
+				_, err := core.ParseValidatedJSONResponse[depsTechDecisionsResponse](raw, depsResponseSchema)
+				return err
+			},
+			valid:   `{"tech_decisions": [{"title": "Cobra CLI", "what": "Commands use cobra", "confidence": 0.8}]}`,
+			invalid: `{"tech_decisions": [{"title": "Cobra CLI", "confidence": 0.8}]}`,
+			field:   "$.tech_decisions[0].what",
+		},
+		{
+			name: "git",
+			parse: func(raw string) error {
+				_, err := core.ParseValidatedJSONResponse[gitMilestonesResponse](raw, gitResponseSchema)
+				return err
+			},
+			valid:   `{"milestones": [{"title": "MCP server", "description": "Added the MCP server"}]}`,
+			invalid: `{"milestones": [{"description": "Added the MCP server"}]}`,
+			field:   "$.milestones[0].title",
+		},
+		{
+			name: "react",
+			parse: func(raw string) error {
+				_, err := core.ParseValidatedJSONResponse[reactParseResult](raw, reactResponseSchema)
+				return err
+			},
+			valid:   `{"decisions": [{"title": "SQLite store", "what": "Uses SQLite", "debt_score": 0.2}], "patterns": [{"name": "Repository"}]}`,
+			invalid: `{"decisions": [{"title": "SQLite store", "what": "Uses SQLite"}], "patterns": [{"solution": "Wrap queries"}]}`,
+			field:   "$.patterns[0].name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.parse(tt.valid); err != nil {
+				t.Fatalf("valid response rejected: %v", err)
+			}
+			err := tt.parse(tt.invalid)
+			if !errors.Is(err, core.ErrSchemaValidation) {
+				t.Fatalf("expected ErrSchemaValidation, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("error should name %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestDocAgent_ParseFindingsRequiresEvidence(t *testing.T) {
+	type feature = struct {
+		Name        string              `json:"name"`
+		Description string              `json:"description"`
+		Confidence  any                 `json:"confidence"`
+		Evidence    []core.EvidenceJSON `json:"evidence"`
+		SourceFile  string              `json:"source_file"`
+	}
+	var parsed docAnalysisResponse
+	parsed.Features = []feature{
+		{Name: "Offline Mode", Description: "Works without network", Confidence: 0.9},
+		{Name: "Plan Export", Description: "Exports plans to Markdown", Confidence: 0.9, SourceFile: "docs/export.md"},
+	}
+	agent := NewDocAgent(llm.Config{})
+
+	if findings, _ := agent.parseFindings(parsed); len(findings) != 2 {
+		t.Fatalf("default mode kept %d findings, want 2", len(findings))
+	}
+
+	viper.Set("agents.evidence.mode", "drop")
+	t.Cleanup(func() { viper.Set("agents.evidence.mode", nil) })
+	findings, _ := agent.parseFindings(parsed)
+	if len(findings) != 1 || findings[0].Title != "Plan Export" {
+		t.Fatalf("drop mode findings = %+v, want only the file-backed Plan Export", findings)
+	}
+
+	viper.Set("agents.evidence.mode", "downrank")
+	findings, _ = agent.parseFindings(parsed)
+	if len(findings) != 2 || findings[0].ConfidenceScore >= findings[1].ConfidenceScore {
+		t.Errorf("downrank mode should keep both and lower the unsupported finding's confidence, got %+v", findings)
+	}
+}
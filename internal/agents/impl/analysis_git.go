@@ -59,7 +59,7 @@ func (a *GitAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 	start := time.Now()
 
 	// Gather commits early to check if git history exists (needed for both paths)
-	chunks, projectMeta := gatherGitChunks(input.BasePath, input.Verbose)
+	chunks, projectMeta := gatherGitChunks(input.BasePath, input.GitHistory, input.Verbose)
 	if len(chunks) == 0 {
 		return core.Output{AgentName: a.Name(), Error: fmt.Errorf("no git history available")}, nil
 	}
@@ -68,9 +68,12 @@ func (a *GitAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 	// Tried BEFORE chain init to avoid wasting an LLM connection if ReAct succeeds.
 	{
 		userMsg := fmt.Sprintf("Analyze the git history for project %q. Start by running git log --oneline -100 to get an overview of recent commits.", input.ProjectName)
+		if window := gitWindowArgs(input.GitHistory, time.Now()); len(window) > 0 {
+			userMsg += fmt.Sprintf(" Only consider commits selected by git log %s.", strings.Join(window, " "))
+		}
 		raw, reactDuration, err := runReactMode(ctx, a.LLMConfig(), input.BasePath, config.SystemPromptGitReactAgent, userMsg, 15)
 		if err == nil && raw != "" {
-			parsed, parseErr := core.ParseJSONResponse[gitMilestonesResponse](raw)
+			parsed, parseErr := core.ParseValidatedJSONResponse[gitMilestonesResponse](raw, gitResponseSchema)
 			if parseErr == nil {
 				findings := a.parseFindings(parsed)
 				if len(findings) >= reactMinFindingsGit {
@@ -106,7 +109,9 @@ func (a *GitAgent) Run(ctx context.Context, input core.Input) (core.Output, erro
 			ctx,
 			a.Name(),
 			chatModel.BaseChatModel,
-			config.PromptTemplateGitAgentChunked,
+			a.PromptTemplate(input.BasePath, config.PromptTemplateGitAgentChunked),
+			a.TimeoutOption(),
+			core.WithResponseSchema(gitResponseSchema),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -235,6 +240,19 @@ type gitMilestonesResponse struct {
 	} `json:"milestones"`
 }
 
+// gitResponseSchema is the shape gitMilestonesResponse expects: each
+// milestone needs the title and description its finding is built from.
+var gitResponseSchema = core.ObjectSchema(map[string]*core.ResponseSchema{
+	"milestones": core.ArraySchema(core.ObjectSchema(map[string]*core.ResponseSchema{
+		"title":        core.TypeSchema(core.JSONString),
+		"scope":        core.TypeSchema(core.JSONString),
+		"description":  core.TypeSchema(core.JSONString),
+		"confidence":   core.ConfidenceSchema,
+		"evidence":     core.ArraySchema(core.EvidenceSchema),
+		"evidence_old": core.TypeSchema(core.JSONString),
+	}, "title", "description")),
+})
+
 func (a *GitAgent) parseFindings(parsed gitMilestonesResponse) []core.Finding {
 	var findings []core.Finding
 	for _, m := range parsed.Milestones {
@@ -258,14 +276,15 @@ func (a *GitAgent) parseFindings(parsed gitMilestonesResponse) []core.Finding {
 			map[string]any{"component": component},
 		))
 	}
-	return findings
+	return core.ApplyEvidenceMode(findings, core.ActiveEvidenceMode())
 }
 
 // gatherGitChunks returns commit chunks (newest first) and project metadata.
 // When running in a monorepo (ProjectRoot != GitRoot), it scopes git analysis
-// to only include commits affecting the project subdirectory.
+// to only include commits affecting the project subdirectory. window narrows
+// the commits read; the zero window reads the latest gitMaxCommits.
 // Requires project context to be set via config.SetProjectContext() - no fallbacks.
-func gatherGitChunks(basePath string, verbose bool) ([]string, string) {
+func gatherGitChunks(basePath string, window core.GitHistoryWindow, verbose bool) ([]string, string) {
 	// DETERMINISTIC: Use project context from CLI init - no fallback detection
 	projectCtx := config.GetProjectContext()
 	// Note: projectCtx may be nil if running outside CLI context (e.g., tests)
@@ -291,7 +310,11 @@ func gatherGitChunks(basePath string, verbose bool) ([]string, string) {
 	}
 
 	// Build git log command with optional path scoping
-	args := []string{"log", "--format=%h %ad %s", "--date=short", fmt.Sprintf("-%d", gitMaxCommits)}
+	args := []string{"log", "--format=%h %ad %s", "--date=short"}
+	if window.MaxCommits <= 0 {
+		args = append(args, fmt.Sprintf("-%d", gitMaxCommits))
+	}
+	args = append(args, gitWindowArgs(window, time.Now())...)
 	if scopePath != "" {
 		// Scope to project subdirectory: git log ... -- <path>
 		args = append(args, "--", scopePath)
@@ -338,6 +361,19 @@ func gatherGitChunks(basePath string, verbose bool) ([]string, string) {
 	return chunks, meta
 }
 
+// gitWindowArgs returns the git log arguments that limit history to window,
+// measuring durations back from now.
+func gitWindowArgs(window core.GitHistoryWindow, now time.Time) []string {
+	var args []string
+	if window.MaxCommits > 0 {
+		args = append(args, fmt.Sprintf("-%d", window.MaxCommits))
+	}
+	if window.Since > 0 {
+		args = append(args, "--since="+now.Add(-window.Since).Format(time.RFC3339))
+	}
+	return args
+}
+
 // getGitScopePath returns the relative path to scope git operations to,
 // or empty string if no scoping is needed.
 func getGitScopePath(ctx *project.Context) string {
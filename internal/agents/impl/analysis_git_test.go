@@ -0,0 +1,77 @@
+package impl
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+)
+
+func TestGatherGitChunks_HistoryWindow(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+
+	runGit := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	now := time.Now()
+	runGit("", "init", "-q")
+	for _, c := range []struct {
+		age time.Duration
+		msg string
+	}{
+		{400 * 24 * time.Hour, "feat: initial import"},
+		{120 * 24 * time.Hour, "feat: add billing"},
+		{20 * 24 * time.Hour, "fix: billing rounding"},
+		{2 * 24 * time.Hour, "feat: add exports"},
+	} {
+		runGit(now.Add(-c.age).Format(time.RFC3339), "commit", "-q", "--allow-empty", "-m", c.msg)
+	}
+
+	commits := func(window string) []string {
+		t.Helper()
+		w, err := core.ParseGitHistoryWindow(window)
+		if err != nil {
+			t.Fatalf("ParseGitHistoryWindow(%q): %v", window, err)
+		}
+		chunks, _ := gatherGitChunks(dir, w, false)
+		var msgs []string
+		for _, chunk := range chunks {
+			for _, line := range strings.Split(chunk, "\n") {
+				// "<hash> <date> <subject>"
+				if parts := strings.SplitN(line, " ", 3); len(parts) == 3 {
+					msgs = append(msgs, parts[2])
+				}
+			}
+		}
+		return msgs
+	}
+
+	if got := commits(""); len(got) != 4 {
+		t.Errorf("default window returned %v, want all 4 commits", got)
+	}
+	if got := commits("30d"); strings.Join(got, "|") != "feat: add exports|fix: billing rounding" {
+		t.Errorf("30d window returned %v, want the two newest commits", got)
+	}
+	if got := commits("26w"); len(got) != 3 {
+		t.Errorf("26w window returned %v, want 3 commits", got)
+	}
+	if got := commits("1"); strings.Join(got, "|") != "feat: add exports" {
+		t.Errorf("commit-count window returned %v, want only the newest commit", got)
+	}
+	if _, err := core.ParseGitHistoryWindow("-5d"); err == nil {
+		t.Error("negative window should be rejected")
+	}
+}
@@ -8,19 +8,21 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/cloudwego/eino/schema"
 	"github.com/josephgoksu/TaskWing/internal/agents/core"
 	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/task"
 )
 
 // ClarifyingAgent helps users refine their goals by asking questions.
 // Call Close() when done to release resources.
 type ClarifyingAgent struct {
 	core.BaseAgent
-	chain       *core.DeterministicChain[ClarifyingOutput]
-	modelCloser io.Closer
+	chain     *core.DeterministicChain[ClarifyingOutput]
+	chatModel *llm.CloseableChatModel
 }
 
 // ClarifyingOutput defines the structured response from the LLM.
@@ -40,45 +42,20 @@ func NewClarifyingAgent(cfg llm.Config) *ClarifyingAgent {
 
 // Close releases LLM resources. Safe to call multiple times.
 func (a *ClarifyingAgent) Close() error {
-	if a.modelCloser != nil {
-		return a.modelCloser.Close()
+	if a.chatModel != nil {
+		return a.chatModel.Close()
 	}
 	return nil
 }
 
 // Run executes the clarification loop using Eino Chain.
 func (a *ClarifyingAgent) Run(ctx context.Context, input core.Input) (core.Output, error) {
-	if a.chain == nil {
-		chatModel, err := a.CreateCloseableChatModel(ctx)
-		if err != nil {
-			return core.Output{}, err
-		}
-		a.modelCloser = chatModel
-		chain, err := core.NewDeterministicChain[ClarifyingOutput](
-			ctx,
-			a.Name(),
-			chatModel.BaseChatModel,
-			config.ClarifyingAgentUserTemplate,
-			core.WithSystemPrompt(config.ClarifyingAgentSystemPrompt),
-		)
-		if err != nil {
-			return core.Output{}, fmt.Errorf("create chain: %w", err)
-		}
-		a.chain = chain
+	if err := a.ensureChain(ctx, input.BasePath); err != nil {
+		return core.Output{}, err
 	}
-
-	goal, ok := input.ExistingContext["goal"].(string)
-	if !ok || goal == "" {
-		return core.Output{}, fmt.Errorf("missing 'goal' in input context")
-	}
-
-	history, _ := input.ExistingContext["history"].(string)
-	context, _ := input.ExistingContext["context"].(string)
-
-	chainInput := map[string]any{
-		"Goal":    goal,
-		"History": history,
-		"Context": context,
+	chainInput, err := clarifyingChainInput(input)
+	if err != nil {
+		return core.Output{}, err
 	}
 
 	parsed, raw, duration, err := a.chain.Invoke(ctx, chainInput)
@@ -91,6 +68,11 @@ func (a *ClarifyingAgent) Run(ctx context.Context, input core.Input) (core.Outpu
 		}, nil
 	}
 
+	return a.buildClarifyingOutput(parsed, duration), nil
+}
+
+// buildClarifyingOutput wraps a parsed clarification in the agent's Output.
+func (a *ClarifyingAgent) buildClarifyingOutput(parsed ClarifyingOutput, duration time.Duration) core.Output {
 	return core.BuildOutput(
 		a.Name(),
 		[]core.Finding{{
@@ -106,7 +88,49 @@ func (a *ClarifyingAgent) Run(ctx context.Context, input core.Input) (core.Outpu
 		}},
 		"JSON handled by Eino",
 		duration,
-	), nil
+	)
+}
+
+// ensureChain lazily creates the chat model and clarifying chain.
+func (a *ClarifyingAgent) ensureChain(ctx context.Context, basePath string) error {
+	if a.chain != nil {
+		return nil
+	}
+	chatModel, err := a.CreateCloseableChatModel(ctx)
+	if err != nil {
+		return err
+	}
+	a.chatModel = chatModel
+	chain, err := core.NewDeterministicChain[ClarifyingOutput](
+		ctx,
+		a.Name(),
+		chatModel.BaseChatModel,
+		a.PromptTemplate(basePath, config.ClarifyingAgentUserTemplate),
+		core.WithSystemPrompt(config.ClarifyingAgentSystemPrompt),
+		a.TimeoutOption(),
+	)
+	if err != nil {
+		return fmt.Errorf("create chain: %w", err)
+	}
+	a.chain = chain
+	return nil
+}
+
+// clarifyingChainInput builds the template variables for the clarifying prompt.
+func clarifyingChainInput(input core.Input) (map[string]any, error) {
+	goal, ok := input.ExistingContext["goal"].(string)
+	if !ok || goal == "" {
+		return nil, fmt.Errorf("missing 'goal' in input context")
+	}
+
+	history, _ := input.ExistingContext["history"].(string)
+	kgContext, _ := input.ExistingContext["context"].(string)
+
+	return map[string]any{
+		"Goal":    goal,
+		"History": history,
+		"Context": kgContext,
+	}, nil
 }
 
 // AutoAnswer (Auto-Refine) uses the LLM to fill in the specification draft based on architectural context.
@@ -176,24 +200,24 @@ Respond ONLY with the FULL, UPDATED technical specification. Use professional la
 // Call Close() when done to release resources.
 type PlanningAgent struct {
 	core.BaseAgent
-	chain       *core.DeterministicChain[PlanningOutput]
-	modelCloser io.Closer
+	chain     *core.DeterministicChain[PlanningOutput]
+	chatModel *llm.CloseableChatModel
 }
 
 // PlanningTask represents a single task in the plan.
 // Fields align with task.LLMTaskSchema for validation compatibility.
 type PlanningTask struct {
-	Title              string   `json:"title"`
-	Description        string   `json:"description"`
-	AcceptanceCriteria []string `json:"acceptance_criteria"`
-	ValidationSteps    []string `json:"validation_steps"`
-	Priority           int      `json:"priority"`
-	AssignedAgent      string   `json:"assigned_agent"`
-	Dependencies       []string `json:"dependencies"` // List of Task IDs (indices or titles)
-	Complexity         string   `json:"complexity"`   // "low", "medium", "high"
-	Scope              string   `json:"scope,omitempty"`
-	Keywords           []string `json:"keywords,omitempty"`
-	ExpectedFiles      []string `json:"expected_files,omitempty"` // Files expected to be created/modified/deleted
+	Title              string                `json:"title"`
+	Description        string                `json:"description"`
+	AcceptanceCriteria []string              `json:"acceptance_criteria"`
+	ValidationSteps    []task.ValidationStep `json:"validation_steps"` // Plain strings are commands
+	Priority           int                   `json:"priority"`
+	AssignedAgent      string                `json:"assigned_agent"`
+	Dependencies       []string              `json:"dependencies"` // List of Task IDs (indices or titles)
+	Complexity         string                `json:"complexity"`   // "low", "medium", "high"
+	Scope              string                `json:"scope,omitempty"`
+	Keywords           []string              `json:"keywords,omitempty"`
+	ExpectedFiles      []string              `json:"expected_files,omitempty"` // Files expected to be created/modified/deleted
 }
 
 // PlanningOutput defines the structured response from the LLM.
@@ -211,49 +235,20 @@ func NewPlanningAgent(cfg llm.Config) *PlanningAgent {
 
 // Close releases LLM resources. Safe to call multiple times.
 func (a *PlanningAgent) Close() error {
-	if a.modelCloser != nil {
-		return a.modelCloser.Close()
+	if a.chatModel != nil {
+		return a.chatModel.Close()
 	}
 	return nil
 }
 
 // Run executes the planning logic using Eino Chain.
 func (a *PlanningAgent) Run(ctx context.Context, input core.Input) (core.Output, error) {
-	if a.chain == nil {
-		chatModel, err := a.CreateCloseableChatModel(ctx)
-		if err != nil {
-			return core.Output{}, err
-		}
-		a.modelCloser = chatModel
-		chain, err := core.NewDeterministicChain[PlanningOutput](
-			ctx,
-			a.Name(),
-			chatModel.BaseChatModel,
-			config.PlanningAgentUserTemplate,
-			core.WithSystemPrompt(config.PlanningAgentSystemPrompt),
-		)
-		if err != nil {
-			return core.Output{}, fmt.Errorf("create chain: %w", err)
-		}
-		a.chain = chain
+	if err := a.ensureChain(ctx, input.BasePath); err != nil {
+		return core.Output{}, err
 	}
-
-	goal, ok := input.ExistingContext["enriched_goal"].(string)
-	if !ok || goal == "" {
-		goal, _ = input.ExistingContext["goal"].(string)
-	}
-	if goal == "" {
-		return core.Output{}, fmt.Errorf("missing 'enriched_goal' or 'goal' in input context")
-	}
-
-	kgContext, _ := input.ExistingContext["context"].(string)
-	if kgContext == "" {
-		kgContext = "No specific knowledge graph context provided."
-	}
-
-	chainInput := map[string]any{
-		"Goal":    goal,
-		"Context": kgContext,
+	chainInput, err := planningChainInput(input)
+	if err != nil {
+		return core.Output{}, err
 	}
 
 	parsed, raw, duration, err := a.chain.Invoke(ctx, chainInput)
@@ -279,6 +274,52 @@ func (a *PlanningAgent) Run(ctx context.Context, input core.Input) (core.Output,
 	), nil
 }
 
+// ensureChain lazily creates the chat model and planning chain.
+func (a *PlanningAgent) ensureChain(ctx context.Context, basePath string) error {
+	if a.chain != nil {
+		return nil
+	}
+	chatModel, err := a.CreateCloseableChatModel(ctx)
+	if err != nil {
+		return err
+	}
+	a.chatModel = chatModel
+	chain, err := core.NewDeterministicChain[PlanningOutput](
+		ctx,
+		a.Name(),
+		chatModel.BaseChatModel,
+		a.PromptTemplate(basePath, config.PlanningAgentUserTemplate),
+		core.WithSystemPrompt(config.PlanningAgentSystemPrompt),
+		a.TimeoutOption(),
+	)
+	if err != nil {
+		return fmt.Errorf("create chain: %w", err)
+	}
+	a.chain = chain
+	return nil
+}
+
+// planningChainInput builds the template variables for the planning prompt.
+func planningChainInput(input core.Input) (map[string]any, error) {
+	goal, ok := input.ExistingContext["enriched_goal"].(string)
+	if !ok || goal == "" {
+		goal, _ = input.ExistingContext["goal"].(string)
+	}
+	if goal == "" {
+		return nil, fmt.Errorf("missing 'enriched_goal' or 'goal' in input context")
+	}
+
+	kgContext, _ := input.ExistingContext["context"].(string)
+	if kgContext == "" {
+		kgContext = "No specific knowledge graph context provided."
+	}
+
+	return map[string]any{
+		"Goal":    goal,
+		"Context": kgContext,
+	}, nil
+}
+
 // DecompositionAgent breaks enriched goals into high-level phases.
 // This is the second stage of interactive planning (after clarify).
 // Call Close() when done to release resources.
@@ -330,8 +371,9 @@ func (a *DecompositionAgent) Run(ctx context.Context, input core.Input) (core.Ou
 			ctx,
 			a.Name(),
 			chatModel.BaseChatModel,
-			config.DecompositionAgentUserTemplate,
+			a.PromptTemplate(input.BasePath, config.DecompositionAgentUserTemplate),
 			core.WithSystemPrompt(config.DecompositionAgentSystemPrompt),
+			a.TimeoutOption(),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -422,8 +464,9 @@ func (a *ExpandAgent) Run(ctx context.Context, input core.Input) (core.Output, e
 			ctx,
 			a.Name(),
 			chatModel.BaseChatModel,
-			config.ExpandAgentUserTemplate,
+			a.PromptTemplate(input.BasePath, config.ExpandAgentUserTemplate),
 			core.WithSystemPrompt(config.ExpandAgentSystemPrompt),
+			a.TimeoutOption(),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -443,11 +486,16 @@ func (a *ExpandAgent) Run(ctx context.Context, input core.Input) (core.Output, e
 		kgContext = "No specific knowledge graph context provided."
 	}
 
+	feedback, _ := input.ExistingContext["feedback"].(string)
+	history, _ := input.ExistingContext["history"].(string)
+
 	chainInput := map[string]any{
 		"PhaseTitle":       phaseTitle,
 		"PhaseDescription": phaseDescription,
 		"EnrichedGoal":     enrichedGoal,
 		"Context":          kgContext,
+		"Feedback":         feedback,
+		"History":          history,
 	}
 
 	parsed, raw, duration, err := a.chain.Invoke(ctx, chainInput)
@@ -0,0 +1,311 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+)
+
+// RunStream generates a plan like Run, but calls emit for each task as soon as
+// the model has finished writing it, so callers can persist tasks incrementally.
+// Returning an error from emit aborts the stream.
+func (a *PlanningAgent) RunStream(ctx context.Context, input core.Input, emit func(PlanningTask) error) error {
+	if err := a.ensureChain(ctx, input.BasePath); err != nil {
+		return err
+	}
+	chainInput, err := planningChainInput(input)
+	if err != nil {
+		return err
+	}
+	messages, err := a.chain.RenderMessages(ctx, chainInput)
+	if err != nil {
+		return fmt.Errorf("render prompt: %w", err)
+	}
+
+	streamCtx, cancel := a.TimeoutContext(ctx)
+	defer cancel()
+	stream, err := a.chatModel.Stream(streamCtx, messages)
+	if err != nil {
+		return streamError(ctx, streamCtx, a.LLMConfig().AgentTimeout, "stream", err)
+	}
+	defer stream.Close()
+
+	var decoder PlanningTaskDecoder
+	emitted := 0
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return streamError(ctx, streamCtx, a.LLMConfig().AgentTimeout, "recv stream", err)
+		}
+		tasks, err := decoder.Write(chunk.Content)
+		if err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if err := emit(t); err != nil {
+				return err
+			}
+			emitted++
+		}
+	}
+
+	if emitted > 0 {
+		return nil
+	}
+
+	// Nothing was decoded incrementally (e.g. unusual formatting); parse the full response.
+	parsed, err := a.chain.ParseResponse(decoder.String())
+	if err != nil {
+		return fmt.Errorf("parse planning response: %w", err)
+	}
+	for _, t := range parsed.Tasks {
+		if err := emit(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamError wraps a failure of a stream running under streamCtx, reporting
+// core.ErrInvocationTimeout when the agent timeout rather than ctx ended it.
+func streamError(ctx, streamCtx context.Context, timeout time.Duration, op string, err error) error {
+	if streamCtx.Err() != nil && ctx.Err() == nil {
+		return fmt.Errorf("%s: %w after %v", op, core.ErrInvocationTimeout, timeout)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// PlanningTaskDecoder incrementally extracts tasks from a streamed PlanningOutput
+// JSON document. Each element of the "tasks" array is returned once its closing
+// brace has arrived.
+type PlanningTaskDecoder struct {
+	buf      strings.Builder
+	pos      int  // Next unscanned byte
+	inArray  bool // Inside the "tasks" array
+	done     bool // The "tasks" array has been closed
+	depth    int  // Brace depth within the array
+	inString bool
+	escaped  bool
+	objStart int
+}
+
+// Write appends a chunk of model output and returns the tasks completed by it.
+func (d *PlanningTaskDecoder) Write(chunk string) ([]PlanningTask, error) {
+	d.buf.WriteString(chunk)
+	if d.done {
+		return nil, nil
+	}
+	data := d.buf.String()
+
+	if !d.inArray {
+		start, ok := findJSONArray(data, "tasks")
+		if !ok {
+			return nil, nil
+		}
+		d.inArray = true
+		d.pos = start
+	}
+
+	var tasks []PlanningTask
+	for ; d.pos < len(data); d.pos++ {
+		c := data[d.pos]
+		if d.inString {
+			switch {
+			case d.escaped:
+				d.escaped = false
+			case c == '\\':
+				d.escaped = true
+			case c == '"':
+				d.inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			d.inString = true
+		case '{':
+			if d.depth == 0 {
+				d.objStart = d.pos
+			}
+			d.depth++
+		case '}':
+			d.depth--
+			if d.depth == 0 {
+				var t PlanningTask
+				if err := json.Unmarshal([]byte(data[d.objStart:d.pos+1]), &t); err != nil {
+					return tasks, fmt.Errorf("decode streamed task: %w", err)
+				}
+				tasks = append(tasks, t)
+			}
+		case ']':
+			if d.depth == 0 {
+				d.done = true
+				d.pos++
+				return tasks, nil
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// String returns everything written so far.
+func (d *PlanningTaskDecoder) String() string {
+	return d.buf.String()
+}
+
+// findJSONArray returns the offset just past the '[' that opens the array
+// stored under key.
+func findJSONArray(data, key string) (int, bool) {
+	quoted := `"` + key + `"`
+	at := strings.Index(data, quoted)
+	if at < 0 {
+		return 0, false
+	}
+	rest := strings.TrimLeft(data[at+len(quoted):], " \t\r\n")
+	if !strings.HasPrefix(rest, ":") {
+		return 0, false
+	}
+	rest = strings.TrimLeft(rest[1:], " \t\r\n")
+	if !strings.HasPrefix(rest, "[") {
+		return 0, false
+	}
+	return len(data) - len(rest) + 1, true
+}
+
+// RunStream clarifies like Run, but calls onQuestion for each question as soon
+// as the model has finished writing it. The returned Output is the same as Run's.
+func (a *ClarifyingAgent) RunStream(ctx context.Context, input core.Input, onQuestion func(string)) (core.Output, error) {
+	if err := a.ensureChain(ctx, input.BasePath); err != nil {
+		return core.Output{}, err
+	}
+	chainInput, err := clarifyingChainInput(input)
+	if err != nil {
+		return core.Output{}, err
+	}
+	messages, err := a.chain.RenderMessages(ctx, chainInput)
+	if err != nil {
+		return core.Output{}, fmt.Errorf("render prompt: %w", err)
+	}
+
+	start := time.Now()
+	streamCtx, cancel := a.TimeoutContext(ctx)
+	defer cancel()
+	stream, err := a.chatModel.Stream(streamCtx, messages)
+	if err != nil {
+		return core.Output{}, streamError(ctx, streamCtx, a.LLMConfig().AgentTimeout, "stream", err)
+	}
+	defer stream.Close()
+
+	var decoder QuestionDecoder
+	emitted := 0
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return core.Output{}, streamError(ctx, streamCtx, a.LLMConfig().AgentTimeout, "recv stream", err)
+		}
+		questions, err := decoder.Write(chunk.Content)
+		if err != nil {
+			return core.Output{}, err
+		}
+		for _, q := range questions {
+			onQuestion(q)
+			emitted++
+		}
+	}
+
+	parsed, err := a.chain.ParseResponse(decoder.String())
+	if err != nil {
+		return core.Output{
+			AgentName: a.Name(),
+			Error:     fmt.Errorf("parse clarifying response: %w", err),
+			Duration:  time.Since(start),
+			RawOutput: decoder.String(),
+		}, nil
+	}
+	// Nothing was decoded incrementally (e.g. unusual formatting); report the parsed questions.
+	if emitted == 0 {
+		for _, q := range parsed.Questions {
+			onQuestion(q)
+		}
+	}
+	return a.buildClarifyingOutput(parsed, time.Since(start)), nil
+}
+
+// QuestionDecoder incrementally extracts questions from a streamed
+// ClarifyingOutput JSON document. Each element of the "questions" array is
+// returned once its closing quote has arrived.
+type QuestionDecoder struct {
+	buf      strings.Builder
+	pos      int  // Next unscanned byte
+	inArray  bool // Inside the "questions" array
+	done     bool // The "questions" array has been closed
+	inString bool
+	escaped  bool
+	strStart int
+}
+
+// Write appends a chunk of model output and returns the questions completed by it.
+func (d *QuestionDecoder) Write(chunk string) ([]string, error) {
+	d.buf.WriteString(chunk)
+	if d.done {
+		return nil, nil
+	}
+	data := d.buf.String()
+
+	if !d.inArray {
+		start, ok := findJSONArray(data, "questions")
+		if !ok {
+			return nil, nil
+		}
+		d.inArray = true
+		d.pos = start
+	}
+
+	var questions []string
+	for ; d.pos < len(data); d.pos++ {
+		c := data[d.pos]
+		if d.inString {
+			switch {
+			case d.escaped:
+				d.escaped = false
+			case c == '\\':
+				d.escaped = true
+			case c == '"':
+				d.inString = false
+				var q string
+				if err := json.Unmarshal([]byte(data[d.strStart:d.pos+1]), &q); err != nil {
+					return questions, fmt.Errorf("decode streamed question: %w", err)
+				}
+				questions = append(questions, q)
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			d.inString = true
+			d.strStart = d.pos
+		case ']':
+			d.done = true
+			d.pos++
+			return questions, nil
+		}
+	}
+	return questions, nil
+}
+
+// String returns everything written so far.
+func (d *QuestionDecoder) String() string {
+	return d.buf.String()
+}
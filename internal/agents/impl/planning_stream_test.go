@@ -0,0 +1,94 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+)
+
+func TestPlanningTaskDecoder(t *testing.T) {
+	response := "```json\n" + `{"tasks": [{"title": "Add {braces} \"quoted\"", "description": "a"}, {"title": "Second", "description": "b", "dependencies": ["Add {braces} \"quoted\""]}], "rationale": "x"}` + "\n```"
+
+	// Feed the response in small chunks to split tokens, strings, and objects
+	var d PlanningTaskDecoder
+	var titles []string
+	for i := 0; i < len(response); i += 7 {
+		tasks, err := d.Write(response[i:min(i+7, len(response))])
+		if err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		for _, task := range tasks {
+			titles = append(titles, task.Title)
+		}
+	}
+
+	if len(titles) != 2 || titles[0] != `Add {braces} "quoted"` || titles[1] != "Second" {
+		t.Errorf("decoded titles = %q", titles)
+	}
+}
+
+func TestQuestionDecoder(t *testing.T) {
+	response := `{"questions": ["Which DB [sql or \"nosql\"]?", "Auth?"], "goal_summary": "x", "enriched_goal": "y", "is_ready_to_plan": false}`
+
+	var d QuestionDecoder
+	var questions []string
+	for i := 0; i < len(response); i += 5 {
+		qs, err := d.Write(response[i:min(i+5, len(response))])
+		if err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		questions = append(questions, qs...)
+	}
+
+	if len(questions) != 2 || questions[0] != `Which DB [sql or "nosql"]?` || questions[1] != "Auth?" {
+		t.Errorf("decoded questions = %q", questions)
+	}
+}
+
+// stallingChatModel streams the start of a plan, then stops sending until
+// the request context ends.
+type stallingChatModel struct{}
+
+func (stallingChatModel) Generate(ctx context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (stallingChatModel) Stream(ctx context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer sw.Close()
+		sw.Send(schema.AssistantMessage(`{"tasks": [`, nil), nil)
+		<-ctx.Done()
+		sw.Send(nil, ctx.Err())
+	}()
+	return sr, nil
+}
+
+func TestPlanningAgent_RunStreamAppliesAgentTimeout(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	ctx := context.Background()
+	agent := NewPlanningAgent(llm.Config{AgentTimeout: timeout})
+	agent.chatModel = &llm.CloseableChatModel{BaseChatModel: stallingChatModel{}}
+	chain, err := core.NewDeterministicChain[PlanningOutput](ctx, agent.Name(), agent.chatModel, "Plan {{.Goal}}")
+	if err != nil {
+		t.Fatalf("NewDeterministicChain: %v", err)
+	}
+	agent.chain = chain
+
+	start := time.Now()
+	err = agent.RunStream(ctx, core.Input{ExistingContext: map[string]any{"goal": "Add login"}}, func(PlanningTask) error { return nil })
+	if !errors.Is(err, core.ErrInvocationTimeout) {
+		t.Fatalf("RunStream error = %v, want ErrInvocationTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*timeout {
+		t.Errorf("RunStream took %v, want it cut off near %v", elapsed, timeout)
+	}
+}
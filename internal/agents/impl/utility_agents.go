@@ -7,10 +7,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/josephgoksu/TaskWing/internal/agents/core"
 	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/task"
 )
 
 // =============================================================================
@@ -70,6 +72,7 @@ func (a *SimplifyAgent) Run(ctx context.Context, input core.Input) (core.Output,
 			a.Name(),
 			chatModel.BaseChatModel,
 			config.SystemPromptSimplifyAgent,
+			a.TimeoutOption(),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -183,6 +186,7 @@ func (a *ExplainAgent) Run(ctx context.Context, input core.Input) (core.Output,
 			a.Name(),
 			chatModel.BaseChatModel,
 			config.SystemPromptExplainAgent,
+			a.TimeoutOption(),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -302,6 +306,7 @@ func (a *DebugAgent) Run(ctx context.Context, input core.Input) (core.Output, er
 			a.Name(),
 			chatModel.BaseChatModel,
 			config.SystemPromptDebugAgent,
+			a.TimeoutOption(),
 		)
 		if err != nil {
 			return core.Output{}, fmt.Errorf("create chain: %w", err)
@@ -317,12 +322,14 @@ func (a *DebugAgent) Run(ctx context.Context, input core.Input) (core.Output, er
 	errorMsg, _ := input.ExistingContext["error"].(string)
 	stackTrace, _ := input.ExistingContext["stack_trace"].(string)
 	kgContext, _ := input.ExistingContext["context"].(string)
+	relatedNotes, _ := input.ExistingContext["related_notes"].(string)
 
 	chainInput := map[string]any{
-		"Problem":    problem,
-		"Error":      errorMsg,
-		"StackTrace": stackTrace,
-		"Context":    kgContext,
+		"Problem":      problem,
+		"Error":        errorMsg,
+		"StackTrace":   stackTrace,
+		"Context":      kgContext,
+		"RelatedNotes": relatedNotes,
 	}
 
 	parsed, raw, duration, err := a.chain.Invoke(ctx, chainInput)
@@ -375,3 +382,96 @@ func init() {
 		return NewDebugAgent(cfg)
 	}, "Debug Helper", "Helps diagnose issues systematically")
 }
+
+// =============================================================================
+// CriteriaVerificationAgent
+// =============================================================================
+
+// CriteriaVerificationAgent checks a completed task's acceptance criteria
+// against the files it touched.
+// Call Close() when done to release resources.
+type CriteriaVerificationAgent struct {
+	core.BaseAgent
+	chain       *core.DeterministicChain[CriteriaVerificationOutput]
+	modelCloser io.Closer
+}
+
+// CriteriaVerificationOutput defines the structured response from the LLM.
+type CriteriaVerificationOutput struct {
+	Criteria []task.CriterionVerification `json:"criteria"`
+}
+
+// NewCriteriaVerificationAgent creates a new agent for acceptance criteria verification.
+func NewCriteriaVerificationAgent(cfg llm.Config) *CriteriaVerificationAgent {
+	return &CriteriaVerificationAgent{
+		BaseAgent: core.NewBaseAgent("criteria_verification", "Checks acceptance criteria against a task's changes", cfg),
+	}
+}
+
+// Close releases LLM resources. Safe to call multiple times.
+func (a *CriteriaVerificationAgent) Close() error {
+	if a.modelCloser != nil {
+		return a.modelCloser.Close()
+	}
+	return nil
+}
+
+// Run verifies the criteria using Eino Chain.
+func (a *CriteriaVerificationAgent) Run(ctx context.Context, input core.Input) (core.Output, error) {
+	if a.chain == nil {
+		chatModel, err := a.CreateCloseableChatModel(ctx)
+		if err != nil {
+			return core.Output{}, err
+		}
+		a.modelCloser = chatModel
+		chain, err := core.NewDeterministicChain[CriteriaVerificationOutput](
+			ctx,
+			a.Name(),
+			chatModel.BaseChatModel,
+			config.SystemPromptCriteriaVerificationAgent,
+			a.TimeoutOption(),
+		)
+		if err != nil {
+			return core.Output{}, fmt.Errorf("create chain: %w", err)
+		}
+		a.chain = chain
+	}
+
+	criteria, ok := input.ExistingContext["criteria"].([]string)
+	if !ok || len(criteria) == 0 {
+		return core.Output{}, fmt.Errorf("missing 'criteria' in input context")
+	}
+
+	title, _ := input.ExistingContext["title"].(string)
+	summary, _ := input.ExistingContext["summary"].(string)
+	files, _ := input.ExistingContext["files"].(string)
+
+	chainInput := map[string]any{
+		"Title":    title,
+		"Summary":  summary,
+		"Criteria": "- " + strings.Join(criteria, "\n- "),
+		"Files":    files,
+	}
+
+	parsed, raw, duration, err := a.chain.Invoke(ctx, chainInput)
+	if err != nil {
+		return core.Output{
+			AgentName: a.Name(),
+			Error:     fmt.Errorf("chain invoke: %w", err),
+			Duration:  duration,
+			RawOutput: raw,
+		}, nil
+	}
+
+	return core.BuildOutput(
+		a.Name(),
+		[]core.Finding{{
+			Type:        "verification",
+			Title:       "Acceptance Criteria Verification",
+			Description: fmt.Sprintf("Checked %d acceptance criteria", len(parsed.Criteria)),
+			Metadata:    map[string]any{"criteria": parsed.Criteria},
+		}},
+		"JSON handled by Eino",
+		duration,
+	), nil
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/llm"
 	"github.com/josephgoksu/TaskWing/internal/utils"
 )
@@ -48,9 +49,10 @@ type ChunkFile struct {
 
 // CodeChunker splits a codebase into LLM-friendly chunks.
 type CodeChunker struct {
-	basePath string
-	config   ChunkConfig
-	coverage CoverageStats
+	basePath        string
+	config          ChunkConfig
+	coverage        CoverageStats
+	excludePatterns []string
 }
 
 // NewCodeChunker creates a new code chunker.
@@ -62,9 +64,15 @@ func NewCodeChunker(basePath string) *CodeChunker {
 			FilesRead:    make([]FileRecord, 0),
 			FilesSkipped: make([]SkipRecord, 0),
 		},
+		excludePatterns: config.LoadExcludeFilePatterns(),
 	}
 }
 
+// SetExcludePatterns replaces the glob patterns for source files to skip.
+func (c *CodeChunker) SetExcludePatterns(patterns []string) {
+	c.excludePatterns = patterns
+}
+
 // SetConfig updates the chunking configuration.
 // C6 Fix: Validates config values and falls back to defaults for invalid values.
 func (c *CodeChunker) SetConfig(cfg ChunkConfig) {
@@ -180,6 +188,15 @@ func (c *CodeChunker) collectFiles() ([]prioritizedFile, error) {
 			return nil
 		}
 
+		// Skip generated and other excluded files
+		if utils.MatchesFilePattern(relPath, c.excludePatterns) {
+			c.coverage.FilesSkipped = append(c.coverage.FilesSkipped, SkipRecord{
+				Path:   relPath,
+				Reason: "excluded by pattern",
+			})
+			return nil
+		}
+
 		// Skip symlinks
 		if isSymlink(path) {
 			c.coverage.FilesSkipped = append(c.coverage.FilesSkipped, SkipRecord{
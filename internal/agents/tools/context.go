@@ -9,7 +9,9 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/llm"
 	"github.com/josephgoksu/TaskWing/internal/utils"
 )
@@ -70,9 +72,12 @@ type CoverageStats struct {
 
 // ContextGatherer provides methods for gathering file context.
 type ContextGatherer struct {
-	BasePath string
-	coverage CoverageStats
-	budget   *ContextBudget
+	BasePath        string
+	coverage        CoverageStats
+	budget          *ContextBudget
+	excludePatterns []string
+	includeDirs     []string // If set, only docs under these directories are gathered
+	excludeDirs     []string // Docs under these directories are never gathered
 }
 
 // NewContextGatherer creates a new helper for gathering context.
@@ -83,9 +88,83 @@ func NewContextGatherer(basePath string) *ContextGatherer {
 			FilesRead:    make([]FileRecord, 0),
 			FilesSkipped: make([]SkipRecord, 0),
 		},
+		excludePatterns: config.LoadExcludeFilePatterns(),
 	}
 }
 
+// SetExcludePatterns replaces the glob patterns for source files to skip.
+func (g *ContextGatherer) SetExcludePatterns(patterns []string) {
+	g.excludePatterns = patterns
+}
+
+// SetDirScope restricts markdown gathering to the include directories (all
+// directories if empty) minus the exclude directories. Paths are relative to
+// BasePath, e.g. "services/billing". Root-level files are always gathered.
+func (g *ContextGatherer) SetDirScope(include, exclude []string) {
+	g.includeDirs = cleanScopeDirs(include)
+	g.excludeDirs = cleanScopeDirs(exclude)
+}
+
+// cleanScopeDirs normalizes scope directories to slash-separated relative paths.
+func cleanScopeDirs(dirs []string) []string {
+	var out []string
+	for _, d := range dirs {
+		d = strings.Trim(filepath.ToSlash(filepath.Clean(strings.TrimSpace(d))), "/")
+		if d != "" && d != "." {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// underDir reports whether relPath is dir or inside it.
+func underDir(relPath, dir string) bool {
+	return relPath == dir || strings.HasPrefix(relPath, dir+"/")
+}
+
+// dirInScope reports whether docs in the directory relDir may be gathered.
+// The base directory itself is always in scope.
+func (g *ContextGatherer) dirInScope(relDir string) bool {
+	relDir = filepath.ToSlash(relDir)
+	if relDir == "" || relDir == "." {
+		return true
+	}
+	for _, ex := range g.excludeDirs {
+		if underDir(relDir, ex) {
+			return false
+		}
+	}
+	if len(g.includeDirs) == 0 {
+		return true
+	}
+	for _, in := range g.includeDirs {
+		if underDir(relDir, in) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirLeadsToScope reports whether walking relDir can reach an in-scope
+// directory: it is in scope itself or is a parent of an include directory.
+func (g *ContextGatherer) dirLeadsToScope(relDir string) bool {
+	if g.dirInScope(relDir) {
+		return true
+	}
+	relDir = filepath.ToSlash(relDir)
+	for _, ex := range g.excludeDirs {
+		if underDir(relDir, ex) {
+			return false
+		}
+	}
+	for _, in := range g.includeDirs {
+		if strings.HasPrefix(in, relDir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // SetBudget assigns a context budget to the gatherer.
 // If set, gathering will stop when the budget is exceeded.
 func (g *ContextGatherer) SetBudget(b *ContextBudget) {
@@ -129,10 +208,19 @@ func (g *ContextGatherer) recordSkip(relPath, reason string) {
 // GatherMarkdownDocs reads markdown files from root, docs/, and package-level READMEs.
 // Includes line numbers so LLM can provide accurate evidence with start_line/end_line.
 func (g *ContextGatherer) GatherMarkdownDocs() string {
-	var sb strings.Builder
+	return strings.Join(g.GatherMarkdownDocSections(), "")
+}
+
+// GatherMarkdownDocSections is like GatherMarkdownDocs but returns one formatted
+// section per file, so callers can batch large documentation trees.
+func (g *ContextGatherer) GatherMarkdownDocSections() []string {
+	var sections []string
 	seen := make(map[string]bool) // Key: relative path (lowercase) for consistent deduplication
 
 	gatherFromDir := func(dir, prefix string, maxLen int) {
+		if !g.dirInScope(prefix) {
+			return
+		}
 		safeDir, err := utils.ValidateAbsPath(g.BasePath, dir)
 		if err != nil {
 			// If dir == g.BasePath itself, allow it.
@@ -189,7 +277,7 @@ func (g *ContextGatherer) GatherMarkdownDocs() string {
 
 			// Track this file read for coverage reporting
 			g.recordRead(relPath, content, truncated)
-			sb.WriteString(formatted)
+			sections = append(sections, formatted)
 			seen[key] = true
 		}
 	}
@@ -201,6 +289,8 @@ func (g *ContextGatherer) GatherMarkdownDocs() string {
 	// NEW: Recursively gather package-level READMEs from common source directories
 	// These contain critical implementation details (auth patterns, error handling, etc.)
 	packageDirs := []string{"internal", "pkg", "lib", "src", "app", "server", "api"}
+	// Scoped include directories are walked even without a conventional layout
+	packageDirs = append(packageDirs, g.includeDirs...)
 
 	// Also check for monorepo subdirectories (e.g., backend-go/internal)
 	entries, _ := os.ReadDir(g.BasePath)
@@ -237,6 +327,9 @@ func (g *ContextGatherer) GatherMarkdownDocs() string {
 		if g.budget != nil && g.budget.IsExhausted() {
 			break
 		}
+		if !g.dirLeadsToScope(pkgDir) {
+			continue
+		}
 		searchDir := filepath.Join(g.BasePath, pkgDir)
 		_ = filepath.WalkDir(searchDir, func(path string, d os.DirEntry, err error) error {
 			if err != nil {
@@ -249,17 +342,17 @@ func (g *ContextGatherer) GatherMarkdownDocs() string {
 				return nil
 			}
 
+			relPath, _ := filepath.Rel(g.BasePath, path)
 			if d.IsDir() {
-				if utils.ShouldIgnoreDir(d.Name()) {
+				if utils.ShouldIgnoreDir(d.Name()) || !g.dirLeadsToScope(relPath) {
 					return filepath.SkipDir
 				}
 				return nil
 			}
 			nameLower := strings.ToLower(d.Name())
-			if !importantMdFiles[nameLower] {
+			if !importantMdFiles[nameLower] || !g.dirInScope(filepath.Dir(relPath)) {
 				return nil
 			}
-			relPath, _ := filepath.Rel(g.BasePath, path)
 			// Use lowercase path as key for consistent deduplication
 			key := strings.ToLower(relPath)
 			if seen[key] {
@@ -288,12 +381,12 @@ func (g *ContextGatherer) GatherMarkdownDocs() string {
 
 			// Track this file read for coverage reporting
 			g.recordRead(relPath, content, truncated)
-			sb.WriteString(formatted)
+			sections = append(sections, formatted)
 			return nil
 		})
 	}
 
-	return sb.String()
+	return sections
 }
 
 // GatherKeyFiles reads critical key files like README.md, go.mod, package.json.
@@ -445,25 +538,42 @@ func (g *ContextGatherer) GatherCIConfigs() string {
 	return sb.String()
 }
 
+// specificFilesConcurrency bounds the concurrent reads in GatherSpecificFiles.
+const specificFilesConcurrency = 8
+
 // GatherSpecificFiles reads specific files given a list of relative paths.
+// Files are read concurrently, but sections are emitted in the order given so
+// the output is deterministic.
 func (g *ContextGatherer) GatherSpecificFiles(files []string) string {
-	var sb strings.Builder
-	for _, relPath := range files {
-		fullPath, joinErr := utils.SafeJoin(g.BasePath, relPath)
-		if joinErr != nil {
-			continue
-		}
-		content, err := os.ReadFile(fullPath)
-		if err != nil {
-			continue
-		}
-		if len(content) > 12000 { // Increased limit for specific files since we focus on them
-			content = append(content[:12000], []byte("\n...[truncated]")...)
-		}
-		numberedContent := addLineNumbers(string(content))
-		sb.WriteString(fmt.Sprintf("## %s\n```\n%s\n```\n\n", relPath, numberedContent))
+	sections := make([]string, len(files))
+	sem := make(chan struct{}, specificFilesConcurrency)
+
+	var wg sync.WaitGroup
+	for i, relPath := range files {
+		wg.Add(1)
+		go func(i int, relPath string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fullPath, joinErr := utils.SafeJoin(g.BasePath, relPath)
+			if joinErr != nil {
+				return
+			}
+			content, err := os.ReadFile(fullPath)
+			if err != nil {
+				return
+			}
+			if len(content) > 12000 { // Increased limit for specific files since we focus on them
+				content = append(content[:12000], []byte("\n...[truncated]")...)
+			}
+			numberedContent := addLineNumbers(string(content))
+			sections[i] = fmt.Sprintf("## %s\n```\n%s\n```\n\n", relPath, numberedContent)
+		}(i, relPath)
 	}
-	return sb.String()
+	wg.Wait()
+
+	return strings.Join(sections, "")
 }
 
 // GatherSourceCode reads key source files: entry points, handlers, configs, middleware.
@@ -507,6 +617,10 @@ func (g *ContextGatherer) GatherSourceCode() string {
 		if err != nil || info.IsDir() {
 			return false
 		}
+		if utils.MatchesFilePattern(relPath, g.excludePatterns) {
+			g.recordSkip(relPath, "excluded by pattern")
+			return false
+		}
 		// Only add code files (filter by extension)
 		ext := filepath.Ext(relPath)
 		if !utils.CodeExtensions[ext] && !utils.ConfigExtensions[ext] && !isConfigFile(filepath.Base(relPath)) {
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContextGatherer_DirScope(t *testing.T) {
+	base := t.TempDir()
+	files := map[string]string{
+		"README.md":                      "Monorepo root",
+		"billing/internal/README.md":     "Billing service internals",
+		"billing/docs/design.md":         "Billing design",
+		"search/internal/README.md":      "Search service internals",
+		"search/internal/index/api.md":   "Search index API",
+		"billing/internal/ledger/api.md": "Ledger API",
+	}
+	for rel, content := range files {
+		path := filepath.Join(base, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g := NewContextGatherer(base)
+	g.SetDirScope(nil, []string{"search"})
+	docs := g.GatherMarkdownDocs()
+	for _, want := range []string{"Monorepo root", "Billing service internals", "Ledger API"} {
+		if !strings.Contains(docs, want) {
+			t.Errorf("docs missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"Search service internals", "Search index API"} {
+		if strings.Contains(docs, unwanted) {
+			t.Errorf("excluded dir leaked %q", unwanted)
+		}
+	}
+
+	// Include walks the service even outside the conventional package layout
+	g = NewContextGatherer(base)
+	g.SetDirScope([]string{"billing/docs/"}, nil)
+	docs = g.GatherMarkdownDocs()
+	if !strings.Contains(docs, "Billing design") {
+		t.Errorf("included dir docs missing:\n%s", docs)
+	}
+	if strings.Contains(docs, "Billing service internals") || strings.Contains(docs, "Search") {
+		t.Errorf("docs outside include dir gathered:\n%s", docs)
+	}
+}
+
+func TestContextGatherer_GatherSpecificFilesOrdered(t *testing.T) {
+	base := t.TempDir()
+	var files []string
+	for i := range 40 {
+		rel := filepath.Join("pkg", fmt.Sprintf("file%02d.go", i))
+		path := filepath.Join(base, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("package pkg // marker %02d\n", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, rel)
+	}
+	files = append(files, "pkg/missing.go")
+
+	g := NewContextGatherer(base)
+	first := g.GatherSpecificFiles(files)
+	for range 5 {
+		if again := g.GatherSpecificFiles(files); again != first {
+			t.Fatal("output differs between runs")
+		}
+	}
+
+	last := -1
+	for i := range 40 {
+		pos := strings.Index(first, fmt.Sprintf("marker %02d", i))
+		if pos < 0 {
+			t.Fatalf("output missing file%02d.go", i)
+		}
+		if pos < last {
+			t.Errorf("file%02d.go out of order", i)
+		}
+		last = pos
+	}
+	if strings.Contains(first, "missing.go") {
+		t.Error("unreadable file should be skipped")
+	}
+}
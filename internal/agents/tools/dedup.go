@@ -182,16 +182,7 @@ func (d *FindingDeduplicator) getConfidence(f core.Finding) float64 {
 	}
 
 	// Fall back to legacy string Confidence field
-	switch strings.ToLower(f.Confidence) {
-	case "high":
-		return 0.9
-	case "medium":
-		return 0.6
-	case "low":
-		return 0.3
-	}
-
-	return 0.5 // Default medium confidence
+	return core.ConfidenceScoreFromLabel(f.Confidence)
 }
 
 // jaccardSimilarity calculates word-level Jaccard similarity between two strings.
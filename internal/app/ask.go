@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -42,13 +44,32 @@ type AskResult struct {
 	RewrittenQuery string                   `json:"rewritten_query,omitempty"`
 	Pipeline       string                   `json:"pipeline"`
 	Results        []knowledge.NodeResponse `json:"results"`
+	Related        []knowledge.NodeResponse `json:"related,omitempty"`        // One hop from Results (ExpandRelated)
+	FeatureGroups  []AskFeatureGroup        `json:"feature_groups,omitempty"` // Results grouped by feature (GroupByFeature)
 	Symbols        []SymbolResponse         `json:"symbols,omitempty"`
 	Total          int                      `json:"total"`
 	TotalSymbols   int                      `json:"total_symbols,omitempty"`
 	Answer         string                   `json:"answer,omitempty"`
+	Citations      []AskCitation            `json:"citations,omitempty"` // Knowledge nodes the answer was grounded on
 	Warning        string                   `json:"warning,omitempty"`
 }
 
+// AskCitation identifies a knowledge node that a generated answer drew from,
+// so users can verify the answer against its sources.
+type AskCitation struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Summary string `json:"summary"`
+}
+
+// AskFeatureGroup lists the results associated with one feature node, either
+// the feature itself or nodes linked to it by a graph edge.
+type AskFeatureGroup struct {
+	FeatureID string   `json:"feature_id"`
+	Feature   string   `json:"feature"` // Feature summary
+	NodeIDs   []string `json:"node_ids"`
+}
+
 // AskOptions configures the behavior of an ask query.
 type AskOptions struct {
 	Limit          int       // Maximum number of knowledge results (default: 5)
@@ -59,12 +80,56 @@ type AskOptions struct {
 	DisableVector  bool      // Disable vector search (FTS-only, no embeddings)
 	DisableRerank  bool      // Disable reranking (skip TEI reranker)
 	StreamWriter   io.Writer // If set, stream RAG answer tokens to this writer
+	RecordAccess   bool      // Count the returned results toward their access boost (user-facing recall only)
+	Debug          bool      // Attach a MatchReason with the component scores to each result
+
+	// Relationship expansion: after search, follow knowledge graph edges one hop out
+	ExpandRelated bool   // Include nodes directly related to each result
+	Relation      string // Only follow edges of this type (e.g. "depends_on"); empty = all
+
+	// GroupByFeature groups results under the feature node each is linked to
+	GroupByFeature bool
+
+	// Result filters, applied after ranking; a node must match one of each
+	Types []string // Only return nodes of these types (e.g. "decision"); empty = all
+	Tags  []string // Only return nodes carrying at least one of these tags; empty = all
 
 	// Workspace filtering for monorepo support
 	Workspace   string // Filter by workspace ('root' for global, or service name like 'osprey')
 	IncludeRoot bool   // When Workspace is set, also include 'root' workspace nodes (default: true)
 }
 
+// RecallOptions holds the flags of the recall command.
+type RecallOptions struct {
+	Limit  int      // Maximum number of knowledge results
+	Types  []string // Only return nodes of these types; matched case-insensitively
+	Tags   []string // Only return nodes carrying at least one of these tags
+	Answer bool     // Generate a RAG answer from the results
+}
+
+// AskOptions converts recall flags to ask options: knowledge nodes only, and
+// the returned results count toward their access boost.
+// Returns an error for an unknown node type.
+func (o RecallOptions) AskOptions() (AskOptions, error) {
+	validTypes := memory.AllNodeTypes()
+	types := make([]string, len(o.Types))
+	for i, t := range o.Types {
+		types[i] = strings.ToLower(strings.TrimSpace(t))
+		if !slices.Contains(validTypes, types[i]) {
+			return AskOptions{}, fmt.Errorf("unknown node type %q (valid: %s)", t, strings.Join(validTypes, ", "))
+		}
+	}
+
+	opts := DefaultAskOptions()
+	opts.Limit = o.Limit
+	opts.IncludeSymbols = false
+	opts.GenerateAnswer = o.Answer
+	opts.Types = types
+	opts.Tags = o.Tags
+	opts.RecordAccess = true
+	return opts, nil
+}
+
 // DefaultAskOptions returns sensible defaults for ask queries.
 func DefaultAskOptions() AskOptions {
 	return AskOptions{
@@ -259,6 +324,12 @@ func (a *AskApp) Query(ctx context.Context, query string, opts AskOptions) (*Ask
 
 	// 3. Execute knowledge search (hybrid + rerank + graph expansion)
 	// Use workspace-aware search if workspace filter is specified
+	// Type/tag filters drop ranked results, so over-fetch to still fill the limit
+	filtered := len(opts.Types) > 0 || len(opts.Tags) > 0
+	searchLimit := opts.Limit
+	if filtered {
+		searchLimit = opts.Limit * 4
+	}
 	var scored []knowledge.ScoredNode
 	var searchErr error
 	if opts.Workspace != "" {
@@ -266,18 +337,47 @@ func (a *AskApp) Query(ctx context.Context, query string, opts AskOptions) (*Ask
 			Workspace:   opts.Workspace,
 			IncludeRoot: opts.IncludeRoot,
 		}
-		scored, searchErr = ks.SearchWithFilter(ctx, searchQuery, opts.Limit, filter)
+		scored, searchErr = ks.SearchWithFilter(ctx, searchQuery, searchLimit, filter)
 	} else {
-		scored, searchErr = ks.Search(ctx, searchQuery, opts.Limit)
+		scored, searchErr = ks.Search(ctx, searchQuery, searchLimit)
 	}
 	if searchErr != nil {
 		return nil, fmt.Errorf("search failed: %w", searchErr)
 	}
+	if filtered {
+		if scored, searchErr = a.filterScored(scored, opts.Types, opts.Tags); searchErr != nil {
+			return nil, searchErr
+		}
+		if len(scored) > opts.Limit {
+			scored = scored[:opts.Limit]
+		}
+	}
 
 	// 4. Convert results to response format (strips embeddings)
 	results := make([]knowledge.NodeResponse, 0, len(scored))
 	for _, sn := range scored {
-		results = append(results, knowledge.ScoredNodeToResponse(sn))
+		resp := knowledge.ScoredNodeToResponse(sn)
+		if opts.Debug {
+			resp.MatchReason = sn.MatchReason
+		}
+		results = append(results, resp)
+	}
+	if opts.RecordAccess {
+		ids := make([]string, len(results))
+		for i, r := range results {
+			ids[i] = r.ID
+		}
+		ks.RecordAccess(ids)
+	}
+
+	// 4b. Expand to directly related nodes (one hop along graph edges)
+	var related []knowledge.NodeResponse
+	if opts.ExpandRelated {
+		related = a.expandRelated(ctx, ks, results, opts.Relation)
+	}
+	var featureGroups []AskFeatureGroup
+	if opts.GroupByFeature {
+		featureGroups = groupByFeature(ctx, ks, results)
 	}
 
 	// 5. Search for code symbols (if enabled and database available)
@@ -288,6 +388,7 @@ func (a *AskApp) Query(ctx context.Context, query string, opts AskOptions) (*Ask
 
 	// 6. Generate RAG answer if requested (Code-Based RAG)
 	var answer string
+	var citations []AskCitation
 	if opts.GenerateAnswer {
 		// Fetch actual source code for symbols to ground the answer
 		// Use same search as UI symbols to ensure consistency (what you see = what RAG uses)
@@ -308,6 +409,7 @@ func (a *AskApp) Query(ctx context.Context, query string, opts AskOptions) (*Ask
 			warnings = append(warnings, fmt.Sprintf("Answer unavailable: %v", err))
 		} else {
 			answer = ans
+			citations = answerCitations(scored)
 		}
 	}
 
@@ -321,14 +423,127 @@ func (a *AskApp) Query(ctx context.Context, query string, opts AskOptions) (*Ask
 		RewrittenQuery: rewrittenQuery,
 		Pipeline:       pipeline,
 		Results:        results,
+		Related:        related,
+		FeatureGroups:  featureGroups,
 		Symbols:        symbols,
 		Total:          len(results),
 		TotalSymbols:   len(symbols),
 		Answer:         answer,
+		Citations:      citations,
 		Warning:        strings.Join(warnings, " "),
 	}, nil
 }
 
+// answerCitations lists the knowledge nodes given to the answer prompt, in
+// ranked order.
+func answerCitations(nodes []knowledge.ScoredNode) []AskCitation {
+	citations := make([]AskCitation, 0, len(nodes))
+	for _, sn := range nodes {
+		citations = append(citations, AskCitation{ID: sn.Node.ID, Type: sn.Node.Type, Summary: sn.Node.Summary})
+	}
+	return citations
+}
+
+// filterScored keeps results whose type is one of types and that carry at
+// least one of tags. An empty filter matches every node.
+func (a *AskApp) filterScored(scored []knowledge.ScoredNode, types, tags []string) ([]knowledge.ScoredNode, error) {
+	var tagged map[string]bool
+	if len(tags) > 0 {
+		tagged = make(map[string]bool)
+		for _, tag := range tags {
+			tag = memory.NormalizeTag(tag)
+			if tag == "" {
+				continue
+			}
+			nodes, err := a.ctx.Repo.ListNodesByTag(tag)
+			if err != nil {
+				return nil, fmt.Errorf("list nodes tagged %q: %w", tag, err)
+			}
+			for _, n := range nodes {
+				tagged[n.ID] = true
+			}
+		}
+	}
+
+	kept := make([]knowledge.ScoredNode, 0, len(scored))
+	for _, sn := range scored {
+		if sn.Node == nil {
+			continue
+		}
+		if len(types) > 0 && !slices.Contains(types, sn.Node.Type) {
+			continue
+		}
+		if tagged != nil && !tagged[sn.Node.ID] {
+			continue
+		}
+		kept = append(kept, sn)
+	}
+	return kept, nil
+}
+
+// groupByFeature assigns each result to a feature: a feature result to
+// itself, any other result to the first feature it has an edge to. Results
+// with no linked feature are left out of the groups. Groups are ordered by
+// their first result.
+func groupByFeature(ctx context.Context, ks *knowledge.Service, results []knowledge.NodeResponse) []AskFeatureGroup {
+	var groups []AskFeatureGroup
+	index := make(map[string]int)
+	add := func(featureID, feature, nodeID string) {
+		i, ok := index[featureID]
+		if !ok {
+			i = len(groups)
+			index[featureID] = i
+			groups = append(groups, AskFeatureGroup{FeatureID: featureID, Feature: feature})
+		}
+		groups[i].NodeIDs = append(groups[i].NodeIDs, nodeID)
+	}
+
+	for _, r := range results {
+		if r.Type == memory.NodeTypeFeature {
+			add(r.ID, r.Summary, r.ID)
+			continue
+		}
+		nodes, err := ks.Related(ctx, r.ID, "")
+		if err != nil {
+			slog.Debug("group by feature failed", "nodeID", r.ID, "error", err)
+			continue
+		}
+		for _, n := range nodes {
+			if n.Type == memory.NodeTypeFeature {
+				add(n.ID, n.Summary, r.ID)
+				break
+			}
+		}
+	}
+	return groups
+}
+
+// expandRelated collects the nodes one edge away from the search results,
+// skipping nodes already present in results.
+func (a *AskApp) expandRelated(ctx context.Context, ks *knowledge.Service, results []knowledge.NodeResponse, relation string) []knowledge.NodeResponse {
+	seen := make(map[string]bool, len(results))
+	for _, r := range results {
+		seen[r.ID] = true
+	}
+
+	var related []knowledge.NodeResponse
+	for _, r := range results {
+		nodes, err := ks.Related(ctx, r.ID, relation)
+		if err != nil {
+			slog.Debug("expand related failed", "nodeID", r.ID, "error", err)
+			continue
+		}
+		for _, n := range nodes {
+			if seen[n.ID] {
+				continue
+			}
+			seen[n.ID] = true
+			related = append(related, n)
+		}
+	}
+	return related
+}
+
 // searchSymbols searches the code intelligence index for matching symbols.
 // It prioritizes public symbols over private ones.
 func (a *AskApp) searchSymbols(ctx context.Context, query string, limit int) []SymbolResponse {
@@ -344,7 +559,7 @@ func (a *AskApp) searchSymbols(ctx context.Context, query string, limit int) []S
 
 	// Create codeintel repository and search
 	codeRepo := codeintel.NewRepository(db)
-	symbols, err := codeRepo.SearchSymbolsFTS(ctx, query, limit*2) // Get extra for sorting
+	symbols, err := codeRepo.SearchSymbolsFTS(ctx, query, limit*2, codeintel.SymbolFilter{}) // Get extra for sorting
 	if err != nil {
 		return nil // Silent failure - symbols are supplementary
 	}
@@ -409,7 +624,7 @@ func (a *AskApp) getRawSymbols(ctx context.Context, query string, limit int) []c
 	}
 
 	codeRepo := codeintel.NewRepository(db)
-	symbols, err := codeRepo.SearchSymbolsFTS(ctx, query, limit)
+	symbols, err := codeRepo.SearchSymbolsFTS(ctx, query, limit, codeintel.SymbolFilter{})
 	if err != nil {
 		return nil
 	}
@@ -427,7 +642,7 @@ func (a *AskApp) generateRAGAnswer(ctx context.Context, query string, nodes []kn
 	if len(nodes) > 0 {
 		contextParts = append(contextParts, "## Project Knowledge\n")
 		for _, sn := range nodes {
-			nodeContext := fmt.Sprintf("### [%s] %s\n%s", sn.Node.Type, sn.Node.Summary, sn.Node.Text())
+			nodeContext := fmt.Sprintf("### [%s] %s (source: %s)\n%s", sn.Node.Type, sn.Node.Summary, sn.Node.ID, sn.Node.Text())
 			contextParts = append(contextParts, nodeContext)
 		}
 	}
@@ -460,6 +675,7 @@ The context includes project documentation, architectural decisions, constraints
 Guidelines:
 - Structure your answer clearly with sections when the question is broad (e.g., architecture overviews)
 - When referencing code, cite the file and line numbers
+- When using project knowledge, cite its source id in brackets, e.g. [n-abc123]
 - Include the "why" behind decisions, not just the "what"
 - Mention relevant constraints that affect the answer
 - Be thorough but avoid repeating information
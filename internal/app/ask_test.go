@@ -0,0 +1,272 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+func TestAskApp_AnswerCitesRetrievedNodes(t *testing.T) {
+	var prompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompt = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"1","object":"chat.completion","model":"test","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Sessions live in Redis [n-sessions]."}}]}`)
+	}))
+	defer server.Close()
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	repo := memory.NewRepository(store, nil)
+
+	// Unrelated nodes give BM25 a corpus to score "sessions" against
+	nodes := []*memory.Node{{
+		ID:      "n-sessions",
+		Type:    memory.NodeTypeDecision,
+		Summary: "Sessions stored in Redis",
+		Content: "Login sessions are stored in Redis with a 24h TTL.",
+	}}
+	for i, topic := range []string{"Logging uses slog", "Config loaded from YAML", "Migrations run at startup", "Errors wrapped with context", "CLI built on cobra", "Tests use temp dirs", "Plans are stored as DAGs", "Hooks run on session start", "Embeddings are optional"} {
+		nodes = append(nodes, &memory.Node{ID: fmt.Sprintf("n-filler-%d", i), Type: memory.NodeTypePattern, Summary: topic, Content: topic})
+	}
+	for _, n := range nodes {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	cfg := llm.Config{Provider: llm.ProviderOpenAI, Model: "test", APIKey: "test", BaseURL: server.URL}
+	askApp := NewAskApp(&Context{Repo: repo, LLMCfg: cfg})
+	opts := DefaultAskOptions()
+	opts.GenerateAnswer = true
+	opts.IncludeSymbols = false
+	opts.NoRewrite = true
+	opts.DisableVector = true
+	opts.DisableRerank = true
+
+	result, err := askApp.Query(context.Background(), "sessions", opts)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if result.Answer == "" {
+		t.Fatalf("expected an answer, warning: %s", result.Warning)
+	}
+	if len(result.Results) == 0 || len(result.Citations) != len(result.Results) {
+		t.Fatalf("citations = %v, want one per result %v", result.Citations, result.Results)
+	}
+	for i, c := range result.Citations {
+		if c.ID != result.Results[i].ID || c.Summary != result.Results[i].Summary {
+			t.Errorf("citation %d = %+v, want result %s", i, c, result.Results[i].ID)
+		}
+	}
+	if !strings.Contains(prompt, "source: n-sessions") {
+		t.Errorf("prompt should label knowledge with its source id:\n%s", prompt)
+	}
+
+	// Without an answer there is nothing to cite
+	opts.GenerateAnswer = false
+	result, err = askApp.Query(context.Background(), "sessions", opts)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(result.Citations) != 0 {
+		t.Errorf("expected no citations without an answer, got %v", result.Citations)
+	}
+}
+
+func TestRecallOptions_AnswerFlag(t *testing.T) {
+	var answered bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		content := "sessions" // Query rewrite keeps the query as is
+		if !strings.Contains(string(body), "improving a search query") {
+			answered = true
+			content = "Sessions live in Redis [n-sessions]."
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"id":"1","object":"chat.completion","model":"test","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":%q}}]}`, content)
+	}))
+	defer server.Close()
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	repo := memory.NewRepository(store, nil)
+	nodes := []*memory.Node{
+		{ID: "n-sessions", Type: memory.NodeTypeDecision, Summary: "Sessions stored in Redis", Content: "Login sessions are stored in Redis with a 24h TTL."},
+		{ID: "n-session-hooks", Type: memory.NodeTypePattern, Summary: "Hooks run on session start", Content: "Hooks run on session start"},
+	}
+	for i, topic := range []string{"Logging uses slog", "Config loaded from YAML", "Migrations run at startup", "Errors wrapped with context", "CLI built on cobra", "Tests use temp dirs", "Plans are stored as DAGs", "Embeddings are optional"} {
+		nodes = append(nodes, &memory.Node{ID: fmt.Sprintf("n-filler-%d", i), Type: memory.NodeTypePattern, Summary: topic, Content: topic})
+	}
+	for _, n := range nodes {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	if _, err := (RecallOptions{Types: []string{"decisions"}}).AskOptions(); err == nil {
+		t.Error("expected an error for an unknown node type")
+	}
+
+	cfg := llm.Config{Provider: llm.ProviderOpenAI, Model: "test", APIKey: "test", BaseURL: server.URL}
+	askApp := NewAskApp(&Context{Repo: repo, LLMCfg: cfg})
+	recall := func(flags RecallOptions) *AskResult {
+		t.Helper()
+		opts, err := flags.AskOptions()
+		if err != nil {
+			t.Fatalf("AskOptions: %v", err)
+		}
+		result, err := askApp.Query(context.Background(), "sessions", opts)
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		return result
+	}
+
+	result := recall(RecallOptions{Limit: 5, Types: []string{" Decision "}})
+	if answered || result.Answer != "" {
+		t.Errorf("answer generated without --answer: %q", result.Answer)
+	}
+	if len(result.Results) != 1 || result.Results[0].ID != "n-sessions" || len(result.Symbols) != 0 {
+		t.Fatalf("results = %v, symbols = %v; want only n-sessions", result.Results, result.Symbols)
+	}
+
+	result = recall(RecallOptions{Limit: 5, Types: []string{"decision"}, Answer: true})
+	if !answered || !strings.Contains(result.Answer, "[n-sessions]") {
+		t.Errorf("--answer should generate an answer, got %q (warning: %s)", result.Answer, result.Warning)
+	}
+	if len(result.Citations) != 1 || result.Citations[0].ID != "n-sessions" {
+		t.Errorf("citations = %v, want [n-sessions]", result.Citations)
+	}
+}
+
+func TestAskApp_QueryFiltersTypesAndTags(t *testing.T) {
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	repo := memory.NewRepository(store, nil)
+
+	nodes := []*memory.Node{
+		{ID: "n-decision", Type: memory.NodeTypeDecision, Summary: "Cache sessions in Redis", Content: "Sessions cache lives in Redis.", Tags: []string{"storage"}},
+		{ID: "n-pattern", Type: memory.NodeTypePattern, Summary: "Cache keys are namespaced", Content: "Every cache key is prefixed by service.", Tags: []string{"naming"}},
+		{ID: "n-constraint", Type: memory.NodeTypeConstraint, Summary: "Cache must expire", Content: "No cache entry may live forever.", Tags: []string{"storage"}},
+	}
+	// Unrelated nodes keep "cache" rare enough for BM25 to score it
+	for i, topic := range []string{"Logging uses slog", "Config loaded from YAML", "Migrations run at startup", "Errors wrapped with context", "CLI built on cobra", "Tests use temp dirs", "Plans are stored as DAGs", "Hooks run on session start", "Embeddings are optional"} {
+		nodes = append(nodes, &memory.Node{ID: fmt.Sprintf("n-filler-%d", i), Type: memory.NodeTypePattern, Summary: topic, Content: topic})
+	}
+	for _, n := range nodes {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	askApp := NewAskApp(&Context{Repo: repo})
+	query := func(types, tags []string) []string {
+		opts := DefaultAskOptions()
+		opts.IncludeSymbols = false
+		opts.NoRewrite = true
+		opts.DisableVector = true
+		opts.DisableRerank = true
+		opts.Types = types
+		opts.Tags = tags
+		result, err := askApp.Query(context.Background(), "cache", opts)
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		var ids []string
+		for _, r := range result.Results {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	if ids := query([]string{memory.NodeTypePattern}, nil); len(ids) != 1 || ids[0] != "n-pattern" {
+		t.Errorf("types=pattern returned %v, want [n-pattern]", ids)
+	}
+	ids := query(nil, []string{"Storage"})
+	if len(ids) != 2 || !slices.Contains(ids, "n-decision") || !slices.Contains(ids, "n-constraint") {
+		t.Errorf("tags=storage returned %v, want n-decision and n-constraint", ids)
+	}
+	if ids := query([]string{memory.NodeTypeDecision}, []string{"storage"}); len(ids) != 1 || ids[0] != "n-decision" {
+		t.Errorf("types=decision tags=storage returned %v, want [n-decision]", ids)
+	}
+}
+
+func TestAskApp_QueryRecordsAccessForReturnedResults(t *testing.T) {
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	repo := memory.NewRepository(store, nil)
+
+	nodes := []*memory.Node{
+		{ID: "n-decision", Type: memory.NodeTypeDecision, Summary: "Cache sessions in Redis", Content: "Sessions cache lives in Redis."},
+		{ID: "n-pattern", Type: memory.NodeTypePattern, Summary: "Cache keys are namespaced", Content: "Every cache key is prefixed by service."},
+	}
+	for i, topic := range []string{"Logging uses slog", "Config loaded from YAML", "Migrations run at startup", "Errors wrapped with context", "CLI built on cobra", "Tests use temp dirs", "Plans are stored as DAGs", "Hooks run on session start", "Embeddings are optional"} {
+		nodes = append(nodes, &memory.Node{ID: fmt.Sprintf("n-filler-%d", i), Type: memory.NodeTypePattern, Summary: topic, Content: topic})
+	}
+	for _, n := range nodes {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	askApp := NewAskApp(&Context{Repo: repo})
+	opts := DefaultAskOptions()
+	opts.IncludeSymbols = false
+	opts.NoRewrite = true
+	opts.DisableVector = true
+	opts.DisableRerank = true
+	opts.Types = []string{memory.NodeTypeDecision} // n-pattern is fetched as a candidate, then filtered out
+
+	// Context gathering leaves RecordAccess off
+	result, err := askApp.Query(context.Background(), "cache", opts)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(result.Results) != 1 || result.Results[0].MatchReason != nil {
+		t.Fatalf("results = %+v, want one without a match reason outside debug", result.Results)
+	}
+	opts.RecordAccess = true
+	opts.Debug = true
+	result, err = askApp.Query(context.Background(), "cache", opts)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(result.Results) != 1 || result.Results[0].ID != "n-decision" {
+		t.Fatalf("results = %v, want [n-decision]", result.Results)
+	}
+	if m := result.Results[0].MatchReason; m == nil || m.Via != "fts" || m.FTSScore <= 0 || m.FTSWeight != 1 || m.VectorScore != 0 {
+		t.Errorf("match reason = %+v, want an FTS-only match with its score", m)
+	}
+
+	for id, want := range map[string]int{"n-decision": 1, "n-pattern": 0} {
+		n, err := repo.GetNode(id)
+		if err != nil {
+			t.Fatalf("GetNode: %v", err)
+		}
+		if n.AccessCount != want {
+			t.Errorf("%s access count = %d, want %d", id, n.AccessCount, want)
+		}
+	}
+}
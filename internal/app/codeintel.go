@@ -3,11 +3,17 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"sort"
 
 	"github.com/josephgoksu/TaskWing/internal/codeintel"
 )
 
+// ErrSymbolNotFound is returned when a symbol lookup matches nothing.
+var ErrSymbolNotFound = errors.New("symbol not found")
+
 // CodeIntelApp provides code intelligence operations through the app layer.
 // This follows the same pattern as AskApp, TaskApp, etc.
 type CodeIntelApp struct {
@@ -40,12 +46,33 @@ type SearchCodeResult struct {
 
 // GetCallersResult is the result of a get_callers operation.
 type GetCallersResult struct {
-	Success bool               `json:"success"`
-	Symbol  *codeintel.Symbol  `json:"symbol,omitempty"`  // The target symbol
-	Callers []codeintel.Symbol `json:"callers,omitempty"` // Who calls this symbol
-	Callees []codeintel.Symbol `json:"callees,omitempty"` // Who this symbol calls
-	Count   int                `json:"count"`
-	Message string             `json:"message,omitempty"`
+	Success  bool               `json:"success"`
+	Symbol   *codeintel.Symbol  `json:"symbol,omitempty"`  // The target symbol
+	Callers  []codeintel.Symbol `json:"callers,omitempty"` // Who calls this symbol
+	Callees  []codeintel.Symbol `json:"callees,omitempty"` // Who this symbol calls
+	Count    int                `json:"count"`
+	Message  string             `json:"message,omitempty"`
+	NotFound bool               `json:"not_found,omitempty"` // The target symbol does not exist
+}
+
+// GetUsagesResult is the result of a get_usages operation.
+type GetUsagesResult struct {
+	Success  bool                    `json:"success"`
+	Symbol   *codeintel.Symbol       `json:"symbol,omitempty"` // The target symbol
+	Usages   []codeintel.SymbolUsage `json:"usages,omitempty"` // Callers and reference sites
+	Count    int                     `json:"count"`
+	Message  string                  `json:"message,omitempty"`
+	NotFound bool                    `json:"not_found,omitempty"` // The target symbol does not exist
+}
+
+// GetImplementationsResult is the result of a get_implementations operation.
+type GetImplementationsResult struct {
+	Success         bool               `json:"success"`
+	Interface       *codeintel.Symbol  `json:"interface,omitempty"`       // The target interface
+	Implementations []codeintel.Symbol `json:"implementations,omitempty"` // Types implementing it, directly or through embedding
+	Count           int                `json:"count"`
+	Message         string             `json:"message,omitempty"`
+	NotFound        bool               `json:"not_found,omitempty"` // The target interface does not exist
 }
 
 // AnalyzeImpactResult is the result of an analyze_impact operation.
@@ -58,6 +85,31 @@ type AnalyzeImpactResult struct {
 	MaxDepth      int                        `json:"max_depth"`
 	ByDepth       map[int][]codeintel.Symbol `json:"by_depth,omitempty"` // Grouped by depth
 	Message       string                     `json:"message,omitempty"`
+	NotFound      bool                       `json:"not_found,omitempty"` // The target symbol does not exist
+}
+
+// ChangedSymbolsResult is the result of a changed-symbols query between two git refs.
+type ChangedSymbolsResult struct {
+	Success bool                      `json:"success"`
+	Base    string                    `json:"base"`
+	Head    string                    `json:"head"`
+	Symbols []codeintel.ChangedSymbol `json:"symbols,omitempty"`
+	Count   int                       `json:"count"`
+	Message string                    `json:"message,omitempty"`
+}
+
+// FileImportsResult is the result of listing a file's imports.
+type FileImportsResult struct {
+	Success bool                   `json:"success"`
+	Imports *codeintel.FileImports `json:"imports,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// ModuleTreeResult is the result of listing a directory's symbols by file.
+type ModuleTreeResult struct {
+	Success bool                  `json:"success"`
+	Tree    *codeintel.ModuleTree `json:"tree,omitempty"`
+	Message string                `json:"message,omitempty"`
 }
 
 // IndexStatsResult is the result of getting index statistics.
@@ -69,6 +121,13 @@ type IndexStatsResult struct {
 	Message        string `json:"message,omitempty"`
 }
 
+// SimplifyScope lists what a simplification could break.
+type SimplifyScope struct {
+	Targets  []codeintel.Symbol `json:"targets"`            // Symbols being simplified
+	Callers  []codeintel.Symbol `json:"callers"`            // Outside callers to re-test
+	Warnings []string           `json:"warnings,omitempty"` // High fan-in targets
+}
+
 // === Options Types ===
 
 // FindSymbolOptions configures the find_symbol operation.
@@ -85,6 +144,22 @@ type SearchCodeOptions struct {
 	Limit    int                  `json:"limit,omitempty"`     // Max results (default 20)
 	Kind     codeintel.SymbolKind `json:"kind,omitempty"`      // Filter by symbol kind
 	FilePath string               `json:"file_path,omitempty"` // Filter by file path
+
+	// BySignature matches Query against symbol signatures as comma-separated
+	// types in order (e.g. "context.Context, string") instead of searching names
+	BySignature bool `json:"by_signature,omitempty"`
+
+	// ExcludePrivate drops unexported symbols from the results
+	ExcludePrivate bool `json:"exclude_private,omitempty"`
+
+	// MinScore drops hybrid search results scoring below it (0 = service default)
+	MinScore float32 `json:"min_score,omitempty"`
+
+	// Debug attaches a match reason with component scores to hybrid search results
+	Debug bool `json:"debug,omitempty"`
+
+	// Language keeps only symbols of that language (e.g. "go", "typescript")
+	Language string `json:"language,omitempty"`
 }
 
 // GetCallersOptions configures the get_callers operation.
@@ -94,11 +169,40 @@ type GetCallersOptions struct {
 	Direction  string `json:"direction,omitempty"`   // "callers", "callees", or "both" (default: "both")
 }
 
+// GetUsagesOptions configures the get_usages operation.
+type GetUsagesOptions struct {
+	SymbolID   uint32 `json:"symbol_id,omitempty"`   // Symbol ID to get usages for
+	SymbolName string `json:"symbol_name,omitempty"` // Symbol name (if ID not provided)
+}
+
+// GetImplementationsOptions configures the get_implementations operation.
+type GetImplementationsOptions struct {
+	SymbolID   uint32 `json:"symbol_id,omitempty"`   // Interface symbol ID
+	SymbolName string `json:"symbol_name,omitempty"` // Interface name (if ID not provided)
+	MaxDepth   int    `json:"max_depth,omitempty"`   // Embedding hops to follow (default and cap from QueryConfig)
+}
+
 // AnalyzeImpactOptions configures the analyze_impact operation.
 type AnalyzeImpactOptions struct {
 	SymbolID   uint32 `json:"symbol_id,omitempty"`   // Symbol ID to analyze
 	SymbolName string `json:"symbol_name,omitempty"` // Symbol name (if ID not provided)
 	MaxDepth   int    `json:"max_depth,omitempty"`   // Max recursion depth (default 5)
+
+	// ExcludeVendored leaves vendored, third-party, and generated code out of the analysis
+	ExcludeVendored bool `json:"exclude_vendored,omitempty"`
+}
+
+// ChangedSymbolsOptions configures the changed-symbols query.
+type ChangedSymbolsOptions struct {
+	Base string `json:"base,omitempty"` // Base ref (default "main")
+	Head string `json:"head,omitempty"` // Head ref (default "HEAD")
+}
+
+// SimplifyScopeOptions identifies the code being simplified.
+type SimplifyScopeOptions struct {
+	FilePath string `json:"file_path,omitempty"` // File being simplified
+	Query    string `json:"query,omitempty"`     // Symbol name being simplified (optional)
+	SymbolID uint32 `json:"symbol_id,omitempty"` // Direct symbol ID (optional, overrides Query)
 }
 
 // === App Methods ===
@@ -220,15 +324,20 @@ func (a *CodeIntelApp) SearchCode(ctx context.Context, opts SearchCodeOptions) (
 
 	var results []codeintel.SymbolSearchResult
 
-	if opts.Kind != "" {
-		// Filter by kind
-		results, err = qs.SearchByKind(ctx, opts.Query, opts.Kind, limit)
-	} else if opts.FilePath != "" {
-		// Filter by file
-		results, err = qs.SearchByFile(ctx, opts.Query, opts.FilePath, limit)
+	searchOpts := codeintel.SearchOptions{
+		ExcludePrivate: opts.ExcludePrivate,
+		Kind:           opts.Kind,
+		FilePath:       opts.FilePath,
+		MinScore:       opts.MinScore,
+		Debug:          opts.Debug,
+		Language:       opts.Language,
+	}
+	if opts.BySignature {
+		// Match by signature shape
+		results, err = qs.SearchBySignature(ctx, opts.Query, limit, searchOpts)
 	} else {
-		// Full hybrid search
-		results, err = qs.HybridSearch(ctx, opts.Query, limit)
+		// Hybrid search, filtered by kind, file and language when set
+		results, err = qs.HybridSearchWithOptions(ctx, opts.Query, limit, searchOpts)
 	}
 
 	if err != nil {
@@ -266,8 +375,9 @@ func (a *CodeIntelApp) GetCallers(ctx context.Context, opts GetCallersOptions) (
 		symbols, err := qs.FindSymbolByName(ctx, opts.SymbolName)
 		if err != nil || len(symbols) == 0 {
 			return &GetCallersResult{
-				Success: false,
-				Message: fmt.Sprintf("symbol '%s' not found", opts.SymbolName),
+				Success:  false,
+				Message:  fmt.Sprintf("symbol '%s' not found", opts.SymbolName),
+				NotFound: true,
 			}, nil
 		}
 		symbolID = symbols[0].ID
@@ -282,8 +392,9 @@ func (a *CodeIntelApp) GetCallers(ctx context.Context, opts GetCallersOptions) (
 	symbol, err := qs.FindSymbol(ctx, symbolID)
 	if err != nil {
 		return &GetCallersResult{
-			Success: false,
-			Message: fmt.Sprintf("symbol not found: %v", err),
+			Success:  false,
+			Message:  fmt.Sprintf("symbol not found: %v", err),
+			NotFound: true,
 		}, nil
 	}
 
@@ -317,6 +428,134 @@ func (a *CodeIntelApp) GetCallers(ctx context.Context, opts GetCallersOptions) (
 	return result, nil
 }
 
+// GetUsages returns every use of a symbol: the symbols that call it and the
+// sites that reference it without calling it, such as a struct field of its type.
+func (a *CodeIntelApp) GetUsages(ctx context.Context, opts GetUsagesOptions) (*GetUsagesResult, error) {
+	qs, err := a.getQueryService()
+	if err != nil {
+		return &GetUsagesResult{
+			Success: false,
+			Message: fmt.Sprintf("failed to initialize query service: %v", err),
+		}, nil
+	}
+
+	// Resolve symbol ID
+	var symbolID uint32
+	if opts.SymbolID > 0 {
+		symbolID = opts.SymbolID
+	} else if opts.SymbolName != "" {
+		// Find symbol by name (use first match)
+		symbols, err := qs.FindSymbolByName(ctx, opts.SymbolName)
+		if err != nil || len(symbols) == 0 {
+			return &GetUsagesResult{
+				Success:  false,
+				Message:  fmt.Sprintf("symbol '%s' not found", opts.SymbolName),
+				NotFound: true,
+			}, nil
+		}
+		symbolID = symbols[0].ID
+	} else {
+		return &GetUsagesResult{
+			Success: false,
+			Message: "symbol_id or symbol_name is required",
+		}, nil
+	}
+
+	symbol, err := qs.FindSymbol(ctx, symbolID)
+	if err != nil {
+		return &GetUsagesResult{
+			Success:  false,
+			Message:  fmt.Sprintf("symbol not found: %v", err),
+			NotFound: true,
+		}, nil
+	}
+
+	usages, err := qs.GetUsages(ctx, symbolID)
+	if err != nil {
+		return &GetUsagesResult{
+			Success: false,
+			Message: fmt.Sprintf("failed to get usages: %v", err),
+		}, nil
+	}
+
+	return &GetUsagesResult{
+		Success: true,
+		Symbol:  symbol,
+		Usages:  usages,
+		Count:   len(usages),
+	}, nil
+}
+
+// GetImplementations returns the types implementing an interface, including
+// implementations of interfaces that embed it and types embedding an
+// implementation.
+func (a *CodeIntelApp) GetImplementations(ctx context.Context, opts GetImplementationsOptions) (*GetImplementationsResult, error) {
+	qs, err := a.getQueryService()
+	if err != nil {
+		return &GetImplementationsResult{
+			Success: false,
+			Message: fmt.Sprintf("failed to initialize query service: %v", err),
+		}, nil
+	}
+
+	// Resolve symbol ID, preferring an interface among same-named symbols
+	var symbolID uint32
+	if opts.SymbolID > 0 {
+		symbolID = opts.SymbolID
+	} else if opts.SymbolName != "" {
+		symbols, err := qs.FindSymbolByName(ctx, opts.SymbolName)
+		if err != nil || len(symbols) == 0 {
+			return &GetImplementationsResult{
+				Success:  false,
+				Message:  fmt.Sprintf("symbol '%s' not found", opts.SymbolName),
+				NotFound: true,
+			}, nil
+		}
+		symbolID = symbols[0].ID
+		for _, s := range symbols {
+			if s.Kind == codeintel.SymbolInterface {
+				symbolID = s.ID
+				break
+			}
+		}
+	} else {
+		return &GetImplementationsResult{
+			Success: false,
+			Message: "symbol_id or symbol_name is required",
+		}, nil
+	}
+
+	symbol, err := qs.FindSymbol(ctx, symbolID)
+	if err != nil {
+		return &GetImplementationsResult{
+			Success:  false,
+			Message:  fmt.Sprintf("symbol not found: %v", err),
+			NotFound: true,
+		}, nil
+	}
+	if symbol.Kind != codeintel.SymbolInterface {
+		return &GetImplementationsResult{
+			Success: false,
+			Message: fmt.Sprintf("'%s' is a %s, not an interface", symbol.Name, symbol.Kind),
+		}, nil
+	}
+
+	impls, err := qs.GetImplementationsTransitive(ctx, symbolID, opts.MaxDepth)
+	if err != nil {
+		return &GetImplementationsResult{
+			Success: false,
+			Message: fmt.Sprintf("failed to get implementations: %v", err),
+		}, nil
+	}
+
+	return &GetImplementationsResult{
+		Success:         true,
+		Interface:       symbol,
+		Implementations: impls,
+		Count:           len(impls),
+	}, nil
+}
+
 // AnalyzeImpact finds all symbols affected by changing a given symbol.
 func (a *CodeIntelApp) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (*AnalyzeImpactResult, error) {
 	qs, err := a.getQueryService()
@@ -336,8 +575,9 @@ func (a *CodeIntelApp) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOpti
 		symbols, err := qs.FindSymbolByName(ctx, opts.SymbolName)
 		if err != nil || len(symbols) == 0 {
 			return &AnalyzeImpactResult{
-				Success: false,
-				Message: fmt.Sprintf("symbol '%s' not found", opts.SymbolName),
+				Success:  false,
+				Message:  fmt.Sprintf("symbol '%s' not found", opts.SymbolName),
+				NotFound: true,
 			}, nil
 		}
 		symbolID = symbols[0].ID
@@ -353,6 +593,10 @@ func (a *CodeIntelApp) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOpti
 		maxDepth = 5
 	}
 
+	if opts.ExcludeVendored {
+		qs.SetImpactExcludePatterns(codeintel.DefaultImpactExcludePatterns())
+	}
+
 	// Run impact analysis
 	analysis, err := qs.AnalyzeImpact(ctx, symbolID, maxDepth)
 	if err != nil {
@@ -373,6 +617,204 @@ func (a *CodeIntelApp) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOpti
 	}, nil
 }
 
+// SymbolsChanged lists the symbols changed between two git refs, with their impact.
+func (a *CodeIntelApp) SymbolsChanged(ctx context.Context, opts ChangedSymbolsOptions) (*ChangedSymbolsResult, error) {
+	base := opts.Base
+	if base == "" {
+		base = "main"
+	}
+	head := opts.Head
+	if head == "" {
+		head = "HEAD"
+	}
+
+	qs, err := a.getQueryService()
+	if err != nil {
+		return &ChangedSymbolsResult{
+			Success: false,
+			Base:    base,
+			Head:    head,
+			Message: fmt.Sprintf("failed to initialize query service: %v", err),
+		}, nil
+	}
+	qs.SetWorkDir(a.ctx.BasePath)
+
+	changed, err := qs.SymbolsChangedBetween(ctx, base, head)
+	if err != nil {
+		return &ChangedSymbolsResult{
+			Success: false,
+			Base:    base,
+			Head:    head,
+			Message: fmt.Sprintf("failed to diff %s..%s: %v", base, head, err),
+		}, nil
+	}
+
+	return &ChangedSymbolsResult{
+		Success: true,
+		Base:    base,
+		Head:    head,
+		Symbols: changed,
+		Count:   len(changed),
+	}, nil
+}
+
+// FileImports lists the imports of an indexed file, split into internal and
+// external (stdlib/third-party) packages.
+func (a *CodeIntelApp) FileImports(ctx context.Context, filePath string) (*FileImportsResult, error) {
+	if filePath == "" {
+		return &FileImportsResult{
+			Success: false,
+			Message: "file_path is required",
+		}, nil
+	}
+
+	qs, err := a.getQueryService()
+	if err != nil {
+		return &FileImportsResult{
+			Success: false,
+			Message: fmt.Sprintf("failed to initialize query service: %v", err),
+		}, nil
+	}
+
+	imports, err := qs.GetFileImports(ctx, filepath.Clean(filePath))
+	if err != nil {
+		return &FileImportsResult{
+			Success: false,
+			Message: fmt.Sprintf("failed to get imports: %v", err),
+		}, nil
+	}
+
+	return &FileImportsResult{
+		Success: true,
+		Imports: imports,
+	}, nil
+}
+
+// ModuleTree lists the symbols of an indexed directory, grouped by file.
+func (a *CodeIntelApp) ModuleTree(ctx context.Context, modulePath string) (*ModuleTreeResult, error) {
+	if modulePath == "" {
+		return &ModuleTreeResult{
+			Success: false,
+			Message: "file_path is required",
+		}, nil
+	}
+
+	qs, err := a.getQueryService()
+	if err != nil {
+		return &ModuleTreeResult{
+			Success: false,
+			Message: fmt.Sprintf("failed to initialize query service: %v", err),
+		}, nil
+	}
+
+	tree, err := qs.GetSymbolsInModule(ctx, modulePath)
+	if err != nil {
+		return &ModuleTreeResult{
+			Success: false,
+			Message: fmt.Sprintf("failed to get module symbols: %v", err),
+		}, nil
+	}
+
+	return &ModuleTreeResult{
+		Success: true,
+		Tree:    tree,
+	}, nil
+}
+
+// SimplifyScope resolves the symbols being simplified and the callers that
+// should be re-tested afterwards. Symbol lookup takes precedence over the whole
+// file: SymbolID, then Query (narrowed to FilePath if set), then every symbol in
+// FilePath. Targets with more than codeintel.DefaultFanInThreshold callers get
+// a warning, since behavior changes there ripple furthest.
+func (a *CodeIntelApp) SimplifyScope(ctx context.Context, opts SimplifyScopeOptions) (*SimplifyScope, error) {
+	qs, err := a.getQueryService()
+	if err != nil {
+		return nil, err
+	}
+
+	path := ""
+	if opts.FilePath != "" {
+		path = filepath.ToSlash(filepath.Clean(opts.FilePath))
+	}
+
+	var targets []codeintel.Symbol
+	switch {
+	case opts.SymbolID > 0:
+		sym, err := qs.FindSymbol(ctx, opts.SymbolID)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, *sym)
+	case opts.Query != "":
+		matches, err := qs.FindSymbolByName(ctx, opts.Query)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if path == "" || m.FilePath == path {
+				targets = append(targets, m)
+			}
+		}
+	case path != "":
+		symbols, err := qs.GetSymbolsInFile(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range symbols {
+			if s.Kind != codeintel.SymbolPackage {
+				targets = append(targets, s)
+			}
+		}
+	}
+
+	isTarget := make(map[uint32]bool, len(targets))
+	for _, t := range targets {
+		isTarget[t.ID] = true
+	}
+
+	scope := &SimplifyScope{Targets: targets}
+	seen := make(map[uint32]bool)
+	for _, t := range targets {
+		callers, err := qs.GetCallers(ctx, t.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get callers of %s: %w", t.Name, err)
+		}
+		if n := countDistinctSymbols(callers); n > codeintel.DefaultFanInThreshold {
+			scope.Warnings = append(scope.Warnings, highFanInWarning(t, n))
+		}
+		for _, c := range callers {
+			if isTarget[c.ID] || seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			scope.Callers = append(scope.Callers, c)
+		}
+	}
+	sort.SliceStable(scope.Callers, func(i, j int) bool {
+		if scope.Callers[i].FilePath != scope.Callers[j].FilePath {
+			return scope.Callers[i].FilePath < scope.Callers[j].FilePath
+		}
+		return scope.Callers[i].StartLine < scope.Callers[j].StartLine
+	})
+
+	return scope, nil
+}
+
+// highFanInWarning describes a symbol with too many callers.
+func highFanInWarning(s codeintel.Symbol, callers int) string {
+	return fmt.Sprintf("`%s` (%s:%d) has %d callers (threshold %d); it may be a god-object worth splitting before further changes",
+		s.Name, s.FilePath, s.StartLine, callers, codeintel.DefaultFanInThreshold)
+}
+
+// countDistinctSymbols counts unique symbol IDs.
+func countDistinctSymbols(symbols []codeintel.Symbol) int {
+	seen := make(map[uint32]bool, len(symbols))
+	for _, s := range symbols {
+		seen[s.ID] = true
+	}
+	return len(seen)
+}
+
 // GetStats returns the current index statistics.
 func (a *CodeIntelApp) GetStats(ctx context.Context) (*IndexStatsResult, error) {
 	qs, err := a.getQueryService()
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+// maxRuleTermWords caps how many words of a rule are treated as the required or forbidden term.
+const maxRuleTermWords = 4
+
+var (
+	// forbiddenRulePattern matches "never use X", "must not call X in Y", "avoid X".
+	forbiddenRulePattern = regexp.MustCompile(`(?i)\b(?:never|must\s+not|do\s+not|don't|should\s+not|avoid)\s+(?:use\s+|using\s+|call\s+|calling\s+)?(.+?)(?:\s+(?:for|when|in|from|inside)\s+(.+))?$`)
+	// requiredRulePattern matches "use X for Y", "must use X when Y".
+	requiredRulePattern = regexp.MustCompile(`(?i)\buse\s+(.+?)\s+(?:for|when|in)\s+(.+)$`)
+
+	nonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// lintStopwords are ignored when deciding whether a task touches a rule's topic.
+var lintStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "all": true, "any": true, "and": true, "or": true,
+	"of": true, "to": true, "on": true, "with": true, "every": true, "each": true,
+}
+
+// constraintRule is a keyword form of a constraint: tasks touching Topic must
+// mention Required, and must never mention Forbidden.
+type constraintRule struct {
+	Text      string
+	Required  string
+	Forbidden string
+	Topic     []string
+}
+
+// isHighSeverityConstraint reports whether a constraint node is strong enough to lint against.
+// Severity is not stored on nodes; ingestion maps it to the confidence score.
+func isHighSeverityConstraint(n memory.Node) bool {
+	if n.VerificationStatus == string(core.VerificationStatusRejected) {
+		return false
+	}
+	cal := core.ActiveConfidenceCalibration()
+	threshold, ok := cal.SeverityScore("high")
+	if !ok {
+		threshold = cal.HighThreshold
+	}
+	return n.ConfidenceScore >= threshold
+}
+
+// parseConstraintRule extracts a keyword rule from a constraint's text.
+// Returns false when the constraint has no recognizable "use X for Y" or
+// "never use X" form.
+func parseConstraintRule(text string) (constraintRule, bool) {
+	text = strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+	sentence := strings.TrimRight(text, ".!")
+
+	if m := forbiddenRulePattern.FindStringSubmatch(sentence); m != nil {
+		term := ruleTerm(m[1])
+		if term == "" {
+			return constraintRule{}, false
+		}
+		return constraintRule{Text: text, Forbidden: term, Topic: topicKeywords(m[2])}, true
+	}
+	if m := requiredRulePattern.FindStringSubmatch(sentence); m != nil {
+		term, topic := ruleTerm(m[1]), topicKeywords(m[2])
+		if term == "" || len(topic) == 0 {
+			return constraintRule{}, false
+		}
+		return constraintRule{Text: text, Required: term, Topic: topic}, true
+	}
+	return constraintRule{}, false
+}
+
+// ruleTerm trims a rule's subject to a short term without leading articles.
+func ruleTerm(s string) string {
+	words := strings.Fields(strings.Trim(s, " ,;:"))
+	for len(words) > 0 && lintStopwords[strings.ToLower(words[0])] {
+		words = words[1:]
+	}
+	if len(words) > maxRuleTermWords {
+		words = words[:maxRuleTermWords]
+	}
+	return strings.Join(words, " ")
+}
+
+// topicKeywords returns the significant, normalized words of a rule's topic.
+func topicKeywords(s string) []string {
+	var keywords []string
+	for _, w := range strings.Fields(strings.ToLower(s)) {
+		w = normalizeLintWord(w)
+		if len(w) >= 3 && !lintStopwords[w] {
+			keywords = append(keywords, w)
+		}
+	}
+	return keywords
+}
+
+// normalizeLintWord strips punctuation and a plural "s" so "reads" matches "read".
+func normalizeLintWord(w string) string {
+	w = nonAlnum.ReplaceAllString(strings.ToLower(w), "")
+	if len(w) > 3 && strings.HasSuffix(w, "s") {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+// mentionsTerm reports whether text names term, ignoring case, spacing, and
+// punctuation so "ReadReplica" matches "read replica".
+func mentionsTerm(text, term string) bool {
+	squash := func(s string) string { return nonAlnum.ReplaceAllString(strings.ToLower(s), "") }
+	t := squash(term)
+	return t != "" && strings.Contains(squash(text), t)
+}
+
+// mentionsTopic reports whether text contains every topic keyword.
+func mentionsTopic(text string, topic []string) bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(text) {
+		words[normalizeLintWord(w)] = true
+	}
+	for _, k := range topic {
+		if !words[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// lintTasksAgainstConstraints returns a warning for each task that appears to
+// violate a high-severity constraint. Matching is keyword based, so warnings
+// are hints for review rather than hard failures.
+func lintTasksAgainstConstraints(tasks []task.Task, constraints []memory.Node) []Warning {
+	var rules []constraintRule
+	for _, n := range constraints {
+		if !isHighSeverityConstraint(n) {
+			continue
+		}
+		text := n.Summary
+		if text == "" {
+			text = n.Content
+		}
+		if rule, ok := parseConstraintRule(text); ok {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var warnings []Warning
+	for i, t := range tasks {
+		text := strings.Join(append([]string{t.Title, t.Description}, t.AcceptanceCriteria...), " ")
+		for _, rule := range rules {
+			if len(rule.Topic) > 0 && !mentionsTopic(text, rule.Topic) {
+				continue
+			}
+			switch {
+			case rule.Forbidden != "" && mentionsTerm(text, rule.Forbidden):
+				warnings = append(warnings, Warning{Category: WarningCategoryConstraint, TaskIndex: i,
+					Message: fmt.Sprintf("constraint_violation: uses %q, but constraint says %q", rule.Forbidden, rule.Text)})
+			case rule.Required != "" && !mentionsTerm(text, rule.Required):
+				warnings = append(warnings, Warning{Category: WarningCategoryConstraint, TaskIndex: i,
+					Message: fmt.Sprintf("constraint_violation: does not mention %q, but constraint says %q", rule.Required, rule.Text)})
+			}
+		}
+	}
+	return warnings
+}
@@ -43,6 +43,9 @@ type ExplainResult struct {
 	// Source context
 	SourceCode []CodeSnippet `json:"source_code,omitempty"`
 
+	// Refactoring signals, such as high fan-in
+	Warnings []string `json:"warnings,omitempty"`
+
 	// Synthesized explanation
 	Explanation string `json:"explanation"`
 }
@@ -68,6 +71,8 @@ type ImpactStats struct {
 type ExplainApp struct {
 	ctx          *Context
 	queryService *codeintel.QueryService
+	// ChatModelFactory creates the model used for narrative explanations.
+	ChatModelFactory func(context.Context, llm.Config) (*llm.CloseableChatModel, error)
 }
 
 // NewExplainApp creates a new explain application service.
@@ -83,8 +88,9 @@ func NewExplainApp(ctx *Context) *ExplainApp {
 	}
 
 	return &ExplainApp{
-		ctx:          ctx,
-		queryService: queryService,
+		ctx:              ctx,
+		queryService:     queryService,
+		ChatModelFactory: llm.NewCloseableChatModel,
 	}
 }
 
@@ -142,6 +148,9 @@ func (a *ExplainApp) Explain(ctx context.Context, req ExplainRequest) (*ExplainR
 		result.ImpactStats.TransitiveDependents = impact.AffectedCount
 		result.ImpactStats.AffectedFiles = impact.AffectedFiles
 	}
+	if callerCount := countDistinctSymbols(callers); callerCount > codeintel.DefaultFanInThreshold {
+		result.Warnings = append(result.Warnings, highFanInWarning(*symbol, callerCount))
+	}
 
 	// 5. Fetch source code context
 	if req.IncludeCode && a.ctx.BasePath != "" {
@@ -193,7 +202,7 @@ func (a *ExplainApp) resolveSymbol(ctx context.Context, req ExplainRequest) (*co
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("symbol not found: %s", req.Query)
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, req.Query)
 	}
 
 	// Return best match
@@ -293,9 +302,12 @@ func (a *ExplainApp) findRelevantKnowledge(ctx context.Context, symbol *codeinte
 
 // generateExplanation uses LLM to synthesize a narrative explanation.
 func (a *ExplainApp) generateExplanation(ctx context.Context, result *ExplainResult, streamWriter io.Writer) (string, error) {
-	prompt := buildExplainPrompt(result)
+	return a.complete(ctx, buildExplainPrompt(result), streamWriter)
+}
 
-	chatModel, err := llm.NewCloseableChatModel(ctx, a.ctx.LLMCfg)
+// complete sends a single prompt to the chat model, streaming if a writer is provided.
+func (a *ExplainApp) complete(ctx context.Context, prompt string, streamWriter io.Writer) (string, error) {
+	chatModel, err := a.ChatModelFactory(ctx, a.ctx.LLMCfg)
 	if err != nil {
 		return "", fmt.Errorf("create chat model: %w", err)
 	}
@@ -386,6 +398,10 @@ func buildExplainPrompt(result *ExplainResult) string {
 		sb.WriteString(fmt.Sprintf("- Affected files: %d\n", result.ImpactStats.AffectedFiles))
 	}
 
+	for _, w := range result.Warnings {
+		sb.WriteString(fmt.Sprintf("- Warning: %s\n", w))
+	}
+
 	// Related decisions
 	if len(result.Decisions) > 0 {
 		sb.WriteString("\n## Related Architectural Decisions\n")
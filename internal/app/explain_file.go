@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/codeintel"
+)
+
+// maxMostCalled caps how many hot symbols are reported per file.
+const maxMostCalled = 5
+
+// ExplainFileRequest configures a whole-file explanation.
+type ExplainFileRequest struct {
+	Path         string    // Project-relative file path
+	StreamWriter io.Writer // For streaming output (optional)
+}
+
+// CalledSymbol is a symbol ranked by how many callers it has.
+type CalledSymbol struct {
+	Symbol      SymbolResponse `json:"symbol"`
+	CallerCount int            `json:"caller_count"`
+}
+
+// FileExplainResult summarizes a file: its symbols, hot spots, imports, and a narrative.
+type FileExplainResult struct {
+	FilePath    string           `json:"file_path"`
+	Language    string           `json:"language,omitempty"`
+	Symbols     []SymbolResponse `json:"symbols"`
+	MostCalled  []CalledSymbol   `json:"most_called,omitempty"`
+	Imports     []string         `json:"imports,omitempty"`
+	Explanation string           `json:"explanation"`
+}
+
+// ExplainFile generates an aggregate explanation for a single file.
+func (a *ExplainApp) ExplainFile(ctx context.Context, req ExplainFileRequest) (*FileExplainResult, error) {
+	if a.queryService == nil {
+		return nil, fmt.Errorf("code intelligence not available (run 'taskwing bootstrap' first)")
+	}
+
+	path := filepath.ToSlash(filepath.Clean(req.Path))
+	outline, err := a.queryService.GetFileOutline(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(outline.Symbols) == 0 {
+		return nil, fmt.Errorf("no indexed symbols in %s (run 'taskwing bootstrap' to index code)", path)
+	}
+
+	result := &FileExplainResult{
+		FilePath: path,
+		Language: outline.Language,
+		Symbols:  make([]SymbolResponse, len(outline.Symbols)),
+	}
+	for i, s := range outline.Symbols {
+		result.Symbols[i] = symbolToResponse(s)
+	}
+
+	result.MostCalled = a.rankByCallers(ctx, outline.Symbols)
+
+	if a.ctx.BasePath != "" {
+		if content, err := os.ReadFile(filepath.Join(a.ctx.BasePath, path)); err == nil {
+			result.Imports = extractImports(path, string(content))
+		}
+	}
+
+	explanation, err := a.complete(ctx, buildExplainFilePrompt(result), req.StreamWriter)
+	if err != nil {
+		// Non-fatal: still return structured data
+		result.Explanation = fmt.Sprintf("(Explanation unavailable: %v)", err)
+	} else {
+		result.Explanation = explanation
+	}
+
+	return result, nil
+}
+
+// rankByCallers returns the functions and methods with the most callers.
+func (a *ExplainApp) rankByCallers(ctx context.Context, symbols []codeintel.Symbol) []CalledSymbol {
+	var ranked []CalledSymbol
+	for _, s := range symbols {
+		if s.Kind != codeintel.SymbolFunction && s.Kind != codeintel.SymbolMethod {
+			continue
+		}
+		callers, err := a.queryService.GetCallers(ctx, s.ID)
+		if err != nil || len(callers) == 0 {
+			continue
+		}
+		ranked = append(ranked, CalledSymbol{Symbol: symbolToResponse(s), CallerCount: len(callers)})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].CallerCount > ranked[j].CallerCount
+	})
+	if len(ranked) > maxMostCalled {
+		ranked = ranked[:maxMostCalled]
+	}
+	return ranked
+}
+
+var (
+	jsImportRegex     = regexp.MustCompile(`(?m)(?:^\s*import\s+(?:[^'"]*\s+from\s+)?|require\()\s*['"]([^'"]+)['"]`)
+	pythonImportRegex = regexp.MustCompile(`(?m)^\s*(?:from\s+([\w.]+)\s+import|import\s+([\w.]+))`)
+	rustUseRegex      = regexp.MustCompile(`(?m)^\s*(?:pub\s+)?use\s+([\w:]+)`)
+)
+
+// extractImports lists the modules a file imports, based on its extension.
+func extractImports(path, content string) []string {
+	var imports []string
+	switch filepath.Ext(path) {
+	case ".go":
+		f, err := parser.ParseFile(token.NewFileSet(), path, content, parser.ImportsOnly)
+		if err != nil {
+			return nil
+		}
+		for _, imp := range f.Imports {
+			if p, err := strconv.Unquote(imp.Path.Value); err == nil {
+				imports = append(imports, p)
+			}
+		}
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs":
+		for _, m := range jsImportRegex.FindAllStringSubmatch(content, -1) {
+			imports = append(imports, m[1])
+		}
+	case ".py":
+		for _, m := range pythonImportRegex.FindAllStringSubmatch(content, -1) {
+			imports = append(imports, m[1]+m[2])
+		}
+	case ".rs":
+		for _, m := range rustUseRegex.FindAllStringSubmatch(content, -1) {
+			imports = append(imports, strings.TrimSuffix(m[1], "::"))
+		}
+	}
+	return imports
+}
+
+// buildExplainFilePrompt constructs the LLM prompt for a file explanation.
+func buildExplainFilePrompt(result *FileExplainResult) string {
+	var sb strings.Builder
+
+	sb.WriteString("You are explaining the role of a source file in a larger system.\n\n")
+	sb.WriteString(fmt.Sprintf("## File\n%s", result.FilePath))
+	if result.Language != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", result.Language))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("\n## Symbols (%d)\n", len(result.Symbols)))
+	for _, s := range result.Symbols {
+		line := fmt.Sprintf("- %s %s", s.Kind, s.Name)
+		if s.Signature != "" {
+			line += ": " + s.Signature
+		}
+		sb.WriteString(line + "\n")
+		if s.DocComment != "" {
+			sb.WriteString(fmt.Sprintf("  %s\n", truncateString(s.DocComment, 200)))
+		}
+	}
+
+	if len(result.MostCalled) > 0 {
+		sb.WriteString("\n## Most Called\n")
+		for _, c := range result.MostCalled {
+			sb.WriteString(fmt.Sprintf("- %s (%d callers)\n", c.Symbol.Name, c.CallerCount))
+		}
+	}
+
+	if len(result.Imports) > 0 {
+		sb.WriteString("\n## Imports\n")
+		for _, imp := range result.Imports {
+			sb.WriteString(fmt.Sprintf("- %s\n", imp))
+		}
+	}
+
+	sb.WriteString(`
+## Task
+Write one concise paragraph that:
+1. Describes the responsibility of this file
+2. Names its key entry points and who depends on them
+3. Notes the external dependencies it relies on
+`)
+
+	return sb.String()
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/josephgoksu/TaskWing/internal/codeintel"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+// fakeChatModel returns a canned answer and records the prompt it received.
+type fakeChatModel struct {
+	prompt string
+}
+
+func (f *fakeChatModel) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	f.prompt = input[len(input)-1].Content
+	return schema.AssistantMessage("This file manages sessions.", nil), nil
+}
+
+func (f *fakeChatModel) Stream(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("This file manages sessions.", nil)}), nil
+}
+
+const sessionFixture = `package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Store struct{}
+
+func NewStore() *Store { return &Store{} }
+
+func (s *Store) Create(ctx context.Context, ttl time.Duration) string { return uuid.NewString() }
+
+func cleanup() {}
+`
+
+func TestExplainApp_ExplainFile(t *testing.T) {
+	ctx := context.Background()
+	basePath := t.TempDir()
+	path := "internal/session/store.go"
+	if err := os.MkdirAll(filepath.Join(basePath, "internal/session"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(basePath, path), []byte(sessionFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	codeRepo := codeintel.NewRepository(store.DB())
+	ids := map[string]uint32{}
+	for _, s := range []codeintel.Symbol{
+		{Name: "Store", Kind: codeintel.SymbolStruct, StartLine: 10, Visibility: "public"},
+		{Name: "NewStore", Kind: codeintel.SymbolFunction, StartLine: 12, Visibility: "public"},
+		{Name: "Create", Kind: codeintel.SymbolMethod, StartLine: 14, Visibility: "public"},
+		{Name: "cleanup", Kind: codeintel.SymbolFunction, StartLine: 16, Visibility: "private"},
+		{Name: "main", Kind: codeintel.SymbolFunction, StartLine: 3, Visibility: "private", FilePath: "cmd/server/main.go"},
+	} {
+		if s.FilePath == "" {
+			s.FilePath = path
+		}
+		s.Language = "go"
+		s.EndLine = s.StartLine
+		id, err := codeRepo.UpsertSymbol(ctx, &s)
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+		ids[s.Name] = id
+	}
+	if err := codeRepo.UpsertRelation(ctx, &codeintel.SymbolRelation{
+		FromSymbolID: ids["main"], ToSymbolID: ids["NewStore"], RelationType: codeintel.RelationCalls,
+	}); err != nil {
+		t.Fatalf("UpsertRelation: %v", err)
+	}
+
+	fake := &fakeChatModel{}
+	explainApp := NewExplainApp(&Context{Repo: memory.NewRepository(store, nil), BasePath: basePath})
+	explainApp.ChatModelFactory = func(context.Context, llm.Config) (*llm.CloseableChatModel, error) {
+		return &llm.CloseableChatModel{BaseChatModel: fake}, nil
+	}
+
+	result, err := explainApp.ExplainFile(ctx, ExplainFileRequest{Path: path})
+	if err != nil {
+		t.Fatalf("ExplainFile: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, s := range result.Symbols {
+		names[s.Name] = true
+	}
+	for _, want := range []string{"Store", "NewStore", "Create"} {
+		if !names[want] {
+			t.Errorf("public symbol %s missing from aggregate", want)
+		}
+	}
+	if names["main"] {
+		t.Error("symbols from other files must not be included")
+	}
+	if len(result.MostCalled) != 1 || result.MostCalled[0].Symbol.Name != "NewStore" {
+		t.Errorf("MostCalled = %+v, want NewStore", result.MostCalled)
+	}
+	if strings.Join(result.Imports, ",") != "context,time,github.com/google/uuid" {
+		t.Errorf("Imports = %v", result.Imports)
+	}
+	if result.Explanation != "This file manages sessions." {
+		t.Errorf("Explanation = %q", result.Explanation)
+	}
+	if !strings.Contains(fake.prompt, "github.com/google/uuid") {
+		t.Error("prompt should include the file's imports")
+	}
+}
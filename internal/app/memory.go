@@ -13,16 +13,18 @@ import (
 // AddResult contains the result of adding knowledge to the memory.
 // This is the canonical response type used by both CLI and MCP.
 type AddResult struct {
-	ID           string `json:"id"`
-	Type         string `json:"type"`
-	Summary      string `json:"summary"`
-	HasEmbedding bool   `json:"has_embedding"`
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	Summary      string   `json:"summary"`
+	HasEmbedding bool     `json:"has_embedding"`
+	Tags         []string `json:"tags,omitempty"`
 }
 
 // AddOptions configures the behavior of an add operation.
 type AddOptions struct {
-	Type   string // Optional manual type override (decision, feature, plan, note)
-	SkipAI bool   // Skip AI classification, store as-is
+	Type   string   // Optional manual type override (decision, feature, plan, note)
+	SkipAI bool     // Skip AI classification, store as-is
+	Tags   []string // Optional grouping labels (e.g. security, perf)
 }
 
 // MemoryApp provides knowledge CRUD operations.
@@ -64,6 +66,7 @@ func (a *MemoryApp) Add(ctx context.Context, content string, opts AddOptions) (*
 	input := knowledge.NodeInput{
 		Content: content,
 		Type:    opts.Type,
+		Tags:    opts.Tags,
 	}
 
 	// If skipping AI, provide fallback values
@@ -85,6 +88,7 @@ func (a *MemoryApp) Add(ctx context.Context, content string, opts AddOptions) (*
 		Type:         node.Type,
 		Summary:      node.Summary,
 		HasEmbedding: len(node.Embedding) > 0,
+		Tags:         node.Tags,
 	}, nil
 }
 
@@ -103,6 +107,51 @@ func (a *MemoryApp) List(ctx context.Context, typeFilter string) ([]knowledge.No
 	return results, nil
 }
 
+// MemoryStatsResult is a size snapshot of the project memory.
+type MemoryStatsResult struct {
+	TotalNodes     int            `json:"total_nodes"`
+	NodesByType    map[string]int `json:"nodes_by_type"`
+	SymbolsFound   int            `json:"symbols_found"`
+	RelationsFound int            `json:"relations_found"`
+	FilesIndexed   int            `json:"files_indexed"`
+	DBSizeBytes    int64          `json:"db_size_bytes"`
+}
+
+// Stats counts knowledge nodes by type, indexed code symbols, relations and
+// files, and reports the database size.
+func (a *MemoryApp) Stats(ctx context.Context) (*MemoryStatsResult, error) {
+	nodes, err := a.ctx.Repo.ListNodes("")
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	result := &MemoryStatsResult{TotalNodes: len(nodes), NodesByType: make(map[string]int)}
+	for _, n := range nodes {
+		t := n.Type
+		if t == "" {
+			t = memory.NodeTypeUnknown
+		}
+		result.NodesByType[t]++
+	}
+
+	codeStats, err := NewCodeIntelApp(a.ctx).GetStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !codeStats.Success {
+		return nil, fmt.Errorf("code index stats: %s", codeStats.Message)
+	}
+	result.SymbolsFound = codeStats.SymbolsFound
+	result.RelationsFound = codeStats.RelationsFound
+	result.FilesIndexed = codeStats.FilesIndexed
+
+	if store := a.ctx.Repo.GetDB(); store != nil {
+		if result.DBSizeBytes, err = store.SizeBytes(); err != nil {
+			return nil, fmt.Errorf("database size: %w", err)
+		}
+	}
+	return result, nil
+}
+
 // Delete removes a knowledge node by ID.
 func (a *MemoryApp) Delete(ctx context.Context, id string) error {
 	if id == "" {
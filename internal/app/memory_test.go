@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/codeintel"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+func TestMemoryApp_Stats(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	repo := memory.NewRepository(store, nil)
+
+	for _, n := range []*memory.Node{
+		{Type: memory.NodeTypeDecision, Summary: "Use SQLite", Content: "Use SQLite for local storage"},
+		{Type: memory.NodeTypeDecision, Summary: "Use Cobra", Content: "Use Cobra for the CLI"},
+		{Type: memory.NodeTypeFeature, Summary: "Plans", Content: "Generate task plans from goals"},
+	} {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	codeRepo := codeintel.NewRepository(store.DB())
+	var ids []uint32
+	for _, s := range []codeintel.Symbol{
+		{Name: "Open", FilePath: "store/open.go"},
+		{Name: "Close", FilePath: "store/open.go"},
+		{Name: "main", FilePath: "cmd/main.go"},
+	} {
+		s.Kind = codeintel.SymbolFunction
+		s.Language = "go"
+		s.StartLine, s.EndLine = 1, 2
+		id, err := codeRepo.UpsertSymbol(ctx, &s)
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := codeRepo.UpsertRelation(ctx, &codeintel.SymbolRelation{
+		FromSymbolID: ids[2], ToSymbolID: ids[0], RelationType: codeintel.RelationCalls,
+	}); err != nil {
+		t.Fatalf("UpsertRelation: %v", err)
+	}
+
+	stats, err := NewMemoryApp(&Context{Repo: repo}).Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.TotalNodes != 3 || stats.NodesByType[memory.NodeTypeDecision] != 2 || stats.NodesByType[memory.NodeTypeFeature] != 1 {
+		t.Errorf("node counts = %d %v, want 3 with 2 decisions and 1 feature", stats.TotalNodes, stats.NodesByType)
+	}
+	if stats.SymbolsFound != 3 || stats.RelationsFound != 1 || stats.FilesIndexed != 2 {
+		t.Errorf("code counts = %d symbols, %d relations, %d files, want 3, 1, 2",
+			stats.SymbolsFound, stats.RelationsFound, stats.FilesIndexed)
+	}
+	if stats.DBSizeBytes <= 0 {
+		t.Errorf("DBSizeBytes = %d, want a positive size", stats.DBSizeBytes)
+	}
+}
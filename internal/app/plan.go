@@ -5,12 +5,18 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,6 +27,7 @@ import (
 	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/knowledge"
 	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
 	"github.com/josephgoksu/TaskWing/internal/planner"
 	"github.com/josephgoksu/TaskWing/internal/task"
 
@@ -29,16 +36,17 @@ import (
 
 // ClarifyResult contains the result of plan clarification.
 type ClarifyResult struct {
-	Success          bool     `json:"success"`
-	ClarifySessionID string   `json:"clarify_session_id,omitempty"`
-	Questions        []string `json:"questions,omitempty"`
-	GoalSummary      string   `json:"goal_summary,omitempty"`
-	EnrichedGoal     string   `json:"enriched_goal,omitempty"`
-	IsReadyToPlan    bool     `json:"is_ready_to_plan"`
-	RoundIndex       int      `json:"round_index,omitempty"`
-	MaxRoundsReached bool     `json:"max_rounds_reached,omitempty"`
-	ContextUsed      string   `json:"context_used,omitempty"`
-	Message          string   `json:"message,omitempty"`
+	Success          bool          `json:"success"`
+	ClarifySessionID string        `json:"clarify_session_id,omitempty"`
+	Questions        []string      `json:"questions,omitempty"`
+	GoalSummary      string        `json:"goal_summary,omitempty"`
+	EnrichedGoal     string        `json:"enriched_goal,omitempty"`
+	IsReadyToPlan    bool          `json:"is_ready_to_plan"`
+	RoundIndex       int           `json:"round_index,omitempty"`
+	MaxRoundsReached bool          `json:"max_rounds_reached,omitempty"`
+	ContextUsed      string        `json:"context_used,omitempty"`
+	Code             PlanErrorCode `json:"code,omitempty"`
+	Message          string        `json:"message,omitempty"`
 }
 
 // ClarifyAnswer is a structured answer to a single clarifying question.
@@ -54,6 +62,11 @@ type ClarifyOptions struct {
 	Answers          []ClarifyAnswer // Answers for the previous round questions
 	AutoAnswer       bool            // Whether to autonomously refine context
 	MaxRounds        int             // Maximum clarification rounds (default: 5)
+
+	// OnQuestion, if set, is called with each question as the agent produces
+	// it (streamed when the clarifier supports it, otherwise once each round
+	// completes). ClarifyResult.Questions remains the authoritative list.
+	OnQuestion func(question string)
 }
 
 // GenerateResult contains the result of plan generation.
@@ -63,11 +76,19 @@ type GenerateResult struct {
 	PlanID           string                           `json:"plan_id,omitempty"`
 	Goal             string                           `json:"goal,omitempty"`
 	EnrichedGoal     string                           `json:"enriched_goal,omitempty"`
+	DryRun           bool                             `json:"dry_run,omitempty"`
+	Code             PlanErrorCode                    `json:"code,omitempty"`
 	Message          string                           `json:"message,omitempty"`
 	Hint             string                           `json:"hint,omitempty"`
-	SemanticWarnings []string                         `json:"semantic_warnings,omitempty"`
+	SemanticWarnings []string                         `json:"semantic_warnings,omitempty"` // Flat form of Warnings, kept for compatibility
+	Warnings         []Warning                        `json:"warnings,omitempty"`
 	SemanticErrors   []string                         `json:"semantic_errors,omitempty"`
 	ValidationStats  *planner.SemanticValidationStats `json:"validation_stats,omitempty"`
+
+	// CodeIntelAvailable reports whether a non-empty code index was used to
+	// auto-correct task paths and commands. False when the index is missing or
+	// empty, and for explicit or streamed tasks, which skip the verifier.
+	CodeIntelAvailable bool `json:"code_intel_available"`
 }
 
 // GenerateOptions configures the behavior of plan generation.
@@ -76,28 +97,62 @@ type GenerateOptions struct {
 	ClarifySessionID string           // Required: clarify session that reached ready state
 	EnrichedGoal     string           // Fully clarified specification
 	Save             bool             // Whether to persist plan/tasks to DB
+	DryRun           bool             // Preview only: no persistence, no active plan change, no recall enrichment
+	StrictValidation bool             // Fail on semantic errors (also enabled by planning.strict_validation)
 	ExplicitTasks    []task.TaskInput // If provided, use these instead of LLM generation
+	Stream           bool             // Persist tasks as the planner emits them (requires Save; ignored for dry runs)
+
+	// NormalizePriorities remaps task priorities with task.NormalizePriorities
+	// before validation (also enabled by planning.normalize_priorities).
+	// Not applied to streamed plans, whose tasks are saved as they arrive.
+	NormalizePriorities bool
+
+	// TestFirst asks the planner to precede each implementation task with a
+	// failing-test task, and makes the implementation task depend on it (also
+	// enabled by planning.test_first). Streamed plans rely on the planner alone
+	// for the dependencies.
+	TestFirst bool
+
+	// MergeDuplicates removes near-duplicate generated tasks, folding each
+	// into the earlier task it repeats (also enabled by
+	// planning.merge_duplicate_tasks). Duplicates are always reported as
+	// warnings. Not applied to explicit or streamed tasks.
+	MergeDuplicates bool
+
+	// PhaseID adds the generated tasks to this existing phase and its plan
+	// instead of creating a new plan, and marks the phase expanded. The phase
+	// must not be expanded yet. Stream is ignored when it is set.
+	PhaseID string
 }
 
 // AuditResult contains the result of plan auditing.
 type AuditResult struct {
-	Success        bool            `json:"success"`
-	PlanID         string          `json:"plan_id,omitempty"`
-	Status         string          `json:"status,omitempty"`      // "verified", "needs_revision", "failed"
-	PlanStatus     task.PlanStatus `json:"plan_status,omitempty"` // Updated plan status
-	BuildPassed    bool            `json:"build_passed,omitempty"`
-	TestsPassed    bool            `json:"tests_passed,omitempty"`
-	SemanticIssues []string        `json:"semantic_issues,omitempty"`
-	FixesApplied   []string        `json:"fixes_applied,omitempty"`
-	RetryCount     int             `json:"retry_count,omitempty"`
-	Message        string          `json:"message,omitempty"`
-	Hint           string          `json:"hint,omitempty"`
+	Success        bool              `json:"success"`
+	PlanID         string            `json:"plan_id,omitempty"`
+	Status         string            `json:"status,omitempty"`      // "verified", "needs_revision", "failed"
+	PlanStatus     task.PlanStatus   `json:"plan_status,omitempty"` // Updated plan status
+	BuildPassed    bool              `json:"build_passed,omitempty"`
+	TestsPassed    bool              `json:"tests_passed,omitempty"`
+	SemanticIssues []string          `json:"semantic_issues,omitempty"`
+	FixesApplied   []string          `json:"fixes_applied,omitempty"`
+	RetryCount     int               `json:"retry_count,omitempty"`
+	Cached         bool              `json:"cached,omitempty"` // Tracked files unchanged since the last successful audit
+	Report         *task.AuditReport `json:"-"`                // Full report, e.g. for ToJUnit; nil unless the audit ran or was cached
+	Code           PlanErrorCode     `json:"code,omitempty"`
+	Message        string            `json:"message,omitempty"`
+	Hint           string            `json:"hint,omitempty"`
 }
 
 // AuditOptions configures the behavior of plan auditing.
 type AuditOptions struct {
 	PlanID  string // Optional: specific plan ID (defaults to active plan)
 	AutoFix bool   // If true, attempt to fix failures automatically
+	Force   bool   // Re-audit even if tracked files are unchanged since the last successful audit
+}
+
+// PlanAuditor runs build, test, and semantic checks for a plan.
+type PlanAuditor interface {
+	Audit(ctx context.Context, plan *task.Plan, autoFix bool) (*task.AuditReport, error)
 }
 
 // GoalsClarifier defines the interface for the clarifying agent.
@@ -113,10 +168,29 @@ type TaskPlanner interface {
 	Close() error
 }
 
+// StreamingClarifier is a GoalsClarifier that can emit questions while they are generated.
+type StreamingClarifier interface {
+	GoalsClarifier
+	RunStream(ctx context.Context, input core.Input, onQuestion func(string)) (core.Output, error)
+}
+
+// StreamingTaskPlanner is a TaskPlanner that can emit tasks while the plan is generated.
+type StreamingTaskPlanner interface {
+	TaskPlanner
+	RunStream(ctx context.Context, input core.Input, emit func(impl.PlanningTask) error) error
+}
+
 // TaskContextEnricher executes ask queries and returns aggregated context for a task.
 // This is used during task creation to populate ContextSummary (early binding).
 // See docs/architecture/ADR_CONTEXT_BINDING.md for the full context binding design.
-type TaskContextEnricher func(ctx context.Context, queries []string, scope string) (string, error)
+type TaskContextEnricher func(ctx context.Context, queries []string, scope string) (TaskEnrichment, error)
+
+// TaskEnrichment is the aggregated context for a task and how many of its
+// queries failed. Context holds whatever the successful queries returned.
+type TaskEnrichment struct {
+	Context       string
+	FailedQueries int
+}
 
 const (
 	defaultClarifyMaxRounds            = 5
@@ -130,9 +204,21 @@ type PlanApp struct {
 	Repo             task.Repository
 	ClarifierFactory func(llm.Config) GoalsClarifier
 	PlannerFactory   func(llm.Config) TaskPlanner
+	ExpanderFactory  func(llm.Config) PhaseExpander
 	// TaskEnricher populates task ContextSummary at creation time.
 	// Uses GetProjectContext with compact options by default.
 	TaskEnricher TaskContextEnricher
+
+	// TaskIDGenerator returns IDs for generated tasks. Defaults to short random
+	// UUIDs; tests and deterministic pipelines can supply sequential IDs.
+	TaskIDGenerator func() string
+
+	// Auditor verifies completed plans. NewPlanApp sets a CommandAuditor when
+	// the project has build or test commands; auditing is unsupported when nil.
+	Auditor PlanAuditor
+
+	// contextRecall runs a single enrichment query. Overridden in tests.
+	contextRecall func(ctx context.Context, opts knowledge.ContextOptions) (*knowledge.ProjectContext, error)
 }
 
 // NewPlanApp creates a new plan application service.
@@ -146,11 +232,34 @@ func NewPlanApp(ctx *Context) *PlanApp {
 		PlannerFactory: func(cfg llm.Config) TaskPlanner {
 			return impl.NewPlanningAgent(cfg)
 		},
+		ExpanderFactory: func(cfg llm.Config) PhaseExpander {
+			return impl.NewExpandAgent(cfg)
+		},
 	}
 	pa.TaskEnricher = pa.defaultTaskEnricher
+	pa.TaskIDGenerator = newRandomTaskID
+	if auditor := NewCommandAuditor(ctx.BasePath); auditor != nil {
+		pa.Auditor = auditor
+	}
+	pa.contextRecall = func(ctx context.Context, opts knowledge.ContextOptions) (*knowledge.ProjectContext, error) {
+		return knowledge.GetProjectContext(ctx, knowledge.NewService(pa.ctx.Repo, pa.ctx.LLMCfg), opts)
+	}
 	return pa
 }
 
+// newRandomTaskID returns a task ID with a short random UUID suffix.
+func newRandomTaskID() string {
+	return "task-" + uuid.New().String()[:8]
+}
+
+// newTaskID returns the next task ID from TaskIDGenerator.
+func (a *PlanApp) newTaskID() string {
+	if a.TaskIDGenerator == nil {
+		return newRandomTaskID()
+	}
+	return a.TaskIDGenerator()
+}
+
 // retrieveContext performs unified project context retrieval for planning.
 // Returns the formatted context string ready for LLM prompt injection.
 func (a *PlanApp) retrieveContext(ctx context.Context, ks *knowledge.Service, goal, memoryPath string) (string, error) {
@@ -167,44 +276,105 @@ func (a *PlanApp) retrieveContext(ctx context.Context, ks *knowledge.Service, go
 }
 
 // defaultTaskEnricher uses GetProjectContext with compact options to enrich tasks.
-func (a *PlanApp) defaultTaskEnricher(ctx context.Context, queries []string, scope string) (string, error) {
-	if a.ctx == nil || a.ctx.Repo == nil {
-		return "", nil
+// Each ask query is recalled separately so one failing query does not discard
+// the context the others found; failures are counted in FailedQueries.
+func (a *PlanApp) defaultTaskEnricher(ctx context.Context, queries []string, scope string) (TaskEnrichment, error) {
+	if a.ctx == nil || a.ctx.Repo == nil || a.contextRecall == nil {
+		return TaskEnrichment{}, nil
 	}
 
-	ks := knowledge.NewService(a.ctx.Repo, a.ctx.LLMCfg)
-
-	// Scope-aware query with broad project context as baseline.
-	// The scope narrows the search, but constraints are always included
+	// Scope-aware queries with broad project context as baseline.
+	// The scope narrows each search, but constraints are always included
 	// (IncludeConstraints=true) so the task always has the full project rules.
-	var query string
-	if len(queries) > 0 && scope != "" {
-		// Combine both: task-specific queries + scope for broader coverage
-		query = strings.Join(queries, " ") + " " + scope
-	} else if len(queries) > 0 {
-		query = strings.Join(queries, " ")
-	} else if scope != "" {
-		query = scope + " patterns constraints decisions"
-	} else {
-		query = "project architecture patterns constraints decisions"
+	var searches []string
+	for _, q := range queries {
+		if q = strings.TrimSpace(q); q == "" {
+			continue
+		}
+		if scope != "" {
+			q += " " + scope
+		}
+		searches = append(searches, q)
+	}
+	if len(searches) == 0 {
+		if scope != "" {
+			searches = []string{scope + " patterns constraints decisions"}
+		} else {
+			searches = []string{"project architecture patterns constraints decisions"}
+		}
 	}
 
 	modelID := a.ctx.LLMCfg.Model
-	opts := knowledge.DefaultContextOptionsForModel(modelID)
-	opts.Query = query
-	opts.IncludeArchitectureMD = false // Included selectively for first task
-	opts.IncludeConstraints = true     // Always include all constraints regardless of scope
-	opts.UseLLMQueries = false         // Use queries directly for speed
-
 	memoryPath, _ := config.GetMemoryBasePath()
-	opts.MemoryBasePath = memoryPath
 
-	pc, err := knowledge.GetProjectContext(ctx, ks, opts)
-	if err != nil || (len(pc.Constraints) == 0 && len(pc.RelevantNodes) == 0) {
-		return "", err
+	merged := &knowledge.ProjectContext{}
+	seen := make(map[string]int) // node ID -> index in merged.RelevantNodes
+	var failed int
+	var lastErr error
+	for _, query := range searches {
+		opts := knowledge.DefaultContextOptionsForModel(modelID)
+		opts.Query = query
+		opts.IncludeArchitectureMD = false                     // Included selectively for first task
+		opts.IncludeConstraints = len(merged.Constraints) == 0 // Constraints are global; fetch once
+		opts.UseLLMQueries = false                             // Use queries directly for speed
+		opts.MemoryBasePath = memoryPath
+
+		pc, err := a.contextRecall(ctx, opts)
+		if err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		if len(merged.Constraints) == 0 {
+			merged.Constraints = pc.Constraints
+		}
+		for _, sn := range pc.RelevantNodes {
+			if sn.Node == nil {
+				continue
+			}
+			if i, ok := seen[sn.Node.ID]; ok {
+				if sn.Score > merged.RelevantNodes[i].Score {
+					merged.RelevantNodes[i] = sn
+				}
+				continue
+			}
+			seen[sn.Node.ID] = len(merged.RelevantNodes)
+			merged.RelevantNodes = append(merged.RelevantNodes, sn)
+		}
+	}
+
+	result := TaskEnrichment{FailedQueries: failed}
+	if failed == len(searches) {
+		return result, fmt.Errorf("all %d enrichment queries failed: %w", failed, lastErr)
+	}
+	if len(merged.Constraints) == 0 && len(merged.RelevantNodes) == 0 {
+		return result, nil
 	}
 
-	return pc.FormatCompact(modelID), nil
+	sort.SliceStable(merged.RelevantNodes, func(i, j int) bool {
+		return merged.RelevantNodes[i].Score > merged.RelevantNodes[j].Score
+	})
+	if maxNodes := knowledge.DefaultContextOptionsForModel(modelID).MaxNodes; maxNodes > 0 && len(merged.RelevantNodes) > maxNodes {
+		merged.RelevantNodes = merged.RelevantNodes[:maxNodes]
+	}
+	result.Context = merged.FormatCompact(modelID)
+	return result, nil
+}
+
+// enrichTask populates a task's ContextSummary by executing its ask queries,
+// keeping partial context when some queries fail and recording the failures.
+func (a *PlanApp) enrichTask(ctx context.Context, t *task.Task) {
+	if a.TaskEnricher == nil || (len(t.SuggestedAskQueries) == 0 && t.Scope == "") {
+		return
+	}
+	enrichment, err := a.TaskEnricher(ctx, t.SuggestedAskQueries, t.Scope)
+	if err != nil {
+		slog.Debug("task enrichment failed", "task", t.Title, "error", err)
+	}
+	t.EnrichmentErrors = enrichment.FailedQueries
+	if enrichment.Context != "" {
+		t.ContextSummary = enrichment.Context
+	}
 }
 
 // Clarify refines a development goal by asking clarifying questions.
@@ -229,6 +399,7 @@ func (a *PlanApp) Clarify(ctx context.Context, opts ClarifyOptions) (*ClarifyRes
 		if goal == "" {
 			return &ClarifyResult{
 				Success: false,
+				Code:    PlanErrorGoalRequired,
 				Message: "goal is required",
 			}, nil
 		}
@@ -250,6 +421,7 @@ func (a *PlanApp) Clarify(ctx context.Context, opts ClarifyOptions) (*ClarifyRes
 		if err != nil {
 			return &ClarifyResult{
 				Success: false,
+				Code:    PlanErrorClarifySessionNotFound,
 				Message: fmt.Sprintf("clarify session not found: %s", sessionID),
 			}, nil
 		}
@@ -308,6 +480,7 @@ func (a *PlanApp) Clarify(ctx context.Context, opts ClarifyOptions) (*ClarifyRes
 	if len(inputAnswers) > 0 && len(session.CurrentQuestions) == 0 {
 		return &ClarifyResult{
 			Success: false,
+			Code:    PlanErrorInvalidInput,
 			Message: "answers provided but no pending clarification questions exist",
 		}, nil
 	}
@@ -332,6 +505,10 @@ func (a *PlanApp) Clarify(ctx context.Context, opts ClarifyOptions) (*ClarifyRes
 	if contextStr != "" {
 		contextSummary = "Retrieved relevant nodes and constraints from knowledge graph"
 	}
+	autoAnswerCtx := contextStr
+	if opts.AutoAnswer {
+		autoAnswerCtx = a.withConventions(contextStr)
+	}
 
 	answersForRound := append([]string(nil), inputAnswers...)
 	for {
@@ -344,22 +521,25 @@ func (a *PlanApp) Clarify(ctx context.Context, opts ClarifyOptions) (*ClarifyRes
 			},
 		}
 
-		output, err := clarifyingAgent.Run(ctx, input)
+		output, err := runClarifier(ctx, clarifyingAgent, input, opts.OnQuestion)
 		if err != nil {
 			return &ClarifyResult{
 				Success: false,
+				Code:    PlanErrorAgentFailed,
 				Message: fmt.Sprintf("clarifying agent failed: %v", err),
 			}, nil
 		}
 		if output.Error != nil {
 			return &ClarifyResult{
 				Success: false,
+				Code:    PlanErrorAgentFailed,
 				Message: fmt.Sprintf("clarifying agent error: %v", output.Error),
 			}, nil
 		}
 		if len(output.Findings) == 0 {
 			return &ClarifyResult{
 				Success: false,
+				Code:    PlanErrorAgentFailed,
 				Message: "no findings from clarifying agent",
 			}, nil
 		}
@@ -411,7 +591,7 @@ func (a *PlanApp) Clarify(ctx context.Context, opts ClarifyOptions) (*ClarifyRes
 		}
 
 		if opts.AutoAnswer && !isReady && !maxRoundsReached && len(questions) > 0 {
-			autoAnswer, err := clarifyingAgent.AutoAnswer(ctx, enrichedGoal, questions, contextStr)
+			autoAnswer, err := clarifyingAgent.AutoAnswer(ctx, enrichedGoal, questions, autoAnswerCtx)
 			if err != nil || strings.TrimSpace(autoAnswer) == "" {
 				return &ClarifyResult{
 					Success:          true,
@@ -444,6 +624,69 @@ func (a *PlanApp) Clarify(ctx context.Context, opts ClarifyOptions) (*ClarifyRes
 	}
 }
 
+// runClarifier runs one clarification round, reporting questions to onQuestion
+// when set: streamed if the agent supports it, otherwise after the batch run.
+func runClarifier(ctx context.Context, agent GoalsClarifier, input core.Input, onQuestion func(string)) (core.Output, error) {
+	if onQuestion == nil {
+		return agent.Run(ctx, input)
+	}
+	if streamer, ok := agent.(StreamingClarifier); ok {
+		return streamer.RunStream(ctx, input, onQuestion)
+	}
+	output, err := agent.Run(ctx, input)
+	if err == nil && output.Error == nil && len(output.Findings) > 0 {
+		for _, q := range parseQuestionsFromMetadata(output.Findings[0].Metadata) {
+			onQuestion(q)
+		}
+	}
+	return output, err
+}
+
+// SaveClarification stores a clarification completed outside the clarify loop
+// (for example an enriched goal drafted by the user's own AI) as a session that
+// is ready to plan. Pass the returned ID as GenerateOptions.ClarifySessionID to
+// generate from it without repeating the enriched goal.
+func (a *PlanApp) SaveClarification(goal, enrichedGoal, goalSummary string) (string, error) {
+	goal = strings.TrimSpace(goal)
+	enrichedGoal = strings.TrimSpace(enrichedGoal)
+	if goal == "" {
+		return "", fmt.Errorf("goal is required")
+	}
+	if enrichedGoal == "" {
+		return "", fmt.Errorf("enriched goal is required")
+	}
+	if a.ctx == nil || a.ctx.Repo == nil {
+		return "", fmt.Errorf("save clarification: no repository")
+	}
+
+	session := &task.ClarifySession{
+		Goal:                 goal,
+		EnrichedGoal:         enrichedGoal,
+		GoalSummary:          strings.TrimSpace(goalSummary),
+		State:                task.ClarifySessionStateReadyToPlan,
+		MaxRounds:            defaultClarifyMaxRounds,
+		MaxQuestionsPerRound: defaultClarifyMaxQuestionsPerRound,
+		IsReadyToPlan:        true,
+	}
+	if err := a.ctx.Repo.CreateClarifySession(session); err != nil {
+		return "", fmt.Errorf("save clarification: %w", err)
+	}
+	return session.ID, nil
+}
+
+// withConventions prepends the project's team conventions file, if any, to
+// the context given to clarify auto-answers so they follow team defaults.
+func (a *PlanApp) withConventions(contextStr string) string {
+	conventions, err := config.LoadConventions(a.ctx.BasePath)
+	if err != nil {
+		slog.Warn("team conventions unavailable for auto-answer", "error", err)
+	}
+	if conventions == "" {
+		return contextStr
+	}
+	return "## Team Conventions\nFollow these defaults unless the goal says otherwise.\n\n" + conventions + "\n\n" + contextStr
+}
+
 // clarifyWithoutPersistence is a test-friendly fallback when repository context is unavailable.
 // It preserves the clarify contract but skips session storage.
 func (a *PlanApp) clarifyWithoutPersistence(ctx context.Context, opts ClarifyOptions) (*ClarifyResult, error) {
@@ -451,6 +694,7 @@ func (a *PlanApp) clarifyWithoutPersistence(ctx context.Context, opts ClarifyOpt
 	if goal == "" {
 		return &ClarifyResult{
 			Success: false,
+			Code:    PlanErrorGoalRequired,
 			Message: "goal is required",
 		}, nil
 	}
@@ -481,15 +725,15 @@ func (a *PlanApp) clarifyWithoutPersistence(ctx context.Context, opts ClarifyOpt
 				"context": "",
 			},
 		}
-		output, err := clarifyingAgent.Run(ctx, input)
+		output, err := runClarifier(ctx, clarifyingAgent, input, opts.OnQuestion)
 		if err != nil {
-			return &ClarifyResult{Success: false, Message: fmt.Sprintf("clarifying agent failed: %v", err)}, nil
+			return &ClarifyResult{Success: false, Code: PlanErrorAgentFailed, Message: fmt.Sprintf("clarifying agent failed: %v", err)}, nil
 		}
 		if output.Error != nil {
-			return &ClarifyResult{Success: false, Message: fmt.Sprintf("clarifying agent error: %v", output.Error)}, nil
+			return &ClarifyResult{Success: false, Code: PlanErrorAgentFailed, Message: fmt.Sprintf("clarifying agent error: %v", output.Error)}, nil
 		}
 		if len(output.Findings) == 0 {
-			return &ClarifyResult{Success: false, Message: "no findings from clarifying agent"}, nil
+			return &ClarifyResult{Success: false, Code: PlanErrorAgentFailed, Message: "no findings from clarifying agent"}, nil
 		}
 
 		finding := output.Findings[0]
@@ -500,7 +744,7 @@ func (a *PlanApp) clarifyWithoutPersistence(ctx context.Context, opts ClarifyOpt
 		maxReached := roundIndex >= maxRounds && !isReady
 
 		if opts.AutoAnswer && !isReady && !maxReached && len(questions) > 0 {
-			autoAnswer, err := clarifyingAgent.AutoAnswer(ctx, enrichedGoal, questions, "")
+			autoAnswer, err := clarifyingAgent.AutoAnswer(ctx, enrichedGoal, questions, a.withConventions(""))
 			if err != nil || strings.TrimSpace(autoAnswer) == "" {
 				return &ClarifyResult{
 					Success:          true,
@@ -535,6 +779,7 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 	if opts.Goal == "" {
 		return &GenerateResult{
 			Success: false,
+			Code:    PlanErrorGoalRequired,
 			Message: "goal is required",
 		}, nil
 	}
@@ -543,6 +788,7 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 	if opts.EnrichedGoal == "" && strings.TrimSpace(opts.ClarifySessionID) == "" {
 		return &GenerateResult{
 			Success: false,
+			Code:    PlanErrorNoEnrichedGoal,
 			Message: "Either enriched_goal or clarify_session_id is required",
 			Hint:    "Provide enriched_goal directly, or run plan action=clarify first.",
 		}, nil
@@ -555,12 +801,14 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 		if err != nil {
 			return &GenerateResult{
 				Success: false,
+				Code:    PlanErrorClarifySessionNotFound,
 				Message: fmt.Sprintf("clarify session not found: %s", opts.ClarifySessionID),
 			}, nil
 		}
 		if !session.IsReadyToPlan || session.State != task.ClarifySessionStateReadyToPlan {
 			return &GenerateResult{
 				Success: false,
+				Code:    PlanErrorClarifyIncomplete,
 				Message: "clarification is not complete for this session",
 				Hint:    "Continue plan action=clarify with clarify_session_id and answers until is_ready_to_plan=true.",
 			}, nil
@@ -574,13 +822,49 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 	repo := a.Repo
 	llmCfg := a.ctx.LLMCfg
 
+	var targetPhase *task.Phase
+	if phaseID := strings.TrimSpace(opts.PhaseID); phaseID != "" {
+		phase, err := repo.GetPhase(phaseID)
+		if err != nil {
+			return &GenerateResult{
+				Success: false,
+				Code:    PlanErrorPhaseNotFound,
+				Message: fmt.Sprintf("Phase not found: %s", phaseID),
+				Hint:    "Run plan decompose to create phases, or omit phase_id to generate a new plan.",
+			}, nil
+		}
+		// Generating into a phase that already has tasks would append duplicates
+		existing, err := repo.ListTasksByPhase(phase.ID)
+		if err != nil {
+			return &GenerateResult{
+				Success: false,
+				Code:    PlanErrorPersistence,
+				Message: fmt.Sprintf("Failed to list phase tasks: %v", err),
+				PlanID:  phase.PlanID,
+			}, nil
+		}
+		if phase.Status == task.PhaseStatusExpanded || len(existing) > 0 {
+			return &GenerateResult{
+				Success: false,
+				Code:    PlanErrorPhaseExpanded,
+				Message: fmt.Sprintf("Phase already expanded: %s (%d tasks)", phase.Title, len(existing)),
+				PlanID:  phase.PlanID,
+				Hint:    "Run plan expand with feedback to regenerate the phase's tasks.",
+			}, nil
+		}
+		targetPhase = phase
+	}
+
 	// Fetch context from knowledge graph using canonical shared function
 	// Context retrieval is optional enhancement - log errors but don't fail
-	ks := knowledge.NewService(a.ctx.Repo, llmCfg)
+	// Dry runs skip recall to stay fast.
 	var contextStr string
-	if memoryPath, err := config.GetMemoryBasePath(); err == nil {
-		if retrievedCtx, err := a.retrieveContext(ctx, ks, opts.EnrichedGoal, memoryPath); err == nil {
-			contextStr = retrievedCtx
+	if !opts.DryRun {
+		ks := knowledge.NewService(a.ctx.Repo, llmCfg)
+		if memoryPath, err := config.GetMemoryBasePath(); err == nil {
+			if retrievedCtx, err := a.retrieveContext(ctx, ks, opts.EnrichedGoal, memoryPath); err == nil {
+				contextStr = retrievedCtx
+			}
 		}
 	}
 
@@ -597,14 +881,15 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 				complexity = "medium"
 			}
 			t := task.Task{
-				ID:                 fmt.Sprintf("task-%s", uuid.New().String()[:8]),
+				ID:                 a.newTaskID(),
 				Title:              et.Title,
 				Description:        et.Description,
 				AcceptanceCriteria: et.AcceptanceCriteria,
-				ValidationSteps:    et.ValidationSteps,
+				ValidationSteps:    task.CommandValidationSteps(et.ValidationSteps),
 				Priority:           priority,
 				Complexity:         complexity,
 				Status:             task.StatusPending,
+				SkipAudit:          et.SkipAudit,
 			}
 			t.EnrichAIFields()
 			tasks = append(tasks, t)
@@ -614,6 +899,11 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 		planningAgent := a.PlannerFactory(llmCfg)
 		defer func() { _ = planningAgent.Close() }()
 
+		testFirst := opts.TestFirst || config.LoadTestFirst()
+		if testFirst {
+			contextStr = strings.TrimSpace(config.PlanningTestFirstInstructions + "\n\n" + contextStr)
+		}
+
 		input := core.Input{
 			ExistingContext: map[string]any{
 				"goal":          opts.Goal,
@@ -622,16 +912,22 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 			},
 		}
 
+		if streamer, ok := planningAgent.(StreamingTaskPlanner); ok && opts.Stream && opts.Save && !opts.DryRun && targetPhase == nil {
+			return a.generateStreaming(ctx, streamer, input, opts), nil
+		}
+
 		output, err := planningAgent.Run(ctx, input)
 		if err != nil {
 			return &GenerateResult{
 				Success: false,
+				Code:    PlanErrorAgentFailed,
 				Message: fmt.Sprintf("Planning agent failed: %v", err),
 			}, nil
 		}
 		if output.Error != nil {
 			return &GenerateResult{
 				Success: false,
+				Code:    PlanErrorAgentFailed,
 				Message: fmt.Sprintf("Planning agent error: %v", output.Error),
 			}, nil
 		}
@@ -640,33 +936,50 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 		if len(output.Findings) == 0 {
 			return &GenerateResult{
 				Success: false,
+				Code:    PlanErrorAgentFailed,
 				Message: "No findings from planning agent",
 			}, nil
 		}
 
 		finding := output.Findings[0]
 		tasks = a.parseTasksFromMetadata(ctx, finding.Metadata)
+		if testFirst {
+			task.WireTestFirstDependencies(tasks)
+		}
 	}
 
 	if len(tasks) == 0 {
 		return &GenerateResult{
 			Success: false,
+			Code:    PlanErrorNoTasksGenerated,
 			Message: "No tasks generated",
 		}, nil
 	}
 
+	// Flag near-duplicate tasks from the planner; merge them when asked
+	var warnings warningList
+	if len(opts.ExplicitTasks) == 0 {
+		tasks = dedupGeneratedTasks(tasks, opts.MergeDuplicates || config.LoadMergeDuplicateTasks(), &warnings)
+	}
+
+	// Normalize before validation so out-of-range generated priorities are fixed, not rejected
+	if opts.NormalizePriorities || config.LoadNormalizePriorities() {
+		task.NormalizePriorities(tasks)
+	}
+
 	// Validate tasks
 	for i, t := range tasks {
 		if err := t.Validate(); err != nil {
 			return &GenerateResult{
 				Success: false,
+				Code:    PlanErrorTaskValidation,
 				Message: fmt.Sprintf("Task %d validation failed: %v", i+1, err),
 			}, nil
 		}
 	}
 
 	// Run semantic validation (file paths, shell commands)
-	var semanticWarnings, semanticErrors []string
+	var semanticErrors []string
 	// Track whether this is a passthrough call (user provided tasks directly).
 	// Passthrough skips semantic validation and path correction since the user
 	// trusts their own input and does not want TaskWing to rewrite it.
@@ -679,9 +992,11 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 		if workDir == "" {
 			workDir, _ = os.Getwd()
 		}
+		strict := opts.StrictValidation || config.LoadStrictValidation()
 		middleware := planner.NewSemanticMiddleware(planner.MiddlewareConfig{
 			BasePath:          workDir,
 			AllowMissingFiles: true, // Warnings, not errors - plans often create new files
+			StrictMode:        strict,
 		})
 
 		// Convert tasks to planner schema for validation
@@ -691,7 +1006,7 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 				Title:              t.Title,
 				Description:        t.Description,
 				AcceptanceCriteria: t.AcceptanceCriteria,
-				ValidationSteps:    t.ValidationSteps,
+				ValidationSteps:    task.CommandSteps(t.ValidationSteps),
 			}
 		}
 
@@ -706,18 +1021,41 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 
 		// Collect warnings
 		for _, w := range semanticResult.Warnings {
-			semanticWarnings = append(semanticWarnings, fmt.Sprintf("[Task %d] %s: %s", w.TaskIndex+1, w.Type, w.Message))
+			warnings.add(semanticWarningCategory(w.Type), w.TaskIndex, fmt.Sprintf("%s: %s", w.Type, w.Message))
 		}
 
-		// Collect errors (these are non-blocking but logged)
+		// Collect errors (non-blocking unless strict)
 		for _, e := range semanticResult.Errors {
 			semanticErrors = append(semanticErrors, fmt.Sprintf("[Task %d] %s: %s", e.TaskIndex+1, e.Type, e.Message))
 		}
+		if strict && !semanticResult.Valid {
+			return &GenerateResult{
+				Success:          false,
+				Code:             PlanErrorSemanticValidation,
+				Message:          fmt.Sprintf("Semantic validation failed (strict mode): %s", semanticResult.ErrorSummary()),
+				Hint:             "Fix the referenced paths/commands, or disable planning.strict_validation to treat them as warnings.",
+				SemanticWarnings: warnings.flat,
+				Warnings:         warnings.items,
+				SemanticErrors:   semanticErrors,
+				ValidationStats:  &semanticResult.Stats,
+			}, nil
+		}
+
+		// Cross-check tasks against high-severity constraints (warnings only)
+		if a.ctx.Repo != nil {
+			constraints, err := knowledge.NewService(a.ctx.Repo, llmCfg).ListNodesByType(ctx, memory.NodeTypeConstraint)
+			if err != nil {
+				slog.Debug("constraint lint skipped", "error", err)
+			}
+			for _, w := range lintTasksAgainstConstraints(tasks, constraints) {
+				warnings.add(w.Category, w.TaskIndex, w.Message)
+			}
+		}
 
 		// Log validation results
-		if len(semanticWarnings) > 0 || len(semanticErrors) > 0 {
+		if warnings.len() > 0 || len(semanticErrors) > 0 {
 			slog.Debug("semantic validation completed",
-				"warnings", len(semanticWarnings),
+				"warnings", warnings.len(),
 				"errors", len(semanticErrors),
 				"paths_checked", semanticResult.Stats.PathsChecked,
 				"commands_validated", semanticResult.Stats.CommandsValidated)
@@ -726,6 +1064,7 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 
 	// Run PlanVerifier to auto-correct paths and commands using code intelligence.
 	// Skipped for passthrough mode since the user trusts their own paths.
+	var codeIntelAvailable bool
 	if !isPassthrough {
 		// Try to get codeintel QueryService (optional - best effort)
 		var queryService *codeintel.QueryService
@@ -733,13 +1072,25 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 		if memoryPath, err := config.GetMemoryBasePath(); err == nil {
 			dbPath := filepath.Join(memoryPath, "memory.db")
 			if _, statErr := os.Stat(dbPath); statErr == nil {
-				if db, err = sql.Open("sqlite", dbPath); err == nil {
+				dsn := dbPath
+				if opts.DryRun {
+					dsn = "file:" + dbPath + "?mode=ro" // Dry runs must not touch the index
+				}
+				if db, err = sql.Open("sqlite", dsn); err == nil {
 					defer func() { _ = db.Close() }()
 					repo := codeintel.NewRepository(db)
-					queryService = codeintel.NewQueryService(repo, llmCfg)
+					// An empty index has nothing to correct against
+					if count, err := repo.GetSymbolCount(ctx); err == nil && count > 0 {
+						queryService = codeintel.NewQueryService(repo, llmCfg)
+						codeIntelAvailable = true
+					}
 				}
 			}
 		}
+		if !codeIntelAvailable {
+			warnings.add(WarningCategoryCodeIntel, -1,
+				"Code intelligence index is missing or empty, so paths and commands were not auto-corrected. Run 'taskwing bootstrap' to build it.")
+		}
 
 		verifier := planner.NewPlanVerifierWithConfig(queryService, planner.VerifierConfig{
 			BasePath: a.ctx.BasePath,
@@ -752,7 +1103,7 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 				Title:              t.Title,
 				Description:        t.Description,
 				AcceptanceCriteria: t.AcceptanceCriteria,
-				ValidationSteps:    t.ValidationSteps,
+				ValidationSteps:    task.CommandSteps(t.ValidationSteps),
 			}
 		}
 
@@ -790,7 +1141,7 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 				if corrected {
 					commandCorrections++
 					for _, note := range notes {
-						semanticWarnings = append(semanticWarnings, fmt.Sprintf("[Task %d] %s", i+1, note))
+						warnings.add(WarningCategoryCommand, i, note)
 					}
 				}
 			}
@@ -800,7 +1151,7 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 				tasks[i].Title = correctedTasks[i].Title
 				tasks[i].Description = correctedTasks[i].Description
 				tasks[i].AcceptanceCriteria = correctedTasks[i].AcceptanceCriteria
-				tasks[i].ValidationSteps = correctedTasks[i].ValidationSteps
+				tasks[i].ValidationSteps = task.ReplaceCommandSteps(tasks[i].ValidationSteps, correctedTasks[i].ValidationSteps)
 			}
 
 			// Log corrections
@@ -808,7 +1159,7 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 				slog.Info("plan verifier applied corrections",
 					"path_corrections", pathCorrections,
 					"command_corrections", commandCorrections)
-				semanticWarnings = append(semanticWarnings,
+				warnings.add(WarningCategoryVerifier, -1,
 					fmt.Sprintf("Auto-corrected %d paths and %d commands using code intelligence", pathCorrections, commandCorrections))
 			}
 		}
@@ -816,7 +1167,23 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 
 	// Save the plan
 	var planID string
-	{
+	if targetPhase != nil {
+		planID = targetPhase.PlanID
+		for i := range tasks {
+			tasks[i].PlanID = targetPhase.PlanID
+			tasks[i].PhaseID = targetPhase.ID
+		}
+		if opts.Save && !opts.DryRun {
+			if err := a.saveGeneratedPhaseTasks(targetPhase, tasks); err != nil {
+				return &GenerateResult{
+					Success: false,
+					Code:    PlanErrorPersistence,
+					Message: fmt.Sprintf("Failed to save phase tasks: %v", err),
+					PlanID:  planID,
+				}, nil
+			}
+		}
+	} else {
 		plan := &task.Plan{
 			Goal:         opts.Goal,
 			EnrichedGoal: opts.EnrichedGoal,
@@ -824,34 +1191,25 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 			Tasks:        tasks,
 		}
 
-		if opts.Save {
+		if opts.Save && !opts.DryRun {
 			// Transactional creation logic is handled by repo.CreatePlan
 			if err := repo.CreatePlan(plan); err != nil {
 				return &GenerateResult{
 					Success: false,
+					Code:    PlanErrorPersistence,
 					Message: fmt.Sprintf("Failed to save plan: %v", err),
 				}, nil
 			}
 			planID = plan.ID
 
 			// Set as active plan (fail if we can't set it active)
-			if memoryPathSvc, err := config.GetMemoryBasePath(); err == nil {
-				svc := task.NewService(repo, memoryPathSvc)
-				if err := svc.SetActivePlan(planID); err != nil {
-					return &GenerateResult{
-						Success: false,
-						Message: fmt.Sprintf("Plan created but failed to set active: %v", err),
-						PlanID:  planID,
-					}, nil
-				}
-			} else {
-				if err := repo.SetActivePlan(planID); err != nil {
-					return &GenerateResult{
-						Success: false,
-						Message: fmt.Sprintf("Plan created but failed to set active: %v", err),
-						PlanID:  planID,
-					}, nil
-				}
+			if err := a.activatePlan(planID); err != nil {
+				return &GenerateResult{
+					Success: false,
+					Code:    PlanErrorPersistence,
+					Message: fmt.Sprintf("Plan created but failed to set active: %v", err),
+					PlanID:  planID,
+				}, nil
 			}
 		} else {
 			// Even if not saving to DB, generate a temporary ID or leave empty
@@ -859,20 +1217,89 @@ func (a *PlanApp) Generate(ctx context.Context, opts GenerateOptions) (*Generate
 		}
 	}
 
+	if opts.DryRun {
+		return &GenerateResult{
+			Success:            true,
+			Tasks:              tasks,
+			Goal:               opts.Goal,
+			EnrichedGoal:       opts.EnrichedGoal,
+			DryRun:             true,
+			Message:            "Dry run: plan previewed, nothing saved",
+			Hint:               "Re-run without dry_run to save the plan and set it active.",
+			SemanticWarnings:   warnings.flat,
+			Warnings:           warnings.items,
+			SemanticErrors:     semanticErrors,
+			ValidationStats:    validationStats,
+			CodeIntelAvailable: codeIntelAvailable,
+		}, nil
+	}
+
 	return &GenerateResult{
-		Success:          true,
-		Tasks:            tasks,
-		PlanID:           planID,
-		Goal:             opts.Goal,
-		EnrichedGoal:     opts.EnrichedGoal,
-		Message:          "Plan generated successfully",
-		Hint:             "Use task action=next to begin working on the first task.",
-		SemanticWarnings: semanticWarnings,
-		SemanticErrors:   semanticErrors,
-		ValidationStats:  validationStats,
+		Success:            true,
+		Tasks:              tasks,
+		PlanID:             planID,
+		Goal:               opts.Goal,
+		EnrichedGoal:       opts.EnrichedGoal,
+		Message:            "Plan generated successfully",
+		Hint:               "Use task action=next to begin working on the first task.",
+		SemanticWarnings:   warnings.flat,
+		Warnings:           warnings.items,
+		SemanticErrors:     semanticErrors,
+		ValidationStats:    validationStats,
+		CodeIntelAvailable: codeIntelAvailable,
 	}, nil
 }
 
+// saveGeneratedPhaseTasks adds tasks from Generate to an existing phase,
+// marks the phase expanded and makes its plan active.
+func (a *PlanApp) saveGeneratedPhaseTasks(phase *task.Phase, tasks []task.Task) error {
+	for i := range tasks {
+		if err := a.Repo.CreateTask(&tasks[i]); err != nil {
+			return fmt.Errorf("save task %q: %w", tasks[i].Title, err)
+		}
+	}
+	if err := a.Repo.UpdatePhaseStatus(phase.ID, task.PhaseStatusExpanded); err != nil {
+		slog.Warn("failed to update phase status", "phase_id", phase.ID, "error", err)
+	}
+	attempt := task.ExpandAttempt{}
+	for _, t := range tasks {
+		attempt.Tasks = append(attempt.Tasks, t.Title)
+	}
+	if err := a.Repo.RecordPhaseExpandAttempt(phase.ID, attempt); err != nil {
+		slog.Warn("failed to record expand attempt", "phase_id", phase.ID, "error", err)
+	}
+	if err := a.activatePlan(phase.PlanID); err != nil {
+		return fmt.Errorf("tasks saved but plan not set active: %w", err)
+	}
+	return nil
+}
+
+// EnsureActivePlan returns the active plan. When none is active it returns a
+// *PlanError with code PlanErrorNoActivePlan and a hint on how to get one.
+func (a *PlanApp) EnsureActivePlan(_ context.Context) (*task.Plan, error) {
+	return ensureActivePlan(a.Repo)
+}
+
+// ensureActivePlan backs EnsureActivePlan for any repository that tracks plans.
+func ensureActivePlan(repo interface{ GetActivePlan() (*task.Plan, error) }) (*task.Plan, error) {
+	plan, err := repo.GetActivePlan()
+	if err != nil {
+		return nil, fmt.Errorf("get active plan: %w", err)
+	}
+	if plan == nil {
+		return nil, &PlanError{
+			Code: PlanErrorNoActivePlan,
+			Hint: "Create one with /taskwing:plan, or activate an existing plan.",
+		}
+	}
+	return plan, nil
+}
+
+// activatePlan makes planID the active plan.
+func (a *PlanApp) activatePlan(planID string) error {
+	return a.planService().SetActivePlan(planID)
+}
+
 // truncateString truncates a string to maxLen characters.
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -899,15 +1326,230 @@ func loadArchitectureMD(modelID string) string {
 	return string(content)
 }
 
-// Audit runs verification on a completed plan.
-func (a *PlanApp) Audit(_ context.Context, _ AuditOptions) (*AuditResult, error) {
+// Merge combines two plans: the source plan's phases and tasks move into the
+// target, dependencies between them are kept, and the source plan is deleted.
+// Returns the merged target plan with its phases and tasks.
+func (a *PlanApp) Merge(_ context.Context, targetID, sourceID string) (*task.Plan, error) {
+	targetID = strings.TrimSpace(targetID)
+	sourceID = strings.TrimSpace(sourceID)
+	if targetID == "" || sourceID == "" {
+		return nil, fmt.Errorf("target and source plan ids are required")
+	}
+
+	if err := a.Repo.MergePlans(targetID, sourceID); err != nil {
+		return nil, fmt.Errorf("merge plans: %w", err)
+	}
+
+	plan, err := a.Repo.GetPlanWithPhases(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("get merged plan: %w", err)
+	}
+	return plan, nil
+}
+
+// List returns one page of plans matching filter, newest first.
+// Archived plans are hidden unless requested via the filter.
+func (a *PlanApp) List(_ context.Context, filter task.PlanFilter) ([]task.Plan, error) {
+	plans, err := a.Repo.ListPlans(filter)
+	if err != nil {
+		return nil, fmt.Errorf("list plans: %w", err)
+	}
+	return plans, nil
+}
+
+// Archive hides a plan from default listings without deleting it.
+// An archived plan is no longer active.
+func (a *PlanApp) Archive(planID string) error {
+	return a.planService().ArchivePlan(planID)
+}
+
+// Unarchive restores an archived plan as a draft; activate it separately to resume work.
+func (a *PlanApp) Unarchive(planID string) error {
+	return a.planService().UnarchivePlan(planID)
+}
+
+// ReorderPhases sets the order of a plan's phases and recomputes their order
+// indexes. Every phase must be listed, so phases with expanded tasks are never
+// dropped from the plan.
+func (a *PlanApp) ReorderPhases(planID string, orderedPhaseIDs []string) ([]task.Phase, error) {
+	planID = strings.TrimSpace(planID)
+	if planID == "" {
+		return nil, fmt.Errorf("plan id is required")
+	}
+	if err := a.Repo.ReorderPhases(planID, orderedPhaseIDs); err != nil {
+		return nil, fmt.Errorf("reorder phases: %w", err)
+	}
+	return a.Repo.ListPhases(planID)
+}
+
+// InsertPhase adds a pending phase at index (0-based) and shifts later phases
+// down. Use index equal to the phase count to append.
+func (a *PlanApp) InsertPhase(planID string, index int, phase task.Phase) (*task.Phase, error) {
+	planID = strings.TrimSpace(planID)
+	if planID == "" {
+		return nil, fmt.Errorf("plan id is required")
+	}
+	if _, err := a.Repo.GetPlan(planID); err != nil {
+		return nil, fmt.Errorf("get plan: %w", err)
+	}
+
+	phase.ID = ""
+	phase.OrderIndex = max(index, 0)
+	phase.Status = task.PhaseStatusPending
+	phase.Tasks = nil
+	if err := phase.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.Repo.InsertPhase(planID, index, &phase); err != nil {
+		return nil, fmt.Errorf("insert phase: %w", err)
+	}
+	return &phase, nil
+}
+
+// planService returns a task service over the plan repository.
+func (a *PlanApp) planService() *task.Service {
+	memoryPath, _ := config.GetMemoryBasePath()
+	return task.NewService(a.Repo, memoryPath)
+}
+
+// Audit runs verification on a completed plan. When the tracked files are
+// unchanged since the last successful audit, that result is reused unless
+// opts.Force is set.
+func (a *PlanApp) Audit(ctx context.Context, opts AuditOptions) (*AuditResult, error) {
+	var plan *task.Plan
+	var err error
+	if opts.PlanID != "" {
+		plan, err = a.Repo.GetPlan(opts.PlanID)
+		if err != nil {
+			return &AuditResult{Success: false, Code: PlanErrorPlanNotFound, Message: fmt.Sprintf("Failed to get plan: %v", err)}, nil
+		}
+	} else {
+		plan, err = a.EnsureActivePlan(ctx)
+		if errors.Is(err, ErrNoActivePlan) {
+			return &AuditResult{Success: false, Code: PlanErrorNoActivePlan, Message: "No active plan to audit", Hint: ErrorHint(err)}, nil
+		}
+		if err != nil {
+			return &AuditResult{Success: false, Code: PlanErrorPersistence, Message: fmt.Sprintf("Failed to get active plan: %v", err)}, nil
+		}
+	}
+
+	// Best-effort: without a fingerprint every audit runs in full
+	filesHash, err := hashTrackedFiles(a.ctx.BasePath)
+	if err != nil {
+		slog.Debug("audit: hash tracked files", "error", err)
+	}
+
+	if !opts.Force && filesHash != "" && plan.LastAuditReport != "" {
+		var last task.AuditReport
+		if err := json.Unmarshal([]byte(plan.LastAuditReport), &last); err == nil &&
+			last.Succeeded() && last.TrackedFilesHash == filesHash {
+			return &AuditResult{
+				Success:        true,
+				PlanID:         plan.ID,
+				Status:         "verified",
+				PlanStatus:     plan.Status,
+				BuildPassed:    last.BuildPassed(),
+				TestsPassed:    last.TestsPassed(),
+				SemanticIssues: last.SemanticIssues,
+				FixesApplied:   last.FixesApplied,
+				RetryCount:     last.RetryCount,
+				Cached:         true,
+				Report:         &last,
+				Message:        "verified (cached)",
+				Hint:           "Tracked files are unchanged since the last successful audit. Use force to re-run.",
+			}, nil
+		}
+	}
+
+	// Tasks marked SkipAudit (e.g. documentation-only) neither widen the
+	// build/test scope nor block verification; a plan of only such tasks
+	// verifies even without an Auditor
+	auditPlan := *plan
+	auditPlan.Tasks = task.AuditableTasks(plan.Tasks)
+	skipped := len(plan.Tasks) - len(auditPlan.Tasks)
+
+	var report *task.AuditReport
+	message := ""
+	if skipped > 0 && len(auditPlan.Tasks) == 0 {
+		report = &task.AuditReport{Status: "passed", CompletedAt: time.Now()}
+		message = fmt.Sprintf("verified without build/test: all %d tasks are excluded from audit", skipped)
+	} else if a.Auditor == nil {
+		return &AuditResult{
+			Success: false,
+			PlanID:  plan.ID,
+			Code:    PlanErrorUnsupported,
+			Message: "No build or test command found for this project.",
+			Hint:    "Set audit.build_command and audit.test_command in .taskwing.yaml.",
+		}, nil
+	} else {
+		report, err = a.Auditor.Audit(ctx, &auditPlan, opts.AutoFix)
+		if err != nil {
+			return &AuditResult{Success: false, PlanID: plan.ID, Code: PlanErrorAgentFailed, Message: fmt.Sprintf("Audit failed: %v", err)}, nil
+		}
+		if skipped > 0 {
+			message = fmt.Sprintf("%d tasks excluded from audit", skipped)
+		}
+	}
+
+	status, planStatus := "needs_revision", task.PlanStatusNeedsRevision
+	if report.Succeeded() {
+		status, planStatus = "verified", task.PlanStatusVerified
+		report.TrackedFilesHash = filesHash
+	}
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("encode audit report: %w", err)
+	}
+	if err := a.Repo.UpdatePlanAuditReport(plan.ID, planStatus, string(reportJSON)); err != nil {
+		return &AuditResult{Success: false, PlanID: plan.ID, Code: PlanErrorPersistence, Message: fmt.Sprintf("Failed to save audit report: %v", err)}, nil
+	}
+
 	return &AuditResult{
-		Success: false,
-		Message: "Audit service has been removed. Use your AI tool's built-in verification instead.",
-		Hint:    "Run your project's build and test commands directly to verify plan completion.",
+		Success:        true,
+		PlanID:         plan.ID,
+		Status:         status,
+		PlanStatus:     planStatus,
+		BuildPassed:    report.BuildPassed(),
+		TestsPassed:    report.TestsPassed(),
+		SemanticIssues: report.SemanticIssues,
+		FixesApplied:   report.FixesApplied,
+		RetryCount:     report.RetryCount,
+		Report:         report,
+		Message:        message,
 	}, nil
 }
 
+// hashTrackedFiles fingerprints the paths and contents of the files git tracks
+// under dir. Returns an error if dir is not inside a git work tree.
+func hashTrackedFiles(dir string) (string, error) {
+	cmd := exec.Command("git", "ls-files", "-z")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git ls-files: %w", err)
+	}
+	files := strings.Split(strings.TrimRight(string(out), "\x00"), "\x00")
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, f := range files {
+		if f == "" {
+			continue
+		}
+		h.Write([]byte(f))
+		h.Write([]byte{0})
+		content, err := os.ReadFile(filepath.Join(dir, f))
+		if err != nil {
+			// Deleted or unreadable tracked files still change the fingerprint
+			h.Write([]byte("<missing>"))
+		} else {
+			h.Write(content)
+		}
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // parseQuestionsFromMetadata extracts questions from agent metadata,
 // handling both []string and []any (from JSON unmarshaling).
 func parseQuestionsFromMetadata(metadata map[string]any) []string {
@@ -993,47 +1635,12 @@ func (a *PlanApp) parseTasksFromMetadata(ctx context.Context, metadata map[strin
 	}
 	var pendingDeps []pendingDep
 
-	// Helper to generate IDs immediately so we can link them
-	genID := func() string {
-		return "task-" + uuid.New().String()[:8]
-	}
-
 	// Try typed slice first
 	if tasksRaw, ok := metadata["tasks"].([]impl.PlanningTask); ok {
 		for i, pt := range tasksRaw {
-			id := genID()
-			t := task.Task{
-				ID:                 id,
-				Title:              pt.Title,
-				Description:        pt.Description,
-				AcceptanceCriteria: pt.AcceptanceCriteria,
-				ValidationSteps:    pt.ValidationSteps,
-				Priority:           pt.Priority,
-				Status:             task.StatusPending,
-				AssignedAgent:      pt.AssignedAgent,
-				Complexity:         pt.Complexity,
-				Scope:              pt.Scope,
-				Keywords:           pt.Keywords,
-				ExpectedFiles:      pt.ExpectedFiles,
-			}
-			t.EnrichAIFields()
-
-			// Populate ContextSummary by executing ask queries
-			if a.TaskEnricher != nil && (len(t.SuggestedAskQueries) > 0 || t.Scope != "") {
-				if contextSummary, err := a.TaskEnricher(ctx, t.SuggestedAskQueries, t.Scope); err == nil && contextSummary != "" {
-					t.ContextSummary = contextSummary
-				}
-			}
-
-			// First task gets ARCHITECTURE.md for full architectural context
-			if i == 0 {
-				if archContent := loadArchitectureMD(a.ctx.LLMCfg.Model); archContent != "" {
-					t.ContextSummary = "## Architecture Overview\n" + archContent + "\n\n" + t.ContextSummary
-				}
-			}
-
+			t := a.planningTaskToTask(ctx, pt, i)
 			tasks = append(tasks, t)
-			titleToID[pt.Title] = id
+			titleToID[pt.Title] = t.ID
 
 			if len(pt.Dependencies) > 0 {
 				pendingDeps = append(pendingDeps, pendingDep{taskIdx: i, titles: pt.Dependencies})
@@ -1059,11 +1666,11 @@ func (a *PlanApp) parseTasksFromMetadata(ctx context.Context, metadata map[strin
 					}
 				}
 
-				var validation []string
+				var validation []task.ValidationStep
 				if vs, ok := tm["validation_steps"].([]any); ok {
 					for _, v := range vs {
-						if vs, ok := v.(string); ok {
-							validation = append(validation, vs)
+						if step, ok := task.ParseValidationStep(v); ok {
+							validation = append(validation, step)
 						}
 					}
 				}
@@ -1095,7 +1702,8 @@ func (a *PlanApp) parseTasksFromMetadata(ctx context.Context, metadata map[strin
 					}
 				}
 
-				id := genID()
+				// Generate IDs immediately so dependencies can link to them
+				id := a.newTaskID()
 				newTask := task.Task{
 					ID:                 id,
 					Title:              title,
@@ -1109,15 +1717,12 @@ func (a *PlanApp) parseTasksFromMetadata(ctx context.Context, metadata map[strin
 					Scope:              scope,
 					Keywords:           keywords,
 					ExpectedFiles:      expectedFiles,
+					SkipAudit:          task.HasSkipAuditKeyword(keywords),
 				}
 				newTask.EnrichAIFields()
 
 				// Populate ContextSummary by executing ask queries
-				if a.TaskEnricher != nil && (len(newTask.SuggestedAskQueries) > 0 || newTask.Scope != "") {
-					if contextSummary, err := a.TaskEnricher(ctx, newTask.SuggestedAskQueries, newTask.Scope); err == nil && contextSummary != "" {
-						newTask.ContextSummary = contextSummary
-					}
-				}
+				a.enrichTask(ctx, &newTask)
 
 				// First task gets ARCHITECTURE.md for full architectural context
 				if i == 0 {
@@ -1148,6 +1753,39 @@ func (a *PlanApp) parseTasksFromMetadata(ctx context.Context, metadata map[strin
 	return tasks
 }
 
+// planningTaskToTask converts a planning agent task into a pending task with
+// AI fields and early-bound context. index is the task's position in the plan.
+// Dependencies are left for the caller to resolve.
+func (a *PlanApp) planningTaskToTask(ctx context.Context, pt impl.PlanningTask, index int) task.Task {
+	t := task.Task{
+		ID:                 a.newTaskID(),
+		Title:              pt.Title,
+		Description:        pt.Description,
+		AcceptanceCriteria: pt.AcceptanceCriteria,
+		ValidationSteps:    pt.ValidationSteps,
+		Priority:           pt.Priority,
+		Status:             task.StatusPending,
+		AssignedAgent:      pt.AssignedAgent,
+		Complexity:         pt.Complexity,
+		Scope:              pt.Scope,
+		Keywords:           pt.Keywords,
+		ExpectedFiles:      pt.ExpectedFiles,
+		SkipAudit:          task.HasSkipAuditKeyword(pt.Keywords),
+	}
+	t.EnrichAIFields()
+
+	// Populate ContextSummary by executing ask queries
+	a.enrichTask(ctx, &t)
+
+	// First task gets ARCHITECTURE.md for full architectural context
+	if index == 0 {
+		if archContent := loadArchitectureMD(a.ctx.LLMCfg.Model); archContent != "" {
+			t.ContextSummary = "## Architecture Overview\n" + archContent + "\n\n" + t.ContextSummary
+		}
+	}
+	return t
+}
+
 // === Interactive Planning (Phase-based workflow) ===
 
 // PhaseGoalDecomposer defines the interface for the decomposition agent.
@@ -1172,12 +1810,13 @@ type DecomposeOptions struct {
 
 // DecomposeResult contains the result of plan decomposition.
 type DecomposeResult struct {
-	Success   bool         `json:"success"`
-	PlanID    string       `json:"plan_id,omitempty"`
-	Phases    []task.Phase `json:"phases,omitempty"`
-	Rationale string       `json:"rationale,omitempty"`
-	Message   string       `json:"message,omitempty"`
-	Hint      string       `json:"hint,omitempty"`
+	Success   bool          `json:"success"`
+	PlanID    string        `json:"plan_id,omitempty"`
+	Phases    []task.Phase  `json:"phases,omitempty"`
+	Rationale string        `json:"rationale,omitempty"`
+	Code      PlanErrorCode `json:"code,omitempty"`
+	Message   string        `json:"message,omitempty"`
+	Hint      string        `json:"hint,omitempty"`
 }
 
 // ExpandOptions configures the behavior of phase expansion.
@@ -1185,21 +1824,22 @@ type ExpandOptions struct {
 	PlanID     string // Required: plan containing the phase
 	PhaseID    string // Required if PhaseIndex < 0
 	PhaseIndex int    // 0-based index (use -1 to indicate PhaseID should be used)
-	Feedback   string // Optional: regeneration hint
+	Feedback   string // Optional: regeneration hint; regenerates an already expanded phase
 }
 
 // ExpandResult contains the result of phase expansion.
 type ExpandResult struct {
-	Success         bool        `json:"success"`
-	PlanID          string      `json:"plan_id,omitempty"`
-	PhaseID         string      `json:"phase_id,omitempty"`
-	PhaseTitle      string      `json:"phase_title,omitempty"`
-	Tasks           []task.Task `json:"tasks,omitempty"`
-	Rationale       string      `json:"rationale,omitempty"`
-	RemainingPhases int         `json:"remaining_phases,omitempty"`
-	NextPhaseTitle  string      `json:"next_phase_title,omitempty"`
-	Message         string      `json:"message,omitempty"`
-	Hint            string      `json:"hint,omitempty"`
+	Success         bool          `json:"success"`
+	PlanID          string        `json:"plan_id,omitempty"`
+	PhaseID         string        `json:"phase_id,omitempty"`
+	PhaseTitle      string        `json:"phase_title,omitempty"`
+	Tasks           []task.Task   `json:"tasks,omitempty"`
+	Rationale       string        `json:"rationale,omitempty"`
+	RemainingPhases int           `json:"remaining_phases,omitempty"`
+	NextPhaseTitle  string        `json:"next_phase_title,omitempty"`
+	Code            PlanErrorCode `json:"code,omitempty"`
+	Message         string        `json:"message,omitempty"`
+	Hint            string        `json:"hint,omitempty"`
 }
 
 // FinalizeOptions configures the behavior of plan finalization.
@@ -1209,13 +1849,14 @@ type FinalizeOptions struct {
 
 // FinalizeResult contains the result of plan finalization.
 type FinalizeResult struct {
-	Success     bool   `json:"success"`
-	PlanID      string `json:"plan_id,omitempty"`
-	Status      string `json:"status,omitempty"`
-	TotalPhases int    `json:"total_phases,omitempty"`
-	TotalTasks  int    `json:"total_tasks,omitempty"`
-	Message     string `json:"message,omitempty"`
-	Hint        string `json:"hint,omitempty"`
+	Success     bool          `json:"success"`
+	PlanID      string        `json:"plan_id,omitempty"`
+	Status      string        `json:"status,omitempty"`
+	TotalPhases int           `json:"total_phases,omitempty"`
+	TotalTasks  int           `json:"total_tasks,omitempty"`
+	Code        PlanErrorCode `json:"code,omitempty"`
+	Message     string        `json:"message,omitempty"`
+	Hint        string        `json:"hint,omitempty"`
 }
 
 // Decompose breaks an enriched goal into high-level phases (Stage 2).
@@ -1225,6 +1866,7 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 	if opts.EnrichedGoal == "" {
 		return &DecomposeResult{
 			Success: false,
+			Code:    PlanErrorNoEnrichedGoal,
 			Message: "enriched_goal is required (run Clarify first)",
 		}, nil
 	}
@@ -1241,6 +1883,7 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 		if err != nil {
 			return &DecomposeResult{
 				Success: false,
+				Code:    PlanErrorPlanNotFound,
 				Message: fmt.Sprintf("Failed to get plan: %v", err),
 			}, nil
 		}
@@ -1255,6 +1898,7 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 		if err := repo.CreatePlan(plan); err != nil {
 			return &DecomposeResult{
 				Success: false,
+				Code:    PlanErrorPersistence,
 				Message: fmt.Sprintf("Failed to create plan: %v", err),
 			}, nil
 		}
@@ -1285,6 +1929,7 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 	if err != nil {
 		return &DecomposeResult{
 			Success: false,
+			Code:    PlanErrorAgentFailed,
 			PlanID:  plan.ID,
 			Message: fmt.Sprintf("Decomposition agent failed: %v", err),
 		}, nil
@@ -1292,6 +1937,7 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 	if output.Error != nil {
 		return &DecomposeResult{
 			Success: false,
+			Code:    PlanErrorAgentFailed,
 			PlanID:  plan.ID,
 			Message: fmt.Sprintf("Decomposition agent error: %v", output.Error),
 		}, nil
@@ -1301,6 +1947,7 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 	if len(output.Findings) == 0 {
 		return &DecomposeResult{
 			Success: false,
+			Code:    PlanErrorAgentFailed,
 			PlanID:  plan.ID,
 			Message: "No findings from decomposition agent",
 		}, nil
@@ -1313,10 +1960,19 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 	if len(phases) == 0 {
 		return &DecomposeResult{
 			Success: false,
+			Code:    PlanErrorNoPhasesGenerated,
 			PlanID:  plan.ID,
 			Message: "No phases generated",
 		}, nil
 	}
+	if err := task.VerifyPhaseDAG(phases); err != nil {
+		return &DecomposeResult{
+			Success: false,
+			Code:    PlanErrorAgentFailed,
+			PlanID:  plan.ID,
+			Message: fmt.Sprintf("Invalid phase dependencies: %v", err),
+		}, nil
+	}
 
 	// Save phases to database
 	for i := range phases {
@@ -1337,6 +1993,7 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 		}
 		return &DecomposeResult{
 			Success: false,
+			Code:    PlanErrorPersistence,
 			PlanID:  plan.ID,
 			Message: fmt.Sprintf("Failed to save phases: %v", err),
 		}, nil
@@ -1365,10 +2022,13 @@ func (a *PlanApp) Decompose(ctx context.Context, opts DecomposeOptions) (*Decomp
 
 // Expand generates detailed tasks for a single phase (Stage 3).
 // Call this for each phase after Decompose, in order.
+// Expanding an already expanded phase with feedback replaces its tasks; every
+// attempt is recorded on the phase and summarized for the agent on re-expand.
 func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult, error) {
 	if opts.PlanID == "" {
 		return &ExpandResult{
 			Success: false,
+			Code:    PlanErrorInvalidInput,
 			Message: "plan_id is required",
 		}, nil
 	}
@@ -1381,6 +2041,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 	if err != nil {
 		return &ExpandResult{
 			Success: false,
+			Code:    PlanErrorPlanNotFound,
 			Message: fmt.Sprintf("Failed to get plan: %v", err),
 		}, nil
 	}
@@ -1388,6 +2049,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 	if len(plan.Phases) == 0 {
 		return &ExpandResult{
 			Success: false,
+			Code:    PlanErrorNoPhases,
 			PlanID:  plan.ID,
 			Message: "Plan has no phases. Run decompose first.",
 		}, nil
@@ -1399,6 +2061,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 		if opts.PhaseIndex >= len(plan.Phases) {
 			return &ExpandResult{
 				Success: false,
+				Code:    PlanErrorPhaseNotFound,
 				PlanID:  plan.ID,
 				Message: fmt.Sprintf("Phase index %d out of range (0-%d)", opts.PhaseIndex, len(plan.Phases)-1),
 			}, nil
@@ -1414,24 +2077,54 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 		if phase == nil {
 			return &ExpandResult{
 				Success: false,
+				Code:    PlanErrorPhaseNotFound,
 				PlanID:  plan.ID,
 				Message: fmt.Sprintf("Phase not found: %s", opts.PhaseID),
 			}, nil
 		}
 	}
 
-	// Check if already expanded
+	// Check if already expanded; feedback asks for a regeneration
+	regenerate := false
 	if phase.Status == task.PhaseStatusExpanded {
-		existingTasks, _ := repo.ListTasksByPhase(phase.ID)
-		return &ExpandResult{
-			Success:    true,
-			PlanID:     plan.ID,
-			PhaseID:    phase.ID,
-			PhaseTitle: phase.Title,
-			Tasks:      existingTasks,
-			Message:    "Phase already expanded",
-			Hint:       "Use plan finalize when all phases are expanded.",
-		}, nil
+		if strings.TrimSpace(opts.Feedback) == "" {
+			existingTasks, _ := repo.ListTasksByPhase(phase.ID)
+			return &ExpandResult{
+				Success:    true,
+				PlanID:     plan.ID,
+				PhaseID:    phase.ID,
+				PhaseTitle: phase.Title,
+				Tasks:      existingTasks,
+				Message:    "Phase already expanded",
+				Hint:       "Use plan finalize when all phases are expanded, or expand again with feedback to regenerate.",
+			}, nil
+		}
+		// Regenerating replaces the phase's tasks, so refuse once work on them has begun
+		existingTasks, err := repo.ListTasksByPhase(phase.ID)
+		if err != nil {
+			return &ExpandResult{
+				Success: false,
+				Code:    PlanErrorPersistence,
+				PlanID:  plan.ID,
+				PhaseID: phase.ID,
+				Message: fmt.Sprintf("Failed to list phase tasks: %v", err),
+			}, nil
+		}
+		for _, t := range existingTasks {
+			if taskStarted(t.Status) {
+				return &ExpandResult{
+					Success:    false,
+					Code:       PlanErrorPhaseStarted,
+					PlanID:     plan.ID,
+					PhaseID:    phase.ID,
+					PhaseTitle: phase.Title,
+					Tasks:      existingTasks,
+					Message:    fmt.Sprintf("Phase has started tasks (%s is %s); it cannot be regenerated", t.Title, t.Status),
+					Hint:       "Add follow-up tasks for the phase instead of expanding it again.",
+				}, nil
+			}
+		}
+		regenerate = true
 	}
 
 	// Fetch context from knowledge graph
@@ -1444,7 +2137,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 	}
 
 	// Create and run ExpandAgent
-	expandAgent := impl.NewExpandAgent(llmCfg)
+	expandAgent := a.ExpanderFactory(llmCfg)
 	defer func() { _ = expandAgent.Close() }()
 
 	input := core.Input{
@@ -1454,6 +2147,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 			"enriched_goal":     plan.EnrichedGoal,
 			"context":           contextStr,
 			"feedback":          opts.Feedback,
+			"history":           summarizeExpandAttempts(phase.ExpandAttempts),
 		},
 	}
 
@@ -1461,6 +2155,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 	if err != nil {
 		return &ExpandResult{
 			Success: false,
+			Code:    PlanErrorAgentFailed,
 			PlanID:  plan.ID,
 			PhaseID: phase.ID,
 			Message: fmt.Sprintf("Expand agent failed: %v", err),
@@ -1469,6 +2164,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 	if output.Error != nil {
 		return &ExpandResult{
 			Success: false,
+			Code:    PlanErrorAgentFailed,
 			PlanID:  plan.ID,
 			PhaseID: phase.ID,
 			Message: fmt.Sprintf("Expand agent error: %v", output.Error),
@@ -1479,6 +2175,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 	if len(output.Findings) == 0 {
 		return &ExpandResult{
 			Success: false,
+			Code:    PlanErrorAgentFailed,
 			PlanID:  plan.ID,
 			PhaseID: phase.ID,
 			Message: "No findings from expand agent",
@@ -1492,12 +2189,34 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 	if len(tasks) == 0 {
 		return &ExpandResult{
 			Success: false,
+			Code:    PlanErrorNoTasksGenerated,
 			PlanID:  plan.ID,
 			PhaseID: phase.ID,
 			Message: "No tasks generated for phase",
 		}, nil
 	}
 
+	// Replace the rejected tasks of a regenerated phase, none of which has started
+	if regenerate {
+		oldTasks, err := repo.ListTasksByPhase(phase.ID)
+		for i := 0; err == nil && i < len(oldTasks); i++ {
+			if taskStarted(oldTasks[i].Status) {
+				err = fmt.Errorf("task %s started during regeneration", oldTasks[i].ID)
+				break
+			}
+			err = repo.DeleteTask(oldTasks[i].ID)
+		}
+		if err != nil {
+			return &ExpandResult{
+				Success: false,
+				Code:    PlanErrorPersistence,
+				PlanID:  plan.ID,
+				PhaseID: phase.ID,
+				Message: fmt.Sprintf("Failed to remove previous tasks: %v", err),
+			}, nil
+		}
+	}
+
 	// Link tasks to phase and save
 	for i := range tasks {
 		tasks[i].PlanID = plan.ID
@@ -1505,6 +2224,7 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 		if err := repo.CreateTask(&tasks[i]); err != nil {
 			return &ExpandResult{
 				Success: false,
+				Code:    PlanErrorPersistence,
 				PlanID:  plan.ID,
 				PhaseID: phase.ID,
 				Message: fmt.Sprintf("Failed to save task: %v", err),
@@ -1517,6 +2237,14 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 		slog.Warn("failed to update phase status", "phase_id", phase.ID, "error", err)
 	}
 
+	attempt := task.ExpandAttempt{Feedback: strings.TrimSpace(opts.Feedback)}
+	for _, t := range tasks {
+		attempt.Tasks = append(attempt.Tasks, t.Title)
+	}
+	if err := repo.RecordPhaseExpandAttempt(phase.ID, attempt); err != nil {
+		slog.Warn("failed to record expand attempt", "phase_id", phase.ID, "error", err)
+	}
+
 	// Calculate remaining phases
 	remainingPhases := 0
 	var nextPhaseTitle string
@@ -1553,12 +2281,46 @@ func (a *PlanApp) Expand(ctx context.Context, opts ExpandOptions) (*ExpandResult
 	}, nil
 }
 
+// taskStarted reports whether work on a task has begun, i.e. it has left the
+// states a task waits in before anyone picks it up.
+func taskStarted(status task.TaskStatus) bool {
+	switch status {
+	case task.StatusDraft, task.StatusPending, task.StatusReady, task.StatusBlocked:
+		return false
+	}
+	return true
+}
+
+// maxExpandHistory caps how many prior expansion attempts are summarized in the prompt.
+const maxExpandHistory = 3
+
+// summarizeExpandAttempts renders the most recent expansion attempts for the expand agent.
+func summarizeExpandAttempts(attempts []task.ExpandAttempt) string {
+	if len(attempts) == 0 {
+		return ""
+	}
+	start := max(len(attempts)-maxExpandHistory, 0)
+	var sb strings.Builder
+	for i := start; i < len(attempts); i++ {
+		at := attempts[i]
+		sb.WriteString(fmt.Sprintf("Attempt %d:\n", i+1))
+		if at.Feedback != "" {
+			sb.WriteString(fmt.Sprintf("- Feedback: %s\n", at.Feedback))
+		}
+		if len(at.Tasks) > 0 {
+			sb.WriteString(fmt.Sprintf("- Tasks: %s\n", strings.Join(at.Tasks, "; ")))
+		}
+	}
+	return sb.String()
+}
+
 // Finalize completes the interactive plan generation (Stage 4).
 // Sets the plan as active and clears draft state.
 func (a *PlanApp) Finalize(ctx context.Context, opts FinalizeOptions) (*FinalizeResult, error) {
 	if opts.PlanID == "" {
 		return &FinalizeResult{
 			Success: false,
+			Code:    PlanErrorInvalidInput,
 			Message: "plan_id is required",
 		}, nil
 	}
@@ -1570,6 +2332,7 @@ func (a *PlanApp) Finalize(ctx context.Context, opts FinalizeOptions) (*Finalize
 	if err != nil {
 		return &FinalizeResult{
 			Success: false,
+			Code:    PlanErrorPlanNotFound,
 			Message: fmt.Sprintf("Failed to get plan: %v", err),
 		}, nil
 	}
@@ -1589,6 +2352,7 @@ func (a *PlanApp) Finalize(ctx context.Context, opts FinalizeOptions) (*Finalize
 	if pendingCount > 0 {
 		return &FinalizeResult{
 			Success:     false,
+			Code:        PlanErrorPhasesPending,
 			PlanID:      plan.ID,
 			TotalPhases: len(plan.Phases),
 			Message:     fmt.Sprintf("%d phases still pending expansion", pendingCount),
@@ -1601,6 +2365,7 @@ func (a *PlanApp) Finalize(ctx context.Context, opts FinalizeOptions) (*Finalize
 	if err != nil {
 		return &FinalizeResult{
 			Success: false,
+			Code:    PlanErrorPersistence,
 			PlanID:  plan.ID,
 			Message: fmt.Sprintf("Failed to list tasks: %v", err),
 		}, nil
@@ -1610,6 +2375,7 @@ func (a *PlanApp) Finalize(ctx context.Context, opts FinalizeOptions) (*Finalize
 	if err := repo.SetActivePlan(plan.ID); err != nil {
 		return &FinalizeResult{
 			Success: false,
+			Code:    PlanErrorPersistence,
 			PlanID:  plan.ID,
 			Message: fmt.Sprintf("Failed to activate plan: %v", err),
 		}, nil
@@ -1643,6 +2409,7 @@ func (a *PlanApp) parsePhasesFromMetadata(metadata map[string]any) []task.Phase
 				Rationale:     po.Rationale,
 				ExpectedTasks: po.ExpectedTasks,
 				Status:        task.PhaseStatusPending,
+				DependsOn:     po.Dependencies, // titles until resolvePhaseDependencies
 			}
 			if err := phase.Validate(); err != nil {
 				slog.Warn("skipping invalid phase from LLM", "index", i, "error", err)
@@ -1650,7 +2417,7 @@ func (a *PlanApp) parsePhasesFromMetadata(metadata map[string]any) []task.Phase
 			}
 			phases = append(phases, phase)
 		}
-		return phases
+		return resolvePhaseDependencies(phases)
 	}
 
 	// Handle []any from JSON unmarshaling
@@ -1661,6 +2428,14 @@ func (a *PlanApp) parsePhasesFromMetadata(metadata map[string]any) []task.Phase
 				desc, _ := pm["description"].(string)
 				rationale, _ := pm["rationale"].(string)
 				expectedTasks, _ := pm["expected_tasks"].(float64)
+				var deps []string
+				if depsAny, ok := pm["dependencies"].([]any); ok {
+					for _, d := range depsAny {
+						if title, ok := d.(string); ok {
+							deps = append(deps, title)
+						}
+					}
+				}
 
 				phase := task.Phase{
 					Title:         title,
@@ -1668,6 +2443,7 @@ func (a *PlanApp) parsePhasesFromMetadata(metadata map[string]any) []task.Phase
 					Rationale:     rationale,
 					ExpectedTasks: int(expectedTasks),
 					Status:        task.PhaseStatusPending,
+					DependsOn:     deps, // titles until resolvePhaseDependencies
 				}
 				if err := phase.Validate(); err != nil {
 					slog.Warn("skipping invalid phase from LLM", "index", i, "error", err)
@@ -1678,5 +2454,71 @@ func (a *PlanApp) parsePhasesFromMetadata(metadata map[string]any) []task.Phase
 		}
 	}
 
+	return resolvePhaseDependencies(phases)
+}
+
+// dedupGeneratedTasks reports near-duplicate tasks as warnings and, when
+// merge is set, removes them with task.MergeDuplicateTasks. Warnings point at
+// the task positions in the returned slice.
+func dedupGeneratedTasks(tasks []task.Task, merge bool, warnings *warningList) []task.Task {
+	dups := task.FindDuplicateTasks(tasks, task.DefaultDuplicateThreshold)
+	if len(dups) == 0 {
+		return tasks
+	}
+	slog.Debug("planner emitted duplicate tasks", "count", len(dups), "merge", merge)
+
+	var merged []task.Task
+	if merge {
+		merged = task.MergeDuplicateTasks(tasks, dups)
+		// Merging drops cyclic inherited dependencies; if the plan still is not
+		// a DAG, report the duplicates instead
+		if err := task.VerifyDAG(merged); err != nil {
+			slog.Debug("duplicate merge skipped", "error", err)
+			merge = false
+		}
+	}
+
+	if !merge {
+		for _, d := range dups {
+			warnings.add(WarningCategoryDuplicate, d.Index, fmt.Sprintf("Near-duplicate of task %d %q (%.0f%% similar)",
+				d.DuplicateOf+1, tasks[d.DuplicateOf].Title, d.Similarity*100))
+		}
+		return tasks
+	}
+
+	for _, d := range dups {
+		index := slices.IndexFunc(merged, func(t task.Task) bool { return t.ID == tasks[d.DuplicateOf].ID })
+		warnings.add(WarningCategoryDuplicate, index, fmt.Sprintf("Merged near-duplicate task %q into this task (%.0f%% similar)",
+			tasks[d.Index].Title, d.Similarity*100))
+	}
+	return merged
+}
+
+// resolvePhaseDependencies gives each phase an ID and rewrites its DependsOn
+// from the phase titles the agent returns to those IDs. Titles match
+// case-insensitively; references to unknown phases are dropped with a warning.
+// Cycles are left for task.VerifyPhaseDAG to report.
+func resolvePhaseDependencies(phases []task.Phase) []task.Phase {
+	idByTitle := make(map[string]string, len(phases))
+	for i := range phases {
+		if phases[i].ID == "" {
+			phases[i].ID = "phase-" + uuid.New().String()[:8]
+		}
+		idByTitle[strings.ToLower(strings.TrimSpace(phases[i].Title))] = phases[i].ID
+	}
+	for i := range phases {
+		var ids []string
+		for _, title := range phases[i].DependsOn {
+			id, ok := idByTitle[strings.ToLower(strings.TrimSpace(title))]
+			if !ok {
+				slog.Warn("dropping unknown phase dependency from LLM", "phase", phases[i].Title, "dependency", title)
+				continue
+			}
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		phases[i].DependsOn = ids
+	}
 	return phases
 }
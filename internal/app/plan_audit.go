@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+// auditOutputLimit caps how much output of each audit command the report keeps.
+const auditOutputLimit = 10000
+
+// manifestAuditCommands are the build and test commands used for a project
+// with the given manifest when none are configured, in detection order.
+var manifestAuditCommands = []struct{ manifest, build, test string }{
+	{"go.mod", "go build ./...", "go test ./..."},
+	{"Cargo.toml", "cargo build", "cargo test"},
+	{"package.json", "npm run build --if-present", "npm test"},
+}
+
+// CommandAuditor is the PlanAuditor that runs the project's build and test
+// commands in Dir. It reports no semantic issues and cannot apply fixes, so
+// autoFix is ignored.
+type CommandAuditor struct {
+	Dir          string
+	BuildCommand string // Shell command; empty skips the build step
+	TestCommand  string // Shell command; empty skips the test step
+}
+
+// NewCommandAuditor returns an auditor for the project at dir. Configured
+// audit commands take precedence over those detected from the manifest.
+// Returns nil when dir is empty or no command is configured or detected.
+func NewCommandAuditor(dir string) *CommandAuditor {
+	if dir == "" {
+		return nil
+	}
+	build, test := config.LoadAuditCommands()
+	if build == "" && test == "" {
+		for _, m := range manifestAuditCommands {
+			if _, err := os.Stat(filepath.Join(dir, m.manifest)); err == nil {
+				build, test = m.build, m.test
+				break
+			}
+		}
+	}
+	if build == "" && test == "" {
+		return nil
+	}
+	return &CommandAuditor{Dir: dir, BuildCommand: build, TestCommand: test}
+}
+
+// Audit runs the build and then the test command. Both always run, so the
+// report says which of them failed.
+func (c *CommandAuditor) Audit(ctx context.Context, _ *task.Plan, _ bool) (*task.AuditReport, error) {
+	report := &task.AuditReport{Status: "passed"}
+	var err error
+	if report.BuildOutput, report.BuildFailed, err = c.run(ctx, c.BuildCommand); err != nil {
+		return nil, fmt.Errorf("run build command: %w", err)
+	}
+	if report.TestOutput, report.TestsFailed, err = c.run(ctx, c.TestCommand); err != nil {
+		return nil, fmt.Errorf("run test command: %w", err)
+	}
+	if report.BuildFailed || report.TestsFailed {
+		report.Status = "failed"
+	}
+	report.CompletedAt = time.Now()
+	return report, nil
+}
+
+// run executes command through the shell and returns the tail of its
+// combined output. A non-zero exit is a failed check; err is set only when
+// the command could not run to completion.
+func (c *CommandAuditor) run(ctx context.Context, command string) (output string, failed bool, err error) {
+	if command == "" {
+		return "", false, nil
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = c.Dir
+	out, runErr := cmd.CombinedOutput()
+
+	output = string(out)
+	if len(output) > auditOutputLimit {
+		// Failures are reported last, so keep the end
+		output = "... [truncated]\n" + output[len(output)-auditOutputLimit:]
+	}
+	var exitErr *exec.ExitError
+	if runErr == nil {
+		return output, false, nil
+	}
+	if errors.As(runErr, &exitErr) && ctx.Err() == nil {
+		return output, true, nil
+	}
+	return output, false, runErr
+}
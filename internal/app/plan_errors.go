@@ -0,0 +1,125 @@
+package app
+
+import "errors"
+
+// Sentinel errors for plan lifecycle failures.
+// Result structs keep a human-readable Message; use Code to branch programmatically.
+var (
+	ErrGoalRequired           = errors.New("goal is required")
+	ErrNoActivePlan           = errors.New("no active plan")
+	ErrPlanNotFound           = errors.New("plan not found")
+	ErrPhaseNotFound          = errors.New("phase not found")
+	ErrNoPhases               = errors.New("plan has no phases")
+	ErrPhasesPending          = errors.New("phases still pending expansion")
+	ErrPhaseStarted           = errors.New("phase has started tasks")
+	ErrPhaseExpanded          = errors.New("phase already expanded")
+	ErrNoEnrichedGoal         = errors.New("enriched goal is required")
+	ErrClarifySessionNotFound = errors.New("clarify session not found")
+	ErrClarifyIncomplete      = errors.New("clarification is not complete")
+	ErrInvalidPlanInput       = errors.New("invalid plan input")
+	ErrAgentFailed            = errors.New("planning agent failed")
+	ErrNoTasksGenerated       = errors.New("no tasks generated")
+	ErrNoPhasesGenerated      = errors.New("no phases generated")
+	ErrTaskValidation         = errors.New("task validation failed")
+	ErrSemanticValidation     = errors.New("semantic validation failed")
+	ErrPlanPersistence        = errors.New("failed to persist plan")
+	ErrUnsupported            = errors.New("operation not supported")
+)
+
+// PlanErrorCode is a stable, machine-readable identifier for a plan failure.
+// It is set on result structs whenever Success is false.
+type PlanErrorCode string
+
+const (
+	PlanErrorGoalRequired           PlanErrorCode = "goal_required"
+	PlanErrorNoActivePlan           PlanErrorCode = "no_active_plan"
+	PlanErrorPlanNotFound           PlanErrorCode = "plan_not_found"
+	PlanErrorPhaseNotFound          PlanErrorCode = "phase_not_found"
+	PlanErrorNoPhases               PlanErrorCode = "no_phases"
+	PlanErrorPhasesPending          PlanErrorCode = "phases_pending"
+	PlanErrorPhaseStarted           PlanErrorCode = "phase_started"
+	PlanErrorPhaseExpanded          PlanErrorCode = "phase_expanded"
+	PlanErrorNoEnrichedGoal         PlanErrorCode = "no_enriched_goal"
+	PlanErrorClarifySessionNotFound PlanErrorCode = "clarify_session_not_found"
+	PlanErrorClarifyIncomplete      PlanErrorCode = "clarify_incomplete"
+	PlanErrorInvalidInput           PlanErrorCode = "invalid_input"
+	PlanErrorAgentFailed            PlanErrorCode = "agent_failed"
+	PlanErrorNoTasksGenerated       PlanErrorCode = "no_tasks_generated"
+	PlanErrorNoPhasesGenerated      PlanErrorCode = "no_phases_generated"
+	PlanErrorTaskValidation         PlanErrorCode = "task_validation_failed"
+	PlanErrorSemanticValidation     PlanErrorCode = "semantic_validation_failed"
+	PlanErrorPersistence            PlanErrorCode = "persistence_failed"
+	PlanErrorUnsupported            PlanErrorCode = "unsupported"
+)
+
+var planErrorsByCode = map[PlanErrorCode]error{
+	PlanErrorGoalRequired:           ErrGoalRequired,
+	PlanErrorNoActivePlan:           ErrNoActivePlan,
+	PlanErrorPlanNotFound:           ErrPlanNotFound,
+	PlanErrorPhaseNotFound:          ErrPhaseNotFound,
+	PlanErrorNoPhases:               ErrNoPhases,
+	PlanErrorPhasesPending:          ErrPhasesPending,
+	PlanErrorPhaseStarted:           ErrPhaseStarted,
+	PlanErrorPhaseExpanded:          ErrPhaseExpanded,
+	PlanErrorNoEnrichedGoal:         ErrNoEnrichedGoal,
+	PlanErrorClarifySessionNotFound: ErrClarifySessionNotFound,
+	PlanErrorClarifyIncomplete:      ErrClarifyIncomplete,
+	PlanErrorInvalidInput:           ErrInvalidPlanInput,
+	PlanErrorAgentFailed:            ErrAgentFailed,
+	PlanErrorNoTasksGenerated:       ErrNoTasksGenerated,
+	PlanErrorNoPhasesGenerated:      ErrNoPhasesGenerated,
+	PlanErrorTaskValidation:         ErrTaskValidation,
+	PlanErrorSemanticValidation:     ErrSemanticValidation,
+	PlanErrorPersistence:            ErrPlanPersistence,
+	PlanErrorUnsupported:            ErrUnsupported,
+}
+
+// Err returns the sentinel error for the code, or nil for an empty or unknown code.
+func (c PlanErrorCode) Err() error {
+	return planErrorsByCode[c]
+}
+
+// PlanErrorCodeOf returns the code for a sentinel error (or an error wrapping one).
+// Returns "" if err is not a plan sentinel.
+func PlanErrorCodeOf(err error) PlanErrorCode {
+	if err == nil {
+		return ""
+	}
+	for code, sentinel := range planErrorsByCode {
+		if errors.Is(err, sentinel) {
+			return code
+		}
+	}
+	return ""
+}
+
+// PlanError is a plan failure carrying a remediation hint for the caller.
+// It unwraps to the code's sentinel, so errors.Is and PlanErrorCodeOf work on it.
+type PlanError struct {
+	Code PlanErrorCode
+	Hint string
+}
+
+func (e *PlanError) Error() string {
+	msg := string(e.Code)
+	if err := e.Code.Err(); err != nil {
+		msg = err.Error()
+	}
+	if e.Hint == "" {
+		return msg
+	}
+	return msg + ": " + e.Hint
+}
+
+func (e *PlanError) Unwrap() error {
+	return e.Code.Err()
+}
+
+// ErrorHint returns the remediation hint carried by err, or "".
+func ErrorHint(err error) string {
+	var pe *PlanError
+	if errors.As(err, &pe) {
+		return pe.Hint
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/agents/impl"
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+// generateStreaming creates a draft plan up front and saves each task as the planner
+// emits it. Dependencies are resolved once the stream ends, because a task may
+// depend on one that has not been generated yet. The plan only becomes active after
+// a complete stream; on failure the saved tasks stay attached to the draft plan.
+//
+// Streaming trades whole-plan semantic validation and path correction for
+// incremental persistence: tasks are checked individually with Task.Validate.
+func (a *PlanApp) generateStreaming(ctx context.Context, planner StreamingTaskPlanner, input core.Input, opts GenerateOptions) *GenerateResult {
+	plan := &task.Plan{
+		Goal:         opts.Goal,
+		EnrichedGoal: opts.EnrichedGoal,
+		Status:       task.PlanStatusDraft,
+	}
+	if err := a.Repo.CreatePlan(plan); err != nil {
+		return &GenerateResult{
+			Success: false,
+			Code:    PlanErrorPersistence,
+			Message: fmt.Sprintf("Failed to save plan: %v", err),
+		}
+	}
+
+	var (
+		tasks     []task.Task
+		depTitles [][]string
+		warnings  warningList
+		received  int
+	)
+	titleToID := make(map[string]string)
+
+	streamErr := planner.RunStream(ctx, input, func(pt impl.PlanningTask) error {
+		received++
+		t := a.planningTaskToTask(ctx, pt, received-1)
+		if err := t.Validate(); err != nil {
+			warnings.add(WarningCategoryTask, received-1, fmt.Sprintf("skipped: %v", err))
+			return nil
+		}
+		t.PlanID = plan.ID
+		if err := a.Repo.CreateTask(&t); err != nil {
+			return fmt.Errorf("save task %q: %w", t.Title, err)
+		}
+		tasks = append(tasks, t)
+		depTitles = append(depTitles, pt.Dependencies)
+		titleToID[pt.Title] = t.ID
+		return nil
+	})
+	if streamErr != nil {
+		return &GenerateResult{
+			Success:          false,
+			Code:             PlanErrorAgentFailed,
+			Message:          fmt.Sprintf("Planning stream failed after saving %d tasks: %v", len(tasks), streamErr),
+			Hint:             "The saved tasks remain in the draft plan. Review it, or delete it and generate again.",
+			PlanID:           plan.ID,
+			Tasks:            tasks,
+			Goal:             opts.Goal,
+			EnrichedGoal:     opts.EnrichedGoal,
+			SemanticWarnings: warnings.flat,
+			Warnings:         warnings.items,
+		}
+	}
+	if len(tasks) == 0 {
+		return &GenerateResult{
+			Success:          false,
+			Code:             PlanErrorNoTasksGenerated,
+			Message:          "No tasks generated",
+			PlanID:           plan.ID,
+			SemanticWarnings: warnings.flat,
+			Warnings:         warnings.items,
+		}
+	}
+
+	// Resolve dependencies now that every task title is known
+	for i := range tasks {
+		for _, title := range depTitles[i] {
+			depID, ok := titleToID[title]
+			if !ok || depID == tasks[i].ID {
+				continue
+			}
+			if err := a.Repo.AddDependency(tasks[i].ID, depID); err != nil {
+				warnings.add(WarningCategoryTask, i, fmt.Sprintf("dependency on %q not saved: %v", title, err))
+				continue
+			}
+			tasks[i].Dependencies = append(tasks[i].Dependencies, depID)
+		}
+	}
+
+	if err := a.activatePlan(plan.ID); err != nil {
+		return &GenerateResult{
+			Success: false,
+			Code:    PlanErrorPersistence,
+			Message: fmt.Sprintf("Plan created but failed to set active: %v", err),
+			PlanID:  plan.ID,
+		}
+	}
+
+	return &GenerateResult{
+		Success:          true,
+		Tasks:            tasks,
+		PlanID:           plan.ID,
+		Goal:             opts.Goal,
+		EnrichedGoal:     opts.EnrichedGoal,
+		Message:          "Plan generated successfully",
+		Hint:             "Use task action=next to begin working on the first task.",
+		SemanticWarnings: warnings.flat,
+		Warnings:         warnings.items,
+	}
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/agents/impl"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+// streamingPlannerMock emits a fixed list of tasks, calling afterEmit after each one.
+type streamingPlannerMock struct {
+	tasks     []impl.PlanningTask
+	failAfter int // Return an error after this many tasks (0 = never)
+	afterEmit func(emitted int)
+}
+
+func (m *streamingPlannerMock) Run(context.Context, core.Input) (core.Output, error) {
+	return core.Output{}, errors.New("Run should not be called in stream mode")
+}
+
+func (m *streamingPlannerMock) Close() error { return nil }
+
+func (m *streamingPlannerMock) RunStream(_ context.Context, _ core.Input, emit func(impl.PlanningTask) error) error {
+	for i, t := range m.tasks {
+		if m.failAfter > 0 && i == m.failAfter {
+			return errors.New("connection reset")
+		}
+		if err := emit(t); err != nil {
+			return err
+		}
+		if m.afterEmit != nil {
+			m.afterEmit(i + 1)
+		}
+	}
+	return nil
+}
+
+func TestPlanApp_GenerateStream(t *testing.T) {
+	ctx := context.Background()
+	streamTasks := []impl.PlanningTask{
+		{Title: "Add JWT middleware", Description: "Validate tokens", Priority: 10, Dependencies: []string{"Add token signer"}},
+		{Title: "Add token signer", Description: "Sign tokens with HS256", Priority: 20},
+		{Title: "Protect routes", Description: "Apply middleware to /api", Priority: 30, Dependencies: []string{"Add JWT middleware"}},
+	}
+	opts := GenerateOptions{Goal: "Add auth", EnrichedGoal: "Add JWT auth to the API", Save: true, Stream: true}
+
+	newApp := func(t *testing.T, mock *streamingPlannerMock) *PlanApp {
+		planApp := newTestPlanApp(t)
+		planApp.TaskEnricher = nil
+		planApp.PlannerFactory = func(llm.Config) TaskPlanner { return mock }
+		return planApp
+	}
+	savedTasks := func(t *testing.T, planApp *PlanApp) (*task.Plan, []task.Task) {
+		t.Helper()
+		plans, err := planApp.Repo.ListPlans(task.PlanFilter{IncludeArchived: true})
+		if err != nil || len(plans) != 1 {
+			t.Fatalf("ListPlans = %d plans, err %v; want 1", len(plans), err)
+		}
+		tasks, err := planApp.Repo.ListTasks(plans[0].ID)
+		if err != nil {
+			t.Fatalf("ListTasks: %v", err)
+		}
+		return &plans[0], tasks
+	}
+
+	t.Run("persists_progressively", func(t *testing.T) {
+		mock := &streamingPlannerMock{tasks: streamTasks}
+		planApp := newApp(t, mock)
+		mock.afterEmit = func(emitted int) {
+			plan, tasks := savedTasks(t, planApp)
+			if len(tasks) != emitted {
+				t.Errorf("after %d emitted tasks, %d are saved", emitted, len(tasks))
+			}
+			if plan.Status != task.PlanStatusDraft {
+				t.Errorf("plan status mid-stream = %s, want draft", plan.Status)
+			}
+		}
+
+		result, err := planApp.Generate(ctx, opts)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Generate failed: %s", result.Message)
+		}
+
+		plan, tasks := savedTasks(t, planApp)
+		if plan.ID != result.PlanID || plan.Status != task.PlanStatusActive {
+			t.Errorf("plan %s status %s, want %s active", plan.ID, plan.Status, result.PlanID)
+		}
+		byTitle := make(map[string]task.Task)
+		for _, st := range tasks {
+			byTitle[st.Title] = st
+		}
+		middleware, signer := byTitle["Add JWT middleware"], byTitle["Add token signer"]
+		if len(middleware.Dependencies) != 1 || middleware.Dependencies[0] != signer.ID {
+			t.Errorf("forward dependency not resolved: %v", middleware.Dependencies)
+		}
+		if deps := byTitle["Protect routes"].Dependencies; len(deps) != 1 || deps[0] != middleware.ID {
+			t.Errorf("dependency not resolved: %v", deps)
+		}
+	})
+
+	t.Run("failure_keeps_draft", func(t *testing.T) {
+		planApp := newApp(t, &streamingPlannerMock{tasks: streamTasks, failAfter: 2})
+
+		result, err := planApp.Generate(ctx, opts)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if result.Success || result.Code != PlanErrorAgentFailed {
+			t.Fatalf("expected agent failure, got success=%v code=%q", result.Success, result.Code)
+		}
+
+		plan, tasks := savedTasks(t, planApp)
+		if result.PlanID != plan.ID {
+			t.Errorf("result PlanID = %q, want %q", result.PlanID, plan.ID)
+		}
+		if plan.Status != task.PlanStatusDraft {
+			t.Errorf("plan status = %s, want draft", plan.Status)
+		}
+		if len(tasks) != 2 {
+			t.Errorf("expected 2 saved tasks, got %d", len(tasks))
+		}
+	})
+}
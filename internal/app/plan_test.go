@@ -0,0 +1,1329 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/agents/impl"
+	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/knowledge"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+	"github.com/josephgoksu/TaskWing/internal/task"
+	"github.com/spf13/viper"
+)
+
+func newTestPlanApp(t *testing.T) *PlanApp {
+	t.Helper()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return NewPlanApp(&Context{Repo: memory.NewRepository(store, nil)})
+}
+
+func TestPlanApp_ErrorCodes(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+
+	t.Run("expand_missing_plan", func(t *testing.T) {
+		result, err := planApp.Expand(ctx, ExpandOptions{PlanID: "plan-missing", PhaseIndex: 0})
+		if err != nil {
+			t.Fatalf("Expand: %v", err)
+		}
+		if result.Success {
+			t.Fatal("expected failure for missing plan")
+		}
+		if result.Code != PlanErrorPlanNotFound {
+			t.Errorf("Code = %q, want %q", result.Code, PlanErrorPlanNotFound)
+		}
+		if !errors.Is(result.Code.Err(), ErrPlanNotFound) {
+			t.Errorf("Code.Err() = %v, want ErrPlanNotFound", result.Code.Err())
+		}
+		if result.Message == "" {
+			t.Error("human-readable message should be kept")
+		}
+	})
+
+	t.Run("finalize_missing_plan", func(t *testing.T) {
+		result, err := planApp.Finalize(ctx, FinalizeOptions{PlanID: "plan-missing"})
+		if err != nil {
+			t.Fatalf("Finalize: %v", err)
+		}
+		if result.Code != PlanErrorPlanNotFound {
+			t.Errorf("Code = %q, want %q", result.Code, PlanErrorPlanNotFound)
+		}
+	})
+
+	t.Run("decompose_missing_enriched_goal", func(t *testing.T) {
+		result, err := planApp.Decompose(ctx, DecomposeOptions{Goal: "Add auth"})
+		if err != nil {
+			t.Fatalf("Decompose: %v", err)
+		}
+		if result.Code != PlanErrorNoEnrichedGoal {
+			t.Errorf("Code = %q, want %q", result.Code, PlanErrorNoEnrichedGoal)
+		}
+	})
+
+	t.Run("generate_missing_enriched_goal", func(t *testing.T) {
+		result, err := planApp.Generate(ctx, GenerateOptions{Goal: "Add auth"})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if result.Code != PlanErrorNoEnrichedGoal {
+			t.Errorf("Code = %q, want %q", result.Code, PlanErrorNoEnrichedGoal)
+		}
+		if PlanErrorCodeOf(result.Code.Err()) != result.Code {
+			t.Error("code and sentinel should round-trip")
+		}
+	})
+}
+
+func TestPlanApp_EnsureActivePlan(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+
+	plan, err := planApp.EnsureActivePlan(ctx)
+	if plan != nil || !errors.Is(err, ErrNoActivePlan) {
+		t.Fatalf("EnsureActivePlan = %v, %v; want ErrNoActivePlan", plan, err)
+	}
+	if PlanErrorCodeOf(err) != PlanErrorNoActivePlan {
+		t.Errorf("code = %q, want %q", PlanErrorCodeOf(err), PlanErrorNoActivePlan)
+	}
+	if hint := ErrorHint(err); !strings.Contains(hint, "/taskwing:plan") {
+		t.Errorf("hint = %q, want a pointer to /taskwing:plan", hint)
+	}
+
+	// Task paths that fall back to the active plan surface the same hint
+	result, err := NewTaskApp(planApp.ctx).Next(ctx, TaskNextOptions{})
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if result.Success || !strings.Contains(result.Hint, "/taskwing:plan") {
+		t.Errorf("Next without a plan = %+v, want failure with hint", result)
+	}
+
+	active := &task.Plan{Goal: "Ship it", Status: task.PlanStatusActive}
+	if err := planApp.ctx.Repo.CreatePlan(active); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	plan, err = planApp.EnsureActivePlan(ctx)
+	if err != nil || plan.ID != active.ID {
+		t.Errorf("EnsureActivePlan = %v, %v; want %s", plan, err, active.ID)
+	}
+}
+
+func TestPlanApp_GenerateDryRun(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	repo := planApp.ctx.Repo
+
+	existing := &task.Plan{Goal: "Existing goal", Status: task.PlanStatusActive}
+	if err := repo.CreatePlan(existing); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	if err := repo.SetActivePlan(existing.ID); err != nil {
+		t.Fatalf("SetActivePlan: %v", err)
+	}
+
+	result, err := planApp.Generate(ctx, GenerateOptions{
+		Goal:         "Add auth",
+		EnrichedGoal: "Add JWT auth to the API",
+		Save:         true,
+		DryRun:       true,
+		ExplicitTasks: []task.TaskInput{
+			{Title: "Add JWT middleware", Description: "Validate tokens on every request"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("dry run failed: %s", result.Message)
+	}
+	if !result.DryRun || result.PlanID != "" {
+		t.Errorf("DryRun = %v, PlanID = %q; want dry run without a plan ID", result.DryRun, result.PlanID)
+	}
+	if len(result.Tasks) != 1 {
+		t.Errorf("expected 1 proposed task, got %d", len(result.Tasks))
+	}
+
+	plans, err := repo.ListPlans(task.PlanFilter{IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("ListPlans: %v", err)
+	}
+	if len(plans) != 1 {
+		t.Errorf("dry run must not create plans, have %d", len(plans))
+	}
+	active, err := repo.GetActivePlan()
+	if err != nil {
+		t.Fatalf("GetActivePlan: %v", err)
+	}
+	if active.ID != existing.ID {
+		t.Errorf("active plan changed to %s, want %s", active.ID, existing.ID)
+	}
+}
+
+func TestPlanApp_Merge(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	repo := planApp.Repo
+
+	target := &task.Plan{Goal: "Auth backend", Status: task.PlanStatusActive, Tasks: []task.Task{
+		{Title: "Add user table", Description: "a", Priority: 10},
+		{Title: "Add login endpoint", Description: "b", Priority: 20},
+	}}
+	source := &task.Plan{Goal: "Auth frontend", Status: task.PlanStatusDraft, Tasks: []task.Task{
+		{Title: "Add login form", Description: "c", Priority: 10},
+		{Title: "Wire form to API", Description: "d", Priority: 20},
+	}}
+	for _, p := range []*task.Plan{target, source} {
+		if err := repo.CreatePlan(p); err != nil {
+			t.Fatalf("CreatePlan: %v", err)
+		}
+	}
+	if err := repo.AddDependency(target.Tasks[1].ID, target.Tasks[0].ID); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	if err := repo.AddDependency(source.Tasks[1].ID, source.Tasks[0].ID); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	if err := repo.CreatePhase(&task.Phase{PlanID: source.ID, Title: "Frontend"}); err != nil {
+		t.Fatalf("CreatePhase: %v", err)
+	}
+
+	merged, err := planApp.Merge(ctx, target.ID, source.ID)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if merged.ID != target.ID {
+		t.Errorf("merged plan ID = %s, want %s", merged.ID, target.ID)
+	}
+	if len(merged.Tasks) != 4 {
+		t.Fatalf("merged plan has %d tasks, want 4", len(merged.Tasks))
+	}
+	if len(merged.Phases) != 1 || merged.Phases[0].PlanID != target.ID {
+		t.Errorf("source phase not moved: %+v", merged.Phases)
+	}
+
+	deps := make(map[string][]string)
+	for _, mt := range merged.Tasks {
+		deps[mt.ID] = mt.Dependencies
+	}
+	for _, p := range []*task.Plan{target, source} {
+		if got := deps[p.Tasks[1].ID]; len(got) != 1 || got[0] != p.Tasks[0].ID {
+			t.Errorf("dependency of %q = %v, want [%s]", p.Tasks[1].Title, got, p.Tasks[0].ID)
+		}
+	}
+
+	if _, err := repo.GetPlan(source.ID); err == nil {
+		t.Error("source plan should be deleted")
+	}
+	if _, err := planApp.Merge(ctx, target.ID, target.ID); err == nil {
+		t.Error("merging a plan into itself should fail")
+	}
+}
+
+func TestPlanApp_Archive(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	repo := planApp.Repo
+
+	kept := &task.Plan{Goal: "Keep", Status: task.PlanStatusDraft}
+	archived := &task.Plan{Goal: "Old", Status: task.PlanStatusDraft, Tasks: []task.Task{
+		{Title: "Old task", Description: "a", Priority: 10},
+	}}
+	for _, p := range []*task.Plan{kept, archived} {
+		if err := repo.CreatePlan(p); err != nil {
+			t.Fatalf("CreatePlan: %v", err)
+		}
+	}
+	if err := repo.SetActivePlan(archived.ID); err != nil {
+		t.Fatalf("SetActivePlan: %v", err)
+	}
+
+	if err := planApp.Archive(archived.ID); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+
+	plans, err := planApp.List(ctx, task.PlanFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(plans) != 1 || plans[0].ID != kept.ID {
+		t.Errorf("default listing = %v, want only %s", plans, kept.ID)
+	}
+	if all, _ := planApp.List(ctx, task.PlanFilter{IncludeArchived: true}); len(all) != 2 {
+		t.Errorf("listing with archived = %d plans, want 2", len(all))
+	}
+
+	if active, err := repo.GetActivePlan(); err != nil || active != nil {
+		t.Errorf("archived plan must not stay active: active=%v err=%v", active, err)
+	}
+	if err := repo.SetActivePlan(archived.ID); err == nil {
+		t.Error("activating an archived plan should fail")
+	}
+	if active, _ := repo.GetActivePlan(); active != nil {
+		t.Errorf("active plan = %s, want none", active.ID)
+	}
+	if tasks, _ := repo.ListTasks(archived.ID); len(tasks) != 1 {
+		t.Errorf("archiving must keep tasks, have %d", len(tasks))
+	}
+
+	if err := planApp.Unarchive(archived.ID); err != nil {
+		t.Fatalf("Unarchive: %v", err)
+	}
+	restored, err := repo.GetPlan(archived.ID)
+	if err != nil {
+		t.Fatalf("GetPlan: %v", err)
+	}
+	if restored.Status != task.PlanStatusDraft {
+		t.Errorf("unarchived status = %s, want draft", restored.Status)
+	}
+	if plans, _ := planApp.List(ctx, task.PlanFilter{}); len(plans) != 2 {
+		t.Errorf("unarchived plan should be listed again, have %d plans", len(plans))
+	}
+	if err := planApp.Unarchive(kept.ID); err == nil {
+		t.Error("unarchiving a plan that is not archived should fail")
+	}
+}
+
+func TestPlanApp_ListFiltered(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	planApp := NewPlanApp(&Context{Repo: memory.NewRepository(store, nil)})
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	statuses := []task.PlanStatus{
+		task.PlanStatusActive, task.PlanStatusDraft, task.PlanStatusArchived,
+		task.PlanStatusDraft, task.PlanStatusCompleted,
+	}
+	ids := make([]string, len(statuses))
+	for i, status := range statuses {
+		p := &task.Plan{Goal: string(status), Status: status, CreatedAt: base.Add(time.Duration(i) * time.Hour)}
+		if err := planApp.Repo.CreatePlan(p); err != nil {
+			t.Fatalf("CreatePlan: %v", err)
+		}
+		ids[i] = p.ID
+	}
+
+	planIDs := func(filter task.PlanFilter) []string {
+		t.Helper()
+		plans, err := planApp.List(ctx, filter)
+		if err != nil {
+			t.Fatalf("List(%+v): %v", filter, err)
+		}
+		var got []string
+		for _, p := range plans {
+			got = append(got, p.ID)
+		}
+		return got
+	}
+	assertIDs := func(name string, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s = %v, want %v", name, got, want)
+				return
+			}
+		}
+	}
+
+	assertIDs("default", planIDs(task.PlanFilter{}), ids[4], ids[3], ids[1], ids[0])
+	assertIDs("with archived", planIDs(task.PlanFilter{IncludeArchived: true}), ids[4], ids[3], ids[2], ids[1], ids[0])
+	assertIDs("drafts", planIDs(task.PlanFilter{Status: task.PlanStatusDraft}), ids[3], ids[1])
+	assertIDs("archived status", planIDs(task.PlanFilter{Status: task.PlanStatusArchived}), ids[2])
+	assertIDs("page 1", planIDs(task.PlanFilter{Limit: 2}), ids[4], ids[3])
+	assertIDs("page 2", planIDs(task.PlanFilter{Limit: 2, Offset: 2}), ids[1], ids[0])
+	assertIDs("offset only", planIDs(task.PlanFilter{Offset: 3}), ids[0])
+
+	// Timestamps have second resolution, so set update times directly instead of sleeping
+	touched := base.Add(24 * time.Hour).Format(time.RFC3339)
+	if _, err := store.DB().Exec(`UPDATE plans SET updated_at = ? WHERE id = ?`, touched, ids[0]); err != nil {
+		t.Fatalf("touch plan: %v", err)
+	}
+	if _, err := store.DB().Exec(`UPDATE plans SET updated_at = ? WHERE id != ?`, base.Format(time.RFC3339), ids[0]); err != nil {
+		t.Fatalf("reset plans: %v", err)
+	}
+	if got := planIDs(task.PlanFilter{OrderBy: task.PlanOrderUpdated, Limit: 1}); len(got) != 1 || got[0] != ids[0] {
+		t.Errorf("most recently updated = %v, want %s", got, ids[0])
+	}
+
+	if _, err := planApp.List(ctx, task.PlanFilter{OrderBy: "goal"}); err == nil {
+		t.Error("expected error for unknown order")
+	}
+}
+
+func TestPlanApp_ParsePhaseDependencies(t *testing.T) {
+	planApp := newTestPlanApp(t)
+
+	phases := planApp.parsePhasesFromMetadata(map[string]any{"phases": []any{
+		map[string]any{"title": "Schema"},
+		map[string]any{"title": "API", "dependencies": []any{"schema", "Unknown phase"}},
+		map[string]any{"title": "UI", "dependencies": []any{"API"}},
+		map[string]any{"title": "Docs"},
+	}})
+	if len(phases) != 4 {
+		t.Fatalf("got %d phases, want 4", len(phases))
+	}
+	schema, api := phases[0].ID, phases[1].ID
+	if !slices.Equal(phases[1].DependsOn, []string{schema}) {
+		t.Errorf("API depends on %v, want [%s]", phases[1].DependsOn, schema)
+	}
+	if !slices.Equal(phases[2].DependsOn, []string{api}) {
+		t.Errorf("UI depends on %v, want [%s]", phases[2].DependsOn, api)
+	}
+	if len(phases[3].DependsOn) != 0 {
+		t.Errorf("Docs depends on %v, want none", phases[3].DependsOn)
+	}
+	if err := task.VerifyPhaseDAG(phases); err != nil {
+		t.Errorf("VerifyPhaseDAG: %v", err)
+	}
+
+	plan := &task.Plan{Goal: "Add auth", Status: task.PlanStatusDraft}
+	if err := planApp.Repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	if err := planApp.Repo.CreatePhasesForPlan(plan.ID, phases); err != nil {
+		t.Fatalf("CreatePhasesForPlan: %v", err)
+	}
+	stored, err := planApp.Repo.GetPhase(phases[2].ID)
+	if err != nil {
+		t.Fatalf("GetPhase: %v", err)
+	}
+	if !slices.Equal(stored.DependsOn, []string{api}) {
+		t.Errorf("stored UI depends on %v, want [%s]", stored.DependsOn, api)
+	}
+
+	cyclic := planApp.parsePhasesFromMetadata(map[string]any{"phases": []impl.PhaseOutput{
+		{Title: "Schema", Dependencies: []string{"UI"}},
+		{Title: "API", Dependencies: []string{"Schema"}},
+		{Title: "UI", Dependencies: []string{"API"}},
+	}})
+	if err := task.VerifyPhaseDAG(cyclic); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("VerifyPhaseDAG on a cycle = %v, want a cycle error", err)
+	}
+}
+
+func TestPlanApp_PhaseOrdering(t *testing.T) {
+	planApp := newTestPlanApp(t)
+	repo := planApp.Repo
+
+	plan := &task.Plan{Goal: "Add auth", Status: task.PlanStatusDraft}
+	if err := repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	phases := []task.Phase{
+		{Title: "Schema", OrderIndex: 0},
+		{Title: "API", OrderIndex: 1},
+		{Title: "UI", OrderIndex: 2},
+	}
+	if err := repo.CreatePhasesForPlan(plan.ID, phases); err != nil {
+		t.Fatalf("CreatePhasesForPlan: %v", err)
+	}
+	schema, api, ui := phases[0].ID, phases[1].ID, phases[2].ID
+	if err := repo.CreateTask(&task.Task{PlanID: plan.ID, PhaseID: api, Title: "Add handler", Description: "a", Priority: 10}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	titles := func(phases []task.Phase) string {
+		t.Helper()
+		var parts []string
+		for i, p := range phases {
+			if p.OrderIndex != i {
+				t.Errorf("phase %s order_index = %d, want %d", p.Title, p.OrderIndex, i)
+			}
+			parts = append(parts, p.Title)
+		}
+		return strings.Join(parts, ",")
+	}
+
+	reordered, err := planApp.ReorderPhases(plan.ID, []string{api, schema, ui})
+	if err != nil {
+		t.Fatalf("ReorderPhases: %v", err)
+	}
+	if got := titles(reordered); got != "API,Schema,UI" {
+		t.Errorf("reordered phases = %s", got)
+	}
+
+	if _, err := planApp.ReorderPhases(plan.ID, []string{schema, ui}); err == nil || !strings.Contains(err.Error(), "expanded tasks") {
+		t.Errorf("dropping a phase with tasks should be rejected, got %v", err)
+	}
+	if _, err := planApp.ReorderPhases(plan.ID, []string{api, schema}); err == nil {
+		t.Error("dropping a phase should be rejected")
+	}
+	if _, err := planApp.ReorderPhases(plan.ID, []string{api, api, ui}); err == nil {
+		t.Error("duplicate phase IDs should be rejected")
+	}
+
+	inserted, err := planApp.InsertPhase(plan.ID, 1, task.Phase{Title: "Migrations"})
+	if err != nil {
+		t.Fatalf("InsertPhase: %v", err)
+	}
+	if inserted.OrderIndex != 1 || inserted.Status != task.PhaseStatusPending {
+		t.Errorf("inserted phase index %d status %s, want 1 pending", inserted.OrderIndex, inserted.Status)
+	}
+	listed, err := repo.ListPhases(plan.ID)
+	if err != nil {
+		t.Fatalf("ListPhases: %v", err)
+	}
+	if got := titles(listed); got != "API,Migrations,Schema,UI" {
+		t.Errorf("phases after insert = %s", got)
+	}
+
+	if _, err := planApp.InsertPhase(plan.ID, 4, task.Phase{Title: "Docs"}); err != nil {
+		t.Errorf("appending at the end should work: %v", err)
+	}
+	if _, err := planApp.InsertPhase(plan.ID, 9, task.Phase{Title: "Too far"}); err == nil {
+		t.Error("out of range index should be rejected")
+	}
+	if tasks, _ := repo.ListTasksByPhase(api); len(tasks) != 1 {
+		t.Errorf("API phase should keep its task, has %d", len(tasks))
+	}
+}
+
+// recordingExpander returns one task per call and records every input it receives.
+type recordingExpander struct {
+	inputs []core.Input
+}
+
+func (m *recordingExpander) Run(_ context.Context, input core.Input) (core.Output, error) {
+	m.inputs = append(m.inputs, input)
+	title := fmt.Sprintf("Attempt %d task", len(m.inputs))
+	return core.Output{Findings: []core.Finding{{Metadata: map[string]any{
+		"tasks": []impl.PlanningTask{{Title: title, Description: "do it", Priority: 10}},
+	}}}}, nil
+}
+
+func (m *recordingExpander) Close() error { return nil }
+
+func TestPlanApp_ExpandFeedbackHistory(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.TaskEnricher = nil
+	expander := &recordingExpander{}
+	planApp.ExpanderFactory = func(llm.Config) PhaseExpander { return expander }
+	repo := planApp.Repo
+
+	plan := &task.Plan{Goal: "Add auth", Status: task.PlanStatusDraft}
+	if err := repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	phases := []task.Phase{{Title: "API", OrderIndex: 0}}
+	if err := repo.CreatePhasesForPlan(plan.ID, phases); err != nil {
+		t.Fatalf("CreatePhasesForPlan: %v", err)
+	}
+
+	expand := func(feedback string) *ExpandResult {
+		t.Helper()
+		result, err := planApp.Expand(ctx, ExpandOptions{PlanID: plan.ID, PhaseIndex: 0, Feedback: feedback})
+		if err != nil {
+			t.Fatalf("Expand: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Expand failed: %s", result.Message)
+		}
+		return result
+	}
+
+	expand("keep tasks small")
+	if got := expand(""); got.Message != "Phase already expanded" || len(expander.inputs) != 1 {
+		t.Fatalf("expanding without feedback should return existing tasks, got %q after %d runs", got.Message, len(expander.inputs))
+	}
+
+	second := expand("use middleware instead")
+	if len(expander.inputs) != 2 {
+		t.Fatalf("feedback should regenerate the phase, agent ran %d times", len(expander.inputs))
+	}
+	history, _ := expander.inputs[1].ExistingContext["history"].(string)
+	if !strings.Contains(history, "keep tasks small") || !strings.Contains(history, "Attempt 1 task") {
+		t.Errorf("second prompt history should include the first attempt, got:\n%s", history)
+	}
+	if fb := expander.inputs[1].ExistingContext["feedback"]; fb != "use middleware instead" {
+		t.Errorf("feedback = %v", fb)
+	}
+
+	tasks, err := repo.ListTasksByPhase(phases[0].ID)
+	if err != nil {
+		t.Fatalf("ListTasksByPhase: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "Attempt 2 task" || second.Tasks[0].Title != "Attempt 2 task" {
+		t.Errorf("regenerated phase should only hold the new task, got %v", tasks)
+	}
+	phase, err := repo.GetPhase(phases[0].ID)
+	if err != nil {
+		t.Fatalf("GetPhase: %v", err)
+	}
+	if len(phase.ExpandAttempts) != 2 || phase.ExpandAttempts[1].Feedback != "use middleware instead" {
+		t.Errorf("expand attempts = %+v", phase.ExpandAttempts)
+	}
+
+	// Once work has started the phase is no longer regenerated
+	if err := repo.UpdateTaskStatus(tasks[0].ID, task.StatusInProgress); err != nil {
+		t.Fatalf("UpdateTaskStatus: %v", err)
+	}
+	refused, err := planApp.Expand(ctx, ExpandOptions{PlanID: plan.ID, PhaseIndex: 0, Feedback: "split it further"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if refused.Success || refused.Code != PlanErrorPhaseStarted || len(expander.inputs) != 2 {
+		t.Errorf("expand with started tasks = %+v after %d runs, want phase_started without running", refused, len(expander.inputs))
+	}
+	if kept, _ := repo.ListTasksByPhase(phases[0].ID); len(kept) != 1 || kept[0].ID != tasks[0].ID {
+		t.Errorf("started task should be kept, phase holds %v", kept)
+	}
+}
+
+// staticPlanner returns a fixed task list.
+type staticPlanner struct {
+	tasks []impl.PlanningTask
+}
+
+func (m *staticPlanner) Run(context.Context, core.Input) (core.Output, error) {
+	return core.Output{Findings: []core.Finding{{Metadata: map[string]any{"tasks": m.tasks}}}}, nil
+}
+
+func (m *staticPlanner) Close() error { return nil }
+
+// recordingPlanner is a staticPlanner that keeps the input it was run with.
+type recordingPlanner struct {
+	staticPlanner
+	input core.Input
+}
+
+func (m *recordingPlanner) Run(ctx context.Context, input core.Input) (core.Output, error) {
+	m.input = input
+	return m.staticPlanner.Run(ctx, input)
+}
+
+func TestPlanApp_GenerateIntoPhase(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	repo := planApp.Repo
+
+	plan := &task.Plan{Goal: "Add auth", Status: task.PlanStatusDraft}
+	if err := repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	phases := []task.Phase{{Title: "Schema"}, {Title: "API", OrderIndex: 1}}
+	if err := repo.CreatePhasesForPlan(plan.ID, phases); err != nil {
+		t.Fatalf("CreatePhasesForPlan: %v", err)
+	}
+	api := phases[1]
+
+	result, err := planApp.Generate(ctx, GenerateOptions{
+		Goal:         "Add auth",
+		EnrichedGoal: "Add JWT auth to the API",
+		Save:         true,
+		PhaseID:      api.ID,
+		ExplicitTasks: []task.TaskInput{
+			{Title: "Add JWT middleware", Description: "Validate tokens on every request"},
+			{Title: "Add login endpoint", Description: "Issue tokens"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Generate failed: %s", result.Message)
+	}
+	if result.PlanID != plan.ID {
+		t.Errorf("PlanID = %q, want the phase's plan %s", result.PlanID, plan.ID)
+	}
+
+	linked, err := repo.ListTasksByPhase(api.ID)
+	if err != nil {
+		t.Fatalf("ListTasksByPhase: %v", err)
+	}
+	if len(linked) != 2 {
+		t.Fatalf("phase has %d tasks, want 2", len(linked))
+	}
+	for _, tk := range linked {
+		if tk.PlanID != plan.ID {
+			t.Errorf("task %q plan = %s, want %s", tk.Title, tk.PlanID, plan.ID)
+		}
+	}
+	stored, err := repo.GetPhase(api.ID)
+	if err != nil {
+		t.Fatalf("GetPhase: %v", err)
+	}
+	if stored.Status != task.PhaseStatusExpanded {
+		t.Errorf("phase status = %s, want expanded", stored.Status)
+	}
+	if plans, _ := repo.ListPlans(task.PlanFilter{IncludeArchived: true}); len(plans) != 1 {
+		t.Errorf("generating into a phase must not create a plan, have %d plans", len(plans))
+	}
+
+	missing, err := planApp.Generate(ctx, GenerateOptions{
+		Goal:          "Add auth",
+		EnrichedGoal:  "Add JWT auth to the API",
+		PhaseID:       "phase-missing",
+		ExplicitTasks: []task.TaskInput{{Title: "Add JWT middleware", Description: "a"}},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if missing.Success || missing.Code != PlanErrorPhaseNotFound {
+		t.Errorf("unknown phase: success=%v code=%s, want %s", missing.Success, missing.Code, PlanErrorPhaseNotFound)
+	}
+
+	// A second generate into the expanded phase must not append duplicates
+	again, err := planApp.Generate(ctx, GenerateOptions{
+		Goal:          "Add auth",
+		EnrichedGoal:  "Add JWT auth to the API",
+		Save:          true,
+		PhaseID:       api.ID,
+		ExplicitTasks: []task.TaskInput{{Title: "Add JWT middleware", Description: "Validate tokens on every request"}},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if again.Success || again.Code != PlanErrorPhaseExpanded {
+		t.Errorf("expanded phase: success=%v code=%s, want %s", again.Success, again.Code, PlanErrorPhaseExpanded)
+	}
+	if linked, _ := repo.ListTasksByPhase(api.ID); len(linked) != 2 {
+		t.Errorf("phase has %d tasks after the rejected generate, want 2", len(linked))
+	}
+}
+
+func TestPlanApp_GenerateFromSavedClarification(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.TaskEnricher = nil
+	planner := &recordingPlanner{staticPlanner: staticPlanner{tasks: []impl.PlanningTask{
+		{Title: "Add JWT middleware", Description: "Validate tokens on every request", Priority: 10},
+	}}}
+	planApp.PlannerFactory = func(llm.Config) TaskPlanner { return planner }
+
+	if _, err := planApp.SaveClarification("Add auth", "  ", ""); err == nil {
+		t.Error("expected an error without an enriched goal")
+	}
+	enriched := "Add JWT auth to the API with 24h token expiry"
+	sessionID, err := planApp.SaveClarification("Add auth", enriched, "JWT auth")
+	if err != nil {
+		t.Fatalf("SaveClarification: %v", err)
+	}
+
+	result, err := planApp.Generate(ctx, GenerateOptions{
+		Goal:             "Add auth",
+		ClarifySessionID: sessionID,
+		DryRun:           true,
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Generate failed: %s", result.Message)
+	}
+	if result.EnrichedGoal != enriched {
+		t.Errorf("EnrichedGoal = %q, want the saved clarification", result.EnrichedGoal)
+	}
+	if got := planner.input.ExistingContext["enriched_goal"]; got != enriched {
+		t.Errorf("planner enriched_goal = %v, want %q", got, enriched)
+	}
+}
+
+func TestPlanApp_GenerateTestFirst(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.TaskEnricher = nil
+	planner := &recordingPlanner{staticPlanner: staticPlanner{tasks: []impl.PlanningTask{
+		{Title: "Write failing tests for JWT middleware", Description: "Cover token validation", Priority: 10, Keywords: []string{"test-first"}},
+		{Title: "Add JWT middleware", Description: "Validate tokens on every request", Priority: 20},
+		{Title: "Write failing tests for login", Description: "Cover the login endpoint", Priority: 30, Keywords: []string{"test-first"}},
+		{Title: "Add login endpoint", Description: "Issue tokens", Priority: 40, Dependencies: []string{"Write failing tests for login"}},
+	}}}
+	planApp.PlannerFactory = func(llm.Config) TaskPlanner { return planner }
+
+	result, err := planApp.Generate(ctx, GenerateOptions{
+		Goal:         "Add auth",
+		EnrichedGoal: "Add JWT auth to the API",
+		TestFirst:    true,
+		DryRun:       true,
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Generate failed: %s", result.Message)
+	}
+	if got, _ := planner.input.ExistingContext["context"].(string); !strings.Contains(got, config.PlanningTestFirstInstructions) {
+		t.Errorf("planner context = %q, want the test-first instructions", got)
+	}
+	if len(result.Tasks) != 4 {
+		t.Fatalf("got %d tasks, want 4", len(result.Tasks))
+	}
+	for _, pair := range [][2]int{{0, 1}, {2, 3}} {
+		testTask, implTask := result.Tasks[pair[0]], result.Tasks[pair[1]]
+		if !slices.Equal(implTask.Dependencies, []string{testTask.ID}) {
+			t.Errorf("%q dependencies = %v, want [%s]", implTask.Title, implTask.Dependencies, testTask.ID)
+		}
+		if len(testTask.Dependencies) != 0 {
+			t.Errorf("%q dependencies = %v, want none", testTask.Title, testTask.Dependencies)
+		}
+	}
+}
+
+func TestPlanApp_GenerateConstraintLint(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.TaskEnricher = nil
+	planApp.PlannerFactory = func(llm.Config) TaskPlanner {
+		return &staticPlanner{tasks: []impl.PlanningTask{
+			{Title: "Add analytics endpoint", Description: "Serve high-volume reads from the primary database", Priority: 10},
+			{Title: "Add report export", Description: "Run high-volume reads against the ReadReplica pool", Priority: 20},
+			{Title: "Add logging", Description: "Log each request with fmt.Println in handlers", Priority: 30},
+		}}
+	}
+
+	for _, n := range []*memory.Node{
+		{Type: memory.NodeTypeConstraint, Summary: "Use ReadReplica for high-volume reads", Content: "Use ReadReplica for high-volume reads", ConfidenceScore: 0.95},
+		{Type: memory.NodeTypeConstraint, Summary: "Never use fmt.Println in handlers", Content: "Never use fmt.Println in handlers", ConfidenceScore: 0.4},
+	} {
+		if err := planApp.ctx.Repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	result, err := planApp.Generate(ctx, GenerateOptions{
+		Goal:         "Add analytics",
+		EnrichedGoal: "Add analytics endpoints",
+		DryRun:       true,
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Generate failed: %s", result.Message)
+	}
+
+	var violations []string
+	for _, w := range result.SemanticWarnings {
+		if strings.Contains(w, "constraint_violation") {
+			violations = append(violations, w)
+		}
+	}
+	if len(violations) != 1 || !strings.HasPrefix(violations[0], "[Task 1]") || !strings.Contains(violations[0], "ReadReplica") {
+		t.Errorf("expected one ReadReplica violation for task 1, got %v", violations)
+	}
+}
+
+func TestPlanApp_GenerateCategorizesWarnings(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "internal", "store"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	planApp := newTestPlanApp(t)
+	planApp.ctx.BasePath = dir
+	planApp.TaskEnricher = nil
+	planApp.PlannerFactory = func(llm.Config) TaskPlanner {
+		return &staticPlanner{tasks: []impl.PlanningTask{
+			{Title: "Tidy logging", Description: "Update internal/api/zz_handler_missing.go to log errors", Priority: 10},
+			{Title: "Cover the store", Description: "Add store tests", Priority: 20, ValidationSteps: task.CommandValidationSteps([]string{"go test ./internal/db/store/..."})},
+		}}
+	}
+
+	result, err := planApp.Generate(ctx, GenerateOptions{Goal: "Cleanup", EnrichedGoal: "Clean up logging and storage", DryRun: true})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Generate failed: %s", result.Message)
+	}
+	if len(result.Warnings) != len(result.SemanticWarnings) {
+		t.Errorf("flat warnings %v should mirror categorized %v", result.SemanticWarnings, result.Warnings)
+	}
+
+	found := make(map[WarningCategory]Warning)
+	for _, w := range result.Warnings {
+		if _, seen := found[w.Category]; !seen {
+			found[w.Category] = w
+		}
+	}
+	if w, ok := found[WarningCategoryPath]; !ok || w.TaskIndex != 0 || !strings.Contains(w.Message, "zz_handler_missing.go") {
+		t.Errorf("path warning = %+v, want task 0 missing file", w)
+	}
+	if w, ok := found[WarningCategoryCommand]; !ok || w.TaskIndex != 1 || !strings.Contains(w.Message, "./internal/store/...") {
+		t.Errorf("command warning = %+v, want task 1 corrected package", w)
+	}
+}
+
+func TestPlanApp_GenerateWithoutCodeIntel(t *testing.T) {
+	ctx := context.Background()
+	// Point memory at an empty directory so no memory.db exists
+	viper.Set("memory.path", t.TempDir())
+	t.Cleanup(func() { viper.Set("memory.path", "") })
+
+	planApp := newTestPlanApp(t)
+	planApp.ctx.BasePath = t.TempDir()
+	planApp.TaskEnricher = nil
+	planApp.PlannerFactory = func(llm.Config) TaskPlanner {
+		return &staticPlanner{tasks: []impl.PlanningTask{{Title: "Add health check", Description: "Expose /healthz", Priority: 10}}}
+	}
+
+	result, err := planApp.Generate(ctx, GenerateOptions{Goal: "Health", EnrichedGoal: "Add a health endpoint", DryRun: true})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Generate failed: %s", result.Message)
+	}
+	if result.CodeIntelAvailable {
+		t.Error("CodeIntelAvailable = true without a code index")
+	}
+	var hint *Warning
+	for i, w := range result.Warnings {
+		if w.Category == WarningCategoryCodeIntel {
+			hint = &result.Warnings[i]
+		}
+	}
+	if hint == nil || !strings.Contains(hint.Message, "taskwing bootstrap") {
+		t.Errorf("warnings = %+v, want a codeintel hint to run bootstrap", result.Warnings)
+	}
+}
+
+func TestPlanApp_EnrichPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.contextRecall = func(_ context.Context, opts knowledge.ContextOptions) (*knowledge.ProjectContext, error) {
+		if strings.HasPrefix(opts.Query, "broken") {
+			return nil, errors.New("fts unavailable")
+		}
+		node := &memory.Node{ID: "n-auth", Type: memory.NodeTypeDecision, Summary: "JWT auth middleware"}
+		return &knowledge.ProjectContext{RelevantNodes: []knowledge.ScoredNode{{Node: node, Score: 0.9}}}, nil
+	}
+
+	result, err := planApp.defaultTaskEnricher(ctx, []string{"auth middleware", "broken query"}, "api")
+	if err != nil {
+		t.Fatalf("defaultTaskEnricher: %v", err)
+	}
+	if result.FailedQueries != 1 {
+		t.Errorf("FailedQueries = %d, want 1", result.FailedQueries)
+	}
+	if !strings.Contains(result.Context, "JWT auth middleware") {
+		t.Errorf("expected successful context to be kept, got:\n%s", result.Context)
+	}
+
+	tk := task.Task{Title: "Add login", SuggestedAskQueries: []string{"auth middleware", "broken query"}}
+	planApp.enrichTask(ctx, &tk)
+	if tk.EnrichmentErrors != 1 || tk.ContextSummary == "" {
+		t.Errorf("task EnrichmentErrors = %d, ContextSummary empty = %v", tk.EnrichmentErrors, tk.ContextSummary == "")
+	}
+}
+
+func TestPlanApp_TaskIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.TaskEnricher = nil
+	n := 0
+	planApp.TaskIDGenerator = func() string {
+		n++
+		return fmt.Sprintf("task-%d", n)
+	}
+
+	metadata := map[string]any{"tasks": []any{
+		map[string]any{"title": "Add schema", "description": "a"},
+		map[string]any{"title": "Add login", "description": "b", "dependencies": []any{"Add schema"}},
+		map[string]any{"title": "Add logout", "description": "c", "dependencies": []any{"Add login", "Add schema"}},
+	}}
+	tasks := planApp.parseTasksFromMetadata(ctx, metadata)
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(tasks))
+	}
+	for i, tk := range tasks {
+		if want := fmt.Sprintf("task-%d", i+1); tk.ID != want {
+			t.Errorf("task %d ID = %s, want %s", i, tk.ID, want)
+		}
+	}
+	if strings.Join(tasks[1].Dependencies, ",") != "task-1" {
+		t.Errorf("Add login dependencies = %v, want [task-1]", tasks[1].Dependencies)
+	}
+	if strings.Join(tasks[2].Dependencies, ",") != "task-2,task-1" {
+		t.Errorf("Add logout dependencies = %v, want [task-2 task-1]", tasks[2].Dependencies)
+	}
+}
+
+// countingAuditor is a PlanAuditor that records how often it runs and which
+// tasks it was asked to check.
+type countingAuditor struct {
+	calls   int
+	audited []string
+}
+
+func (c *countingAuditor) Audit(_ context.Context, plan *task.Plan, _ bool) (*task.AuditReport, error) {
+	c.calls++
+	c.audited = c.audited[:0]
+	for _, tk := range plan.Tasks {
+		c.audited = append(c.audited, tk.Title)
+	}
+	return &task.AuditReport{Status: "passed", CompletedAt: time.Now()}, nil
+}
+
+func TestPlanApp_AuditCachesUnchangedTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	mainPath := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(mainPath, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "-C", dir, "add", "main.go").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.ctx.BasePath = dir
+	auditor := &countingAuditor{}
+	planApp.Auditor = auditor
+
+	plan := &task.Plan{Goal: "Ship it", Status: task.PlanStatusCompleted}
+	if err := planApp.Repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	audit := func(force bool) *AuditResult {
+		t.Helper()
+		result, err := planApp.Audit(ctx, AuditOptions{PlanID: plan.ID, Force: force})
+		if err != nil {
+			t.Fatalf("Audit: %v", err)
+		}
+		if !result.Success || result.Status != "verified" {
+			t.Fatalf("audit failed: %+v", result)
+		}
+		return result
+	}
+
+	if first := audit(false); first.Cached || auditor.calls != 1 {
+		t.Fatalf("first audit should run: cached=%v calls=%d", first.Cached, auditor.calls)
+	}
+	if second := audit(false); !second.Cached || second.Message != "verified (cached)" || auditor.calls != 1 {
+		t.Fatalf("unchanged re-audit should be cached: cached=%v message=%q calls=%d", second.Cached, second.Message, auditor.calls)
+	}
+	if forced := audit(true); forced.Cached || auditor.calls != 2 {
+		t.Fatalf("forced audit should run: cached=%v calls=%d", forced.Cached, auditor.calls)
+	}
+
+	if err := os.WriteFile(mainPath, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed := audit(false); changed.Cached || auditor.calls != 3 {
+		t.Fatalf("audit after a file change should run: cached=%v calls=%d", changed.Cached, auditor.calls)
+	}
+}
+
+func TestPlanApp_AuditNoActivePlanHint(t *testing.T) {
+	planApp := newTestPlanApp(t)
+
+	result, err := planApp.Audit(context.Background(), AuditOptions{})
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if result.Success || result.Code != PlanErrorNoActivePlan {
+		t.Fatalf("expected no_active_plan, got %+v", result)
+	}
+	_, ensureErr := planApp.EnsureActivePlan(context.Background())
+	if want := ErrorHint(ensureErr); want == "" || result.Hint != want {
+		t.Errorf("Hint = %q, want the EnsureActivePlan hint %q", result.Hint, want)
+	}
+}
+
+func TestPlanApp_AuditIgnoresSkipAuditTasks(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.ctx.BasePath = t.TempDir() // Not a git repo, so audits are never cached
+	auditor := &countingAuditor{}
+	planApp.Auditor = auditor
+
+	// The doc-only task is still pending, yet must not block verification
+	plan := &task.Plan{Goal: "Ship it", Status: task.PlanStatusActive, Tasks: []task.Task{
+		{Title: "Add export endpoint", Status: task.StatusCompleted, Priority: 10},
+		{Title: "Document export endpoint", Status: task.StatusPending, Priority: 20, SkipAudit: true},
+	}}
+	if err := planApp.Repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+
+	result, err := planApp.Audit(ctx, AuditOptions{PlanID: plan.ID})
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if !result.Success || result.Status != "verified" {
+		t.Fatalf("audit should verify: %+v", result)
+	}
+	if strings.Join(auditor.audited, ",") != "Add export endpoint" {
+		t.Errorf("auditor checked %v, want only the code task", auditor.audited)
+	}
+
+	// A plan of only doc tasks verifies without running build/test
+	docsOnly := &task.Plan{Goal: "Docs", Status: task.PlanStatusActive, Tasks: []task.Task{
+		{Title: "Update README", Status: task.StatusPending, SkipAudit: true},
+	}}
+	if err := planApp.Repo.CreatePlan(docsOnly); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	result, err = planApp.Audit(ctx, AuditOptions{PlanID: docsOnly.ID})
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if !result.Success || result.Status != "verified" || auditor.calls != 1 {
+		t.Errorf("doc-only plan: status=%q calls=%d, want verified without running the auditor", result.Status, auditor.calls)
+	}
+
+	// Without build or test commands, only plans needing no checks verify
+	planApp.Auditor = nil
+	if result, err = planApp.Audit(ctx, AuditOptions{PlanID: docsOnly.ID}); err != nil || !result.Success {
+		t.Errorf("doc-only plan without auditor: %+v, %v; want verified", result, err)
+	}
+	if result, err = planApp.Audit(ctx, AuditOptions{PlanID: plan.ID}); err != nil || result.Code != PlanErrorUnsupported {
+		t.Errorf("code plan without auditor: %+v, %v; want %s", result, err, PlanErrorUnsupported)
+	}
+}
+
+func TestCommandAuditor_ReportsBuildAndTestSeparately(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+	dir := t.TempDir()
+	if NewCommandAuditor(dir) != nil {
+		t.Fatal("a directory without a manifest should have no auditor")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if a := NewCommandAuditor(dir); a == nil || a.BuildCommand != "go build ./..." || a.TestCommand != "go test ./..." {
+		t.Fatalf("go.mod auditor = %+v, want go build and go test", a)
+	}
+
+	planApp := newTestPlanApp(t)
+	planApp.ctx.BasePath = dir
+	planApp.Auditor = &CommandAuditor{Dir: dir, BuildCommand: "true", TestCommand: "echo 1 test failed; exit 1"}
+	plan := &task.Plan{Goal: "Ship it", Status: task.PlanStatusCompleted}
+	if err := planApp.Repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+
+	result, err := planApp.Audit(context.Background(), AuditOptions{PlanID: plan.ID})
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if !result.Success || result.Status != "needs_revision" {
+		t.Fatalf("audit with failing tests: %+v", result)
+	}
+	if !result.BuildPassed || result.TestsPassed {
+		t.Errorf("BuildPassed=%v TestsPassed=%v, want build passed and tests failed", result.BuildPassed, result.TestsPassed)
+	}
+	if result.Report == nil || !strings.Contains(string(result.Report.ToJUnit()), `<failure message="test failed">`) {
+		t.Errorf("JUnit report should fail only the test case, got %+v", result.Report)
+	}
+	saved, err := planApp.Repo.GetPlan(plan.ID)
+	if err != nil {
+		t.Fatalf("GetPlan: %v", err)
+	}
+	if !strings.Contains(saved.LastAuditReport, "1 test failed") {
+		t.Errorf("saved report should keep the test output, got %s", saved.LastAuditReport)
+	}
+}
+
+// streamingClarifier emits its questions one at a time, recording the order
+// of emitted questions and the final return.
+type streamingClarifier struct {
+	questions         []string
+	events            *[]string
+	autoAnswerContext string // Context passed to the last AutoAnswer call
+}
+
+func (m *streamingClarifier) output() core.Output {
+	return core.Output{Findings: []core.Finding{{Metadata: map[string]any{
+		"questions":        m.questions,
+		"goal_summary":     "Add auth",
+		"enriched_goal":    "Add session-based auth",
+		"is_ready_to_plan": false,
+	}}}}
+}
+
+func (m *streamingClarifier) Run(context.Context, core.Input) (core.Output, error) {
+	return m.output(), nil
+}
+
+func (m *streamingClarifier) RunStream(_ context.Context, _ core.Input, onQuestion func(string)) (core.Output, error) {
+	for _, q := range m.questions {
+		onQuestion(q)
+	}
+	*m.events = append(*m.events, "done")
+	return m.output(), nil
+}
+
+func (m *streamingClarifier) AutoAnswer(_ context.Context, _ string, _ []string, kgContext string) (string, error) {
+	m.autoAnswerContext = kgContext
+	return "", nil
+}
+
+func (m *streamingClarifier) Close() error { return nil }
+
+func TestPlanApp_ClarifyStreamsQuestions(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	var events []string
+	clarifier := &streamingClarifier{questions: []string{"Which auth provider?", "Do sessions expire?"}, events: &events}
+	planApp.ClarifierFactory = func(llm.Config) GoalsClarifier { return clarifier }
+
+	result, err := planApp.Clarify(ctx, ClarifyOptions{
+		Goal:       "add auth",
+		OnQuestion: func(q string) { events = append(events, q) },
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("Clarify: %v %+v", err, result)
+	}
+
+	want := []string{"Which auth provider?", "Do sessions expire?", "done"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %q, want each question before the stream ends: %q", events, want)
+	}
+	if len(result.Questions) != 2 {
+		t.Errorf("result questions = %q, want both streamed questions", result.Questions)
+	}
+
+	// A clarifier without streaming still reports each question, after the batch run
+	events = nil
+	planApp.ClarifierFactory = func(llm.Config) GoalsClarifier {
+		return &struct{ GoalsClarifier }{clarifier}
+	}
+	if _, err := planApp.Clarify(ctx, ClarifyOptions{
+		Goal:       "add auth",
+		OnQuestion: func(q string) { events = append(events, q) },
+	}); err != nil {
+		t.Fatalf("Clarify (batch): %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("batch fallback events = %q, want both questions", events)
+	}
+}
+
+func TestPlanApp_ClarifyAutoAnswerUsesConventions(t *testing.T) {
+	ctx := context.Background()
+	planApp := newTestPlanApp(t)
+	planApp.ctx.BasePath = t.TempDir()
+	conventions := "- Database: PostgreSQL via sqlc\n- Tests: table-driven with testify"
+	path := filepath.Join(planApp.ctx.BasePath, config.DefaultConventionsFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(conventions+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []string
+	clarifier := &streamingClarifier{questions: []string{"Which database?"}, events: &events}
+	planApp.ClarifierFactory = func(llm.Config) GoalsClarifier { return clarifier }
+
+	if _, err := planApp.Clarify(ctx, ClarifyOptions{Goal: "add audit log", AutoAnswer: true}); err != nil {
+		t.Fatalf("Clarify: %v", err)
+	}
+	if !strings.Contains(clarifier.autoAnswerContext, conventions) {
+		t.Errorf("auto-answer context should include the conventions file, got:\n%s", clarifier.autoAnswerContext)
+	}
+}
+
+func TestPlanApp_GenerateDuplicateTasks(t *testing.T) {
+	ctx := context.Background()
+	planner := &staticPlanner{tasks: []impl.PlanningTask{
+		{Title: "Create users table", Description: "Add a migration for users", Priority: 10},
+		{Title: "Add JWT middleware", Description: "Validate tokens on every request", Priority: 30,
+			Dependencies: []string{"Create users table"}, AcceptanceCriteria: []string{"Expired tokens are rejected"}},
+		{Title: "Add login endpoint", Description: "Issue tokens for valid credentials", Priority: 20},
+		{Title: "Add JWT auth middleware", Description: "Validate tokens on every request", Priority: 25,
+			Dependencies: []string{"Add login endpoint"}, AcceptanceCriteria: []string{"Missing tokens get a 401"}},
+		{Title: "Protect admin routes", Description: "Require an admin role", Priority: 40,
+			Dependencies: []string{"Add JWT auth middleware"}},
+	}}
+
+	generate := func(merge bool) *GenerateResult {
+		t.Helper()
+		planApp := newTestPlanApp(t)
+		planApp.TaskEnricher = nil
+		planApp.PlannerFactory = func(llm.Config) TaskPlanner { return planner }
+		result, err := planApp.Generate(ctx, GenerateOptions{
+			Goal:            "Add auth",
+			EnrichedGoal:    "Add JWT auth to the API",
+			DryRun:          true,
+			MergeDuplicates: merge,
+		})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Generate failed: %s", result.Message)
+		}
+		return result
+	}
+	duplicateWarnings := func(result *GenerateResult) []Warning {
+		var found []Warning
+		for _, w := range result.Warnings {
+			if w.Category == WarningCategoryDuplicate {
+				found = append(found, w)
+			}
+		}
+		return found
+	}
+
+	flagged := generate(false)
+	if len(flagged.Tasks) != 5 {
+		t.Errorf("without merge got %d tasks, want all 5", len(flagged.Tasks))
+	}
+	if w := duplicateWarnings(flagged); len(w) != 1 || w[0].TaskIndex != 3 {
+		t.Errorf("duplicate warnings = %+v, want one for task index 3", w)
+	}
+
+	merged := generate(true)
+	if len(merged.Tasks) != 4 {
+		t.Fatalf("with merge got %d tasks, want 4", len(merged.Tasks))
+	}
+	byTitle := make(map[string]task.Task)
+	for _, tk := range merged.Tasks {
+		byTitle[tk.Title] = tk
+	}
+	kept, ok := byTitle["Add JWT middleware"]
+	if !ok {
+		t.Fatalf("the earlier duplicate should be kept, got %+v", merged.Tasks)
+	}
+	if _, ok := byTitle["Add JWT auth middleware"]; ok {
+		t.Error("the later duplicate should be removed")
+	}
+	wantDeps := []string{byTitle["Create users table"].ID, byTitle["Add login endpoint"].ID}
+	if !slices.Equal(kept.Dependencies, wantDeps) {
+		t.Errorf("kept dependencies = %v, want %v", kept.Dependencies, wantDeps)
+	}
+	if len(kept.AcceptanceCriteria) != 2 || kept.Priority != 25 {
+		t.Errorf("kept task criteria = %v priority = %d, want both criteria and priority 25", kept.AcceptanceCriteria, kept.Priority)
+	}
+	if deps := byTitle["Protect admin routes"].Dependencies; !slices.Equal(deps, []string{kept.ID}) {
+		t.Errorf("dependents of the removed task = %v, want redirected to %s", deps, kept.ID)
+	}
+	if w := duplicateWarnings(merged); len(w) != 1 || merged.Tasks[w[0].TaskIndex].ID != kept.ID {
+		t.Errorf("merge warnings = %+v, want one pointing at the kept task", w)
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WarningCategory groups plan generation warnings by what raised them.
+type WarningCategory string
+
+const (
+	WarningCategoryPath       WarningCategory = "path"       // Missing or corrected file paths
+	WarningCategoryCommand    WarningCategory = "command"    // Validation step commands that were corrected or could not be checked
+	WarningCategoryConstraint WarningCategory = "constraint" // Tasks that appear to break a project constraint
+	WarningCategoryVerifier   WarningCategory = "verifier"   // Summary of corrections applied by the plan verifier
+	WarningCategoryTask       WarningCategory = "task"       // Tasks skipped or not fully saved while streaming
+	WarningCategoryCodeIntel  WarningCategory = "codeintel"  // Code index missing or empty, so the verifier could not auto-correct
+	WarningCategoryDuplicate  WarningCategory = "duplicate"  // Near-duplicate tasks, flagged or merged
+)
+
+// Warning is a non-blocking plan generation issue.
+type Warning struct {
+	Category  WarningCategory `json:"category"`
+	TaskIndex int             `json:"task_index"` // 0-based task index; -1 for plan-wide warnings
+	Message   string          `json:"message"`
+}
+
+// String renders the warning in the flat SemanticWarnings format.
+func (w Warning) String() string {
+	if w.TaskIndex < 0 {
+		return w.Message
+	}
+	return fmt.Sprintf("[Task %d] %s", w.TaskIndex+1, w.Message)
+}
+
+// warningList collects warnings in both the categorized and flat forms.
+type warningList struct {
+	items []Warning
+	flat  []string
+}
+
+func (l *warningList) add(category WarningCategory, taskIndex int, message string) {
+	w := Warning{Category: category, TaskIndex: taskIndex, Message: message}
+	l.items = append(l.items, w)
+	l.flat = append(l.flat, w.String())
+}
+
+func (l *warningList) len() int {
+	return len(l.items)
+}
+
+// semanticWarningCategory maps a planner.SemanticWarning type to its category.
+func semanticWarningCategory(warningType string) WarningCategory {
+	if strings.Contains(warningType, "command") {
+		return WarningCategoryCommand
+	}
+	return WarningCategoryPath
+}
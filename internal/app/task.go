@@ -2,11 +2,23 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
-
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/impl"
+	"github.com/josephgoksu/TaskWing/internal/codeintel"
 	"github.com/josephgoksu/TaskWing/internal/git"
+	"github.com/josephgoksu/TaskWing/internal/knowledge"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
 	"github.com/josephgoksu/TaskWing/internal/policy"
 	"github.com/josephgoksu/TaskWing/internal/task"
 )
@@ -14,12 +26,17 @@ import (
 // TaskResult contains the result of a task operation.
 // This is the canonical response type used by both CLI and MCP.
 type TaskResult struct {
-	Success bool       `json:"success"`
-	Message string     `json:"message,omitempty"`
-	Task    *task.Task `json:"task,omitempty"`
-	Plan    *task.Plan `json:"plan,omitempty"`
-	Hint    string     `json:"hint,omitempty"`
-	Context string     `json:"context,omitempty"` // Rich Markdown context
+	Success bool          `json:"success"`
+	Message string        `json:"message,omitempty"`
+	Code    PlanErrorCode `json:"code,omitempty"` // Set for plan failures, e.g. no active plan
+	Task    *task.Task    `json:"task,omitempty"`
+	Plan    *task.Plan    `json:"plan,omitempty"`
+	Hint    string        `json:"hint,omitempty"`
+	Context string        `json:"context,omitempty"` // Rich Markdown context
+
+	// Symbols is the code the task touches, resolved by Current from its
+	// expected files and keywords
+	Symbols []codeintel.Symbol `json:"symbols,omitempty"`
 
 	// Git workflow fields
 	GitBranch          string `json:"git_branch,omitempty"`
@@ -51,6 +68,7 @@ type TaskNextOptions struct {
 	AutoStart         bool   // If true, automatically claim the task
 	CreateBranch      bool   // Create a new git branch for this plan (default: true)
 	SkipUnpushedCheck bool   // If true, proceed despite unpushed commits
+	Agent             string // Optional: only tasks assigned to this agent (task.AgentUnassigned for none)
 }
 
 // TaskStartOptions configures the behavior of starting a task.
@@ -61,20 +79,48 @@ type TaskStartOptions struct {
 
 // TaskCompleteOptions configures the behavior of completing a task.
 type TaskCompleteOptions struct {
-	TaskID        string   // Required: task to complete
-	Summary       string   // Optional: what was accomplished
-	FilesModified []string // Optional: files changed
+	TaskID         string   // Required: task to complete
+	Summary        string   // Optional: what was accomplished
+	FilesModified  []string // Optional: files changed
+	VerifyCriteria bool     // Optional: check each acceptance criterion against the touched files (uses LLM)
+}
+
+// Task list sort orders.
+const (
+	TaskSortPriority = "priority" // Most urgent first (lowest priority value)
+	TaskSortCreated  = "created"  // Oldest first
+)
+
+// TaskListOptions configures a filtered task listing.
+type TaskListOptions struct {
+	PlanID string          // Optional: plan to list (defaults to active)
+	Status task.TaskStatus // Optional: only tasks with this status
+	Phase  string          // Optional: phase ID, title, or 1-based position
+	Sort   string          // TaskSortPriority (default) or TaskSortCreated
+	Limit  int             // Optional: maximum number of tasks (0 = all)
 }
 
 // TaskApp provides task lifecycle operations.
 // This is THE implementation - CLI and MCP both call these methods.
 type TaskApp struct {
 	ctx *Context
+
+	// TaskEnricher re-runs a task's ask queries for RefreshContext.
+	// Defaults to the PlanApp enricher used at task creation.
+	TaskEnricher TaskContextEnricher
+
+	// CriteriaVerifierFactory creates the agent used by Complete with VerifyCriteria.
+	CriteriaVerifierFactory func(llm.Config) CriteriaVerifier
 }
 
 // NewTaskApp creates a new task application service.
 func NewTaskApp(ctx *Context) *TaskApp {
-	return &TaskApp{ctx: ctx}
+	return &TaskApp{
+		ctx: ctx,
+		CriteriaVerifierFactory: func(cfg llm.Config) CriteriaVerifier {
+			return impl.NewCriteriaVerificationAgent(cfg)
+		},
+	}
 }
 
 // Next gets the next pending task with optional git workflow and auto-start.
@@ -93,25 +139,34 @@ func (a *TaskApp) Next(ctx context.Context, opts TaskNextOptions) (*TaskResult,
 			return nil, fmt.Errorf("get plan: %w", err)
 		}
 	} else {
-		activePlan, err := repo.GetActivePlan()
-		if err != nil {
-			return nil, fmt.Errorf("get active plan: %w", err)
-		}
-		if activePlan == nil {
+		activePlan, err := ensureActivePlan(repo)
+		if errors.Is(err, ErrNoActivePlan) {
 			return &TaskResult{
 				Success: false,
-				Message: "No active plan found. Use /taskwing:plan to create one.",
+				Message: "No active plan found.",
+				Code:    PlanErrorNoActivePlan,
+				Hint:    ErrorHint(err),
 			}, nil
 		}
+		if err != nil {
+			return nil, err
+		}
 		planID = activePlan.ID
 		plan = activePlan
 	}
 
-	// Get next pending task
-	nextTask, err := repo.GetNextTask(planID)
+	// Get next pending task, optionally for one agent
+	nextTask, err := repo.GetNextTaskForAgent(planID, opts.Agent)
 	if err != nil {
 		return nil, fmt.Errorf("get next task: %w", err)
 	}
+	if nextTask == nil && opts.Agent != "" {
+		return &TaskResult{
+			Success: true,
+			Message: fmt.Sprintf("No ready tasks assigned to %q in this plan.", opts.Agent),
+			Hint:    "Omit the agent filter to get the next task for any agent.",
+		}, nil
+	}
 	if nextTask == nil {
 		return &TaskResult{
 			Success: true,
@@ -219,22 +274,25 @@ func (a *TaskApp) Current(ctx context.Context, sessionID, planID string) (*TaskR
 				Task:    currentTask,
 				Plan:    plan,
 				Context: a.buildRichContext(ctx, currentTask, plan),
+				Symbols: a.relatedSymbols(ctx, currentTask),
 			}, nil
 		}
 	}
 
 	// Fallback: find any in-progress task in the plan
 	if planID == "" {
-		activePlan, err := repo.GetActivePlan()
-		if err != nil {
-			return nil, fmt.Errorf("get active plan: %w", err)
-		}
-		if activePlan == nil {
+		activePlan, err := ensureActivePlan(repo)
+		if errors.Is(err, ErrNoActivePlan) {
 			return &TaskResult{
 				Success: false,
 				Message: "No active plan found.",
+				Code:    PlanErrorNoActivePlan,
+				Hint:    ErrorHint(err),
 			}, nil
 		}
+		if err != nil {
+			return nil, err
+		}
 		planID = activePlan.ID
 	}
 
@@ -257,9 +315,62 @@ func (a *TaskApp) Current(ctx context.Context, sessionID, planID string) (*TaskR
 		Plan:    plan,
 		Message: "Found in-progress task (may be from a different session).",
 		Context: a.buildRichContext(ctx, inProgressTask, plan),
+		Symbols: a.relatedSymbols(ctx, inProgressTask),
 	}, nil
 }
 
+const (
+	maxTaskSymbols        = 10 // Related symbols attached to the current task
+	maxTaskSymbolsPerFile = 5  // So one large expected file can't crowd out the rest
+)
+
+// relatedSymbols resolves the code a task touches through the code index:
+// symbols defined in its expected files first, then symbols matching its
+// keywords. A missing index or failed lookup yields fewer (or no) symbols.
+func (a *TaskApp) relatedSymbols(ctx context.Context, t *task.Task) []codeintel.Symbol {
+	if a.ctx == nil || a.ctx.Repo == nil || (len(t.ExpectedFiles) == 0 && len(t.Keywords) == 0) {
+		return nil
+	}
+	qs, err := NewCodeIntelApp(a.ctx).getQueryService()
+	if err != nil {
+		return nil
+	}
+
+	var symbols []codeintel.Symbol
+	seen := make(map[uint32]bool)
+	add := func(sym codeintel.Symbol) {
+		if seen[sym.ID] || len(symbols) >= maxTaskSymbols {
+			return
+		}
+		seen[sym.ID] = true
+		sym.Embedding = nil
+		symbols = append(symbols, sym)
+	}
+
+	for _, file := range t.ExpectedFiles {
+		inFile, err := qs.GetSymbolsInFile(ctx, filepath.ToSlash(filepath.Clean(file)))
+		if err != nil {
+			slog.Debug("task symbol lookup failed", "task", t.ID, "file", file, "error", err)
+			continue
+		}
+		for _, sym := range inFile[:min(len(inFile), maxTaskSymbolsPerFile)] {
+			add(sym)
+		}
+	}
+
+	if len(t.Keywords) > 0 && len(symbols) < maxTaskSymbols {
+		// FTS ORs the words, so one search covers every keyword
+		results, err := qs.HybridSearch(ctx, strings.Join(t.Keywords, " "), maxTaskSymbols)
+		if err != nil {
+			slog.Debug("task symbol search failed", "task", t.ID, "error", err)
+		}
+		for _, r := range results {
+			add(r.Symbol)
+		}
+	}
+	return symbols
+}
+
 // Start claims a specific task for a session.
 func (a *TaskApp) Start(ctx context.Context, opts TaskStartOptions) (*TaskResult, error) {
 	if opts.TaskID == "" {
@@ -320,7 +431,72 @@ func (a *TaskApp) Start(ctx context.Context, opts TaskStartOptions) (*TaskResult
 	}, nil
 }
 
+// RefreshContext re-runs a task's ask queries against the current knowledge
+// graph and replaces its ContextSummary. The summary is bound when the task is
+// created and goes stale as knowledge changes; refresh before starting a task
+// that has waited a long time. The existing summary is kept if the refresh
+// finds nothing or every query fails.
+func (a *TaskApp) RefreshContext(ctx context.Context, taskID string) (*TaskResult, error) {
+	if taskID == "" {
+		return &TaskResult{
+			Success: false,
+			Message: "task_id is required",
+		}, nil
+	}
+
+	repo := a.ctx.Repo
+	t, err := repo.GetTask(taskID)
+	if err != nil {
+		return &TaskResult{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	// Tasks created before AI fields existed have no queries yet
+	if len(t.SuggestedAskQueries) == 0 {
+		t.EnrichAIFields()
+	}
+
+	enricher := a.TaskEnricher
+	if enricher == nil {
+		enricher = NewPlanApp(a.ctx).TaskEnricher
+	}
+	enrichment, err := enricher(ctx, t.SuggestedAskQueries, t.Scope)
+	if err != nil {
+		return &TaskResult{
+			Success: false,
+			Message: fmt.Sprintf("refresh context: %v", err),
+			Task:    t,
+		}, nil
+	}
+	if enrichment.Context == "" {
+		return &TaskResult{
+			Success: true,
+			Message: "No matching knowledge found; kept the existing context summary.",
+			Task:    t,
+		}, nil
+	}
+
+	if err := repo.UpdateTaskContextSummary(t.ID, enrichment.Context, enrichment.FailedQueries); err != nil {
+		return nil, fmt.Errorf("save refreshed context: %w", err)
+	}
+	t.ContextSummary = enrichment.Context
+	t.EnrichmentErrors = enrichment.FailedQueries
+
+	message := "Context summary refreshed."
+	if enrichment.FailedQueries > 0 {
+		message = fmt.Sprintf("Context summary refreshed (%d of %d queries failed).", enrichment.FailedQueries, len(t.SuggestedAskQueries))
+	}
+	return &TaskResult{
+		Success: true,
+		Message: message,
+		Task:    t,
+	}, nil
+}
+
 // List returns all tasks, optionally filtered by plan.
+// Without a plan filter, tasks of archived plans are left out.
 func (a *TaskApp) List(ctx context.Context, planID string) ([]task.Task, error) {
 	repo := a.ctx.Repo
 
@@ -328,8 +504,8 @@ func (a *TaskApp) List(ctx context.Context, planID string) ([]task.Task, error)
 		return repo.ListTasks(planID)
 	}
 
-	// Get all tasks across all plans
-	plans, err := repo.ListPlans()
+	// Get all tasks across all non-archived plans
+	plans, err := repo.ListPlans(task.PlanFilter{})
 	if err != nil {
 		return nil, fmt.Errorf("list plans: %w", err)
 	}
@@ -345,6 +521,187 @@ func (a *TaskApp) List(ctx context.Context, planID string) ([]task.Task, error)
 	return allTasks, nil
 }
 
+// Reorder reassigns task priorities in a plan so that Next follows orderedIDs.
+// orderedIDs must list every task in the plan exactly once. An empty planID uses the active plan.
+func (a *TaskApp) Reorder(planID string, orderedIDs []string) error {
+	repo := a.ctx.Repo
+
+	if planID == "" {
+		activePlan, err := ensureActivePlan(repo)
+		if err != nil {
+			return err
+		}
+		planID = activePlan.ID
+	}
+
+	return repo.ReorderTasks(planID, orderedIDs)
+}
+
+// TaskDependencies returns the tasks in the same plan that taskID depends on
+// (upstream) and the tasks that depend on it (downstream), in plan order.
+func (a *TaskApp) TaskDependencies(taskID string) (upstream, downstream []task.Task, err error) {
+	repo := a.ctx.Repo
+
+	t, err := repo.GetTask(taskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get task: %w", err)
+	}
+	tasks, err := repo.ListTasks(t.PlanID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list tasks: %w", err)
+	}
+
+	for _, other := range tasks {
+		if other.ID == t.ID {
+			continue
+		}
+		if slices.Contains(t.Dependencies, other.ID) {
+			upstream = append(upstream, other)
+		}
+		if slices.Contains(other.Dependencies, t.ID) {
+			downstream = append(downstream, other)
+		}
+	}
+	return upstream, downstream, nil
+}
+
+// Split breaks a pending task into subtasks, one per title. Each subtask
+// takes the parent's plan, phase, priority, scope, description, acceptance
+// criteria and knowledge context and waits on the parent's dependencies; the
+// parent then depends on every subtask, so it becomes ready once they are
+// done. The subtasks are created in one transaction.
+func (a *TaskApp) Split(taskID string, subtaskTitles []string) ([]task.Task, error) {
+	repo := a.ctx.Repo
+
+	var titles []string
+	for _, title := range subtaskTitles {
+		if title = strings.TrimSpace(title); title != "" {
+			titles = append(titles, title)
+		}
+	}
+	if len(titles) < 2 {
+		return nil, fmt.Errorf("split needs at least two subtask titles, got %d", len(titles))
+	}
+
+	parent, err := repo.GetTask(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get task: %w", err)
+	}
+	if parent.Status != task.StatusPending {
+		return nil, fmt.Errorf("task %s is %s; only pending tasks can be split", parent.ID, parent.Status)
+	}
+	contextNodes, err := repo.GetTaskContextNodes(parent.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get task context: %w", err)
+	}
+
+	description := fmt.Sprintf("Part of %q.", parent.Title)
+	if parent.Description != "" {
+		description += "\n\n" + parent.Description
+	}
+	subtasks := make([]task.Task, 0, len(titles))
+	for _, title := range titles {
+		subtasks = append(subtasks, task.Task{
+			PlanID:              parent.PlanID,
+			PhaseID:             parent.PhaseID,
+			Title:               title,
+			Description:         description,
+			AcceptanceCriteria:  slices.Clone(parent.AcceptanceCriteria),
+			Status:              task.StatusPending,
+			Priority:            parent.Priority,
+			AssignedAgent:       parent.AssignedAgent,
+			ContextSummary:      parent.ContextSummary,
+			Scope:               parent.Scope,
+			Keywords:            slices.Clone(parent.Keywords),
+			SuggestedAskQueries: slices.Clone(parent.SuggestedAskQueries),
+			Dependencies:        slices.Clone(parent.Dependencies),
+			ContextNodes:        slices.Clone(contextNodes),
+		})
+	}
+	if err := repo.SplitTask(parent.ID, subtasks); err != nil {
+		return nil, fmt.Errorf("split task: %w", err)
+	}
+	return subtasks, nil
+}
+
+// ListTasks returns a plan's tasks filtered by status and phase, sorted, and limited.
+func (a *TaskApp) ListTasks(_ context.Context, opts TaskListOptions) ([]task.Task, error) {
+	repo := a.ctx.Repo
+
+	planID := opts.PlanID
+	if planID == "" {
+		activePlan, err := ensureActivePlan(repo)
+		if err != nil {
+			return nil, err
+		}
+		planID = activePlan.ID
+	}
+
+	sortBy := opts.Sort
+	if sortBy == "" {
+		sortBy = TaskSortPriority
+	}
+	if sortBy != TaskSortPriority && sortBy != TaskSortCreated {
+		return nil, fmt.Errorf("invalid sort %q: must be %s or %s", opts.Sort, TaskSortPriority, TaskSortCreated)
+	}
+
+	var phaseID string
+	if opts.Phase != "" {
+		phases, err := repo.ListPhases(planID)
+		if err != nil {
+			return nil, fmt.Errorf("list phases: %w", err)
+		}
+		phaseID = matchPhase(phases, opts.Phase)
+		if phaseID == "" {
+			return nil, fmt.Errorf("phase not found in plan %s: %s", planID, opts.Phase)
+		}
+	}
+
+	tasks, err := repo.ListTasks(planID)
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+
+	filtered := tasks[:0]
+	for _, t := range tasks {
+		if opts.Status != "" && t.Status != opts.Status {
+			continue
+		}
+		if phaseID != "" && t.PhaseID != phaseID {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if sortBy == TaskSortPriority && filtered[i].Priority != filtered[j].Priority {
+			return filtered[i].Priority < filtered[j].Priority
+		}
+		if sortBy == TaskSortPriority && filtered[i].SortOrder != filtered[j].SortOrder {
+			return filtered[i].SortOrder < filtered[j].SortOrder
+		}
+		return filtered[i].CreatedAt.Before(filtered[j].CreatedAt)
+	})
+
+	if opts.Limit > 0 && len(filtered) > opts.Limit {
+		filtered = filtered[:opts.Limit]
+	}
+	return filtered, nil
+}
+
+// matchPhase resolves a phase reference (ID, title, or 1-based position) to a phase ID.
+func matchPhase(phases []task.Phase, ref string) string {
+	for _, p := range phases {
+		if p.ID == ref || strings.EqualFold(p.Title, ref) {
+			return p.ID
+		}
+	}
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(phases) {
+		return phases[n-1].ID
+	}
+	return ""
+}
+
 // Complete marks a task as completed with git workflow and optional PR creation.
 func (a *TaskApp) Complete(ctx context.Context, opts TaskCompleteOptions) (*TaskResult, error) {
 	if opts.TaskID == "" {
@@ -384,7 +741,7 @@ func (a *TaskApp) Complete(ctx context.Context, opts TaskCompleteOptions) (*Task
 	}
 
 	// Create policy engine from .taskwing/policies/
-	policyEngine, policyErr := policy.NewEngine(policy.EngineConfig{
+	policyEngine, policyErr := policy.NewEngine(ctx, policy.EngineConfig{
 		WorkDir: workDir,
 	})
 
@@ -450,6 +807,22 @@ func (a *TaskApp) Complete(ctx context.Context, opts TaskCompleteOptions) (*Task
 	sentinel := task.NewSentinel()
 	sentinelReport := sentinel.AnalyzeWithVerification(ctx, completedTask, workDir)
 
+	// Acceptance criteria verification is advisory: failures never undo the completion
+	var criteriaNote string
+	if opts.VerifyCriteria && len(completedTask.AcceptanceCriteria) > 0 {
+		results, err := a.verifyCriteria(ctx, completedTask, workDir)
+		if err == nil {
+			err = repo.UpdateTaskCriteriaVerification(completedTask.ID, results)
+		}
+		if err != nil {
+			slog.Warn("acceptance criteria verification failed", "task", completedTask.ID, "error", err)
+			criteriaNote = " Criteria verification failed."
+		} else {
+			completedTask.CriteriaVerification = results
+			criteriaNote = fmt.Sprintf(" Criteria verified: %d/%d.", countPassed(results), len(results))
+		}
+	}
+
 	// Git auto-commit and push
 	var gitBranch string
 	var gitCommitApplied bool
@@ -564,6 +937,7 @@ func (a *TaskApp) Complete(ctx context.Context, opts TaskCompleteOptions) (*Task
 	if prCreated {
 		message += " PR created."
 	}
+	message += criteriaNote
 
 	// Add Sentinel deviation warning to message and hint
 	if sentinelReport.HasDeviations() {
@@ -620,33 +994,55 @@ func (a *TaskApp) executeGitWorkflow(plan *task.Plan, skipUnpushedCheck bool) (*
 	return gitClient.StartPlanWorkflow(plan.ID, plan.Goal, skipUnpushedCheck)
 }
 
-// buildRichContext creates markdown context for a task using AskApp.
+// buildRichContext creates markdown context for a task, late-binding its
+// context summary through AskApp when none was stored at creation.
 func (a *TaskApp) buildRichContext(ctx context.Context, t *task.Task, plan *task.Plan) string {
 	if plan == nil {
 		return ""
 	}
+	a.lateBindContext(ctx, t)
+	return task.FormatRichContext(ctx, t, plan, nil)
+}
+
+// lateBindContext fills an empty ContextSummary by recalling the task's ask
+// queries through AskApp, formatted like the early-bound summary from
+// defaultTaskEnricher. The result is not persisted, so a task whose early
+// binding came up empty picks up knowledge added since it was created.
+func (a *TaskApp) lateBindContext(ctx context.Context, t *task.Task) {
+	if t.ContextSummary != "" || a.ctx == nil || a.ctx.Repo == nil {
+		return
+	}
+
+	queries := t.SuggestedAskQueries
+	if len(queries) == 0 {
+		probe := *t
+		probe.EnrichAIFields()
+		queries = probe.SuggestedAskQueries
+	}
 
-	// Create a search function that uses AskApp
 	askApp := NewAskApp(a.ctx)
-	searchFunc := func(ctx context.Context, query string, limit int) ([]task.AskResult, error) {
+	pc := &knowledge.ProjectContext{}
+	seen := make(map[string]bool)
+	for _, query := range queries {
 		result, err := askApp.Query(ctx, query, AskOptions{
-			Limit:          limit,
+			Limit:          3,
 			GenerateAnswer: false,
+			NoRewrite:      true,
 		})
 		if err != nil {
-			return nil, err
+			slog.Debug("late-binding recall failed", "task", t.ID, "query", query, "error", err)
+			continue
 		}
-
-		var adapted []task.AskResult
 		for _, r := range result.Results {
-			adapted = append(adapted, task.AskResult{
-				Summary: r.Summary,
-				Type:    r.Type,
-				Content: r.Content,
+			if seen[r.ID] {
+				continue
+			}
+			seen[r.ID] = true
+			pc.RelevantNodes = append(pc.RelevantNodes, knowledge.ScoredNode{
+				Node:  &memory.Node{ID: r.ID, Type: r.Type, Summary: r.Summary, Content: r.Content},
+				Score: r.MatchScore,
 			})
 		}
-		return adapted, nil
 	}
-
-	return task.FormatRichContext(ctx, t, plan, searchFunc)
+	t.ContextSummary = pc.FormatCompact(a.ctx.LLMCfg.Model)
 }
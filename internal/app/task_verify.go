@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/agents/tools"
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+// CriteriaVerifier checks a completed task's acceptance criteria against its changes.
+// Its finding carries the results as Metadata["criteria"] ([]task.CriterionVerification).
+type CriteriaVerifier interface {
+	Run(ctx context.Context, input core.Input) (core.Output, error)
+	Close() error
+}
+
+// verifyCriteria runs the criteria verifier over a completed task and the
+// current contents of the files it touched. The result holds exactly one entry
+// per acceptance criterion, in order. Verdicts are matched to criteria by
+// text only: criteria no verdict names are reported as unmatched, and those
+// the verifier did not clearly pass as unknown.
+func (a *TaskApp) verifyCriteria(ctx context.Context, t *task.Task, workDir string) ([]task.CriterionVerification, error) {
+	verifier := a.CriteriaVerifierFactory(a.ctx.LLMCfg)
+	defer func() { _ = verifier.Close() }()
+
+	output, err := verifier.Run(ctx, core.Input{
+		BasePath: workDir,
+		ExistingContext: map[string]any{
+			"title":    t.Title,
+			"summary":  t.CompletionSummary,
+			"criteria": t.AcceptanceCriteria,
+			"files":    tools.NewContextGatherer(workDir).GatherSpecificFiles(t.FilesModified),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output.Error != nil {
+		return nil, output.Error
+	}
+	if len(output.Findings) == 0 {
+		return nil, fmt.Errorf("no findings from criteria verifier")
+	}
+	verdicts, _ := output.Findings[0].Metadata["criteria"].([]task.CriterionVerification)
+
+	// A verdict for a reworded criterion could belong to any of them, so
+	// verdicts are never assigned by position
+	byText := make(map[string]task.CriterionVerification, len(verdicts))
+	for _, v := range verdicts {
+		byText[criterionKey(v.Criterion)] = v
+	}
+	results := make([]task.CriterionVerification, len(t.AcceptanceCriteria))
+	for i, criterion := range t.AcceptanceCriteria {
+		v, ok := byText[criterionKey(criterion)]
+		if !ok {
+			results[i] = task.CriterionVerification{Criterion: criterion, Status: task.CriterionUnmatched}
+			continue
+		}
+		status := task.CriterionUnknown
+		if v.Status == task.CriterionPass {
+			status = task.CriterionPass
+		}
+		results[i] = task.CriterionVerification{Criterion: criterion, Status: status, Evidence: v.Evidence}
+	}
+	return results, nil
+}
+
+// criterionKey normalizes criterion text for matching, ignoring case and
+// differences in whitespace.
+func criterionKey(criterion string) string {
+	return strings.ToLower(strings.Join(strings.Fields(criterion), " "))
+}
+
+// countPassed returns how many criteria were verified as met.
+func countPassed(results []task.CriterionVerification) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == task.CriterionPass {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/config"
+)
+
+// gitignoreHeader precedes the entries TaskWing appends to .taskwing/.gitignore.
+const gitignoreHeader = "# Added by TaskWing: local state that should not be committed"
+
+// ensureGitignore adds the configured entries (config.LoadGitignoreEntries)
+// to <basePath>/.taskwing/.gitignore. Entries already present, including ones
+// the user wrote, are not repeated, so re-running bootstrap changes nothing.
+// Projects without a .taskwing/ directory are left alone: creating it would
+// make it the project marker and look like legacy local state to migrate.
+// It returns the entries it added.
+func (i *Initializer) ensureGitignore(verbose bool) ([]string, error) {
+	entries := config.LoadGitignoreEntries()
+	if i.basePath == "" || len(entries) == 0 {
+		return nil, nil
+	}
+
+	dir := filepath.Join(i.basePath, ".taskwing")
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+	path := filepath.Join(dir, ".gitignore")
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(existing), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || present[entry] {
+			continue
+		}
+		present[entry] = true
+		missing = append(missing, entry)
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	b.Write(existing)
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		b.WriteString("\n")
+	}
+	if !present[gitignoreHeader] {
+		if len(existing) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(gitignoreHeader + "\n")
+	}
+	for _, entry := range missing {
+		b.WriteString(entry + "\n")
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return nil, fmt.Errorf("write %s: %w", path, err)
+	}
+	if verbose {
+		fmt.Fprintf(i.out, "  ✓ Ignored %s in %s\n", strings.Join(missing, ", "), path)
+	}
+	return missing, nil
+}
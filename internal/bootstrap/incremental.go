@@ -0,0 +1,158 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+	"github.com/josephgoksu/TaskWing/internal/utils"
+)
+
+// lastBootstrapRefComponent is the bootstrap_state key holding the git HEAD
+// of the last successful single-repo bootstrap. Multi-repo workspaces use
+// "bootstrap-sha-<service>" per service.
+const lastBootstrapRefComponent = "bootstrap-sha"
+
+// SinceLastBootstrap is the --since value that diffs against the git ref
+// recorded by the last successful bootstrap.
+const SinceLastBootstrap = "last"
+
+// ErrNoBootstrapRef is returned when an incremental bootstrap has no recorded
+// ref to diff against.
+var ErrNoBootstrapRef = errors.New("no previous bootstrap recorded; run a full 'taskwing bootstrap' first or pass --since=<git-ref>")
+
+// IncrementalScope is the set of files an incremental bootstrap analyzes.
+type IncrementalScope struct {
+	BaseRef      string   `json:"base_ref"`      // Git ref the changes are computed from
+	ChangedFiles []string `json:"changed_files"` // Files changed between BaseRef and HEAD
+}
+
+// ResolveIncrementalScope returns the files changed since the given git ref.
+// With SinceLastBootstrap (or an empty ref), it diffs against the HEAD
+// recorded by the last successful bootstrap.
+func (s *Service) ResolveIncrementalScope(since string) (*IncrementalScope, error) {
+	if since == "" || since == SinceLastBootstrap {
+		ref, err := s.lastBootstrapRef()
+		if err != nil {
+			return nil, err
+		}
+		since = ref
+	}
+
+	cmd := exec.Command("git", "diff", "--name-only", since+"..HEAD")
+	cmd.Dir = s.basePath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff since %s: %w", since, err)
+	}
+
+	scope := &IncrementalScope{BaseRef: since}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			scope.ChangedFiles = append(scope.ChangedFiles, line)
+		}
+	}
+	return scope, nil
+}
+
+// lastBootstrapRef returns the git HEAD recorded by the last successful bootstrap.
+func (s *Service) lastBootstrapRef() (string, error) {
+	store, err := memory.NewSQLiteStore(s.storePath)
+	if err != nil {
+		return "", fmt.Errorf("open memory store: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	state, err := store.GetBootstrapState(lastBootstrapRefComponent)
+	if err != nil {
+		return "", err
+	}
+	if state == nil || state.Checksum == "" {
+		return "", ErrNoBootstrapRef
+	}
+	return state.Checksum, nil
+}
+
+// recordBootstrapRef stores the current git HEAD as the base for the next
+// incremental bootstrap. No-op outside a git repository.
+func (s *Service) recordBootstrapRef(store *memory.SQLiteStore) {
+	if headSHA := getGitHEAD(s.basePath); headSHA != "" {
+		_ = store.SetBootstrapState(&memory.BootstrapState{
+			Component: lastBootstrapRefComponent,
+			Status:    memory.BootstrapStatusCompleted,
+			Checksum:  headSHA,
+		})
+	}
+}
+
+// RunIncremental analyzes only the files changed in scope and merges the
+// findings into existing knowledge. Returns the agent outputs; nil outputs
+// with a nil error mean nothing relevant changed and analysis was skipped.
+func (s *Service) RunIncremental(ctx context.Context, runner *Runner, scope *IncrementalScope, isQuiet bool) ([]core.Output, error) {
+	if len(agentsForChangedFiles(runner.agents, scope.ChangedFiles)) == 0 {
+		// Nothing analyzable changed; advance the ref so the next diff starts here
+		store, err := memory.NewSQLiteStore(s.storePath)
+		if err != nil {
+			return nil, fmt.Errorf("open memory store: %w", err)
+		}
+		defer func() { _ = store.Close() }()
+		s.recordBootstrapRef(store)
+		return nil, nil
+	}
+
+	opts := s.runOptions("root")
+	opts.ChangedFiles = scope.ChangedFiles
+	results, err := runner.RunWithOptions(ctx, s.basePath, opts)
+	if err != nil && len(results) == 0 {
+		return nil, err
+	}
+	return results, s.saveAnalysis(ctx, results, nil, scope.ChangedFiles, false, isQuiet)
+}
+
+// agentsForChangedFiles returns the agents whose inputs include at least one
+// changed file: doc for markdown and docs/, deps for manifests, code for
+// source files. The git agent analyzes history as a whole and is skipped.
+func agentsForChangedFiles(agents []core.Agent, changed []string) []core.Agent {
+	needed := make(map[string]bool)
+	for _, path := range changed {
+		if name := changedFileAgent(path); name != "" {
+			needed[name] = true
+		}
+	}
+
+	var selected []core.Agent
+	for _, a := range agents {
+		if needed[a.Name()] {
+			selected = append(selected, a)
+		}
+	}
+	return selected
+}
+
+// changedFileAgent returns the name of the agent that analyzes path, or ""
+// when no agent covers it.
+func changedFileAgent(path string) string {
+	name := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if utils.IgnoredDirs[dir] {
+			return ""
+		}
+	}
+
+	switch {
+	case utils.IsDependencyFile(name):
+		return "deps"
+	case utils.IsDocFile(path):
+		return "doc"
+	case utils.IsCodeFile(ext):
+		return "code"
+	default:
+		return ""
+	}
+}
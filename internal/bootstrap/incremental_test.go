@@ -0,0 +1,197 @@
+package bootstrap
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/project"
+)
+
+// recordingAgent returns one finding per run and records the inputs it saw.
+type recordingAgent struct {
+	name   string
+	inputs []core.Input
+}
+
+func (a *recordingAgent) Name() string        { return a.name }
+func (a *recordingAgent) Description() string { return a.name }
+func (a *recordingAgent) Run(_ context.Context, input core.Input) (core.Output, error) {
+	a.inputs = append(a.inputs, input)
+	return core.Output{AgentName: a.name, Findings: []core.Finding{{Title: a.name + " finding"}}}, nil
+}
+
+func TestService_RunIncremental(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("README.md", "# Project\n")
+	write("docs/guide.md", "# Guide\n")
+	write("main.go", "package main\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	svc := NewService(dir, t.TempDir(), llm.Config{})
+	var persistedPaths []string
+	svc.persist = func(_ context.Context, _ []core.Output, filePaths []string, _, _ bool) error {
+		persistedPaths = filePaths
+		return nil
+	}
+
+	if _, err := svc.ResolveIncrementalScope(SinceLastBootstrap); err == nil {
+		t.Fatal("expected error before any bootstrap was recorded")
+	}
+
+	// A full bootstrap records HEAD as the incremental base
+	if err := svc.SaveAnalysis(ctx, []core.Output{{AgentName: "doc"}}, nil, false, true); err != nil {
+		t.Fatalf("SaveAnalysis: %v", err)
+	}
+
+	doc, code, gitAgent := &recordingAgent{name: "doc"}, &recordingAgent{name: "code"}, &recordingAgent{name: "git"}
+	runner := &Runner{agents: []core.Agent{doc, code, gitAgent}}
+
+	// No changes since last run: analysis is skipped
+	scope, err := svc.ResolveIncrementalScope(SinceLastBootstrap)
+	if err != nil {
+		t.Fatalf("ResolveIncrementalScope: %v", err)
+	}
+	results, err := svc.RunIncremental(ctx, runner, scope, true)
+	if err != nil {
+		t.Fatalf("RunIncremental: %v", err)
+	}
+	if results != nil || len(doc.inputs)+len(code.inputs)+len(gitAgent.inputs) != 0 {
+		t.Fatalf("expected analysis to be skipped, got %d results", len(results))
+	}
+
+	// A changed doc: only the doc agent runs, on just that file
+	write("docs/guide.md", "# Guide\n\nUse the repository pattern.\n")
+	git("commit", "-q", "-am", "update guide")
+
+	scope, err = svc.ResolveIncrementalScope(SinceLastBootstrap)
+	if err != nil {
+		t.Fatalf("ResolveIncrementalScope: %v", err)
+	}
+	results, err = svc.RunIncremental(ctx, runner, scope, true)
+	if err != nil {
+		t.Fatalf("RunIncremental: %v", err)
+	}
+	if len(results) != 1 || results[0].AgentName != "doc" {
+		t.Fatalf("expected only the doc agent to run, got %v", results)
+	}
+	if len(code.inputs) != 0 || len(gitAgent.inputs) != 0 {
+		t.Error("code and git agents should not run for a doc-only change")
+	}
+	if in := doc.inputs[0]; in.Mode != core.ModeWatch || !slices.Equal(in.ChangedFiles, []string{"docs/guide.md"}) {
+		t.Errorf("doc agent input mode=%s files=%v, want watch mode on docs/guide.md", in.Mode, in.ChangedFiles)
+	}
+	if !slices.Equal(persistedPaths, []string{"docs/guide.md"}) {
+		t.Errorf("ingest should be scoped to the changed doc, got %v", persistedPaths)
+	}
+
+	// The successful incremental run advances the base ref
+	scope, err = svc.ResolveIncrementalScope(SinceLastBootstrap)
+	if err != nil {
+		t.Fatalf("ResolveIncrementalScope: %v", err)
+	}
+	if len(scope.ChangedFiles) != 0 {
+		t.Errorf("expected no changes after incremental run, got %v", scope.ChangedFiles)
+	}
+}
+
+func TestService_RunMultiRepoAnalysisIncremental(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Cleanup(config.ClearProjectContext)
+	ctx := context.Background()
+	root := t.TempDir()
+	serviceDir := filepath.Join(root, "api")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = serviceDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(serviceDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("README.md", "# API\n")
+	write("docs/diagram.txt", "client -> api\n")
+	write("main.go", "package main\n")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	svc := NewService(root, t.TempDir(), llm.Config{})
+	doc, code := &recordingAgent{name: "doc"}, &recordingAgent{name: "code"}
+	svc.newRunner = func(string) *Runner { return &Runner{agents: []core.Agent{doc, code}} }
+	ws := &project.WorkspaceInfo{Type: project.WorkspaceTypeMultiRepo, RootPath: root, Services: []string{"api"}, Name: "ws"}
+
+	// First run: no recorded state, full analysis
+	analyses, errs, err := svc.RunMultiRepoAnalysis(ctx, ws, nil)
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("RunMultiRepoAnalysis: %v %v", err, errs)
+	}
+	if len(analyses) != 1 || analyses[0].ChangedFiles != nil {
+		t.Fatalf("first run should be a full analysis, got %+v", analyses)
+	}
+	if doc.inputs[0].Mode != core.ModeBootstrap || len(code.inputs) != 1 {
+		t.Fatalf("first run should run every agent in bootstrap mode")
+	}
+
+	// A small change under docs/ (not markdown): only the doc agent, on that file
+	write("docs/diagram.txt", "client -> gateway -> api\n")
+	git("commit", "-q", "-am", "update diagram")
+
+	analyses, errs, err = svc.RunMultiRepoAnalysis(ctx, ws, nil)
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("RunMultiRepoAnalysis: %v %v", err, errs)
+	}
+	if len(doc.inputs) != 2 || len(code.inputs) != 1 {
+		t.Fatalf("expected only the doc agent to re-run, doc=%d code=%d", len(doc.inputs), len(code.inputs))
+	}
+	if in := doc.inputs[1]; in.Mode != core.ModeWatch || !slices.Equal(in.ChangedFiles, []string{"docs/diagram.txt"}) {
+		t.Errorf("doc agent input mode=%s files=%v, want watch mode on docs/diagram.txt", in.Mode, in.ChangedFiles)
+	}
+	if len(analyses) != 1 || !slices.Equal(analyses[0].ChangedFiles, []string{"api/docs/diagram.txt"}) {
+		t.Errorf("ingest scope = %+v, want the workspace-relative changed file", analyses)
+	}
+}
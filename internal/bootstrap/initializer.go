@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,14 +14,16 @@ import (
 	"time"
 
 	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/memory"
 	"github.com/josephgoksu/TaskWing/internal/utils"
 	"github.com/josephgoksu/TaskWing/skills"
 )
 
 // Initializer handles the setup of TaskWing project structure and integrations.
 type Initializer struct {
-	basePath  string // project root (for file scanning, AI config generation)
-	storePath string // global store path (~/.taskwing/projects/<slug>/) for memory and metadata
+	basePath  string    // project root (for file scanning, AI config generation)
+	storePath string    // global store path (~/.taskwing/projects/<slug>/) for memory and metadata
+	out       io.Writer // progress messages (os.Stdout by default)
 	// Version is the CLI version to stamp in the store's version file.
 	// If empty, no version file is written.
 	Version string
@@ -31,7 +34,13 @@ func NewInitializer(basePath string, storePath ...string) *Initializer {
 	if len(storePath) > 0 {
 		sp = storePath[0]
 	}
-	return &Initializer{basePath: basePath, storePath: sp}
+	return &Initializer{basePath: basePath, storePath: sp, out: os.Stdout}
+}
+
+// SetOutput redirects progress messages, e.g. to stderr when stdout is
+// reserved for machine-readable output.
+func (i *Initializer) SetOutput(w io.Writer) {
+	i.out = w
 }
 
 // ValidAINames returns the list of supported AI assistant names.
@@ -59,11 +68,16 @@ func (i *Initializer) Run(verbose bool, selectedAIs []string) error {
 		return err
 	}
 
+	// 2. Keep local state under an existing .taskwing/ out of git
+	if _, err := i.ensureGitignore(verbose); err != nil {
+		return err
+	}
+
 	if len(selectedAIs) == 0 {
 		return nil
 	}
 
-	// 2. Setup AI integrations
+	// 3. Setup AI integrations
 	return i.setupAIIntegrations(verbose, selectedAIs, true)
 }
 
@@ -76,6 +90,140 @@ func (i *Initializer) RegenerateConfigs(verbose bool, targetAIs []string) error
 	return i.setupAIIntegrations(verbose, targetAIs, false)
 }
 
+// InstalledConfigVersion returns the AIToolConfigVersion recorded in an AI's
+// TaskWing-managed config: the .taskwing-managed marker for directory-based
+// configs, or the version header of a single-file config.
+// managed is false when the config is missing or owned by the user.
+func (i *Initializer) InstalledConfigVersion(aiName string) (version string, managed bool) {
+	cfg, ok := aiHelpers[aiName]
+	if !ok {
+		return "", false
+	}
+	return i.managedConfigVersion(cfg)
+}
+
+func (i *Initializer) managedConfigVersion(cfg aiHelperConfig) (version string, managed bool) {
+	if cfg.singleFile {
+		content, err := os.ReadFile(filepath.Join(i.basePath, cfg.commandsDir, cfg.singleFileName))
+		if err != nil || !strings.Contains(string(content), "<!-- TASKWING_MANAGED -->") {
+			return "", false
+		}
+		return parseEmbeddedVersion(string(content)), true
+	}
+	markerPath := filepath.Join(i.basePath, cfg.commandsDir, TaskWingManagedFile)
+	if _, err := os.Stat(markerPath); err != nil {
+		return "", false
+	}
+	return parseManagedMarkerVersion(markerPath), true
+}
+
+// OutdatedAIConfigs returns the AIs whose managed config was generated for a
+// different AIToolConfigVersion (or predates version stamping).
+func (i *Initializer) OutdatedAIConfigs() []string {
+	var outdated []string
+	for _, ai := range aiCatalog {
+		version, managed := i.InstalledConfigVersion(ai.name)
+		if managed && version != AIToolConfigVersion(ai.name) {
+			outdated = append(outdated, ai.name)
+		}
+	}
+	return outdated
+}
+
+// RefreshOutdatedConfigs regenerates managed AI configs whose recorded version
+// no longer matches AIToolConfigVersion. User-owned configs are never touched.
+// Returns the AIs that were regenerated.
+func (i *Initializer) RefreshOutdatedConfigs(verbose bool) ([]string, error) {
+	outdated := i.OutdatedAIConfigs()
+	if len(outdated) == 0 {
+		return nil, nil
+	}
+	if err := i.setupAIIntegrations(verbose, outdated, false); err != nil {
+		return nil, err
+	}
+	return outdated, nil
+}
+
+// RemovedAIConfigs returns the AIs in removedAICatalog that still have a
+// TaskWing-managed config in the project.
+func (i *Initializer) RemovedAIConfigs() []string {
+	var stale []string
+	for _, ai := range removedAICatalog {
+		if _, managed := i.managedConfigVersion(ai); managed {
+			stale = append(stale, ai.name)
+		}
+	}
+	return stale
+}
+
+// PruneRemovedAIConfigs deletes the managed configs left behind for AIs that
+// TaskWing no longer supports. Only TaskWing-owned content is removed: the
+// marker, the taskwing/ namespace and managed command files of a marked
+// directory, or a single file carrying the managed header. User files are kept.
+// Returns the AIs whose configs were pruned.
+func (i *Initializer) PruneRemovedAIConfigs(verbose bool) ([]string, error) {
+	var pruned []string
+	for _, cfg := range removedAICatalog {
+		if _, managed := i.managedConfigVersion(cfg); !managed {
+			continue
+		}
+		commandsDir := filepath.Join(i.basePath, cfg.commandsDir)
+		if cfg.singleFile {
+			if err := os.Remove(filepath.Join(commandsDir, cfg.singleFileName)); err != nil && !os.IsNotExist(err) {
+				return pruned, fmt.Errorf("remove %s config: %w", cfg.name, err)
+			}
+		} else if err := pruneManagedCommandsDir(commandsDir, cfg.fileExt); err != nil {
+			return pruned, fmt.Errorf("remove %s config: %w", cfg.name, err)
+		}
+		removeEmptyDirs(i.basePath, commandsDir)
+		if verbose {
+			fmt.Fprintf(i.out, "  ✓ Removed config for unsupported AI %s\n", cfg.name)
+		}
+		pruned = append(pruned, cfg.name)
+	}
+	return pruned, nil
+}
+
+// pruneManagedCommandsDir removes everything TaskWing writes to a managed
+// commands directory, leaving any other files in place.
+func pruneManagedCommandsDir(commandsDir, ext string) error {
+	if err := os.RemoveAll(filepath.Join(commandsDir, slashCommandNamespace)); err != nil {
+		return err
+	}
+	managedBases := managedSlashCommandBases()
+	entries, err := os.ReadDir(commandsDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) != ext {
+			continue
+		}
+		if _, known := managedBases[strings.TrimSuffix(name, ext)]; !known {
+			continue
+		}
+		if err := os.Remove(filepath.Join(commandsDir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Remove(filepath.Join(commandsDir, TaskWingManagedFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// removeEmptyDirs removes dir and its parents up to (not including) basePath
+// for as long as they are empty.
+func removeEmptyDirs(basePath, dir string) {
+	for dir != basePath && strings.HasPrefix(dir, basePath+string(filepath.Separator)) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
 // AdoptionResult contains backup metadata for an unmanaged adoption operation.
 type AdoptionResult struct {
 	AI           string   `json:"ai"`
@@ -161,7 +309,7 @@ func (i *Initializer) AdoptAIConfig(aiName string, verbose bool) (*AdoptionResul
 	}
 
 	if verbose {
-		fmt.Printf("  ✓ Adopted unmanaged config for %s (backup: %s)\n", aiName, backupDir)
+		fmt.Fprintf(i.out, "  ✓ Adopted unmanaged config for %s (backup: %s)\n", aiName, backupDir)
 	}
 
 	return &AdoptionResult{
@@ -187,13 +335,13 @@ func (i *Initializer) setupAIIntegrations(verbose bool, selectedAIs []string, sh
 
 	if len(validAIs) == 0 {
 		if verbose {
-			fmt.Println("⚠️  No valid AI assistants specified")
+			fmt.Fprintln(i.out, "⚠️  No valid AI assistants specified")
 		}
 		return nil
 	}
 
 	if showHeader {
-		fmt.Printf("🔧 Setting up AI integrations for: %s\n", strings.Join(validAIs, ", "))
+		fmt.Fprintf(i.out, "🔧 Setting up AI integrations for: %s\n", strings.Join(validAIs, ", "))
 	}
 
 	for _, ai := range validAIs {
@@ -208,7 +356,7 @@ func (i *Initializer) setupAIIntegrations(verbose bool, selectedAIs []string, sh
 		}
 
 		if showHeader {
-			fmt.Printf("   ✓ Created local config for %s\n", ai)
+			fmt.Fprintf(i.out, "   ✓ Created local config for %s\n", ai)
 		}
 	}
 
@@ -221,14 +369,22 @@ func (i *Initializer) setupAIIntegrations(verbose bool, selectedAIs []string, sh
 }
 
 func (i *Initializer) createStructure(verbose bool) error {
-	fmt.Println("📁 Creating project store...")
+	fmt.Fprintln(i.out, "📁 Creating project store...")
 	if err := os.MkdirAll(i.storePath, 0700); err != nil {
 		return fmt.Errorf("create store: %w", err)
 	}
 	if verbose {
-		fmt.Printf("  ✓ Created %s\n", i.storePath)
+		fmt.Fprintf(i.out, "  ✓ Created %s\n", i.storePath)
 	}
 
+	// Create the memory database up front so the store is usable even when
+	// no analysis runs (e.g. bootstrap --no-analyze)
+	store, err := memory.NewSQLiteStore(i.storePath)
+	if err != nil {
+		return fmt.Errorf("create memory database: %w", err)
+	}
+	_ = store.Close()
+
 	// Track CLI version for post-upgrade migration detection
 	if i.Version != "" {
 		versionPath := filepath.Join(i.storePath, "version")
@@ -259,6 +415,11 @@ var aiCatalog = []aiHelperConfig{
 	{name: "opencode", displayName: "OpenCode", commandsDir: ".opencode/commands", fileExt: ".md", singleFile: false, skillsDir: true},
 }
 
+// removedAICatalog lists AI integrations TaskWing used to support. Configs
+// generated for them by older versions are offered for removal on bootstrap.
+// Move an entry here from aiCatalog when dropping an AI.
+var removedAICatalog []aiHelperConfig
+
 // Map AI name to config for O(1) lookups.
 var aiHelpers = func() map[string]aiHelperConfig {
 	cfg := make(map[string]aiHelperConfig, len(aiCatalog))
@@ -438,7 +599,7 @@ func managedSlashCommandBases() map[string]struct{} {
 	return managed
 }
 
-func pruneStaleSlashCommands(commandsDir, ext string, verbose bool) error {
+func (i *Initializer) pruneStaleSlashCommands(commandsDir, ext string, verbose bool) error {
 	managedBases := managedSlashCommandBases()
 
 	// Prune legacy flat tw-* files from the commands directory root
@@ -467,7 +628,7 @@ func pruneStaleSlashCommands(commandsDir, ext string, verbose bool) error {
 				return fmt.Errorf("remove legacy slash command %s: %w", name, err)
 			}
 			if verbose {
-				fmt.Printf("  ✓ Removed legacy command %s\n", name)
+				fmt.Fprintf(i.out, "  ✓ Removed legacy command %s\n", name)
 			}
 		}
 	}
@@ -502,7 +663,7 @@ func pruneStaleSlashCommands(commandsDir, ext string, verbose bool) error {
 			return fmt.Errorf("remove stale slash command %s: %w", name, err)
 		}
 		if verbose {
-			fmt.Printf("  ✓ Removed stale command %s/%s\n", slashCommandNamespace, name)
+			fmt.Fprintf(i.out, "  ✓ Removed stale command %s/%s\n", slashCommandNamespace, name)
 		}
 	}
 
@@ -581,11 +742,11 @@ func (i *Initializer) CreateSlashCommands(aiName string, verbose bool) error {
 			return fmt.Errorf("create %s: %w", fileName, err)
 		}
 		if verbose {
-			fmt.Printf("  ✓ Created %s/%s/%s\n", cfg.commandsDir, slashCommandNamespace, fileName)
+			fmt.Fprintf(i.out, "  ✓ Created %s/%s/%s\n", cfg.commandsDir, slashCommandNamespace, fileName)
 		}
 	}
 
-	if err := pruneStaleSlashCommands(commandsDir, cfg.fileExt, verbose); err != nil {
+	if err := i.pruneStaleSlashCommands(commandsDir, cfg.fileExt, verbose); err != nil {
 		return err
 	}
 
@@ -634,11 +795,11 @@ func (i *Initializer) createClaudeSkills(verbose bool) error {
 			return fmt.Errorf("create %s: %w", fileName, err)
 		}
 		if verbose {
-			fmt.Printf("  ✓ Created %s/%s/%s\n", cfg.commandsDir, slashCommandNamespace, fileName)
+			fmt.Fprintf(i.out, "  ✓ Created %s/%s/%s\n", cfg.commandsDir, slashCommandNamespace, fileName)
 		}
 	}
 
-	if err := pruneStaleSlashCommands(commandsDir, cfg.fileExt, verbose); err != nil {
+	if err := i.pruneStaleSlashCommands(commandsDir, cfg.fileExt, verbose); err != nil {
 		return err
 	}
 
@@ -653,7 +814,7 @@ func (i *Initializer) createClaudeSkills(verbose bool) error {
 				}
 				_ = os.RemoveAll(p)
 				if verbose {
-					fmt.Printf("  ✓ Removed intermediate skill %s\n", e.Name())
+					fmt.Fprintf(i.out, "  ✓ Removed intermediate skill %s\n", e.Name())
 				}
 			}
 		}
@@ -709,7 +870,7 @@ func (i *Initializer) createSingleFileInstructions(aiName string, verbose bool)
 				return fmt.Errorf("backup legacy directory: %w", err)
 			}
 			if verbose {
-				fmt.Printf("  ✓ Backed up legacy %s/ directory\n", legacyDirName)
+				fmt.Fprintf(i.out, "  ✓ Backed up legacy %s/ directory\n", legacyDirName)
 			}
 		}
 	}
@@ -720,7 +881,7 @@ func (i *Initializer) createSingleFileInstructions(aiName string, verbose bool)
 		if !strings.Contains(string(existingContent), "<!-- TASKWING_MANAGED -->") {
 			// User owns this file - do not overwrite
 			if verbose {
-				fmt.Printf("  ⚠️  Skipping %s - file exists and is user-managed\n", cfg.singleFileName)
+				fmt.Fprintf(i.out, "  ⚠️  Skipping %s - file exists and is user-managed\n", cfg.singleFileName)
 			}
 			// Clean up backup since we're not proceeding
 			if legacyBackup != "" {
@@ -780,12 +941,12 @@ func (i *Initializer) createSingleFileInstructions(aiName string, verbose bool)
 	if legacyBackup != "" {
 		_ = os.RemoveAll(legacyBackup)
 		if verbose {
-			fmt.Printf("  ✓ Removed legacy %s/ directory\n", legacyDirName)
+			fmt.Fprintf(i.out, "  ✓ Removed legacy %s/ directory\n", legacyDirName)
 		}
 	}
 
 	if verbose {
-		fmt.Printf("  ✓ Created %s/%s\n", cfg.commandsDir, cfg.singleFileName)
+		fmt.Fprintf(i.out, "  ✓ Created %s/%s\n", cfg.commandsDir, cfg.singleFileName)
 	}
 
 	return nil
@@ -849,11 +1010,11 @@ func (i *Initializer) createOpenCodeCommands(aiName string, verbose bool) error
 		}
 
 		if verbose {
-			fmt.Printf("  ✓ Created %s/%s.md\n", cfg.commandsDir, cmd.SlashCmd)
+			fmt.Fprintf(i.out, "  ✓ Created %s/%s.md\n", cfg.commandsDir, cmd.SlashCmd)
 		}
 	}
 
-	if err := pruneStaleSlashCommands(commandsDir, ".md", verbose); err != nil {
+	if err := i.pruneStaleSlashCommands(commandsDir, ".md", verbose); err != nil {
 		return err
 	}
 
@@ -1057,7 +1218,7 @@ func (i *Initializer) InstallHooksConfig(aiName string, verbose bool) error {
 
 	if !changed {
 		if verbose {
-			fmt.Printf("  ℹ️  Hooks already configured in %s\n", settingsPath)
+			fmt.Fprintf(i.out, "  ℹ️  Hooks already configured in %s\n", settingsPath)
 		}
 		return nil
 	}
@@ -1072,8 +1233,8 @@ func (i *Initializer) InstallHooksConfig(aiName string, verbose bool) error {
 	}
 
 	if verbose {
-		fmt.Printf("  ✓ Created hooks config: %s\n", settingsPath)
-		fmt.Println("  ℹ️  If Claude Code is already running, review/reload hooks from /hooks for changes to take effect.")
+		fmt.Fprintf(i.out, "  ✓ Created hooks config: %s\n", settingsPath)
+		fmt.Fprintln(i.out, "  ℹ️  If Claude Code is already running, review/reload hooks from /hooks for changes to take effect.")
 	}
 	return nil
 }
@@ -1145,7 +1306,7 @@ func (i *Initializer) installOpenCodePlugin(verbose bool) error {
 		if !strings.Contains(string(existingContent), "TASKWING_MANAGED_PLUGIN") {
 			// User owns this file - do not overwrite
 			if verbose {
-				fmt.Printf("  ⚠️  Skipping taskwing-hooks.js - file exists and is user-managed\n")
+				fmt.Fprintf(i.out, "  ⚠️  Skipping taskwing-hooks.js - file exists and is user-managed\n")
 			}
 			return nil
 		}
@@ -1159,7 +1320,7 @@ func (i *Initializer) installOpenCodePlugin(verbose bool) error {
 	}
 
 	if verbose {
-		fmt.Printf("  ✓ Created OpenCode plugin: .opencode/plugins/taskwing-hooks.js\n")
+		fmt.Fprintf(i.out, "  ✓ Created OpenCode plugin: .opencode/plugins/taskwing-hooks.js\n")
 	}
 	return nil
 }
@@ -1322,10 +1483,10 @@ func (i *Initializer) updateAgentDocs(verbose bool) error {
 				return fmt.Errorf("update %s: %w", fileName, err)
 			}
 			if verbose {
-				fmt.Printf("  ✓ TaskWing docs %s in %s\n", action, fileName)
+				fmt.Fprintf(i.out, "  ✓ TaskWing docs %s in %s\n", action, fileName)
 			}
 		} else if verbose {
-			fmt.Printf("  ℹ️  TaskWing docs unchanged in %s\n", fileName)
+			fmt.Fprintf(i.out, "  ℹ️  TaskWing docs unchanged in %s\n", fileName)
 		}
 	}
 	return nil
@@ -0,0 +1,243 @@
+package bootstrap
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+)
+
+func TestInitializer_RefreshOutdatedConfigs(t *testing.T) {
+	dir := t.TempDir()
+	initializer := NewInitializer(dir)
+
+	// Cursor: managed marker from an older TaskWing version
+	cursorDir := filepath.Join(dir, ".cursor", "rules")
+	if err := os.MkdirAll(cursorDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cursorMarker := filepath.Join(cursorDir, TaskWingManagedFile)
+	if err := os.WriteFile(cursorMarker, []byte("# This directory is managed by TaskWing\n# AI: cursor\n# Version: old-version\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Gemini: managed marker already at the current version
+	geminiDir := filepath.Join(dir, ".gemini", "commands")
+	if err := os.MkdirAll(geminiDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	geminiMarker := filepath.Join(geminiDir, TaskWingManagedFile)
+	current := "# This directory is managed by TaskWing\n# AI: gemini\n# Version: " + AIToolConfigVersion("gemini") + "\n"
+	if err := os.WriteFile(geminiMarker, []byte(current), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Copilot: user-owned single file with no TaskWing header
+	githubDir := filepath.Join(dir, ".github")
+	if err := os.MkdirAll(githubDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	copilotFile := filepath.Join(githubDir, "copilot-instructions.md")
+	userContent := "# My own instructions\n"
+	if err := os.WriteFile(copilotFile, []byte(userContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err := initializer.RefreshOutdatedConfigs(false)
+	if err != nil {
+		t.Fatalf("RefreshOutdatedConfigs: %v", err)
+	}
+	if len(refreshed) != 1 || refreshed[0] != "cursor" {
+		t.Fatalf("refreshed = %v, want [cursor]", refreshed)
+	}
+
+	if version, managed := initializer.InstalledConfigVersion("cursor"); !managed || version != AIToolConfigVersion("cursor") {
+		t.Errorf("cursor marker version = %q (managed=%v), want current", version, managed)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(cursorDir, slashCommandNamespace)); len(entries) == 0 {
+		t.Error("cursor commands should have been regenerated")
+	}
+
+	if got, _ := os.ReadFile(geminiMarker); string(got) != current {
+		t.Error("up-to-date gemini marker should be left untouched")
+	}
+	if got, _ := os.ReadFile(copilotFile); string(got) != userContent {
+		t.Error("user-owned copilot instructions must not be modified")
+	}
+	if _, err := os.Stat(filepath.Join(geminiDir, slashCommandNamespace)); !os.IsNotExist(err) {
+		t.Error("gemini commands should not have been regenerated")
+	}
+
+	if outdated := initializer.OutdatedAIConfigs(); len(outdated) != 0 {
+		t.Errorf("expected no outdated configs after refresh, got %s", strings.Join(outdated, ", "))
+	}
+}
+
+func TestInitializer_PruneRemovedAIConfigs(t *testing.T) {
+	previous := removedAICatalog
+	removedAICatalog = []aiHelperConfig{
+		{name: "oldai", displayName: "Old AI", commandsDir: ".oldai/commands", fileExt: ".md"},
+		{name: "userai", displayName: "User AI", commandsDir: ".userai/commands", fileExt: ".md"},
+	}
+	t.Cleanup(func() { removedAICatalog = previous })
+
+	dir := t.TempDir()
+	write := func(rel, content string) string {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// Managed by TaskWing, with one user file mixed in
+	write(".oldai/commands/"+TaskWingManagedFile, "# This directory is managed by TaskWing\n# AI: oldai\n")
+	write(".oldai/commands/taskwing/plan.md", "plan")
+	write(".oldai/commands/tw-next.md", "next")
+	userNote := write(".oldai/commands/notes.md", "mine")
+
+	// Same layout but no marker: owned by the user
+	userPlan := write(".userai/commands/taskwing/plan.md", "plan")
+
+	initializer := NewInitializer(dir)
+	if stale := initializer.RemovedAIConfigs(); !slices.Equal(stale, []string{"oldai"}) {
+		t.Fatalf("RemovedAIConfigs = %v, want [oldai]", stale)
+	}
+	pruned, err := initializer.PruneRemovedAIConfigs(false)
+	if err != nil {
+		t.Fatalf("PruneRemovedAIConfigs: %v", err)
+	}
+	if !slices.Equal(pruned, []string{"oldai"}) {
+		t.Fatalf("pruned = %v, want [oldai]", pruned)
+	}
+
+	for _, rel := range []string{".oldai/commands/" + TaskWingManagedFile, ".oldai/commands/taskwing", ".oldai/commands/tw-next.md"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed", rel)
+		}
+	}
+	for _, path := range []string{userNote, userPlan} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("user file %s must be kept: %v", path, err)
+		}
+	}
+	if stale := initializer.RemovedAIConfigs(); len(stale) != 0 {
+		t.Errorf("expected no stale configs after pruning, got %v", stale)
+	}
+}
+
+func TestBootstrap_NoAnalyzeCreatesStore(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(t.TempDir(), "store")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Project\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range []string{"TASKWING_API_KEY", "OPENAI_API_KEY", "ANTHROPIC_API_KEY", "GOOGLE_API_KEY", "BEDROCK_API_KEY"} {
+		t.Setenv(env, "")
+	}
+
+	snap := &Snapshot{Project: ProjectHealth{Status: HealthMissing}}
+	plan := DecidePlan(snap, Flags{SkipAnalyze: true})
+	if plan.Mode == ModeError {
+		t.Fatalf("plan failed: %s", plan.ErrorMessage)
+	}
+	if plan.RequiresLLMConfig || slices.Contains(plan.Actions, ActionLLMAnalyze) {
+		t.Fatalf("--no-analyze plan should not need an LLM: %v", plan.Actions)
+	}
+	if !slices.Contains(plan.Actions, ActionInitProject) {
+		t.Fatalf("--no-analyze plan should still initialize the project: %v", plan.Actions)
+	}
+
+	svc := NewService(dir, storePath, llm.Config{})
+	if err := svc.InitializeProject(false, nil); err != nil {
+		t.Fatalf("InitializeProject: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(storePath, "memory.db")); err != nil {
+		t.Errorf("memory.db should exist after structure-only bootstrap: %v", err)
+	}
+}
+
+func TestService_SetOutputRedirectsProgress(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(t.TempDir(), "store")
+
+	var out bytes.Buffer
+	svc := NewService(dir, storePath, llm.Config{})
+	svc.SetOutput(&out)
+	if err := svc.InitializeProject(true, nil); err != nil {
+		t.Fatalf("InitializeProject: %v", err)
+	}
+	if !strings.Contains(out.String(), "Creating project store") || !strings.Contains(out.String(), storePath) {
+		t.Errorf("progress should go to the configured writer, got %q", out.String())
+	}
+}
+
+func TestInitializer_EnsureGitignore(t *testing.T) {
+	dir := t.TempDir()
+	initializer := NewInitializer(dir)
+
+	// Without .taskwing/ nothing is written: the directory is a project marker
+	added, err := initializer.ensureGitignore(false)
+	if err != nil {
+		t.Fatalf("ensureGitignore without .taskwing: %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("added %v without .taskwing/, want nothing", added)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".taskwing")); !os.IsNotExist(err) {
+		t.Errorf(".taskwing/ must not be created, stat err = %v", err)
+	}
+
+	// A user entry that matches a default must not be repeated
+	ignorePath := filepath.Join(dir, ".taskwing", ".gitignore")
+	if err := os.MkdirAll(filepath.Dir(ignorePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ignorePath, []byte("scratch/\nmemory/"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err = initializer.ensureGitignore(false)
+	if err != nil {
+		t.Fatalf("ensureGitignore: %v", err)
+	}
+	if len(added) != len(config.DefaultGitignoreEntries)-1 || slices.Contains(added, "memory/") {
+		t.Errorf("added = %v, want every default except memory/", added)
+	}
+
+	added, err = initializer.ensureGitignore(false)
+	if err != nil {
+		t.Fatalf("ensureGitignore rerun: %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("rerun added %v, want nothing", added)
+	}
+
+	content, err := os.ReadFile(ignorePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if !slices.Contains(lines, "scratch/") {
+		t.Error("user entries must be kept")
+	}
+	for _, entry := range append(config.DefaultGitignoreEntries, gitignoreHeader) {
+		count := 0
+		for _, line := range lines {
+			if line == entry {
+				count++
+			}
+		}
+		if count != 1 {
+			t.Errorf("%q appears %d times, want once", entry, count)
+		}
+	}
+}
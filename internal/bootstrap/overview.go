@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,8 +18,9 @@ import (
 
 // OverviewAnalyzer generates project overviews by analyzing README and manifest files.
 type OverviewAnalyzer struct {
-	cfg         llm.Config
-	projectPath string
+	cfg              llm.Config
+	projectPath      string
+	maxContextTokens int // Budget for gathered project content (0 = derive from the model)
 }
 
 // NewOverviewAnalyzer creates a new analyzer for generating project overviews.
@@ -28,6 +31,13 @@ func NewOverviewAnalyzer(cfg llm.Config, projectPath string) *OverviewAnalyzer {
 	}
 }
 
+// SetContextBudget caps the gathered project content at maxTokens. When the
+// content is larger, the lowest-priority sections are dropped first.
+// A non-positive value restores the model-derived default.
+func (a *OverviewAnalyzer) SetContextBudget(maxTokens int) {
+	a.maxContextTokens = maxTokens
+}
+
 // overviewResponse is the expected JSON response from the LLM.
 type overviewResponse struct {
 	ShortDescription string `json:"short_description"`
@@ -110,9 +120,52 @@ func (a *OverviewAnalyzer) Analyze(ctx context.Context) (*memory.ProjectOverview
 	return overview, nil
 }
 
-// gatherProjectContext reads key project files and returns their concatenated content.
+// overviewSection is one block of project context. Sections sharing a group
+// are rendered under a single group heading.
+type overviewSection struct {
+	group  string // Group heading written before the first kept section of the group
+	text   string
+	weight int // Higher weights are kept first when the context is over budget
+}
+
+// Section weights, highest first: root files, workspace structure, docs,
+// sub-project READMEs, then the directory listing fallback.
+const (
+	weightRootFile         = 100 // Minus the file's position in the priority list
+	weightWorkspace        = 60
+	weightDocFile          = 50
+	weightSubProjectReadme = 40
+	weightDirListing       = 30
+)
+
+// minTruncatedSectionTokens is the smallest remainder worth filling with a
+// truncated section rather than dropping it.
+const minTruncatedSectionTokens = 200
+
+// gatherProjectContext reads key project files and returns their concatenated
+// content, fitted to the analyzer's context budget.
 func (a *OverviewAnalyzer) gatherProjectContext() string {
-	var sb strings.Builder
+	return fitOverviewSections(a.collectProjectSections(), a.contextBudgetTokens())
+}
+
+// contextBudgetTokens returns the token budget for gathered project content:
+// the configured budget, or 40% of the model's input window.
+func (a *OverviewAnalyzer) contextBudgetTokens() int {
+	if a.maxContextTokens > 0 {
+		return a.maxContextTokens
+	}
+	return llm.GetMaxInputTokens(a.cfg.Model) * 40 / 100
+}
+
+// collectProjectSections reads key project files as weighted sections, in
+// the order they appear in the prompt.
+func (a *OverviewAnalyzer) collectProjectSections() []overviewSection {
+	var sections []overviewSection
+	size := 0
+	add := func(group, text string, weight int) {
+		sections = append(sections, overviewSection{group: group, text: text, weight: weight})
+		size += len(text)
+	}
 
 	// 1. Root Level Context
 	// Priority order: README, Architecture, other MDs, manifests
@@ -136,7 +189,7 @@ func (a *OverviewAnalyzer) gatherProjectContext() string {
 	foundRootContext := false
 
 	// Check specific priority files
-	for _, f := range files {
+	for i, f := range files {
 		fullPath := filepath.Join(a.projectPath, f.pattern)
 		if content, err := os.ReadFile(fullPath); err == nil {
 			foundRootContext = true
@@ -144,14 +197,13 @@ func (a *OverviewAnalyzer) gatherProjectContext() string {
 			if len(text) > 8000 {
 				text = text[:8000] + "\n... [truncated]"
 			}
-			sb.WriteString(fmt.Sprintf("## %s (%s)\n\n%s\n\n", f.heading, f.pattern, text))
+			add("", fmt.Sprintf("## %s (%s)\n\n%s\n\n", f.heading, f.pattern, text), weightRootFile-i)
 		}
 	}
 
 	// 2. Docs Folder (as before)
 	docsPath := filepath.Join(a.projectPath, "docs")
 	if entries, err := os.ReadDir(docsPath); err == nil {
-		var docsContent strings.Builder
 		for _, entry := range entries {
 			if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".md") {
 				continue
@@ -166,11 +218,7 @@ func (a *OverviewAnalyzer) gatherProjectContext() string {
 			if len(text) > 2000 {
 				text = text[:2000] + "\n... [truncated]"
 			}
-			docsContent.WriteString(fmt.Sprintf("### %s\n\n%s\n\n", entry.Name(), text))
-		}
-		if docsContent.Len() > 0 {
-			sb.WriteString("## Documentation (docs/)\n\n")
-			sb.WriteString(docsContent.String())
+			add("## Documentation (docs/)\n\n", fmt.Sprintf("### %s\n\n%s\n\n", entry.Name(), text), weightDocFile)
 		}
 	}
 
@@ -179,6 +227,7 @@ func (a *OverviewAnalyzer) gatherProjectContext() string {
 	subProjects := findSubProjects(a.projectPath)
 	if len(subProjects) > 0 {
 		foundRootContext = true
+		var sb strings.Builder
 		sb.WriteString("## Workspace Structure (Sub-projects)\n\n")
 		sb.WriteString("The project appears to be a monorepo containing the following services:\n\n")
 
@@ -192,14 +241,11 @@ func (a *OverviewAnalyzer) gatherProjectContext() string {
 			sb.WriteString("\n")
 		}
 		sb.WriteString("\n")
+		add("", sb.String(), weightWorkspace)
 
 		// If we have very little root context, grab READMEs from top 3 sub-projects
-		if sb.Len() < 1000 && len(subProjects) > 0 {
-			sb.WriteString("## Sub-project Context\n\n")
-			limit := 3
-			if len(subProjects) < limit {
-				limit = len(subProjects)
-			}
+		if size < 1000 {
+			limit := min(3, len(subProjects))
 			for i := 0; i < limit; i++ {
 				p := subProjects[i]
 				readmePath := filepath.Join(a.projectPath, p.Name, "README.md")
@@ -208,7 +254,7 @@ func (a *OverviewAnalyzer) gatherProjectContext() string {
 					if len(text) > 3000 {
 						text = text[:3000] + "\n... [truncated]"
 					}
-					sb.WriteString(fmt.Sprintf("### %s/README.md\n\n%s\n\n", p.Name, text))
+					add("## Sub-project Context\n\n", fmt.Sprintf("### %s/README.md\n\n%s\n\n", p.Name, text), weightSubProjectReadme)
 				}
 			}
 		}
@@ -218,6 +264,7 @@ func (a *OverviewAnalyzer) gatherProjectContext() string {
 	if !foundRootContext {
 		entries, err := os.ReadDir(a.projectPath)
 		if err == nil {
+			var sb strings.Builder
 			sb.WriteString("## Directory Listing\n\n")
 			for _, e := range entries {
 				name := e.Name()
@@ -230,12 +277,77 @@ func (a *OverviewAnalyzer) gatherProjectContext() string {
 					sb.WriteString(fmt.Sprintf("- %s\n", name))
 				}
 			}
+			add("", sb.String(), weightDirListing)
 		}
 	}
 
+	return sections
+}
+
+// fitOverviewSections renders sections within budgetTokens (<= 0 means no
+// limit). Sections are admitted by descending weight, ties in prompt order;
+// one that does not fit is truncated if enough budget remains, otherwise
+// dropped. Kept sections are rendered in their original order.
+func fitOverviewSections(sections []overviewSection, budgetTokens int) string {
+	kept := make([]string, len(sections))
+	if budgetTokens <= 0 {
+		for i, sec := range sections {
+			kept[i] = sec.text
+		}
+	} else {
+		order := make([]int, len(sections))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(x, y int) bool { return sections[order[x]].weight > sections[order[y]].weight })
+
+		remaining := budgetTokens
+		var dropped []string
+		for _, i := range order {
+			sec := sections[i]
+			// Reserve room for the group heading when this would be its first section
+			cost := llm.EstimateTokens(sec.text) + llm.EstimateTokens(sec.group)
+			switch {
+			case cost <= remaining:
+				kept[i] = sec.text
+				remaining -= cost
+			case remaining >= minTruncatedSectionTokens:
+				keep := (remaining-llm.EstimateTokens(sec.group))*4 - len(truncatedMarker)
+				kept[i] = sec.text[:min(keep, len(sec.text))] + truncatedMarker
+				remaining = 0
+			default:
+				dropped = append(dropped, firstLine(sec.text))
+			}
+		}
+		if len(dropped) > 0 {
+			slog.Info("overview context over budget, dropped low-priority sections",
+				"budget_tokens", budgetTokens, "dropped", dropped)
+		}
+	}
+
+	var sb strings.Builder
+	group := ""
+	for i, text := range kept {
+		if text == "" {
+			continue
+		}
+		if g := sections[i].group; g != "" && g != group {
+			sb.WriteString(g)
+		}
+		group = sections[i].group
+		sb.WriteString(text)
+	}
 	return sb.String()
 }
 
+const truncatedMarker = "\n... [truncated]\n\n"
+
+// firstLine returns the first line of text, used to name dropped sections.
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
 type subProjectInfo struct {
 	Name        string
 	Type        string
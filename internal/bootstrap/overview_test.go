@@ -0,0 +1,51 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/llm"
+)
+
+func TestOverviewAnalyzer_ContextFitsBudget(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("README.md", "# Shop\n\nAn online shop.\n"+strings.Repeat("readme ", 1000))
+	write("go.mod", "module example.com/shop\n")
+	for i := range 40 {
+		write(fmt.Sprintf("docs/guide-%02d.md", i), strings.Repeat("doc ", 600))
+	}
+
+	a := NewOverviewAnalyzer(llm.Config{}, dir)
+	const budget = 4000
+	a.SetContextBudget(budget)
+	got := a.gatherProjectContext()
+
+	if tokens := llm.EstimateTokens(got); tokens > budget {
+		t.Errorf("context is %d tokens, want at most %d", tokens, budget)
+	}
+	if !strings.Contains(got, "## README (README.md)") || !strings.Contains(got, "## Root Go Module (go.mod)") {
+		t.Error("highest-weight root files were dropped")
+	}
+	if !strings.Contains(got, "## Documentation (docs/)") || !strings.Contains(got, "### guide-00.md") {
+		t.Error("expected the first docs to be kept")
+	}
+	if strings.Contains(got, "### guide-39.md") {
+		t.Error("expected the last docs to be dropped")
+	}
+	// Root files still precede docs in the rendered prompt
+	if strings.Index(got, "## Root Go Module") > strings.Index(got, "## Documentation") {
+		t.Error("kept sections should keep their original order")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
 	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/project"
 )
@@ -103,6 +104,9 @@ type Snapshot struct {
 
 	// Workspace
 	Workspace *project.WorkspaceInfo `json:"workspace,omitempty"`
+
+	// Checkpoints left by a previous bootstrap that did not finish
+	Resume *ResumeState `json:"resume,omitempty"`
 }
 
 // Flags captures all CLI flags in a structured way.
@@ -110,10 +114,14 @@ type Flags struct {
 	Preview     bool     `json:"preview"`      // Dry-run, no writes
 	SkipInit    bool     `json:"skip_init"`    // Skip initialization phase
 	SkipIndex   bool     `json:"skip_index"`   // Skip code indexing
-	SkipAnalyze bool     `json:"skip_analyze"` // Skip LLM analysis (for CI/testing)
+	SkipAnalyze bool     `json:"skip_analyze"` // Skip LLM analysis: structure, AI configs, and metadata only (--no-analyze)
 	Force       bool     `json:"force"`        // Force index even on large codebases (--force flag)
 	Resume      bool     `json:"resume"`       // Resume from last checkpoint (skip completed agents)
+	Since       string   `json:"since"`        // Incremental: analyze only files changed since this git ref ("last" = last bootstrap)
 	OnlyAgents  []string `json:"only_agents"`  // Run only specified agents
+	GitSince    string   `json:"git_since"`    // Git history window: commit count or duration (empty = default)
+	IncludeDirs []string `json:"include_dirs"` // Limit doc gathering to these directories (relative to the project)
+	ExcludeDirs []string `json:"exclude_dirs"` // Directories skipped during doc gathering
 	Trace       bool     `json:"trace"`        // Enable tracing
 	TraceStdout bool     `json:"trace_stdout"` // Trace to stdout instead of file
 	TraceFile   string   `json:"trace_file,omitempty"`
@@ -143,6 +151,10 @@ type Plan struct {
 	UnmanagedDriftAIs []string `json:"unmanaged_drift_ais,omitempty"`
 	GlobalMCPDriftAIs []string `json:"global_mcp_drift_ais,omitempty"`
 
+	// ResumeAnalysis re-runs only the agents and persist step a previous
+	// bootstrap left unfinished, skipping completed agents.
+	ResumeAnalysis bool `json:"resume_analysis"`
+
 	// Execution state (populated during execution, not planning)
 	SelectedAIs []string `json:"selected_ais,omitempty"` // User's actual AI selection
 
@@ -177,12 +189,43 @@ func ValidateFlags(f Flags) error {
 		}
 	}
 
+	// --since analyzes a diff; --resume re-runs failed agents over the whole project
+	if f.Since != "" && f.Resume {
+		return FlagError{
+			Flags:   []string{"--since", "--resume"},
+			Message: "cannot run an incremental bootstrap and resume a full one at the same time",
+		}
+	}
+
+	if _, err := core.ParseGitHistoryWindow(f.GitSince); err != nil {
+		return fmt.Errorf("--git-since: %w", err)
+	}
+
+	// Scope directories are matched against project-relative paths
+	if err := validateScopeDirs("--include-dir", f.IncludeDirs); err != nil {
+		return err
+	}
+	if err := validateScopeDirs("--exclude-dir", f.ExcludeDirs); err != nil {
+		return err
+	}
+
 	// --trace-stdout without --trace is ignored but not an error
 	// (we could warn in Plan.Warnings instead)
 
 	return nil
 }
 
+// validateScopeDirs rejects directories outside the project.
+func validateScopeDirs(flag string, dirs []string) error {
+	for _, d := range dirs {
+		clean := filepath.ToSlash(filepath.Clean(d))
+		if filepath.IsAbs(d) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("%s %q: must be a directory inside the project", flag, d)
+		}
+	}
+	return nil
+}
+
 // ProbeEnvironment collects a complete snapshot of the environment.
 // This function has NO side effects - it only reads state.
 // Returns an error only if basePath is invalid (doesn't exist or not a directory).
@@ -235,6 +278,15 @@ func ProbeEnvironment(basePath string) (*Snapshot, error) {
 		snap.Workspace = ws
 	}
 
+	// Detect a previous bootstrap that stopped before finishing
+	if snap.Project.DBAccessible {
+		if storePath, err := config.GetProjectStorePath(basePath); err == nil {
+			if state, err := DetectInterruptedBootstrap(storePath); err == nil && state.Interrupted() {
+				snap.Resume = state
+			}
+		}
+	}
+
 	return snap, nil
 }
 
@@ -351,6 +403,13 @@ func DecidePlan(snap *Snapshot, flags Flags) *Plan {
 		plan.RequiresRepoSelection = true
 	}
 
+	// Resume an interrupted bootstrap instead of starting over
+	if shouldResumeAnalysis(snap, flags) {
+		plan.ResumeAnalysis = true
+		plan.Reasons = append(plan.Reasons, snap.Resume.Reason())
+		plan.Warnings = append(plan.Warnings, snap.Resume.Reason())
+	}
+
 	// Now determine actions based on mode and flags
 	plan.Actions = decideActions(snap, flags, plan.Mode)
 
@@ -367,7 +426,7 @@ func DecidePlan(snap *Snapshot, flags Flags) *Plan {
 		}
 	}
 
-	// LLM analysis runs by default unless --skip-analyze is set
+	// LLM analysis runs by default unless --no-analyze is set
 	if !flags.SkipAnalyze {
 		plan.RequiresLLMConfig = true
 		if !slices.Contains(plan.Actions, ActionLLMAnalyze) {
@@ -433,15 +492,18 @@ func decideActions(snap *Snapshot, flags Flags, mode BootstrapMode) []Action {
 		}
 	}
 
+	// Indexing and extraction finished before the interrupted analysis started
+	resuming := shouldResumeAnalysis(snap, flags)
+
 	// Indexing (if not skipped and not blocked by size)
-	if !flags.SkipIndex {
+	if !flags.SkipIndex && !resuming {
 		if !snap.IsLargeProject || flags.Force {
 			actions = append(actions, ActionIndexCode)
 		}
 	}
 
 	// Deterministic extraction always runs unless preview
-	if !flags.Preview {
+	if !flags.Preview && !resuming {
 		actions = append(actions, ActionExtractMetadata)
 	}
 
@@ -453,6 +515,12 @@ func decideActions(snap *Snapshot, flags Flags, mode BootstrapMode) []Action {
 	return actions
 }
 
+// shouldResumeAnalysis reports whether a healthy project has an interrupted
+// bootstrap whose analysis should be resumed.
+func shouldResumeAnalysis(snap *Snapshot, flags Flags) bool {
+	return snap.Project.Status == HealthOK && snap.Resume.Interrupted() && !flags.SkipAnalyze
+}
+
 // hasAIsNeedingRepair checks if any existing AI integration needs repair.
 // An AI needs repair ONLY if TaskWing created the directory (marker file exists)
 // and the configuration is incomplete.
@@ -548,7 +616,14 @@ func generateSkippedActions(snap *Snapshot, flags Flags) []string {
 	}
 
 	if flags.SkipAnalyze {
-		skipped = append(skipped, "llm_analyze (reason: --skip-analyze flag)")
+		skipped = append(skipped, "llm_analyze (reason: --no-analyze flag)")
+	}
+
+	if shouldResumeAnalysis(snap, flags) {
+		if !flags.SkipIndex {
+			skipped = append(skipped, "index_code (reason: resuming interrupted bootstrap)")
+		}
+		skipped = append(skipped, "extract_metadata (reason: resuming interrupted bootstrap)")
 	}
 
 	if flags.Preview {
@@ -0,0 +1,147 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+// PersistComponent is the bootstrap_state key for saving analysis results to memory.
+const PersistComponent = "persist"
+
+// ResumeState describes a previous bootstrap that stopped before its
+// analysis was fully saved, as recorded in the bootstrap_state checkpoints.
+type ResumeState struct {
+	FailedAgents   []string `json:"failed_agents,omitempty"`   // Agents that failed or never completed
+	PersistPending bool     `json:"persist_pending,omitempty"` // Results were not saved to memory
+}
+
+// Interrupted reports whether there is analysis or persist work left to resume.
+func (r *ResumeState) Interrupted() bool {
+	return r != nil && (len(r.FailedAgents) > 0 || r.PersistPending)
+}
+
+// Reason returns a human-readable description of what will be resumed.
+func (r *ResumeState) Reason() string {
+	var parts []string
+	if len(r.FailedAgents) > 0 {
+		parts = append(parts, "failed agents: "+strings.Join(r.FailedAgents, ", "))
+	}
+	if r.PersistPending {
+		parts = append(parts, "results not saved")
+	}
+	return fmt.Sprintf("Previous bootstrap was interrupted (%s) - resuming", strings.Join(parts, "; "))
+}
+
+// DetectInterruptedBootstrap reads the checkpoints in a project store and
+// returns what a previous bootstrap left unfinished.
+func DetectInterruptedBootstrap(storePath string) (*ResumeState, error) {
+	store, err := memory.NewSQLiteStore(storePath)
+	if err != nil {
+		return nil, fmt.Errorf("open memory store: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+	return detectInterrupted(store)
+}
+
+func detectInterrupted(store *memory.SQLiteStore) (*ResumeState, error) {
+	states, err := store.ListBootstrapStates()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &ResumeState{}
+	for _, s := range states {
+		switch {
+		case strings.HasPrefix(s.Component, lastBootstrapRefComponent):
+			// Multi-repo incremental markers, not agent checkpoints
+		case s.Component == PersistComponent:
+			state.PersistPending = s.Status != memory.BootstrapStatusCompleted
+		case s.Status != memory.BootstrapStatusCompleted:
+			state.FailedAgents = append(state.FailedAgents, s.Component)
+		}
+	}
+	sort.Strings(state.FailedAgents)
+	return state, nil
+}
+
+// SaveAnalysis persists findings from the agents that succeeded and records
+// per-agent checkpoints so a failed run can be resumed. Agents are marked
+// completed only after their findings are saved; failed agents are marked
+// failed and re-run by the next bootstrap. failures maps agent name to error.
+func (s *Service) SaveAnalysis(ctx context.Context, results []core.Output, failures map[string]error, isPreview, isQuiet bool) error {
+	return s.saveAnalysis(ctx, results, failures, nil, isPreview, isQuiet)
+}
+
+// saveAnalysis is SaveAnalysis scoped to filePaths for incremental runs.
+// After a fully successful run, HEAD is recorded as the base for the next
+// incremental bootstrap.
+func (s *Service) saveAnalysis(ctx context.Context, results []core.Output, failures map[string]error, filePaths []string, isPreview, isQuiet bool) error {
+	failed := make(map[string]error, len(failures))
+	for name, err := range failures {
+		failed[name] = err
+	}
+	var succeeded []core.Output
+	for _, r := range results {
+		if _, ok := failed[r.AgentName]; ok {
+			continue
+		}
+		if r.Error != nil {
+			failed[r.AgentName] = r.Error
+			continue
+		}
+		succeeded = append(succeeded, r)
+	}
+
+	if isPreview {
+		return s.persist(ctx, succeeded, filePaths, true, isQuiet)
+	}
+
+	store, err := memory.NewSQLiteStore(s.storePath)
+	if err != nil {
+		return fmt.Errorf("open memory store: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	_ = store.SetBootstrapState(&memory.BootstrapState{Component: PersistComponent, Status: memory.BootstrapStatusInProgress})
+	persistErr := s.persist(ctx, succeeded, filePaths, false, isQuiet)
+
+	persistState := &memory.BootstrapState{Component: PersistComponent, Status: memory.BootstrapStatusCompleted}
+	agentStatus := memory.BootstrapStatusCompleted
+	if persistErr != nil {
+		persistState.Status = memory.BootstrapStatusFailed
+		persistState.ErrorMessage = persistErr.Error()
+		agentStatus = memory.BootstrapStatusPending
+	}
+	for _, r := range succeeded {
+		_ = store.SetBootstrapState(&memory.BootstrapState{
+			Component: r.AgentName,
+			Status:    agentStatus,
+			Metadata: map[string]any{
+				"findings_count": len(r.Findings),
+				"duration_ms":    r.Duration.Milliseconds(),
+			},
+		})
+	}
+	for name, ferr := range failed {
+		state := &memory.BootstrapState{Component: name, Status: memory.BootstrapStatusFailed}
+		if ferr != nil {
+			state.ErrorMessage = ferr.Error()
+		}
+		_ = store.SetBootstrapState(state)
+	}
+	_ = store.SetBootstrapState(persistState)
+
+	if persistErr != nil {
+		return persistErr
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("bootstrap failed: %d agent(s) errored; re-run 'taskwing bootstrap' to retry them", len(failed))
+	}
+	s.recordBootstrapRef(store)
+	return nil
+}
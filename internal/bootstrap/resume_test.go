@@ -0,0 +1,95 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+)
+
+func TestService_ResumeInterruptedBootstrap(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	storePath := t.TempDir()
+	svc := NewService(dir, storePath, llm.Config{})
+
+	var persisted []string
+	svc.persist = func(_ context.Context, results []core.Output, _ []string, _, _ bool) error {
+		for _, f := range core.AggregateFindings(results) {
+			persisted = append(persisted, f.Title)
+		}
+		return nil
+	}
+
+	if err := svc.InitializeProject(false, nil); err != nil {
+		t.Fatalf("InitializeProject: %v", err)
+	}
+
+	// First run: the code agent fails midway through analysis
+	results := []core.Output{
+		{AgentName: "doc", Findings: []core.Finding{{Title: "Doc finding"}}},
+		{AgentName: "code", Error: errors.New("llm timeout")},
+	}
+	if err := svc.SaveAnalysis(ctx, results, nil, false, true); err == nil {
+		t.Fatal("expected error when an agent failed")
+	}
+
+	state, err := DetectInterruptedBootstrap(storePath)
+	if err != nil {
+		t.Fatalf("DetectInterruptedBootstrap: %v", err)
+	}
+	if !state.Interrupted() || !slices.Equal(state.FailedAgents, []string{"code"}) || state.PersistPending {
+		t.Fatalf("resume state = %+v, want only code failed", state)
+	}
+
+	// Re-run planning: structure exists, so only the analysis is resumed
+	snap := &Snapshot{
+		Project:         ProjectHealth{Status: HealthOK, DirExists: true, DBAccessible: true},
+		HasAnyLocalAI:   true,
+		ExistingLocalAI: []string{"claude"},
+		Resume:          state,
+	}
+	plan := DecidePlan(snap, Flags{})
+	if !plan.ResumeAnalysis {
+		t.Error("plan should resume the interrupted analysis")
+	}
+	for _, a := range plan.Actions {
+		if a == ActionInitProject || a == ActionIndexCode || a == ActionExtractMetadata {
+			t.Errorf("resume plan should not include %s: %v", a, plan.Actions)
+		}
+	}
+	if !slices.Contains(plan.Actions, ActionLLMAnalyze) {
+		t.Errorf("resume plan should include %s: %v", ActionLLMAnalyze, plan.Actions)
+	}
+
+	// Second run: only the failed agent is re-run and it succeeds
+	results = []core.Output{{AgentName: "code", Findings: []core.Finding{{Title: "Code finding"}}}}
+	if err := svc.SaveAnalysis(ctx, results, nil, false, true); err != nil {
+		t.Fatalf("SaveAnalysis on resume: %v", err)
+	}
+
+	state, err = DetectInterruptedBootstrap(storePath)
+	if err != nil {
+		t.Fatalf("DetectInterruptedBootstrap: %v", err)
+	}
+	if state.Interrupted() {
+		t.Errorf("bootstrap should be complete after resume, got %+v", state)
+	}
+	if !slices.Equal(persisted, []string{"Doc finding", "Code finding"}) {
+		t.Errorf("persisted findings = %v, want each finding once", persisted)
+	}
+
+	entries, err := os.ReadDir(storePath)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			t.Errorf("unexpected nested directory in store: %s", e.Name())
+		}
+	}
+}
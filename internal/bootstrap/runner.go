@@ -37,6 +37,35 @@ func (r *Runner) Close() {
 type RunOptions struct {
 	Workspace    string   // Workspace name for monorepo support ('root' for global, service name for scoped)
 	ChangedFiles []string // If set, only analyze these files (incremental mode)
+	IncludeDirs  []string // If set, limit doc gathering to these directories
+	ExcludeDirs  []string // Directories skipped during doc gathering
+
+	GitHistory core.GitHistoryWindow // Limits the git history read; zero = default
+}
+
+// agentInput builds the shared agent input for a run. ChangedFiles switches
+// agents to incremental (watch) mode over just those files.
+func agentInput(projectPath string, opts RunOptions) core.Input {
+	workspace := opts.Workspace
+	if workspace == "" {
+		workspace = "root"
+	}
+
+	input := core.Input{
+		BasePath:    projectPath,
+		ProjectName: filepath.Base(projectPath),
+		Mode:        core.ModeBootstrap,
+		Verbose:     false,
+		Workspace:   workspace,
+		IncludeDirs: opts.IncludeDirs,
+		ExcludeDirs: opts.ExcludeDirs,
+		GitHistory:  opts.GitHistory,
+	}
+	if len(opts.ChangedFiles) > 0 {
+		input.Mode = core.ModeWatch
+		input.ChangedFiles = opts.ChangedFiles
+	}
+	return input
 }
 
 // ProviderSupportsBatch returns true if the provider has a batch API with cost savings.
@@ -66,8 +95,9 @@ func (r *Runner) RunWithOptions(ctx context.Context, projectPath string, opts Ru
 	default:
 	}
 
-	// Auto-batch when provider supports it and there are batchable agents
-	if ProviderSupportsBatch(r.llmCfg.Provider) && hasBatchableAgents(r.agents) {
+	// Auto-batch when provider supports it and there are batchable agents.
+	// Incremental runs are small and use the agents' watch mode, so run them sync.
+	if len(opts.ChangedFiles) == 0 && ProviderSupportsBatch(r.llmCfg.Provider) && hasBatchableAgents(r.agents) {
 		results, err := r.runWithBatch(ctx, projectPath, opts)
 		if err == nil {
 			return results, nil
@@ -80,25 +110,20 @@ func (r *Runner) RunWithOptions(ctx context.Context, projectPath string, opts Ru
 
 // runSync is the original wave-based parallel execution path.
 func (r *Runner) runSync(ctx context.Context, projectPath string, opts RunOptions) ([]core.Output, error) {
-	workspace := opts.Workspace
-	if workspace == "" {
-		workspace = "root"
-	}
+	input := agentInput(projectPath, opts)
 
-	input := core.Input{
-		BasePath:    projectPath,
-		ProjectName: filepath.Base(projectPath),
-		Mode:        core.ModeBootstrap,
-		Verbose:     false,
-		Workspace:   workspace,
+	// Incremental mode: only agents that cover the changed files
+	agents := r.agents
+	if len(opts.ChangedFiles) > 0 {
+		agents = agentsForChangedFiles(agents, opts.ChangedFiles)
 	}
 
 	// Split agents into waves
-	wave1Agents, wave2Agents := splitAgentsByWave(r.agents)
+	wave1Agents, wave2Agents := splitAgentsByWave(agents)
 
 	// If no wave2 agents, just run everything in parallel (single wave)
 	if len(wave2Agents) == 0 {
-		return runParallel(ctx, r.agents, input)
+		return runParallel(ctx, agents, input)
 	}
 
 	// Wave 1: doc + deps (parallel)
@@ -140,18 +165,7 @@ func hasBatchableAgents(agents []core.Agent) bool {
 // runWithBatch executes batchable agents via the Batch API and non-batchable agents in parallel.
 // Called automatically by RunWithOptions when the provider supports batch.
 func (r *Runner) runWithBatch(ctx context.Context, projectPath string, opts RunOptions) ([]core.Output, error) {
-	workspace := opts.Workspace
-	if workspace == "" {
-		workspace = "root"
-	}
-
-	input := core.Input{
-		BasePath:    projectPath,
-		ProjectName: filepath.Base(projectPath),
-		Mode:        core.ModeBootstrap,
-		Verbose:     false,
-		Workspace:   workspace,
-	}
+	input := agentInput(projectPath, opts)
 
 	// Split agents into batchable vs non-batchable
 	var batchable []core.BatchableAgent
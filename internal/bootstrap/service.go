@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -26,6 +27,17 @@ type Service struct {
 	storePath   string       // global store (~/.taskwing/projects/<slug>/)
 	llmCfg      llm.Config
 	initializer *Initializer
+	gitHistory  core.GitHistoryWindow // Passed to agents; zero = default history
+	includeDirs []string              // Limits doc gathering to these directories; nil = all
+	excludeDirs []string              // Directories skipped during doc gathering
+	out         io.Writer             // Progress messages (os.Stdout by default)
+
+	// persist saves analysis results to memory. filePaths scopes an
+	// incremental update; nil means a full update. Overridden in tests.
+	persist func(ctx context.Context, results []core.Output, filePaths []string, isPreview, isQuiet bool) error
+
+	// newRunner creates the runner for one multi-repo service. Overridden in tests.
+	newRunner func(servicePath string) *Runner
 }
 
 // BootstrapResult contains the outcome of a bootstrap operation including warnings.
@@ -37,12 +49,20 @@ type BootstrapResult struct {
 
 // NewService creates a new bootstrap service.
 func NewService(basePath, storePath string, llmCfg llm.Config) *Service {
-	return &Service{
+	s := &Service{
 		basePath:    basePath,
 		storePath:   storePath,
 		llmCfg:      llmCfg,
 		initializer: NewInitializer(basePath, storePath),
+		out:         os.Stdout,
+	}
+	s.persist = func(ctx context.Context, results []core.Output, filePaths []string, isPreview, isQuiet bool) error {
+		return s.processAndSave(ctx, results, core.AggregateFindings(results), core.AggregateRelationships(results), filePaths, isPreview, isQuiet)
 	}
+	s.newRunner = func(servicePath string) *Runner {
+		return NewRunner(s.llmCfg, servicePath)
+	}
+	return s
 }
 
 // SetVersion sets the CLI version on the underlying initializer so that
@@ -51,6 +71,44 @@ func (s *Service) SetVersion(v string) {
 	s.initializer.Version = v
 }
 
+// SetOutput redirects progress messages from the service, its initializer
+// and knowledge ingestion, e.g. to stderr when stdout is reserved for JSON.
+func (s *Service) SetOutput(w io.Writer) {
+	s.out = w
+	s.initializer.SetOutput(w)
+}
+
+// SetGitHistory limits the git history read by the git agent.
+func (s *Service) SetGitHistory(window core.GitHistoryWindow) {
+	s.gitHistory = window
+}
+
+// SetDirScope limits doc gathering to the include directories (all if empty)
+// minus the exclude directories. Paths are relative to the analyzed project,
+// or to each service in a multi-repo workspace.
+func (s *Service) SetDirScope(include, exclude []string) {
+	s.includeDirs = include
+	s.excludeDirs = exclude
+}
+
+// runOptions returns the runner options configured on the service.
+func (s *Service) runOptions(workspace string) RunOptions {
+	return RunOptions{
+		Workspace:   workspace,
+		IncludeDirs: s.includeDirs,
+		ExcludeDirs: s.excludeDirs,
+		GitHistory:  s.gitHistory,
+	}
+}
+
+// AgentInput builds the input for a full analysis of projectPath with the
+// git history window and directory scope configured on the service.
+func (s *Service) AgentInput(projectPath string, verbose bool) core.Input {
+	input := agentInput(projectPath, s.runOptions("root"))
+	input.Verbose = verbose
+	return input
+}
+
 // InitializeProject sets up the .taskwing directory structure and integrations.
 func (s *Service) InitializeProject(verbose bool, selectedAIs []string) error {
 	return s.initializer.Run(verbose, selectedAIs)
@@ -62,16 +120,46 @@ func (s *Service) RegenerateAIConfigs(verbose bool, targetAIs []string) error {
 	return s.initializer.RegenerateConfigs(verbose, targetAIs)
 }
 
+// RefreshOutdatedAIConfigs regenerates managed AI configs generated by an older TaskWing version.
+// Returns the AIs that were regenerated.
+func (s *Service) RefreshOutdatedAIConfigs(verbose bool) ([]string, error) {
+	return s.initializer.RefreshOutdatedConfigs(verbose)
+}
+
+// RemovedAIConfigs returns no-longer-supported AIs that still have a managed config in the project.
+func (s *Service) RemovedAIConfigs() []string {
+	return s.initializer.RemovedAIConfigs()
+}
+
+// PruneRemovedAIConfigs removes managed configs of no-longer-supported AIs, keeping user-owned files.
+// Returns the AIs whose configs were removed.
+func (s *Service) PruneRemovedAIConfigs(verbose bool) ([]string, error) {
+	return s.initializer.PruneRemovedAIConfigs(verbose)
+}
+
 // ProgressFunc is called during multi-repo analysis with the service name and status.
 type ProgressFunc func(serviceName string, status string)
 
+// maxIncrementalChangedFiles is the largest diff a multi-repo service is
+// analyzed incrementally for; larger changes re-analyze the service in full.
+const maxIncrementalChangedFiles = 50
+
+// ServiceAnalysis holds the findings of one service in a multi-repo workspace.
+type ServiceAnalysis struct {
+	Service       string
+	Findings      []core.Finding
+	Relationships []core.Relationship
+	ChangedFiles  []string // Workspace-relative files of an incremental run; nil = full analysis
+}
+
 // RunMultiRepoAnalysis executes analysis for all services in a workspace.
 // Each service's findings are tagged with the service name as workspace.
+// Services with fewer than maxIncrementalChangedFiles changes since their
+// last analysis are analyzed incrementally over just those files.
 // If onProgress is non-nil, it is called before and after each service analysis.
 // NOTE: Not safe for concurrent use. Swaps global project context per-service.
-func (s *Service) RunMultiRepoAnalysis(ctx context.Context, ws *project.WorkspaceInfo, onProgress ProgressFunc) ([]core.Finding, []core.Relationship, []string, error) {
-	var allFindings []core.Finding
-	var allRelationships []core.Relationship
+func (s *Service) RunMultiRepoAnalysis(ctx context.Context, ws *project.WorkspaceInfo, onProgress ProgressFunc) ([]ServiceAnalysis, []string, error) {
+	var analyses []ServiceAnalysis
 	var serviceErrors []string
 
 	// Save the workspace-level project context to restore after each service
@@ -89,10 +177,10 @@ func (s *Service) RunMultiRepoAnalysis(ctx context.Context, ws *project.Workspac
 			_ = config.SetProjectContext(svcCtx)
 		}
 
-		runner := NewRunner(s.llmCfg, servicePath)
+		runner := s.newRunner(servicePath)
 
 		// Incremental mode: check if we can skip or limit analysis
-		opts := RunOptions{Workspace: serviceName}
+		opts := s.runOptions(serviceName)
 		stateKey := "bootstrap-sha-" + serviceName
 		dbPath := s.storePath
 		if store, storeErr := memory.NewSQLiteStore(dbPath); storeErr == nil {
@@ -116,7 +204,16 @@ func (s *Service) RunMultiRepoAnalysis(ctx context.Context, ws *project.Workspac
 						runner.Close()
 						continue
 					}
-					if changedFiles != nil && len(changedFiles) < 50 {
+					if changedFiles != nil && len(changedFiles) < maxIncrementalChangedFiles {
+						if len(agentsForChangedFiles(runner.agents, changedFiles)) == 0 {
+							if onProgress != nil {
+								onProgress(serviceName, fmt.Sprintf("[%d/%d] no analyzable changes", i+1, len(ws.Services)))
+							}
+							_ = store.SetBootstrapState(&memory.BootstrapState{Component: stateKey, Status: "completed", Checksum: headSHA})
+							_ = store.Close()
+							runner.Close()
+							continue
+						}
 						opts.ChangedFiles = changedFiles
 					}
 				}
@@ -152,6 +249,10 @@ func (s *Service) RunMultiRepoAnalysis(ctx context.Context, ws *project.Workspac
 			serviceErrors = append(serviceErrors, fmt.Sprintf("%s: compute relative path: %s", serviceName, relErr.Error()))
 			continue
 		}
+		var changedFiles []string
+		for _, f := range opts.ChangedFiles {
+			changedFiles = append(changedFiles, filepath.Join(serviceRelPath, f))
+		}
 		for i := range findings {
 			for j := range findings[i].Evidence {
 				ev := &findings[i].Evidence[j]
@@ -175,8 +276,12 @@ func (s *Service) RunMultiRepoAnalysis(ctx context.Context, ws *project.Workspac
 			relationships[i].To = fmt.Sprintf("[%s] %s", serviceName, relationships[i].To)
 		}
 
-		allFindings = append(allFindings, findings...)
-		allRelationships = append(allRelationships, relationships...)
+		analyses = append(analyses, ServiceAnalysis{
+			Service:       serviceName,
+			Findings:      findings,
+			Relationships: relationships,
+			ChangedFiles:  changedFiles,
+		})
 
 		// Save git SHA for incremental mode on next run
 		if headSHA := getGitHEAD(servicePath); headSHA != "" {
@@ -195,11 +300,16 @@ func (s *Service) RunMultiRepoAnalysis(ctx context.Context, ws *project.Workspac
 		}
 	}
 
-	return allFindings, allRelationships, serviceErrors, nil
+	return analyses, serviceErrors, nil
 }
 
 // ProcessAndSaveResults aggregates, reports, and ingests findings into the knowledge system.
 func (s *Service) ProcessAndSaveResults(ctx context.Context, results []core.Output, findings []core.Finding, relationships []core.Relationship, isPreview, isQuiet bool) error {
+	return s.processAndSave(ctx, results, findings, relationships, nil, isPreview, isQuiet)
+}
+
+// processAndSave is ProcessAndSaveResults scoped to filePaths for incremental runs.
+func (s *Service) processAndSave(ctx context.Context, results []core.Output, findings []core.Finding, relationships []core.Relationship, filePaths []string, isPreview, isQuiet bool) error {
 	// 1. Generate and save report
 	report := generateReport(s.basePath, results, findings)
 	reportPath := filepath.Join(s.storePath, "last-bootstrap-report.json")
@@ -209,23 +319,46 @@ func (s *Service) ProcessAndSaveResults(ctx context.Context, results []core.Outp
 	}
 
 	// 2. Print summary using consistent UI renderer
-	ui.RenderBootstrapResults(report)
+	ui.RenderBootstrapResults(s.out, report)
 
 	if isPreview {
-		fmt.Println("\n💡 This was a preview. Run 'taskwing bootstrap' to save to memory.")
+		fmt.Fprintln(s.out, "\n💡 This was a preview. Run 'taskwing bootstrap' to save to memory.")
 		return nil
 	}
 
 	// 3. Ingest into Knowledge System
-	return s.ingestToMemory(ctx, findings, relationships, isQuiet)
+	return s.ingestToMemory(ctx, findings, relationships, filePaths, isQuiet)
 }
 
-// IngestDirectly ingests pre-aggregated findings (used by Multi-repo mode).
-func (s *Service) IngestDirectly(ctx context.Context, findings []core.Finding, relationships []core.Relationship, isQuiet bool) error {
-	return s.ingestToMemory(ctx, findings, relationships, isQuiet)
+// IngestServices ingests multi-repo analysis results. Fully analyzed services
+// are ingested together; each incrementally analyzed service is ingested on
+// its own, scoped to its changed files so the rest of its knowledge is kept.
+func (s *Service) IngestServices(ctx context.Context, analyses []ServiceAnalysis, isQuiet bool) error {
+	var findings []core.Finding
+	var relationships []core.Relationship
+	for _, a := range analyses {
+		if a.ChangedFiles == nil {
+			findings = append(findings, a.Findings...)
+			relationships = append(relationships, a.Relationships...)
+			continue
+		}
+		if len(a.Findings) == 0 {
+			continue
+		}
+		if err := s.ingestToMemory(ctx, a.Findings, a.Relationships, a.ChangedFiles, isQuiet); err != nil {
+			return fmt.Errorf("ingest %s: %w", a.Service, err)
+		}
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	return s.ingestToMemory(ctx, findings, relationships, nil, isQuiet)
 }
 
-func (s *Service) ingestToMemory(ctx context.Context, findings []core.Finding, relationships []core.Relationship, isQuiet bool) error {
+// ingestToMemory saves findings into the knowledge graph. filePaths limits
+// stale-marking to nodes from those files so an incremental run merges into
+// existing knowledge; nil treats the findings as a full update.
+func (s *Service) ingestToMemory(ctx context.Context, findings []core.Finding, relationships []core.Relationship, filePaths []string, isQuiet bool) error {
 	memoryPath := s.storePath
 	if memoryPath == "" {
 		var err error
@@ -246,9 +379,10 @@ func (s *Service) ingestToMemory(ctx context.Context, findings []core.Finding, r
 	// Create Knowledge Service
 	ks := knowledge.NewService(repo, s.llmCfg)
 	ks.SetBasePath(s.basePath)
+	ks.SetOutput(s.out)
 
 	// Ingest
-	if err := ks.IngestFindingsWithRelationships(ctx, findings, relationships, nil, !isQuiet); err != nil {
+	if err := ks.IngestFindingsWithRelationships(ctx, findings, relationships, filePaths, !isQuiet); err != nil {
 		return err
 	}
 
@@ -263,7 +397,7 @@ func (s *Service) ingestToMemory(ctx context.Context, findings []core.Finding, r
 		// Log warning but don't fail bootstrap
 		fmt.Fprintf(os.Stderr, "⚠️  Failed to generate ARCHITECTURE.md: %v\n", err)
 	} else if !isQuiet {
-		fmt.Println("   ✓ Generated ARCHITECTURE.md")
+		fmt.Fprintln(s.out, "   ✓ Generated ARCHITECTURE.md")
 	}
 
 	return nil
@@ -277,13 +411,13 @@ func (s *Service) generateOverviewIfNeeded(ctx context.Context, repo *memory.Rep
 	}
 	if existing != nil {
 		if verbose {
-			fmt.Println("\n📋 Project overview already exists (re-run bootstrap with --force to refresh)")
+			fmt.Fprintln(s.out, "\n📋 Project overview already exists (re-run bootstrap with --force to refresh)")
 		}
 		return nil
 	}
 
 	if verbose {
-		fmt.Println("\n📋 Generating project overview...")
+		fmt.Fprintln(s.out, "\n📋 Generating project overview...")
 	}
 
 	analyzer := NewOverviewAnalyzer(s.llmCfg, s.basePath)
@@ -297,8 +431,8 @@ func (s *Service) generateOverviewIfNeeded(ctx context.Context, repo *memory.Rep
 	}
 
 	if verbose {
-		fmt.Println("   ✓ Project overview generated")
-		fmt.Printf("   \"%s\"\n", overview.ShortDescription)
+		fmt.Fprintln(s.out, "   ✓ Project overview generated")
+		fmt.Fprintf(s.out, "   \"%s\"\n", overview.ShortDescription)
 	}
 	return nil
 }
@@ -326,9 +460,9 @@ func (s *Service) RunDeterministicBootstrap(ctx context.Context, isQuiet bool) (
 	defer func() { _ = repo.Close() }()
 
 	if !isQuiet {
-		fmt.Println()
-		fmt.Println("📊 Extracting Project Metadata")
-		fmt.Println("──────────────────────────────")
+		fmt.Fprintln(s.out)
+		fmt.Fprintln(s.out, "📊 Extracting Project Metadata")
+		fmt.Fprintln(s.out, "──────────────────────────────")
 	}
 
 	var findings []core.Finding
@@ -336,7 +470,7 @@ func (s *Service) RunDeterministicBootstrap(ctx context.Context, isQuiet bool) (
 
 	// 1. Extract Git Statistics (deterministic)
 	if !isQuiet {
-		fmt.Print("   📈 Analyzing git history...")
+		fmt.Fprint(s.out, "   📈 Analyzing git history...")
 	}
 	gitParser := NewGitStatParser(s.basePath)
 	gitStats, err := gitParser.Parse()
@@ -345,14 +479,14 @@ func (s *Service) RunDeterministicBootstrap(ctx context.Context, isQuiet bool) (
 		result.Warnings = append(result.Warnings, fmt.Sprintf("git stats: %v", err))
 		if !isQuiet {
 			if strings.Contains(err.Error(), "not a git repository") {
-				fmt.Println(" skipped (not a git repository)")
+				fmt.Fprintln(s.out, " skipped (not a git repository)")
 			} else {
-				fmt.Printf(" skipped (%v)\n", err)
+				fmt.Fprintf(s.out, " skipped (%v)\n", err)
 			}
 		}
 	} else {
 		if !isQuiet {
-			fmt.Printf(" %d commits, %d contributors\n", gitStats.TotalCommits, len(gitStats.Contributors))
+			fmt.Fprintf(s.out, " %d commits, %d contributors\n", gitStats.TotalCommits, len(gitStats.Contributors))
 		}
 		// Convert to finding for storage (deterministic bootstrap data)
 		findings = append(findings, core.Finding{
@@ -371,7 +505,7 @@ func (s *Service) RunDeterministicBootstrap(ctx context.Context, isQuiet bool) (
 	// 2. Load Documentation Files (deterministic)
 	// For multi-repo workspaces, also scan sub-repo directories
 	if !isQuiet {
-		fmt.Print("   📄 Loading documentation...")
+		fmt.Fprint(s.out, "   📄 Loading documentation...")
 	}
 	docLoader := NewDocLoader(s.basePath)
 	ws, wsErr := project.DetectWorkspace(s.basePath)
@@ -385,7 +519,7 @@ func (s *Service) RunDeterministicBootstrap(ctx context.Context, isQuiet bool) (
 		// Track warning instead of silently swallowing
 		result.Warnings = append(result.Warnings, fmt.Sprintf("doc loader: %v", err))
 		if !isQuiet {
-			fmt.Printf(" failed (%v)\n", err)
+			fmt.Fprintf(s.out, " failed (%v)\n", err)
 		}
 	} else {
 		if !isQuiet {
@@ -394,15 +528,15 @@ func (s *Service) RunDeterministicBootstrap(ctx context.Context, isQuiet bool) (
 			for _, doc := range docs {
 				categories[doc.Category]++
 			}
-			fmt.Printf(" %d files", len(docs))
+			fmt.Fprintf(s.out, " %d files", len(docs))
 			if len(categories) > 0 {
 				var parts []string
 				for cat, count := range categories {
 					parts = append(parts, fmt.Sprintf("%d %s", count, cat))
 				}
-				fmt.Printf(" (%s)", joinMax(parts, 3))
+				fmt.Fprintf(s.out, " (%s)", joinMax(parts, 3))
 			}
-			fmt.Println()
+			fmt.Fprintln(s.out)
 		}
 		// Convert each doc to a finding for storage and RAG retrieval
 		for _, doc := range docs {
@@ -422,7 +556,7 @@ func (s *Service) RunDeterministicBootstrap(ctx context.Context, isQuiet bool) (
 
 	if len(findings) == 0 {
 		if !isQuiet {
-			fmt.Println("   ⚠️  No metadata extracted (not a git repo or no docs)")
+			fmt.Fprintln(s.out, "   ⚠️  No metadata extracted (not a git repo or no docs)")
 		}
 		result.Warnings = append(result.Warnings, "no metadata extracted (not a git repo or no docs)")
 		return result, nil
@@ -431,22 +565,23 @@ func (s *Service) RunDeterministicBootstrap(ctx context.Context, isQuiet bool) (
 	// 3. Ingest findings to knowledge graph
 	ks := knowledge.NewService(repo, s.llmCfg)
 	ks.SetBasePath(s.basePath)
+	ks.SetOutput(s.out)
 
 	if !isQuiet {
-		fmt.Print("   💾 Storing to memory...")
+		fmt.Fprint(s.out, "   💾 Storing to memory...")
 	}
 
 	if err := ks.IngestFindings(ctx, findings, nil, false); err != nil {
 		if !isQuiet {
-			fmt.Println(" failed")
+			fmt.Fprintln(s.out, " failed")
 		}
 		return nil, fmt.Errorf("ingest metadata: %w", err)
 	}
 
 	elapsed := time.Since(startTime).Round(time.Millisecond)
 	if !isQuiet {
-		fmt.Printf(" done (%v)\n", elapsed)
-		fmt.Printf("\n   ✅ Extracted %d items in %v\n", len(findings), elapsed)
+		fmt.Fprintf(s.out, " done (%v)\n", elapsed)
+		fmt.Fprintf(s.out, "\n   ✅ Extracted %d items in %v\n", len(findings), elapsed)
 	}
 
 	result.FindingsCount = len(findings)
@@ -0,0 +1,74 @@
+package bootstrap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/agents/tools"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+)
+
+func TestService_DirScopeReachesDocGatherer(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"README.md":              "# Root readme\n",
+		"docs/guide.md":          "# Included guide\n",
+		"docs/internal/notes.md": "# Excluded notes\n",
+		"vendor/lib/README.md":   "# Vendored readme\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Flags as filled by bootstrap --include-dir/--exclude-dir
+	flags := Flags{IncludeDirs: []string{"docs"}, ExcludeDirs: []string{"docs/internal"}}
+	if err := ValidateFlags(flags); err != nil {
+		t.Fatalf("ValidateFlags: %v", err)
+	}
+	if err := ValidateFlags(Flags{IncludeDirs: []string{"../other"}}); err == nil {
+		t.Error("ValidateFlags accepted an include directory outside the project")
+	}
+
+	svc := NewService(dir, t.TempDir(), llm.Config{})
+	svc.SetDirScope(flags.IncludeDirs, flags.ExcludeDirs)
+
+	// Full analysis: the TUI hands this input to the agents
+	input := svc.AgentInput(dir, false)
+	gatherer := tools.NewContextGatherer(input.BasePath)
+	gatherer.SetDirScope(input.IncludeDirs, input.ExcludeDirs)
+	docs := gatherer.GatherMarkdownDocs()
+	for _, want := range []string{"Root readme", "Included guide"} {
+		if !strings.Contains(docs, want) {
+			t.Errorf("gathered docs missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"Excluded notes", "Vendored readme"} {
+		if strings.Contains(docs, unwanted) {
+			t.Errorf("gathered docs contain out-of-scope %q", unwanted)
+		}
+	}
+
+	// Runner path used by multi-repo and incremental analysis
+	agent := &recordingAgent{name: "doc"}
+	runner := &Runner{agents: []core.Agent{agent}}
+	if _, err := runner.RunWithOptions(context.Background(), dir, svc.runOptions("root")); err != nil {
+		t.Fatalf("RunWithOptions: %v", err)
+	}
+	if len(agent.inputs) != 1 {
+		t.Fatalf("agent ran %d times, want 1", len(agent.inputs))
+	}
+	got := agent.inputs[0]
+	if !slices.Equal(got.IncludeDirs, flags.IncludeDirs) || !slices.Equal(got.ExcludeDirs, flags.ExcludeDirs) {
+		t.Errorf("agent input scope = %v/%v, want %v/%v", got.IncludeDirs, got.ExcludeDirs, flags.IncludeDirs, flags.ExcludeDirs)
+	}
+}
@@ -0,0 +1,69 @@
+package bootstrap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+// RunSummary is the machine-readable outcome of a bootstrap run (--json).
+type RunSummary struct {
+	StructureCreated  bool     `json:"structure_created"`  // .taskwing structure was initialized
+	AIsConfigured     []string `json:"ais_configured"`     // AI integrations written or refreshed
+	FeaturesExtracted int      `json:"features_extracted"` // Feature nodes added by this run
+	NodesAdded        int      `json:"nodes_added"`        // Knowledge nodes added by this run
+	Duration          float64  `json:"duration"`           // Wall time in seconds
+}
+
+// NodeCounts is a snapshot of the knowledge store size.
+type NodeCounts struct {
+	Total    int
+	Features int
+}
+
+// CountNodes returns the number of knowledge nodes currently in the project store.
+func (s *Service) CountNodes() (NodeCounts, error) {
+	memoryPath := s.storePath
+	if memoryPath == "" {
+		var err error
+		memoryPath, err = config.GetMemoryBasePath()
+		if err != nil {
+			return NodeCounts{}, fmt.Errorf("get memory path: %w", err)
+		}
+	}
+
+	repo, err := memory.NewDefaultRepository(memoryPath)
+	if err != nil {
+		return NodeCounts{}, fmt.Errorf("open memory repo: %w", err)
+	}
+	defer func() { _ = repo.Close() }()
+
+	nodes, err := repo.ListNodes("")
+	if err != nil {
+		return NodeCounts{}, fmt.Errorf("list nodes: %w", err)
+	}
+	counts := NodeCounts{Total: len(nodes)}
+	for _, n := range nodes {
+		if n.Type == memory.NodeTypeFeature {
+			counts.Features++
+		}
+	}
+	return counts, nil
+}
+
+// NewRunSummary builds a run summary from node counts taken before and after
+// the run. Counts never go negative when a forced run replaces knowledge.
+func NewRunSummary(before, after NodeCounts, structureCreated bool, ais []string, elapsed time.Duration) RunSummary {
+	if ais == nil {
+		ais = []string{}
+	}
+	return RunSummary{
+		StructureCreated:  structureCreated,
+		AIsConfigured:     ais,
+		FeaturesExtracted: max(after.Features-before.Features, 0),
+		NodesAdded:        max(after.Total-before.Total, 0),
+		Duration:          elapsed.Seconds(),
+	}
+}
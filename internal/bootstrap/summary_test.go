@@ -0,0 +1,70 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/josephgoksu/TaskWing/internal/llm"
+)
+
+func TestRunSummary_JSONAfterBootstrap(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"README.md":            "# Shop\n\nAn online shop.\n",
+		"docs/architecture.md": "# Architecture\n\nCheckout talks to payments.\n",
+		"go.mod":               "module example.com/shop\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewService(dir, t.TempDir(), llm.Config{})
+
+	start := time.Now()
+	before, err := svc.CountNodes()
+	if err != nil {
+		t.Fatalf("CountNodes: %v", err)
+	}
+	if err := svc.InitializeProject(false, nil); err != nil {
+		t.Fatalf("InitializeProject: %v", err)
+	}
+	if _, err := svc.RunDeterministicBootstrap(ctx, true); err != nil {
+		t.Fatalf("RunDeterministicBootstrap: %v", err)
+	}
+	after, err := svc.CountNodes()
+	if err != nil {
+		t.Fatalf("CountNodes: %v", err)
+	}
+
+	data, err := json.Marshal(NewRunSummary(before, after, true, nil, time.Since(start)))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("summary is not valid JSON: %v\n%s", err, data)
+	}
+	for _, field := range []string{"structure_created", "ais_configured", "features_extracted", "nodes_added", "duration"} {
+		if _, ok := out[field]; !ok {
+			t.Errorf("summary missing %q: %s", field, data)
+		}
+	}
+	if out["structure_created"] != true {
+		t.Errorf("structure_created = %v, want true", out["structure_created"])
+	}
+	if ais, ok := out["ais_configured"].([]any); !ok || len(ais) != 0 {
+		t.Errorf("ais_configured = %v, want empty list", out["ais_configured"])
+	}
+	if added, _ := out["nodes_added"].(float64); added <= 0 {
+		t.Errorf("nodes_added = %v, want the loaded docs counted", out["nodes_added"])
+	}
+}
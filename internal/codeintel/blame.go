@@ -0,0 +1,68 @@
+package codeintel
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isGitWorkTree reports whether dir is inside a git work tree and git is installed.
+func isGitWorkTree(dir string) bool {
+	if _, err := exec.LookPath("git"); err != nil {
+		return false
+	}
+	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// blameLineTimes returns, for each line of the file (index 0 is line 1), the
+// author time of the commit that last changed it. Uncommitted lines carry the
+// current time. Fails for files git does not track.
+func blameLineTimes(path string) ([]time.Time, error) {
+	cmd := exec.Command("git", "blame", "--line-porcelain", "--", filepath.Base(path))
+	cmd.Dir = filepath.Dir(path)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var times []time.Time
+	var current time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// Content line closes the entry for one source line
+			times = append(times, current)
+		case strings.HasPrefix(line, "author-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				current = time.Unix(sec, 0).UTC()
+			}
+		}
+	}
+	return times, scanner.Err()
+}
+
+// applyBlameTimes sets each symbol's LastModified to the newest change among
+// the lines it spans. Symbols outside the blamed range are left as they are.
+func applyBlameTimes(symbols []Symbol, lineTimes []time.Time) {
+	for i := range symbols {
+		var newest time.Time
+		for line := symbols[i].StartLine; line <= symbols[i].EndLine && line <= len(lineTimes); line++ {
+			if line >= 1 && lineTimes[line-1].After(newest) {
+				newest = lineTimes[line-1]
+			}
+		}
+		if !newest.IsZero() {
+			symbols[i].LastModified = newest
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package codeintel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/josephgoksu/TaskWing/internal/git"
+)
+
+// ChangedSymbol is a symbol whose source lines changed between two git refs.
+type ChangedSymbol struct {
+	Symbol        Symbol `json:"symbol"`
+	AffectedCount int    `json:"affectedCount"` // Symbols that transitively depend on this one
+	AffectedFiles int    `json:"affectedFiles"` // Files containing those dependents
+}
+
+// SymbolsChangedBetween returns the indexed symbols whose line ranges overlap
+// the changes between refA and refB, with an impact summary for each.
+// Line numbers are matched against the current index, so refB should be the
+// indexed revision (typically HEAD).
+func (qs *QueryService) SymbolsChangedBetween(ctx context.Context, refA, refB string) ([]ChangedSymbol, error) {
+	changes, err := git.NewClient(qs.workDir).ChangedLines(refA, refB)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(changes))
+	for f := range changes {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	var changed []ChangedSymbol
+	for _, file := range files {
+		symbols, err := qs.repo.FindSymbolsByFile(ctx, file)
+		if err != nil {
+			return nil, fmt.Errorf("find symbols in %s: %w", file, err)
+		}
+		sort.SliceStable(symbols, func(i, j int) bool {
+			return symbols[i].StartLine < symbols[j].StartLine
+		})
+
+		for _, sym := range symbols {
+			// A package clause spans the whole file; it would match every change
+			if sym.Kind == SymbolPackage || !overlapsAny(changes[file], sym.StartLine, sym.EndLine) {
+				continue
+			}
+			entry := ChangedSymbol{Symbol: sym}
+			if impact, err := qs.AnalyzeImpact(ctx, sym.ID, 0); err == nil {
+				entry.AffectedCount = impact.AffectedCount
+				entry.AffectedFiles = impact.AffectedFiles
+			}
+			changed = append(changed, entry)
+		}
+	}
+
+	return changed, nil
+}
+
+func overlapsAny(ranges []git.LineRange, start, end int) bool {
+	for _, r := range ranges {
+		if r.Overlaps(start, end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package codeintel
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+func TestQueryService_SymbolsChangedBetween(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runGit("init", "-q")
+	writeFile("package main\n\nfunc Alpha() int {\n\treturn 1\n}\n\nfunc Beta() int {\n\treturn 2\n}\n")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "initial")
+	writeFile("package main\n\nfunc Alpha() int {\n\treturn 1\n}\n\nfunc Beta() int {\n\treturn 3\n}\n")
+	runGit("commit", "-q", "-am", "change beta")
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	for _, s := range []Symbol{
+		{Name: "main", Kind: SymbolPackage, FilePath: "main.go", StartLine: 1, EndLine: 9, Language: "go"},
+		{Name: "Alpha", Kind: SymbolFunction, FilePath: "main.go", StartLine: 3, EndLine: 5, Language: "go"},
+		{Name: "Beta", Kind: SymbolFunction, FilePath: "main.go", StartLine: 7, EndLine: 9, Language: "go"},
+	} {
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	qs.SetWorkDir(dir)
+
+	changed, err := qs.SymbolsChangedBetween(ctx, "HEAD~1", "HEAD")
+	if err != nil {
+		t.Fatalf("SymbolsChangedBetween: %v", err)
+	}
+	if len(changed) != 1 || changed[0].Symbol.Name != "Beta" {
+		names := make([]string, len(changed))
+		for i, c := range changed {
+			names[i] = c.Symbol.Name
+		}
+		t.Fatalf("changed symbols = %v, want [Beta]", names)
+	}
+}
+
+func TestIndexer_LastModifiedFromGitBlame(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	commitAt := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commitAt("", "init", "-q")
+	writeFile("main.go", "package main\n\nfunc Alpha() int {\n\treturn 1\n}\n\nfunc Beta() int {\n\treturn 2\n}\n")
+	commitAt("2020-01-01T00:00:00Z", "add", ".")
+	commitAt("2020-01-01T00:00:00Z", "commit", "-q", "-m", "initial")
+	writeFile("main.go", "package main\n\nfunc Alpha() int {\n\treturn 1\n}\n\nfunc Beta() int {\n\treturn 3\n}\n")
+	commitAt("2023-06-01T00:00:00Z", "commit", "-q", "-am", "change beta")
+	writeFile("scratch.go", "package main\n\nfunc Gamma() {}\n")
+
+	indexModified := func(gitBlame bool) map[string]time.Time {
+		t.Helper()
+		store, err := memory.NewSQLiteStore(":memory:")
+		if err != nil {
+			t.Fatalf("NewSQLiteStore: %v", err)
+		}
+		defer func() { _ = store.Close() }()
+		repo := NewRepository(store.DB())
+		cfg := DefaultIndexerConfig()
+		cfg.GitBlame = gitBlame
+		if _, err := NewIndexer(repo, cfg).IndexDirectory(ctx, dir); err != nil {
+			t.Fatalf("IndexDirectory: %v", err)
+		}
+
+		modified := make(map[string]time.Time)
+		for _, file := range []string{"main.go", "scratch.go"} {
+			symbols, err := repo.FindSymbolsByFile(ctx, file)
+			if err != nil {
+				t.Fatalf("FindSymbolsByFile %s: %v", file, err)
+			}
+			for _, s := range symbols {
+				modified[s.Name] = s.LastModified
+			}
+		}
+		return modified
+	}
+
+	// Blame is opt-in: without it no symbol is dated
+	for name, got := range indexModified(false) {
+		if !got.IsZero() {
+			t.Errorf("%s LastModified = %v without git blame, want zero", name, got)
+		}
+	}
+
+	modified := indexModified(true)
+
+	for name, want := range map[string]time.Time{
+		"Alpha": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		"Beta":  time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if got := modified[name]; !got.Equal(want) {
+			t.Errorf("%s LastModified = %v, want last commit %v", name, got, want)
+		}
+	}
+	// Untracked files have no blame data
+	if got := modified["Gamma"]; !got.IsZero() {
+		t.Errorf("untracked Gamma LastModified = %v, want zero", got)
+	}
+}
@@ -0,0 +1,133 @@
+package codeintel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/josephgoksu/TaskWing/internal/knowledge"
+)
+
+// EmbedFunc turns text into an embedding vector.
+type EmbedFunc func(ctx context.Context, text string) ([]float32, error)
+
+// SetEmbedFunc replaces the embedder used for query vectors and embedding
+// backfills. By default the configured LLM provider is called.
+func (qs *QueryService) SetEmbedFunc(fn EmbedFunc) {
+	qs.embedFunc = fn
+}
+
+// embed returns the embedding for text from the configured embedder.
+func (qs *QueryService) embed(ctx context.Context, text string) ([]float32, error) {
+	if qs.embedFunc != nil {
+		return qs.embedFunc(ctx, text)
+	}
+	return knowledge.GenerateEmbedding(ctx, text, qs.llmCfg)
+}
+
+// symbolEmbeddingText is the text embedded for a symbol: its name, signature
+// and doc comment.
+func symbolEmbeddingText(sym Symbol) string {
+	text := sym.Name
+	if sym.Signature != "" {
+		text += " " + sym.Signature
+	}
+	if sym.DocComment != "" {
+		text += " " + sym.DocComment
+	}
+	return text
+}
+
+// BackfillOptions configures BackfillEmbeddings.
+type BackfillOptions struct {
+	// BatchSize is the number of symbols embedded between progress reports
+	// (default 50).
+	BatchSize int
+
+	// Concurrency bounds the embedding calls in flight (default 4).
+	Concurrency int
+
+	// OnProgress, when set, is called after each batch with the number of
+	// symbols processed so far and the total.
+	OnProgress func(done, total int)
+}
+
+// BackfillResult summarizes an embedding backfill.
+type BackfillResult struct {
+	Total    int      `json:"total"`    // Symbols that lacked an embedding
+	Embedded int      `json:"embedded"` // Symbols that have one now
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// BackfillEmbeddings embeds every symbol stored without a vector, e.g. after
+// indexing without an API key, so vector search covers the whole index.
+// It stops with an error when a whole batch fails, which usually means the
+// embedding provider is unavailable; symbols embedded so far are kept.
+func (qs *QueryService) BackfillEmbeddings(ctx context.Context, opts BackfillOptions) (*BackfillResult, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	symbols, err := qs.repo.GetSymbolsWithoutEmbeddings(ctx, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+	result := &BackfillResult{Total: len(symbols)}
+
+	for start := 0; start < len(symbols); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		batch := symbols[start:min(start+batchSize, len(symbols))]
+
+		errs := make([]error, len(batch))
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i, sym := range batch {
+			wg.Add(1)
+			go func(i int, sym Symbol) {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					errs[i] = ctx.Err()
+					return
+				}
+				defer func() { <-sem }()
+
+				embedding, err := qs.embed(ctx, symbolEmbeddingText(sym))
+				if err != nil {
+					errs[i] = fmt.Errorf("embedding for %s: %w", sym.Name, err)
+					return
+				}
+				if err := qs.repo.UpdateSymbolEmbedding(ctx, sym.ID, embedding); err != nil {
+					errs[i] = fmt.Errorf("store embedding for %s: %w", sym.Name, err)
+				}
+			}(i, sym)
+		}
+		wg.Wait()
+
+		failed := 0
+		for _, err := range errs {
+			if err != nil {
+				failed++
+				result.Errors = append(result.Errors, err.Error())
+			}
+		}
+		result.Embedded += len(batch) - failed
+		if opts.OnProgress != nil {
+			opts.OnProgress(start+len(batch), len(symbols))
+		}
+		if failed == len(batch) {
+			return result, fmt.Errorf("embedding backfill stopped: every symbol in a batch failed: %s", result.Errors[len(result.Errors)-1])
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package codeintel
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+func TestQueryService_BackfillEmbeddings(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	for _, s := range []Symbol{
+		{Name: "ValidateJWT", Kind: SymbolFunction, FilePath: "auth/jwt.go", StartLine: 1, EndLine: 9, DocComment: "ValidateJWT checks the bearer token.", Language: "go"},
+		{Name: "RenderInvoice", Kind: SymbolFunction, FilePath: "billing/render.go", StartLine: 1, EndLine: 9, Language: "go"},
+		{Name: "SendReminder", Kind: SymbolFunction, FilePath: "billing/remind.go", StartLine: 1, EndLine: 9, Language: "go"},
+	} {
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+	}
+
+	// Auth-related text points one way, everything else the other
+	qs := NewQueryService(repo, llm.Config{})
+	qs.SetEmbedFunc(func(_ context.Context, text string) ([]float32, error) {
+		lower := strings.ToLower(text)
+		if strings.Contains(lower, "token") || strings.Contains(lower, "authentication") {
+			return []float32{1, 0}, nil
+		}
+		return []float32{0, 1}, nil
+	})
+
+	before, err := qs.HybridSearch(ctx, "authentication", 10)
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+	if len(before) != 0 {
+		t.Fatalf("search before backfill = %v, want nothing without embeddings", before)
+	}
+
+	var progress []int
+	result, err := qs.BackfillEmbeddings(ctx, BackfillOptions{BatchSize: 2, Concurrency: 2, OnProgress: func(done, total int) {
+		if total != 3 {
+			t.Errorf("progress total = %d, want 3", total)
+		}
+		progress = append(progress, done)
+	}})
+	if err != nil {
+		t.Fatalf("BackfillEmbeddings: %v", err)
+	}
+	if result.Total != 3 || result.Embedded != 3 || len(result.Errors) != 0 {
+		t.Errorf("result = %+v, want 3 of 3 embedded", result)
+	}
+	if len(progress) != 2 || progress[1] != 3 {
+		t.Errorf("progress = %v, want two batches ending at 3", progress)
+	}
+	if missing, err := repo.GetSymbolsWithoutEmbeddings(ctx, 10); err != nil || len(missing) != 0 {
+		t.Errorf("symbols without embeddings = %v, err %v; want none", missing, err)
+	}
+
+	after, err := qs.HybridSearch(ctx, "authentication", 10)
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+	if len(after) != 1 || after[0].Symbol.Name != "ValidateJWT" || after[0].Source != "vector" {
+		t.Errorf("search after backfill = %v, want ValidateJWT via vector", after)
+	}
+}
@@ -0,0 +1,192 @@
+package codeintel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// linkGoImplementations recomputes the implements relations between Go types
+// and interfaces from the indexed symbols. Go satisfies interfaces implicitly
+// and the parser works one file at a time without type information, so a
+// concrete type implements an interface when methods with every name in the
+// interface's method set, including methods of embedded interfaces, are
+// declared on it. Empty interfaces are skipped. Returns the number of
+// relations stored.
+func (idx *Indexer) linkGoImplementations(ctx context.Context) (int, error) {
+	ifaceSyms, err := idx.repo.FindSymbolsByKind(ctx, "go", SymbolInterface)
+	if err != nil {
+		return 0, err
+	}
+	if len(ifaceSyms) == 0 {
+		return 0, idx.repo.ReplaceImplementations(ctx, "go", nil)
+	}
+	typeSyms, err := idx.repo.FindSymbolsByKind(ctx, "go", SymbolStruct, SymbolType)
+	if err != nil {
+		return 0, err
+	}
+	methodSyms, err := idx.repo.FindSymbolsByKind(ctx, "go", SymbolMethod)
+	if err != nil {
+		return 0, err
+	}
+
+	// Concrete types by package and name; methods are declared in the package
+	// of their receiver, which the module path identifies
+	typeIDs := make(map[string]uint32, len(typeSyms))
+	for _, t := range typeSyms {
+		typeIDs[t.ModulePath+"."+t.Name] = t.ID
+	}
+	methodSets := make(map[uint32]map[string]bool)
+	for _, m := range methodSyms {
+		recv := goReceiverTypeName(m.Signature, m.Name)
+		id, ok := typeIDs[m.ModulePath+"."+recv]
+		if recv == "" || !ok {
+			continue
+		}
+		if methodSets[id] == nil {
+			methodSets[id] = make(map[string]bool)
+		}
+		methodSets[id][m.Name] = true
+	}
+	typesWithMethods := make([]uint32, 0, len(methodSets))
+	for id := range methodSets {
+		typesWithMethods = append(typesWithMethods, id)
+	}
+	sort.Slice(typesWithMethods, func(i, j int) bool { return typesWithMethods[i] < typesWithMethods[j] })
+
+	ifaces := newGoInterfaceSet(ifaceSyms)
+	var rels []SymbolRelation
+	for _, iface := range ifaceSyms {
+		required := ifaces.methodSet(iface)
+		if len(required) == 0 {
+			continue
+		}
+		for _, typeID := range typesWithMethods {
+			if hasAllMethods(methodSets[typeID], required) {
+				rels = append(rels, SymbolRelation{FromSymbolID: typeID, ToSymbolID: iface.ID, RelationType: RelationImplements})
+			}
+		}
+	}
+	if err := idx.repo.ReplaceImplementations(ctx, "go", rels); err != nil {
+		return 0, fmt.Errorf("store implementations: %w", err)
+	}
+	return len(rels), nil
+}
+
+// goInterfaceSet resolves the method sets of indexed Go interfaces, following
+// embedded interfaces by name.
+type goInterfaceSet struct {
+	byName map[string][]Symbol // Interfaces sharing a name, in index order
+}
+
+func newGoInterfaceSet(ifaces []Symbol) *goInterfaceSet {
+	set := &goInterfaceSet{byName: make(map[string][]Symbol)}
+	for _, s := range ifaces {
+		set.byName[s.Name] = append(set.byName[s.Name], s)
+	}
+	return set
+}
+
+// methodSet returns the method names iface requires, its own and those of the
+// interfaces it embeds.
+func (set *goInterfaceSet) methodSet(iface Symbol) map[string]bool {
+	methods := make(map[string]bool)
+	set.collect(iface, methods, map[uint32]bool{})
+	return methods
+}
+
+func (set *goInterfaceSet) collect(iface Symbol, methods map[string]bool, seen map[uint32]bool) {
+	if seen[iface.ID] {
+		return
+	}
+	seen[iface.ID] = true
+	own, embedded := goInterfaceMembers(iface.Signature)
+	for _, m := range own {
+		methods[m] = true
+	}
+	for _, name := range embedded {
+		if e, ok := set.lookup(name, iface.ModulePath); ok {
+			set.collect(e, methods, seen)
+		}
+	}
+}
+
+// lookup finds the interface an embedded name refers to. A package-qualified
+// name ("io.Reader") matches an interface whose module path ends in that
+// package; an unqualified one prefers the embedding interface's own package.
+// Ties go to the first interface in index order.
+func (set *goInterfaceSet) lookup(name, modulePath string) (Symbol, bool) {
+	pkg, base, qualified := strings.Cut(name, ".")
+	if !qualified {
+		base = name
+	}
+	candidates := set.byName[base]
+	for _, c := range candidates {
+		if qualified && (c.ModulePath == pkg || strings.HasSuffix(c.ModulePath, "/"+pkg)) {
+			return c, true
+		}
+		if !qualified && c.ModulePath == modulePath {
+			return c, true
+		}
+	}
+	if !qualified && len(candidates) > 0 {
+		return candidates[0], true
+	}
+	return Symbol{}, false
+}
+
+// goInterfaceMembers splits an interface signature built by the Go parser
+// ("type X interface { Read(p []byte) (int, error); io.Closer }") into its
+// method names and embedded type names.
+func goInterfaceMembers(signature string) (methods, embedded []string) {
+	_, body, ok := strings.Cut(signature, " interface { ")
+	if !ok {
+		return nil, nil
+	}
+	body = strings.TrimSuffix(body, " }")
+	for _, member := range strings.Split(body, "; ") {
+		if name, _, isMethod := strings.Cut(member, "("); isMethod {
+			methods = append(methods, name)
+		} else if member != "?" {
+			embedded = append(embedded, member)
+		}
+	}
+	return methods, embedded
+}
+
+// goReceiverTypeName returns the receiver type name of a method signature built
+// by the Go parser ("func (s *Server) Start(...)" gives "Server"), or "" when
+// the receiver cannot be read.
+func goReceiverTypeName(signature, method string) string {
+	recv, ok := strings.CutPrefix(signature, "func (")
+	if !ok {
+		return ""
+	}
+	recv, _, ok = strings.Cut(recv, ") "+method+"(")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(recv)
+	if len(fields) == 0 {
+		return ""
+	}
+	name := strings.TrimPrefix(fields[len(fields)-1], "*")
+	if name == "?" {
+		return "" // Generic receiver
+	}
+	return name
+}
+
+// hasAllMethods reports whether have contains every name in want.
+func hasAllMethods(have, want map[string]bool) bool {
+	if len(have) < len(want) {
+		return false
+	}
+	for name := range want {
+		if !have[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -15,6 +16,7 @@ import (
 	"github.com/josephgoksu/TaskWing/internal/codeintel/parser"
 	"github.com/josephgoksu/TaskWing/internal/knowledge"
 	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/utils"
 )
 
 // IndexerConfig holds configuration for the indexer.
@@ -31,9 +33,16 @@ type IndexerConfig struct {
 	// ExcludePatterns are glob patterns for directories to skip.
 	ExcludePatterns []string
 
+	// ExcludeFilePatterns are glob patterns for files to skip (e.g. generated code).
+	ExcludeFilePatterns []string
+
 	// IncludeTests controls whether test files are indexed.
 	IncludeTests bool
 
+	// GitBlame dates symbols by the last commit touching their lines. It runs
+	// git blame once per file, which is slow on large repositories.
+	GitBlame bool
+
 	// BatchSize is the number of symbols to insert in a single transaction.
 	BatchSize int
 
@@ -53,7 +62,8 @@ func DefaultIndexerConfig() IndexerConfig {
 			".taskwing",
 			"testdata",
 		},
-		IncludeTests: false,
+		ExcludeFilePatterns: append([]string(nil), utils.GeneratedFilePatterns...),
+		IncludeTests:        false,
 	}
 }
 
@@ -62,6 +72,7 @@ type Indexer struct {
 	repo     Repository
 	config   IndexerConfig
 	registry *parser.ParserRegistry
+	gitBlame bool // GitBlame is enabled and the root is a git work tree
 }
 
 // NewIndexer creates a new indexer with the given repository and config.
@@ -88,6 +99,7 @@ type parseResult struct {
 	path      string
 	symbols   []Symbol
 	relations []SymbolRelation
+	imports   []FileImport
 	err       error
 }
 
@@ -99,6 +111,7 @@ func (idx *Indexer) IndexDirectory(ctx context.Context, rootPath string) (*Index
 
 	// Create parser registry with all language parsers
 	idx.registry = parser.NewDefaultRegistry(rootPath)
+	idx.gitBlame = idx.config.GitBlame && isGitWorkTree(rootPath)
 
 	// Find all supported source files
 	files, err := idx.findSupportedFiles(rootPath)
@@ -147,7 +160,6 @@ func (idx *Indexer) IndexDirectory(ctx context.Context, rootPath string) (*Index
 		symbolStart int // Starting index in allSymbols for this file's symbols
 	}
 	allRelations := make([]relationWithContext, 0)
-	symbolMap := make(map[string]uint32) // key -> symbol ID
 
 	var filesIndexed, filesSkipped int32
 	var parseErrors []string
@@ -160,6 +172,9 @@ func (idx *Indexer) IndexDirectory(ctx context.Context, rootPath string) (*Index
 		}
 
 		atomic.AddInt32(&filesIndexed, 1)
+		if err := idx.storeImports(ctx, rootPath, result); err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("store imports for %s: %v", result.path, err))
+		}
 		// Track the starting index before appending this file's symbols
 		symbolStart := len(allSymbols)
 		allSymbols = append(allSymbols, result.symbols...)
@@ -186,49 +201,43 @@ func (idx *Indexer) IndexDirectory(ctx context.Context, rootPath string) (*Index
 		}
 		allSymbols[i].ID = id
 		stats.SymbolsFound++
-
-		// Build symbol key for relation resolution
-		key := buildSymbolKeyForIndexer(allSymbols[i].ModulePath, allSymbols[i].Name, allSymbols[i].Kind)
-		symbolMap[key] = id
 	}
 
 	// Resolve and insert relations
+	targets := newRelationTargets(allSymbols)
 	for _, relCtx := range allRelations {
 		rel := &relCtx.relation
 
+		// FromSymbolID from parser is a FILE-LOCAL index
+		// We need to add the file's starting offset to get the global index
+		globalIdx := relCtx.symbolStart + int(rel.FromSymbolID)
+		if globalIdx >= len(allSymbols) || allSymbols[globalIdx].ID == 0 {
+			continue
+		}
+		from := allSymbols[globalIdx]
+
 		// Try to resolve target symbol from metadata
-		if meta := rel.Metadata; meta != nil {
-			if calleeName, ok := meta["calleeName"].(string); ok {
-				// Only match callable symbols (functions and methods)
-				// This prevents linking to fields, variables, or structs with the same name
-				for symKey, symID := range symbolMap {
-					// Key format is "modulePath:name:kind"
-					// Only match function or method kinds for call relations
-					if strings.HasSuffix(symKey, ":"+calleeName+":function") ||
-						strings.HasSuffix(symKey, ":"+calleeName+":method") {
-						rel.ToSymbolID = symID
-						break
-					}
-				}
-			}
+		if id := targets.resolve(from, rel.Metadata); id > 0 {
+			rel.ToSymbolID = id
 		}
 
-		// Only insert if we have valid source and resolved target
+		// Only insert if we have a resolved target
 		if rel.ToSymbolID > 0 {
-			// FromSymbolID from parser is a FILE-LOCAL index
-			// We need to add the file's starting offset to get the global index
-			globalIdx := relCtx.symbolStart + int(rel.FromSymbolID)
-			if globalIdx < len(allSymbols) {
-				rel.FromSymbolID = allSymbols[globalIdx].ID
-				if err := idx.repo.UpsertRelation(ctx, rel); err != nil {
-					stats.Errors = append(stats.Errors, fmt.Sprintf("insert relation: %v", err))
-					continue
-				}
-				stats.RelationsFound++
+			rel.FromSymbolID = from.ID
+			if err := idx.repo.UpsertRelation(ctx, rel); err != nil {
+				stats.Errors = append(stats.Errors, fmt.Sprintf("insert relation: %v", err))
+				continue
 			}
+			stats.RelationsFound++
 		}
 	}
 
+	if linked, err := idx.linkGoImplementations(ctx); err != nil {
+		stats.Errors = append(stats.Errors, fmt.Sprintf("link implementations: %v", err))
+	} else {
+		stats.RelationsFound += linked
+	}
+
 	// Generate embeddings if enabled
 	if idx.config.GenerateEmbeddings {
 		embeddingsGenerated, embeddingErrors := idx.generateEmbeddings(ctx, allSymbols)
@@ -279,34 +288,42 @@ func (idx *Indexer) worker(ctx context.Context, jobs <-chan fileJob, results cha
 			symbols := convertSymbols(result.Symbols)
 			relations := convertRelations(result.Relations)
 
+			// Best-effort: symbols of untracked files keep a zero LastModified
+			if idx.gitBlame {
+				if lineTimes, err := blameLineTimes(job.path); err == nil {
+					applyBlameTimes(symbols, lineTimes)
+				}
+			}
+
 			results <- parseResult{
 				path:      job.path,
 				symbols:   symbols,
 				relations: relations,
+				imports:   convertImports(result.Imports),
 			}
 		}()
 	}
 }
 
-// convertSymbols converts parser.Symbol to codeintel.Symbol
+// convertSymbols converts parser.Symbol to codeintel.Symbol. LastModified is
+// left zero: it is set from git blame only, never from the parse time.
 func convertSymbols(parserSymbols []parser.Symbol) []Symbol {
 	symbols := make([]Symbol, len(parserSymbols))
 	for i, ps := range parserSymbols {
 		symbols[i] = Symbol{
-			ID:           ps.ID,
-			Name:         ps.Name,
-			Kind:         SymbolKind(ps.Kind),
-			FilePath:     ps.FilePath,
-			StartLine:    ps.StartLine,
-			EndLine:      ps.EndLine,
-			Signature:    ps.Signature,
-			DocComment:   ps.DocComment,
-			ModulePath:   ps.ModulePath,
-			Visibility:   ps.Visibility,
-			Language:     ps.Language,
-			FileHash:     ps.FileHash,
-			Embedding:    ps.Embedding,
-			LastModified: ps.LastModified,
+			ID:         ps.ID,
+			Name:       ps.Name,
+			Kind:       SymbolKind(ps.Kind),
+			FilePath:   ps.FilePath,
+			StartLine:  ps.StartLine,
+			EndLine:    ps.EndLine,
+			Signature:  ps.Signature,
+			DocComment: ps.DocComment,
+			ModulePath: ps.ModulePath,
+			Visibility: ps.Visibility,
+			Language:   ps.Language,
+			FileHash:   ps.FileHash,
+			Embedding:  ps.Embedding,
 		}
 	}
 	return symbols
@@ -327,6 +344,29 @@ func convertRelations(parserRelations []parser.SymbolRelation) []SymbolRelation
 	return relations
 }
 
+// convertImports converts parser.Import to codeintel.FileImport
+func convertImports(parserImports []parser.Import) []FileImport {
+	imports := make([]FileImport, len(parserImports))
+	for i, pi := range parserImports {
+		imports[i] = FileImport{
+			FilePath: pi.FilePath,
+			Path:     pi.Path,
+			Category: ImportCategory(pi.Category),
+			Line:     pi.Line,
+		}
+	}
+	return imports
+}
+
+// storeImports records the imports of a parsed file, replacing any from a previous index.
+func (idx *Indexer) storeImports(ctx context.Context, rootPath string, result parseResult) error {
+	relPath, err := filepath.Rel(rootPath, result.path)
+	if err != nil {
+		return err
+	}
+	return idx.repo.ReplaceFileImports(ctx, relPath, result.imports)
+}
+
 // findSupportedFiles walks the directory and returns all supported source files to index.
 // Supports: Go (.go), TypeScript (.ts, .tsx), JavaScript (.js, .jsx, .mjs, .cjs),
 // Python (.py), and Rust (.rs) files.
@@ -362,6 +402,11 @@ func (idx *Indexer) findSupportedFiles(rootPath string) ([]string, error) {
 			return nil
 		}
 
+		// Skip generated and other excluded files
+		if relPath, relErr := filepath.Rel(rootPath, path); relErr == nil && utils.MatchesFilePattern(relPath, idx.config.ExcludeFilePatterns) {
+			return nil
+		}
+
 		// Skip test files unless configured to include them
 		if !idx.config.IncludeTests {
 			fileName := info.Name()
@@ -406,16 +451,7 @@ func (idx *Indexer) generateEmbeddings(ctx context.Context, symbols []Symbol) (i
 			continue
 		}
 
-		// Build text for embedding: name + signature + doc
-		text := sym.Name
-		if sym.Signature != "" {
-			text += " " + sym.Signature
-		}
-		if sym.DocComment != "" {
-			text += " " + sym.DocComment
-		}
-
-		embedding, err := knowledge.GenerateEmbedding(ctx, text, idx.config.LLMConfig)
+		embedding, err := knowledge.GenerateEmbedding(ctx, symbolEmbeddingText(sym), idx.config.LLMConfig)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("embedding for %s: %v", sym.Name, err))
 			continue
@@ -440,6 +476,7 @@ func (idx *Indexer) IncrementalIndex(ctx context.Context, rootPath string) (*Ind
 
 	// Create parser registry with all language parsers
 	idx.registry = parser.NewDefaultRegistry(rootPath)
+	idx.gitBlame = idx.config.GitBlame && isGitWorkTree(rootPath)
 
 	// Find all supported source files
 	allFiles, err := idx.findSupportedFiles(rootPath)
@@ -511,7 +548,6 @@ func (idx *Indexer) IncrementalIndex(ctx context.Context, rootPath string) (*Ind
 	// C1 FIX: Collect all symbols and relations first, then insert with proper ID mapping
 	allSymbols := make([]Symbol, 0)
 	allRelations := make([]SymbolRelation, 0)
-	symbolMap := make(map[string]uint32) // key -> symbol ID
 
 	// Collect results
 	for result := range results {
@@ -522,6 +558,9 @@ func (idx *Indexer) IncrementalIndex(ctx context.Context, rootPath string) (*Ind
 		}
 
 		stats.FilesIndexed++
+		if err := idx.storeImports(ctx, rootPath, result); err != nil {
+			stats.Errors = append(stats.Errors, fmt.Sprintf("store imports for %s: %v", result.path, err))
+		}
 		allSymbols = append(allSymbols, result.symbols...)
 		allRelations = append(allRelations, result.relations...)
 	}
@@ -535,41 +574,39 @@ func (idx *Indexer) IncrementalIndex(ctx context.Context, rootPath string) (*Ind
 		}
 		allSymbols[i].ID = id
 		stats.SymbolsFound++
-
-		// Build symbol key for relation resolution
-		key := buildSymbolKeyForIndexer(allSymbols[i].ModulePath, allSymbols[i].Name, allSymbols[i].Kind)
-		symbolMap[key] = id
 	}
 
 	// C1 FIX: Resolve and insert relations (was completely missing before!)
+	targets := newRelationTargets(allSymbols)
 	for _, rel := range allRelations {
+		// FromSymbolID from parser is an index, need to map to actual ID
+		if int(rel.FromSymbolID) >= len(allSymbols) || allSymbols[rel.FromSymbolID].ID == 0 {
+			continue
+		}
+		from := allSymbols[rel.FromSymbolID]
+
 		// Try to resolve target symbol from metadata
-		if meta := rel.Metadata; meta != nil {
-			if calleeName, ok := meta["calleeName"].(string); ok {
-				// Look for target in any module
-				for symKey, symID := range symbolMap {
-					if strings.HasSuffix(symKey, ":"+calleeName) {
-						rel.ToSymbolID = symID
-						break
-					}
-				}
-			}
+		if id := targets.resolve(from, rel.Metadata); id > 0 {
+			rel.ToSymbolID = id
 		}
 
-		// Only insert if we have valid source and resolved target
+		// Only insert if we have a resolved target
 		if rel.ToSymbolID > 0 {
-			// FromSymbolID from parser is an index, need to map to actual ID
-			if int(rel.FromSymbolID) < len(allSymbols) {
-				rel.FromSymbolID = allSymbols[rel.FromSymbolID].ID
-				if err := idx.repo.UpsertRelation(ctx, &rel); err != nil {
-					stats.Errors = append(stats.Errors, fmt.Sprintf("insert relation: %v", err))
-					continue
-				}
-				stats.RelationsFound++
+			rel.FromSymbolID = from.ID
+			if err := idx.repo.UpsertRelation(ctx, &rel); err != nil {
+				stats.Errors = append(stats.Errors, fmt.Sprintf("insert relation: %v", err))
+				continue
 			}
+			stats.RelationsFound++
 		}
 	}
 
+	if linked, err := idx.linkGoImplementations(ctx); err != nil {
+		stats.Errors = append(stats.Errors, fmt.Sprintf("link implementations: %v", err))
+	} else {
+		stats.RelationsFound += linked
+	}
+
 	stats.Duration = time.Since(start)
 	return stats, nil
 }
@@ -637,7 +674,89 @@ func (idx *Indexer) PruneStaleFiles(ctx context.Context) (int, error) {
 	return count, nil
 }
 
-// buildSymbolKeyForIndexer creates a unique key for symbol lookup.
-func buildSymbolKeyForIndexer(modulePath, name string, kind SymbolKind) string {
-	return fmt.Sprintf("%s:%s:%s", modulePath, kind, name)
+// relationTargets resolves the targets of parser relations against the
+// symbols stored in one indexing run. Symbols sharing a name are kept in
+// module path then ID order, so resolution never depends on map iteration.
+type relationTargets struct {
+	byName map[string][]Symbol
+}
+
+func newRelationTargets(symbols []Symbol) *relationTargets {
+	t := &relationTargets{byName: make(map[string][]Symbol)}
+	for _, s := range symbols {
+		if s.ID != 0 {
+			t.byName[s.Name] = append(t.byName[s.Name], s)
+		}
+	}
+	for _, candidates := range t.byName {
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].ModulePath != candidates[j].ModulePath {
+				return candidates[i].ModulePath < candidates[j].ModulePath
+			}
+			return candidates[i].ID < candidates[j].ID
+		})
+	}
+	return t
+}
+
+// resolve finds the symbol a parser relation from the symbol from points at,
+// using the relation's metadata. Calls ("calleeName") only match functions
+// and methods, and type references ("typeName") only match type
+// declarations, so neither links to a field or variable that happens to share
+// the name. Returns 0 if unresolved.
+func (t *relationTargets) resolve(from Symbol, meta map[string]any) uint32 {
+	if calleeName, ok := meta["calleeName"].(string); ok {
+		calleePkg, _ := meta["calleePkg"].(string)
+		return t.resolveCall(from, calleeName, calleePkg)
+	}
+	if typeName, ok := meta["typeName"].(string); ok {
+		return t.resolveType(from, typeName)
+	}
+	return 0
+}
+
+// resolveCall resolves a call to name. When the parser knew the callee's
+// import path (calleePkg) only a function or method in that package matches;
+// otherwise the caller's own package is preferred over the first candidate.
+func (t *relationTargets) resolveCall(from Symbol, name, calleePkg string) uint32 {
+	var fallback uint32
+	for _, c := range t.byName[name] {
+		if c.Kind != SymbolFunction && c.Kind != SymbolMethod {
+			continue
+		}
+		switch {
+		case calleePkg != "":
+			if c.ModulePath != "" && (calleePkg == c.ModulePath || strings.HasSuffix(calleePkg, "/"+c.ModulePath)) {
+				return c.ID
+			}
+		case c.ModulePath == from.ModulePath:
+			return c.ID
+		case fallback == 0:
+			fallback = c.ID
+		}
+	}
+	return fallback
+}
+
+// resolveType resolves a type reference. A qualified name ("store.Config")
+// matches a type in a package whose module path ends in that package name;
+// an unqualified one only matches the referencing symbol's own package, as
+// in Go. Among several matching packages the first in module path order wins.
+func (t *relationTargets) resolveType(from Symbol, name string) uint32 {
+	pkg, base, qualified := strings.Cut(name, ".")
+	if !qualified {
+		base = name
+	}
+	for _, c := range t.byName[base] {
+		if c.Kind != SymbolStruct && c.Kind != SymbolInterface && c.Kind != SymbolType {
+			continue
+		}
+		if qualified && (c.ModulePath == pkg || strings.HasSuffix(c.ModulePath, "/"+pkg)) {
+			return c.ID
+		}
+		if !qualified && c.ModulePath == from.ModulePath {
+			return c.ID
+		}
+	}
+	return 0
 }
@@ -35,7 +35,7 @@ type Symbol struct {
 	Language     string     `json:"language"`             // go, typescript, python, etc.
 	FileHash     string     `json:"fileHash,omitempty"`   // SHA256 of file for incremental updates
 	Embedding    []float32  `json:"embedding,omitempty"`  // Semantic vector for similarity search
-	LastModified time.Time  `json:"lastModified"`         // When the symbol was last indexed
+	LastModified time.Time  `json:"lastModified"`         // Last commit touching the symbol (git blame); zero when unknown
 }
 
 // SymbolRelation represents a relationship between two symbols.
@@ -61,6 +61,23 @@ const (
 	RelationReferences RelationType = "references" // Symbol A references symbol B
 )
 
+// ImportCategory classifies an import relative to the indexed project.
+type ImportCategory string
+
+const (
+	ImportInternal   ImportCategory = "internal"    // Package within the project
+	ImportStdlib     ImportCategory = "stdlib"      // Language standard library
+	ImportThirdParty ImportCategory = "third_party" // External dependency
+)
+
+// FileImport is an import declared by a source file, categorized at index time.
+type FileImport struct {
+	FilePath string         `json:"filePath"`
+	Path     string         `json:"path"`
+	Category ImportCategory `json:"category"`
+	Line     int            `json:"line,omitempty"`
+}
+
 // IndexStats holds statistics from an indexing operation.
 type IndexStats struct {
 	FilesScanned   int           `json:"filesScanned"`
@@ -78,6 +95,20 @@ type SymbolSearchResult struct {
 	Symbol Symbol  `json:"symbol"`
 	Score  float32 `json:"score"`  // Combined FTS + vector score
 	Source string  `json:"source"` // "fts", "vector", or "hybrid"
+
+	// MatchReason breaks Score down into its components. Only set when the
+	// search was run with SearchOptions.Debug.
+	MatchReason *MatchReason `json:"matchReason,omitempty"`
+}
+
+// MatchReason explains why a search result matched and how it was scored.
+// Score = FTSScore*FTSWeight + VectorScore*VectorWeight.
+type MatchReason struct {
+	Via          string  `json:"via"`          // "fts", "vector", or "hybrid"
+	FTSScore     float32 `json:"ftsScore"`     // Rank-based FTS5 score before weighting (0 if not an FTS hit)
+	VectorScore  float32 `json:"vectorScore"`  // Cosine similarity before weighting (0 if not a vector hit)
+	FTSWeight    float32 `json:"ftsWeight"`    // Weight applied to FTSScore
+	VectorWeight float32 `json:"vectorWeight"` // Weight applied to VectorScore
 }
 
 // ImpactNode represents a node in the impact analysis graph.
@@ -87,6 +118,19 @@ type ImpactNode struct {
 	Relation string `json:"relation"` // How it's related (calls, implements, etc.)
 }
 
+// SymbolUsage is a symbol that uses another, with the kind of use.
+type SymbolUsage struct {
+	Symbol   Symbol       `json:"symbol"`
+	Relation RelationType `json:"relation"`       // calls, references, implements, etc.
+	Line     int          `json:"line,omitempty"` // Line of the use site, if known
+}
+
+// SymbolFanIn is a symbol with the number of distinct symbols that call it.
+type SymbolFanIn struct {
+	Symbol      Symbol `json:"symbol"`
+	CallerCount int    `json:"callerCount"`
+}
+
 // SymbolStats holds symbol index statistics for health checks.
 type SymbolStats struct {
 	TotalSymbols   int            `json:"totalSymbols"`
@@ -73,10 +73,28 @@ type SymbolRelation struct {
 	Metadata     map[string]any `json:"metadata,omitempty"`
 }
 
+// ImportCategory classifies an import relative to the indexed project.
+type ImportCategory string
+
+const (
+	ImportInternal   ImportCategory = "internal"    // Package within the project
+	ImportStdlib     ImportCategory = "stdlib"      // Language standard library
+	ImportThirdParty ImportCategory = "third_party" // External dependency
+)
+
+// Import is an import declaration extracted from a source file.
+type Import struct {
+	FilePath string         `json:"filePath"`
+	Path     string         `json:"path"`
+	Category ImportCategory `json:"category"`
+	Line     int            `json:"line"`
+}
+
 // GoParser extracts symbols and relationships from Go source files.
 type GoParser struct {
 	fset     *token.FileSet
 	basePath string // Root path for relative file paths
+	module   string // Module path from go.mod, used to classify imports
 }
 
 // NewGoParser creates a new Go parser instance.
@@ -84,6 +102,7 @@ func NewGoParser(basePath string) *GoParser {
 	return &GoParser{
 		fset:     token.NewFileSet(),
 		basePath: basePath,
+		module:   readGoModulePath(basePath),
 	}
 }
 
@@ -108,6 +127,7 @@ func (p *GoParser) CanParse(filePath string) bool {
 type ParseResult struct {
 	Symbols   []Symbol
 	Relations []SymbolRelation
+	Imports   []Import
 	Errors    []error
 }
 
@@ -135,6 +155,16 @@ func (p *GoParser) ParseFile(filePath string) (*ParseResult, error) {
 	// Extract package-level symbols
 	p.extractSymbols(file, relPath, fileHash, modulePath, result)
 
+	for _, spec := range file.Imports {
+		path := strings.Trim(spec.Path.Value, "`\"")
+		result.Imports = append(result.Imports, Import{
+			FilePath: relPath,
+			Path:     path,
+			Category: classifyGoImport(path, p.module),
+			Line:     p.fset.Position(spec.Pos()).Line,
+		})
+	}
+
 	return result, nil
 }
 
@@ -172,6 +202,7 @@ func (p *GoParser) ParseDirectory(dirPath string) (*ParseResult, error) {
 
 		combined.Symbols = append(combined.Symbols, result.Symbols...)
 		combined.Relations = append(combined.Relations, result.Relations...)
+		combined.Imports = append(combined.Imports, result.Imports...)
 		return nil
 	})
 
@@ -320,8 +351,13 @@ func (p *GoParser) extractTypeSpec(s *ast.TypeSpec, d *ast.GenDecl, filePath, fi
 		kind = SymbolInterface
 		sig = buildInterfaceSignature(s.Name.Name, t)
 	default:
+		// Aliases and defined types share a kind; the signature tells them apart
 		kind = SymbolType
-		sig = fmt.Sprintf("type %s", s.Name.Name)
+		if s.Assign.IsValid() {
+			sig = fmt.Sprintf("type %s = %s", s.Name.Name, exprToString(s.Type))
+		} else {
+			sig = fmt.Sprintf("type %s %s", s.Name.Name, exprToString(s.Type))
+		}
 	}
 
 	doc := extractDocComment(s.Doc)
@@ -343,6 +379,39 @@ func (p *GoParser) extractTypeSpec(s *ast.TypeSpec, d *ast.GenDecl, filePath, fi
 		FileHash:     fileHash,
 		LastModified: now,
 	})
+
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		p.extractEmbeddedTypes(t.Fields, len(result.Symbols)-1, result)
+	case *ast.InterfaceType:
+		p.extractEmbeddedTypes(t.Methods, len(result.Symbols)-1, result)
+	}
+}
+
+// extractEmbeddedTypes records an extends relation from the type at fromIdx to
+// each type embedded in its struct fields or interface methods. Targets are
+// resolved during indexing.
+func (p *GoParser) extractEmbeddedTypes(fields *ast.FieldList, fromIdx int, result *ParseResult) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		if len(field.Names) > 0 {
+			continue // Named field or interface method
+		}
+		names := referencedTypeNames(field.Type)
+		if len(names) == 0 {
+			continue // Type constraint such as ~int | ~string
+		}
+		result.Relations = append(result.Relations, SymbolRelation{
+			FromSymbolID: uint32(fromIdx), // Temporary index
+			RelationType: RelationExtends,
+			CallSiteLine: p.fset.Position(field.Pos()).Line,
+			Metadata: map[string]any{
+				"typeName": names[0],
+			},
+		})
+	}
 }
 
 // extractStructFields extracts field declarations from a struct.
@@ -367,8 +436,53 @@ func (p *GoParser) extractStructFields(t *ast.StructType, structName, filePath,
 				FileHash:     fileHash,
 				LastModified: now,
 			})
+			p.extractTypeReferences(field.Type, len(result.Symbols)-1, result)
+		}
+	}
+}
+
+// extractTypeReferences records a references relation from the symbol at
+// fromIdx to each named type in expr. Targets are resolved during indexing.
+func (p *GoParser) extractTypeReferences(expr ast.Expr, fromIdx int, result *ParseResult) {
+	line := p.fset.Position(expr.Pos()).Line
+	for _, name := range referencedTypeNames(expr) {
+		result.Relations = append(result.Relations, SymbolRelation{
+			FromSymbolID: uint32(fromIdx), // Temporary index
+			RelationType: RelationReferences,
+			CallSiteLine: line,
+			Metadata: map[string]any{
+				"typeName": name,
+			},
+		})
+	}
+}
+
+// referencedTypeNames returns the named types in a type expression, unwrapping
+// pointers, slices, maps and channels. Types from other packages keep their
+// package qualifier ("io.Reader").
+func referencedTypeNames(expr ast.Expr) []string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return []string{t.Name}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			return []string{pkg.Name + "." + t.Sel.Name}
 		}
+		return []string{t.Sel.Name}
+	case *ast.StarExpr:
+		return referencedTypeNames(t.X)
+	case *ast.ArrayType:
+		return referencedTypeNames(t.Elt)
+	case *ast.Ellipsis:
+		return referencedTypeNames(t.Elt)
+	case *ast.ChanType:
+		return referencedTypeNames(t.Value)
+	case *ast.MapType:
+		return append(referencedTypeNames(t.Key), referencedTypeNames(t.Value)...)
+	case *ast.IndexExpr:
+		return referencedTypeNames(t.X)
 	}
+	return nil
 }
 
 // extractValueSpec extracts const and var declarations.
@@ -383,10 +497,16 @@ func (p *GoParser) extractValueSpec(s *ast.ValueSpec, d *ast.GenDecl, filePath,
 		doc = parentDoc
 	}
 
-	for _, name := range s.Names {
-		var sig string
+	for i, name := range s.Names {
+		sig := d.Tok.String() + " " + name.Name
 		if s.Type != nil {
-			sig = exprToString(s.Type)
+			sig += " " + exprToString(s.Type)
+		}
+		// Simple values (literals, identifiers) make config-like constants searchable
+		if len(s.Values) == len(s.Names) {
+			if v := exprToString(s.Values[i]); v != "?" {
+				sig += " = " + v
+			}
 		}
 
 		result.Symbols = append(result.Symbols, Symbol{
@@ -620,6 +740,38 @@ func (p *GoParser) relativePath(absPath string) string {
 	return rel
 }
 
+// readGoModulePath returns the module path declared in basePath/go.mod, or ""
+// when there is no go.mod.
+func readGoModulePath(basePath string) string {
+	if basePath == "" {
+		return ""
+	}
+	content, err := os.ReadFile(filepath.Join(basePath, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), "\"")
+		}
+	}
+	return ""
+}
+
+// classifyGoImport categorizes an import path. Paths under the project module
+// are internal; standard library paths have no dot in their first element.
+func classifyGoImport(path, module string) ImportCategory {
+	if module != "" && (path == module || strings.HasPrefix(path, module+"/")) {
+		return ImportInternal
+	}
+	first, _, _ := strings.Cut(path, "/")
+	if !strings.Contains(first, ".") {
+		return ImportStdlib
+	}
+	return ImportThirdParty
+}
+
 func extractModulePath(filePath string) string {
 	dir := filepath.Dir(filePath)
 	if dir == "." || dir == "" {
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGoParser_EmbeddedTypesExtend(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "store.go")
+	src := `package store
+
+import "io"
+
+type Reader interface {
+	Read(key string) ([]byte, error)
+}
+
+type ReadCloser interface {
+	Reader
+	io.Closer
+}
+
+type base struct{ name string }
+
+type Store struct {
+	*base
+	path string
+}
+`
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewGoParser(dir).ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	got := make(map[string][]string) // embedding type -> embedded type names
+	for _, rel := range result.Relations {
+		if rel.RelationType != RelationExtends {
+			continue
+		}
+		from := result.Symbols[rel.FromSymbolID].Name
+		got[from] = append(got[from], rel.Metadata["typeName"].(string))
+	}
+	want := map[string][]string{
+		"ReadCloser": {"Reader", "io.Closer"},
+		"Store":      {"base"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("extends relations = %v, want %v", got, want)
+	}
+	for from, names := range want {
+		if len(got[from]) != len(names) {
+			t.Fatalf("%s extends %v, want %v", from, got[from], names)
+		}
+		for i, name := range names {
+			if got[from][i] != name {
+				t.Errorf("%s extends %v, want %v", from, got[from], names)
+			}
+		}
+	}
+}
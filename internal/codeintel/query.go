@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"path/filepath"
 	"sort"
+	"strings"
 
-	"github.com/josephgoksu/TaskWing/internal/knowledge"
 	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/utils"
 )
 
+// DefaultFanInThreshold is the caller count above which a symbol is flagged
+// as a high fan-in refactor target.
+const DefaultFanInThreshold = 10
+
 // QueryConfig holds configuration for the query service.
 type QueryConfig struct {
 	// FTSWeight is the weight for FTS5 keyword matches (default 0.3).
@@ -29,26 +35,63 @@ type QueryConfig struct {
 
 	// MaxImpactDepth is the maximum depth for impact analysis (default 5).
 	MaxImpactDepth int
+
+	// MaxImplementationDepth is the default and the upper bound for the number
+	// of interface-embedding hops followed by transitive implementation lookups
+	// (default 3).
+	MaxImplementationDepth int
+
+	// ImpactExcludePatterns lists file globs whose symbols are left out of impact
+	// analysis, both from the counts and from further traversal (default: none).
+	// Patterns ending in "/" match a directory anywhere in the path.
+	// See DefaultImpactExcludePatterns for vendored and generated code.
+	ImpactExcludePatterns []string
+}
+
+// DefaultImpactExcludePatterns returns patterns for vendored, third-party, and generated code.
+func DefaultImpactExcludePatterns() []string {
+	patterns := []string{"vendor/", "node_modules/", "third_party/"}
+	return append(patterns, utils.GeneratedFilePatterns...)
+}
+
+// matchesImpactExclude reports whether a symbol file path matches any exclude pattern.
+func matchesImpactExclude(path string, patterns []string) bool {
+	slashPath := "/" + filepath.ToSlash(path)
+	var globs []string
+	for _, pattern := range patterns {
+		if dir, ok := strings.CutSuffix(pattern, "/"); ok && !strings.ContainsAny(dir, "*?[") {
+			if strings.Contains(slashPath, "/"+dir+"/") {
+				return true
+			}
+			continue
+		}
+		globs = append(globs, pattern)
+	}
+	return utils.MatchesFilePattern(path, globs)
 }
 
 // DefaultQueryConfig returns sensible defaults for query configuration.
 func DefaultQueryConfig() QueryConfig {
 	return QueryConfig{
-		FTSWeight:          0.3,
-		VectorWeight:       0.7,
-		VectorThreshold:    0.5,
-		MinResultThreshold: 0.1,
-		DefaultLimit:       20,
-		MaxImpactDepth:     5,
+		FTSWeight:              0.3,
+		VectorWeight:           0.7,
+		VectorThreshold:        0.5,
+		MinResultThreshold:     0.1,
+		DefaultLimit:           20,
+		MaxImpactDepth:         5,
+		MaxImplementationDepth: 3,
 	}
 }
 
 // QueryService provides hybrid search and impact analysis for code symbols.
 // It combines FTS5 lexical search with vector similarity for best results.
 type QueryService struct {
-	repo   Repository
-	llmCfg llm.Config
-	config QueryConfig
+	repo    Repository
+	llmCfg  llm.Config
+	config  QueryConfig
+	workDir string // Repository root for git-based queries ("" = current directory)
+
+	embedFunc EmbedFunc // nil = knowledge.GenerateEmbedding with llmCfg
 }
 
 // NewQueryService creates a new query service with default configuration.
@@ -69,6 +112,16 @@ func NewQueryServiceWithConfig(repo Repository, llmCfg llm.Config, config QueryC
 	}
 }
 
+// SetImpactExcludePatterns sets the file globs excluded from impact analysis.
+func (qs *QueryService) SetImpactExcludePatterns(patterns []string) {
+	qs.config.ImpactExcludePatterns = patterns
+}
+
+// SetWorkDir sets the repository root used by git-based queries such as SymbolsChangedBetween.
+func (qs *QueryService) SetWorkDir(dir string) {
+	qs.workDir = dir
+}
+
 // HybridSearch performs a combined FTS5 and vector similarity search.
 // Returns ranked results combining text match (FTS5) and semantic meaning (embeddings).
 //
@@ -77,6 +130,40 @@ func NewQueryServiceWithConfig(repo Repository, llmCfg llm.Config, config QueryC
 // 2. Semantically similar code is found even with different naming (via vectors)
 // 3. Combined scoring balances precision and recall
 func (qs *QueryService) HybridSearch(ctx context.Context, query string, limit int) ([]SymbolSearchResult, error) {
+	return qs.HybridSearchWithOptions(ctx, query, limit, SearchOptions{})
+}
+
+// SearchOptions narrows the symbols a hybrid search may return.
+type SearchOptions struct {
+	// ExcludePrivate drops unexported symbols, for API-surface work.
+	ExcludePrivate bool
+
+	// Kind keeps only symbols of that kind. Empty keeps every kind.
+	Kind SymbolKind
+
+	// FilePath keeps only symbols declared in that file. Empty keeps every file.
+	FilePath string
+
+	// MinScore drops results whose combined score is below it. Zero falls
+	// back to QueryConfig.MinResultThreshold.
+	MinScore float32
+
+	// Debug attaches a MatchReason with the component scores to each result.
+	Debug bool
+
+	// Language keeps only symbols of that language (e.g. "go", "typescript"),
+	// compared case-insensitively. Empty keeps every language.
+	Language string
+}
+
+// symbolFilter returns the options the repository applies in its queries.
+func (o SearchOptions) symbolFilter() SymbolFilter {
+	return SymbolFilter{ExcludePrivate: o.ExcludePrivate, Kind: o.Kind, FilePath: o.FilePath, Language: o.Language}
+}
+
+// HybridSearchWithOptions is HybridSearch with result filtering. Filtered
+// symbols are dropped before the limit is applied.
+func (qs *QueryService) HybridSearchWithOptions(ctx context.Context, query string, limit int, opts SearchOptions) ([]SymbolSearchResult, error) {
 	if limit <= 0 {
 		limit = qs.config.DefaultLimit
 	}
@@ -85,9 +172,12 @@ func (qs *QueryService) HybridSearch(ctx context.Context, query string, limit in
 	scoreByID := make(map[uint32]float32)
 	symbolByID := make(map[uint32]*Symbol)
 	sourceByID := make(map[uint32]string)
+	ftsScoreByID := make(map[uint32]float32)
+	vectorScoreByID := make(map[uint32]float32)
 
 	// 1. FTS5 keyword search (fast, no API call)
-	ftsResults, err := qs.repo.SearchSymbolsFTS(ctx, query, limit*2)
+	filter := opts.symbolFilter()
+	ftsResults, err := qs.repo.SearchSymbolsFTS(ctx, query, limit*2, filter)
 	if err != nil {
 		// FTS errors are non-fatal - vector search can still work
 		// This allows graceful degradation if FTS5 index is unavailable
@@ -98,19 +188,20 @@ func (qs *QueryService) HybridSearch(ctx context.Context, query string, limit in
 			// based on position (first result gets highest score)
 			ftsScore := float32(1.0) - float32(i)/float32(len(ftsResults)+1)
 			scoreByID[sym.ID] = ftsScore * qs.config.FTSWeight
+			ftsScoreByID[sym.ID] = ftsScore
 			symbolByID[sym.ID] = sym
 			sourceByID[sym.ID] = "fts"
 		}
 	}
 
 	// 2. Vector similarity search (semantic)
-	queryEmbedding, embErr := knowledge.GenerateEmbedding(ctx, query, qs.llmCfg)
+	queryEmbedding, embErr := qs.embed(ctx, query)
 	if embErr == nil && len(queryEmbedding) > 0 {
 		symbolsWithEmb, err := qs.repo.ListSymbolsWithEmbeddings(ctx)
 		if err == nil {
 			for i := range symbolsWithEmb {
 				sym := &symbolsWithEmb[i]
-				if len(sym.Embedding) == 0 {
+				if len(sym.Embedding) == 0 || !filter.Matches(sym) {
 					continue
 				}
 
@@ -128,23 +219,38 @@ func (qs *QueryService) HybridSearch(ctx context.Context, query string, limit in
 					sourceByID[sym.ID] = "hybrid"
 				}
 				scoreByID[sym.ID] += vectorScore * qs.config.VectorWeight
+				vectorScoreByID[sym.ID] = vectorScore
 			}
 		}
 	}
 
 	// 3. Merge, filter low-confidence, and sort by combined score
+	minScore := qs.config.MinResultThreshold
+	if opts.MinScore > 0 {
+		minScore = opts.MinScore
+	}
 	var results []SymbolSearchResult
 	for id, score := range scoreByID {
 		// Filter out noise: only include results above minimum threshold
-		if score < qs.config.MinResultThreshold {
+		if score < minScore {
 			continue
 		}
 		if sym, ok := symbolByID[id]; ok {
-			results = append(results, SymbolSearchResult{
+			result := SymbolSearchResult{
 				Symbol: *sym,
 				Score:  score,
 				Source: sourceByID[id],
-			})
+			}
+			if opts.Debug {
+				result.MatchReason = &MatchReason{
+					Via:          sourceByID[id],
+					FTSScore:     ftsScoreByID[id],
+					VectorScore:  vectorScoreByID[id],
+					FTSWeight:    qs.config.FTSWeight,
+					VectorWeight: qs.config.VectorWeight,
+				}
+			}
+			results = append(results, result)
 		}
 	}
 
@@ -164,44 +270,37 @@ func (qs *QueryService) HybridSearch(ctx context.Context, query string, limit in
 // SearchByKind performs hybrid search filtered to a specific symbol kind.
 // Useful for finding only functions, only structs, etc.
 func (qs *QueryService) SearchByKind(ctx context.Context, query string, kind SymbolKind, limit int) ([]SymbolSearchResult, error) {
-	// Get all results then filter by kind
-	// This is simpler than adding kind filtering to both FTS and vector search
-	allResults, err := qs.HybridSearch(ctx, query, limit*3) // Fetch more to allow for filtering
-	if err != nil {
-		return nil, err
-	}
-
-	var filtered []SymbolSearchResult
-	for _, r := range allResults {
-		if r.Symbol.Kind == kind {
-			filtered = append(filtered, r)
-			if len(filtered) >= limit {
-				break
-			}
-		}
-	}
-
-	return filtered, nil
+	return qs.HybridSearchWithOptions(ctx, query, limit, SearchOptions{Kind: kind})
 }
 
 // SearchByFile performs hybrid search filtered to a specific file.
 func (qs *QueryService) SearchByFile(ctx context.Context, query string, filePath string, limit int) ([]SymbolSearchResult, error) {
-	allResults, err := qs.HybridSearch(ctx, query, limit*3)
+	return qs.HybridSearchWithOptions(ctx, query, limit, SearchOptions{FilePath: filePath})
+}
+
+// SearchBySignature finds symbols by signature shape rather than name.
+// pattern lists parameter or return types in order, comma-separated
+// (e.g. "context.Context, string"). Results are ranked by signature length.
+// The filtering fields of opts apply; MinScore and Debug are ignored.
+func (qs *QueryService) SearchBySignature(ctx context.Context, pattern string, limit int, opts SearchOptions) ([]SymbolSearchResult, error) {
+	if limit <= 0 {
+		limit = qs.config.DefaultLimit
+	}
+
+	symbols, err := qs.repo.SearchSymbolsBySignature(ctx, pattern, limit, opts.symbolFilter())
 	if err != nil {
 		return nil, err
 	}
 
-	var filtered []SymbolSearchResult
-	for _, r := range allResults {
-		if r.Symbol.FilePath == filePath {
-			filtered = append(filtered, r)
-			if len(filtered) >= limit {
-				break
-			}
+	results := make([]SymbolSearchResult, len(symbols))
+	for i, sym := range symbols {
+		results[i] = SymbolSearchResult{
+			Symbol: sym,
+			Score:  float32(1.0) - float32(i)/float32(len(symbols)+1),
+			Source: "signature",
 		}
 	}
-
-	return filtered, nil
+	return results, nil
 }
 
 // AnalyzeImpact finds all symbols that would be affected by changing a given symbol.
@@ -225,8 +324,13 @@ func (qs *QueryService) AnalyzeImpact(ctx context.Context, symbolID uint32, maxD
 		return nil, fmt.Errorf("get source symbol: %w", err)
 	}
 
+	var exclude func(string) bool
+	if patterns := qs.config.ImpactExcludePatterns; len(patterns) > 0 {
+		exclude = func(path string) bool { return matchesImpactExclude(path, patterns) }
+	}
+
 	// Use repository's recursive CTE-based impact analysis
-	impactNodes, err := qs.repo.GetImpactRadius(ctx, symbolID, maxDepth)
+	impactNodes, err := qs.repo.GetImpactRadius(ctx, symbolID, maxDepth, exclude)
 	if err != nil {
 		return nil, fmt.Errorf("get impact radius: %w", err)
 	}
@@ -291,6 +395,24 @@ func (qs *QueryService) FindSymbolByNameAndLang(ctx context.Context, name, lang
 	return qs.repo.FindSymbolsByName(ctx, name, &lang)
 }
 
+// PublicAPISurface lists public symbols ranked by how many symbols call them,
+// most-depended-upon first. Pass a module path to restrict the report to one
+// module, or an empty string for the whole index. Symbols at the bottom of the
+// list with no callers are candidates for dead-code review.
+func (qs *QueryService) PublicAPISurface(ctx context.Context, modulePath string) ([]SymbolFanIn, error) {
+	return qs.repo.GetPublicSymbolsByCallers(ctx, modulePath)
+}
+
+// HighFanIn returns symbols called by more than threshold distinct symbols,
+// most-called first. Such symbols are likely god-objects or refactor targets.
+// A threshold of zero or less uses DefaultFanInThreshold.
+func (qs *QueryService) HighFanIn(ctx context.Context, threshold int) ([]SymbolFanIn, error) {
+	if threshold <= 0 {
+		threshold = DefaultFanInThreshold
+	}
+	return qs.repo.GetHighFanInSymbols(ctx, threshold)
+}
+
 // GetCallers returns all symbols that call the given symbol.
 func (qs *QueryService) GetCallers(ctx context.Context, symbolID uint32) ([]Symbol, error) {
 	return qs.repo.GetCallers(ctx, symbolID)
@@ -301,16 +423,123 @@ func (qs *QueryService) GetCallees(ctx context.Context, symbolID uint32) ([]Symb
 	return qs.repo.GetCallees(ctx, symbolID)
 }
 
+// GetUsages returns all symbols that use the given symbol: callers as well as
+// reference sites such as struct fields of its type.
+func (qs *QueryService) GetUsages(ctx context.Context, symbolID uint32) ([]SymbolUsage, error) {
+	return qs.repo.GetUsages(ctx, symbolID)
+}
+
 // GetImplementations returns all types that implement a given interface.
 func (qs *QueryService) GetImplementations(ctx context.Context, interfaceID uint32) ([]Symbol, error) {
 	return qs.repo.GetImplementations(ctx, interfaceID)
 }
 
+// GetImplementationsTransitive returns the types that implement the interface
+// or any interface that embeds it, following up to maxDepth embedding hops.
+// A maxDepth <= 0 uses the configured default; larger values are capped at it.
+func (qs *QueryService) GetImplementationsTransitive(ctx context.Context, interfaceID uint32, maxDepth int) ([]Symbol, error) {
+	limit := qs.config.MaxImplementationDepth
+	if limit <= 0 {
+		limit = DefaultQueryConfig().MaxImplementationDepth
+	}
+	if maxDepth <= 0 || maxDepth > limit {
+		maxDepth = limit
+	}
+	return qs.repo.GetTransitiveImplementations(ctx, interfaceID, maxDepth)
+}
+
 // GetSymbolsInFile returns all symbols defined in a file.
 func (qs *QueryService) GetSymbolsInFile(ctx context.Context, filePath string) ([]Symbol, error) {
 	return qs.repo.FindSymbolsByFile(ctx, filePath)
 }
 
+// FileOutline lists the symbols defined in a single file, in source order.
+type FileOutline struct {
+	FilePath string   `json:"filePath"`
+	Language string   `json:"language,omitempty"`
+	Symbols  []Symbol `json:"symbols"`
+}
+
+// GetFileOutline returns the symbols of a file sorted by position.
+func (qs *QueryService) GetFileOutline(ctx context.Context, filePath string) (*FileOutline, error) {
+	symbols, err := qs.repo.FindSymbolsByFile(ctx, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("find symbols in %s: %w", filePath, err)
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].StartLine < symbols[j].StartLine
+	})
+
+	outline := &FileOutline{FilePath: filePath, Symbols: symbols}
+	if len(symbols) > 0 {
+		outline.Language = symbols[0].Language
+	}
+	return outline, nil
+}
+
+// ModuleFile groups the symbols of one file within a module tree.
+type ModuleFile struct {
+	FilePath string   `json:"filePath"`
+	Count    int      `json:"count"`
+	Symbols  []Symbol `json:"symbols"`
+}
+
+// ModuleTree lists the symbols of a directory grouped by file.
+type ModuleTree struct {
+	ModulePath  string       `json:"modulePath"`
+	Files       []ModuleFile `json:"files"`
+	SymbolCount int          `json:"symbolCount"`
+}
+
+// GetSymbolsInModule returns the symbols defined in a module directory relative
+// to the project root (e.g. "internal/memory"), grouped by file in path order.
+// It is the directory-scoped counterpart of GetSymbolsInFile; subdirectories
+// are separate modules and are not included.
+func (qs *QueryService) GetSymbolsInModule(ctx context.Context, modulePath string) (*ModuleTree, error) {
+	modulePath = filepath.ToSlash(filepath.Clean(modulePath))
+	symbols, err := qs.repo.FindSymbolsInDir(ctx, modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("find symbols in module %s: %w", modulePath, err)
+	}
+
+	tree := &ModuleTree{ModulePath: modulePath, Files: []ModuleFile{}, SymbolCount: len(symbols)}
+	for _, s := range symbols {
+		if n := len(tree.Files); n == 0 || tree.Files[n-1].FilePath != s.FilePath {
+			tree.Files = append(tree.Files, ModuleFile{FilePath: s.FilePath})
+		}
+		f := &tree.Files[len(tree.Files)-1]
+		f.Symbols = append(f.Symbols, s)
+		f.Count++
+	}
+	return tree, nil
+}
+
+// FileImports lists a file's imports, split into packages within the project
+// and external ones (standard library or third-party, see Category).
+type FileImports struct {
+	FilePath string       `json:"filePath"`
+	Internal []FileImport `json:"internal"`
+	External []FileImport `json:"external"`
+}
+
+// GetFileImports returns the imports recorded for a file when it was indexed.
+func (qs *QueryService) GetFileImports(ctx context.Context, filePath string) (*FileImports, error) {
+	imports, err := qs.repo.GetFileImports(ctx, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("get imports of %s: %w", filePath, err)
+	}
+
+	result := &FileImports{FilePath: filePath, Internal: []FileImport{}, External: []FileImport{}}
+	for _, imp := range imports {
+		if imp.Category == ImportInternal {
+			result.Internal = append(result.Internal, imp)
+		} else {
+			result.External = append(result.External, imp)
+		}
+	}
+	return result, nil
+}
+
 // GetStats returns current index statistics.
 func (qs *QueryService) GetStats(ctx context.Context) (*IndexStats, error) {
 	symbolCount, err := qs.repo.GetSymbolCount(ctx)
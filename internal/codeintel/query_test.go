@@ -0,0 +1,1033 @@
+package codeintel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+func TestQueryService_AnalyzeImpactExcludesVendored(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	ids := make(map[string]uint32)
+	for _, s := range []Symbol{
+		{Name: "Target", Kind: SymbolFunction, FilePath: "internal/core/target.go", StartLine: 1, EndLine: 3, Language: "go"},
+		{Name: "Handler", Kind: SymbolFunction, FilePath: "internal/api/handler.go", StartLine: 1, EndLine: 3, Language: "go"},
+		{Name: "Wrap", Kind: SymbolFunction, FilePath: "vendor/github.com/acme/lib/wrap.go", StartLine: 1, EndLine: 3, Language: "go"},
+		{Name: "UseWrap", Kind: SymbolFunction, FilePath: "internal/api/wrapped.go", StartLine: 1, EndLine: 3, Language: "go"},
+	} {
+		id, err := repo.UpsertSymbol(ctx, &s)
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+		ids[s.Name] = id
+	}
+	for _, r := range [][2]string{{"Handler", "Target"}, {"Wrap", "Target"}, {"UseWrap", "Wrap"}} {
+		rel := &SymbolRelation{FromSymbolID: ids[r[0]], ToSymbolID: ids[r[1]], RelationType: RelationCalls}
+		if err := repo.UpsertRelation(ctx, rel); err != nil {
+			t.Fatalf("UpsertRelation %s->%s: %v", r[0], r[1], err)
+		}
+	}
+
+	affectedNames := func(a *ImpactAnalysis) map[string]bool {
+		names := make(map[string]bool)
+		for _, n := range a.Affected {
+			names[n.Symbol.Name] = true
+		}
+		return names
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	all, err := qs.AnalyzeImpact(ctx, ids["Target"], 0)
+	if err != nil {
+		t.Fatalf("AnalyzeImpact: %v", err)
+	}
+	if all.AffectedCount != 3 || !affectedNames(all)["Wrap"] {
+		t.Fatalf("without exclusions expected Handler, Wrap, UseWrap; got %v", affectedNames(all))
+	}
+
+	qs.SetImpactExcludePatterns(DefaultImpactExcludePatterns())
+	filtered, err := qs.AnalyzeImpact(ctx, ids["Target"], 0)
+	if err != nil {
+		t.Fatalf("AnalyzeImpact: %v", err)
+	}
+	names := affectedNames(filtered)
+	if filtered.AffectedCount != 1 || !names["Handler"] {
+		t.Fatalf("expected only Handler, got %v", names)
+	}
+	if names["Wrap"] || names["UseWrap"] {
+		t.Errorf("vendored caller and its dependents should be excluded, got %v", names)
+	}
+	if filtered.AffectedFiles != 1 {
+		t.Errorf("AffectedFiles = %d, want 1", filtered.AffectedFiles)
+	}
+}
+
+func TestQueryService_PublicAPISurface(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	ids := make(map[string]uint32)
+	for _, s := range []Symbol{
+		{Name: "Unused", Kind: SymbolFunction, FilePath: "core/unused.go", StartLine: 1, EndLine: 3, ModulePath: "core", Visibility: "public", Language: "go"},
+		{Name: "Parse", Kind: SymbolFunction, FilePath: "core/parse.go", StartLine: 1, EndLine: 3, ModulePath: "core", Visibility: "public", Language: "go"},
+		{Name: "helper", Kind: SymbolFunction, FilePath: "core/parse.go", StartLine: 5, EndLine: 7, ModulePath: "core", Visibility: "private", Language: "go"},
+		{Name: "Serve", Kind: SymbolFunction, FilePath: "api/serve.go", StartLine: 1, EndLine: 3, ModulePath: "api", Visibility: "public", Language: "go"},
+	} {
+		id, err := repo.UpsertSymbol(ctx, &s)
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+		ids[s.Name] = id
+	}
+	for _, r := range [][2]string{{"Serve", "Parse"}, {"helper", "Parse"}, {"Serve", "helper"}} {
+		rel := &SymbolRelation{FromSymbolID: ids[r[0]], ToSymbolID: ids[r[1]], RelationType: RelationCalls}
+		if err := repo.UpsertRelation(ctx, rel); err != nil {
+			t.Fatalf("UpsertRelation %s->%s: %v", r[0], r[1], err)
+		}
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	surface, err := qs.PublicAPISurface(ctx, "core")
+	if err != nil {
+		t.Fatalf("PublicAPISurface: %v", err)
+	}
+	if len(surface) != 2 {
+		t.Fatalf("expected 2 public symbols in core, got %d: %v", len(surface), surface)
+	}
+	if surface[0].Symbol.Name != "Parse" || surface[0].CallerCount != 2 {
+		t.Errorf("first = %s (%d callers), want Parse (2)", surface[0].Symbol.Name, surface[0].CallerCount)
+	}
+	if surface[1].Symbol.Name != "Unused" || surface[1].CallerCount != 0 {
+		t.Errorf("second = %s (%d callers), want Unused (0)", surface[1].Symbol.Name, surface[1].CallerCount)
+	}
+
+	all, err := qs.PublicAPISurface(ctx, "")
+	if err != nil {
+		t.Fatalf("PublicAPISurface: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("expected 3 public symbols across modules, got %d", len(all))
+	}
+}
+
+func TestQueryService_PublicAPISurfaceFromIndexedCalls(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	// Both packages declare Format; each caller uses its own package's
+	for name, src := range map[string]string{
+		"alpha/format.go": "package alpha\n\nfunc Format() string { return \"a\" }\n\nfunc Render() string { return Format() }\n",
+		"beta/format.go":  "package beta\n\nfunc Format() string { return \"b\" }\n\nfunc Print() { _ = Format() }\n\nfunc Log() { _ = Format() }\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+	if _, err := NewIndexer(repo, DefaultIndexerConfig()).IndexDirectory(ctx, dir); err != nil {
+		t.Fatalf("IndexDirectory: %v", err)
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	for module, want := range map[string]int{"alpha": 1, "beta": 2} {
+		surface, err := qs.PublicAPISurface(ctx, module)
+		if err != nil {
+			t.Fatalf("PublicAPISurface(%s): %v", module, err)
+		}
+		if len(surface) == 0 || surface[0].Symbol.Name != "Format" || surface[0].CallerCount != want {
+			t.Errorf("%s surface = %+v, want Format with %d callers first", module, surface, want)
+		}
+	}
+}
+
+func TestQueryService_HighFanIn(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	upsert := func(name, file string) uint32 {
+		t.Helper()
+		id, err := repo.UpsertSymbol(ctx, &Symbol{Name: name, Kind: SymbolFunction, FilePath: file, StartLine: 1, EndLine: 3, Language: "go"})
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", name, err)
+		}
+		return id
+	}
+	call := func(from, to uint32) {
+		t.Helper()
+		if err := repo.UpsertRelation(ctx, &SymbolRelation{FromSymbolID: from, ToSymbolID: to, RelationType: RelationCalls}); err != nil {
+			t.Fatalf("UpsertRelation: %v", err)
+		}
+	}
+
+	hub := upsert("Registry", "core/registry.go")
+	leaf := upsert("Format", "core/format.go")
+	for i := 0; i < 12; i++ {
+		caller := upsert(fmt.Sprintf("Handler%d", i), fmt.Sprintf("api/handler%d.go", i))
+		call(caller, hub)
+		if i < 3 {
+			call(caller, leaf)
+		}
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	flagged, err := qs.HighFanIn(ctx, 10)
+	if err != nil {
+		t.Fatalf("HighFanIn: %v", err)
+	}
+	if len(flagged) != 1 || flagged[0].Symbol.Name != "Registry" || flagged[0].CallerCount != 12 {
+		t.Fatalf("threshold 10: got %v, want only Registry with 12 callers", flagged)
+	}
+
+	flagged, err = qs.HighFanIn(ctx, 12)
+	if err != nil {
+		t.Fatalf("HighFanIn: %v", err)
+	}
+	if len(flagged) != 0 {
+		t.Errorf("threshold 12: expected nothing flagged, got %v", flagged)
+	}
+
+	flagged, err = qs.HighFanIn(ctx, 2)
+	if err != nil {
+		t.Fatalf("HighFanIn: %v", err)
+	}
+	if len(flagged) != 2 || flagged[0].Symbol.Name != "Registry" || flagged[1].Symbol.Name != "Format" {
+		t.Errorf("threshold 2: got %v, want Registry then Format", flagged)
+	}
+}
+
+func TestQueryService_SearchBySignature(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	for _, s := range []Symbol{
+		{Name: "LoadUser", Kind: SymbolFunction, FilePath: "user.go", StartLine: 1, EndLine: 3, Language: "go",
+			Signature: "func LoadUser(ctx context.Context, id string) (*User, error)"},
+		{Name: "CountUsers", Kind: SymbolFunction, FilePath: "user.go", StartLine: 5, EndLine: 7, Language: "go",
+			Signature: "func CountUsers(ctx context.Context) (int, error)"},
+		{Name: "ParseID", Kind: SymbolFunction, FilePath: "id.go", StartLine: 1, EndLine: 3, Language: "go",
+			Signature: "func ParseID(raw string) (int, error)"},
+		{Name: "Render", Kind: SymbolFunction, FilePath: "tmpl.go", StartLine: 1, EndLine: 3, Language: "go",
+			Signature: "func Render(name string, ctx context.Context) error"},
+	} {
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	results, err := qs.SearchBySignature(ctx, "context.Context, string", 10, SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchBySignature: %v", err)
+	}
+	if len(results) != 1 || results[0].Symbol.Name != "LoadUser" {
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Symbol.Name)
+		}
+		t.Fatalf("expected only LoadUser (types in order), got %v", names)
+	}
+	if results[0].Source != "signature" {
+		t.Errorf("Source = %q, want signature", results[0].Source)
+	}
+
+	// LIKE wildcards in the pattern are matched literally
+	if results, err := qs.SearchBySignature(ctx, "%", 10, SearchOptions{}); err != nil || len(results) != 0 {
+		t.Errorf("pattern %%: got %d results, err %v; want none", len(results), err)
+	}
+}
+
+func TestQueryService_HybridSearchExcludesPrivate(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	for _, s := range []Symbol{
+		{Name: "ParseConfig", Kind: SymbolFunction, FilePath: "config/parse.go", ModulePath: "config", StartLine: 1, EndLine: 5, Visibility: "public", Language: "go"},
+		{Name: "parseConfigLine", Kind: SymbolFunction, FilePath: "config/parse.go", ModulePath: "config", StartLine: 7, EndLine: 12, Visibility: "private", Language: "go"},
+	} {
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	all, err := qs.HybridSearch(ctx, "config", 10)
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("default search = %v, want public and private symbols", all)
+	}
+
+	public, err := qs.HybridSearchWithOptions(ctx, "config", 10, SearchOptions{ExcludePrivate: true})
+	if err != nil {
+		t.Fatalf("HybridSearchWithOptions: %v", err)
+	}
+	if len(public) != 1 || public[0].Symbol.Name != "ParseConfig" {
+		t.Errorf("public-only search = %v, want only ParseConfig", public)
+	}
+
+	// Private symbols that outrank the public one must not use up the limit
+	for i := range 5 {
+		s := Symbol{Name: fmt.Sprintf("tokenHelper%d", i), Kind: SymbolFunction, FilePath: "auth/token.go", StartLine: i*10 + 1, EndLine: i*10 + 5,
+			DocComment: "token token token", Visibility: "private", Language: "go"}
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+	}
+	refresh := Symbol{Name: "Refresh", Kind: SymbolFunction, FilePath: "auth/refresh.go", StartLine: 1, EndLine: 9,
+		DocComment: "Refresh renews the session token before it expires and stores the result", Visibility: "public", Language: "go"}
+	if _, err := repo.UpsertSymbol(ctx, &refresh); err != nil {
+		t.Fatalf("UpsertSymbol Refresh: %v", err)
+	}
+	for name, search := range map[string]func() ([]SymbolSearchResult, error){
+		"hybrid": func() ([]SymbolSearchResult, error) {
+			return qs.HybridSearchWithOptions(ctx, "token", 1, SearchOptions{ExcludePrivate: true})
+		},
+		"by file": func() ([]SymbolSearchResult, error) {
+			return qs.HybridSearchWithOptions(ctx, "token", 1, SearchOptions{ExcludePrivate: true, FilePath: "auth/refresh.go"})
+		},
+	} {
+		results, err := search()
+		if err != nil {
+			t.Fatalf("%s search: %v", name, err)
+		}
+		if len(results) != 1 || results[0].Symbol.Name != "Refresh" {
+			t.Errorf("%s public-only search with limit 1 = %v, want Refresh", name, results)
+		}
+	}
+}
+
+func TestQueryService_HybridSearchLanguage(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	for _, s := range []Symbol{
+		{Name: "ParseConfig", Kind: SymbolFunction, FilePath: "config/parse.go", StartLine: 1, EndLine: 5, Visibility: "public", Language: "go"},
+		{Name: "ParseConfig", Kind: SymbolFunction, FilePath: "web/src/config.ts", StartLine: 1, EndLine: 5, Visibility: "public", Language: "typescript"},
+	} {
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.FilePath, err)
+		}
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	all, err := qs.HybridSearch(ctx, "ParseConfig", 10)
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("unfiltered search = %v, want both languages", all)
+	}
+
+	ts, err := qs.HybridSearchWithOptions(ctx, "ParseConfig", 10, SearchOptions{Language: "TypeScript"})
+	if err != nil {
+		t.Fatalf("HybridSearchWithOptions: %v", err)
+	}
+	if len(ts) != 1 || ts[0].Symbol.FilePath != "web/src/config.ts" {
+		t.Errorf("typescript search = %v, want only web/src/config.ts", ts)
+	}
+
+	// Symbols of other languages must not use up the limit
+	for i := range 5 {
+		s := Symbol{Name: fmt.Sprintf("loadConfig%d", i), Kind: SymbolFunction, FilePath: "config/load.go", StartLine: i*10 + 1, EndLine: i*10 + 5,
+			Signature: "func(path string) (*Config, error)", DocComment: "config config config", Visibility: "public", Language: "go"}
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+	}
+	loader := Symbol{Name: "loadSettings", Kind: SymbolFunction, FilePath: "web/src/settings.ts", StartLine: 1, EndLine: 9,
+		Signature: "function loadSettings(path string): Config", DocComment: "Reads the settings file and merges it with the default config values",
+		Visibility: "public", Language: "typescript"}
+	if _, err := repo.UpsertSymbol(ctx, &loader); err != nil {
+		t.Fatalf("UpsertSymbol loadSettings: %v", err)
+	}
+	byText, err := qs.HybridSearchWithOptions(ctx, "config", 1, SearchOptions{Language: "typescript"})
+	if err != nil {
+		t.Fatalf("HybridSearchWithOptions: %v", err)
+	}
+	if len(byText) != 1 || byText[0].Symbol.Name != "loadSettings" {
+		t.Errorf("typescript search with limit 1 = %v, want loadSettings", byText)
+	}
+	bySig, err := qs.SearchBySignature(ctx, "string, Config", 1, SearchOptions{Language: "TypeScript"})
+	if err != nil {
+		t.Fatalf("SearchBySignature: %v", err)
+	}
+	if len(bySig) != 1 || bySig[0].Symbol.Name != "loadSettings" {
+		t.Errorf("typescript signature search with limit 1 = %v, want loadSettings", bySig)
+	}
+}
+
+func TestQueryService_HybridSearchMinScore(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	for _, s := range []Symbol{
+		{Name: "Invoice", Kind: SymbolStruct, FilePath: "billing/invoice.go", StartLine: 1, EndLine: 5, DocComment: "Invoice is a billed invoice.", Language: "go"},
+		{Name: "SendReminder", Kind: SymbolFunction, FilePath: "billing/remind.go", StartLine: 1, EndLine: 5, DocComment: "SendReminder emails customers about overdue payments, for example an unpaid invoice, and logs the delivery attempt.", Language: "go"},
+	} {
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+	}
+
+	// Without embeddings scores come from FTS rank alone: 0.3 for the strong
+	// match, 0.2 for the weak one
+	qs := NewQueryService(repo, llm.Config{})
+	kept, err := qs.HybridSearchWithOptions(ctx, "invoice", 10, SearchOptions{MinScore: 0.15})
+	if err != nil {
+		t.Fatalf("HybridSearchWithOptions: %v", err)
+	}
+	if len(kept) != 2 || kept[0].Symbol.Name != "Invoice" {
+		t.Fatalf("min_score 0.15 = %v, want Invoice then SendReminder", kept)
+	}
+
+	strict, err := qs.HybridSearchWithOptions(ctx, "invoice", 10, SearchOptions{MinScore: 0.25})
+	if err != nil {
+		t.Fatalf("HybridSearchWithOptions: %v", err)
+	}
+	if len(strict) != 1 || strict[0].Symbol.Name != "Invoice" {
+		t.Errorf("min_score 0.25 = %v, want only Invoice", strict)
+	}
+}
+
+func TestQueryService_HybridSearchDebugMatchReason(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	sym := Symbol{Name: "Ledger", Kind: SymbolStruct, FilePath: "billing/ledger.go", StartLine: 1, EndLine: 5, Language: "go"}
+	if _, err := repo.UpsertSymbol(ctx, &sym); err != nil {
+		t.Fatalf("UpsertSymbol: %v", err)
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	plain, err := qs.HybridSearch(ctx, "Ledger", 10)
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+	if len(plain) != 1 || plain[0].MatchReason != nil {
+		t.Fatalf("non-debug search = %+v, want one result without a match reason", plain)
+	}
+
+	results, err := qs.HybridSearchWithOptions(ctx, "Ledger", 10, SearchOptions{Debug: true})
+	if err != nil {
+		t.Fatalf("HybridSearchWithOptions: %v", err)
+	}
+	if len(results) != 1 || results[0].MatchReason == nil {
+		t.Fatalf("debug search = %+v, want one result with a match reason", results)
+	}
+	cfg := DefaultQueryConfig()
+	want := MatchReason{Via: "fts", FTSScore: 1, VectorScore: 0, FTSWeight: cfg.FTSWeight, VectorWeight: cfg.VectorWeight}
+	if got := *results[0].MatchReason; got != want {
+		t.Errorf("match reason = %+v, want %+v", got, want)
+	}
+	if results[0].Score != want.FTSScore*want.FTSWeight {
+		t.Errorf("score = %v, want fts component %v", results[0].Score, want.FTSScore*want.FTSWeight)
+	}
+}
+
+func TestQueryService_GetImplementationsTransitive(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	// ReadWriteCloser embeds ReadCloser, which embeds Closer; File implements ReadWriteCloser
+	ids := make(map[string]uint32)
+	for _, s := range []Symbol{
+		{Name: "Closer", Kind: SymbolInterface, FilePath: "io.go", StartLine: 1, EndLine: 3, Language: "go"},
+		{Name: "ReadCloser", Kind: SymbolInterface, FilePath: "io.go", StartLine: 5, EndLine: 8, Language: "go"},
+		{Name: "ReadWriteCloser", Kind: SymbolInterface, FilePath: "io.go", StartLine: 10, EndLine: 13, Language: "go"},
+		{Name: "File", Kind: SymbolStruct, FilePath: "file.go", StartLine: 1, EndLine: 5, Language: "go"},
+	} {
+		id, err := repo.UpsertSymbol(ctx, &s)
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+		ids[s.Name] = id
+	}
+	for _, r := range []struct {
+		from, to string
+		rel      RelationType
+	}{
+		{"ReadCloser", "Closer", RelationExtends},
+		{"ReadWriteCloser", "ReadCloser", RelationExtends},
+		{"File", "ReadWriteCloser", RelationImplements},
+	} {
+		rel := &SymbolRelation{FromSymbolID: ids[r.from], ToSymbolID: ids[r.to], RelationType: r.rel}
+		if err := repo.UpsertRelation(ctx, rel); err != nil {
+			t.Fatalf("UpsertRelation %s->%s: %v", r.from, r.to, err)
+		}
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	direct, err := qs.GetImplementations(ctx, ids["ReadCloser"])
+	if err != nil {
+		t.Fatalf("GetImplementations: %v", err)
+	}
+	if len(direct) != 0 {
+		t.Errorf("one-hop lookup should not follow embedding, got %d results", len(direct))
+	}
+
+	impls, err := qs.GetImplementationsTransitive(ctx, ids["ReadCloser"], 0)
+	if err != nil {
+		t.Fatalf("GetImplementationsTransitive: %v", err)
+	}
+	if len(impls) != 1 || impls[0].Name != "File" {
+		t.Fatalf("expected File to implement the embedded ReadCloser, got %v", impls)
+	}
+
+	// Closer is two embedding hops away; a depth of 1 stops short
+	if impls, err := qs.GetImplementationsTransitive(ctx, ids["Closer"], 1); err != nil || len(impls) != 0 {
+		t.Errorf("depth 1 from Closer: got %v, err %v; want none", impls, err)
+	}
+	if impls, err := qs.GetImplementationsTransitive(ctx, ids["Closer"], 2); err != nil || len(impls) != 1 {
+		t.Errorf("depth 2 from Closer: got %v, err %v; want File", impls, err)
+	}
+
+	// Requested depth is capped at the configured maximum
+	cfg := DefaultQueryConfig()
+	cfg.MaxImplementationDepth = 1
+	capped := NewQueryServiceWithConfig(repo, llm.Config{}, cfg)
+	if impls, err := capped.GetImplementationsTransitive(ctx, ids["Closer"], 10); err != nil || len(impls) != 0 {
+		t.Errorf("capped depth from Closer: got %v, err %v; want none", impls, err)
+	}
+}
+
+func TestQueryService_GetFileImports(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":                  "module example.com/shop\n",
+		"internal/store/store.go": "package store\n\nfunc Open() {}\n",
+		"cmd/main.go": `package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"example.com/shop/internal/store"
+	"github.com/google/uuid"
+)
+
+func main() { store.Open(); fmt.Println(http.MethodGet, uuid.New()) }
+`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+	if _, err := NewIndexer(repo, DefaultIndexerConfig()).IndexDirectory(ctx, dir); err != nil {
+		t.Fatalf("IndexDirectory: %v", err)
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	imports, err := qs.GetFileImports(ctx, filepath.Join("cmd", "main.go"))
+	if err != nil {
+		t.Fatalf("GetFileImports: %v", err)
+	}
+	if len(imports.Internal) != 1 || imports.Internal[0].Path != "example.com/shop/internal/store" {
+		t.Errorf("internal = %v, want only the store package", imports.Internal)
+	}
+	want := map[string]ImportCategory{"fmt": ImportStdlib, "net/http": ImportStdlib, "github.com/google/uuid": ImportThirdParty}
+	if len(imports.External) != len(want) {
+		t.Fatalf("external = %v, want %v", imports.External, want)
+	}
+	for _, imp := range imports.External {
+		if want[imp.Path] != imp.Category {
+			t.Errorf("%s categorized as %q, want %q", imp.Path, imp.Category, want[imp.Path])
+		}
+	}
+}
+
+func TestQueryService_GetUsagesReportsFieldReference(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	src := `package server
+
+type Config struct {
+	Addr string
+}
+
+type Server struct {
+	cfg *Config
+}
+
+func NewConfig() Config { return Config{} }
+
+func Start() { _ = NewConfig() }
+`
+	if err := os.WriteFile(filepath.Join(dir, "server.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+	if _, err := NewIndexer(repo, DefaultIndexerConfig()).IndexDirectory(ctx, dir); err != nil {
+		t.Fatalf("IndexDirectory: %v", err)
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	usageOf := func(name string, kind SymbolKind) []SymbolUsage {
+		t.Helper()
+		symbols, err := qs.FindSymbolByName(ctx, name)
+		if err != nil {
+			t.Fatalf("FindSymbolByName(%s): %v", name, err)
+		}
+		for _, s := range symbols {
+			if s.Kind == kind {
+				usages, err := qs.GetUsages(ctx, s.ID)
+				if err != nil {
+					t.Fatalf("GetUsages(%s): %v", name, err)
+				}
+				return usages
+			}
+		}
+		t.Fatalf("no %s symbol named %s", kind, name)
+		return nil
+	}
+
+	usages := usageOf("Config", SymbolStruct)
+	if len(usages) != 1 {
+		t.Fatalf("Config usages = %+v, want the Server.cfg field", usages)
+	}
+	if u := usages[0]; u.Relation != RelationReferences || u.Symbol.Name != "cfg" || u.Line != 8 {
+		t.Errorf("Config usage = %s %s line %d, want references cfg line 8", u.Relation, u.Symbol.Name, u.Line)
+	}
+
+	// Calls are reported alongside references
+	usages = usageOf("NewConfig", SymbolFunction)
+	if len(usages) != 1 || usages[0].Relation != RelationCalls || usages[0].Symbol.Name != "Start" {
+		t.Errorf("NewConfig usages = %+v, want a call from Start", usages)
+	}
+}
+
+func TestQueryService_GetUsagesResolvesQualifiedTypes(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	// Both packages declare Config; the qualifier decides which one a field uses
+	for name, src := range map[string]string{
+		"config/config.go": "package config\n\ntype Config struct{ Path string }\n",
+		"server/server.go": "package server\n\nimport \"example.com/app/config\"\n\ntype Config struct{ Addr string }\n\ntype Server struct {\n\tlocal  Config\n\tloaded *config.Config\n}\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+	if _, err := NewIndexer(repo, DefaultIndexerConfig()).IndexDirectory(ctx, dir); err != nil {
+		t.Fatalf("IndexDirectory: %v", err)
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	symbols, err := qs.FindSymbolByName(ctx, "Config")
+	if err != nil {
+		t.Fatalf("FindSymbolByName: %v", err)
+	}
+	want := map[string]string{"config": "loaded", "server": "local"} // module -> referencing field
+	for _, s := range symbols {
+		if s.Kind != SymbolStruct {
+			continue
+		}
+		usages, err := qs.GetUsages(ctx, s.ID)
+		if err != nil {
+			t.Fatalf("GetUsages(%s.Config): %v", s.ModulePath, err)
+		}
+		if len(usages) != 1 || usages[0].Symbol.Name != want[s.ModulePath] {
+			t.Errorf("%s.Config usages = %+v, want only the %s field", s.ModulePath, usages, want[s.ModulePath])
+		}
+		delete(want, s.ModulePath)
+	}
+	if len(want) != 0 {
+		t.Errorf("Config structs not indexed for modules %v", want)
+	}
+}
+
+func TestQueryService_SearchByKindConstantsAndAliases(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	src := `package config
+
+// Retry limits for outbound requests.
+const (
+	MaxRetries   = 3
+	RetryBackoff = "250ms"
+)
+
+// Duration is kept as an alias for older callers.
+type Duration = int64
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+	if _, err := NewIndexer(repo, DefaultIndexerConfig()).IndexDirectory(ctx, dir); err != nil {
+		t.Fatalf("IndexDirectory: %v", err)
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	for _, tc := range []struct {
+		query   string
+		kind    SymbolKind
+		wantSig string
+	}{
+		{"MaxRetries", SymbolConstant, "const MaxRetries = 3"},
+		{"RetryBackoff", SymbolConstant, `const RetryBackoff = "250ms"`},
+		{"Duration", SymbolType, "type Duration = int64"},
+	} {
+		results, err := qs.SearchByKind(ctx, tc.query, tc.kind, 5)
+		if err != nil {
+			t.Fatalf("SearchByKind(%s): %v", tc.query, err)
+		}
+		if len(results) == 0 || results[0].Symbol.Name != tc.query {
+			t.Errorf("SearchByKind(%q, %s) = %+v, want %s first", tc.query, tc.kind, results, tc.query)
+			continue
+		}
+		if got := results[0].Symbol.Signature; got != tc.wantSig {
+			t.Errorf("%s signature = %q, want %q", tc.query, got, tc.wantSig)
+		}
+	}
+}
+
+func TestQueryService_GetSymbolsInModule(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	for _, s := range []Symbol{
+		{Name: "Open", Kind: SymbolFunction, FilePath: "internal/store/store.go", StartLine: 10, EndLine: 12, Language: "go"},
+		{Name: "Store", Kind: SymbolStruct, FilePath: "internal/store/store.go", StartLine: 3, EndLine: 5, Language: "go"},
+		{Name: "Query", Kind: SymbolFunction, FilePath: "internal/store/query.go", StartLine: 1, EndLine: 4, Language: "go"},
+		{Name: "Migrate", Kind: SymbolFunction, FilePath: "internal/store/migrations/migrate.go", StartLine: 1, EndLine: 4, Language: "go"},
+		{Name: "Serve", Kind: SymbolFunction, FilePath: "internal/storefront/serve.go", StartLine: 1, EndLine: 4, Language: "go"},
+	} {
+		if _, err := repo.UpsertSymbol(ctx, &s); err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+	}
+
+	tree, err := NewQueryService(repo, llm.Config{}).GetSymbolsInModule(ctx, "internal/store/")
+	if err != nil {
+		t.Fatalf("GetSymbolsInModule: %v", err)
+	}
+	if tree.ModulePath != "internal/store" || tree.SymbolCount != 3 {
+		t.Errorf("tree = %s with %d symbols, want internal/store with 3", tree.ModulePath, tree.SymbolCount)
+	}
+	if len(tree.Files) != 2 {
+		t.Fatalf("files = %+v, want query.go and store.go only", tree.Files)
+	}
+	if f := tree.Files[0]; f.FilePath != "internal/store/query.go" || f.Count != 1 {
+		t.Errorf("first file = %s (%d), want query.go (1)", f.FilePath, f.Count)
+	}
+	f := tree.Files[1]
+	if f.FilePath != "internal/store/store.go" || f.Count != 2 || f.Symbols[0].Name != "Store" {
+		t.Errorf("second file = %s (%d) %v, want store.go with Store before Open", f.FilePath, f.Count, f.Symbols)
+	}
+}
+
+func TestIndexer_LinksImplementationsThroughEmbedding(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod": "module example.com/files\n",
+		"io.go": `package files
+
+type Closer interface {
+	Close() error
+}
+
+type ReadCloser interface {
+	Closer
+	Read(p []byte) (int, error)
+}
+`,
+		"file.go": `package files
+
+type File struct{}
+
+func (f *File) Read(p []byte) (int, error) { return 0, nil }
+func (f *File) Close() error               { return nil }
+
+// LoggedFile gets Read and Close from the embedded File
+type LoggedFile struct {
+	*File
+}
+
+type Buffer struct{}
+
+func (b *Buffer) Read(p []byte) (int, error) { return 0, nil }
+`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+	stats, err := NewIndexer(repo, DefaultIndexerConfig()).IndexDirectory(ctx, dir)
+	if err != nil || len(stats.Errors) > 0 {
+		t.Fatalf("IndexDirectory: %v %v", err, stats.Errors)
+	}
+
+	qs := NewQueryService(repo, llm.Config{})
+	symbolID := func(name string) uint32 {
+		syms, err := qs.FindSymbolByName(ctx, name)
+		if err != nil || len(syms) == 0 {
+			t.Fatalf("symbol %s not indexed: %v", name, err)
+		}
+		return syms[0].ID
+	}
+	names := func(syms []Symbol) []string {
+		var out []string
+		for _, s := range syms {
+			out = append(out, s.Name)
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	direct, err := qs.GetImplementations(ctx, symbolID("ReadCloser"))
+	if err != nil {
+		t.Fatalf("GetImplementations: %v", err)
+	}
+	if got := names(direct); !slices.Equal(got, []string{"File"}) {
+		t.Errorf("ReadCloser implementations = %v, want [File] (Buffer lacks Close)", got)
+	}
+
+	impls, err := qs.GetImplementationsTransitive(ctx, symbolID("Closer"), 0)
+	if err != nil {
+		t.Fatalf("GetImplementationsTransitive: %v", err)
+	}
+	if got := names(impls); !slices.Equal(got, []string{"File", "LoggedFile"}) {
+		t.Errorf("Closer transitive implementations = %v, want [File LoggedFile]", got)
+	}
+}
+
+func TestMatchesImpactExclude(t *testing.T) {
+	patterns := DefaultImpactExcludePatterns()
+	tests := map[string]bool{
+		"vendor/github.com/acme/lib/wrap.go": true,
+		"web/node_modules/react/index.js":    true,
+		"api/v1/service.pb.go":               true,
+		"internal/vendors/list.go":           false,
+		"internal/api/handler.go":            false,
+	}
+	for path, want := range tests {
+		if got := matchesImpactExclude(path, patterns); got != want {
+			t.Errorf("matchesImpactExclude(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestRepository_UpdateSymbolFTSRefreshesOnlyGivenRows(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+
+	ids := make(map[string]uint32)
+	for _, s := range []Symbol{
+		{Name: "OpenLedger", Kind: SymbolFunction, FilePath: "ledger/open.go", StartLine: 1, EndLine: 3, Language: "go"},
+		{Name: "CloseLedger", Kind: SymbolFunction, FilePath: "ledger/close.go", StartLine: 1, EndLine: 3, Language: "go"},
+	} {
+		id, err := repo.UpsertSymbol(ctx, &s)
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+		ids[s.Name] = id
+	}
+	if err := repo.RebuildSymbolsFTS(ctx); err != nil {
+		t.Fatalf("RebuildSymbolsFTS: %v", err)
+	}
+
+	// Drop both entries, as if symbols_fts had been cleared under them
+	for _, id := range ids {
+		if _, err := store.DB().ExecContext(ctx, `
+			INSERT INTO symbols_fts(symbols_fts, rowid, name, signature, doc_comment, module_path)
+			SELECT 'delete', id, name, COALESCE(signature, ''), COALESCE(doc_comment, ''), COALESCE(module_path, '')
+			FROM symbols WHERE id = ?`, id); err != nil {
+			t.Fatalf("drop FTS entry: %v", err)
+		}
+	}
+	found := func(name string) bool {
+		t.Helper()
+		results, err := repo.SearchSymbolsFTS(ctx, name, 10, SymbolFilter{})
+		if err != nil {
+			t.Fatalf("SearchSymbolsFTS %s: %v", name, err)
+		}
+		return len(results) > 0
+	}
+	if found("OpenLedger") || found("CloseLedger") {
+		t.Fatal("entries should be gone before the update")
+	}
+
+	if err := repo.UpdateSymbolFTS(ctx, []uint32{ids["OpenLedger"]}); err != nil {
+		t.Fatalf("UpdateSymbolFTS: %v", err)
+	}
+	if !found("OpenLedger") {
+		t.Error("updated symbol should be searchable")
+	}
+	if found("CloseLedger") {
+		t.Error("symbols outside the update should be untouched")
+	}
+
+	// Refreshing an entry that is already indexed must not duplicate it
+	if err := repo.UpdateSymbolFTS(ctx, []uint32{ids["OpenLedger"], ids["CloseLedger"]}); err != nil {
+		t.Fatalf("UpdateSymbolFTS: %v", err)
+	}
+	if _, err := store.DB().ExecContext(ctx, `INSERT INTO symbols_fts(symbols_fts, rank) VALUES('integrity-check', 1)`); err != nil {
+		t.Errorf("symbols_fts out of sync after update: %v", err)
+	}
+}
+
+func TestIndexer_IncrementalIndexRefreshesChangedSymbolsFTS(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	ledgerPath := filepath.Join(dir, "ledger.go")
+	for name, content := range map[string]string{
+		"go.mod":    "module example.com/ledger\n",
+		"ledger.go": "package ledger\n\nfunc OpenLedger() {}\n",
+		"close.go":  "package ledger\n\nfunc CloseLedger() {}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store.DB())
+	indexer := NewIndexer(repo, DefaultIndexerConfig())
+	if _, err := indexer.IndexDirectory(ctx, dir); err != nil {
+		t.Fatalf("IndexDirectory: %v", err)
+	}
+
+	if err := os.WriteFile(ledgerPath, []byte("package ledger\n\nfunc ReopenLedger() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := indexer.IncrementalIndex(ctx, dir)
+	if err != nil {
+		t.Fatalf("IncrementalIndex: %v", err)
+	}
+	if len(stats.Errors) > 0 || stats.FilesIndexed != 1 {
+		t.Fatalf("IncrementalIndex indexed %d files, errors %v; want only ledger.go", stats.FilesIndexed, stats.Errors)
+	}
+
+	for name, want := range map[string]bool{"ReopenLedger": true, "OpenLedger": false, "CloseLedger": true} {
+		results, err := repo.SearchSymbolsFTS(ctx, name, 10, SymbolFilter{})
+		if err != nil {
+			t.Fatalf("SearchSymbolsFTS %s: %v", name, err)
+		}
+		if got := slices.ContainsFunc(results, func(r Symbol) bool { return r.Name == name }); got != want {
+			t.Errorf("%s searchable = %v, want %v", name, got, want)
+		}
+	}
+	if _, err := store.DB().ExecContext(ctx, `INSERT INTO symbols_fts(symbols_fts, rank) VALUES('integrity-check', 1)`); err != nil {
+		t.Errorf("symbols_fts out of sync after incremental index: %v", err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -24,18 +26,30 @@ type Repository interface {
 	// Symbol query operations
 	FindSymbolsByName(ctx context.Context, name string, lang *string) ([]Symbol, error)
 	FindSymbolsByFile(ctx context.Context, filePath string) ([]Symbol, error)
-	SearchSymbolsFTS(ctx context.Context, query string, limit int) ([]Symbol, error)
+	FindSymbolsInDir(ctx context.Context, dir string) ([]Symbol, error)
+	FindSymbolsByKind(ctx context.Context, language string, kinds ...SymbolKind) ([]Symbol, error)
+	SearchSymbolsFTS(ctx context.Context, query string, limit int, filter SymbolFilter) ([]Symbol, error)
+	SearchSymbolsBySignature(ctx context.Context, pattern string, limit int, filter SymbolFilter) ([]Symbol, error)
 	ListSymbolsWithEmbeddings(ctx context.Context) ([]Symbol, error)
 
 	// Relation CRUD operations
 	UpsertRelation(ctx context.Context, r *SymbolRelation) error
 	DeleteRelationsBySymbol(ctx context.Context, symbolID uint32) error
+	ReplaceImplementations(ctx context.Context, language string, rels []SymbolRelation) error
 
 	// Relation query operations (for call graph traversal)
 	GetCallers(ctx context.Context, symbolID uint32) ([]Symbol, error)
 	GetCallees(ctx context.Context, symbolID uint32) ([]Symbol, error)
+	GetUsages(ctx context.Context, symbolID uint32) ([]SymbolUsage, error)
 	GetImplementations(ctx context.Context, interfaceID uint32) ([]Symbol, error)
-	GetImpactRadius(ctx context.Context, symbolID uint32, maxDepth int) ([]ImpactNode, error)
+	GetTransitiveImplementations(ctx context.Context, interfaceID uint32, maxDepth int) ([]Symbol, error)
+	GetImpactRadius(ctx context.Context, symbolID uint32, maxDepth int, exclude func(filePath string) bool) ([]ImpactNode, error)
+	GetPublicSymbolsByCallers(ctx context.Context, modulePath string) ([]SymbolFanIn, error)
+	GetHighFanInSymbols(ctx context.Context, threshold int) ([]SymbolFanIn, error)
+
+	// File import operations
+	ReplaceFileImports(ctx context.Context, filePath string, imports []FileImport) error
+	GetFileImports(ctx context.Context, filePath string) ([]FileImport, error)
 
 	// Statistics
 	GetSymbolCount(ctx context.Context) (int, error)
@@ -50,6 +64,7 @@ type Repository interface {
 
 	// Maintenance
 	RebuildSymbolsFTS(ctx context.Context) error
+	UpdateSymbolFTS(ctx context.Context, symbolIDs []uint32) error
 
 	// C5 FIX: Atomic clear operation to avoid race conditions
 	ClearAllSymbols(ctx context.Context) error
@@ -80,10 +95,6 @@ func NewRepository(db *sql.DB) *SQLiteRepository {
 // UpsertSymbol creates or updates a symbol, returning its ID.
 // Uses atomic INSERT ... ON CONFLICT for thread-safety during concurrent indexing.
 func (r *SQLiteRepository) UpsertSymbol(ctx context.Context, s *Symbol) (uint32, error) {
-	if s.LastModified.IsZero() {
-		s.LastModified = time.Now().UTC()
-	}
-
 	var embeddingBytes []byte
 	if len(s.Embedding) > 0 {
 		embeddingBytes = float32SliceToBytes(s.Embedding)
@@ -174,12 +185,15 @@ func (r *SQLiteRepository) DeleteSymbol(ctx context.Context, id uint32) error {
 	return nil
 }
 
-// DeleteSymbolsByFile removes all symbols from a file.
+// DeleteSymbolsByFile removes all symbols and imports recorded for a file.
 func (r *SQLiteRepository) DeleteSymbolsByFile(ctx context.Context, filePath string) error {
 	_, err := r.db.ExecContext(ctx, "DELETE FROM symbols WHERE file_path = ?", filePath)
 	if err != nil {
 		return fmt.Errorf("delete symbols by file: %w", err)
 	}
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM file_imports WHERE file_path = ?", filePath); err != nil {
+		return fmt.Errorf("delete file imports: %w", err)
+	}
 	return nil
 }
 
@@ -239,9 +253,109 @@ func (r *SQLiteRepository) FindSymbolsByFile(ctx context.Context, filePath strin
 	return scanSymbols(rows)
 }
 
-// SearchSymbolsFTS performs full-text search on symbols.
+// FindSymbolsByKind returns the symbols of a language with any of the given
+// kinds, ordered by file and position.
+func (r *SQLiteRepository) FindSymbolsByKind(ctx context.Context, language string, kinds ...SymbolKind) ([]Symbol, error) {
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+	args := []any{language}
+	for _, k := range kinds {
+		args = append(args, k)
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, kind, file_path, start_line, end_line, signature, doc_comment,
+		       module_path, visibility, language, file_hash, last_modified
+		FROM symbols WHERE language = ? AND kind IN (?`+strings.Repeat(", ?", len(kinds)-1)+`)
+		ORDER BY file_path, start_line
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols by kind: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanSymbols(rows)
+}
+
+// FindSymbolsInDir returns all symbols in files directly inside dir (not its
+// subdirectories), ordered by file and position. An empty dir or "." means the
+// project root.
+func (r *SQLiteRepository) FindSymbolsInDir(ctx context.Context, dir string) ([]Symbol, error) {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	pattern := "%"
+	if dir != "." {
+		pattern = likeEscaper.Replace(dir) + "/%"
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, kind, file_path, start_line, end_line, signature, doc_comment,
+		       module_path, visibility, language, file_hash, last_modified
+		FROM symbols WHERE file_path LIKE ? ESCAPE '\'
+		ORDER BY file_path, start_line
+	`, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols by dir: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	symbols, err := scanSymbols(rows)
+	if err != nil {
+		return nil, err
+	}
+	direct := symbols[:0]
+	for _, s := range symbols {
+		if path.Dir(filepath.ToSlash(s.FilePath)) == dir {
+			direct = append(direct, s)
+		}
+	}
+	return direct, nil
+}
+
+// likeEscaper escapes LIKE wildcards for patterns used with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SymbolFilter narrows a symbol search inside the query, so filtered symbols
+// never take a slot under its LIMIT. The zero value matches every symbol.
+type SymbolFilter struct {
+	ExcludePrivate bool       // Only public symbols
+	Kind           SymbolKind // Only symbols of this kind
+	FilePath       string     // Only symbols declared in this file
+	Language       string     // Only symbols of this language, case-insensitive
+}
+
+// where returns the filter as SQL conditions on the symbols table alias,
+// each prefixed with AND, and their arguments.
+func (f SymbolFilter) where(alias string) (string, []any) {
+	var sb strings.Builder
+	var args []any
+	if f.ExcludePrivate {
+		sb.WriteString(" AND " + alias + ".visibility = 'public'")
+	}
+	if f.Kind != "" {
+		sb.WriteString(" AND " + alias + ".kind = ?")
+		args = append(args, string(f.Kind))
+	}
+	if f.FilePath != "" {
+		sb.WriteString(" AND " + alias + ".file_path = ?")
+		args = append(args, f.FilePath)
+	}
+	if f.Language != "" {
+		sb.WriteString(" AND " + alias + ".language = ? COLLATE NOCASE")
+		args = append(args, f.Language)
+	}
+	return sb.String(), args
+}
+
+// Matches reports whether s passes the filter.
+func (f SymbolFilter) Matches(s *Symbol) bool {
+	return (!f.ExcludePrivate || s.IsExported()) &&
+		(f.Kind == "" || s.Kind == f.Kind) &&
+		(f.FilePath == "" || s.FilePath == f.FilePath) &&
+		(f.Language == "" || strings.EqualFold(s.Language, f.Language))
+}
+
+// SearchSymbolsFTS performs full-text search on symbols matching filter.
 // C3 FIX: Sanitizes query to prevent FTS5 syntax errors and injection attacks.
-func (r *SQLiteRepository) SearchSymbolsFTS(ctx context.Context, query string, limit int) ([]Symbol, error) {
+func (r *SQLiteRepository) SearchSymbolsFTS(ctx context.Context, query string, limit int, filter SymbolFilter) ([]Symbol, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -253,16 +367,18 @@ func (r *SQLiteRepository) SearchSymbolsFTS(ctx context.Context, query string, l
 		return nil, nil // Empty query returns no results
 	}
 
+	conds, filterArgs := filter.where("s")
+	args := append([]any{sanitizedQuery}, filterArgs...)
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT s.id, s.name, s.kind, s.file_path, s.start_line, s.end_line,
 		       s.signature, s.doc_comment, s.module_path, s.visibility, s.language,
 		       s.file_hash, s.last_modified
 		FROM symbols_fts f
 		JOIN symbols s ON f.rowid = s.id
-		WHERE symbols_fts MATCH ?
+		WHERE symbols_fts MATCH ?`+conds+`
 		ORDER BY bm25(symbols_fts)
 		LIMIT ?
-	`, sanitizedQuery, limit)
+	`, append(args, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("FTS search: %w", err)
 	}
@@ -271,6 +387,52 @@ func (r *SQLiteRepository) SearchSymbolsFTS(ctx context.Context, query string, l
 	return scanSymbols(rows)
 }
 
+// SearchSymbolsBySignature finds symbols whose signature contains the
+// comma-separated fragments of pattern in order, so "context.Context, string"
+// matches "func(ctx context.Context, name string) error". Shorter signatures
+// (closer matches) come first. Only symbols matching filter are returned.
+func (r *SQLiteRepository) SearchSymbolsBySignature(ctx context.Context, pattern string, limit int, filter SymbolFilter) ([]Symbol, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	like := signatureLikePattern(pattern)
+	if like == "" {
+		return nil, nil
+	}
+
+	conds, filterArgs := filter.where("symbols")
+	args := append([]any{like}, filterArgs...)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, kind, file_path, start_line, end_line, signature, doc_comment,
+		       module_path, visibility, language, file_hash, last_modified
+		FROM symbols WHERE signature LIKE ? ESCAPE '\'`+conds+`
+		ORDER BY length(signature), file_path, start_line
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols by signature: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanSymbols(rows)
+}
+
+// signatureLikePattern turns "context.Context, string" into the LIKE pattern
+// "%context.Context%string%", escaping LIKE wildcards in the fragments.
+func signatureLikePattern(pattern string) string {
+	var sb strings.Builder
+	for _, frag := range strings.Split(pattern, ",") {
+		if frag = strings.TrimSpace(frag); frag != "" {
+			sb.WriteString("%" + likeEscaper.Replace(frag))
+		}
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return sb.String() + "%"
+}
+
 // ListSymbolsWithEmbeddings returns all symbols that have embeddings.
 func (r *SQLiteRepository) ListSymbolsWithEmbeddings(ctx context.Context) ([]Symbol, error) {
 	rows, err := r.db.QueryContext(ctx, `
@@ -312,6 +474,33 @@ func (r *SQLiteRepository) UpsertRelation(ctx context.Context, rel *SymbolRelati
 	return nil
 }
 
+// ReplaceImplementations swaps every implements relation from a symbol of the
+// given language for rels in one transaction.
+func (r *SQLiteRepository) ReplaceImplementations(ctx context.Context, language string, rels []SymbolRelation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM symbol_relations
+		WHERE relation_type = 'implements'
+		  AND from_symbol_id IN (SELECT id FROM symbols WHERE language = ?)
+	`, language); err != nil {
+		return fmt.Errorf("delete implementations: %w", err)
+	}
+	for _, rel := range rels {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR REPLACE INTO symbol_relations (from_symbol_id, to_symbol_id, relation_type, call_site_line)
+			VALUES (?, ?, 'implements', 0)
+		`, rel.FromSymbolID, rel.ToSymbolID); err != nil {
+			return fmt.Errorf("insert implementation: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
 // DeleteRelationsBySymbol removes all relations involving a symbol.
 func (r *SQLiteRepository) DeleteRelationsBySymbol(ctx context.Context, symbolID uint32) error {
 	_, err := r.db.ExecContext(ctx, `
@@ -344,6 +533,69 @@ func (r *SQLiteRepository) GetCallers(ctx context.Context, symbolID uint32) ([]S
 	return scanSymbols(rows)
 }
 
+// GetPublicSymbolsByCallers returns public symbols with the number of distinct
+// symbols that call each one, most-called first. Symbols without callers are
+// included with a zero count. An empty modulePath matches every module.
+func (r *SQLiteRepository) GetPublicSymbolsByCallers(ctx context.Context, modulePath string) ([]SymbolFanIn, error) {
+	return r.symbolsByCallerCount(ctx,
+		"s.visibility = 'public' AND s.kind != 'package' AND (? = '' OR s.module_path = ?)",
+		0, modulePath, modulePath)
+}
+
+// GetHighFanInSymbols returns symbols called by more than threshold distinct
+// symbols, most-called first.
+func (r *SQLiteRepository) GetHighFanInSymbols(ctx context.Context, threshold int) ([]SymbolFanIn, error) {
+	return r.symbolsByCallerCount(ctx, "s.kind != 'package'", threshold+1)
+}
+
+// symbolsByCallerCount counts distinct callers per symbol matching where and
+// returns those with at least minCallers, ordered by caller count descending.
+func (r *SQLiteRepository) symbolsByCallerCount(ctx context.Context, where string, minCallers int, args ...any) ([]SymbolFanIn, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.id, s.name, s.kind, s.file_path, s.start_line, s.end_line,
+		       s.signature, s.doc_comment, s.module_path, s.visibility, s.language,
+		       s.file_hash, s.last_modified,
+		       COUNT(DISTINCT sr.from_symbol_id) AS caller_count
+		FROM symbols s
+		LEFT JOIN symbol_relations sr
+		       ON sr.to_symbol_id = s.id AND sr.relation_type = 'calls'
+		WHERE `+where+`
+		GROUP BY s.id
+		HAVING caller_count >= ?
+		ORDER BY caller_count DESC, s.file_path, s.start_line
+	`, append(args, minCallers)...)
+	if err != nil {
+		return nil, fmt.Errorf("query caller counts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []SymbolFanIn
+	for rows.Next() {
+		var a SymbolFanIn
+		var signature, docComment, module, fileHash sql.NullString
+		var lastModified string
+
+		err := rows.Scan(&a.Symbol.ID, &a.Symbol.Name, &a.Symbol.Kind, &a.Symbol.FilePath,
+			&a.Symbol.StartLine, &a.Symbol.EndLine, &signature, &docComment, &module,
+			&a.Symbol.Visibility, &a.Symbol.Language, &fileHash, &lastModified, &a.CallerCount)
+		if err != nil {
+			return nil, fmt.Errorf("scan caller count: %w", err)
+		}
+
+		a.Symbol.Signature = signature.String
+		a.Symbol.DocComment = docComment.String
+		a.Symbol.ModulePath = module.String
+		a.Symbol.FileHash = fileHash.String
+		a.Symbol.LastModified, _ = time.Parse(time.RFC3339, lastModified)
+
+		result = append(result, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return result, nil
+}
+
 // GetCallees returns all symbols called by the given symbol.
 func (r *SQLiteRepository) GetCallees(ctx context.Context, symbolID uint32) ([]Symbol, error) {
 	rows, err := r.db.QueryContext(ctx, `
@@ -363,6 +615,52 @@ func (r *SQLiteRepository) GetCallees(ctx context.Context, symbolID uint32) ([]S
 	return scanSymbols(rows)
 }
 
+// GetUsages returns every symbol with a relation to the given symbol, of any
+// kind, ordered by location.
+func (r *SQLiteRepository) GetUsages(ctx context.Context, symbolID uint32) ([]SymbolUsage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.id, s.name, s.kind, s.file_path, s.start_line, s.end_line,
+		       s.signature, s.doc_comment, s.module_path, s.visibility, s.language,
+		       s.file_hash, s.last_modified,
+		       sr.relation_type, COALESCE(sr.call_site_line, 0)
+		FROM symbol_relations sr
+		JOIN symbols s ON sr.from_symbol_id = s.id
+		WHERE sr.to_symbol_id = ?
+		ORDER BY s.file_path, s.start_line, sr.relation_type
+	`, symbolID)
+	if err != nil {
+		return nil, fmt.Errorf("query usages: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []SymbolUsage
+	for rows.Next() {
+		var u SymbolUsage
+		var signature, docComment, module, fileHash sql.NullString
+		var lastModified string
+
+		err := rows.Scan(&u.Symbol.ID, &u.Symbol.Name, &u.Symbol.Kind, &u.Symbol.FilePath,
+			&u.Symbol.StartLine, &u.Symbol.EndLine, &signature, &docComment, &module,
+			&u.Symbol.Visibility, &u.Symbol.Language, &fileHash, &lastModified,
+			&u.Relation, &u.Line)
+		if err != nil {
+			return nil, fmt.Errorf("scan usage: %w", err)
+		}
+
+		u.Symbol.Signature = signature.String
+		u.Symbol.DocComment = docComment.String
+		u.Symbol.ModulePath = module.String
+		u.Symbol.FileHash = fileHash.String
+		u.Symbol.LastModified, _ = time.Parse(time.RFC3339, lastModified)
+
+		result = append(result, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return result, nil
+}
+
 // GetImplementations returns all types that implement the given interface.
 func (r *SQLiteRepository) GetImplementations(ctx context.Context, interfaceID uint32) ([]Symbol, error) {
 	rows, err := r.db.QueryContext(ctx, `
@@ -382,19 +680,89 @@ func (r *SQLiteRepository) GetImplementations(ctx context.Context, interfaceID u
 	return scanSymbols(rows)
 }
 
+// GetTransitiveImplementations returns all types that implement the given
+// interface or an interface embedding it (an "extends" relation from one
+// interface to another), plus the types embedding an implementation and so
+// inheriting its methods. Each kind of embedding is followed up to maxDepth
+// hops.
+func (r *SQLiteRepository) GetTransitiveImplementations(ctx context.Context, interfaceID uint32, maxDepth int) ([]Symbol, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH RECURSIVE ifaces(id, depth) AS (
+			SELECT ?, 0
+
+			UNION
+
+			-- Interfaces embedding an interface already in the set
+			SELECT sr.from_symbol_id, i.depth + 1
+			FROM symbol_relations sr
+			JOIN ifaces i ON sr.to_symbol_id = i.id
+			JOIN symbols e ON e.id = sr.from_symbol_id
+			WHERE sr.relation_type = 'extends' AND e.kind = 'interface' AND i.depth < ?
+		),
+		impls(id, depth) AS (
+			SELECT sr.from_symbol_id, 0
+			FROM symbol_relations sr
+			WHERE sr.to_symbol_id IN (SELECT id FROM ifaces) AND sr.relation_type = 'implements'
+
+			UNION
+
+			-- Concrete types embedding an implementation already in the set
+			SELECT sr.from_symbol_id, i.depth + 1
+			FROM symbol_relations sr
+			JOIN impls i ON sr.to_symbol_id = i.id
+			JOIN symbols e ON e.id = sr.from_symbol_id
+			WHERE sr.relation_type = 'extends' AND e.kind != 'interface' AND i.depth < ?
+		)
+		SELECT s.id, s.name, s.kind, s.file_path, s.start_line, s.end_line,
+		       s.signature, s.doc_comment, s.module_path, s.visibility, s.language,
+		       s.file_hash, s.last_modified
+		FROM symbols s
+		WHERE s.id IN (SELECT id FROM impls)
+		ORDER BY s.file_path, s.start_line
+	`, interfaceID, maxDepth, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("query transitive implementations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanSymbols(rows)
+}
+
 // GetImpactRadius finds all symbols affected by changing the given symbol.
 // Uses recursive CTE to traverse the call graph up to maxDepth levels.
-func (r *SQLiteRepository) GetImpactRadius(ctx context.Context, symbolID uint32, maxDepth int) ([]ImpactNode, error) {
+// Symbols in files for which exclude returns true are neither reported nor traversed;
+// a nil exclude includes everything.
+func (r *SQLiteRepository) GetImpactRadius(ctx context.Context, symbolID uint32, maxDepth int, exclude func(filePath string) bool) ([]ImpactNode, error) {
 	if maxDepth <= 0 {
 		maxDepth = 5
 	}
 
+	excludedFiles := "[]"
+	if exclude != nil {
+		files, err := r.symbolFilesWhere(ctx, exclude)
+		if err != nil {
+			return nil, fmt.Errorf("resolve excluded files: %w", err)
+		}
+		if len(files) > 0 {
+			data, err := json.Marshal(files)
+			if err != nil {
+				return nil, fmt.Errorf("encode excluded files: %w", err)
+			}
+			excludedFiles = string(data)
+		}
+	}
+
 	rows, err := r.db.QueryContext(ctx, `
-		WITH RECURSIVE impact AS (
+		WITH RECURSIVE excluded AS (
+			SELECT id FROM symbols
+			WHERE file_path IN (SELECT value FROM json_each(?))
+		),
+		impact AS (
 			-- Base case: direct callers of the target symbol
 			SELECT from_symbol_id as id, 1 as depth, relation_type as rel
 			FROM symbol_relations
 			WHERE to_symbol_id = ?
+			  AND from_symbol_id NOT IN (SELECT id FROM excluded)
 
 			UNION ALL
 
@@ -403,6 +771,7 @@ func (r *SQLiteRepository) GetImpactRadius(ctx context.Context, symbolID uint32,
 			FROM symbol_relations sr
 			JOIN impact i ON sr.to_symbol_id = i.id
 			WHERE i.depth < ?
+			  AND sr.from_symbol_id NOT IN (SELECT id FROM excluded)
 		)
 		SELECT DISTINCT s.id, s.name, s.kind, s.file_path, s.start_line, s.end_line,
 		       s.signature, s.doc_comment, s.module_path, s.visibility, s.language,
@@ -410,7 +779,7 @@ func (r *SQLiteRepository) GetImpactRadius(ctx context.Context, symbolID uint32,
 		FROM impact i
 		JOIN symbols s ON s.id = i.id
 		ORDER BY i.depth, s.file_path
-	`, symbolID, maxDepth)
+	`, excludedFiles, symbolID, maxDepth)
 	if err != nil {
 		return nil, fmt.Errorf("impact analysis: %w", err)
 	}
@@ -450,6 +819,57 @@ func (r *SQLiteRepository) GetImpactRadius(ctx context.Context, symbolID uint32,
 	return results, nil
 }
 
+// === File Import Operations ===
+
+// ReplaceFileImports replaces the stored imports of a file with the given set.
+func (r *SQLiteRepository) ReplaceFileImports(ctx context.Context, filePath string, imports []FileImport) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM file_imports WHERE file_path = ?", filePath); err != nil {
+		return fmt.Errorf("delete file imports: %w", err)
+	}
+	for _, imp := range imports {
+		_, err := tx.ExecContext(ctx, `
+			INSERT OR REPLACE INTO file_imports (file_path, import_path, category, line)
+			VALUES (?, ?, ?, ?)
+		`, filePath, imp.Path, imp.Category, imp.Line)
+		if err != nil {
+			return fmt.Errorf("insert file import %s: %w", imp.Path, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetFileImports returns the imports of a file in source order.
+func (r *SQLiteRepository) GetFileImports(ctx context.Context, filePath string) ([]FileImport, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT file_path, import_path, category, COALESCE(line, 0)
+		FROM file_imports WHERE file_path = ?
+		ORDER BY line, import_path
+	`, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("query file imports: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var imports []FileImport
+	for rows.Next() {
+		var imp FileImport
+		if err := rows.Scan(&imp.FilePath, &imp.Path, &imp.Category, &imp.Line); err != nil {
+			return nil, fmt.Errorf("scan file import: %w", err)
+		}
+		imports = append(imports, imp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return imports, nil
+}
+
 // === Statistics ===
 
 // GetSymbolCount returns the total number of indexed symbols.
@@ -551,27 +971,32 @@ func (r *SQLiteRepository) GetSymbolStats(ctx context.Context) (*SymbolStats, er
 // GetStaleSymbolFiles returns file paths that have symbols indexed but no longer exist.
 // The checkPath function should return true if the file exists.
 func (r *SQLiteRepository) GetStaleSymbolFiles(ctx context.Context, checkPath func(string) bool) ([]string, error) {
+	return r.symbolFilesWhere(ctx, func(path string) bool { return !checkPath(path) })
+}
+
+// symbolFilesWhere returns the indexed file paths for which match returns true.
+func (r *SQLiteRepository) symbolFilesWhere(ctx context.Context, match func(string) bool) ([]string, error) {
 	rows, err := r.db.QueryContext(ctx, "SELECT DISTINCT file_path FROM symbols ORDER BY file_path")
 	if err != nil {
 		return nil, fmt.Errorf("query files: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
-	var staleFiles []string
+	var files []string
 	for rows.Next() {
 		var filePath string
 		if err := rows.Scan(&filePath); err != nil {
 			continue
 		}
-		if !checkPath(filePath) {
-			staleFiles = append(staleFiles, filePath)
+		if match(filePath) {
+			files = append(files, filePath)
 		}
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate rows: %w", err)
 	}
 
-	return staleFiles, nil
+	return files, nil
 }
 
 // === Embedding Operations ===
@@ -624,6 +1049,10 @@ func (r *SQLiteRepository) RebuildSymbolsFTS(ctx context.Context) error {
 		return fmt.Errorf("clear symbols_fts: %w", err)
 	}
 
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM file_imports"); err != nil {
+		return fmt.Errorf("clear file_imports: %w", err)
+	}
+
 	// Repopulate from symbols table
 	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO symbols_fts(rowid, name, signature, doc_comment, module_path)
@@ -637,6 +1066,60 @@ func (r *SQLiteRepository) RebuildSymbolsFTS(ctx context.Context) error {
 	return nil
 }
 
+// UpdateSymbolFTS re-syncs the FTS5 entries of the given symbols only, a
+// cheap alternative to RebuildSymbolsFTS when a few rows lost their entries
+// (e.g. written while symbols_fts was cleared). Normal writes need no call:
+// the symbols triggers keep symbols_fts in sync. IDs with no symbol are
+// skipped; the delete trigger already removed their entries.
+func (r *SQLiteRepository) UpdateSymbolFTS(ctx context.Context, symbolIDs []uint32) error {
+	if len(symbolIDs) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, id := range symbolIDs {
+		var name string
+		err := tx.QueryRowContext(ctx, "SELECT name FROM symbols WHERE id = ?", id).Scan(&name)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("get symbol %d: %w", id, err)
+		}
+
+		// Only drop an entry the index actually holds: deleting one that was
+		// never indexed corrupts an external-content FTS table
+		var indexed bool
+		if err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM symbols_fts WHERE symbols_fts MATCH ? AND rowid = ?)
+		`, `name:"`+strings.ReplaceAll(name, `"`, `""`)+`"`, id).Scan(&indexed); err != nil {
+			return fmt.Errorf("check symbols_fts entry %d: %w", id, err)
+		}
+		if indexed {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO symbols_fts(symbols_fts, rowid, name, signature, doc_comment, module_path)
+				SELECT 'delete', id, name, COALESCE(signature, ''), COALESCE(doc_comment, ''), COALESCE(module_path, '')
+				FROM symbols WHERE id = ?
+			`, id); err != nil {
+				return fmt.Errorf("remove symbols_fts entry %d: %w", id, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO symbols_fts(rowid, name, signature, doc_comment, module_path)
+			SELECT id, name, COALESCE(signature, ''), COALESCE(doc_comment, ''), COALESCE(module_path, '')
+			FROM symbols WHERE id = ?
+		`, id); err != nil {
+			return fmt.Errorf("update symbols_fts entry %d: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
 // ClearAllSymbols atomically removes all symbols and relations.
 // C5 FIX: Uses single DELETE statements instead of fetch-then-delete-one-by-one
 // to avoid race conditions with concurrent indexing operations.
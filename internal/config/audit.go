@@ -0,0 +1,18 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// LoadAuditCommands returns the shell commands a plan audit runs to build and
+// test the project. When neither is set, the audit detects them from the
+// project manifest (go.mod, Cargo.toml or package.json):
+//
+//	audit:
+//	  build_command: "make build"
+//	  test_command: "make test"
+func LoadAuditCommands() (build, test string) {
+	return strings.TrimSpace(viper.GetString("audit.build_command")), strings.TrimSpace(viper.GetString("audit.test_command"))
+}
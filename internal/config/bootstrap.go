@@ -0,0 +1,57 @@
+package config
+
+import (
+	"github.com/josephgoksu/TaskWing/internal/utils"
+	"github.com/spf13/viper"
+)
+
+// LoadExcludeFilePatterns returns glob patterns for files that bootstrap analysis skips.
+// Projects can override the defaults via .taskwing.yaml:
+//
+//	bootstrap:
+//	  exclude_files:
+//	    - "*.gen.go"
+//	    - "internal/legacy/*.go"
+//
+// Falls back to utils.GeneratedFilePatterns when unset.
+func LoadExcludeFilePatterns() []string {
+	if viper.IsSet("bootstrap.exclude_files") {
+		return viper.GetStringSlice("bootstrap.exclude_files")
+	}
+	return append([]string(nil), utils.GeneratedFilePatterns...)
+}
+
+// LoadIndexGitBlame reports whether code indexing dates symbols with git
+// blame. Off by default since it runs git once per indexed file:
+//
+//	bootstrap:
+//	  git_blame: true
+func LoadIndexGitBlame() bool {
+	return viper.GetBool("bootstrap.git_blame")
+}
+
+// DefaultGitignoreEntries keeps the local state older TaskWing versions wrote
+// to <project>/.taskwing (the memory database and version stamp, which now
+// live in the global project store) out of git. Shareable files (prompts,
+// policies, conventions) stay tracked.
+var DefaultGitignoreEntries = []string{
+	"memory/",
+	"version",
+}
+
+// LoadGitignoreEntries returns the patterns bootstrap adds to
+// .taskwing/.gitignore. Projects can replace them, e.g. to also keep
+// plans they export there out of git, or set an empty list to skip the file:
+//
+//	bootstrap:
+//	  gitignore:
+//	    - "memory/"
+//	    - "plans/"
+//
+// Falls back to DefaultGitignoreEntries when unset.
+func LoadGitignoreEntries() []string {
+	if viper.IsSet("bootstrap.gitignore") {
+		return viper.GetStringSlice("bootstrap.gitignore")
+	}
+	return append([]string(nil), DefaultGitignoreEntries...)
+}
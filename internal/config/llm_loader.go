@@ -108,15 +108,25 @@ func ParseModelSpec(spec string, role llm.ModelRole) (llm.Config, error) {
 	if err != nil {
 		return llm.Config{}, err
 	}
+	agentTimeout, err := ResolveAgentTimeout()
+	if err != nil {
+		return llm.Config{}, err
+	}
+	requestsPerMinute, err := ResolveRequestsPerMinute()
+	if err != nil {
+		return llm.Config{}, err
+	}
 
 	return llm.Config{
-		Provider:       llmProvider,
-		Model:          model,
-		EmbeddingModel: embeddingModel,
-		APIKey:         apiKey,
-		BaseURL:        baseURL,
-		ThinkingBudget: thinkingBudget,
-		Timeout:        timeout,
+		Provider:          llmProvider,
+		Model:             model,
+		EmbeddingModel:    embeddingModel,
+		APIKey:            apiKey,
+		BaseURL:           baseURL,
+		ThinkingBudget:    thinkingBudget,
+		Timeout:           timeout,
+		AgentTimeout:      agentTimeout,
+		RequestsPerMinute: requestsPerMinute,
 		// EmbeddingProvider, EmbeddingAPIKey, EmbeddingBaseURL left empty
 		// client.go will fallback to main Provider for embeddings
 	}, nil
@@ -208,6 +218,14 @@ func LoadLLMConfig() (llm.Config, error) {
 	if err != nil {
 		return llm.Config{}, err
 	}
+	agentTimeout, err := ResolveAgentTimeout()
+	if err != nil {
+		return llm.Config{}, err
+	}
+	requestsPerMinute, err := ResolveRequestsPerMinute()
+	if err != nil {
+		return llm.Config{}, err
+	}
 
 	return llm.Config{
 		Provider:          llmProvider,
@@ -219,6 +237,8 @@ func LoadLLMConfig() (llm.Config, error) {
 		EmbeddingAPIKey:   embeddingAPIKey,
 		EmbeddingBaseURL:  embeddingBaseURL,
 		Timeout:           timeout,
+		AgentTimeout:      agentTimeout,
+		RequestsPerMinute: requestsPerMinute,
 	}, nil
 }
 
@@ -326,6 +346,33 @@ func ResolveLLMTimeout() (time.Duration, error) {
 	return llm.DefaultRequestTimeout, nil
 }
 
+// ResolveAgentTimeout resolves the per-invocation agent deadline from llm.agent_timeout.
+// Unset or empty means no deadline beyond the per-request llm.timeout.
+func ResolveAgentTimeout() (time.Duration, error) {
+	raw := strings.TrimSpace(viper.GetString("llm.agent_timeout"))
+	if raw == "" {
+		return 0, nil
+	}
+	dur, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid llm.agent_timeout: %w", err)
+	}
+	if dur < 0 {
+		return 0, fmt.Errorf("invalid llm.agent_timeout: %s", raw)
+	}
+	return dur, nil
+}
+
+// ResolveRequestsPerMinute resolves the chat request rate cap from
+// llm.requests_per_minute. Unset or zero means unlimited.
+func ResolveRequestsPerMinute() (int, error) {
+	rpm := viper.GetInt("llm.requests_per_minute")
+	if rpm < 0 {
+		return 0, fmt.Errorf("invalid llm.requests_per_minute: %d", rpm)
+	}
+	return rpm, nil
+}
+
 // ResolveAPIKey returns the best API key for the given provider using
 // per-provider config keys, then provider-specific env vars.
 func ResolveAPIKey(provider llm.Provider) string {
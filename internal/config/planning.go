@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadStrictValidation reports whether plan semantic validation runs in strict mode.
+// Enable it in CI via .taskwing.yaml or TASKWING_PLANNING_STRICT_VALIDATION=true:
+//
+//	planning:
+//	  strict_validation: true
+//
+// In strict mode missing files and unvalidated commands fail plan generation.
+func LoadStrictValidation() bool {
+	return getBoolWithDefault("planning.strict_validation", false)
+}
+
+// LoadNormalizePriorities reports whether generated plans get their task
+// priorities remapped to an evenly spaced, dependency-respecting order:
+//
+//	planning:
+//	  normalize_priorities: true
+func LoadNormalizePriorities() bool {
+	return getBoolWithDefault("planning.normalize_priorities", false)
+}
+
+// LoadTestFirst reports whether generated plans precede each implementation
+// task with a failing-test task:
+//
+//	planning:
+//	  test_first: true
+func LoadTestFirst() bool {
+	return getBoolWithDefault("planning.test_first", false)
+}
+
+// LoadMergeDuplicateTasks reports whether near-duplicate tasks in a
+// generated plan are merged rather than only reported:
+//
+//	planning:
+//	  merge_duplicate_tasks: true
+func LoadMergeDuplicateTasks() bool {
+	return getBoolWithDefault("planning.merge_duplicate_tasks", false)
+}
+
+// DefaultConventionsFile is the project-relative file of team conventions
+// (database, auth, testing framework, ...) that clarify auto-answers follow.
+const DefaultConventionsFile = ".taskwing/conventions.md"
+
+// maxConventionsBytes caps how much of the conventions file reaches a prompt.
+const maxConventionsBytes = 16 * 1024
+
+// LoadConventions returns the team conventions used to auto-answer clarify
+// questions. The file defaults to DefaultConventionsFile under basePath and
+// can be moved with:
+//
+//	planning:
+//	  conventions_file: docs/conventions.md
+//
+// An empty basePath falls back to the detected project root. A missing file
+// yields "" and no error.
+func LoadConventions(basePath string) (string, error) {
+	if basePath == "" {
+		root, err := GetProjectRoot()
+		if err != nil {
+			return "", nil
+		}
+		basePath = root
+	}
+
+	path := getStringWithDefault("planning.conventions_file", DefaultConventionsFile)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(basePath, path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read conventions %s: %w", path, err)
+	}
+	if len(content) > maxConventionsBytes {
+		content = append(content[:maxConventionsBytes], "\n[truncated]"...)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
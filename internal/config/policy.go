@@ -0,0 +1,14 @@
+package config
+
+import "github.com/spf13/viper"
+
+// LoadPolicyBundles returns OPA bundle locations to load alongside the policies directory.
+// Entries may be local .tar.gz paths or http(s) URLs:
+//
+//	policy:
+//	  bundles:
+//	    - "https://policies.example.com/bundles/org.tar.gz"
+//	    - "/etc/taskwing/bundles/local.tar.gz"
+func LoadPolicyBundles() []string {
+	return viper.GetStringSlice("policy.bundles")
+}
@@ -0,0 +1,44 @@
+package config
+
+// PresenterConfig holds the character limits the MCP presenters truncate
+// content to. Lower them to spend fewer tokens per tool response:
+//
+//	presenter:
+//	  knowledge_preview: 200
+type PresenterConfig struct {
+	TitleLength            int // Node titles derived from content
+	SummaryPreviewLength   int // Content preview under a node summary
+	KnowledgePreviewLength int // Knowledge previews in ask results
+	NodePreviewLength      int // Content preview of a single node
+	SignatureLength        int // Symbol signatures in search results
+	DocLength              int // Doc comments in search results
+	CompactDocLength       int // Doc comments in compact code intelligence output
+}
+
+// DefaultPresenterConfig returns the built-in presenter limits.
+func DefaultPresenterConfig() PresenterConfig {
+	return PresenterConfig{
+		TitleLength:            120,
+		SummaryPreviewLength:   200,
+		KnowledgePreviewLength: 300,
+		NodePreviewLength:      150,
+		SignatureLength:        60,
+		DocLength:              80,
+		CompactDocLength:       200,
+	}
+}
+
+// LoadPresenterConfig loads presenter limits from Viper with defaults.
+func LoadPresenterConfig() PresenterConfig {
+	defaults := DefaultPresenterConfig()
+
+	return PresenterConfig{
+		TitleLength:            getIntWithDefault("presenter.title", defaults.TitleLength),
+		SummaryPreviewLength:   getIntWithDefault("presenter.summary_preview", defaults.SummaryPreviewLength),
+		KnowledgePreviewLength: getIntWithDefault("presenter.knowledge_preview", defaults.KnowledgePreviewLength),
+		NodePreviewLength:      getIntWithDefault("presenter.node_preview", defaults.NodePreviewLength),
+		SignatureLength:        getIntWithDefault("presenter.signature", defaults.SignatureLength),
+		DocLength:              getIntWithDefault("presenter.doc", defaults.DocLength),
+		CompactDocLength:       getIntWithDefault("presenter.compact_doc", defaults.CompactDocLength),
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+	"text/template/parse"
+)
+
+// PromptOverridesDir is the project-relative directory holding prompt overrides.
+// An agent's prompt is replaced by <PromptOverridesDir>/<agent>.tmpl when present.
+const PromptOverridesDir = ".taskwing/prompts"
+
+// LoadPromptTemplate returns the prompt template for an agent.
+// If <basePath>/.taskwing/prompts/<agent>.tmpl exists it is used instead of builtin,
+// provided it parses and references every variable the built-in template uses.
+// An empty basePath falls back to the detected project root.
+//
+// The built-in template is always returned when there is no usable override;
+// err is non-nil only when an override exists but was rejected.
+func LoadPromptTemplate(basePath, agent, builtin string) (string, error) {
+	if basePath == "" {
+		root, err := GetProjectRoot()
+		if err != nil {
+			return builtin, nil
+		}
+		basePath = root
+	}
+
+	path := filepath.Join(basePath, PromptOverridesDir, agent+".tmpl")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return builtin, nil
+		}
+		return builtin, fmt.Errorf("read prompt override %s: %w", path, err)
+	}
+
+	if err := validatePromptOverride(string(content), builtin); err != nil {
+		return builtin, fmt.Errorf("prompt override %s: %w", path, err)
+	}
+	return string(content), nil
+}
+
+// validatePromptOverride checks that override parses and uses all of builtin's variables.
+func validatePromptOverride(override, builtin string) error {
+	required, err := TemplateVars(builtin)
+	if err != nil {
+		return fmt.Errorf("parse built-in template: %w", err)
+	}
+	used, err := TemplateVars(override)
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+
+	have := make(map[string]bool, len(used))
+	for _, v := range used {
+		have[v] = true
+	}
+	var missing []string
+	for _, v := range required {
+		if !have[v] {
+			missing = append(missing, "{{."+v+"}}")
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required template variables: %v", missing)
+	}
+	return nil
+}
+
+// TemplateVars returns the sorted top-level fields (e.g. .ProjectName) a Go template references.
+// Fields inside range/with bodies refer to a different dot and are not collected.
+func TemplateVars(text string) ([]string, error) {
+	tmpl, err := template.New("prompt").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+		case *parse.WithNode:
+			walk(n.Pipe)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			seen[n.Ident[0]] = true
+		}
+	}
+	if tmpl.Tree != nil {
+		walk(tmpl.Root)
+	}
+
+	vars := make([]string, 0, len(seen))
+	for v := range seen {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	return vars, nil
+}
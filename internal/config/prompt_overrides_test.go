@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPromptTemplate_RejectsMissingVariables(t *testing.T) {
+	dir := t.TempDir()
+	promptsDir := filepath.Join(dir, PromptOverridesDir)
+	if err := os.MkdirAll(promptsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(promptsDir, "doc.tmpl"), []byte("Only {{.ProjectName}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := LoadPromptTemplate(dir, "doc", PromptTemplateDocAgent)
+	if err == nil || !strings.Contains(err.Error(), "DocContent") {
+		t.Errorf("expected missing DocContent error, got %v", err)
+	}
+	if tmpl != PromptTemplateDocAgent {
+		t.Error("invalid override should fall back to the built-in template")
+	}
+
+	tmpl, err = LoadPromptTemplate(dir, "git", PromptTemplateGitAgentChunked)
+	if err != nil || tmpl != PromptTemplateGitAgentChunked {
+		t.Errorf("agent without override should use the built-in, err=%v", err)
+	}
+}
@@ -546,8 +546,9 @@ Use the minimum number of tasks needed. Do NOT over-decompose.
 1.  **Self-Contained Tasks**: Each task MUST include enough context to be executed independently by any AI coding agent without seeing the full plan. Reference relevant decisions, constraints, and patterns from the Knowledge Graph.
 2.  **Dependencies**: Respect logical order. A task cannot rely on something not yet built.
 3.  **Constraint Compliance**: Tasks MUST comply with all constraints from the Knowledge Graph.
-4.  **Verification**: Each task needs acceptance criteria and a validation command.
+4.  **Verification**: Each task needs acceptance criteria and a validation command. Checks that cannot be scripted go in validation_steps as {"kind": "manual", "text": "..."}.
 5.  **No Overlap**: Do NOT split implementation and testing of the same feature into separate tasks. When explicit tasks are provided, use them directly.
+6.  **Documentation-Only Tasks**: Add "skip-audit" to keywords for tasks that only change documentation, so they are not held to the build and test audit.
 
 **Output Format (JSON):**
 {
@@ -556,7 +557,7 @@ Use the minimum number of tasks needed. Do NOT over-decompose.
       "title": "Task Title",
       "description": "Detailed instructions with file paths, patterns, constraints, and context. Self-contained for independent execution.",
       "acceptance_criteria": ["Criteria 1", "Criteria 2"],
-      "validation_steps": ["validation command"],
+      "validation_steps": ["validation command", {"kind": "manual", "text": "manual check"}],
       "priority": 80,
       "assigned_agent": "coder",
       "dependencies": ["Title of dependency task"],
@@ -566,6 +567,14 @@ Use the minimum number of tasks needed. Do NOT over-decompose.
   "rationale": "Why this approach and how it respects architectural constraints..."
 }`
 
+// PlanningTestFirstInstructions is prepended to the planning context when
+// test-first generation is requested.
+const PlanningTestFirstInstructions = `**Test-First Planning (required):**
+Precede each implementation task with a task that writes failing tests for it.
+- Title it "Write failing tests for <implementation task title>" and add "test-first" to its keywords.
+- The tests must fail until the implementation exists; the test task does not change production code.
+- List the test task's title in the implementation task's dependencies.`
+
 // PlanningAgentUserTemplate is the per-call user message template.
 const PlanningAgentUserTemplate = `Enriched Goal: {{.Goal}}
 
@@ -581,6 +590,7 @@ Break the goal into 3-5 logical phases that deliver incremental value.
 2.  Earlier phases enable later ones. Design for incremental delivery.
 3.  Each phase should expand into 2-4 tasks. No overlap between phases.
 4.  Use the Knowledge Graph Context to align with existing patterns and constraints.
+5.  List in "dependencies" the exact titles of the phases that must finish first. Leave it empty for phases that can proceed independently. Dependencies must not form a cycle.
 
 **Output Format (JSON):**
 {
@@ -590,7 +600,7 @@ Break the goal into 3-5 logical phases that deliver incremental value.
       "description": "What this phase accomplishes and its scope boundaries",
       "rationale": "Why this phase exists and what value it delivers",
       "expected_tasks": 3,
-      "dependencies": []
+      "dependencies": ["Title of a phase that must finish first"]
     }
   ],
   "rationale": "Overall reasoning for this phase breakdown and sequencing..."
@@ -611,7 +621,8 @@ Generate 2-4 self-contained tasks that fully accomplish this phase.
 2.  Each task MUST be self-contained with enough context for independent execution.
 3.  Tasks ordered by dependency. No overlap -- do not split implementation and testing.
 4.  Use the Knowledge Graph Context to respect existing patterns and constraints.
-5.  Each task needs acceptance criteria and validation steps.
+5.  Each task needs acceptance criteria and validation steps. Steps are shell commands; checks that cannot be scripted use {"kind": "manual", "text": "..."}.
+6.  Add "skip-audit" to keywords for tasks that only change documentation.
 
 **CRITICAL - Constraint Compliance:**
 If the context contains architectural CONSTRAINTS (marked as CRITICAL, MUST, mandatory), ALL tasks must comply with them.
@@ -623,7 +634,7 @@ If the context contains architectural CONSTRAINTS (marked as CRITICAL, MUST, man
       "title": "Task Title (action-oriented)",
       "description": "DETAILED step-by-step instructions. MUST reference relevant constraints.",
       "acceptance_criteria": ["Criterion 1", "Criterion 2"],
-      "validation_steps": ["go test ./...", "curl http://localhost:8080/health"],
+      "validation_steps": ["go test ./...", {"kind": "manual", "text": "Verify the settings page renders"}],
       "priority": 80,
       "assigned_agent": "coder",
       "dependencies": [],
@@ -640,7 +651,12 @@ Phase Description: {{.PhaseDescription}}
 Overall Goal: {{.EnrichedGoal}}
 
 Knowledge Graph:
-{{.Context}}`
+{{.Context}}
+{{if .History}}
+Previous Expansion Attempts (rejected by the user, do not repeat them):
+{{.History}}{{end}}
+{{if .Feedback}}
+User Feedback: {{.Feedback}}{{end}}`
 
 // SystemPromptSimplifyAgent is the system prompt for the Simplify Agent.
 // Reduces code complexity and line count while preserving behavior.
@@ -976,6 +992,10 @@ Stack Trace:
 Architectural Context:
 {{.Context}}
 {{end}}
+{{if .RelatedNotes}}
+Related Notes (project knowledge about similar issues; prefer causes they confirm):
+{{.RelatedNotes}}
+{{end}}
 
 **Output Format (JSON):**
 {
@@ -1003,3 +1023,37 @@ Architectural Context:
   ]
 }
 `
+
+// SystemPromptCriteriaVerificationAgent is the system prompt for the Criteria Verification Agent.
+// Checks a completed task's acceptance criteria against the files it changed.
+const SystemPromptCriteriaVerificationAgent = `You are a Senior Engineer reviewing whether a completed task met its acceptance criteria.
+Judge each criterion ONLY from the evidence below: the completion summary and the current contents of the files the task touched.
+
+**Guidelines:**
+1.  **Pass Only With Evidence**: Mark a criterion "pass" only when the files clearly show it is met. Cite the file (and line if possible).
+2.  **Otherwise Unknown**: If the evidence is missing, partial, or would require running the code, mark it "unknown" and say what is missing.
+3.  **Never Trust the Summary Alone**: The summary describes intent; the files are the proof.
+4.  **One Result Per Criterion**: Return the criteria in the given order, copying each criterion text exactly.
+
+**Task:** {{.Title}}
+{{if .Summary}}
+**Completion Summary:**
+{{.Summary}}
+{{end}}
+**Acceptance Criteria:**
+{{.Criteria}}
+
+**Touched Files:**
+{{if .Files}}{{.Files}}{{else}}(none reported){{end}}
+
+**Output Format (JSON):**
+{
+  "criteria": [
+    {
+      "criterion": "Exact criterion text",
+      "status": "pass", // "pass" or "unknown"
+      "evidence": "internal/auth/session.go:42 sets the cookie expiry"
+    }
+  ]
+}
+`
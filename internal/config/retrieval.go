@@ -37,6 +37,15 @@ type RetrievalConfig struct {
 
 	// Query rewriting settings
 	QueryRewriteEnabled bool `mapstructure:"query_rewrite_enabled"`
+
+	// Verification ranking: pending nodes below the confidence threshold
+	// have their score multiplied by the penalty
+	UnverifiedConfidenceThreshold float64 `mapstructure:"unverified_confidence_threshold"`
+	UnverifiedPenalty             float64 `mapstructure:"unverified_penalty"`
+
+	// Access ranking: nodes recall returns often get up to this fraction
+	// added to their score (0 disables the boost)
+	AccessBoost float64 `mapstructure:"access_boost"`
 }
 
 // DefaultRetrievalConfig returns the default retrieval configuration.
@@ -74,6 +83,13 @@ func DefaultRetrievalConfig() RetrievalConfig {
 
 		// Query rewriting
 		QueryRewriteEnabled: true, // Enabled by default - improves search quality
+
+		// Verification ranking
+		UnverifiedConfidenceThreshold: 0.6,
+		UnverifiedPenalty:             0.7,
+
+		// Access ranking
+		AccessBoost: 0.1,
 	}
 }
 
@@ -113,6 +129,13 @@ func LoadRetrievalConfig() RetrievalConfig {
 
 		// Query rewriting
 		QueryRewriteEnabled: getBoolWithDefault("retrieval.query_rewrite.enabled", defaults.QueryRewriteEnabled),
+
+		// Verification ranking
+		UnverifiedConfidenceThreshold: getFloat64WithDefault("retrieval.verification.confidence_threshold", defaults.UnverifiedConfidenceThreshold),
+		UnverifiedPenalty:             getFloat64WithDefault("retrieval.verification.penalty", defaults.UnverifiedPenalty),
+
+		// Access ranking
+		AccessBoost: getFloat64WithDefault("retrieval.access.boost", defaults.AccessBoost),
 	}
 }
 
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineRange is an inclusive range of line numbers (1-indexed).
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Overlaps reports whether the range intersects [start, end].
+func (r LineRange) Overlaps(start, end int) bool {
+	return r.Start <= end && r.End >= start
+}
+
+// hunkHeaderRegex matches "@@ -a,b +c,d @@" and captures the new-side start and count.
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// ChangedLines returns, per file, the line ranges changed between two refs.
+// Paths are relative to the client's working directory, and changes outside it are
+// ignored. Ranges refer to line numbers in refB. Deleted files are omitted.
+func (c *Client) ChangedLines(refA, refB string) (map[string][]LineRange, error) {
+	output, err := c.commander.RunInDir(c.workDir, "git", "diff", "--relative", "--unified=0", "--no-color", "--no-ext-diff", refA, refB)
+	if err != nil {
+		return nil, fmt.Errorf("diff %s..%s: %w", refA, refB, err)
+	}
+	return ParseDiffLineRanges(output), nil
+}
+
+// ParseDiffLineRanges extracts changed line ranges from `git diff --unified=0` output.
+// Pure deletions are reported as a one-line range at the deletion point so the
+// enclosing symbol is still attributed.
+func ParseDiffLineRanges(diff string) map[string][]LineRange {
+	changes := make(map[string][]LineRange)
+	var file string
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = ""
+			if path := strings.TrimPrefix(line, "+++ "); path != "/dev/null" {
+				file = strings.TrimPrefix(path, "b/")
+			}
+		case strings.HasPrefix(line, "@@") && file != "":
+			m := hunkHeaderRegex.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			start, _ := strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			r := LineRange{Start: start, End: start + count - 1}
+			if count == 0 {
+				// Deletion: the hunk sits after line `start`
+				r = LineRange{Start: max(start, 1), End: start + 1}
+			}
+			changes[file] = append(changes[file], r)
+		}
+	}
+	return changes
+}
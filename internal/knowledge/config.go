@@ -43,6 +43,10 @@ const (
 	// SemanticSimilarityThreshold for creating "semantically_similar" edges (default: 0.55).
 	SemanticSimilarityThreshold = 0.55
 
+	// ConflictSimilarityThreshold is how similar two constraints must be to be
+	// about the same thing before opposing wording flags a conflict (default: 0.70).
+	ConflictSimilarityThreshold = 0.70
+
 	// DeduplicationThreshold for detecting near-duplicate nodes (default: 0.92).
 	DeduplicationThreshold = 0.92
 
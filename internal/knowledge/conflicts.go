@@ -0,0 +1,123 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+// ConstraintConflict is a pair of constraints that cover the same ground but
+// give opposing guidance, e.g. "Always use the ORM" and "Use raw SQL instead
+// of the ORM on hot paths".
+type ConstraintConflict struct {
+	A, B       memory.Node
+	Similarity float32
+}
+
+// String describes the conflict for warnings.
+func (c ConstraintConflict) String() string {
+	return fmt.Sprintf("%q conflicts with %q", c.A.Summary, c.B.Summary)
+}
+
+// negationWords mark a constraint as steering away from something.
+var negationWords = map[string]bool{
+	"never": true, "not": true, "no": true, "don't": true, "dont": true,
+	"avoid": true, "instead": true, "without": true, "bypass": true,
+	"forbid": true, "forbidden": true, "disallow": true, "prohibited": true,
+}
+
+// DetectConstraintConflicts flags constraint pairs whose embeddings say they
+// are about the same thing (ConflictSimilarityThreshold) while only one of
+// them is worded as a prohibition or exception. Each conflict is recorded as
+// a conflicts_with edge and returned so callers can warn about it.
+// Constraints without embeddings are skipped, as are pairs already linked.
+//
+// Only constraints whose summary is in changed are compared against the
+// rest, so an ingest checks what it wrote rather than every pair again. An
+// empty changed compares all pairs.
+func (s *Service) DetectConstraintConflicts(ctx context.Context, changed []string) ([]ConstraintConflict, error) {
+	nodes, err := s.repo.ListNodesWithEmbeddings()
+	if err != nil {
+		return nil, fmt.Errorf("list embedded nodes: %w", err)
+	}
+	var constraints []memory.Node
+	for _, n := range nodes {
+		if n.Type == memory.NodeTypeConstraint {
+			constraints = append(constraints, n)
+		}
+	}
+	if len(constraints) < 2 {
+		return nil, nil
+	}
+
+	// Compare the changed constraints against every constraint
+	fresh := constraints
+	if len(changed) > 0 {
+		summaries := make(map[string]bool, len(changed))
+		for _, c := range changed {
+			summaries[strings.ToLower(c)] = true
+		}
+		fresh = nil
+		for _, n := range constraints {
+			if summaries[strings.ToLower(n.Summary)] {
+				fresh = append(fresh, n)
+			}
+		}
+	}
+
+	edges, err := s.repo.GetAllNodeEdges()
+	if err != nil {
+		return nil, fmt.Errorf("list edges: %w", err)
+	}
+	// Pairs already linked or compared
+	linked := make(map[[2]string]bool)
+	for _, e := range edges {
+		if e.Relation == memory.NodeRelationConflictsWith {
+			linked[[2]string{e.FromNode, e.ToNode}] = true
+			linked[[2]string{e.ToNode, e.FromNode}] = true
+		}
+	}
+
+	var conflicts []ConstraintConflict
+	for _, a := range fresh {
+		if err := ctx.Err(); err != nil {
+			return conflicts, err
+		}
+		for _, b := range constraints {
+			// A pair of changed constraints is seen twice; linked skips the second
+			if a.ID == b.ID || linked[[2]string{a.ID, b.ID}] {
+				continue
+			}
+			linked[[2]string{a.ID, b.ID}] = true
+			linked[[2]string{b.ID, a.ID}] = true
+			similarity := CosineSimilarity(a.Embedding, b.Embedding)
+			if similarity < ConflictSimilarityThreshold || negated(a) == negated(b) {
+				continue
+			}
+			props := map[string]any{"similarity": similarity}
+			if err := s.repo.LinkNodes(a.ID, b.ID, memory.NodeRelationConflictsWith, float64(similarity), props); err != nil {
+				return conflicts, fmt.Errorf("link conflict %s-%s: %w", a.ID, b.ID, err)
+			}
+			conflicts = append(conflicts, ConstraintConflict{A: a, B: b, Similarity: similarity})
+		}
+	}
+	return conflicts, nil
+}
+
+// negated reports whether a constraint's guidance is a prohibition or an
+// exception rather than a plain directive. The summary carries the guidance;
+// the content is used when there is no summary.
+func negated(n memory.Node) bool {
+	text := n.Summary
+	if text == "" {
+		text = n.Content
+	}
+	for _, w := range strings.Fields(strings.ToLower(text)) {
+		if negationWords[strings.Trim(w, ".,;:!?()\"'")] {
+			return true
+		}
+	}
+	return false
+}
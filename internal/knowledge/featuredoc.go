@@ -0,0 +1,111 @@
+package knowledge
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+// RegenerateFeatureDoc rebuilds one feature's markdown page from its current
+// node, graph edges, and evidence, so an edited feature does not need a full
+// re-bootstrap. Returns the path of the page written.
+func (s *Service) RegenerateFeatureDoc(featureID string) (string, error) {
+	feature, err := s.repo.GetNode(featureID)
+	if err != nil {
+		return "", fmt.Errorf("get feature %s: %w", featureID, err)
+	}
+	if feature.Type != memory.NodeTypeFeature {
+		return "", fmt.Errorf("node %s is a %s, not a feature", featureID, feature.Type)
+	}
+
+	doc := memory.FeatureDoc{
+		Feature:  *feature,
+		Evidence: featureEvidence(feature),
+	}
+
+	edges, err := s.repo.GetNodeEdges(featureID)
+	if err != nil {
+		return "", fmt.Errorf("get edges for %s: %w", featureID, err)
+	}
+	seen := map[string]bool{featureID: true}
+	for _, edge := range edges {
+		connectedID := edge.ToNode
+		if edge.ToNode == featureID {
+			connectedID = edge.FromNode
+		}
+		if seen[connectedID] {
+			continue
+		}
+		seen[connectedID] = true
+
+		node, err := s.repo.GetNode(connectedID)
+		if err != nil {
+			slog.Debug("feature doc: GetNode error", "connectedID", connectedID, "error", err)
+			continue
+		}
+		doc.Related = append(doc.Related, memory.FeatureDocLink{Relation: edge.Relation, Type: node.Type, Summary: node.Summary})
+	}
+	sort.SliceStable(doc.Related, func(i, j int) bool {
+		if doc.Related[i].Relation != doc.Related[j].Relation {
+			return doc.Related[i].Relation < doc.Related[j].Relation
+		}
+		return doc.Related[i].Summary < doc.Related[j].Summary
+	})
+
+	return s.repo.WriteFeatureDoc(doc)
+}
+
+// featureEvidence collects the file locations backing a feature from its
+// evidence and structured snippets, deduplicated and sorted. Snippet code is
+// dropped: only paths and line ranges reach the markdown page.
+func featureEvidence(n *memory.Node) []memory.FeatureDocEvidence {
+	seen := make(map[memory.FeatureDocEvidence]bool)
+	var out []memory.FeatureDocEvidence
+	add := func(ev memory.FeatureDocEvidence) {
+		if ev.FilePath == "" || seen[ev] {
+			return
+		}
+		seen[ev] = true
+		out = append(out, ev)
+	}
+
+	if n.Evidence != "" {
+		var evidence []core.Evidence
+		if err := json.Unmarshal([]byte(n.Evidence), &evidence); err == nil {
+			for _, ev := range evidence {
+				if ev.EvidenceType == "git" {
+					continue
+				}
+				add(memory.FeatureDocEvidence{FilePath: ev.FilePath, StartLine: ev.StartLine, EndLine: ev.EndLine})
+			}
+		}
+	}
+	if sc := n.ParseStructuredContent(); sc != nil {
+		for _, sn := range sc.Snippets {
+			start, end := parseLineRange(sn.Lines)
+			add(memory.FeatureDocEvidence{FilePath: sn.FilePath, StartLine: start, EndLine: end})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].FilePath != out[j].FilePath {
+			return out[i].FilePath < out[j].FilePath
+		}
+		return out[i].StartLine < out[j].StartLine
+	})
+	return out
+}
+
+// parseLineRange parses "45-67" or "45" into start and end lines (0 if absent).
+func parseLineRange(lines string) (int, int) {
+	startStr, endStr, _ := strings.Cut(strings.TrimSpace(lines), "-")
+	start, _ := strconv.Atoi(strings.TrimSpace(startStr))
+	end, _ := strconv.Atoi(strings.TrimSpace(endStr))
+	return start, end
+}
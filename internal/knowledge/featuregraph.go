@@ -0,0 +1,204 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+// featureGraphRelations are the edge types kept in the feature graph.
+var featureGraphRelations = map[string]bool{
+	memory.NodeRelationDependsOn: true,
+	memory.NodeRelationExtends:   true,
+	memory.NodeRelationRelatesTo: true,
+}
+
+// FeatureGraph is the graph of features and the architectural relations between them.
+type FeatureGraph struct {
+	Nodes  []FeatureGraphNode `json:"nodes"`
+	Edges  []FeatureGraphEdge `json:"edges"`
+	Cycles [][]string         `json:"cycles,omitempty"` // Feature IDs in each depends_on cycle
+}
+
+// FeatureGraphNode is a feature in the graph.
+type FeatureGraphNode struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+}
+
+// FeatureGraphEdge is a depends_on, extends, or relates_to edge between two features.
+type FeatureGraphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+}
+
+// FeatureGraph returns the features in memory, the depends_on/extends/relates_to
+// edges between them, and any dependency cycles among them.
+func (s *Service) FeatureGraph(ctx context.Context) (FeatureGraph, error) {
+	features, err := s.repo.ListNodes(memory.NodeTypeFeature)
+	if err != nil {
+		return FeatureGraph{}, fmt.Errorf("list features: %w", err)
+	}
+	edges, err := s.repo.GetAllNodeEdges()
+	if err != nil {
+		return FeatureGraph{}, fmt.Errorf("list edges: %w", err)
+	}
+
+	var g FeatureGraph
+	isFeature := make(map[string]bool, len(features))
+	for _, f := range features {
+		isFeature[f.ID] = true
+		g.Nodes = append(g.Nodes, FeatureGraphNode{ID: f.ID, Summary: f.Summary})
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].Summary < g.Nodes[j].Summary })
+
+	seen := make(map[FeatureGraphEdge]bool)
+	for _, e := range edges {
+		edge := FeatureGraphEdge{From: e.FromNode, To: e.ToNode, Relation: e.Relation}
+		if !featureGraphRelations[e.Relation] || !isFeature[e.FromNode] || !isFeature[e.ToNode] || seen[edge] {
+			continue
+		}
+		seen[edge] = true
+		g.Edges = append(g.Edges, edge)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Relation < b.Relation
+	})
+
+	g.Cycles = dependencyCycles(g.Edges)
+	return g, nil
+}
+
+// dependencyCycles returns the strongly connected components of the depends_on
+// edges that form cycles (more than one feature, or a self-dependency).
+// Each cycle's IDs are sorted; cycles are ordered by their first ID.
+func dependencyCycles(edges []FeatureGraphEdge) [][]string {
+	adj := make(map[string][]string)
+	selfLoop := make(map[string]bool)
+	var ids []string
+	addID := func(id string) {
+		if _, ok := adj[id]; !ok {
+			adj[id] = nil
+			ids = append(ids, id)
+		}
+	}
+	for _, e := range edges {
+		if e.Relation != memory.NodeRelationDependsOn {
+			continue
+		}
+		addID(e.From)
+		addID(e.To)
+		adj[e.From] = append(adj[e.From], e.To)
+		if e.From == e.To {
+			selfLoop[e.From] = true
+		}
+	}
+	sort.Strings(ids)
+
+	// Tarjan's strongly connected components
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, next := range adj[id] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				low[id] = min(low[id], low[next])
+			} else if onStack[next] {
+				low[id] = min(low[id], index[next])
+			}
+		}
+		if low[id] != index[id] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == id {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop[id] {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for _, id := range ids {
+		if _, visited := index[id]; !visited {
+			visit(id)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. depends_on edges are solid
+// arrows, extends edges thick arrows, and relates_to edges dotted lines.
+// Features in a dependency cycle are highlighted.
+func (g FeatureGraph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+
+	for _, n := range g.Nodes {
+		label := n.Summary
+		if label == "" {
+			label = n.ID
+		}
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", mermaidID(n.ID), strings.ReplaceAll(label, `"`, "#quot;")))
+	}
+
+	for _, e := range g.Edges {
+		arrow := "-.-"
+		switch e.Relation {
+		case memory.NodeRelationDependsOn:
+			arrow = "-->"
+		case memory.NodeRelationExtends:
+			arrow = "==>"
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s|%s| %s\n", mermaidID(e.From), arrow, e.Relation, mermaidID(e.To)))
+	}
+
+	if len(g.Cycles) > 0 {
+		sb.WriteString("    classDef cycle stroke:#d33,stroke-width:2px\n")
+		for _, cycle := range g.Cycles {
+			ids := make([]string, len(cycle))
+			for i, id := range cycle {
+				ids[i] = mermaidID(id)
+			}
+			sb.WriteString(fmt.Sprintf("    class %s cycle\n", strings.Join(ids, ",")))
+		}
+	}
+	return sb.String()
+}
+
+// mermaidID makes a node ID safe to use as a Mermaid identifier.
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
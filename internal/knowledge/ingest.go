@@ -29,18 +29,18 @@ func (s *Service) IngestFindingsWithRelationships(ctx context.Context, findings
 	}
 
 	if verbose {
-		fmt.Println("  Ingesting findings...")
+		fmt.Fprintln(s.out, "  Ingesting findings...")
 	}
 
 	// 0. Verify Findings (if basePath is set)
 	rejectedCount := 0
 	if s.basePath != "" {
 		if verbose {
-			fmt.Print("  Verifying evidence...")
+			fmt.Fprint(s.out, "  Verifying evidence...")
 		}
 		findings, _, rejectedCount = s.verifyFindings(ctx, findings, verbose)
 		if verbose {
-			fmt.Println()
+			fmt.Fprintln(s.out)
 		}
 	}
 
@@ -67,24 +67,57 @@ func (s *Service) IngestFindingsWithRelationships(ctx context.Context, findings
 	// 5. Process LLM-extracted relationships
 	llmEdges := s.linkByLLMRelationships(relationships, nodesByTitle)
 
-	totalEdges := evidenceEdges + semanticEdges + llmEdges
+	// 6. Drop depends_on edges implied by longer paths (repeated runs accumulate them)
+	prunedEdges, err := s.PruneRedundantRelations(ctx)
+	if err != nil {
+		slog.Debug("redundant relation pruning failed", "error", err)
+	}
+
+	// 7. Flag constraints that give opposing guidance on the same topic,
+	// comparing only the constraints this ingest wrote
+	var conflicts []ConstraintConflict
+	if constraints := constraintTitles(findings); len(constraints) > 0 {
+		conflicts, err = s.DetectConstraintConflicts(ctx, constraints)
+		if err != nil {
+			slog.Debug("constraint conflict detection failed", "error", err)
+		}
+	}
+
+	totalEdges := evidenceEdges + semanticEdges + llmEdges + len(conflicts)
 
 	if verbose {
-		fmt.Println(" done")
+		fmt.Fprintln(s.out, " done")
 		if rejectedCount > 0 {
-			fmt.Printf("  %d findings rejected (unverifiable evidence)\n", rejectedCount)
+			fmt.Fprintf(s.out, "  %d findings rejected (unverifiable evidence)\n", rejectedCount)
 		}
-		fmt.Printf("  Saved %d nodes, %d edges\n", nodesCreated, totalEdges)
+		fmt.Fprintf(s.out, "  Saved %d nodes, %d edges\n", nodesCreated, totalEdges)
+	}
+	for _, c := range conflicts {
+		slog.Warn("conflicting constraints", "a", c.A.Summary, "b", c.B.Summary, "similarity", c.Similarity)
 	}
 
 	// Log staleness bookkeeping at debug level only
 	if totalDeleted > 0 || totalDemoted > 0 {
 		slog.Debug("stale node reconciliation", "deleted", totalDeleted, "demoted", totalDemoted)
 	}
+	if prunedEdges > 0 {
+		slog.Debug("pruned redundant relations", "count", prunedEdges)
+	}
 
 	return nil
 }
 
+// constraintTitles returns the titles of the constraint findings.
+func constraintTitles(findings []core.Finding) []string {
+	var titles []string
+	for _, f := range findings {
+		if f.Type == core.FindingTypeConstraint {
+			titles = append(titles, f.Title)
+		}
+	}
+	return titles
+}
+
 // verifyFindings runs the VerificationAgent on findings and filters out rejected ones.
 // Returns the filtered findings and counts of verified/rejected.
 func (s *Service) verifyFindings(ctx context.Context, findings []core.Finding, verbose bool) ([]core.Finding, int, int) {
@@ -112,12 +145,12 @@ func (s *Service) verifyFindings(ctx context.Context, findings []core.Finding, v
 	}
 
 	if verbose {
-		fmt.Printf(" %d verified", verifiedCount)
+		fmt.Fprintf(s.out, " %d verified", verifiedCount)
 		if partialCount > 0 {
-			fmt.Printf(" (%d partial)", partialCount)
+			fmt.Fprintf(s.out, " (%d partial)", partialCount)
 		}
 		if rejectedCount > 0 {
-			fmt.Printf(", %d rejected", rejectedCount)
+			fmt.Fprintf(s.out, ", %d rejected", rejectedCount)
 		}
 	}
 
@@ -155,7 +188,7 @@ func (s *Service) markStaleData(findings []core.Finding, filePaths []string, ver
 
 			if len(filePaths) > 0 {
 				if verbose {
-					fmt.Printf("  ♻️  Marking stale nodes for agent %s (files: %d)\n", f.SourceAgent, len(filePaths))
+					fmt.Fprintf(s.out, "  ♻️  Marking stale nodes for agent %s (files: %d)\n", f.SourceAgent, len(filePaths))
 				}
 				if err := s.repo.DeleteNodesByFiles(f.SourceAgent, filePaths); err != nil {
 					return fmt.Errorf("mark stale files for agent %s: %w", f.SourceAgent, err)
@@ -164,7 +197,7 @@ func (s *Service) markStaleData(findings []core.Finding, filePaths []string, ver
 			}
 
 			if verbose {
-				fmt.Printf("  ♻️  Marking nodes for agent: %s\n", f.SourceAgent)
+				fmt.Fprintf(s.out, "  ♻️  Marking nodes for agent: %s\n", f.SourceAgent)
 			}
 			if err := s.repo.MarkNodesStaleByAgent(f.SourceAgent, workspaces...); err != nil {
 				return fmt.Errorf("mark stale agent %s: %w", f.SourceAgent, err)
@@ -197,7 +230,7 @@ func (s *Service) reconcileStaleNodes(findings []core.Finding, verbose bool) (in
 // ingestNodesWithIndex creates document nodes and returns a title->nodeID index for LLM relationship linking
 func (s *Service) ingestNodesWithIndex(ctx context.Context, findings []core.Finding, verbose bool) (int, int, map[string]string, error) {
 	if verbose {
-		fmt.Printf("  Generating embeddings for %d findings...", len(findings))
+		fmt.Fprintf(s.out, "  Generating embeddings for %d findings...", len(findings))
 	}
 
 	nodesCreated := 0
@@ -316,7 +349,7 @@ func (s *Service) ingestNodesWithIndex(ctx context.Context, findings []core.Find
 		}
 	}
 	if verbose {
-		fmt.Printf(" %d created\n", nodesCreated)
+		fmt.Fprintf(s.out, " %d created\n", nodesCreated)
 	}
 	return nodesCreated, skippedDuplicates, nodesByTitle, nil
 }
@@ -327,7 +360,7 @@ func (s *Service) ingestNodesWithIndex(ctx context.Context, findings []core.Find
 // Returns (evidenceEdges, semanticEdges, error)
 func (s *Service) linkKnowledgeGraph(verbose bool) (int, int, error) {
 	if verbose {
-		fmt.Print("  Linking knowledge graph")
+		fmt.Fprint(s.out, "  Linking knowledge graph")
 	}
 
 	allNodes, err := s.repo.ListNodes("")
@@ -0,0 +1,86 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+// PruneRedundantRelations removes depends_on edges that are implied by a longer
+// dependency path (A→B, B→C makes A→C redundant) and returns how many were
+// removed. Edges are checked against the graph as it is pruned, so every
+// dependency stays reachable even when cycles make redundancy mutual.
+func (s *Service) PruneRedundantRelations(ctx context.Context) (int, error) {
+	edges, err := s.repo.GetAllNodeEdges()
+	if err != nil {
+		return 0, fmt.Errorf("list edges: %w", err)
+	}
+
+	type link struct{ from, to string }
+	ids := make(map[link]int64)
+	adj := make(map[string]map[string]bool)
+	var links []link
+	for _, e := range edges {
+		if e.Relation != memory.NodeRelationDependsOn || e.FromNode == e.ToNode {
+			continue
+		}
+		l := link{e.FromNode, e.ToNode}
+		if _, dup := ids[l]; dup {
+			continue
+		}
+		ids[l] = e.ID
+		links = append(links, l)
+		if adj[l.from] == nil {
+			adj[l.from] = make(map[string]bool)
+		}
+		adj[l.from][l.to] = true
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].from != links[j].from {
+			return links[i].from < links[j].from
+		}
+		return links[i].to < links[j].to
+	})
+
+	pruned := 0
+	for _, l := range links {
+		if err := ctx.Err(); err != nil {
+			return pruned, err
+		}
+		if !reachableWithout(adj, l.from, l.to) {
+			continue
+		}
+		if err := s.repo.DeleteNodeEdge(ids[l]); err != nil {
+			return pruned, fmt.Errorf("delete %s→%s: %w", l.from, l.to, err)
+		}
+		delete(adj[l.from], l.to)
+		pruned++
+	}
+	return pruned, nil
+}
+
+// reachableWithout reports whether to can be reached from from without using
+// the direct from→to edge.
+func reachableWithout(adj map[string]map[string]bool, from, to string) bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for next := range adj[id] {
+			if id == from && next == to {
+				continue
+			}
+			if next == to {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
@@ -28,9 +28,10 @@ type NodeResponse struct {
 	Content            string        `json:"content"`
 	ConfidenceScore    float64       `json:"confidenceScore,omitempty"`
 	VerificationStatus string        `json:"verificationStatus,omitempty"`
-	MatchScore         float32       `json:"matchScore,omitempty"` // Semantic similarity (0-1)
-	Evidence           []EvidenceRef `json:"evidence,omitempty"`   // File:line references
-	CreatedAt          time.Time     `json:"createdAt,omitempty"`  // When the node was created
+	MatchScore         float32       `json:"matchScore,omitempty"`  // Semantic similarity (0-1)
+	MatchReason        *MatchReason  `json:"matchReason,omitempty"` // Why the node matched (debug recall only)
+	Evidence           []EvidenceRef `json:"evidence,omitempty"`    // File:line references
+	CreatedAt          time.Time     `json:"createdAt,omitempty"`   // When the node was created
 
 	// Freshness metadata (set at query time by freshness.Check, not stored)
 	FreshnessStatus string   `json:"freshnessStatus,omitempty"` // fresh, stale, missing, no_evidence
@@ -45,6 +46,28 @@ type NodeResponse struct {
 	DebtWarning  string  `json:"debtWarning,omitempty"`  // Human/AI-readable warning (auto-generated)
 }
 
+// MatchReason explains how a recalled node was retrieved and scored.
+// Before boosts and reranking, Score = FTSScore*FTSWeight + VectorScore*VectorWeight.
+type MatchReason struct {
+	Via          string  `json:"via"`          // "fts", "vector", "hybrid", or "graph"
+	FTSScore     float32 `json:"ftsScore"`     // Normalized BM25 score before weighting (0 if not an FTS hit)
+	VectorScore  float32 `json:"vectorScore"`  // Cosine similarity before weighting (0 if not a vector hit)
+	FTSWeight    float32 `json:"ftsWeight"`    // Weight applied to FTSScore
+	VectorWeight float32 `json:"vectorWeight"` // Weight applied to VectorScore
+}
+
+// via names the retrievers that contributed to the match.
+func (m *MatchReason) via() string {
+	switch {
+	case m.FTSScore > 0 && m.VectorScore > 0:
+		return "hybrid"
+	case m.VectorScore > 0:
+		return "vector"
+	default:
+		return "fts"
+	}
+}
+
 // NodeToResponse converts a memory.Node to a token-efficient NodeResponse.
 func NodeToResponse(n memory.Node, matchScore float32) NodeResponse {
 	resp := NodeResponse{
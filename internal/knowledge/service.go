@@ -4,13 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
+	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/cloudwego/eino/schema"
 	"github.com/google/uuid"
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
 	"github.com/josephgoksu/TaskWing/internal/llm"
 	"github.com/josephgoksu/TaskWing/internal/memory"
 )
@@ -31,9 +36,24 @@ type Repository interface {
 	MarkNodesStaleByAgent(agent string, workspaces ...string) error
 	ReconcileStaleNodes(agent string, workspaces ...string) (int, int, error)
 
+	// Verification
+	UpdateNodeVerification(id, status string) error
+
+	// Tags
+	AddNodeTag(id, tag string) error
+	RemoveNodeTag(id, tag string) error
+	ListNodesByTag(tag string) ([]memory.Node, error)
+
+	// Access tracking
+	RecordNodeAccess(ids []string) error
+	GetNodeAccessCounts(ids []string) (map[string]int, error)
+	ListNodesByAccess(limit int) ([]memory.Node, error)
+
 	// Graph edge operations
 	LinkNodes(from, to, relation string, confidence float64, properties map[string]any) error
 	GetNodeEdges(nodeID string) ([]memory.NodeEdge, error)
+	GetAllNodeEdges() ([]memory.NodeEdge, error)
+	DeleteNodeEdge(id int64) error
 
 	// FTS5 Hybrid Search (new)
 	ListNodesWithEmbeddings() ([]memory.Node, error)
@@ -44,6 +64,9 @@ type Repository interface {
 
 	// Project Overview
 	GetProjectOverview() (*memory.ProjectOverview, error)
+
+	// Markdown mirror
+	WriteFeatureDoc(doc memory.FeatureDoc) (string, error)
 }
 
 // Service provides high-level knowledge operations
@@ -52,6 +75,7 @@ type Service struct {
 	llmCfg            llm.Config
 	retrievalCfg      RetrievalConfig // Dynamic search configuration
 	basePath          string          // Project base path for verification
+	out               io.Writer       // Verbose ingestion progress (os.Stdout by default)
 	chatModelFactory  func(ctx context.Context, cfg llm.Config) (*llm.CloseableChatModel, error)
 	reranker          Reranker        // Optional reranker for two-stage retrieval
 	rerankerFactory   RerankerFactory // Factory for creating reranker
@@ -60,9 +84,10 @@ type Service struct {
 
 type NodeInput struct {
 	Content     string
-	Type        string // Optional manual override
-	Summary     string // Optional
-	SourceAgent string // Agent that produced this node
+	Type        string   // Optional manual override
+	Summary     string   // Optional
+	SourceAgent string   // Agent that produced this node
+	Tags        []string // Optional grouping labels (e.g. security, perf)
 	Timestamp   time.Time
 }
 
@@ -75,6 +100,7 @@ func NewService(repo Repository, cfg llm.Config) *Service {
 		retrievalCfg:     retrievalCfg,
 		chatModelFactory: llm.NewCloseableChatModel,
 		rerankerFactory:  DefaultRerankerFactory,
+		out:              os.Stdout,
 	}
 }
 
@@ -86,6 +112,7 @@ func NewServiceWithConfig(repo Repository, llmCfg llm.Config, retrievalCfg Retri
 		retrievalCfg:     retrievalCfg,
 		chatModelFactory: llm.NewCloseableChatModel,
 		rerankerFactory:  DefaultRerankerFactory,
+		out:              os.Stdout,
 	}
 }
 
@@ -123,11 +150,17 @@ func (s *Service) SetBasePath(basePath string) {
 	s.basePath = basePath
 }
 
+// SetOutput redirects verbose ingestion progress.
+func (s *Service) SetOutput(w io.Writer) {
+	s.out = w
+}
+
 // ScoredNode represents a search result with visual relevance score
 type ScoredNode struct {
 	Node         *memory.Node `json:"node"`
 	Score        float32      `json:"score"`
 	ExpandedFrom string       `json:"expanded_from,omitempty"` // Parent node ID if this came from graph expansion
+	MatchReason  *MatchReason `json:"match_reason,omitempty"`  // How the node was retrieved, before reranking and boosts
 }
 
 // RewriteQuery uses LLM to improve a user query for better search results.
@@ -305,6 +338,13 @@ func (s *Service) searchInternal(ctx context.Context, query string, typeFilter s
 	// Collect results from both search methods
 	scoreByID := make(map[string]float32)
 	nodeByID := make(map[string]*memory.Node)
+	reasonByID := make(map[string]*MatchReason)
+	reasonFor := func(id string) *MatchReason {
+		if reasonByID[id] == nil {
+			reasonByID[id] = &MatchReason{FTSWeight: ftsWeight, VectorWeight: vectorWeight}
+		}
+		return reasonByID[id]
+	}
 
 	// 1. FTS5 keyword search (fast, no API call, always works)
 	// Note: FTS currently searches all types. We filter later.
@@ -341,6 +381,7 @@ func (s *Service) searchInternal(ctx context.Context, query string, typeFilter s
 		node := r.Node // Copy to avoid pointer issues
 		nodeByID[r.Node.ID] = &node
 		scoreByID[r.Node.ID] = ftsScore * ftsWeight
+		reasonFor(r.Node.ID).FTSScore = ftsScore
 	}
 
 	// 2. Vector similarity search (single query, not N+1)
@@ -383,12 +424,23 @@ func (s *Service) searchInternal(ctx context.Context, query string, typeFilter s
 						scoreByID[n.ID] = 0
 					}
 					scoreByID[n.ID] += vectorScore * vectorWeight
+					reasonFor(n.ID).VectorScore = vectorScore
 				}
 			}
 		}
 	}
 
 	// 3. Merge, filter low-confidence, and sort by combined score
+	var accessCounts map[string]int
+	if cfg.AccessBoost > 0 && len(scoreByID) > 0 {
+		ids := make([]string, 0, len(scoreByID))
+		for id := range scoreByID {
+			ids = append(ids, id)
+		}
+		if accessCounts, err = s.repo.GetNodeAccessCounts(ids); err != nil {
+			slog.Debug("access count lookup failed", "error", err)
+		}
+	}
 	var scored []ScoredNode
 	for id, score := range scoreByID {
 		// Filter out noise: only include results above minimum threshold
@@ -396,7 +448,13 @@ func (s *Service) searchInternal(ctx context.Context, query string, typeFilter s
 			continue
 		}
 		if node, ok := nodeByID[id]; ok {
-			scored = append(scored, ScoredNode{Node: node, Score: score})
+			if node.VerificationStatus == string(core.VerificationStatusRejected) {
+				continue // Rejected findings are never recalled
+			}
+			score *= verificationFactor(node, cfg) * accessFactor(accessCounts[id], cfg)
+			reason := reasonByID[id]
+			reason.Via = reason.via()
+			scored = append(scored, ScoredNode{Node: node, Score: score, MatchReason: reason})
 		}
 	}
 
@@ -540,6 +598,7 @@ func (s *Service) expandViaGraph(initial []ScoredNode, cfg RetrievalConfig) []Sc
 				Node:         connectedNode,
 				Score:        connectedScore,
 				ExpandedFrom: parentNode.Node.ID, // Track that this came from graph expansion
+				MatchReason:  &MatchReason{Via: "graph"},
 			})
 			addedCount++
 		}
@@ -555,6 +614,132 @@ func (s *Service) expandViaGraph(initial []ScoredNode, cfg RetrievalConfig) []Sc
 	return expanded
 }
 
+// Related returns the nodes one edge away from nodeID, in either direction.
+// A non-empty relation (e.g. memory.NodeRelationDependsOn) keeps only edges of
+// that type. Edges pointing at nodes that no longer exist are skipped.
+func (s *Service) Related(ctx context.Context, nodeID, relation string) ([]NodeResponse, error) {
+	edges, err := s.repo.GetNodeEdges(nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("get edges for %s: %w", nodeID, err)
+	}
+
+	seen := map[string]bool{nodeID: true}
+	var related []NodeResponse
+	for _, edge := range edges {
+		if relation != "" && edge.Relation != relation {
+			continue
+		}
+		connectedID := edge.ToNode
+		if edge.ToNode == nodeID {
+			connectedID = edge.FromNode
+		}
+		if seen[connectedID] {
+			continue
+		}
+		seen[connectedID] = true
+
+		node, err := s.repo.GetNode(connectedID)
+		if err != nil {
+			slog.Debug("related: GetNode error", "connectedID", connectedID, "error", err)
+			continue
+		}
+		related = append(related, NodeToResponse(*node, 0))
+	}
+	return related, nil
+}
+
+// verificationFactor returns the score multiplier for a node's verification state.
+// Findings still pending verification with low confidence are down-ranked so that
+// verified knowledge wins ties.
+func verificationFactor(n *memory.Node, cfg RetrievalConfig) float32 {
+	if n.VerificationStatus == string(core.VerificationStatusPending) &&
+		n.ConfidenceScore < cfg.UnverifiedConfidenceThreshold && cfg.UnverifiedPenalty > 0 {
+		return float32(cfg.UnverifiedPenalty)
+	}
+	return 1
+}
+
+// accessFactor boosts frequently recalled nodes by up to cfg.AccessBoost.
+// The boost grows logarithmically and saturates at 100 accesses, so it breaks
+// ties between similar matches without burying fresh knowledge.
+func accessFactor(count int, cfg RetrievalConfig) float32 {
+	if count <= 0 || cfg.AccessBoost <= 0 {
+		return 1
+	}
+	return float32(1 + cfg.AccessBoost*min(1, math.Log1p(float64(count))/math.Log1p(100)))
+}
+
+// RecordAccess counts one recall of each node in ids. Callers pass only the
+// results shown to the user, not candidates or gathered context. Tracking
+// failures are logged and never fail the recall.
+func (s *Service) RecordAccess(ids []string) {
+	if err := s.repo.RecordNodeAccess(ids); err != nil {
+		slog.Debug("record node access failed", "error", err)
+	}
+}
+
+// TopAccessed returns the nodes recall has returned most often, most accessed
+// first. limit defaults to 10.
+func (s *Service) TopAccessed(limit int) ([]memory.Node, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	return s.repo.ListNodesByAccess(limit)
+}
+
+// Verify records a manual verification decision for a node.
+// Status must be verified or rejected; rejected nodes are excluded from recall.
+func (s *Service) Verify(nodeID, status string) error {
+	switch core.VerificationStatus(status) {
+	case core.VerificationStatusVerified, core.VerificationStatusRejected:
+	default:
+		return fmt.Errorf("invalid verification status %q: must be %s or %s",
+			status, core.VerificationStatusVerified, core.VerificationStatusRejected)
+	}
+	return s.repo.UpdateNodeVerification(nodeID, status)
+}
+
+// AddTag tags a node so it can be listed with related knowledge (e.g. "security").
+func (s *Service) AddTag(nodeID, tag string) error {
+	return s.repo.AddNodeTag(nodeID, tag)
+}
+
+// RemoveTag removes a tag from a node.
+func (s *Service) RemoveTag(nodeID, tag string) error {
+	return s.repo.RemoveNodeTag(nodeID, tag)
+}
+
+// ListByTag returns the nodes carrying a tag. Tags are matched case-insensitively.
+func (s *Service) ListByTag(tag string) ([]memory.Node, error) {
+	if memory.NormalizeTag(tag) == "" {
+		return nil, fmt.Errorf("tag cannot be empty")
+	}
+	nodes, err := s.repo.ListNodesByTag(tag)
+	if err != nil {
+		return nil, fmt.Errorf("list nodes by tag: %w", err)
+	}
+	return nodes, nil
+}
+
+// ListPendingVerification returns nodes still awaiting verification,
+// lowest confidence first.
+func (s *Service) ListPendingVerification() ([]memory.Node, error) {
+	nodes, err := s.repo.ListNodes("")
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	var pending []memory.Node
+	for _, n := range nodes {
+		if n.VerificationStatus == string(core.VerificationStatusPending) {
+			pending = append(pending, n)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].ConfidenceScore < pending[j].ConfidenceScore
+	})
+	return pending, nil
+}
+
 // Ask generates an answer based on the search results
 func (s *Service) Ask(ctx context.Context, query string, contextNodes []ScoredNode) (string, error) {
 	if len(contextNodes) == 0 {
@@ -609,6 +794,11 @@ func (s *Service) AddNode(ctx context.Context, input NodeInput) (*memory.Node, e
 		SourceAgent: input.SourceAgent,
 		CreatedAt:   input.Timestamp,
 	}
+	for _, tag := range input.Tags {
+		if tag = memory.NormalizeTag(tag); tag != "" && !slices.Contains(node.Tags, tag) {
+			node.Tags = append(node.Tags, tag)
+		}
+	}
 
 	// Default source agent for remember path
 	if node.SourceAgent == "" {
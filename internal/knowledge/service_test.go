@@ -0,0 +1,502 @@
+package knowledge
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/llm"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+func TestService_Related(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	checkout := &memory.Node{ID: "n-checkout", Type: memory.NodeTypeFeature, Summary: "Checkout", Content: "Checkout flow"}
+	payments := &memory.Node{ID: "n-payments", Type: memory.NodeTypeFeature, Summary: "Payments", Content: "Payment processing"}
+	audit := &memory.Node{ID: "n-audit", Type: memory.NodeTypeFeature, Summary: "Audit log", Content: "Audit logging"}
+	for _, n := range []*memory.Node{checkout, payments, audit} {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode %s: %v", n.Summary, err)
+		}
+	}
+	if err := repo.LinkNodes(checkout.ID, payments.ID, memory.NodeRelationDependsOn, 1.0, nil); err != nil {
+		t.Fatalf("LinkNodes: %v", err)
+	}
+	if err := repo.LinkNodes(checkout.ID, audit.ID, memory.NodeRelationRelatesTo, 1.0, nil); err != nil {
+		t.Fatalf("LinkNodes: %v", err)
+	}
+
+	svc := NewService(repo, llm.Config{})
+	deps, err := svc.Related(ctx, checkout.ID, memory.NodeRelationDependsOn)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	if len(deps) != 1 || deps[0].ID != payments.ID {
+		t.Errorf("depends_on from checkout = %v, want only Payments", deps)
+	}
+
+	// Edges are followed in both directions
+	back, err := svc.Related(ctx, payments.ID, "")
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	if len(back) != 1 || back[0].ID != checkout.ID {
+		t.Errorf("related to payments = %v, want only Checkout", back)
+	}
+
+	all, err := svc.Related(ctx, checkout.ID, "")
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected 2 related nodes for any relation, got %d", len(all))
+	}
+}
+
+func TestService_VerifiedOutranksUnverified(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	// Identical content so FTS scores tie before verification is considered
+	for _, id := range []string{"n-first", "n-second"} {
+		n := &memory.Node{
+			ID: id, Type: memory.NodeTypeDecision, Summary: "Use SQLite for storage",
+			Content:            "Use SQLite for local storage",
+			VerificationStatus: "pending_verification", ConfidenceScore: 0.4,
+		}
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	cfg := DefaultRetrievalConfig()
+	cfg.VectorWeight = 0
+	cfg.FTSWeight = 1.0
+	cfg.GraphExpansionEnabled = false
+	cfg.QueryRewriteEnabled = false
+	cfg.MinResultScoreThreshold = 0 // BM25 scores are weak in a two-document corpus
+	svc := NewServiceWithConfig(repo, llm.Config{}, cfg)
+
+	if err := svc.Verify("n-second", "verified"); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if err := svc.Verify("n-first", "maybe"); err == nil {
+		t.Error("expected error for invalid verification status")
+	}
+
+	results, err := svc.Search(ctx, "SQLite storage", 5)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Node.ID != "n-second" || results[0].Score <= results[1].Score {
+		t.Errorf("verified node should outrank unverified: got %s (%.3f) before %s (%.3f)",
+			results[0].Node.ID, results[0].Score, results[1].Node.ID, results[1].Score)
+	}
+
+	pending, err := svc.ListPendingVerification()
+	if err != nil {
+		t.Fatalf("ListPendingVerification: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != "n-first" {
+		t.Errorf("pending = %v, want only n-first", pending)
+	}
+
+	if err := svc.Verify("n-first", "rejected"); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	results, err = svc.Search(ctx, "SQLite storage", 5)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].Node.ID != "n-second" {
+		t.Errorf("rejected node should be excluded from recall, got %d results", len(results))
+	}
+}
+
+func TestService_RegenerateFeatureDoc(t *testing.T) {
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	dir := t.TempDir()
+	repo := memory.NewRepository(store, memory.NewMarkdownStore(dir))
+
+	content, _ := json.Marshal(memory.StructuredContent{
+		Title:       "Checkout",
+		Description: "Orders are placed through a single checkout flow.",
+		Why:         "Keeps payment handling in one place.",
+		Snippets:    []memory.EvidenceSnippet{{FilePath: "internal/checkout/flow.go", Lines: "10-24", Code: "func PlaceOrderWithRetry(ctx context.Context) error {"}},
+	})
+	evidence, _ := json.Marshal([]core.Evidence{
+		{FilePath: "internal/checkout/cart.go", StartLine: 5, EndLine: 9, Snippet: "type CartLineItem struct {"},
+		{FilePath: "internal/checkout/flow.go", StartLine: 10, EndLine: 24, Snippet: "func PlaceOrderWithRetry(ctx context.Context) error {"},
+	})
+	checkout := &memory.Node{ID: "n-checkout", Type: memory.NodeTypeFeature, Summary: "Checkout", Content: string(content), Evidence: string(evidence)}
+	payments := &memory.Node{ID: "n-payments", Type: memory.NodeTypeFeature, Summary: "Payments", Content: "Payment processing"}
+	for _, n := range []*memory.Node{checkout, payments} {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode %s: %v", n.Summary, err)
+		}
+	}
+	if err := repo.LinkNodes(checkout.ID, payments.ID, memory.NodeRelationDependsOn, 1.0, nil); err != nil {
+		t.Fatalf("LinkNodes: %v", err)
+	}
+
+	svc := NewService(repo, llm.Config{})
+	path, err := svc.RegenerateFeatureDoc(checkout.ID)
+	if err != nil {
+		t.Fatalf("RegenerateFeatureDoc: %v", err)
+	}
+	if want := filepath.Join(dir, "features", "n-checkout.md"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read feature doc: %v", err)
+	}
+	page := string(data)
+
+	for _, want := range []string{"# Checkout", "single checkout flow", "`internal/checkout/cart.go:5-9`", "`internal/checkout/flow.go:10-24`", "depends_on: **Payments**"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page missing %q:\n%s", want, page)
+		}
+	}
+	if strings.Count(page, "internal/checkout/flow.go") != 1 {
+		t.Errorf("duplicate evidence should be listed once:\n%s", page)
+	}
+	for _, symbol := range []string{"PlaceOrderWithRetry", "CartLineItem"} {
+		if strings.Contains(page, symbol) {
+			t.Errorf("page leaks symbol %q:\n%s", symbol, page)
+		}
+	}
+
+	if _, err := svc.RegenerateFeatureDoc("n-missing"); err == nil {
+		t.Error("expected error for unknown feature")
+	}
+}
+
+func TestService_FeatureGraph(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	for _, n := range []*memory.Node{
+		{ID: "n-checkout", Type: memory.NodeTypeFeature, Summary: "Checkout", Content: "Checkout flow"},
+		{ID: "n-payments", Type: memory.NodeTypeFeature, Summary: "Payments", Content: "Payment processing"},
+		{ID: "n-ledger", Type: memory.NodeTypeFeature, Summary: "Ledger", Content: "Ledger"},
+		{ID: "n-decision", Type: memory.NodeTypeDecision, Summary: "Use Stripe", Content: "Use Stripe"},
+	} {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode %s: %v", n.Summary, err)
+		}
+	}
+	link := func(from, to, relation string) {
+		t.Helper()
+		if err := repo.LinkNodes(from, to, relation, 1.0, nil); err != nil {
+			t.Fatalf("LinkNodes %s->%s: %v", from, to, err)
+		}
+	}
+	link("n-checkout", "n-payments", memory.NodeRelationDependsOn)
+	link("n-checkout", "n-decision", memory.NodeRelationDependsOn) // not a feature
+	link("n-checkout", "n-ledger", memory.NodeRelationSemanticallySimilar)
+
+	svc := NewService(repo, llm.Config{})
+	graph, err := svc.FeatureGraph(ctx)
+	if err != nil {
+		t.Fatalf("FeatureGraph: %v", err)
+	}
+	if len(graph.Nodes) != 3 {
+		t.Errorf("expected 3 feature nodes, got %v", graph.Nodes)
+	}
+	want := FeatureGraphEdge{From: "n-checkout", To: "n-payments", Relation: memory.NodeRelationDependsOn}
+	if len(graph.Edges) != 1 || graph.Edges[0] != want {
+		t.Fatalf("edges = %v, want only %v", graph.Edges, want)
+	}
+	if len(graph.Cycles) != 0 {
+		t.Errorf("unexpected cycles: %v", graph.Cycles)
+	}
+	mermaid := graph.Mermaid()
+	for _, line := range []string{"graph LR", `n_checkout["Checkout"]`, "n_checkout -->|depends_on| n_payments"} {
+		if !strings.Contains(mermaid, line) {
+			t.Errorf("mermaid missing %q:\n%s", line, mermaid)
+		}
+	}
+
+	// Closing the loop through Ledger creates a dependency cycle
+	link("n-payments", "n-ledger", memory.NodeRelationDependsOn)
+	link("n-ledger", "n-checkout", memory.NodeRelationDependsOn)
+	graph, err = svc.FeatureGraph(ctx)
+	if err != nil {
+		t.Fatalf("FeatureGraph: %v", err)
+	}
+	if len(graph.Cycles) != 1 || strings.Join(graph.Cycles[0], ",") != "n-checkout,n-ledger,n-payments" {
+		t.Fatalf("cycles = %v, want checkout/ledger/payments", graph.Cycles)
+	}
+	if !strings.Contains(graph.Mermaid(), "class n_checkout,n_ledger,n_payments cycle") {
+		t.Errorf("mermaid should highlight the cycle:\n%s", graph.Mermaid())
+	}
+}
+
+func TestService_ListByTag(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+	svc := NewService(repo, llm.Config{})
+
+	secrets, err := svc.AddNode(ctx, NodeInput{Content: "Secrets are read from Vault at startup", Type: memory.NodeTypeDecision, Summary: "Secrets from Vault", Tags: []string{"Security"}})
+	if err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	cache, err := svc.AddNode(ctx, NodeInput{Content: "Responses are cached in Redis for 5 minutes", Type: memory.NodeTypeDecision, Summary: "Redis response cache"})
+	if err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	if err := svc.AddTag(cache.ID, "perf"); err != nil {
+		t.Fatalf("AddTag: %v", err)
+	}
+	if err := svc.AddTag(cache.ID, "security"); err != nil {
+		t.Fatalf("AddTag: %v", err)
+	}
+
+	tagged, err := svc.ListByTag("SECURITY")
+	if err != nil {
+		t.Fatalf("ListByTag: %v", err)
+	}
+	if len(tagged) != 2 {
+		t.Fatalf("security nodes = %v, want Vault and Redis", tagged)
+	}
+
+	if err := svc.RemoveTag(cache.ID, "security"); err != nil {
+		t.Fatalf("RemoveTag: %v", err)
+	}
+	tagged, err = svc.ListByTag("security")
+	if err != nil {
+		t.Fatalf("ListByTag: %v", err)
+	}
+	if len(tagged) != 1 || tagged[0].ID != secrets.ID {
+		t.Errorf("security nodes after untag = %v, want only %s", tagged, secrets.ID)
+	}
+
+	perf, err := svc.ListByTag("perf")
+	if err != nil {
+		t.Fatalf("ListByTag: %v", err)
+	}
+	if len(perf) != 1 || perf[0].ID != cache.ID || !slices.Contains(perf[0].Tags, "perf") {
+		t.Errorf("perf nodes = %v, want only %s", perf, cache.ID)
+	}
+
+	if _, err := svc.ListByTag("  "); err == nil {
+		t.Error("expected error for empty tag")
+	}
+}
+
+func TestService_PruneRedundantRelations(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	for _, id := range []string{"n-a", "n-b", "n-c"} {
+		if err := repo.CreateNode(&memory.Node{ID: id, Type: memory.NodeTypeFeature, Summary: id, Content: id}); err != nil {
+			t.Fatalf("CreateNode %s: %v", id, err)
+		}
+	}
+	for _, e := range [][2]string{{"n-a", "n-b"}, {"n-b", "n-c"}, {"n-a", "n-c"}} {
+		if err := repo.LinkNodes(e[0], e[1], memory.NodeRelationDependsOn, 1.0, nil); err != nil {
+			t.Fatalf("LinkNodes %s->%s: %v", e[0], e[1], err)
+		}
+	}
+	// Other relation types are not dependency paths and are left alone
+	if err := repo.LinkNodes("n-a", "n-c", memory.NodeRelationRelatesTo, 1.0, nil); err != nil {
+		t.Fatalf("LinkNodes: %v", err)
+	}
+
+	svc := NewService(repo, llm.Config{})
+	pruned, err := svc.PruneRedundantRelations(ctx)
+	if err != nil {
+		t.Fatalf("PruneRedundantRelations: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned = %d, want 1", pruned)
+	}
+
+	edges, err := repo.GetAllNodeEdges()
+	if err != nil {
+		t.Fatalf("GetAllNodeEdges: %v", err)
+	}
+	remaining := make(map[string]bool)
+	for _, e := range edges {
+		remaining[e.FromNode+">"+e.ToNode+":"+e.Relation] = true
+	}
+	want := []string{"n-a>n-b:depends_on", "n-b>n-c:depends_on", "n-a>n-c:relates_to"}
+	if len(remaining) != len(want) {
+		t.Errorf("remaining edges = %v, want %v", remaining, want)
+	}
+	for _, key := range want {
+		if !remaining[key] {
+			t.Errorf("edge %s should remain, got %v", key, remaining)
+		}
+	}
+
+	// Nothing left to prune on a second pass
+	if pruned, err := svc.PruneRedundantRelations(ctx); err != nil || pruned != 0 {
+		t.Errorf("second pass pruned %d, err %v; want 0", pruned, err)
+	}
+}
+
+func TestService_DetectConstraintConflicts(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	// Both data-access constraints share a topic; the logging one does not
+	nodes := []*memory.Node{
+		{ID: "c-orm", Type: memory.NodeTypeConstraint, Summary: "Always use the ORM for database access", Content: "All queries go through the ORM.", Embedding: []float32{1, 0.1, 0}},
+		{ID: "c-raw", Type: memory.NodeTypeConstraint, Summary: "Use raw SQL instead of the ORM on hot paths", Content: "Hot paths skip the ORM.", Embedding: []float32{0.95, 0.2, 0}},
+		{ID: "c-logs", Type: memory.NodeTypeConstraint, Summary: "Never log secrets", Content: "Redact tokens in logs.", Embedding: []float32{0, 0.1, 1}},
+		{ID: "d-orm", Type: memory.NodeTypeDecision, Summary: "Do not use an ORM for migrations", Content: "Migrations are plain SQL.", Embedding: []float32{1, 0.1, 0}},
+	}
+	for _, n := range nodes {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode %s: %v", n.ID, err)
+		}
+	}
+
+	svc := NewService(repo, llm.Config{})
+	conflicts, err := svc.DetectConstraintConflicts(ctx, nil)
+	if err != nil {
+		t.Fatalf("DetectConstraintConflicts: %v", err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %v, want only the ORM pair", conflicts)
+	}
+	pair := []string{conflicts[0].A.ID, conflicts[0].B.ID}
+	if !slices.Contains(pair, "c-orm") || !slices.Contains(pair, "c-raw") {
+		t.Errorf("conflict = %v, want c-orm and c-raw", pair)
+	}
+
+	related, err := svc.Related(ctx, "c-orm", memory.NodeRelationConflictsWith)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	if len(related) != 1 || related[0].ID != "c-raw" {
+		t.Errorf("conflicts_with edges from c-orm = %v, want c-raw", related)
+	}
+
+	// Linked pairs are not reported again
+	if again, err := svc.DetectConstraintConflicts(ctx, nil); err != nil || len(again) != 0 {
+		t.Errorf("second pass = %v, err %v; want no new conflicts", again, err)
+	}
+
+	// A new constraint is compared against the existing ones only
+	if err := repo.CreateNode(&memory.Node{ID: "c-logs-debug", Type: memory.NodeTypeConstraint, Summary: "Log secrets in debug builds", Content: "Debug logs keep tokens.", Embedding: []float32{0, 0.12, 1}}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+	conflicts, err = svc.DetectConstraintConflicts(ctx, []string{"Log secrets in debug builds"})
+	if err != nil {
+		t.Fatalf("DetectConstraintConflicts: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].A.ID != "c-logs-debug" || conflicts[0].B.ID != "c-logs" {
+		t.Errorf("conflicts = %v, want c-logs-debug against c-logs", conflicts)
+	}
+}
+
+func TestService_AccessCountBoostsRecall(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	// Same length and shared terms so only access counts separate them
+	for id, word := range map[string]string{"n-alpha": "alpha", "n-beta": "beta"} {
+		n := &memory.Node{
+			ID: id, Type: memory.NodeTypeDecision, Summary: "Use SQLite for storage " + word,
+			Content: "Use SQLite for local storage " + word,
+		}
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	cfg := DefaultRetrievalConfig()
+	cfg.VectorWeight = 0
+	cfg.FTSWeight = 1.0
+	cfg.GraphExpansionEnabled = false
+	cfg.QueryRewriteEnabled = false
+	cfg.MinResultScoreThreshold = 0
+	svc := NewServiceWithConfig(repo, llm.Config{}, cfg)
+
+	// Searching alone must not count: context gathering searches too
+	if _, err := svc.Search(ctx, "SQLite storage", 5); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	for range 3 {
+		svc.RecordAccess([]string{"n-beta"})
+	}
+	svc.RecordAccess([]string{"n-alpha"})
+
+	for id, want := range map[string]int{"n-alpha": 1, "n-beta": 3} {
+		n, err := repo.GetNode(id)
+		if err != nil {
+			t.Fatalf("GetNode: %v", err)
+		}
+		if n.AccessCount != want {
+			t.Errorf("%s access count = %d, want %d", id, n.AccessCount, want)
+		}
+	}
+
+	top, err := svc.TopAccessed(5)
+	if err != nil {
+		t.Fatalf("TopAccessed: %v", err)
+	}
+	if len(top) != 2 || top[0].ID != "n-beta" || top[1].ID != "n-alpha" {
+		t.Errorf("TopAccessed returned %d nodes, want n-beta then n-alpha", len(top))
+	}
+
+	results, err := svc.Search(ctx, "SQLite storage", 5)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 || results[0].Node.ID != "n-beta" || results[0].Score <= results[1].Score {
+		t.Errorf("more accessed node should rank first, got %+v", results)
+	}
+}
@@ -63,6 +63,11 @@ type Config struct {
 	BaseURL        string        // Optional custom endpoint (OpenAI-compatible/Ollama)
 	ThinkingBudget int           // Token budget for extended thinking (0 = disabled, only for supported models)
 	Timeout        time.Duration // Request timeout for chat completions (0 = no timeout)
+	AgentTimeout   time.Duration // Deadline for one agent chain invocation, retries included (0 = no deadline)
+
+	// RequestsPerMinute caps chat requests per minute across every chat model
+	// built for the same provider and endpoint (0 = unlimited)
+	RequestsPerMinute int
 
 	// Embedding-specific provider (optional, defaults to Provider if empty)
 	EmbeddingProvider Provider
@@ -132,7 +137,18 @@ func newOpenAICompatibleChatModel(ctx context.Context, cfg Config, timeout time.
 
 // NewCloseableChatModel creates a ChatModel with proper resource management.
 // Callers MUST call Close() when done to release resources.
+// With cfg.RequestsPerMinute set, calls wait on a limiter shared process-wide.
 func NewCloseableChatModel(ctx context.Context, cfg Config) (*CloseableChatModel, error) {
+	m, err := newProviderChatModel(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	m.BaseChatModel = WithRateLimit(m.BaseChatModel, sharedRateLimiter(cfg))
+	return m, nil
+}
+
+// newProviderChatModel creates the provider-specific chat model for cfg.
+func newProviderChatModel(ctx context.Context, cfg Config) (*CloseableChatModel, error) {
 	timeout := GetEffectiveTimeout(&cfg)
 
 	switch cfg.Provider {
@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RateLimiter is a token bucket that paces requests to a steady rate.
+// Safe for concurrent use.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // Time to refill one token
+	burst    float64       // Bucket capacity
+	tokens   float64
+	last     time.Time
+}
+
+// NewRateLimiter allows requestsPerMinute requests per minute, of which up to
+// burst may be issued back to back. burst below 1 is treated as 1.
+func NewRateLimiter(requestsPerMinute, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		interval: time.Minute / time.Duration(requestsPerMinute),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a request may be issued or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+	l.last = now
+	// Reserve a token; a negative balance is the queue of waiting callers
+	l.tokens--
+	delay := time.Duration(-l.tokens * float64(l.interval))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Hand the reservation back so later callers are not delayed by it
+		l.mu.Lock()
+		l.tokens = min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// sharedLimiters holds one limiter per endpoint and rate, so every chat model
+// built from the same config draws from the same bucket.
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = make(map[string]*RateLimiter)
+)
+
+// sharedRateLimiter returns the process-wide limiter for cfg, or nil when
+// cfg.RequestsPerMinute is not set.
+func sharedRateLimiter(cfg Config) *RateLimiter {
+	if cfg.RequestsPerMinute <= 0 {
+		return nil
+	}
+	key := fmt.Sprintf("%s|%s|%d", cfg.Provider, cfg.BaseURL, cfg.RequestsPerMinute)
+
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+	l, ok := sharedLimiters[key]
+	if !ok {
+		l = NewRateLimiter(cfg.RequestsPerMinute, 1)
+		sharedLimiters[key] = l
+	}
+	return l
+}
+
+// WithRateLimit wraps m so every Generate and Stream call first waits on l.
+// Tool-calling models stay tool-calling, and models derived through WithTools
+// share the same limiter.
+func WithRateLimit(m model.BaseChatModel, l *RateLimiter) model.BaseChatModel {
+	if l == nil {
+		return m
+	}
+	base := &rateLimitedChatModel{model: m, limiter: l}
+	if tc, ok := m.(model.ToolCallingChatModel); ok {
+		return &rateLimitedToolCallingModel{rateLimitedChatModel: base, toolModel: tc}
+	}
+	return base
+}
+
+type rateLimitedChatModel struct {
+	model   model.BaseChatModel
+	limiter *RateLimiter
+}
+
+func (m *rateLimitedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if err := m.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return m.model.Generate(ctx, input, opts...)
+}
+
+func (m *rateLimitedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if err := m.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return m.model.Stream(ctx, input, opts...)
+}
+
+type rateLimitedToolCallingModel struct {
+	*rateLimitedChatModel
+	toolModel model.ToolCallingChatModel
+}
+
+func (m *rateLimitedToolCallingModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	withTools, err := m.toolModel.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return WithRateLimit(withTools, m.limiter).(model.ToolCallingChatModel), nil
+}
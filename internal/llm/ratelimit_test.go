@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// recordingChatModel records when each Generate call reaches the provider.
+type recordingChatModel struct {
+	mu    sync.Mutex
+	calls []time.Time
+}
+
+func (m *recordingChatModel) Generate(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, time.Now())
+	m.mu.Unlock()
+	return schema.AssistantMessage("ok", nil), nil
+}
+
+func (m *recordingChatModel) Stream(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("ok", nil)}), nil
+}
+
+func TestWithRateLimit_PacesConcurrentCalls(t *testing.T) {
+	const (
+		rpm   = 1200 // one request every 50ms
+		calls = 6
+	)
+	interval := time.Minute / rpm
+	fake := &recordingChatModel{}
+	limited := WithRateLimit(fake, NewRateLimiter(rpm, 1))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limited.Generate(context.Background(), nil); err != nil {
+				t.Errorf("Generate: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(fake.calls) != calls {
+		t.Fatalf("provider saw %d calls, want %d", len(fake.calls), calls)
+	}
+	slices.SortFunc(fake.calls, func(a, b time.Time) int { return a.Compare(b) })
+	// The i-th call may not be issued before i intervals have passed
+	for i, at := range fake.calls {
+		if elapsed := at.Sub(start); elapsed < time.Duration(i)*interval {
+			t.Errorf("call %d issued after %v, want at least %v", i, elapsed, time.Duration(i)*interval)
+		}
+	}
+
+	// A cancelled context never reaches the provider
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limited.Generate(ctx, nil); err == nil {
+		t.Error("expected an error for a cancelled context")
+	}
+	if len(fake.calls) != calls {
+		t.Errorf("cancelled call reached the provider")
+	}
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/josephgoksu/TaskWing/internal/app"
+)
+
+// ErrorCode is a stable, machine-readable identifier for a tool failure, so
+// clients can branch without parsing the Markdown message.
+type ErrorCode string
+
+const (
+	ErrorCodeNoActivePlan   ErrorCode = "NO_ACTIVE_PLAN"
+	ErrorCodeSymbolNotFound ErrorCode = "SYMBOL_NOT_FOUND"
+	ErrorCodePolicyBlocked  ErrorCode = "POLICY_BLOCKED"
+	ErrorCodeInvalidInput   ErrorCode = "INVALID_INPUT"
+	ErrorCodeReadOnly       ErrorCode = "READ_ONLY"
+	ErrorCodeToolFailed     ErrorCode = "TOOL_FAILED" // Any failure without a more specific code
+)
+
+// ToolError is the error envelope returned in a failed tool result's
+// structured content, next to the Markdown shown to the user.
+type ToolError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// NewToolError builds an envelope; an empty code becomes ErrorCodeToolFailed.
+func NewToolError(code ErrorCode, message string) ToolError {
+	if code == "" {
+		code = ErrorCodeToolFailed
+	}
+	return ToolError{Code: code, Message: message}
+}
+
+// ErrorCodeOf returns the code for err: plan sentinels map to their plan
+// error code in upper case (e.g. NO_ACTIVE_PLAN), other errors to TOOL_FAILED.
+func ErrorCodeOf(err error) ErrorCode {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrReadOnly):
+		return ErrorCodeReadOnly
+	case errors.Is(err, app.ErrSymbolNotFound):
+		return ErrorCodeSymbolNotFound
+	}
+	if code := planErrorCode(app.PlanErrorCodeOf(err)); code != "" {
+		return code
+	}
+	return ErrorCodeToolFailed
+}
+
+// planErrorCode converts a plan error code to its tool error code.
+func planErrorCode(code app.PlanErrorCode) ErrorCode {
+	return ErrorCode(strings.ToUpper(string(code)))
+}
@@ -3,10 +3,12 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	agentcore "github.com/josephgoksu/TaskWing/internal/agents/core"
@@ -14,16 +16,20 @@ import (
 	"github.com/josephgoksu/TaskWing/internal/app"
 	"github.com/josephgoksu/TaskWing/internal/codeintel"
 	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/knowledge"
 	"github.com/josephgoksu/TaskWing/internal/llm"
 	"github.com/josephgoksu/TaskWing/internal/memory"
+	"github.com/josephgoksu/TaskWing/internal/policy"
+	"github.com/josephgoksu/TaskWing/internal/task"
 	"github.com/josephgoksu/TaskWing/internal/utils"
 )
 
 // CodeToolResult represents the response from the unified code tool.
 type CodeToolResult struct {
-	Action  string `json:"action"`
-	Content string `json:"content"`
-	Error   string `json:"error,omitempty"`
+	Action    string    `json:"action"`
+	Content   string    `json:"content"`
+	Error     string    `json:"error,omitempty"`
+	ErrorCode ErrorCode `json:"error_code,omitempty"` // Machine-readable code when Error is set
 }
 
 // HandleCodeTool is the unified handler for all code intelligence operations.
@@ -33,8 +39,9 @@ func HandleCodeTool(ctx context.Context, repo *memory.Repository, params CodeToo
 	// Validate action
 	if !params.Action.IsValid() {
 		return &CodeToolResult{
-			Action: string(params.Action),
-			Error:  fmt.Sprintf("invalid action %q, must be one of: find, search, explain, callers, impact, simplify", params.Action),
+			Action:    string(params.Action),
+			Error:     fmt.Sprintf("invalid action %q, must be one of: find, search, explain, explain_file, callers, impact, simplify, changed, search_sig, imports, tree, usages, implementations", params.Action),
+			ErrorCode: ErrorCodeInvalidInput,
 		}, nil
 	}
 
@@ -43,14 +50,28 @@ func HandleCodeTool(ctx context.Context, repo *memory.Repository, params CodeToo
 		return handleCodeFind(ctx, repo, params)
 	case CodeActionSearch:
 		return handleCodeSearch(ctx, repo, params)
+	case CodeActionSearchSig:
+		return handleCodeSearchSig(ctx, repo, params)
 	case CodeActionExplain:
 		return handleCodeExplain(ctx, repo, params)
+	case CodeActionExplainFile:
+		return handleCodeExplainFile(ctx, repo, params)
+	case CodeActionImports:
+		return handleCodeImports(ctx, repo, params)
+	case CodeActionTree:
+		return handleCodeTree(ctx, repo, params)
 	case CodeActionCallers:
 		return handleCodeCallers(ctx, repo, params)
+	case CodeActionUsages:
+		return handleCodeUsages(ctx, repo, params)
+	case CodeActionImpls:
+		return handleCodeImplementations(ctx, repo, params)
 	case CodeActionImpact:
 		return handleCodeImpact(ctx, repo, params)
 	case CodeActionSimplify:
 		return handleCodeSimplify(ctx, repo, params)
+	case CodeActionChanged:
+		return handleCodeChanged(ctx, repo, params)
 	default:
 		// This should never happen due to IsValid() check above
 		return &CodeToolResult{
@@ -115,10 +136,14 @@ func handleCodeSearch(ctx context.Context, repo *memory.Repository, params CodeT
 	codeIntelApp := app.NewCodeIntelApp(appCtx)
 
 	result, err := codeIntelApp.SearchCode(ctx, app.SearchCodeOptions{
-		Query:    query,
-		Limit:    limit,
-		Kind:     codeintel.SymbolKind(params.Kind),
-		FilePath: params.FilePath,
+		Query:          query,
+		Limit:          limit,
+		Kind:           codeintel.SymbolKind(params.Kind),
+		FilePath:       params.FilePath,
+		ExcludePrivate: !includePrivate(params),
+		MinScore:       params.MinScore,
+		Debug:          params.Debug,
+		Language:       strings.TrimSpace(params.Language),
 	})
 	if err != nil {
 		return &CodeToolResult{
@@ -133,6 +158,52 @@ func handleCodeSearch(ctx context.Context, repo *memory.Repository, params CodeT
 	}, nil
 }
 
+// includePrivate resolves include_private, which defaults to true.
+func includePrivate(params CodeToolParams) bool {
+	return params.IncludePrivate == nil || *params.IncludePrivate
+}
+
+// handleCodeSearchSig implements the 'search_sig' action - find symbols by signature shape.
+func handleCodeSearchSig(ctx context.Context, repo *memory.Repository, params CodeToolParams) (*CodeToolResult, error) {
+	pattern := strings.TrimSpace(params.Query)
+	if pattern == "" {
+		return &CodeToolResult{
+			Action: "search_sig",
+			Error:  "query is required for search_sig action (e.g. \"context.Context, string\")",
+		}, nil
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	const maxLimit = 100
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	appCtx := app.NewContext(repo)
+	codeIntelApp := app.NewCodeIntelApp(appCtx)
+
+	result, err := codeIntelApp.SearchCode(ctx, app.SearchCodeOptions{
+		Query:          pattern,
+		Limit:          limit,
+		BySignature:    true,
+		ExcludePrivate: !includePrivate(params),
+	})
+	if err != nil {
+		return &CodeToolResult{
+			Action: "search_sig",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &CodeToolResult{
+		Action:  "search_sig",
+		Content: FormatSearchResults(result.Results),
+	}, nil
+}
+
 // handleCodeExplain implements the 'explain' action - deep dive into a symbol.
 func handleCodeExplain(ctx context.Context, repo *memory.Repository, params CodeToolParams) (*CodeToolResult, error) {
 	// Input validation
@@ -172,14 +243,181 @@ func handleCodeExplain(ctx context.Context, repo *memory.Repository, params Code
 	})
 	if err != nil {
 		return &CodeToolResult{
-			Action: "explain",
-			Error:  err.Error(),
+			Action:    "explain",
+			Error:     err.Error(),
+			ErrorCode: ErrorCodeOf(err),
 		}, nil
 	}
 
 	return &CodeToolResult{
 		Action:  "explain",
-		Content: FormatExplainResult(result),
+		Content: FormatExplainResult(result, params.Verbose),
+	}, nil
+}
+
+// handleCodeExplainFile implements the 'explain_file' action - summarize a whole file.
+func handleCodeExplainFile(ctx context.Context, repo *memory.Repository, params CodeToolParams) (*CodeToolResult, error) {
+	filePath := strings.TrimSpace(params.FilePath)
+	if filePath == "" {
+		return &CodeToolResult{
+			Action: "explain_file",
+			Error:  "file_path is required for explain_file action",
+		}, nil
+	}
+
+	basePath, err := config.GetProjectRoot()
+	if err != nil {
+		return &CodeToolResult{
+			Action: "explain_file",
+			Error:  fmt.Sprintf("failed to resolve project root: %v", err),
+		}, nil
+	}
+	absPath, err := validateAndResolvePath(filePath, basePath)
+	if err != nil {
+		return &CodeToolResult{
+			Action: "explain_file",
+			Error:  err.Error(),
+		}, nil
+	}
+	relPath, err := filepath.Rel(basePath, absPath)
+	if err != nil {
+		return &CodeToolResult{
+			Action: "explain_file",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	appCtx := app.NewContextForRole(repo, llm.RoleQuery)
+	appCtx.BasePath = basePath
+	explainApp := app.NewExplainApp(appCtx)
+
+	result, err := explainApp.ExplainFile(ctx, app.ExplainFileRequest{Path: relPath})
+	if err != nil {
+		return &CodeToolResult{
+			Action: "explain_file",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &CodeToolResult{
+		Action:  "explain_file",
+		Content: FormatFileExplainResult(result),
+	}, nil
+}
+
+// handleCodeImports implements the 'imports' action - list a file's dependencies.
+func handleCodeImports(ctx context.Context, repo *memory.Repository, params CodeToolParams) (*CodeToolResult, error) {
+	filePath := strings.TrimSpace(params.FilePath)
+	if filePath == "" {
+		return &CodeToolResult{
+			Action: "imports",
+			Error:  "file_path is required for imports action",
+		}, nil
+	}
+
+	basePath, err := config.GetProjectRoot()
+	if err != nil {
+		return &CodeToolResult{
+			Action: "imports",
+			Error:  fmt.Sprintf("failed to resolve project root: %v", err),
+		}, nil
+	}
+	absPath, err := validateAndResolvePath(filePath, basePath)
+	if err != nil {
+		return &CodeToolResult{
+			Action: "imports",
+			Error:  err.Error(),
+		}, nil
+	}
+	relPath, err := filepath.Rel(basePath, absPath)
+	if err != nil {
+		return &CodeToolResult{
+			Action: "imports",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	appCtx := app.NewContext(repo)
+	codeIntelApp := app.NewCodeIntelApp(appCtx)
+
+	result, err := codeIntelApp.FileImports(ctx, relPath)
+	if err != nil {
+		return &CodeToolResult{
+			Action: "imports",
+			Error:  err.Error(),
+		}, nil
+	}
+	if !result.Success {
+		return &CodeToolResult{
+			Action: "imports",
+			Error:  result.Message,
+		}, nil
+	}
+
+	return &CodeToolResult{
+		Action:  "imports",
+		Content: FormatFileImports(result.Imports),
+	}, nil
+}
+
+// handleCodeTree implements the 'tree' action - list a directory's symbols by file.
+func handleCodeTree(ctx context.Context, repo *memory.Repository, params CodeToolParams) (*CodeToolResult, error) {
+	dirPath := strings.TrimSpace(params.FilePath)
+	if dirPath == "" {
+		return &CodeToolResult{
+			Action: "tree",
+			Error:  "file_path is required for tree action",
+		}, nil
+	}
+
+	basePath, err := config.GetProjectRoot()
+	if err != nil {
+		return &CodeToolResult{
+			Action: "tree",
+			Error:  fmt.Sprintf("failed to resolve project root: %v", err),
+		}, nil
+	}
+	// validateAndResolvePath only accepts files, so resolve the directory here
+	var absPath string
+	if filepath.IsAbs(dirPath) {
+		absPath, err = utils.ValidateAbsPath(basePath, dirPath)
+	} else {
+		absPath, err = utils.SafeJoin(basePath, dirPath)
+	}
+	if err != nil {
+		return &CodeToolResult{
+			Action: "tree",
+			Error:  fmt.Sprintf("path not allowed: %v", err),
+		}, nil
+	}
+	relPath, err := filepath.Rel(basePath, absPath)
+	if err != nil {
+		return &CodeToolResult{
+			Action: "tree",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	appCtx := app.NewContext(repo)
+	codeIntelApp := app.NewCodeIntelApp(appCtx)
+
+	result, err := codeIntelApp.ModuleTree(ctx, relPath)
+	if err != nil {
+		return &CodeToolResult{
+			Action: "tree",
+			Error:  err.Error(),
+		}, nil
+	}
+	if !result.Success {
+		return &CodeToolResult{
+			Action: "tree",
+			Error:  result.Message,
+		}, nil
+	}
+
+	return &CodeToolResult{
+		Action:  "tree",
+		Content: FormatModuleTree(result.Tree),
 	}, nil
 }
 
@@ -209,9 +447,94 @@ func handleCodeCallers(ctx context.Context, repo *memory.Repository, params Code
 		}, nil
 	}
 
+	if result.NotFound {
+		return &CodeToolResult{
+			Action:    "callers",
+			Content:   FormatCallers(result, params.Verbose),
+			Error:     result.Message,
+			ErrorCode: ErrorCodeSymbolNotFound,
+		}, nil
+	}
+
 	return &CodeToolResult{
 		Action:  "callers",
-		Content: FormatCallers(result),
+		Content: FormatCallers(result, params.Verbose),
+	}, nil
+}
+
+// handleCodeUsages implements the 'usages' action - callers plus reference sites.
+func handleCodeUsages(ctx context.Context, repo *memory.Repository, params CodeToolParams) (*CodeToolResult, error) {
+	symbolName := strings.TrimSpace(params.Query)
+	if params.SymbolID == 0 && symbolName == "" {
+		return &CodeToolResult{
+			Action: "usages",
+			Error:  "symbol_id or query (symbol name) is required for usages action",
+		}, nil
+	}
+
+	codeIntelApp := app.NewCodeIntelApp(app.NewContext(repo))
+	result, err := codeIntelApp.GetUsages(ctx, app.GetUsagesOptions{
+		SymbolID:   params.SymbolID,
+		SymbolName: symbolName,
+	})
+	if err != nil {
+		return &CodeToolResult{
+			Action: "usages",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	if result.NotFound {
+		return &CodeToolResult{
+			Action:    "usages",
+			Content:   FormatUsages(result, params.Verbose),
+			Error:     result.Message,
+			ErrorCode: ErrorCodeSymbolNotFound,
+		}, nil
+	}
+
+	return &CodeToolResult{
+		Action:  "usages",
+		Content: FormatUsages(result, params.Verbose),
+	}, nil
+}
+
+// handleCodeImplementations implements the 'implementations' action - types
+// implementing an interface, directly or through embedding.
+func handleCodeImplementations(ctx context.Context, repo *memory.Repository, params CodeToolParams) (*CodeToolResult, error) {
+	symbolName := strings.TrimSpace(params.Query)
+	if params.SymbolID == 0 && symbolName == "" {
+		return &CodeToolResult{
+			Action: "implementations",
+			Error:  "symbol_id or query (interface name) is required for implementations action",
+		}, nil
+	}
+
+	codeIntelApp := app.NewCodeIntelApp(app.NewContext(repo))
+	result, err := codeIntelApp.GetImplementations(ctx, app.GetImplementationsOptions{
+		SymbolID:   params.SymbolID,
+		SymbolName: symbolName,
+		MaxDepth:   params.MaxDepth,
+	})
+	if err != nil {
+		return &CodeToolResult{
+			Action: "implementations",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	if result.NotFound {
+		return &CodeToolResult{
+			Action:    "implementations",
+			Content:   FormatImplementations(result, params.Verbose),
+			Error:     result.Message,
+			ErrorCode: ErrorCodeSymbolNotFound,
+		}, nil
+	}
+
+	return &CodeToolResult{
+		Action:  "implementations",
+		Content: FormatImplementations(result, params.Verbose),
 	}, nil
 }
 
@@ -240,9 +563,10 @@ func handleCodeImpact(ctx context.Context, repo *memory.Repository, params CodeT
 	codeIntelApp := app.NewCodeIntelApp(appCtx)
 
 	result, err := codeIntelApp.AnalyzeImpact(ctx, app.AnalyzeImpactOptions{
-		SymbolID:   params.SymbolID,
-		SymbolName: symbolName,
-		MaxDepth:   maxDepth,
+		SymbolID:        params.SymbolID,
+		SymbolName:      symbolName,
+		MaxDepth:        maxDepth,
+		ExcludeVendored: params.ExcludeVendored,
 	})
 	if err != nil {
 		return &CodeToolResult{
@@ -251,9 +575,49 @@ func handleCodeImpact(ctx context.Context, repo *memory.Repository, params CodeT
 		}, nil
 	}
 
+	if result.NotFound {
+		return &CodeToolResult{
+			Action:    "impact",
+			Content:   FormatImpact(result, params.Verbose),
+			Error:     result.Message,
+			ErrorCode: ErrorCodeSymbolNotFound,
+		}, nil
+	}
+
 	return &CodeToolResult{
 		Action:  "impact",
-		Content: FormatImpact(result),
+		Content: FormatImpact(result, params.Verbose),
+	}, nil
+}
+
+// handleCodeChanged implements the 'changed' action - symbols changed between two git refs.
+func handleCodeChanged(ctx context.Context, repo *memory.Repository, params CodeToolParams) (*CodeToolResult, error) {
+	basePath, err := config.GetProjectRoot()
+	if err != nil {
+		return &CodeToolResult{
+			Action: "changed",
+			Error:  fmt.Sprintf("failed to resolve project root: %v", err),
+		}, nil
+	}
+
+	appCtx := app.NewContext(repo)
+	appCtx.BasePath = basePath
+	codeIntelApp := app.NewCodeIntelApp(appCtx)
+
+	result, err := codeIntelApp.SymbolsChanged(ctx, app.ChangedSymbolsOptions{
+		Base: strings.TrimSpace(params.Base),
+		Head: strings.TrimSpace(params.Head),
+	})
+	if err != nil {
+		return &CodeToolResult{
+			Action: "changed",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &CodeToolResult{
+		Action:  "changed",
+		Content: FormatChangedSymbols(result),
 	}, nil
 }
 
@@ -345,10 +709,22 @@ func handleCodeSimplify(ctx context.Context, repo *memory.Repository, params Cod
 		}, nil
 	}
 
-	// Format the output
+	// Report the callers to re-test, so behavior preservation can be verified
+	var scope *app.SimplifyScope
+	if filePath != "" || params.Query != "" || params.SymbolID > 0 {
+		scope, err = app.NewCodeIntelApp(appCtx).SimplifyScope(ctx, app.SimplifyScopeOptions{
+			FilePath: filePath,
+			Query:    params.Query,
+			SymbolID: params.SymbolID,
+		})
+		if err != nil {
+			slog.Debug("simplify scope lookup failed", "error", err)
+		}
+	}
+
 	return &CodeToolResult{
 		Action:  "simplify",
-		Content: FormatSimplifyResult(output.Findings),
+		Content: FormatSimplifyResult(output.Findings, scope),
 	}, nil
 }
 
@@ -412,8 +788,9 @@ func formatAskContext(result *app.AskResult) string {
 
 // DebugToolResult represents the response from the debug tool.
 type DebugToolResult struct {
-	Content string `json:"content"`
-	Error   string `json:"error,omitempty"`
+	Content   string    `json:"content"`
+	Error     string    `json:"error,omitempty"`
+	ErrorCode ErrorCode `json:"error_code,omitempty"` // Machine-readable code when Error is set
 }
 
 // HandleDebugTool helps diagnose issues using the DebugAgent.
@@ -445,6 +822,8 @@ func HandleDebugTool(ctx context.Context, repo *memory.Repository, params DebugT
 		kgContext = formatAskContext(result)
 	}
 
+	relatedNotes := recallRelatedNotes(ctx, askApp, params)
+
 	// Create and run the DebugAgent
 	llmCfg, err := config.LoadLLMConfigForRole(llm.RoleQuery)
 	if err != nil {
@@ -457,10 +836,11 @@ func HandleDebugTool(ctx context.Context, repo *memory.Repository, params DebugT
 
 	input := agentcore.Input{
 		ExistingContext: map[string]any{
-			"problem":     problem,
-			"error":       params.Error,
-			"stack_trace": params.StackTrace,
-			"context":     kgContext,
+			"problem":       problem,
+			"error":         params.Error,
+			"stack_trace":   params.StackTrace,
+			"context":       kgContext,
+			"related_notes": formatRelatedNotes(relatedNotes),
 		},
 	}
 
@@ -479,7 +859,151 @@ func HandleDebugTool(ctx context.Context, repo *memory.Repository, params DebugT
 
 	// Format the output
 	return &DebugToolResult{
-		Content: FormatDebugResult(output.Findings),
+		Content: FormatDebugResult(output.Findings, relatedNotes),
+	}, nil
+}
+
+// debugRelatedNotesLimit caps the knowledge notes recalled for a debug session.
+const debugRelatedNotesLimit = 3
+
+// recallRelatedNotes finds knowledge nodes about similar issues, searching the
+// error text when given and the problem description otherwise. Best-effort:
+// returns nil when recall fails.
+func recallRelatedNotes(ctx context.Context, askApp *app.AskApp, params DebugToolParams) []knowledge.NodeResponse {
+	query := strings.TrimSpace(params.Error)
+	if query == "" {
+		query = strings.TrimSpace(params.Problem)
+	}
+
+	opts := app.DefaultAskOptions()
+	opts.Limit = debugRelatedNotesLimit
+	opts.IncludeSymbols = false
+	opts.NoRewrite = true // Error text is matched as written; rewriting would paraphrase it away
+	result, err := askApp.Query(ctx, query, opts)
+	if err != nil || result == nil {
+		slog.Debug("debug related notes recall failed", "error", err)
+		return nil
+	}
+	return result.Results
+}
+
+// formatRelatedNotes renders recalled notes for the DebugAgent prompt.
+func formatRelatedNotes(notes []knowledge.NodeResponse) string {
+	var sb strings.Builder
+	for _, n := range notes {
+		sb.WriteString(fmt.Sprintf("- [%s] %s\n", n.Type, n.Summary))
+		if n.Content != "" && n.Content != n.Summary {
+			sb.WriteString(fmt.Sprintf("  %s\n", n.Content))
+		}
+	}
+	return sb.String()
+}
+
+// === Policy Tool Handler ===
+
+// PolicyToolResult represents the response from the policy tool.
+type PolicyToolResult struct {
+	Action    string    `json:"action"`
+	Content   string    `json:"content"`
+	Error     string    `json:"error,omitempty"`
+	ErrorCode ErrorCode `json:"error_code,omitempty"` // Machine-readable code when Error is set
+}
+
+// HandlePolicyTool is the handler for policy inspection operations.
+func HandlePolicyTool(ctx context.Context, params PolicyToolParams) (*PolicyToolResult, error) {
+	if !params.Action.IsValid() {
+		return &PolicyToolResult{
+			Action:    string(params.Action),
+			Error:     fmt.Sprintf("invalid action %q, must be one of: list, why", params.Action),
+			ErrorCode: ErrorCodeInvalidInput,
+		}, nil
+	}
+
+	switch params.Action {
+	case PolicyActionList:
+		return handlePolicyList(ctx)
+	case PolicyActionWhy:
+		return handlePolicyWhy(ctx, params)
+	default:
+		return &PolicyToolResult{
+			Action: string(params.Action),
+			Error:  fmt.Sprintf("unsupported action: %s", params.Action),
+		}, nil
+	}
+}
+
+// handlePolicyList implements the 'list' action - show loaded policies and where they came from.
+func handlePolicyList(ctx context.Context) (*PolicyToolResult, error) {
+	projectRoot, err := config.GetProjectRoot()
+	if err != nil {
+		return &PolicyToolResult{
+			Action: "list",
+			Error:  fmt.Sprintf("failed to resolve project root: %v", err),
+		}, nil
+	}
+
+	engine, err := policy.NewEngine(ctx, policy.EngineConfig{WorkDir: projectRoot})
+	if err != nil {
+		return &PolicyToolResult{
+			Action: "list",
+			Error:  fmt.Sprintf("failed to load policies: %v", err),
+		}, nil
+	}
+
+	return &PolicyToolResult{
+		Action:  "list",
+		Content: FormatPolicyList(engine.GetPolicies()),
+	}, nil
+}
+
+// handlePolicyWhy implements the 'why' action - explain which rules deny a file.
+func handlePolicyWhy(ctx context.Context, params PolicyToolParams) (*PolicyToolResult, error) {
+	file := strings.TrimSpace(params.File)
+	if file == "" {
+		return &PolicyToolResult{
+			Action: "why",
+			Error:  "file is required for why action",
+		}, nil
+	}
+
+	projectRoot, err := config.GetProjectRoot()
+	if err != nil {
+		return &PolicyToolResult{
+			Action: "why",
+			Error:  fmt.Sprintf("failed to resolve project root: %v", err),
+		}, nil
+	}
+	// Policies match project-relative paths; the file may not exist yet
+	if filepath.IsAbs(file) {
+		rel, err := filepath.Rel(projectRoot, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return &PolicyToolResult{
+				Action: "why",
+				Error:  fmt.Sprintf("path not allowed: %s is outside the project", file),
+			}, nil
+		}
+		file = rel
+	}
+
+	engine, err := policy.NewEngine(ctx, policy.EngineConfig{WorkDir: projectRoot})
+	if err != nil {
+		return &PolicyToolResult{
+			Action: "why",
+			Error:  fmt.Sprintf("failed to load policies: %v", err),
+		}, nil
+	}
+
+	explanations, err := engine.ExplainFile(ctx, file)
+	if err != nil {
+		return &PolicyToolResult{
+			Action: "why",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &PolicyToolResult{
+		Action:  "why",
+		Content: FormatPolicyWhy(policy.NormalizePath(file), engine.PolicyCount(), explanations),
 	}, nil
 }
 
@@ -487,9 +1011,11 @@ func HandleDebugTool(ctx context.Context, repo *memory.Repository, params DebugT
 
 // TaskToolResult represents the response from the unified task tool.
 type TaskToolResult struct {
-	Action  string `json:"action"`
-	Content string `json:"content"`
-	Error   string `json:"error,omitempty"`
+	Action    string      `json:"action"`
+	Content   string      `json:"content"`
+	Error     string      `json:"error,omitempty"`
+	ErrorCode ErrorCode   `json:"error_code,omitempty"` // Machine-readable code when Error is set
+	Tasks     []task.Task `json:"tasks,omitempty"`      // Structured result of the list action
 }
 
 // HandleTaskTool is the unified handler for all task lifecycle operations.
@@ -499,8 +1025,9 @@ func HandleTaskTool(ctx context.Context, repo *memory.Repository, params TaskToo
 	// Validate action
 	if !params.Action.IsValid() {
 		return &TaskToolResult{
-			Action: string(params.Action),
-			Error:  fmt.Sprintf("invalid action %q, must be one of: next, current, start, complete, skip", params.Action),
+			Action:    string(params.Action),
+			Error:     fmt.Sprintf("invalid action %q, must be one of: next, current, start, complete, skip, reorder, list, dependencies, split", params.Action),
+			ErrorCode: ErrorCodeInvalidInput,
 		}, nil
 	}
 
@@ -515,6 +1042,14 @@ func HandleTaskTool(ctx context.Context, repo *memory.Repository, params TaskToo
 		return handleTaskComplete(ctx, repo, params)
 	case TaskActionSkip:
 		return handleTaskSkip(ctx, repo, params)
+	case TaskActionReorder:
+		return handleTaskReorder(ctx, repo, params)
+	case TaskActionList:
+		return handleTaskList(ctx, repo, params)
+	case TaskActionDeps:
+		return handleTaskDependencies(ctx, repo, params)
+	case TaskActionSplit:
+		return handleTaskSplit(ctx, repo, params)
 	default:
 		return &TaskToolResult{
 			Action: string(params.Action),
@@ -567,6 +1102,7 @@ func handleTaskNext(ctx context.Context, repo *memory.Repository, params TaskToo
 		AutoStart:         params.AutoStart,
 		CreateBranch:      createBranch,
 		SkipUnpushedCheck: params.SkipUnpushedCheck,
+		Agent:             params.Agent,
 	})
 	if err != nil {
 		return &TaskToolResult{
@@ -575,6 +1111,15 @@ func handleTaskNext(ctx context.Context, repo *memory.Repository, params TaskToo
 		}, nil
 	}
 
+	if !result.Success && result.Code != "" {
+		return &TaskToolResult{
+			Action:    "next",
+			Content:   FormatTask(result),
+			Error:     result.Message,
+			ErrorCode: planErrorCode(result.Code),
+		}, nil
+	}
+
 	return &TaskToolResult{
 		Action:  "next",
 		Content: FormatTask(result),
@@ -608,9 +1153,18 @@ func handleTaskCurrent(ctx context.Context, repo *memory.Repository, params Task
 		}, nil
 	}
 
+	if !result.Success && result.Code != "" {
+		return &TaskToolResult{
+			Action:    "current",
+			Content:   FormatCurrentTask(result),
+			Error:     result.Message,
+			ErrorCode: planErrorCode(result.Code),
+		}, nil
+	}
+
 	return &TaskToolResult{
 		Action:  "current",
-		Content: FormatTask(result),
+		Content: FormatCurrentTask(result),
 	}, nil
 }
 
@@ -669,9 +1223,10 @@ func handleTaskComplete(ctx context.Context, repo *memory.Repository, params Tas
 	taskApp := app.NewTaskApp(appCtx)
 
 	result, err := taskApp.Complete(ctx, app.TaskCompleteOptions{
-		TaskID:        taskID,
-		Summary:       params.Summary,
-		FilesModified: params.FilesModified,
+		TaskID:         taskID,
+		Summary:        params.Summary,
+		FilesModified:  params.FilesModified,
+		VerifyCriteria: params.VerifyCriteria,
 	})
 	if err != nil {
 		return &TaskToolResult{
@@ -682,9 +1237,15 @@ func handleTaskComplete(ctx context.Context, repo *memory.Repository, params Tas
 
 	// Check for policy violations (task not completed successfully)
 	if !result.Success {
+		code := ErrorCodeToolFailed
+		if result.PolicyViolation {
+			code = ErrorCodePolicyBlocked
+		}
 		return &TaskToolResult{
-			Action:  "complete",
-			Content: FormatTaskCompletionBlocked(result),
+			Action:    "complete",
+			Content:   FormatTaskCompletionBlocked(result),
+			Error:     result.Message,
+			ErrorCode: code,
 		}, nil
 	}
 
@@ -731,24 +1292,222 @@ func handleTaskSkip(_ context.Context, repo *memory.Repository, params TaskToolP
 	}, nil
 }
 
+// handleTaskList implements the 'list' action - filtered, sorted task listing.
+func handleTaskList(ctx context.Context, repo *memory.Repository, params TaskToolParams) (*TaskToolResult, error) {
+	status := task.TaskStatus(strings.TrimSpace(params.Status))
+	if status != "" && !isKnownTaskStatus(status) {
+		return &TaskToolResult{
+			Action: "list",
+			Error:  fmt.Sprintf("invalid status %q", params.Status),
+		}, nil
+	}
+	format := strings.ToLower(strings.TrimSpace(params.Format))
+	if format != "" && format != "markdown" && format != "json" {
+		return &TaskToolResult{
+			Action: "list",
+			Error:  fmt.Sprintf("invalid format %q: must be markdown or json", params.Format),
+		}, nil
+	}
+
+	taskApp := app.NewTaskApp(app.NewContext(repo))
+	tasks, err := taskApp.ListTasks(ctx, app.TaskListOptions{
+		PlanID: strings.TrimSpace(params.PlanID),
+		Status: status,
+		Phase:  strings.TrimSpace(params.Phase),
+		Sort:   strings.ToLower(strings.TrimSpace(params.Sort)),
+		Limit:  params.Limit,
+	})
+	if err != nil {
+		return &TaskToolResult{
+			Action: "list",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(tasks, "", "  ")
+		if err != nil {
+			return &TaskToolResult{
+				Action: "list",
+				Error:  fmt.Sprintf("encode tasks: %v", err),
+			}, nil
+		}
+		return &TaskToolResult{
+			Action:  "list",
+			Content: string(data),
+			Tasks:   tasks,
+		}, nil
+	}
+
+	return &TaskToolResult{
+		Action:  "list",
+		Content: FormatTaskList(tasks),
+		Tasks:   tasks,
+	}, nil
+}
+
+// handleTaskDependencies implements the 'dependencies' action - a task's upstream and downstream tasks.
+func handleTaskDependencies(_ context.Context, repo *memory.Repository, params TaskToolParams) (*TaskToolResult, error) {
+	taskID := strings.TrimSpace(params.TaskID)
+	if taskID == "" {
+		return &TaskToolResult{
+			Action: "dependencies",
+			Error:  "task_id is required for dependencies action",
+		}, nil
+	}
+
+	taskApp := app.NewTaskApp(app.NewContext(repo))
+	upstream, downstream, err := taskApp.TaskDependencies(taskID)
+	if err != nil {
+		return &TaskToolResult{
+			Action: "dependencies",
+			Error:  err.Error(),
+		}, nil
+	}
+	t, err := repo.GetTask(taskID)
+	if err != nil {
+		return &TaskToolResult{
+			Action: "dependencies",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &TaskToolResult{
+		Action:  "dependencies",
+		Content: FormatTaskDependencies(t, upstream, downstream),
+	}, nil
+}
+
+// handleTaskSplit implements the 'split' action - break a task into subtasks
+// the original then depends on.
+func handleTaskSplit(_ context.Context, repo *memory.Repository, params TaskToolParams) (*TaskToolResult, error) {
+	taskID := strings.TrimSpace(params.TaskID)
+	if taskID == "" {
+		return &TaskToolResult{
+			Action:    "split",
+			Error:     "task_id is required for split action",
+			ErrorCode: ErrorCodeInvalidInput,
+		}, nil
+	}
+
+	taskApp := app.NewTaskApp(app.NewContext(repo))
+	subtasks, err := taskApp.Split(taskID, params.Subtasks)
+	if err != nil {
+		return &TaskToolResult{
+			Action: "split",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Task Split\n\n`%s` now depends on %d subtasks:\n\n", taskID, len(subtasks)))
+	for i, t := range subtasks {
+		sb.WriteString(fmt.Sprintf("%d. **%s** (`%s`)\n", i+1, t.Title, t.ID))
+	}
+	sb.WriteString("\nUse `task action=next` to pick up the first subtask.")
+	return &TaskToolResult{
+		Action:  "split",
+		Content: sb.String(),
+		Tasks:   subtasks,
+	}, nil
+}
+
+// isKnownTaskStatus reports whether status is a defined task status.
+func isKnownTaskStatus(status task.TaskStatus) bool {
+	switch status {
+	case task.StatusPending, task.StatusInProgress, task.StatusVerifying, task.StatusCompleted,
+		task.StatusFailed, task.StatusSkipped, task.StatusBlocked, task.StatusReady:
+		return true
+	}
+	return false
+}
+
+// handleTaskReorder implements the 'reorder' action - set the priority order of a plan's tasks.
+func handleTaskReorder(_ context.Context, repo *memory.Repository, params TaskToolParams) (*TaskToolResult, error) {
+	if len(params.TaskIDs) == 0 {
+		return &TaskToolResult{
+			Action: "reorder",
+			Error:  "task_ids is required for reorder action",
+		}, nil
+	}
+
+	planID := strings.TrimSpace(params.PlanID)
+	if planID == "" {
+		activePlan, err := repo.GetActivePlan()
+		if err != nil || activePlan == nil {
+			return &TaskToolResult{
+				Action: "reorder",
+				Error:  "no active plan found; pass plan_id",
+			}, nil
+		}
+		planID = activePlan.ID
+	}
+
+	taskIDs := make([]string, len(params.TaskIDs))
+	for i, id := range params.TaskIDs {
+		taskIDs[i] = strings.TrimSpace(id)
+	}
+
+	taskApp := app.NewTaskApp(app.NewContext(repo))
+	if err := taskApp.Reorder(planID, taskIDs); err != nil {
+		return &TaskToolResult{
+			Action: "reorder",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Tasks Reordered\n\n")
+	if tasks, err := repo.ListTasks(planID); err == nil {
+		sort.SliceStable(tasks, func(i, j int) bool {
+			if tasks[i].Priority != tasks[j].Priority {
+				return tasks[i].Priority < tasks[j].Priority
+			}
+			return tasks[i].SortOrder < tasks[j].SortOrder
+		})
+		for i, t := range tasks {
+			sb.WriteString(fmt.Sprintf("%d. **%s** (`%s`, priority %d)\n", i+1, t.Title, t.ID, t.Priority))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Use `task action=next` to get the most urgent ready task.")
+	return &TaskToolResult{
+		Action:  "reorder",
+		Content: sb.String(),
+	}, nil
+}
+
 // === Plan Tool Handler ===
 
 // PlanToolResult represents the response from the unified plan tool.
 type PlanToolResult struct {
-	Action  string `json:"action"`
-	Content string `json:"content"`
-	Error   string `json:"error,omitempty"`
+	Action    string    `json:"action"`
+	Content   string    `json:"content"`
+	Error     string    `json:"error,omitempty"`
+	ErrorCode ErrorCode `json:"error_code,omitempty"` // Machine-readable code when Error is set
+}
+
+// planToolResult wraps a plan app result's Markdown. Failures that carry a plan
+// error code also set Error and ErrorCode.
+func planToolResult(action, content string, success bool, message string, code app.PlanErrorCode) *PlanToolResult {
+	result := &PlanToolResult{Action: action, Content: content}
+	if !success && code != "" {
+		result.Error = message
+		result.ErrorCode = planErrorCode(code)
+	}
+	return result
 }
 
 // HandlePlanTool is the unified handler for all plan operations.
 // It routes to the appropriate service logic based on the action parameter.
-// Supports planning actions: clarify, decompose, expand, generate, finalize, audit
+// Supports planning actions: clarify, decompose, expand, generate, finalize, audit, merge, list
 func HandlePlanTool(ctx context.Context, repo *memory.Repository, params PlanToolParams) (*PlanToolResult, error) {
 	// Validate action
 	if !params.Action.IsValid() {
 		return &PlanToolResult{
-			Action: string(params.Action),
-			Error:  fmt.Sprintf("invalid action %q, must be one of: clarify, decompose, expand, generate, finalize, audit", params.Action),
+			Action:    string(params.Action),
+			Error:     fmt.Sprintf("invalid action %q, must be one of: clarify, decompose, expand, generate, finalize, audit, merge, list", params.Action),
+			ErrorCode: ErrorCodeInvalidInput,
 		}, nil
 	}
 
@@ -765,6 +1524,10 @@ func HandlePlanTool(ctx context.Context, repo *memory.Repository, params PlanToo
 		return handlePlanFinalize(ctx, repo, params)
 	case PlanActionAudit:
 		return handlePlanAudit(ctx, repo, params)
+	case PlanActionMerge:
+		return handlePlanMerge(ctx, repo, params)
+	case PlanActionList:
+		return handlePlanList(ctx, repo, params)
 	default:
 		return &PlanToolResult{
 			Action: string(params.Action),
@@ -833,10 +1596,7 @@ func handlePlanClarify(ctx context.Context, repo *memory.Repository, params Plan
 		}, nil
 	}
 
-	return &PlanToolResult{
-		Action:  "clarify",
-		Content: FormatClarifyResult(result),
-	}, nil
+	return planToolResult("clarify", FormatClarifyResult(result), result.Success, result.Message, result.Code), nil
 }
 
 // handlePlanGenerate implements the 'generate' action - create a plan with tasks.
@@ -882,11 +1642,17 @@ func handlePlanGenerate(ctx context.Context, repo *memory.Repository, params Pla
 	planApp := app.NewPlanApp(appCtx)
 
 	result, err := planApp.Generate(ctx, app.GenerateOptions{
-		Goal:             goal,
-		ClarifySessionID: clarifySessionID,
-		EnrichedGoal:     enrichedGoal,
-		Save:             save,
-		ExplicitTasks:    params.Tasks,
+		Goal:                goal,
+		ClarifySessionID:    clarifySessionID,
+		EnrichedGoal:        enrichedGoal,
+		Save:                save,
+		DryRun:              params.DryRun,
+		ExplicitTasks:       params.Tasks,
+		Stream:              params.Stream,
+		NormalizePriorities: params.NormalizePriorities,
+		TestFirst:           params.TestFirst,
+		MergeDuplicates:     params.MergeDuplicates,
+		PhaseID:             strings.TrimSpace(params.PhaseID),
 	})
 	if err != nil {
 		return &PlanToolResult{
@@ -895,10 +1661,7 @@ func handlePlanGenerate(ctx context.Context, repo *memory.Repository, params Pla
 		}, nil
 	}
 
-	return &PlanToolResult{
-		Action:  "generate",
-		Content: FormatGenerateResult(result),
-	}, nil
+	return planToolResult("generate", FormatGenerateResult(result), result.Success, result.Message, result.Code), nil
 }
 
 // handlePlanDecompose implements the 'decompose' action - break goal into phases.
@@ -935,10 +1698,7 @@ func handlePlanDecompose(ctx context.Context, repo *memory.Repository, params Pl
 		}, nil
 	}
 
-	return &PlanToolResult{
-		Action:  "decompose",
-		Content: FormatDecomposeResult(result),
-	}, nil
+	return planToolResult("decompose", FormatDecomposeResult(result), result.Success, result.Message, result.Code), nil
 }
 
 // handlePlanExpand implements the 'expand' action - generate tasks for a phase.
@@ -995,10 +1755,7 @@ func handlePlanExpand(ctx context.Context, repo *memory.Repository, params PlanT
 		}, nil
 	}
 
-	return &PlanToolResult{
-		Action:  "expand",
-		Content: FormatExpandResult(result),
-	}, nil
+	return planToolResult("expand", FormatExpandResult(result), result.Success, result.Message, result.Code), nil
 }
 
 // handlePlanFinalize implements the 'finalize' action - save completed interactive plan.
@@ -1032,12 +1789,91 @@ func handlePlanFinalize(ctx context.Context, repo *memory.Repository, params Pla
 		}, nil
 	}
 
+	return planToolResult("finalize", FormatFinalizeResult(result), result.Success, result.Message, result.Code), nil
+}
+
+// handlePlanMerge implements the 'merge' action - combine two plans into one.
+func handlePlanMerge(ctx context.Context, repo *memory.Repository, params PlanToolParams) (*PlanToolResult, error) {
+	targetID := strings.TrimSpace(params.PlanID)
+	sourceID := strings.TrimSpace(params.SourcePlanID)
+
+	var missingFields []string
+	if targetID == "" {
+		missingFields = append(missingFields, "plan_id")
+	}
+	if sourceID == "" {
+		missingFields = append(missingFields, "source_plan_id")
+	}
+	if len(missingFields) > 0 {
+		return &PlanToolResult{
+			Action:  "merge",
+			Error:   fmt.Sprintf("missing required fields: %v", missingFields),
+			Content: FormatMultiValidationError("merge", missingFields, "Provide plan_id (the plan to keep) and source_plan_id (the plan to fold into it)."),
+		}, nil
+	}
+
+	planApp := app.NewPlanApp(app.NewContext(repo))
+	plan, err := planApp.Merge(ctx, targetID, sourceID)
+	if err != nil {
+		return &PlanToolResult{
+			Action: "merge",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &PlanToolResult{
+		Action:  "merge",
+		Content: FormatMergeResult(plan, sourceID),
+	}, nil
+}
+
+// handlePlanList implements the 'list' action - page through plans by status.
+func handlePlanList(ctx context.Context, repo *memory.Repository, params PlanToolParams) (*PlanToolResult, error) {
+	status := task.PlanStatus(strings.TrimSpace(params.Status))
+	if status != "" && !isKnownPlanStatus(status) {
+		return &PlanToolResult{
+			Action: "list",
+			Error:  fmt.Sprintf("invalid status %q", params.Status),
+		}, nil
+	}
+	if params.Limit < 0 || params.Offset < 0 {
+		return &PlanToolResult{
+			Action: "list",
+			Error:  "limit and offset must not be negative",
+		}, nil
+	}
+
+	planApp := app.NewPlanApp(app.NewContext(repo))
+	plans, err := planApp.List(ctx, task.PlanFilter{
+		Status:          status,
+		IncludeArchived: params.IncludeArchived,
+		OrderBy:         strings.ToLower(strings.TrimSpace(params.Sort)),
+		Limit:           params.Limit,
+		Offset:          params.Offset,
+	})
+	if err != nil {
+		return &PlanToolResult{
+			Action: "list",
+			Error:  err.Error(),
+		}, nil
+	}
+
 	return &PlanToolResult{
-		Action:  "finalize",
-		Content: FormatFinalizeResult(result),
+		Action:  "list",
+		Content: FormatPlanList(plans, params.Offset),
 	}, nil
 }
 
+// isKnownPlanStatus reports whether status is a valid plan status.
+func isKnownPlanStatus(status task.PlanStatus) bool {
+	switch status {
+	case task.PlanStatusDraft, task.PlanStatusActive, task.PlanStatusCompleted,
+		task.PlanStatusVerified, task.PlanStatusNeedsRevision, task.PlanStatusArchived:
+		return true
+	}
+	return false
+}
+
 // handlePlanAudit implements the 'audit' action - verify and fix a completed plan.
 func handlePlanAudit(ctx context.Context, repo *memory.Repository, params PlanToolParams) (*PlanToolResult, error) {
 	// Default autoFix to true
@@ -1053,6 +1889,7 @@ func handlePlanAudit(ctx context.Context, repo *memory.Repository, params PlanTo
 	result, err := planApp.Audit(ctx, app.AuditOptions{
 		PlanID:  params.PlanID,
 		AutoFix: autoFix,
+		Force:   params.Force,
 	})
 	if err != nil {
 		return &PlanToolResult{
@@ -1061,8 +1898,59 @@ func handlePlanAudit(ctx context.Context, repo *memory.Repository, params PlanTo
 		}, nil
 	}
 
-	return &PlanToolResult{
-		Action:  "audit",
-		Content: FormatAuditResult(result),
-	}, nil
+	if params.JUnit && result.Report != nil {
+		return planToolResult("audit", string(result.Report.ToJUnit()), result.Success, result.Message, result.Code), nil
+	}
+	return planToolResult("audit", FormatAuditResult(result), result.Success, result.Message, result.Code), nil
+}
+
+// === Info Tool Handler ===
+
+// ServerInfo describes the running server to the info tool.
+type ServerInfo struct {
+	Version  string
+	Tools    []string // Enabled tool names
+	ReadOnly bool
+}
+
+// InfoToolResult represents the response from the info tool.
+type InfoToolResult struct {
+	Version        string   `json:"version"`
+	Tools          []string `json:"tools"`
+	ReadOnly       bool     `json:"read_only"`
+	CodeIndex      bool     `json:"code_index"` // A code index with at least one symbol exists
+	IndexedSymbols int      `json:"indexed_symbols"`
+	ActivePlan     bool     `json:"active_plan"`
+	ActivePlanID   string   `json:"active_plan_id,omitempty"`
+	Content        string   `json:"-"`
+}
+
+// HandleInfoTool reports the server version, enabled tools, and project state.
+// It makes no LLM calls, so orchestrators can also use it as a liveness check.
+func HandleInfoTool(ctx context.Context, repo *memory.Repository, info ServerInfo) (*InfoToolResult, error) {
+	result := &InfoToolResult{
+		Version:  info.Version,
+		Tools:    info.Tools,
+		ReadOnly: info.ReadOnly,
+	}
+
+	stats, err := app.NewCodeIntelApp(app.NewContext(repo)).GetStats(ctx)
+	if err != nil {
+		slog.Debug("info tool code index stats failed", "error", err)
+	} else if stats.Success {
+		result.IndexedSymbols = stats.SymbolsFound
+		result.CodeIndex = stats.SymbolsFound > 0
+	}
+
+	plan, err := repo.GetActivePlan()
+	if err != nil {
+		return nil, fmt.Errorf("get active plan: %w", err)
+	}
+	if plan != nil {
+		result.ActivePlan = true
+		result.ActivePlanID = plan.ID
+	}
+
+	result.Content = FormatInfo(result)
+	return result, nil
 }
@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/app"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+func TestHandleTaskTool_List(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	plan := &task.Plan{Goal: "Add auth", Status: task.PlanStatusActive, Tasks: []task.Task{
+		{Title: "Write docs", Description: "a", Priority: 50, Status: task.StatusPending},
+		{Title: "Add schema", Description: "b", Priority: 10, Status: task.StatusCompleted},
+		{Title: "Add login", Description: "c", Priority: 20, Status: task.StatusPending},
+		{Title: "Add logout", Description: "d", Priority: 30, Status: task.StatusPending},
+	}}
+	if err := repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+
+	result, err := HandleTaskTool(ctx, repo, TaskToolParams{Action: TaskActionList, Status: "pending", Format: "json"}, "")
+	if err != nil {
+		t.Fatalf("HandleTaskTool: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("list failed: %s", result.Error)
+	}
+	var titles []string
+	for _, lt := range result.Tasks {
+		titles = append(titles, lt.Title)
+	}
+	if strings.Join(titles, ",") != "Add login,Add logout,Write docs" {
+		t.Errorf("pending tasks by priority = %v", titles)
+	}
+	var decoded []task.Task
+	if err := json.Unmarshal([]byte(result.Content), &decoded); err != nil || len(decoded) != 3 {
+		t.Errorf("json content should decode to 3 tasks: %v", err)
+	}
+
+	result, err = HandleTaskTool(ctx, repo, TaskToolParams{Action: TaskActionList, Limit: 2}, "")
+	if err != nil {
+		t.Fatalf("HandleTaskTool: %v", err)
+	}
+	if !strings.Contains(result.Content, "| # | Priority | Status | Title | ID |") {
+		t.Errorf("expected markdown table, got:\n%s", result.Content)
+	}
+	if len(result.Tasks) != 2 || result.Tasks[0].Title != "Add schema" || result.Tasks[1].Title != "Add login" {
+		t.Errorf("limit/sort across statuses wrong: %v", result.Tasks)
+	}
+
+	result, _ = HandleTaskTool(ctx, repo, TaskToolParams{Action: TaskActionList, Status: "bogus"}, "")
+	if result.Error == "" {
+		t.Error("expected error for unknown status")
+	}
+}
+
+func TestDebugSurfacesRelatedNotes(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	nodes := []*memory.Node{{
+		ID:      "n-db-locked",
+		Type:    memory.NodeTypePattern,
+		Summary: "SQLite database is locked under concurrent writers",
+		Content: "Writers must share one connection; a second writer gets SQLITE_BUSY (database is locked).",
+	}}
+	// Unrelated nodes give BM25 a corpus to score against
+	for i, topic := range []string{"Logging uses slog", "Config loaded from YAML", "CLI built on cobra", "Plans are stored as DAGs", "Hooks run on session start"} {
+		nodes = append(nodes, &memory.Node{ID: fmt.Sprintf("n-filler-%d", i), Type: memory.NodeTypePattern, Summary: topic, Content: topic})
+	}
+	for _, n := range nodes {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+
+	notes := recallRelatedNotes(ctx, app.NewAskApp(&app.Context{Repo: repo}), DebugToolParams{
+		Problem: "bootstrap fails halfway",
+		Error:   "database is locked",
+	})
+	if len(notes) == 0 || notes[0].ID != "n-db-locked" {
+		t.Fatalf("related notes = %+v, want n-db-locked first", notes)
+	}
+
+	out := FormatDebugResult(nil, notes)
+	if !strings.Contains(out, "### Related Notes") || !strings.Contains(out, "SQLite database is locked under concurrent writers") {
+		t.Errorf("debug output should list the related note:\n%s", out)
+	}
+}
+
+func TestServerOptions_ReadOnly(t *testing.T) {
+	readOnly := ServerOptions{ReadOnly: true}
+
+	if err := readOnly.Check("ask", false); err != nil {
+		t.Errorf("ask in read-only mode: %v", err)
+	}
+	err := readOnly.Check("remember", true)
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("remember in read-only mode = %v, want ErrReadOnly", err)
+	}
+	if !strings.Contains(err.Error(), "remember") || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("error %q should name the tool and read-only mode", err)
+	}
+	if err := (ServerOptions{}).Check("remember", true); err != nil {
+		t.Errorf("remember with writes allowed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		mutates bool
+		want    bool
+	}{
+		{"task list", TaskToolParams{Action: TaskActionList}.Mutates(), false},
+		{"task next", TaskToolParams{Action: TaskActionNext}.Mutates(), false},
+		{"task next auto_start", TaskToolParams{Action: TaskActionNext, AutoStart: true}.Mutates(), true},
+		{"task complete", TaskToolParams{Action: TaskActionComplete}.Mutates(), true},
+		{"plan list", PlanToolParams{Action: PlanActionList}.Mutates(), false},
+		{"plan generate dry_run", PlanToolParams{Action: PlanActionGenerate, DryRun: true}.Mutates(), false},
+		{"plan generate", PlanToolParams{Action: PlanActionGenerate}.Mutates(), true},
+		{"plan audit", PlanToolParams{Action: PlanActionAudit}.Mutates(), true},
+	} {
+		if tc.mutates != tc.want {
+			t.Errorf("%s mutates = %v, want %v", tc.name, tc.mutates, tc.want)
+		}
+	}
+}
+
+func TestHandleTaskTool_NextWithoutPlanReturnsErrorCode(t *testing.T) {
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+
+	result, err := HandleTaskTool(context.Background(), repo, TaskToolParams{Action: TaskActionNext}, "session-1")
+	if err != nil {
+		t.Fatalf("HandleTaskTool: %v", err)
+	}
+	if result.ErrorCode != ErrorCodeNoActivePlan {
+		t.Errorf("ErrorCode = %q, want %q", result.ErrorCode, ErrorCodeNoActivePlan)
+	}
+	if result.Error == "" || !strings.Contains(result.Content, "No active plan") {
+		t.Errorf("want an error message and Markdown for display, got error %q content %q", result.Error, result.Content)
+	}
+
+	envelope, err := json.Marshal(NewToolError(result.ErrorCode, result.Error))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(envelope), `"code":"NO_ACTIVE_PLAN"`) {
+		t.Errorf("envelope = %s", envelope)
+	}
+	if got := ErrorCodeOf(fmt.Errorf("wrapped: %w", ErrReadOnly)); got != ErrorCodeReadOnly {
+		t.Errorf("ErrorCodeOf(ErrReadOnly) = %q", got)
+	}
+}
+
+func TestHandleInfoTool(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+	info := ServerInfo{Version: "1.2.3", Tools: []string{"ask", "info"}}
+
+	result, err := HandleInfoTool(ctx, repo, info)
+	if err != nil {
+		t.Fatalf("HandleInfoTool: %v", err)
+	}
+	if result.Version != "1.2.3" || !strings.Contains(result.Content, "1.2.3") {
+		t.Errorf("version = %q, content:\n%s", result.Version, result.Content)
+	}
+	if result.ActivePlan || result.CodeIndex {
+		t.Errorf("empty project reported active plan %v, code index %v", result.ActivePlan, result.CodeIndex)
+	}
+
+	plan := &task.Plan{Goal: "Add auth", Status: task.PlanStatusActive}
+	if err := repo.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	result, err = HandleInfoTool(ctx, repo, info)
+	if err != nil {
+		t.Fatalf("HandleInfoTool: %v", err)
+	}
+	if !result.ActivePlan || result.ActivePlanID != plan.ID {
+		t.Errorf("active plan = %v %q, want %s", result.ActivePlan, result.ActivePlanID, plan.ID)
+	}
+	if !strings.Contains(result.Content, plan.ID) {
+		t.Errorf("content should name the active plan:\n%s", result.Content)
+	}
+}
@@ -5,14 +5,18 @@ package mcp
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	agentcore "github.com/josephgoksu/TaskWing/internal/agents/core"
 	agentimpl "github.com/josephgoksu/TaskWing/internal/agents/impl"
 	"github.com/josephgoksu/TaskWing/internal/app"
 	"github.com/josephgoksu/TaskWing/internal/codeintel"
+	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/knowledge"
 	"github.com/josephgoksu/TaskWing/internal/memory"
+	"github.com/josephgoksu/TaskWing/internal/policy"
 	"github.com/josephgoksu/TaskWing/internal/task"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -39,7 +43,7 @@ func FormatKnowledgeSummary(nodes []memory.Node) string {
 		for _, n := range group {
 			title := n.Summary
 			if title == "" {
-				title = truncate(n.Content, 120)
+				title = truncate(n.Content, presenterConfig.TitleLength)
 			}
 			fmt.Fprintf(&sb, "- %s", title)
 			if n.Workspace != "" && n.Workspace != "root" {
@@ -155,7 +159,7 @@ func writeNodeGroup(sb *strings.Builder, typeName string, nodes []memory.Node) {
 	for _, n := range nodes {
 		title := n.Summary
 		if title == "" {
-			title = truncate(n.Content, 120)
+			title = truncate(n.Content, presenterConfig.TitleLength)
 		}
 		fmt.Fprintf(sb, "- **%s**", title)
 		if n.Workspace != "" && n.Workspace != "root" {
@@ -165,7 +169,7 @@ func writeNodeGroup(sb *strings.Builder, typeName string, nodes []memory.Node) {
 		if n.Content != "" && n.Content != n.Summary {
 			content := cleanContent(n.Content, title)
 			if content != "" {
-				preview := truncate(content, 200)
+				preview := truncate(content, presenterConfig.SummaryPreviewLength)
 				fmt.Fprintf(sb, "\n  %s", preview)
 			}
 		}
@@ -189,10 +193,21 @@ func FormatAsk(result *app.AskResult) string {
 		sb.WriteString("## Answer\n")
 		sb.WriteString(result.Answer)
 		sb.WriteString("\n\n")
+		if len(result.Citations) > 0 {
+			sb.WriteString("### Sources\n")
+			for _, c := range result.Citations {
+				sb.WriteString(fmt.Sprintf("- [%s] **%s** (%s)\n", c.ID, c.Summary, c.Type))
+			}
+			sb.WriteString("\n")
+		}
 	}
 
-	// Knowledge section - grouped by type for clarity
-	if len(result.Results) > 0 {
+	// Knowledge section - grouped by feature when requested, else by type
+	if len(result.FeatureGroups) > 0 {
+		sb.WriteString("## Knowledge\n")
+		writeKnowledgeByFeature(&sb, result)
+		sb.WriteString("\n")
+	} else if len(result.Results) > 0 {
 		sb.WriteString("## Knowledge\n")
 
 		// Group nodes by type for better structure
@@ -204,28 +219,8 @@ func FormatAsk(result *app.AskResult) string {
 
 		idx := 1
 		for _, t := range typeOrder {
-			nodes := grouped[t]
-			if len(nodes) == 0 {
-				continue
-			}
-			for _, node := range nodes {
-				sb.WriteString(fmt.Sprintf("%d. **%s** (%s)", idx, node.Summary, node.Type))
-				if node.FreshnessNote != "" {
-					sb.WriteString(fmt.Sprintf(" %s", node.FreshnessNote))
-				}
-				if node.Content != "" && node.Content != node.Summary {
-					content := cleanContent(node.Content, node.Summary)
-					if content != "" {
-						// Show more content (300 chars) so the consuming LLM has enough
-						// context to produce comprehensive answers
-						preview := truncate(content, 300)
-						sb.WriteString(fmt.Sprintf("\n   %s", preview))
-					}
-				}
-				if node.DebtWarning != "" {
-					sb.WriteString(fmt.Sprintf("\n   %s", node.DebtWarning))
-				}
-				sb.WriteString("\n")
+			for _, node := range grouped[t] {
+				writeAskNode(&sb, idx, node)
 				idx++
 			}
 		}
@@ -241,6 +236,15 @@ func FormatAsk(result *app.AskResult) string {
 		sb.WriteString("\n")
 	}
 
+	// Related knowledge section (one hop along graph edges)
+	if len(result.Related) > 0 {
+		sb.WriteString("## Related Knowledge\n")
+		for _, node := range result.Related {
+			sb.WriteString(fmt.Sprintf("- **%s** (%s)\n", node.Summary, node.Type))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Symbols section
 	if len(result.Symbols) > 0 {
 		sb.WriteString("## Code Symbols\n")
@@ -257,6 +261,70 @@ func FormatAsk(result *app.AskResult) string {
 	return output
 }
 
+// writeAskNode writes one numbered knowledge result with its content preview.
+func writeAskNode(sb *strings.Builder, idx int, node knowledge.NodeResponse) {
+	sb.WriteString(fmt.Sprintf("%d. **%s** (%s)", idx, node.Summary, node.Type))
+	if node.FreshnessNote != "" {
+		sb.WriteString(fmt.Sprintf(" %s", node.FreshnessNote))
+	}
+	if node.Content != "" && node.Content != node.Summary {
+		content := cleanContent(node.Content, node.Summary)
+		if content != "" {
+			// Show a longer preview (300 chars by default) so the consuming LLM has enough
+			// context to produce comprehensive answers
+			preview := truncate(content, presenterConfig.KnowledgePreviewLength)
+			sb.WriteString(fmt.Sprintf("\n   %s", preview))
+		}
+	}
+	if node.DebtWarning != "" {
+		sb.WriteString(fmt.Sprintf("\n   %s", node.DebtWarning))
+	}
+	if m := node.MatchReason; m != nil {
+		sb.WriteString(fmt.Sprintf("\n   Why: %s match (fts %.2f × %.2f + vector %.2f × %.2f)",
+			m.Via, m.FTSScore, m.FTSWeight, m.VectorScore, m.VectorWeight))
+	}
+	sb.WriteString("\n")
+}
+
+// writeKnowledgeByFeature writes the results under one heading per feature,
+// in ranked order within each group. Results linked to no feature follow
+// under "Other".
+func writeKnowledgeByFeature(sb *strings.Builder, result *app.AskResult) {
+	byID := make(map[string]knowledge.NodeResponse, len(result.Results))
+	for _, node := range result.Results {
+		byID[node.ID] = node
+	}
+	placed := make(map[string]bool, len(result.Results))
+
+	idx := 1
+	for _, g := range result.FeatureGroups {
+		sb.WriteString(fmt.Sprintf("### ▸ %s (%d)\n", g.Feature, len(g.NodeIDs)))
+		for _, id := range g.NodeIDs {
+			node, ok := byID[id]
+			if !ok || placed[id] {
+				continue
+			}
+			placed[id] = true
+			writeAskNode(sb, idx, node)
+			idx++
+		}
+	}
+
+	var other []knowledge.NodeResponse
+	for _, node := range result.Results {
+		if !placed[node.ID] {
+			other = append(other, node)
+		}
+	}
+	if len(other) > 0 {
+		sb.WriteString(fmt.Sprintf("### ▸ Other (%d)\n", len(other)))
+		for _, node := range other {
+			writeAskNode(sb, idx, node)
+			idx++
+		}
+	}
+}
+
 // FormatTask converts a TaskResult into concise Markdown.
 func FormatTask(result *app.TaskResult) string {
 	if result == nil {
@@ -283,8 +351,22 @@ func FormatTask(result *app.TaskResult) string {
 			sb.WriteString("\n\n")
 		}
 
-		// Acceptance criteria as checklist
-		if len(t.AcceptanceCriteria) > 0 {
+		// Acceptance criteria as checklist, annotated when verified on completion
+		if len(t.CriteriaVerification) > 0 {
+			sb.WriteString("### Acceptance Criteria (verified)\n")
+			for _, v := range t.CriteriaVerification {
+				checkbox := "[ ]"
+				if v.Status == task.CriterionPass {
+					checkbox = "[x]"
+				}
+				sb.WriteString(fmt.Sprintf("- %s %s — %s", checkbox, v.Criterion, v.Status))
+				if v.Evidence != "" {
+					sb.WriteString(fmt.Sprintf(": %s", v.Evidence))
+				}
+				sb.WriteString("\n")
+			}
+			sb.WriteString("\n")
+		} else if len(t.AcceptanceCriteria) > 0 {
 			sb.WriteString("### Acceptance Criteria\n")
 			for _, ac := range t.AcceptanceCriteria {
 				checkbox := "[ ]"
@@ -299,11 +381,7 @@ func FormatTask(result *app.TaskResult) string {
 		// Validation steps
 		if len(t.ValidationSteps) > 0 {
 			sb.WriteString("### Validation\n")
-			sb.WriteString("```bash\n")
-			for _, step := range t.ValidationSteps {
-				sb.WriteString(step + "\n")
-			}
-			sb.WriteString("```\n")
+			sb.WriteString(task.FormatValidationSteps(t.ValidationSteps))
 		}
 	}
 
@@ -320,6 +398,30 @@ func FormatTask(result *app.TaskResult) string {
 	return strings.TrimSpace(sb.String())
 }
 
+// FormatCurrentTask formats the in-progress task with what an agent needs to
+// resume it: the task details, its context summary and the code it touches.
+func FormatCurrentTask(result *app.TaskResult) string {
+	var sb strings.Builder
+	sb.WriteString(FormatTask(result))
+	if result == nil || result.Task == nil {
+		return sb.String()
+	}
+
+	if summary := strings.TrimSpace(result.Task.ContextSummary); summary != "" {
+		sb.WriteString("\n\n### Context\n")
+		sb.WriteString(summary)
+	}
+
+	if len(result.Symbols) > 0 {
+		sb.WriteString("\n\n### Related Code\n")
+		for _, sym := range result.Symbols {
+			sb.WriteString(fmt.Sprintf("- `%s` (%s) — %s:%d\n", sym.Name, sym.Kind, sym.FilePath, sym.StartLine))
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
 // FormatTaskCompletionBlocked formats a blocked task completion (e.g., policy violations) into Markdown.
 // This provides AI agents with clear, actionable information about why completion was blocked.
 func FormatTaskCompletionBlocked(result *app.TaskResult) string {
@@ -411,11 +513,15 @@ func FormatSymbolList(symbols []codeintel.Symbol) string {
 		if sym.Visibility == "private" {
 			visibility = " (private)"
 		}
-		sb.WriteString(fmt.Sprintf("- `%s`%s (%s) — %s\n", sym.Name, visibility, sym.Kind, location))
+		age := ""
+		if !sym.LastModified.IsZero() {
+			age = " · changed " + formatAge(time.Since(sym.LastModified))
+		}
+		sb.WriteString(fmt.Sprintf("- `%s`%s (%s) — %s%s\n", sym.Name, visibility, sym.Kind, location, age))
 
 		// Add signature for functions/methods
 		if sym.Signature != "" && (sym.Kind == codeintel.SymbolFunction || sym.Kind == codeintel.SymbolMethod) {
-			sig := truncate(sym.Signature, 60)
+			sig := truncate(sym.Signature, presenterConfig.SignatureLength)
 			sb.WriteString(fmt.Sprintf("  `%s`\n", sig))
 		}
 	}
@@ -423,6 +529,21 @@ func FormatSymbolList(symbols []codeintel.Symbol) string {
 	return strings.TrimSpace(sb.String())
 }
 
+// formatAge renders how long ago something happened at day/month/year granularity.
+func formatAge(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 60:
+		return fmt.Sprintf("%dd ago", days)
+	case days < 730:
+		return fmt.Sprintf("%dmo ago", days/30)
+	default:
+		return fmt.Sprintf("%dy ago", days/365)
+	}
+}
+
 // FormatSearchResults converts semantic search results into Markdown.
 func FormatSearchResults(results []codeintel.SymbolSearchResult) string {
 	if len(results) == 0 {
@@ -439,10 +560,14 @@ func FormatSearchResults(results []codeintel.SymbolSearchResult) string {
 		// Score indicator
 		scoreBar := scoreToBar(r.Score)
 		sb.WriteString(fmt.Sprintf("   Score: %s %.2f\n", scoreBar, r.Score))
+		if m := r.MatchReason; m != nil {
+			sb.WriteString(fmt.Sprintf("   Why: %s match (fts %.2f × %.2f + vector %.2f × %.2f)\n",
+				m.Via, m.FTSScore, m.FTSWeight, m.VectorScore, m.VectorWeight))
+		}
 
 		// Doc comment preview
 		if r.Symbol.DocComment != "" {
-			doc := truncate(r.Symbol.DocComment, 80)
+			doc := truncate(r.Symbol.DocComment, presenterConfig.DocLength)
 			sb.WriteString(fmt.Sprintf("   > %s\n", doc))
 		}
 	}
@@ -451,7 +576,8 @@ func FormatSearchResults(results []codeintel.SymbolSearchResult) string {
 }
 
 // FormatCallers converts a GetCallersResult into Markdown.
-func FormatCallers(result *app.GetCallersResult) string {
+// Compact mode lists at most compactListLimit callers and callees; verbose lists all.
+func FormatCallers(result *app.GetCallersResult, verbose bool) string {
 	if result == nil || !result.Success {
 		msg := "Failed to get callers."
 		if result != nil && result.Message != "" {
@@ -472,18 +598,22 @@ func FormatCallers(result *app.GetCallersResult) string {
 	// Callers
 	if len(result.Callers) > 0 {
 		sb.WriteString("### Called By\n")
-		for _, caller := range result.Callers {
+		shown := listCap(len(result.Callers), verbose)
+		for _, caller := range result.Callers[:shown] {
 			sb.WriteString(fmt.Sprintf("- `%s` — %s:%d\n", caller.Name, caller.FilePath, caller.StartLine))
 		}
+		writeMore(&sb, len(result.Callers)-shown)
 		sb.WriteString("\n")
 	}
 
 	// Callees
 	if len(result.Callees) > 0 {
 		sb.WriteString("### Calls\n")
-		for _, callee := range result.Callees {
+		shown := listCap(len(result.Callees), verbose)
+		for _, callee := range result.Callees[:shown] {
 			sb.WriteString(fmt.Sprintf("- `%s` — %s:%d\n", callee.Name, callee.FilePath, callee.StartLine))
 		}
+		writeMore(&sb, len(result.Callees)-shown)
 	}
 
 	output := strings.TrimSpace(sb.String())
@@ -493,8 +623,75 @@ func FormatCallers(result *app.GetCallersResult) string {
 	return output
 }
 
+// FormatUsages converts a GetUsagesResult into Markdown, one line per use
+// labelled with its relation kind. Compact mode lists at most compactListLimit.
+func FormatUsages(result *app.GetUsagesResult, verbose bool) string {
+	if result == nil || !result.Success {
+		msg := "Failed to get usages."
+		if result != nil && result.Message != "" {
+			msg = result.Message
+		}
+		return msg
+	}
+
+	var sb strings.Builder
+	if result.Symbol != nil {
+		sym := result.Symbol
+		sb.WriteString(fmt.Sprintf("## `%s` (%s)\n", sym.Name, sym.Kind))
+		sb.WriteString(fmt.Sprintf("%s:%d\n\n", sym.FilePath, sym.StartLine))
+	}
+	if len(result.Usages) == 0 {
+		sb.WriteString("No usages found.")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("### Used By (%d)\n", len(result.Usages)))
+	shown := listCap(len(result.Usages), verbose)
+	for _, u := range result.Usages[:shown] {
+		line := u.Line
+		if line == 0 {
+			line = u.Symbol.StartLine
+		}
+		sb.WriteString(fmt.Sprintf("- [%s] `%s` — %s:%d\n", u.Relation, u.Symbol.Name, u.Symbol.FilePath, line))
+	}
+	writeMore(&sb, len(result.Usages)-shown)
+	return strings.TrimSpace(sb.String())
+}
+
+// FormatImplementations converts a GetImplementationsResult into Markdown, one
+// line per implementing type.
+func FormatImplementations(result *app.GetImplementationsResult, verbose bool) string {
+	if result == nil || !result.Success {
+		msg := "Failed to get implementations."
+		if result != nil && result.Message != "" {
+			msg = result.Message
+		}
+		return msg
+	}
+
+	var sb strings.Builder
+	if result.Interface != nil {
+		sym := result.Interface
+		sb.WriteString(fmt.Sprintf("## `%s` (%s)\n", sym.Name, sym.Kind))
+		sb.WriteString(fmt.Sprintf("%s:%d\n\n", sym.FilePath, sym.StartLine))
+	}
+	if len(result.Implementations) == 0 {
+		sb.WriteString("No implementations found.")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("### Implemented By (%d)\n", len(result.Implementations)))
+	shown := listCap(len(result.Implementations), verbose)
+	for _, s := range result.Implementations[:shown] {
+		sb.WriteString(fmt.Sprintf("- `%s` (%s) — %s:%d\n", s.Name, s.Kind, s.FilePath, s.StartLine))
+	}
+	writeMore(&sb, len(result.Implementations)-shown)
+	return strings.TrimSpace(sb.String())
+}
+
 // FormatImpact converts an AnalyzeImpactResult into Markdown.
-func FormatImpact(result *app.AnalyzeImpactResult) string {
+// Compact mode lists at most compactListLimit symbols per depth; verbose lists all.
+func FormatImpact(result *app.AnalyzeImpactResult, verbose bool) string {
 	if result == nil || !result.Success {
 		msg := "Failed to analyze impact."
 		if result != nil && result.Message != "" {
@@ -522,9 +719,11 @@ func FormatImpact(result *app.AnalyzeImpactResult) string {
 		for depth := 1; depth <= result.MaxDepth; depth++ {
 			if symbols, ok := result.ByDepth[depth]; ok && len(symbols) > 0 {
 				sb.WriteString(fmt.Sprintf("**Depth %d** (%d symbols):\n", depth, len(symbols)))
-				for _, sym := range symbols {
+				shown := listCap(len(symbols), verbose)
+				for _, sym := range symbols[:shown] {
 					sb.WriteString(fmt.Sprintf("- `%s` — %s:%d\n", sym.Name, sym.FilePath, sym.StartLine))
 				}
+				writeMore(&sb, len(symbols)-shown)
 				sb.WriteString("\n")
 			}
 		}
@@ -533,8 +732,41 @@ func FormatImpact(result *app.AnalyzeImpactResult) string {
 	return strings.TrimSpace(sb.String())
 }
 
+// FormatChangedSymbols converts a ChangedSymbolsResult into Markdown for MCP.
+func FormatChangedSymbols(result *app.ChangedSymbolsResult) string {
+	if result == nil || !result.Success {
+		msg := "Failed to list changed symbols."
+		if result != nil && result.Message != "" {
+			msg = result.Message
+		}
+		return msg
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Changed Symbols: `%s..%s`\n\n", result.Base, result.Head))
+
+	if result.Count == 0 {
+		sb.WriteString("No indexed symbols changed. Re-run `taskwing bootstrap` if the index is stale.")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("**%d symbols changed**\n\n", result.Count))
+	for _, c := range result.Symbols {
+		sym := c.Symbol
+		sb.WriteString(fmt.Sprintf("- `%s` (%s) — %s:%d", sym.Name, sym.Kind, sym.FilePath, sym.StartLine))
+		if c.AffectedCount > 0 {
+			sb.WriteString(fmt.Sprintf(" — impacts %d symbols in %d files", c.AffectedCount, c.AffectedFiles))
+		}
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
 // FormatExplainResult converts an ExplainResult into Markdown for MCP.
-func FormatExplainResult(result *app.ExplainResult) string {
+// Compact mode caps call lists, doc comments, and source snippets for token
+// efficiency; verbose renders them in full.
+func FormatExplainResult(result *app.ExplainResult, verbose bool) string {
 	if result == nil {
 		return "No explanation available."
 	}
@@ -550,7 +782,11 @@ func FormatExplainResult(result *app.ExplainResult) string {
 	}
 
 	if result.Symbol.DocComment != "" {
-		sb.WriteString(fmt.Sprintf("> %s\n\n", truncate(result.Symbol.DocComment, 200)))
+		doc := result.Symbol.DocComment
+		if !verbose {
+			doc = truncate(doc, presenterConfig.CompactDocLength)
+		}
+		sb.WriteString(fmt.Sprintf("> %s\n\n", doc))
 	}
 
 	// Call graph context
@@ -561,13 +797,11 @@ func FormatExplainResult(result *app.ExplainResult) string {
 	if len(result.Callers) == 0 {
 		sb.WriteString("- *(none - may be entry point)*\n")
 	} else {
-		for i, c := range result.Callers {
-			if i >= 5 {
-				sb.WriteString(fmt.Sprintf("- *...and %d more*\n", len(result.Callers)-5))
-				break
-			}
+		shown := listCap(len(result.Callers), verbose)
+		for _, c := range result.Callers[:shown] {
 			sb.WriteString(fmt.Sprintf("- `%s` — %s\n", c.Symbol.Name, c.Symbol.Location))
 		}
+		writeMore(&sb, len(result.Callers)-shown)
 	}
 
 	// Callees
@@ -575,13 +809,11 @@ func FormatExplainResult(result *app.ExplainResult) string {
 	if len(result.Callees) == 0 {
 		sb.WriteString("- *(none - may be leaf function)*\n")
 	} else {
-		for i, c := range result.Callees {
-			if i >= 5 {
-				sb.WriteString(fmt.Sprintf("- *...and %d more*\n", len(result.Callees)-5))
-				break
-			}
+		shown := listCap(len(result.Callees), verbose)
+		for _, c := range result.Callees[:shown] {
 			sb.WriteString(fmt.Sprintf("- `%s` — %s\n", c.Symbol.Name, c.Symbol.Location))
 		}
+		writeMore(&sb, len(result.Callees)-shown)
 	}
 
 	// Impact summary
@@ -595,6 +827,10 @@ func FormatExplainResult(result *app.ExplainResult) string {
 	if result.ImpactStats.AffectedFiles > 0 {
 		sb.WriteString(fmt.Sprintf("- Files affected: %d\n", result.ImpactStats.AffectedFiles))
 	}
+	if len(result.Warnings) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(formatRefactorWarnings(result.Warnings))
+	}
 
 	// Related decisions
 	if len(result.Decisions) > 0 {
@@ -617,12 +853,12 @@ func FormatExplainResult(result *app.ExplainResult) string {
 		sb.WriteString("\n### Source Context\n")
 		for _, snippet := range result.SourceCode {
 			sb.WriteString(fmt.Sprintf("\n**%s `%s`** (%s):\n", snippet.Kind, snippet.SymbolName, snippet.FilePath))
-			// Limit to first 20 lines for tokens
+			// Limit snippet length for tokens unless verbose
 			lines := strings.Split(snippet.Content, "\n")
-			if len(lines) > 20 {
+			if !verbose && len(lines) > compactSnippetLines {
 				sb.WriteString("```\n")
-				sb.WriteString(strings.Join(lines[:20], "\n"))
-				sb.WriteString(fmt.Sprintf("\n// ...%d more lines\n", len(lines)-20))
+				sb.WriteString(strings.Join(lines[:compactSnippetLines], "\n"))
+				sb.WriteString(fmt.Sprintf("\n// ...%d more lines\n", len(lines)-compactSnippetLines))
 				sb.WriteString("```\n")
 			} else {
 				sb.WriteString("```\n")
@@ -642,6 +878,88 @@ func FormatExplainResult(result *app.ExplainResult) string {
 	return strings.TrimSpace(sb.String())
 }
 
+// FormatFileExplainResult converts a FileExplainResult into Markdown for MCP.
+func FormatFileExplainResult(result *app.FileExplainResult) string {
+	if result == nil {
+		return "No explanation available."
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## `%s`", result.FilePath))
+	if result.Language != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", result.Language))
+	}
+	sb.WriteString("\n\n")
+
+	sb.WriteString(fmt.Sprintf("### Symbols (%d)\n", len(result.Symbols)))
+	for _, s := range result.Symbols {
+		sb.WriteString(fmt.Sprintf("- `%s` %s — line %d\n", s.Name, s.Kind, s.StartLine))
+	}
+
+	if len(result.MostCalled) > 0 {
+		sb.WriteString("\n### Most Called\n")
+		for _, c := range result.MostCalled {
+			sb.WriteString(fmt.Sprintf("- `%s` — %d callers\n", c.Symbol.Name, c.CallerCount))
+		}
+	}
+
+	if len(result.Imports) > 0 {
+		sb.WriteString(fmt.Sprintf("\n### Imports (%d)\n", len(result.Imports)))
+		for _, imp := range result.Imports {
+			sb.WriteString(fmt.Sprintf("- `%s`\n", imp))
+		}
+	}
+
+	if result.Explanation != "" {
+		sb.WriteString("\n### Explanation\n")
+		sb.WriteString(result.Explanation)
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// FormatFileImports converts a file's categorized imports into Markdown.
+func FormatFileImports(imports *codeintel.FileImports) string {
+	if imports == nil || len(imports.Internal)+len(imports.External) == 0 {
+		return "No imports recorded for this file. Re-index the project if it was added recently."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Imports of `%s`\n", imports.FilePath))
+
+	sb.WriteString(fmt.Sprintf("\n### Internal (%d)\n", len(imports.Internal)))
+	for _, imp := range imports.Internal {
+		sb.WriteString(fmt.Sprintf("- `%s`\n", imp.Path))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n### External (%d)\n", len(imports.External)))
+	for _, imp := range imports.External {
+		sb.WriteString(fmt.Sprintf("- `%s` (%s)\n", imp.Path, imp.Category))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// FormatModuleTree renders a directory's symbols as a file tree in Markdown.
+func FormatModuleTree(tree *codeintel.ModuleTree) string {
+	if tree == nil || len(tree.Files) == 0 {
+		return "No symbols indexed in this directory. Subdirectories are listed separately; re-index the project if files were added recently."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## `%s/` (%d symbols in %d files)\n\n", tree.ModulePath, tree.SymbolCount, len(tree.Files)))
+	for _, f := range tree.Files {
+		sb.WriteString(fmt.Sprintf("- `%s` (%d)\n", filepath.Base(f.FilePath), f.Count))
+		for _, s := range f.Symbols {
+			sb.WriteString(fmt.Sprintf("  - %s `%s` L%d\n", s.Kind, s.Name, s.StartLine))
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
 // FormatDriftReport converts a DriftReport into Markdown for MCP.
 func FormatDriftReport(report *app.DriftReport) string {
 	if report == nil {
@@ -731,20 +1049,107 @@ func FormatRemember(result *app.AddResult) string {
 	sb.WriteString(fmt.Sprintf("**ID**: `%s`\n", result.ID))
 	sb.WriteString(fmt.Sprintf("**Type**: %s\n", result.Type))
 	sb.WriteString(fmt.Sprintf("**Summary**: %s\n", result.Summary))
+	if len(result.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("**Tags**: %s\n", strings.Join(result.Tags, ", ")))
+	}
 	if result.HasEmbedding {
 		sb.WriteString("\n*Embedding generated for semantic search.*\n")
 	}
 	return strings.TrimSpace(sb.String())
 }
 
+// FormatInfo formats the info tool result.
+func FormatInfo(info *InfoToolResult) string {
+	var sb strings.Builder
+	sb.WriteString("## TaskWing MCP Server\n\n")
+	sb.WriteString(fmt.Sprintf("- **Version**: %s\n", info.Version))
+	mode := "read-write"
+	if info.ReadOnly {
+		mode = "read-only"
+	}
+	sb.WriteString(fmt.Sprintf("- **Mode**: %s\n", mode))
+	sb.WriteString(fmt.Sprintf("- **Tools**: %s\n", strings.Join(info.Tools, ", ")))
+	if info.CodeIndex {
+		sb.WriteString(fmt.Sprintf("- **Code index**: %d symbols\n", info.IndexedSymbols))
+	} else {
+		sb.WriteString("- **Code index**: none (run `taskwing bootstrap` to index code)\n")
+	}
+	if info.ActivePlan {
+		sb.WriteString(fmt.Sprintf("- **Active plan**: `%s`\n", info.ActivePlanID))
+	} else {
+		sb.WriteString("- **Active plan**: none\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
 // === Error Formatters ===
 
+// FormatPolicyList formats loaded policies with their source (local directory or bundle).
+func FormatPolicyList(policies []*policy.PolicyFile) string {
+	if len(policies) == 0 {
+		return "No policies configured. Add .rego files to the policies directory or configure policy.bundles."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Policies (%d)\n\n", len(policies)))
+	sb.WriteString("| Policy | Source | Path |\n")
+	sb.WriteString("|--------|--------|------|\n")
+	for _, p := range policies {
+		source := p.Source
+		if source == "" {
+			source = policy.PolicySourceLocal
+		}
+		if p.Location != "" {
+			source = fmt.Sprintf("%s (`%s`)", source, p.Location)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | `%s` |\n", p.Name, source, p.Path))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// FormatPolicyWhy formats the deny rules that block a file.
+func FormatPolicyWhy(file string, policyCount int, explanations []policy.DenyExplanation) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Policy Check: `%s`\n\n", file))
+
+	if policyCount == 0 {
+		sb.WriteString("No policies configured. Nothing blocks this file.")
+		return sb.String()
+	}
+	if len(explanations) == 0 {
+		sb.WriteString(fmt.Sprintf("✅ Allowed. None of the %d loaded policies deny changes to this file.", policyCount))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("⛔ Denied by %d rule(s).\n", len(explanations)))
+	for _, e := range explanations {
+		sb.WriteString(fmt.Sprintf("\n### %s\n", e.Rule))
+		sb.WriteString(fmt.Sprintf("**Policy**: `%s.rego:%d`\n", e.Policy, e.Line))
+		for _, msg := range e.Messages {
+			sb.WriteString(fmt.Sprintf("- %s\n", msg))
+		}
+		sb.WriteString(fmt.Sprintf("\n```rego\n%s\n```\n", e.Expression))
+		sb.WriteString(fmt.Sprintf("> **Remediation**: %s\n", e.Remediation))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
 // FormatError returns a standardized Markdown error message.
 // Use this for all MCP tool error responses to ensure consistency.
 func FormatError(message string) string {
 	return fmt.Sprintf("## ❌ Error\n\n**Details**: %s", message)
 }
 
+// formatPlanError renders a failed plan result, including its machine-readable code when set.
+func formatPlanError(message string, code app.PlanErrorCode) string {
+	if code == "" {
+		return FormatError(message)
+	}
+	return fmt.Sprintf("%s\n**Code**: `%s`", FormatError(message), code)
+}
+
 // FormatValidationError returns a Markdown error for validation failures.
 func FormatValidationError(field, message string) string {
 	return fmt.Sprintf("## ❌ Validation Error\n\n**Field**: `%s`\n**Details**: %s", field, message)
@@ -817,7 +1222,7 @@ func FormatClarifyResult(result *app.ClarifyResult) string {
 		if msg == "" {
 			msg = "Clarification failed with no details"
 		}
-		return FormatError(msg)
+		return formatPlanError(msg, result.Code)
 	}
 
 	var sb strings.Builder
@@ -888,13 +1293,17 @@ func FormatGenerateResult(result *app.GenerateResult) string {
 		if msg == "" {
 			msg = "Plan generation failed with no details"
 		}
-		return FormatError(msg)
+		return formatPlanError(msg, result.Code)
 	}
 
 	var sb strings.Builder
 
-	sb.WriteString("## ✅ Plan Generated\n\n")
-	sb.WriteString(fmt.Sprintf("**Plan**: `%s`\n", result.PlanID))
+	if result.DryRun {
+		sb.WriteString("## 🔍 Plan Preview (dry run)\n\n")
+	} else {
+		sb.WriteString("## ✅ Plan Generated\n\n")
+		sb.WriteString(fmt.Sprintf("**Plan**: `%s`\n", result.PlanID))
+	}
 	sb.WriteString(fmt.Sprintf("**Goal**: %s\n", result.Goal))
 	sb.WriteString(fmt.Sprintf("**Tasks**: %d\n\n", len(result.Tasks)))
 
@@ -908,6 +1317,25 @@ func FormatGenerateResult(result *app.GenerateResult) string {
 		sb.WriteString("\n")
 	}
 
+	// Dry runs exist to review issues before saving, so surface them
+	if result.DryRun && (len(result.SemanticErrors) > 0 || len(result.SemanticWarnings) > 0) {
+		sb.WriteString("### Validation\n")
+		for _, e := range result.SemanticErrors {
+			sb.WriteString(fmt.Sprintf("- ❌ %s\n", e))
+		}
+		for _, w := range result.SemanticWarnings {
+			sb.WriteString(fmt.Sprintf("- ⚠️ %s\n", w))
+		}
+		sb.WriteString("\n")
+	} else if !result.DryRun {
+		// Saved plans skip the validation list, but a missing index is worth knowing
+		for _, w := range result.Warnings {
+			if w.Category == app.WarningCategoryCodeIntel {
+				sb.WriteString(fmt.Sprintf("⚠️ %s\n\n", w.Message))
+			}
+		}
+	}
+
 	if result.Hint != "" {
 		sb.WriteString(fmt.Sprintf("> %s\n", result.Hint))
 	}
@@ -926,7 +1354,7 @@ func FormatAuditResult(result *app.AuditResult) string {
 		if msg == "" {
 			msg = "Audit failed with no details"
 		}
-		return FormatError(msg)
+		return formatPlanError(msg, result.Code)
 	}
 
 	var sb strings.Builder
@@ -1000,7 +1428,7 @@ func FormatDecomposeResult(result *app.DecomposeResult) string {
 		if msg == "" {
 			msg = "Decomposition failed with no details"
 		}
-		return FormatError(msg)
+		return formatPlanError(msg, result.Code)
 	}
 
 	var sb strings.Builder
@@ -1010,6 +1438,10 @@ func FormatDecomposeResult(result *app.DecomposeResult) string {
 	sb.WriteString(fmt.Sprintf("**Phases**: %d\n\n", len(result.Phases)))
 
 	// List phases
+	titleByID := make(map[string]string, len(result.Phases))
+	for _, phase := range result.Phases {
+		titleByID[phase.ID] = phase.Title
+	}
 	sb.WriteString("### Phases\n")
 	for i, phase := range result.Phases {
 		sb.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, phase.Title))
@@ -1017,6 +1449,13 @@ func FormatDecomposeResult(result *app.DecomposeResult) string {
 			sb.WriteString(fmt.Sprintf("   %s\n", phase.Description))
 		}
 		sb.WriteString(fmt.Sprintf("   _Expected tasks: %d_\n", phase.ExpectedTasks))
+		if len(phase.DependsOn) > 0 {
+			deps := make([]string, 0, len(phase.DependsOn))
+			for _, id := range phase.DependsOn {
+				deps = append(deps, titleByID[id])
+			}
+			sb.WriteString(fmt.Sprintf("   _Depends on: %s_\n", strings.Join(deps, ", ")))
+		}
 	}
 	sb.WriteString("\n")
 
@@ -1046,7 +1485,7 @@ func FormatExpandResult(result *app.ExpandResult) string {
 		if msg == "" {
 			msg = "Expansion failed with no details"
 		}
-		return FormatError(msg)
+		return formatPlanError(msg, result.Code)
 	}
 
 	var sb strings.Builder
@@ -1086,6 +1525,80 @@ func FormatExpandResult(result *app.ExpandResult) string {
 	return strings.TrimSpace(sb.String())
 }
 
+// FormatTaskList formats tasks as a Markdown table.
+func FormatTaskList(tasks []task.Task) string {
+	if len(tasks) == 0 {
+		return "No tasks match the given filters."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Tasks (%d)\n\n", len(tasks)))
+	sb.WriteString("| # | Priority | Status | Title | ID |\n")
+	sb.WriteString("|---|----------|--------|-------|----|\n")
+	for i, t := range tasks {
+		title := strings.ReplaceAll(t.Title, "|", "\\|")
+		sb.WriteString(fmt.Sprintf("| %d | %d | %s %s | %s | `%s` |\n", i+1, t.Priority, statusIcon(t.Status), t.Status, title, t.ID))
+	}
+	return sb.String()
+}
+
+// FormatTaskDependencies shows a task between the tasks it depends on and the tasks that depend on it.
+func FormatTaskDependencies(t *task.Task, upstream, downstream []task.Task) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Dependencies of %s (`%s`)\n\n", t.Title, t.ID))
+
+	writeTasks := func(heading, empty string, tasks []task.Task) {
+		sb.WriteString(fmt.Sprintf("### %s (%d)\n", heading, len(tasks)))
+		if len(tasks) == 0 {
+			sb.WriteString(empty + "\n")
+		}
+		for _, dep := range tasks {
+			sb.WriteString(fmt.Sprintf("- %s %s (`%s`) — %s\n", statusIcon(dep.Status), dep.Title, dep.ID, dep.Status))
+		}
+	}
+	writeTasks("Upstream — depends on", "None; this task can start any time.", upstream)
+	sb.WriteString("\n")
+	writeTasks("Downstream — depended on by", "None; nothing waits on this task.", downstream)
+	return strings.TrimSpace(sb.String())
+}
+
+// FormatPlanList formats one page of plans as a Markdown table.
+// offset is the page start, so row numbers continue across pages.
+func FormatPlanList(plans []task.Plan, offset int) string {
+	if len(plans) == 0 {
+		return "No plans match the given filters."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Plans (%d)\n\n", len(plans)))
+	sb.WriteString("| # | Status | Goal | Tasks | ID |\n")
+	sb.WriteString("|---|--------|------|-------|----|\n")
+	for i, p := range plans {
+		goal := strings.ReplaceAll(p.Goal, "|", "\\|")
+		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %d | `%s` |\n", offset+i+1, p.Status, goal, p.GetTaskCount(), p.ID))
+	}
+	return sb.String()
+}
+
+// FormatMergeResult formats the plan produced by a merge.
+func FormatMergeResult(plan *task.Plan, sourceID string) string {
+	if plan == nil {
+		return FormatError("No merge result.")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Plans Merged\n\n")
+	sb.WriteString(fmt.Sprintf("**Plan ID**: `%s`\n", plan.ID))
+	sb.WriteString(fmt.Sprintf("**Merged From**: `%s` (deleted)\n", sourceID))
+	sb.WriteString(fmt.Sprintf("**Status**: %s\n", plan.Status))
+	if len(plan.Phases) > 0 {
+		sb.WriteString(fmt.Sprintf("**Total Phases**: %d\n", len(plan.Phases)))
+	}
+	sb.WriteString(fmt.Sprintf("**Total Tasks**: %d\n\n", len(plan.Tasks)))
+	sb.WriteString("> **Next**: Use `task action=reorder` to adjust the combined task order.\n")
+	return sb.String()
+}
+
 // FormatFinalizeResult formats plan finalization output.
 func FormatFinalizeResult(result *app.FinalizeResult) string {
 	if result == nil {
@@ -1097,7 +1610,7 @@ func FormatFinalizeResult(result *app.FinalizeResult) string {
 		if msg == "" {
 			msg = "Finalization failed with no details"
 		}
-		return FormatError(msg)
+		return formatPlanError(msg, result.Code)
 	}
 
 	var sb strings.Builder
@@ -1161,6 +1674,53 @@ func statusIcon(status task.TaskStatus) string {
 	}
 }
 
+// Compact-mode caps for code intelligence output; verbose mode lifts them.
+// The doc comment cap is PresenterConfig.CompactDocLength.
+const (
+	compactListLimit    = 5  // Items per caller/callee/impact list
+	compactSnippetLines = 20 // Lines per source snippet
+)
+
+// presenterConfig holds the truncation limits used by every formatter.
+var presenterConfig = config.DefaultPresenterConfig()
+
+// SetPresenterConfig replaces the truncation limits used by the formatters.
+// Zero or negative fields keep their default.
+func SetPresenterConfig(cfg config.PresenterConfig) {
+	defaults := config.DefaultPresenterConfig()
+	presenterConfig = config.PresenterConfig{
+		TitleLength:            positiveOr(cfg.TitleLength, defaults.TitleLength),
+		SummaryPreviewLength:   positiveOr(cfg.SummaryPreviewLength, defaults.SummaryPreviewLength),
+		KnowledgePreviewLength: positiveOr(cfg.KnowledgePreviewLength, defaults.KnowledgePreviewLength),
+		NodePreviewLength:      positiveOr(cfg.NodePreviewLength, defaults.NodePreviewLength),
+		SignatureLength:        positiveOr(cfg.SignatureLength, defaults.SignatureLength),
+		DocLength:              positiveOr(cfg.DocLength, defaults.DocLength),
+		CompactDocLength:       positiveOr(cfg.CompactDocLength, defaults.CompactDocLength),
+	}
+}
+
+func positiveOr(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
+
+// listCap returns how many of n list items to render.
+func listCap(n int, verbose bool) int {
+	if verbose {
+		return n
+	}
+	return min(n, compactListLimit)
+}
+
+// writeMore notes how many list items were left out.
+func writeMore(sb *strings.Builder, hidden int) {
+	if hidden > 0 {
+		sb.WriteString(fmt.Sprintf("- *...and %d more (verbose=true to show all)*\n", hidden))
+	}
+}
+
 // truncate shortens a string to maxLen and adds ellipsis
 func truncate(s string, maxLen int) string {
 	runes := []rune(s)
@@ -1201,7 +1761,7 @@ func FormatNodeResponse(node knowledge.NodeResponse) string {
 	if node.Content != "" && node.Content != node.Summary {
 		content := cleanContent(node.Content, node.Summary)
 		if content != "" {
-			preview := truncate(content, 150)
+			preview := truncate(content, presenterConfig.NodePreviewLength)
 			sb.WriteString(fmt.Sprintf("\n%s", preview))
 		}
 	}
@@ -1212,9 +1772,10 @@ func FormatNodeResponse(node knowledge.NodeResponse) string {
 	return sb.String()
 }
 
-// FormatDebugResult formats the output from the DebugAgent.
-func FormatDebugResult(findings []agentcore.Finding) string {
-	if len(findings) == 0 {
+// FormatDebugResult formats the output from the DebugAgent, followed by the
+// knowledge notes recalled for the problem.
+func FormatDebugResult(findings []agentcore.Finding, relatedNotes []knowledge.NodeResponse) string {
+	if len(findings) == 0 && len(relatedNotes) == 0 {
 		return "No debug analysis available."
 	}
 
@@ -1284,6 +1845,13 @@ func FormatDebugResult(findings []agentcore.Finding) string {
 		}
 	}
 
+	if len(relatedNotes) > 0 {
+		sb.WriteString("\n### Related Notes\n")
+		for _, n := range relatedNotes {
+			sb.WriteString(fmt.Sprintf("- **%s** (%s, `%s`)\n", n.Summary, n.Type, n.ID))
+		}
+	}
+
 	return strings.TrimSpace(sb.String())
 }
 
@@ -1382,13 +1950,28 @@ func getIntField(m map[string]interface{}, key string) int {
 	return 0
 }
 
+// formatRefactorWarnings renders refactoring signals such as high fan-in symbols.
+func formatRefactorWarnings(warnings []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### ⚠️ Warnings (%d)\n", len(warnings)))
+	for _, w := range warnings {
+		sb.WriteString(fmt.Sprintf("- %s\n", w))
+	}
+	return sb.String()
+}
+
 // FormatSimplifyResult formats the output from the SimplifyAgent.
-func FormatSimplifyResult(findings []agentcore.Finding) string {
+// A non-nil scope adds the callers to re-test and any high fan-in warnings.
+func FormatSimplifyResult(findings []agentcore.Finding, scope *app.SimplifyScope) string {
 	if len(findings) == 0 {
 		return "No simplification results."
 	}
 
 	var sb strings.Builder
+	if scope != nil && len(scope.Warnings) > 0 {
+		sb.WriteString(formatRefactorWarnings(scope.Warnings))
+		sb.WriteString("**Review carefully**: these symbols are widely depended upon; prefer splitting them over rewriting in place.\n\n")
+	}
 	for _, f := range findings {
 		sb.WriteString("## Code Simplification\n\n")
 		sb.WriteString(f.Description)
@@ -1429,6 +2012,16 @@ func FormatSimplifyResult(findings []agentcore.Finding) string {
 		}
 	}
 
+	if scope != nil && len(scope.Targets) > 0 {
+		sb.WriteString(fmt.Sprintf("\n### Callers to Re-test (%d)\n", len(scope.Callers)))
+		if len(scope.Callers) == 0 {
+			sb.WriteString("- *(no indexed callers outside the simplified code)*\n")
+		}
+		for _, c := range scope.Callers {
+			sb.WriteString(fmt.Sprintf("- `%s` — %s:%d\n", c.Name, c.FilePath, c.StartLine))
+		}
+	}
+
 	return strings.TrimSpace(sb.String())
 }
 
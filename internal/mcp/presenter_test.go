@@ -1,10 +1,19 @@
 package mcp
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
+	agentcore "github.com/josephgoksu/TaskWing/internal/agents/core"
+	"github.com/josephgoksu/TaskWing/internal/app"
+	"github.com/josephgoksu/TaskWing/internal/codeintel"
+	"github.com/josephgoksu/TaskWing/internal/config"
+	"github.com/josephgoksu/TaskWing/internal/knowledge"
 	"github.com/josephgoksu/TaskWing/internal/memory"
+	"github.com/josephgoksu/TaskWing/internal/task"
 )
 
 func makeTestNodes(n int) []memory.Node {
@@ -135,3 +144,262 @@ func TestFormatKnowledgeFull_PreservedBehavior(t *testing.T) {
 		t.Error("full mode should contain content details")
 	}
 }
+
+func TestFormatSimplifyResult_CallersToRetest(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+	codeRepo := codeintel.NewRepository(store.DB())
+
+	ids := make(map[string]uint32)
+	for _, s := range []codeintel.Symbol{
+		{Name: "ParseConfig", Kind: codeintel.SymbolFunction, FilePath: "internal/config/parse.go", StartLine: 10},
+		{Name: "normalize", Kind: codeintel.SymbolFunction, FilePath: "internal/config/parse.go", StartLine: 30},
+		{Name: "Load", Kind: codeintel.SymbolFunction, FilePath: "cmd/root.go", StartLine: 5},
+		{Name: "Reload", Kind: codeintel.SymbolFunction, FilePath: "internal/server/reload.go", StartLine: 12},
+	} {
+		s.Language = "go"
+		s.EndLine = s.StartLine + 5
+		id, err := codeRepo.UpsertSymbol(ctx, &s)
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+		ids[s.Name] = id
+	}
+	for _, r := range [][2]string{{"Load", "ParseConfig"}, {"Reload", "ParseConfig"}, {"ParseConfig", "normalize"}} {
+		rel := &codeintel.SymbolRelation{FromSymbolID: ids[r[0]], ToSymbolID: ids[r[1]], RelationType: codeintel.RelationCalls}
+		if err := codeRepo.UpsertRelation(ctx, rel); err != nil {
+			t.Fatalf("UpsertRelation: %v", err)
+		}
+	}
+
+	scope, err := app.NewCodeIntelApp(app.NewContext(repo)).SimplifyScope(ctx, app.SimplifyScopeOptions{
+		FilePath: "internal/config/parse.go",
+	})
+	if err != nil {
+		t.Fatalf("SimplifyScope: %v", err)
+	}
+	findings := []agentcore.Finding{{Description: "Reduced from 40 to 25 lines (37% reduction)"}}
+	out := FormatSimplifyResult(findings, scope)
+
+	if !strings.Contains(out, "### Callers to Re-test (2)") {
+		t.Fatalf("expected caller section with 2 callers, got:\n%s", out)
+	}
+	for _, want := range []string{"`Load` — cmd/root.go:5", "`Reload` — internal/server/reload.go:12"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing caller %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "`normalize` —") {
+		t.Errorf("callers inside the simplified file should not be listed:\n%s", out)
+	}
+	if strings.Contains(out, "Warnings") {
+		t.Errorf("unexpected fan-in warning for 2 callers:\n%s", out)
+	}
+}
+
+func TestHandleCodeTool_Implementations(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := memory.NewRepository(store, nil)
+	codeRepo := codeintel.NewRepository(store.DB())
+
+	ids := make(map[string]uint32)
+	for _, s := range []codeintel.Symbol{
+		{Name: "Closer", Kind: codeintel.SymbolInterface, FilePath: "io.go", StartLine: 1},
+		{Name: "ReadCloser", Kind: codeintel.SymbolInterface, FilePath: "io.go", StartLine: 5},
+		{Name: "File", Kind: codeintel.SymbolStruct, FilePath: "file.go", StartLine: 3},
+	} {
+		s.Language = "go"
+		s.EndLine = s.StartLine + 2
+		id, err := codeRepo.UpsertSymbol(ctx, &s)
+		if err != nil {
+			t.Fatalf("UpsertSymbol %s: %v", s.Name, err)
+		}
+		ids[s.Name] = id
+	}
+	for _, r := range []codeintel.SymbolRelation{
+		{FromSymbolID: ids["ReadCloser"], ToSymbolID: ids["Closer"], RelationType: codeintel.RelationExtends},
+		{FromSymbolID: ids["File"], ToSymbolID: ids["ReadCloser"], RelationType: codeintel.RelationImplements},
+	} {
+		if err := codeRepo.UpsertRelation(ctx, &r); err != nil {
+			t.Fatalf("UpsertRelation: %v", err)
+		}
+	}
+
+	result, err := HandleCodeTool(ctx, repo, CodeToolParams{Action: CodeActionImpls, Query: "Closer"})
+	if err != nil || result.Error != "" {
+		t.Fatalf("implementations: %v %s", err, result.Error)
+	}
+	if !strings.Contains(result.Content, "### Implemented By (1)") || !strings.Contains(result.Content, "`File` (struct) — file.go:3") {
+		t.Errorf("expected File through the embedding ReadCloser, got:\n%s", result.Content)
+	}
+
+	notIface, err := HandleCodeTool(ctx, repo, CodeToolParams{Action: CodeActionImpls, Query: "File"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(notIface.Content, "not an interface") {
+		t.Errorf("expected a non-interface message, got %q", notIface.Content)
+	}
+}
+
+func TestFormatExplainResult_Verbosity(t *testing.T) {
+	result := &app.ExplainResult{Symbol: app.SymbolResponse{Name: "Open", Kind: "function", Location: "db/open.go:3"}}
+	for i := 1; i <= 8; i++ {
+		result.Callers = append(result.Callers, app.CallNode{
+			Symbol: app.SymbolResponse{Name: fmt.Sprintf("Caller%d", i), Location: fmt.Sprintf("api/c%d.go:1", i)},
+		})
+	}
+
+	compact := FormatExplainResult(result, false)
+	if !strings.Contains(compact, "`Caller5`") || strings.Contains(compact, "`Caller6`") {
+		t.Errorf("compact mode should list exactly 5 callers:\n%s", compact)
+	}
+	if !strings.Contains(compact, "...and 3 more") {
+		t.Errorf("compact mode should note hidden callers:\n%s", compact)
+	}
+
+	verbose := FormatExplainResult(result, true)
+	for i := 1; i <= 8; i++ {
+		if !strings.Contains(verbose, fmt.Sprintf("`Caller%d`", i)) {
+			t.Errorf("verbose mode missing Caller%d:\n%s", i, verbose)
+		}
+	}
+	if strings.Contains(verbose, "more") {
+		t.Errorf("verbose mode should not truncate:\n%s", verbose)
+	}
+}
+
+func TestFormatTask_ValidationStepKinds(t *testing.T) {
+	// Steps stored before typing are plain strings and load as commands
+	var steps []task.ValidationStep
+	if err := json.Unmarshal([]byte(`["go test ./...", {"kind": "manual", "text": "Verify the UI renders"}]`), &steps); err != nil {
+		t.Fatalf("unmarshal steps: %v", err)
+	}
+	if len(steps) != 2 || steps[0].Kind != task.ValidationCommand || !steps[1].IsManual() {
+		t.Fatalf("steps = %+v, want one command and one manual step", steps)
+	}
+
+	out := FormatTask(&app.TaskResult{Task: &task.Task{ID: "task-1", Title: "Settings page", ValidationSteps: steps}})
+	if !strings.Contains(out, "```bash\ngo test ./...\n```") {
+		t.Errorf("command step should render in a bash block:\n%s", out)
+	}
+	if !strings.Contains(out, "- [ ] Verify the UI renders") {
+		t.Errorf("manual step should render as a checklist item:\n%s", out)
+	}
+	if strings.Contains(out, "```bash\ngo test ./...\nVerify") {
+		t.Errorf("manual step should not render as a command:\n%s", out)
+	}
+
+	// Round trip keeps commands as plain strings
+	data, err := json.Marshal(steps)
+	if err != nil {
+		t.Fatalf("marshal steps: %v", err)
+	}
+	if want := `["go test ./...",{"kind":"manual","text":"Verify the UI renders"}]`; string(data) != want {
+		t.Errorf("marshal = %s, want %s", data, want)
+	}
+}
+
+func TestSetPresenterConfig_KnowledgePreviewLength(t *testing.T) {
+	t.Cleanup(func() { SetPresenterConfig(config.DefaultPresenterConfig()) })
+
+	content := strings.Repeat("x", 500)
+	result := &app.AskResult{Results: []knowledge.NodeResponse{{ID: "n-1", Type: "decision", Summary: "Use SQLite", Content: content}}}
+	previewLen := func() int {
+		for _, line := range strings.Split(FormatAsk(result), "\n") {
+			if preview, ok := strings.CutPrefix(line, "   x"); ok {
+				return len(strings.TrimSuffix(preview, "...")) + 1
+			}
+		}
+		t.Fatalf("no knowledge preview in output:\n%s", FormatAsk(result))
+		return 0
+	}
+
+	if got := previewLen(); got != 300 {
+		t.Errorf("default preview length = %d, want 300", got)
+	}
+
+	// Only the overridden limit changes; zero fields keep their default
+	SetPresenterConfig(config.PresenterConfig{KnowledgePreviewLength: 50})
+	if got := previewLen(); got != 50 {
+		t.Errorf("preview length = %d, want 50", got)
+	}
+	if presenterConfig.TitleLength != config.DefaultPresenterConfig().TitleLength {
+		t.Errorf("TitleLength = %d, want the default", presenterConfig.TitleLength)
+	}
+}
+
+func TestFormatAsk_GroupByFeature(t *testing.T) {
+	store, err := memory.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	repo := memory.NewRepository(store, nil)
+
+	nodes := []*memory.Node{
+		{ID: "f-auth", Type: memory.NodeTypeFeature, Summary: "Authentication", Content: "Login and sessions."},
+		{ID: "f-billing", Type: memory.NodeTypeFeature, Summary: "Billing", Content: "Invoices and payments."},
+		{ID: "n-tokens", Type: memory.NodeTypeDecision, Summary: "Tokens cached in Redis", Content: "Session tokens are cached in Redis."},
+		{ID: "n-invoices", Type: memory.NodeTypePattern, Summary: "Invoices cached per month", Content: "Invoice totals are cached per month."},
+		{ID: "n-loose", Type: memory.NodeTypeConstraint, Summary: "Cached entries expire", Content: "No cached entry may live forever."},
+	}
+	// Unrelated nodes keep "cached" rare enough for BM25 to score it
+	for i, topic := range []string{"Logging uses slog", "Config loaded from YAML", "Migrations run at startup", "CLI built on cobra", "Tests use temp dirs", "Plans are stored as DAGs", "Errors wrapped with context", "Hooks run on session start", "Embeddings are optional", "Metrics exported to Prometheus", "Docs live in the repo"} {
+		nodes = append(nodes, &memory.Node{ID: fmt.Sprintf("n-filler-%d", i), Type: memory.NodeTypePattern, Summary: topic, Content: topic})
+	}
+	for _, n := range nodes {
+		if err := repo.CreateNode(n); err != nil {
+			t.Fatalf("CreateNode: %v", err)
+		}
+	}
+	for _, e := range [][2]string{{"n-tokens", "f-auth"}, {"f-billing", "n-invoices"}} {
+		if err := repo.LinkNodes(e[0], e[1], "relates_to", 1, nil); err != nil {
+			t.Fatalf("LinkNodes: %v", err)
+		}
+	}
+
+	opts := app.DefaultAskOptions()
+	opts.IncludeSymbols = false
+	opts.NoRewrite = true
+	opts.DisableVector = true
+	opts.DisableRerank = true
+	opts.GroupByFeature = true
+	result, err := app.NewAskApp(&app.Context{Repo: repo}).Query(context.Background(), "cached", opts)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	out := FormatAsk(result)
+
+	// Each node sits under its feature's heading, before the next heading
+	section := func(heading string) string {
+		i := strings.Index(out, heading)
+		if i < 0 {
+			t.Fatalf("missing heading %q:\n%s", heading, out)
+		}
+		rest := out[i+len(heading):]
+		if j := strings.Index(rest, "### "); j >= 0 {
+			rest = rest[:j]
+		}
+		return rest
+	}
+	if s := section("### ▸ Authentication"); !strings.Contains(s, "Tokens cached in Redis") {
+		t.Errorf("Authentication group missing its decision:\n%s", out)
+	}
+	if s := section("### ▸ Billing"); !strings.Contains(s, "Invoices cached per month") {
+		t.Errorf("Billing group missing its pattern:\n%s", out)
+	}
+	if s := section("### ▸ Other"); !strings.Contains(s, "Cached entries expire") {
+		t.Errorf("unlinked node should be under Other:\n%s", out)
+	}
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnly is returned for tool calls that would change project state on a
+// read-only server.
+var ErrReadOnly = errors.New("disabled: the MCP server is running in read-only mode")
+
+// ServerOptions configures the MCP server.
+type ServerOptions struct {
+	// ReadOnly rejects tool calls that change project state (remember, task
+	// lifecycle changes, plan creation and audits). ask, code, debug, policy
+	// and the list actions keep working.
+	ReadOnly bool
+}
+
+// Check returns an error wrapping ErrReadOnly when the server is read-only and
+// the call identified by name mutates project state.
+func (o ServerOptions) Check(name string, mutates bool) error {
+	if o.ReadOnly && mutates {
+		return fmt.Errorf("%s: %w", name, ErrReadOnly)
+	}
+	return nil
+}
+
+// Mutates reports whether the task tool call changes project state.
+func (p TaskToolParams) Mutates() bool {
+	switch p.Action {
+	case TaskActionStart, TaskActionComplete, TaskActionSkip, TaskActionReorder, TaskActionSplit:
+		return true
+	case TaskActionNext:
+		return p.AutoStart
+	}
+	return false
+}
+
+// Mutates reports whether the plan tool call changes project state. Dry-run
+// generation saves nothing.
+func (p PlanToolParams) Mutates() bool {
+	switch p.Action {
+	case PlanActionList:
+		return false
+	case PlanActionGenerate:
+		return !p.DryRun
+	}
+	// Unknown actions are left to the handler to reject
+	return p.Action.IsValid()
+}
@@ -14,23 +14,30 @@ import (
 type CodeAction string
 
 const (
-	CodeActionFind     CodeAction = "find"
-	CodeActionSearch   CodeAction = "search"
-	CodeActionExplain  CodeAction = "explain"
-	CodeActionCallers  CodeAction = "callers"
-	CodeActionImpact   CodeAction = "impact"
-	CodeActionSimplify CodeAction = "simplify"
+	CodeActionFind        CodeAction = "find"
+	CodeActionSearch      CodeAction = "search"
+	CodeActionExplain     CodeAction = "explain"
+	CodeActionExplainFile CodeAction = "explain_file"
+	CodeActionCallers     CodeAction = "callers"
+	CodeActionImpact      CodeAction = "impact"
+	CodeActionSimplify    CodeAction = "simplify"
+	CodeActionChanged     CodeAction = "changed"
+	CodeActionSearchSig   CodeAction = "search_sig"
+	CodeActionImports     CodeAction = "imports"
+	CodeActionTree        CodeAction = "tree"
+	CodeActionUsages      CodeAction = "usages"
+	CodeActionImpls       CodeAction = "implementations"
 )
 
 // ValidCodeActions returns all valid code actions.
 func ValidCodeActions() []CodeAction {
-	return []CodeAction{CodeActionFind, CodeActionSearch, CodeActionExplain, CodeActionCallers, CodeActionImpact, CodeActionSimplify}
+	return []CodeAction{CodeActionFind, CodeActionSearch, CodeActionExplain, CodeActionExplainFile, CodeActionCallers, CodeActionImpact, CodeActionSimplify, CodeActionChanged, CodeActionSearchSig, CodeActionImports, CodeActionTree, CodeActionUsages, CodeActionImpls}
 }
 
 // IsValid checks if the action is a valid code action.
 func (a CodeAction) IsValid() bool {
 	switch a {
-	case CodeActionFind, CodeActionSearch, CodeActionExplain, CodeActionCallers, CodeActionImpact, CodeActionSimplify:
+	case CodeActionFind, CodeActionSearch, CodeActionExplain, CodeActionExplainFile, CodeActionCallers, CodeActionImpact, CodeActionSimplify, CodeActionChanged, CodeActionSearchSig, CodeActionImports, CodeActionTree, CodeActionUsages, CodeActionImpls:
 		return true
 	}
 	return false
@@ -45,17 +52,21 @@ const (
 	TaskActionStart    TaskAction = "start"
 	TaskActionComplete TaskAction = "complete"
 	TaskActionSkip     TaskAction = "skip"
+	TaskActionReorder  TaskAction = "reorder"
+	TaskActionList     TaskAction = "list"
+	TaskActionDeps     TaskAction = "dependencies"
+	TaskActionSplit    TaskAction = "split"
 )
 
 // ValidTaskActions returns all valid task actions.
 func ValidTaskActions() []TaskAction {
-	return []TaskAction{TaskActionNext, TaskActionCurrent, TaskActionStart, TaskActionComplete, TaskActionSkip}
+	return []TaskAction{TaskActionNext, TaskActionCurrent, TaskActionStart, TaskActionComplete, TaskActionSkip, TaskActionReorder, TaskActionList, TaskActionDeps, TaskActionSplit}
 }
 
 // IsValid checks if the action is a valid task action.
 func (a TaskAction) IsValid() bool {
 	switch a {
-	case TaskActionNext, TaskActionCurrent, TaskActionStart, TaskActionComplete, TaskActionSkip:
+	case TaskActionNext, TaskActionCurrent, TaskActionStart, TaskActionComplete, TaskActionSkip, TaskActionReorder, TaskActionList, TaskActionDeps, TaskActionSplit:
 		return true
 	}
 	return false
@@ -71,17 +82,41 @@ const (
 	PlanActionGenerate  PlanAction = "generate"  // Generate all tasks at once (batch mode)
 	PlanActionFinalize  PlanAction = "finalize"  // Save completed interactive plan (Stage 4)
 	PlanActionAudit     PlanAction = "audit"     // Verify plan implementation
+	PlanActionMerge     PlanAction = "merge"     // Move another plan's phases and tasks into this one
+	PlanActionList      PlanAction = "list"      // List plans with status filter and pagination
 )
 
 // ValidPlanActions returns all valid plan actions.
 func ValidPlanActions() []PlanAction {
-	return []PlanAction{PlanActionClarify, PlanActionDecompose, PlanActionExpand, PlanActionGenerate, PlanActionFinalize, PlanActionAudit}
+	return []PlanAction{PlanActionClarify, PlanActionDecompose, PlanActionExpand, PlanActionGenerate, PlanActionFinalize, PlanActionAudit, PlanActionMerge, PlanActionList}
 }
 
 // IsValid checks if the action is a valid plan action.
 func (a PlanAction) IsValid() bool {
 	switch a {
-	case PlanActionClarify, PlanActionDecompose, PlanActionExpand, PlanActionGenerate, PlanActionFinalize, PlanActionAudit:
+	case PlanActionClarify, PlanActionDecompose, PlanActionExpand, PlanActionGenerate, PlanActionFinalize, PlanActionAudit, PlanActionMerge, PlanActionList:
+		return true
+	}
+	return false
+}
+
+// PolicyAction defines the valid actions for the policy tool.
+type PolicyAction string
+
+const (
+	PolicyActionList PolicyAction = "list" // List loaded policies and their source
+	PolicyActionWhy  PolicyAction = "why"  // Explain which deny rules block a file
+)
+
+// ValidPolicyActions returns all valid policy actions.
+func ValidPolicyActions() []PolicyAction {
+	return []PolicyAction{PolicyActionList, PolicyActionWhy}
+}
+
+// IsValid checks if the action is a valid policy action.
+func (a PolicyAction) IsValid() bool {
+	switch a {
+	case PolicyActionList, PolicyActionWhy:
 		return true
 	}
 	return false
@@ -93,20 +128,20 @@ func (a PlanAction) IsValid() bool {
 // Consolidates: find_symbol, semantic_search_code, explain_symbol, get_callers, analyze_impact, simplify
 type CodeToolParams struct {
 	// Action specifies which operation to perform.
-	// Required. One of: find, search, explain, callers, impact, simplify
+	// Required. One of: find, search, explain, explain_file, callers, impact, simplify, changed, search_sig, imports, tree, usages, implementations
 	Action CodeAction `json:"action"`
 
 	// Query is the symbol name or search query.
-	// Required for: search, explain (if symbol_id not provided)
-	// Optional for: find (alternative to symbol_id), callers, impact
+	// Required for: search, search_sig (comma-separated types, e.g. "context.Context, string"), explain (if symbol_id not provided)
+	// Optional for: find (alternative to symbol_id), callers, usages, implementations (interface name), impact, simplify (symbol being simplified)
 	Query string `json:"query,omitempty"`
 
 	// SymbolID is the direct symbol ID for precise lookups.
-	// Optional. If provided, takes precedence over query for find/explain/callers/impact.
+	// Optional. If provided, takes precedence over query for find/explain/callers/usages/implementations/impact/simplify.
 	SymbolID uint32 `json:"symbol_id,omitempty"`
 
 	// FilePath filters results to a specific file or directory.
-	// Required for: simplify (specifies file to simplify)
+	// Required for: simplify (specifies file to simplify), explain_file (file to summarize), imports, tree (directory to list)
 	// Optional for: find, search
 	FilePath string `json:"file_path,omitempty"`
 
@@ -115,7 +150,7 @@ type CodeToolParams struct {
 	Code string `json:"code,omitempty"`
 
 	// Language filters results by programming language (e.g., "go", "typescript").
-	// Optional for: find
+	// Optional for: find, search
 	Language string `json:"language,omitempty"`
 
 	// Kind filters by symbol kind (function, struct, interface, etc.).
@@ -123,7 +158,7 @@ type CodeToolParams struct {
 	Kind string `json:"kind,omitempty"`
 
 	// Limit is the maximum number of results to return.
-	// Optional for: search (default: 20)
+	// Optional for: search, search_sig (default: 20)
 	Limit int `json:"limit,omitempty"`
 
 	// Direction specifies call graph direction for callers action.
@@ -131,13 +166,42 @@ type CodeToolParams struct {
 	// Optional for: callers
 	Direction string `json:"direction,omitempty"`
 
-	// MaxDepth is the maximum recursion depth for impact analysis.
-	// Optional for: impact (default: 5)
+	// MaxDepth is the maximum recursion depth for impact analysis, or the
+	// embedding hops followed by implementations.
+	// Optional for: impact (default: 5), implementations (default and cap: 3)
 	MaxDepth int `json:"max_depth,omitempty"`
 
+	// ExcludeVendored skips vendored, third-party, and generated code in impact analysis.
+	// Optional for: impact (default: false)
+	ExcludeVendored bool `json:"exclude_vendored,omitempty"`
+
+	// IncludePrivate keeps unexported symbols in search results; false limits results to the public API surface.
+	// Optional for: search, search_sig (default: true)
+	IncludePrivate *bool `json:"include_private,omitempty"`
+
+	// MinScore drops search results whose combined relevance score (0-1) is below it.
+	// Optional for: search (default: 0.1)
+	MinScore float32 `json:"min_score,omitempty"`
+
+	// Debug shows why each search result matched (FTS, vector, or both) with its component scores.
+	// Optional for: search (default: false)
+	Debug bool `json:"debug,omitempty"`
+
+	// Verbose renders full caller/callee/impact lists and untruncated source snippets.
+	// Optional for: explain, callers, impact (default: false, compact output)
+	Verbose bool `json:"verbose,omitempty"`
+
 	// Depth is the call graph depth for explain action.
 	// Optional for: explain (default: 2, range: 1-5)
 	Depth int `json:"depth,omitempty"`
+
+	// Base is the git ref to diff from.
+	// Optional for: changed (default: main)
+	Base string `json:"base,omitempty"`
+
+	// Head is the git ref to diff to.
+	// Optional for: changed (default: HEAD)
+	Head string `json:"head,omitempty"`
 }
 
 // TaskToolParams defines the parameters for the unified task tool.
@@ -154,13 +218,21 @@ type TaskToolParams struct {
 	Action TaskAction `json:"action"`
 
 	// TaskID is the task identifier.
-	// REQUIRED for: start, complete (will error if empty for these actions)
+	// REQUIRED for: start, complete, dependencies, split (will error if empty for these actions)
 	TaskID string `json:"task_id,omitempty"`
 
 	// PlanID is the plan identifier.
-	// Optional for: next, current (defaults to active plan)
+	// Optional for: next, current, reorder, list (defaults to active plan)
 	PlanID string `json:"plan_id,omitempty"`
 
+	// TaskIDs lists every task of the plan in the desired order, most urgent first.
+	// REQUIRED for: reorder
+	TaskIDs []string `json:"task_ids,omitempty"`
+
+	// Subtasks lists the titles of the tasks to split task_id into.
+	// REQUIRED for: split (at least two)
+	Subtasks []string `json:"subtasks,omitempty"`
+
 	// SessionID is the unique AI session identifier.
 	// Optional for: next, current, start when MCP transport session identity is available.
 	// REQUIRED otherwise.
@@ -174,6 +246,10 @@ type TaskToolParams struct {
 	// Optional for: complete
 	FilesModified []string `json:"files_modified,omitempty"`
 
+	// VerifyCriteria checks each acceptance criterion against the modified files and records pass/unknown on the task.
+	// Optional for: complete (default: false)
+	VerifyCriteria bool `json:"verify_criteria,omitempty"`
+
 	// AutoStart automatically claims the next task.
 	// Optional for: next (default: false)
 	AutoStart bool `json:"auto_start,omitempty"`
@@ -185,6 +261,30 @@ type TaskToolParams struct {
 	// SkipUnpushedCheck proceeds despite unpushed commits.
 	// Optional for: next (only if create_branch=true)
 	SkipUnpushedCheck bool `json:"skip_unpushed_check,omitempty"`
+
+	// Agent restricts next to tasks assigned to this agent ("unassigned" for none).
+	// Optional for: next
+	Agent string `json:"agent,omitempty"`
+
+	// Status filters tasks by status (pending, in_progress, completed, ...).
+	// Optional for: list
+	Status string `json:"status,omitempty"`
+
+	// Phase filters tasks by phase ID, title, or 1-based position.
+	// Optional for: list
+	Phase string `json:"phase,omitempty"`
+
+	// Sort orders the list: priority (default) or created.
+	// Optional for: list
+	Sort string `json:"sort,omitempty"`
+
+	// Limit is the maximum number of tasks to return.
+	// Optional for: list (default: all)
+	Limit int `json:"limit,omitempty"`
+
+	// Format selects the response format: markdown (default) or json.
+	// Optional for: list
+	Format string `json:"format,omitempty"`
 }
 
 type taskToolParamsAlias TaskToolParams
@@ -212,21 +312,29 @@ func (p *TaskToolParams) UnmarshalJSON(data []byte) error {
 // ProjectContextParams defines the parameters for the ask tool.
 type ProjectContextParams struct {
 	Query     string `json:"query,omitempty"`
-	Answer    bool   `json:"answer,omitempty"`    // If true, generate RAG answer using LLM
-	Workspace string `json:"workspace,omitempty"` // Filter by workspace (e.g., 'osprey'). Empty = all workspaces.
-	All       bool   `json:"all,omitempty"`       // Explicitly search all workspaces (ignore auto-detection)
-	Detail    string `json:"detail,omitempty"`    // "summary" (default) or "full"
-	Page      int    `json:"page,omitempty"`      // 1-indexed page number for full detail (default 1)
-	PageSize  int    `json:"page_size,omitempty"` // nodes per page for full detail (default 50)
+	Answer    bool   `json:"answer,omitempty"`     // If true, generate RAG answer using LLM
+	Workspace string `json:"workspace,omitempty"`  // Filter by workspace (e.g., 'osprey'). Empty = all workspaces.
+	All       bool   `json:"all,omitempty"`        // Explicitly search all workspaces (ignore auto-detection)
+	Detail    string `json:"detail,omitempty"`     // "summary" (default) or "full"
+	Page      int    `json:"page,omitempty"`       // 1-indexed page number for full detail (default 1)
+	PageSize  int    `json:"page_size,omitempty"`  // nodes per page for full detail (default 50)
+	Related   bool   `json:"related,omitempty"`    // Also return nodes one graph edge away from each match
+	Relation  string `json:"relation,omitempty"`   // Only follow edges of this type with related=true (e.g. depends_on)
+	ByFeature bool   `json:"by_feature,omitempty"` // Group matches under the feature each is linked to
+	Debug     bool   `json:"debug,omitempty"`      // Show why each match was recalled (FTS, vector, or both) with its component scores
 }
 
 // RememberParams defines the parameters for the remember tool.
 type RememberParams struct {
-	Content string `json:"content"`          // Required: knowledge to store
-	Type    string `json:"type,omitempty"`   // Optional: decision, feature, plan, note
-	Global  bool   `json:"global,omitempty"` // Store in global knowledge (~/.taskwing/knowledge/) instead of project
+	Content string   `json:"content"`          // Required: knowledge to store
+	Type    string   `json:"type,omitempty"`   // Optional: decision, feature, plan, note
+	Global  bool     `json:"global,omitempty"` // Store in global knowledge (~/.taskwing/knowledge/) instead of project
+	Tags    []string `json:"tags,omitempty"`   // Optional: grouping labels (e.g. security, perf)
 }
 
+// InfoToolParams defines the parameters for the info tool, which takes none.
+type InfoToolParams struct{}
+
 // DebugToolParams defines the parameters for the debug tool.
 type DebugToolParams struct {
 	// Problem is the description of the issue.
@@ -246,6 +354,17 @@ type DebugToolParams struct {
 	FilePath string `json:"file_path,omitempty"`
 }
 
+// PolicyToolParams defines the parameters for the policy tool.
+type PolicyToolParams struct {
+	// Action specifies which operation to perform.
+	// Required. One of: list, why
+	Action PolicyAction `json:"action"`
+
+	// File is the project-relative path to check against policies.
+	// REQUIRED for: why
+	File string `json:"file,omitempty"`
+}
+
 // PhaseInput represents user-provided phase data for interactive mode.
 type PhaseInput struct {
 	Title         string `json:"title"`
@@ -265,7 +384,7 @@ type ClarifyAnswerInput struct {
 }
 
 // PlanToolParams defines the parameters for the unified plan tool.
-// Supports planning actions: clarify, decompose, expand, generate, finalize, audit
+// Supports planning actions: clarify, decompose, expand, generate, finalize, audit, merge, list
 //
 // Required fields by action:
 //   - clarify first call: goal
@@ -275,9 +394,10 @@ type ClarifyAnswerInput struct {
 //   - generate: goal, enriched_goal, clarify_session_id
 //   - finalize: plan_id
 //   - audit: none (defaults to active plan)
+//   - list: none
 type PlanToolParams struct {
 	// Action specifies which operation to perform.
-	// Required. One of: clarify, decompose, expand, generate, finalize, audit
+	// Required. One of: clarify, decompose, expand, generate, finalize, audit, merge, list
 	Action PlanAction `json:"action"`
 
 	// Goal is the user's development goal.
@@ -297,7 +417,8 @@ type PlanToolParams struct {
 	// REQUIRED for: clarify follow-up calls (unless auto_answer=true)
 	Answers []ClarifyAnswerInput `json:"answers,omitempty"`
 
-	// AutoAnswer uses knowledge graph to auto-answer clarifying questions.
+	// AutoAnswer uses the knowledge graph and the team conventions file
+	// (.taskwing/conventions.md) to auto-answer clarifying questions.
 	// Optional for: clarify (default: false)
 	AutoAnswer bool `json:"auto_answer,omitempty"`
 
@@ -305,15 +426,54 @@ type PlanToolParams struct {
 	// Optional for: generate (default: true)
 	Save *bool `json:"save,omitempty"`
 
+	// DryRun previews the generated plan without saving it or changing the active plan.
+	// Optional for: generate (default: false)
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Stream saves tasks as they are generated instead of after the whole plan.
+	// On failure, tasks saved so far remain in a draft plan.
+	// Optional for: generate (default: false)
+	Stream bool `json:"stream,omitempty"`
+
+	// NormalizePriorities remaps task priorities to an evenly spaced order that
+	// ranks every task after its dependencies (also enabled by planning.normalize_priorities).
+	// Optional for: generate (default: false)
+	NormalizePriorities bool `json:"normalize_priorities,omitempty"`
+
+	// TestFirst precedes each implementation task with a failing-test task it
+	// depends on (also enabled by planning.test_first).
+	// Optional for: generate
+	TestFirst bool `json:"test_first,omitempty"`
+
+	// MergeDuplicates removes near-duplicate generated tasks, folding their
+	// criteria and dependencies into the task they repeat (also enabled by
+	// planning.merge_duplicate_tasks). Duplicates are always reported as warnings.
+	// Optional for: generate (default: false)
+	MergeDuplicates bool `json:"merge_duplicates,omitempty"`
+
 	// PlanID is the plan to operate on.
-	// REQUIRED for: expand, finalize
+	// REQUIRED for: expand, finalize, merge (the plan that receives the tasks)
 	// Optional for: decompose (creates new plan if not provided), audit (defaults to active plan)
 	PlanID string `json:"plan_id,omitempty"`
 
+	// SourcePlanID is the plan whose phases and tasks are moved; it is deleted afterwards.
+	// REQUIRED for: merge
+	SourcePlanID string `json:"source_plan_id,omitempty"`
+
 	// AutoFix attempts to automatically fix failures.
 	// Optional for: audit (default: true)
 	AutoFix *bool `json:"auto_fix,omitempty"`
 
+	// Force re-runs the audit even if tracked files are unchanged since the
+	// last successful audit.
+	// Optional for: audit (default: false)
+	Force bool `json:"force,omitempty"`
+
+	// JUnit returns the audit report as JUnit XML for CI systems instead of
+	// Markdown.
+	// Optional for: audit (default: false)
+	JUnit bool `json:"junit,omitempty"`
+
 	// === Interactive Mode Fields ===
 
 	// Mode specifies the generation mode.
@@ -322,6 +482,7 @@ type PlanToolParams struct {
 
 	// PhaseID is the ID of the phase to expand.
 	// REQUIRED for: expand (if phase_index not provided)
+	// Optional for: generate (adds the tasks to this phase of an existing plan instead of creating a plan)
 	PhaseID string `json:"phase_id,omitempty"`
 
 	// PhaseIndex is the 0-based index of the phase to expand.
@@ -341,8 +502,31 @@ type PlanToolParams struct {
 	Tasks []TaskInput `json:"tasks,omitempty"`
 
 	// Feedback is a regeneration hint when user wants changes.
-	// Optional for: decompose, expand (e.g., "split phase 2 into smaller chunks")
+	// Optional for: decompose, expand (e.g., "split phase 2 into smaller chunks").
+	// On expand, feedback regenerates an already expanded phase.
 	Feedback string `json:"feedback,omitempty"`
+
+	// === List Fields ===
+
+	// Status filters plans by status (draft, active, completed, verified, needs_revision, archived).
+	// Optional for: list
+	Status string `json:"status,omitempty"`
+
+	// IncludeArchived includes archived plans when no status filter is given.
+	// Optional for: list (default: false)
+	IncludeArchived bool `json:"include_archived,omitempty"`
+
+	// Sort orders plans newest first by created (default) or updated time.
+	// Optional for: list
+	Sort string `json:"sort,omitempty"`
+
+	// Limit is the page size.
+	// Optional for: list (default: no limit)
+	Limit int `json:"limit,omitempty"`
+
+	// Offset is the number of plans to skip before the page starts.
+	// Optional for: list (default: 0)
+	Offset int `json:"offset,omitempty"`
 }
 
 type planToolParamsAlias PlanToolParams
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Dialect describes how a database backend's SQL differs from the SQLite
+// syntax the task store is written in.
+type Dialect struct {
+	name string
+
+	// numberedParams rewrites ? placeholders to $1, $2, ...
+	numberedParams bool
+	// ignoreSuffix is appended to an INSERT to skip rows that already exist;
+	// empty means the INSERT OR IGNORE form is used.
+	ignoreSuffix string
+	// likeOp is the case-insensitive pattern match operator.
+	likeOp string
+	// noLimit is the LIMIT value that returns every row.
+	noLimit any
+}
+
+var (
+	// SQLiteDialect is the dialect of the default local store.
+	SQLiteDialect = Dialect{name: "sqlite", likeOp: "LIKE", noLimit: -1}
+
+	// PostgresDialect is the dialect of PostgresStore.
+	PostgresDialect = Dialect{
+		name:           "postgres",
+		numberedParams: true,
+		ignoreSuffix:   " ON CONFLICT DO NOTHING",
+		likeOp:         "ILIKE",
+		noLimit:        nil,
+	}
+)
+
+// Name returns the backend name, e.g. "sqlite".
+func (d Dialect) Name() string {
+	return d.name
+}
+
+// insertIgnore builds an INSERT that silently skips rows violating a unique
+// constraint. into is everything after INSERT INTO, e.g. "t (a) VALUES (?)".
+func (d Dialect) insertIgnore(into string) string {
+	if d.ignoreSuffix == "" {
+		return "INSERT OR IGNORE INTO " + into
+	}
+	return "INSERT INTO " + strings.TrimSpace(into) + d.ignoreSuffix
+}
+
+// rebind rewrites the ? placeholders in query for the dialect. Question marks
+// inside quoted literals are left alone.
+func (d Dialect) rebind(query string) string {
+	if !d.numberedParams || !strings.Contains(query, "?") {
+		return query
+	}
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	var quote rune
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// sqlDB runs queries written in SQLite syntax against a database of any
+// dialect.
+type sqlDB struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+func (db *sqlDB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.db.Exec(db.dialect.rebind(query), args...)
+}
+
+func (db *sqlDB) Query(query string, args ...any) (*sql.Rows, error) {
+	return db.db.Query(db.dialect.rebind(query), args...)
+}
+
+func (db *sqlDB) QueryRow(query string, args ...any) *sql.Row {
+	return db.db.QueryRow(db.dialect.rebind(query), args...)
+}
+
+func (db *sqlDB) Begin() (*sqlTx, error) {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{Tx: tx, dialect: db.dialect}, nil
+}
+
+// sqlTx is the transaction counterpart of sqlDB.
+type sqlTx struct {
+	*sql.Tx
+	dialect Dialect
+}
+
+func (tx *sqlTx) Exec(query string, args ...any) (sql.Result, error) {
+	return tx.Tx.Exec(tx.dialect.rebind(query), args...)
+}
+
+func (tx *sqlTx) Query(query string, args ...any) (*sql.Rows, error) {
+	return tx.Tx.Query(tx.dialect.rebind(query), args...)
+}
+
+func (tx *sqlTx) QueryRow(query string, args ...any) *sql.Row {
+	return tx.Tx.QueryRow(tx.dialect.rebind(query), args...)
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+func TestSQLiteStore_CheckFlagsOrphanedPhase(t *testing.T) {
+	store, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	plan := &task.Plan{Goal: "Ship it", Status: task.PlanStatusActive}
+	if err := store.CreatePlan(plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+
+	issues, err := store.Check()
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected clean store, got %+v", issues)
+	}
+
+	// Seed an orphaned phase (bypassing the foreign key, as legacy databases can)
+	if _, err := store.db.Exec("PRAGMA foreign_keys = OFF"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.Exec(`INSERT INTO phases (id, plan_id, title, created_at, updated_at)
+		VALUES ('phase-orphan', 'plan-gone', 'Lost phase', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`); err != nil {
+		t.Fatalf("seed phase: %v", err)
+	}
+	if _, err := store.db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err = store.Check()
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Type != "orphan_phase" {
+		t.Fatalf("expected one orphan_phase issue, got %+v", issues)
+	}
+	if issues[0].Hint == "" {
+		t.Error("issue should include a fix suggestion")
+	}
+
+	if err := store.Repair(); err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	issues, err = store.Check()
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues after repair, got %+v", issues)
+	}
+	if p, err := store.GetPlan(plan.ID); err != nil || p == nil {
+		t.Errorf("repair must keep valid plans: %v", err)
+	}
+}
@@ -170,6 +170,112 @@ func (s *MarkdownStore) GenerateArchitectureMD(data ArchitectureData, projectNam
 	return os.WriteFile(archPath, []byte(sb.String()), 0644)
 }
 
+// Feature pages are bounded so a heavily linked feature stays readable.
+const (
+	featureDocMaxEvidence = 50
+	featureDocMaxRelated  = 25
+)
+
+// FeatureDoc holds the knowledge rendered into a single feature page.
+type FeatureDoc struct {
+	Feature  Node
+	Related  []FeatureDocLink
+	Evidence []FeatureDocEvidence
+}
+
+// FeatureDocLink is a node connected to the feature in the knowledge graph.
+type FeatureDocLink struct {
+	Relation string
+	Type     string
+	Summary  string
+}
+
+// FeatureDocEvidence is a file reference backing the feature.
+// Only the location is kept: snippets and symbol data are never written to
+// markdown, so pages cannot leak code that the index holds.
+type FeatureDocEvidence struct {
+	FilePath  string
+	StartLine int
+	EndLine   int
+}
+
+// Location returns "path", "path:start", or "path:start-end".
+func (e FeatureDocEvidence) Location() string {
+	switch {
+	case e.StartLine <= 0:
+		return e.FilePath
+	case e.EndLine <= e.StartLine:
+		return fmt.Sprintf("%s:%d", e.FilePath, e.StartLine)
+	default:
+		return fmt.Sprintf("%s:%d-%d", e.FilePath, e.StartLine, e.EndLine)
+	}
+}
+
+// WriteFeatureDoc writes the page for one feature to features/<id>.md,
+// replacing any previous version. Returns the path written.
+func (s *MarkdownStore) WriteFeatureDoc(doc FeatureDoc) (string, error) {
+	f := doc.Feature
+	var sb strings.Builder
+
+	title := f.Summary
+	if title == "" {
+		title = f.ID
+	}
+	sb.WriteString(fmt.Sprintf("# %s\n\n", title))
+	sb.WriteString(fmt.Sprintf("> Auto-generated by TaskWing on %s from node `%s`\n", time.Now().Format("2006-01-02 15:04"), f.ID))
+	sb.WriteString("> **Do not edit manually** — changes will be overwritten on next generation.\n\n")
+
+	if sc := f.ParseStructuredContent(); sc != nil {
+		if sc.Description != "" {
+			sb.WriteString(sc.Description + "\n\n")
+		}
+		if sc.Why != "" {
+			sb.WriteString(fmt.Sprintf("**Why:** %s\n\n", sc.Why))
+		}
+		if sc.Tradeoffs != "" {
+			sb.WriteString(fmt.Sprintf("**Tradeoffs:** %s\n\n", sc.Tradeoffs))
+		}
+	} else if content := strings.TrimSpace(strings.TrimPrefix(f.Content, f.Summary)); content != "" {
+		sb.WriteString(content + "\n\n")
+	}
+
+	sb.WriteString("## Evidence\n\n")
+	if len(doc.Evidence) == 0 {
+		sb.WriteString("_No evidence recorded._\n\n")
+	} else {
+		for i, ev := range doc.Evidence {
+			if i == featureDocMaxEvidence {
+				sb.WriteString(fmt.Sprintf("- ...and %d more\n", len(doc.Evidence)-i))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("- `%s`\n", ev.Location()))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(doc.Related) > 0 {
+		sb.WriteString("## Related\n\n")
+		for i, rel := range doc.Related {
+			if i == featureDocMaxRelated {
+				sb.WriteString(fmt.Sprintf("- ...and %d more\n", len(doc.Related)-i))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("- %s: **%s** (%s)\n", rel.Relation, rel.Summary, rel.Type))
+		}
+		sb.WriteString("\n")
+	}
+
+	dir := filepath.Join(s.basePath, "features")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create features dir: %w", err)
+	}
+	path := filepath.Join(dir, f.ID+".md")
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return "", fmt.Errorf("write feature doc: %w", err)
+	}
+	return path, nil
+}
+
 // toTitleCase capitalizes the first letter of each word in the string.
 // This is a simple replacement for the deprecated strings.Title.
 func toTitleCase(s string) string {
@@ -9,8 +9,9 @@ import (
 
 // Issue represents a problem found during integrity checks.
 type Issue struct {
-	Type    string `json:"type"`    // missing_file, orphan_edge, index_mismatch
-	Message string `json:"message"` // Human-readable description
+	Type    string `json:"type"`           // orphan_phase, orphan_task, multiple_active_plans, index_mismatch
+	Message string `json:"message"`        // Human-readable description
+	Hint    string `json:"hint,omitempty"` // Suggested fix
 }
 
 // Node represents a piece of knowledge in the graph.
@@ -24,6 +25,7 @@ type Node struct {
 	Workspace   string    `json:"workspace,omitempty"`   // Monorepo workspace/service name ('root' = global, e.g., 'osprey', 'studio')
 	Embedding   []float32 `json:"embedding,omitempty"`   // Vector for similarity search
 	CreatedAt   time.Time `json:"createdAt"`
+	Tags        []string  `json:"tags,omitempty"` // User-assigned grouping labels (e.g. security, perf)
 
 	// Evidence-Based Verification fields (v2.1+)
 	// These support the verification pipeline that validates agent findings
@@ -65,6 +67,9 @@ type Node struct {
 	// CompactSummary is an LLM-generated dense summary for context packing.
 	// Populated during bootstrap ingestion. Used by FormatCompact() instead of truncation.
 	CompactSummary string `json:"compactSummary,omitempty"`
+
+	// AccessCount is how many times recall has returned this node.
+	AccessCount int `json:"accessCount,omitempty"`
 }
 
 // DebtLevel returns human-readable debt classification for a node.
@@ -209,6 +214,7 @@ const (
 	NodeRelationExtends             = "extends"
 	NodeRelationSemanticallySimilar = "semantically_similar"
 	NodeRelationSharesEvidence      = "shares_evidence" // Nodes referencing same files
+	NodeRelationConflictsWith       = "conflicts_with"  // Constraints giving opposing guidance
 )
 
 // ProjectOverview represents the high-level description of a project.
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+// PostgresStore keeps plans, phases, tasks and clarify sessions in PostgreSQL
+// so a team can share them. Knowledge is not stored here; attach the store to
+// a Repository with SetTaskBackend.
+//
+// This is a skeleton: TaskWing links no PostgreSQL driver, so the caller opens
+// the *sql.DB with a driver of its choice (e.g. pgx's stdlib driver).
+type PostgresStore struct {
+	*taskStore
+	db *sql.DB
+}
+
+var _ task.Repository = (*PostgresStore)(nil)
+
+// NewPostgresStore creates the task schema in db if it is missing and adds
+// any columns an older schema lacks.
+func NewPostgresStore(db *sql.DB) (*PostgresStore, error) {
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+	if _, err := db.Exec(postgresTaskSchema); err != nil {
+		return nil, fmt.Errorf("init postgres schema: %w", err)
+	}
+	for _, m := range postgresTaskMigrations {
+		if _, err := db.Exec(m); err != nil {
+			return nil, fmt.Errorf("migrate postgres schema: %w", err)
+		}
+	}
+	return &PostgresStore{taskStore: newTaskStore(db, PostgresDialect), db: db}, nil
+}
+
+// Close closes the database connection.
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// postgresTaskSchema mirrors the SQLite task tables after all migrations.
+// Timestamps stay RFC3339 text and booleans stay integers so both backends
+// share the same queries and scan code. CREATE TABLE IF NOT EXISTS leaves
+// existing tables alone, so a column added here must also be added to
+// postgresTaskMigrations.
+const postgresTaskSchema = `
+	CREATE TABLE IF NOT EXISTS plans (
+		id TEXT PRIMARY KEY,
+		goal TEXT NOT NULL,
+		enriched_goal TEXT,
+		status TEXT DEFAULT 'draft',
+		draft_state TEXT,
+		generation_mode TEXT DEFAULT 'batch',
+		last_audit_report TEXT,
+		created_at TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS phases (
+		id TEXT PRIMARY KEY,
+		plan_id TEXT NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+		title TEXT NOT NULL,
+		description TEXT,
+		rationale TEXT,
+		order_index INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL DEFAULT 'pending',
+		expected_tasks INTEGER DEFAULT 0,
+		expand_attempts TEXT,
+		depends_on TEXT,
+		created_at TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_phases_plan_id ON phases(plan_id);
+	CREATE INDEX IF NOT EXISTS idx_phases_order ON phases(plan_id, order_index);
+
+	CREATE TABLE IF NOT EXISTS tasks (
+		id TEXT PRIMARY KEY,
+		plan_id TEXT NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+		phase_id TEXT REFERENCES phases(id) ON DELETE SET NULL,
+		title TEXT NOT NULL,
+		description TEXT,
+		acceptance_criteria TEXT,
+		validation_steps TEXT,
+		status TEXT DEFAULT 'pending',
+		priority INTEGER DEFAULT 50,
+		complexity TEXT DEFAULT 'medium',
+		assigned_agent TEXT,
+		parent_task_id TEXT REFERENCES tasks(id) ON DELETE SET NULL,
+		context_summary TEXT,
+		scope TEXT,
+		keywords TEXT,
+		suggested_ask_queries TEXT,
+		claimed_by TEXT,
+		claimed_at TEXT,
+		completed_at TEXT,
+		completion_summary TEXT,
+		files_modified TEXT,
+		block_reason TEXT,
+		expected_files TEXT,
+		git_baseline TEXT,
+		enrichment_errors INTEGER DEFAULT 0,
+		criteria_verification TEXT,
+		skip_audit INTEGER DEFAULT 0,
+		sort_order INTEGER DEFAULT 0,
+		created_at TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_tasks_plan_id ON tasks(plan_id);
+
+	CREATE TABLE IF NOT EXISTS task_dependencies (
+		task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		depends_on TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		created_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP::text),
+		PRIMARY KEY (task_id, depends_on)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_node_links (
+		task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		node_id TEXT NOT NULL,
+		link_type TEXT NOT NULL,
+		created_at TEXT NOT NULL DEFAULT (CURRENT_TIMESTAMP::text),
+		PRIMARY KEY (task_id, node_id, link_type)
+	);
+
+	CREATE TABLE IF NOT EXISTS clarify_sessions (
+		id TEXT PRIMARY KEY,
+		goal TEXT NOT NULL,
+		enriched_goal TEXT,
+		goal_summary TEXT,
+		state TEXT NOT NULL,
+		round_index INTEGER NOT NULL DEFAULT 0,
+		max_rounds INTEGER NOT NULL DEFAULT 5,
+		max_questions_per_round INTEGER NOT NULL DEFAULT 3,
+		current_questions TEXT,
+		is_ready_to_plan INTEGER NOT NULL DEFAULT 0,
+		last_context_used TEXT,
+		created_at TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS clarify_turns (
+		id TEXT PRIMARY KEY,
+		session_id TEXT NOT NULL REFERENCES clarify_sessions(id) ON DELETE CASCADE,
+		round_index INTEGER NOT NULL,
+		questions TEXT,
+		answers TEXT,
+		goal_summary TEXT,
+		enriched_goal TEXT,
+		is_ready_to_plan INTEGER NOT NULL DEFAULT 0,
+		auto_answered INTEGER NOT NULL DEFAULT 0,
+		max_rounds_reached INTEGER NOT NULL DEFAULT 0,
+		context_summary TEXT,
+		created_at TEXT NOT NULL
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_clarify_turns_session_round ON clarify_turns(session_id, round_index);
+`
+
+// postgresTaskMigrations add the columns introduced after the Postgres schema
+// was first released, mirroring the SQLite migrateAddColumn calls. They must
+// be idempotent: they run on every NewPostgresStore.
+var postgresTaskMigrations = []string{
+	`ALTER TABLE phases ADD COLUMN IF NOT EXISTS depends_on TEXT`,
+	`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sort_order INTEGER DEFAULT 0`,
+}
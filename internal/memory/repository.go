@@ -13,6 +13,7 @@ import (
 // of project and global nodes, with project nodes ranked first.
 type Repository struct {
 	db     *SQLiteStore
+	tasks  *taskStore // plans and tasks; db's unless SetTaskBackend moved them
 	files  *MarkdownStore
 	global *Repository // optional global knowledge layer
 }
@@ -21,10 +22,17 @@ type Repository struct {
 func NewRepository(db *SQLiteStore, files *MarkdownStore) *Repository {
 	return &Repository{
 		db:    db,
+		tasks: db.taskStore,
 		files: files,
 	}
 }
 
+// SetTaskBackend stores plans, phases, tasks and clarify sessions in backend
+// instead of the SQLite store. Knowledge stays in SQLite.
+func (r *Repository) SetTaskBackend(backend TaskBackend) {
+	r.tasks = backend.taskBackend()
+}
+
 // NewDefaultRepository creates a Repository with standard SQLite and Markdown stores.
 func NewDefaultRepository(basePath string) (*Repository, error) {
 	db, err := NewSQLiteStore(basePath)
@@ -82,110 +90,125 @@ func (r *Repository) Close() error {
 	return err
 }
 
-// === Task Repository Methods (delegate to SQLiteStore) ===
+// === Task Repository Methods (delegate to the task backend) ===
 
 // AddDependency adds a dependency relationship between two tasks.
 func (r *Repository) AddDependency(taskID, dependsOn string) error {
-	return r.db.AddDependency(taskID, dependsOn)
+	return r.tasks.AddDependency(taskID, dependsOn)
 }
 
 // RemoveDependency removes a dependency relationship between two tasks.
 func (r *Repository) RemoveDependency(taskID, dependsOn string) error {
-	return r.db.RemoveDependency(taskID, dependsOn)
+	return r.tasks.RemoveDependency(taskID, dependsOn)
 }
 
 // FindTaskIDsByPrefix returns all task IDs that start with the given prefix.
 // The ctx parameter is accepted for interface compatibility but not currently used.
 func (r *Repository) FindTaskIDsByPrefix(ctx context.Context, prefix string) ([]string, error) {
-	return r.db.FindTaskIDsByPrefix(prefix)
+	return r.tasks.FindTaskIDsByPrefix(prefix)
 }
 
 // FindPlanIDsByPrefix returns all plan IDs that start with the given prefix.
 // The ctx parameter is accepted for interface compatibility but not currently used.
 func (r *Repository) FindPlanIDsByPrefix(ctx context.Context, prefix string) ([]string, error) {
-	return r.db.FindPlanIDsByPrefix(prefix)
+	return r.tasks.FindPlanIDsByPrefix(prefix)
 }
 
 // === Phase Repository Methods (delegate to SQLiteStore) ===
 
 // CreatePhase creates a new phase.
 func (r *Repository) CreatePhase(p *task.Phase) error {
-	return r.db.CreatePhase(p)
+	return r.tasks.CreatePhase(p)
 }
 
 // GetPhase retrieves a phase by ID.
 func (r *Repository) GetPhase(id string) (*task.Phase, error) {
-	return r.db.GetPhase(id)
+	return r.tasks.GetPhase(id)
 }
 
 // ListPhases returns all phases for a plan.
 func (r *Repository) ListPhases(planID string) ([]task.Phase, error) {
-	return r.db.ListPhases(planID)
+	return r.tasks.ListPhases(planID)
 }
 
 // UpdatePhase updates a phase.
 func (r *Repository) UpdatePhase(p *task.Phase) error {
-	return r.db.UpdatePhase(p)
+	return r.tasks.UpdatePhase(p)
 }
 
 // UpdatePhaseStatus updates the status of a phase.
 func (r *Repository) UpdatePhaseStatus(id string, status task.PhaseStatus) error {
-	return r.db.UpdatePhaseStatus(id, status)
+	return r.tasks.UpdatePhaseStatus(id, status)
 }
 
 // DeletePhase deletes a phase.
 func (r *Repository) DeletePhase(id string) error {
-	return r.db.DeletePhase(id)
+	return r.tasks.DeletePhase(id)
 }
 
 // CreatePhasesForPlan creates multiple phases for a plan atomically.
 func (r *Repository) CreatePhasesForPlan(planID string, phases []task.Phase) error {
-	return r.db.CreatePhasesForPlan(planID, phases)
+	return r.tasks.CreatePhasesForPlan(planID, phases)
+}
+
+// ReorderPhases sets the order of a plan's phases.
+func (r *Repository) ReorderPhases(planID string, orderedIDs []string) error {
+	return r.tasks.ReorderPhases(planID, orderedIDs)
+}
+
+// InsertPhase adds a phase to a plan at the given position.
+func (r *Repository) InsertPhase(planID string, index int, p *task.Phase) error {
+	return r.tasks.InsertPhase(planID, index, p)
+}
+
+// RecordPhaseExpandAttempt appends an expansion attempt to a phase's history.
+func (r *Repository) RecordPhaseExpandAttempt(phaseID string, attempt task.ExpandAttempt) error {
+	return r.tasks.RecordPhaseExpandAttempt(phaseID, attempt)
 }
 
 // ListTasksByPhase returns all tasks for a phase.
 func (r *Repository) ListTasksByPhase(phaseID string) ([]task.Task, error) {
-	return r.db.ListTasksByPhase(phaseID)
+	return r.tasks.ListTasksByPhase(phaseID)
 }
 
 // GetPlanWithPhases retrieves a plan with its phases.
 func (r *Repository) GetPlanWithPhases(id string) (*task.Plan, error) {
-	return r.db.GetPlanWithPhases(id)
+	return r.tasks.GetPlanWithPhases(id)
 }
 
 // UpdatePlanDraftState updates the draft state JSON for a plan.
 func (r *Repository) UpdatePlanDraftState(planID string, draftStateJSON string) error {
-	return r.db.UpdatePlanDraftState(planID, draftStateJSON)
+	return r.tasks.UpdatePlanDraftState(planID, draftStateJSON)
 }
 
 // UpdatePlanGenerationMode updates the generation mode for a plan.
 func (r *Repository) UpdatePlanGenerationMode(planID string, mode task.GenerationMode) error {
-	return r.db.UpdatePlanGenerationMode(planID, mode)
+	return r.tasks.UpdatePlanGenerationMode(planID, mode)
 }
 
 // === Clarify Session Repository Methods (delegate to SQLiteStore) ===
 
 // CreateClarifySession creates a persisted clarify session.
 func (r *Repository) CreateClarifySession(session *task.ClarifySession) error {
-	return r.db.CreateClarifySession(session)
+	return r.tasks.CreateClarifySession(session)
 }
 
 // GetClarifySession retrieves a clarify session by ID.
 func (r *Repository) GetClarifySession(id string) (*task.ClarifySession, error) {
-	return r.db.GetClarifySession(id)
+	return r.tasks.GetClarifySession(id)
 }
 
 // UpdateClarifySession updates persisted clarify session state.
 func (r *Repository) UpdateClarifySession(session *task.ClarifySession) error {
-	return r.db.UpdateClarifySession(session)
+	return r.tasks.UpdateClarifySession(session)
 }
 
 // CreateClarifyTurn persists a single clarify round turn.
 func (r *Repository) CreateClarifyTurn(turn *task.ClarifyTurn) error {
-	return r.db.CreateClarifyTurn(turn)
+	return r.tasks.CreateClarifyTurn(turn)
 }
 
 // ListClarifyTurns returns all clarify turns for a session.
 func (r *Repository) ListClarifyTurns(sessionID string) ([]task.ClarifyTurn, error) {
-	return r.db.ListClarifyTurns(sessionID)
+	return r.tasks.ListClarifyTurns(sessionID)
 }
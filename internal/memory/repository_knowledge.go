@@ -7,6 +7,11 @@ func (r *Repository) LinkNodes(from, to, relation string, confidence float64, pr
 	return r.db.LinkNodes(from, to, relation, confidence, properties)
 }
 
+// DeleteNodeEdge removes an edge from the knowledge graph.
+func (r *Repository) DeleteNodeEdge(id int64) error {
+	return r.db.DeleteNodeEdge(id)
+}
+
 // GetAllNodeEdges returns all edges in the knowledge graph.
 func (r *Repository) GetAllNodeEdges() ([]NodeEdge, error) {
 	return r.db.GetAllNodeEdges()
@@ -72,10 +77,42 @@ func (r *Repository) UpdateNodeEmbedding(id string, embedding []float32) error {
 	return r.db.UpdateNodeEmbedding(id, embedding)
 }
 
+// UpdateNodeVerification sets the verification status of a node.
+func (r *Repository) UpdateNodeVerification(id, status string) error {
+	return r.db.UpdateNodeVerification(id, status)
+}
+
 func (r *Repository) UpdateNodeWorkspace(id, workspace string) error {
 	return r.db.UpdateNodeWorkspace(id, workspace)
 }
 
+// AddNodeTag tags a node in the project store.
+func (r *Repository) AddNodeTag(id, tag string) error {
+	return r.db.AddNodeTag(id, tag)
+}
+
+// RemoveNodeTag removes a tag from a node in the project store.
+func (r *Repository) RemoveNodeTag(id, tag string) error {
+	return r.db.RemoveNodeTag(id, tag)
+}
+
+// ListNodesByTag returns project nodes carrying the tag.
+func (r *Repository) ListNodesByTag(tag string) ([]Node, error) {
+	return r.db.ListNodesByTag(tag)
+}
+
+func (r *Repository) RecordNodeAccess(ids []string) error {
+	return r.db.RecordNodeAccess(ids)
+}
+
+func (r *Repository) GetNodeAccessCounts(ids []string) (map[string]int, error) {
+	return r.db.GetNodeAccessCounts(ids)
+}
+
+func (r *Repository) ListNodesByAccess(limit int) ([]Node, error) {
+	return r.db.ListNodesByAccess(limit)
+}
+
 func (r *Repository) DeleteNode(id string) error {
 	return r.db.DeleteNode(id)
 }
@@ -37,6 +37,14 @@ func (r *Repository) GenerateArchitectureMD(projectName string) error {
 	return r.files.GenerateArchitectureMD(data, projectName)
 }
 
+// WriteFeatureDoc writes a single feature's markdown page and returns its path.
+func (r *Repository) WriteFeatureDoc(doc FeatureDoc) (string, error) {
+	if r.files == nil {
+		return "", fmt.Errorf("no markdown store configured")
+	}
+	return r.files.WriteFeatureDoc(doc)
+}
+
 // GetProjectOverview retrieves the project overview from the database.
 // Returns nil if no overview exists yet.
 func (r *Repository) GetProjectOverview() (*ProjectOverview, error) {
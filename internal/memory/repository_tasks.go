@@ -7,98 +7,131 @@ import (
 // === Task & Plan Management ===
 
 func (r *Repository) CreatePlan(p *task.Plan) error {
-	return r.db.CreatePlan(p)
+	return r.tasks.CreatePlan(p)
 }
 
 func (r *Repository) GetPlan(id string) (*task.Plan, error) {
-	return r.db.GetPlan(id)
+	return r.tasks.GetPlan(id)
 }
 
-func (r *Repository) ListPlans() ([]task.Plan, error) {
-	return r.db.ListPlans()
+func (r *Repository) ListPlans(filter task.PlanFilter) ([]task.Plan, error) {
+	return r.tasks.ListPlans(filter)
 }
 
 // SearchPlans returns plans matching query and status.
 func (r *Repository) SearchPlans(query string, status task.PlanStatus) ([]task.Plan, error) {
-	return r.db.SearchPlans(query, status)
+	return r.tasks.SearchPlans(query, status)
 }
 
 func (r *Repository) UpdatePlan(id string, goal, enrichedGoal string, status task.PlanStatus) error {
-	return r.db.UpdatePlan(id, goal, enrichedGoal, status)
+	return r.tasks.UpdatePlan(id, goal, enrichedGoal, status)
 }
 
 func (r *Repository) DeletePlan(id string) error {
-	return r.db.DeletePlan(id)
+	return r.tasks.DeletePlan(id)
 }
 
 func (r *Repository) CreateTask(t *task.Task) error {
-	return r.db.CreateTask(t)
+	return r.tasks.CreateTask(t)
 }
 
 func (r *Repository) GetTask(id string) (*task.Task, error) {
-	return r.db.GetTask(id)
+	return r.tasks.GetTask(id)
+}
+
+// GetTaskContextNodes returns the IDs of the knowledge nodes linked to a task.
+func (r *Repository) GetTaskContextNodes(taskID string) ([]string, error) {
+	return r.tasks.GetTaskContextNodes(taskID)
 }
 
 func (r *Repository) ListTasks(planID string) ([]task.Task, error) {
-	return r.db.ListTasks(planID)
+	return r.tasks.ListTasks(planID)
 }
 
 func (r *Repository) UpdateTaskStatus(id string, status task.TaskStatus) error {
-	return r.db.UpdateTaskStatus(id, status)
+	return r.tasks.UpdateTaskStatus(id, status)
+}
+
+func (r *Repository) UpdateTaskContextSummary(id, summary string, enrichmentErrors int) error {
+	return r.tasks.UpdateTaskContextSummary(id, summary, enrichmentErrors)
+}
+
+func (r *Repository) UpdateTaskCriteriaVerification(id string, results []task.CriterionVerification) error {
+	return r.tasks.UpdateTaskCriteriaVerification(id, results)
 }
 
 func (r *Repository) DeleteTask(id string) error {
-	return r.db.DeleteTask(id)
+	return r.tasks.DeleteTask(id)
+}
+
+// SplitTask creates subtasks of a pending task and links them in one transaction.
+func (r *Repository) SplitTask(parentID string, subtasks []task.Task) error {
+	return r.tasks.SplitTask(parentID, subtasks)
 }
 
 // === Task Lifecycle (for MCP tools) ===
 
 // GetNextTask returns the highest priority pending task from a plan.
 func (r *Repository) GetNextTask(planID string) (*task.Task, error) {
-	return r.db.GetNextTask(planID)
+	return r.tasks.GetNextTask(planID)
+}
+
+// GetNextTaskForAgent returns the highest priority ready task assigned to agent.
+func (r *Repository) GetNextTaskForAgent(planID, agent string) (*task.Task, error) {
+	return r.tasks.GetNextTaskForAgent(planID, agent)
 }
 
 // GetCurrentTask returns the in-progress task claimed by a session.
 func (r *Repository) GetCurrentTask(sessionID string) (*task.Task, error) {
-	return r.db.GetCurrentTask(sessionID)
+	return r.tasks.GetCurrentTask(sessionID)
 }
 
 // GetAnyInProgressTask returns any in-progress task from a plan.
 func (r *Repository) GetAnyInProgressTask(planID string) (*task.Task, error) {
-	return r.db.GetAnyInProgressTask(planID)
+	return r.tasks.GetAnyInProgressTask(planID)
 }
 
 // ClaimTask marks a task as in_progress and assigns it to a session.
 func (r *Repository) ClaimTask(taskID, sessionID string) error {
-	return r.db.ClaimTask(taskID, sessionID)
+	return r.tasks.ClaimTask(taskID, sessionID)
 }
 
 // SetGitBaseline records the git state when a task was claimed.
 func (r *Repository) SetGitBaseline(taskID string, baseline []string) error {
-	return r.db.SetGitBaseline(taskID, baseline)
+	return r.tasks.SetGitBaseline(taskID, baseline)
 }
 
 // CompleteTask marks a task as completed with summary and files modified.
 func (r *Repository) CompleteTask(taskID, summary string, filesModified []string) error {
-	return r.db.CompleteTask(taskID, summary, filesModified)
+	return r.tasks.CompleteTask(taskID, summary, filesModified)
+}
+
+// MergePlans moves the source plan's phases and tasks into the target and deletes the source.
+func (r *Repository) MergePlans(targetID, sourceID string) error {
+	return r.tasks.MergePlans(targetID, sourceID)
+}
+
+// ReorderTasks reassigns task priorities to follow the given order.
+func (r *Repository) ReorderTasks(planID string, orderedIDs []string) error {
+	return r.tasks.ReorderTasks(planID, orderedIDs)
 }
 
 // SkipTask marks a task as skipped with an optional reason.
 func (r *Repository) SkipTask(taskID, reason string) error {
-	return r.db.SkipTask(taskID, reason)
+	return r.tasks.SkipTask(taskID, reason)
 }
 
 // GetActivePlan returns the currently active plan.
 func (r *Repository) GetActivePlan() (*task.Plan, error) {
-	return r.db.GetActivePlan()
+	return r.tasks.GetActivePlan()
 }
 
 // SetActivePlan sets the active plan.
 func (r *Repository) SetActivePlan(id string) error {
-	return r.db.SetActivePlan(id)
+	return r.tasks.SetActivePlan(id)
 }
 
 // UpdatePlanAuditReport updates the audit report and status for a plan.
 func (r *Repository) UpdatePlanAuditReport(id string, status task.PlanStatus, auditReportJSON string) error {
-	return r.db.UpdatePlanAuditReport(id, status, auditReportJSON)
+	return r.tasks.UpdatePlanAuditReport(id, status, auditReportJSON)
 }
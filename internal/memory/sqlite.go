@@ -13,11 +13,13 @@ import (
 	"unsafe"
 
 	"github.com/google/uuid"
+	"github.com/josephgoksu/TaskWing/internal/task"
 	_ "modernc.org/sqlite"
 )
 
 // SQLiteStore implements MemoryStore using SQLite for persistence.
 type SQLiteStore struct {
+	*taskStore
 	db       *sql.DB
 	basePath string // Path to project store directory
 }
@@ -49,8 +51,9 @@ func NewSQLiteStore(basePath string) (*SQLiteStore, error) {
 	}
 
 	store := &SQLiteStore{
-		db:       db,
-		basePath: basePath,
+		taskStore: newTaskStore(db, SQLiteDialect),
+		db:        db,
+		basePath:  basePath,
 	}
 
 	// Initialize schema
@@ -64,6 +67,8 @@ func NewSQLiteStore(basePath string) (*SQLiteStore, error) {
 	migrateAddColumn(db, "plans", "draft_state", `ALTER TABLE plans ADD COLUMN draft_state TEXT`)
 	migrateAddColumn(db, "plans", "generation_mode", `ALTER TABLE plans ADD COLUMN generation_mode TEXT DEFAULT 'batch'`)
 	migrateAddColumn(db, "tasks", "phase_id", `ALTER TABLE tasks ADD COLUMN phase_id TEXT REFERENCES phases(id) ON DELETE SET NULL`)
+	migrateAddColumn(db, "phases", "expand_attempts", `ALTER TABLE phases ADD COLUMN expand_attempts TEXT`)
+	migrateAddColumn(db, "phases", "depends_on", `ALTER TABLE phases ADD COLUMN depends_on TEXT`)
 
 	// Freshness validation columns (v2.3+)
 	migrateAddColumn(db, "nodes", "last_verified_at", `ALTER TABLE nodes ADD COLUMN last_verified_at TEXT`)
@@ -143,8 +148,17 @@ func (s *SQLiteStore) initSchema() error {
 		UNIQUE(from_node, to_node, relation)
 	);
 
+	-- User-assigned tags for grouping nodes (e.g. security, perf)
+	CREATE TABLE IF NOT EXISTS node_tags (
+		node_id TEXT NOT NULL,
+		tag TEXT NOT NULL,                  -- Lowercased, trimmed
+		PRIMARY KEY (node_id, tag),
+		FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+	);
+
 	-- Indexes
 	CREATE INDEX IF NOT EXISTS idx_decisions_feature ON decisions(feature_id);
+	CREATE INDEX IF NOT EXISTS idx_node_tags_tag ON node_tags(tag);
 	CREATE INDEX IF NOT EXISTS idx_nodes_type ON nodes(type);
 	CREATE INDEX IF NOT EXISTS idx_nodes_source_agent ON nodes(source_agent);
 	CREATE INDEX IF NOT EXISTS idx_nodes_summary_agent ON nodes(summary, source_agent);
@@ -184,6 +198,8 @@ func (s *SQLiteStore) initSchema() error {
 		order_index INTEGER NOT NULL DEFAULT 0,
 		status TEXT NOT NULL DEFAULT 'pending', -- pending, expanded, skipped
 		expected_tasks INTEGER DEFAULT 0,
+		expand_attempts TEXT,                 -- JSON array of prior expansions
+		depends_on TEXT,                      -- JSON array of phase IDs
 		created_at TEXT NOT NULL,
 		updated_at TEXT NOT NULL,
 		FOREIGN KEY (plan_id) REFERENCES plans(id) ON DELETE CASCADE
@@ -395,6 +411,15 @@ func (s *SQLiteStore) initSchema() error {
 	CREATE INDEX IF NOT EXISTS idx_symbol_relations_to ON symbol_relations(to_symbol_id);
 	CREATE INDEX IF NOT EXISTS idx_symbol_relations_type ON symbol_relations(relation_type);
 
+	-- Imports per source file, categorized at index time
+	CREATE TABLE IF NOT EXISTS file_imports (
+		file_path TEXT NOT NULL,
+		import_path TEXT NOT NULL,
+		category TEXT NOT NULL,          -- internal, stdlib, third_party
+		line INTEGER,
+		PRIMARY KEY (file_path, import_path)
+	);
+
 	-- Dependencies from lockfiles (package.json, Cargo.lock, poetry.lock, etc.)
 	-- Enables dependency analysis, security scanning, and upgrade planning
 	CREATE TABLE IF NOT EXISTS dependencies (
@@ -571,6 +596,7 @@ func (s *SQLiteStore) initSchema() error {
 		{"workspace", "ALTER TABLE nodes ADD COLUMN workspace TEXT DEFAULT 'root'"},
 		{"stale_count", "ALTER TABLE nodes ADD COLUMN stale_count INTEGER DEFAULT 0"},
 		{"compact_summary", "ALTER TABLE nodes ADD COLUMN compact_summary TEXT DEFAULT ''"},
+		{"access_count", "ALTER TABLE nodes ADD COLUMN access_count INTEGER DEFAULT 0"}, // Times recall returned the node
 	}
 
 	for _, m := range migrations {
@@ -617,17 +643,21 @@ func (s *SQLiteStore) initSchema() error {
 		column string
 		ddl    string
 	}{
-		{"scope", "ALTER TABLE tasks ADD COLUMN scope TEXT"},                                 // e.g., "auth", "api", "vectorsearch"
-		{"keywords", "ALTER TABLE tasks ADD COLUMN keywords TEXT"},                           // JSON array of extracted keywords
-		{"suggested_ask_queries", "ALTER TABLE tasks ADD COLUMN suggested_ask_queries TEXT"}, // JSON array of pre-computed ask queries
-		{"claimed_by", "ALTER TABLE tasks ADD COLUMN claimed_by TEXT"},                       // Session ID that claimed this task
-		{"claimed_at", "ALTER TABLE tasks ADD COLUMN claimed_at TEXT"},                       // Timestamp when claimed
-		{"completed_at", "ALTER TABLE tasks ADD COLUMN completed_at TEXT"},                   // Timestamp when completed
-		{"completion_summary", "ALTER TABLE tasks ADD COLUMN completion_summary TEXT"},       // AI-generated summary on completion
-		{"files_modified", "ALTER TABLE tasks ADD COLUMN files_modified TEXT"},               // JSON array of modified files
-		{"block_reason", "ALTER TABLE tasks ADD COLUMN block_reason TEXT"},                   // Reason if task is blocked
-		{"expected_files", "ALTER TABLE tasks ADD COLUMN expected_files TEXT"},               // JSON array of expected files (for Sentinel)
-		{"git_baseline", "ALTER TABLE tasks ADD COLUMN git_baseline TEXT"},                   // JSON array of files already modified at task start
+		{"scope", "ALTER TABLE tasks ADD COLUMN scope TEXT"},                                      // e.g., "auth", "api", "vectorsearch"
+		{"keywords", "ALTER TABLE tasks ADD COLUMN keywords TEXT"},                                // JSON array of extracted keywords
+		{"suggested_ask_queries", "ALTER TABLE tasks ADD COLUMN suggested_ask_queries TEXT"},      // JSON array of pre-computed ask queries
+		{"claimed_by", "ALTER TABLE tasks ADD COLUMN claimed_by TEXT"},                            // Session ID that claimed this task
+		{"claimed_at", "ALTER TABLE tasks ADD COLUMN claimed_at TEXT"},                            // Timestamp when claimed
+		{"completed_at", "ALTER TABLE tasks ADD COLUMN completed_at TEXT"},                        // Timestamp when completed
+		{"completion_summary", "ALTER TABLE tasks ADD COLUMN completion_summary TEXT"},            // AI-generated summary on completion
+		{"files_modified", "ALTER TABLE tasks ADD COLUMN files_modified TEXT"},                    // JSON array of modified files
+		{"block_reason", "ALTER TABLE tasks ADD COLUMN block_reason TEXT"},                        // Reason if task is blocked
+		{"expected_files", "ALTER TABLE tasks ADD COLUMN expected_files TEXT"},                    // JSON array of expected files (for Sentinel)
+		{"git_baseline", "ALTER TABLE tasks ADD COLUMN git_baseline TEXT"},                        // JSON array of files already modified at task start
+		{"enrichment_errors", "ALTER TABLE tasks ADD COLUMN enrichment_errors INTEGER DEFAULT 0"}, // Failed context queries at creation
+		{"criteria_verification", "ALTER TABLE tasks ADD COLUMN criteria_verification TEXT"},      // JSON array of per-criterion verification results
+		{"skip_audit", "ALTER TABLE tasks ADD COLUMN skip_audit INTEGER DEFAULT 0"},               // Excluded from plan audits
+		{"sort_order", "ALTER TABLE tasks ADD COLUMN sort_order INTEGER DEFAULT 0"},               // Orders tasks of equal priority
 	}
 
 	for _, m := range taskMigrations {
@@ -711,14 +741,117 @@ func (s *SQLiteStore) initSchema() error {
 
 // === Integrity ===
 
+// ftsIndexes are the external-content FTS5 tables kept in sync by triggers.
+var ftsIndexes = []string{"nodes_fts", "symbols_fts", "dependencies_fts"}
+
+// Check reports integrity problems in project memory: orphaned phases and tasks,
+// more than one active plan, and FTS indexes out of sync with their content tables.
 func (s *SQLiteStore) Check() ([]Issue, error) {
-	// Legacy feature-file checks removed. Return empty for now.
-	return nil, nil
+	var issues []Issue
+
+	var activePlans int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM plans WHERE status = ?`, task.PlanStatusActive).Scan(&activePlans); err != nil {
+		return nil, fmt.Errorf("count active plans: %w", err)
+	}
+	if activePlans > 1 {
+		issues = append(issues, Issue{
+			Type:    "multiple_active_plans",
+			Message: fmt.Sprintf("%d plans are marked active; only the most recently updated one is used", activePlans),
+			Hint:    "Run 'taskwing memory repair' to keep only the latest active plan",
+		})
+	}
+
+	orphanQueries := []struct {
+		issueType string
+		query     string
+		format    string
+	}{
+		{"orphan_phase", `SELECT id FROM phases WHERE plan_id NOT IN (SELECT id FROM plans)`, "phase %s belongs to a plan that no longer exists"},
+		{"orphan_task", `SELECT id FROM tasks WHERE plan_id NOT IN (SELECT id FROM plans)`, "task %s belongs to a plan that no longer exists"},
+		{"orphan_task", `SELECT id FROM tasks WHERE phase_id IS NOT NULL AND phase_id != '' AND phase_id NOT IN (SELECT id FROM phases)`, "task %s references a phase that no longer exists"},
+	}
+	for _, oq := range orphanQueries {
+		ids, err := s.queryIDs(oq.query)
+		if err != nil {
+			return nil, fmt.Errorf("check %s: %w", oq.issueType, err)
+		}
+		for _, id := range ids {
+			issues = append(issues, Issue{
+				Type:    oq.issueType,
+				Message: fmt.Sprintf(oq.format, id),
+				Hint:    "Run 'taskwing memory repair' to remove orphaned records",
+			})
+		}
+	}
+
+	for _, table := range ftsIndexes {
+		// 'integrity-check' with rank=1 compares the index against the content table
+		if _, err := s.db.Exec(fmt.Sprintf(`INSERT INTO %s(%s, rank) VALUES('integrity-check', 1)`, table, table)); err != nil {
+			issues = append(issues, Issue{
+				Type:    "index_mismatch",
+				Message: fmt.Sprintf("%s is out of sync with its source table: %v", table, err),
+				Hint:    "Run 'taskwing memory repair' to rebuild the search index",
+			})
+		}
+	}
+
+	return issues, nil
 }
 
+// Repair fixes the problems reported by Check.
 func (s *SQLiteStore) Repair() error {
-	// Legacy index rebuild removed. No-op.
-	return nil
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { rollbackWithLog(tx, "repair") }()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	statements := []struct {
+		name  string
+		query string
+		args  []any
+	}{
+		{"demote extra active plans", `
+			UPDATE plans SET status = ?, updated_at = ?
+			WHERE status = ? AND id != (
+				SELECT id FROM plans WHERE status = ? ORDER BY updated_at DESC LIMIT 1
+			)`, []any{task.PlanStatusDraft, now, task.PlanStatusActive, task.PlanStatusActive}},
+		{"detach tasks from missing phases", `UPDATE tasks SET phase_id = NULL WHERE phase_id IS NOT NULL AND phase_id != '' AND phase_id NOT IN (SELECT id FROM phases)`, nil},
+		{"delete orphaned tasks", `DELETE FROM tasks WHERE plan_id NOT IN (SELECT id FROM plans)`, nil},
+		{"delete orphaned phases", `DELETE FROM phases WHERE plan_id NOT IN (SELECT id FROM plans)`, nil},
+	}
+	for _, st := range statements {
+		if _, err := tx.Exec(st.query, st.args...); err != nil {
+			return fmt.Errorf("%s: %w", st.name, err)
+		}
+	}
+	for _, table := range ftsIndexes {
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s(%s) VALUES('rebuild')`, table, table)); err != nil {
+			return fmt.Errorf("rebuild %s: %w", table, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// queryIDs runs a query returning a single string column.
+func (s *SQLiteStore) queryIDs(query string, args ...any) ([]string, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, checkRowsErr(rows)
 }
 
 // === Lifecycle ===
@@ -733,6 +866,19 @@ func (s *SQLiteStore) DB() *sql.DB {
 	return s.db
 }
 
+// SizeBytes returns the size of the database file (page count × page size).
+// Works for in-memory databases too.
+func (s *SQLiteStore) SizeBytes() (int64, error) {
+	var pageCount, pageSize int64
+	if err := s.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("page count: %w", err)
+	}
+	if err := s.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("page size: %w", err)
+	}
+	return pageCount * pageSize, nil
+}
+
 // === Helpers ===
 
 // === Node Helpers ===
@@ -790,7 +936,7 @@ func (s *SQLiteStore) CreateNode(n *Node) error {
 		return fmt.Errorf("insert node: %w", err)
 	}
 
-	return nil
+	return insertNodeTags(s.db, n.ID, n.Tags)
 }
 
 // GetNode retrieves a node by ID including evidence and verification fields.
@@ -801,16 +947,17 @@ func (s *SQLiteStore) GetNode(id string) (*Node, error) {
 	var evidence, verificationStatus, verificationResult sql.NullString
 	var confidenceScore, debtScore sql.NullFloat64
 	var debtReason, refactorHint sql.NullString
+	var accessCount sql.NullInt64
 	var embeddingBytes []byte
 
 	err := s.db.QueryRow(`
 		SELECT id, content, type, summary, source_agent, workspace, embedding, created_at,
 		       evidence, verification_status, verification_result, confidence_score,
-		       debt_score, debt_reason, refactor_hint
+		       debt_score, debt_reason, refactor_hint, access_count
 		FROM nodes WHERE id = ?
 	`, id).Scan(&n.ID, &n.Content, &nodeType, &summary, &sourceAgent, &workspace, &embeddingBytes, &createdAt,
 		&evidence, &verificationStatus, &verificationResult, &confidenceScore,
-		&debtScore, &debtReason, &refactorHint)
+		&debtScore, &debtReason, &refactorHint, &accessCount)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("node not found: %s", id)
@@ -845,6 +992,13 @@ func (s *SQLiteStore) GetNode(id string) (*Node, error) {
 	if refactorHint.Valid {
 		n.RefactorHint = refactorHint.String
 	}
+	n.AccessCount = int(accessCount.Int64)
+
+	tags, err := s.GetNodeTags(n.ID)
+	if err != nil {
+		return nil, err
+	}
+	n.Tags = tags
 
 	return &n, nil
 }
@@ -1437,6 +1591,9 @@ func (s *SQLiteStore) UpsertNodeBySummary(n Node) error {
 		if err != nil {
 			return fmt.Errorf("update existing node: %w", err)
 		}
+		if err := insertNodeTags(tx, existingID, n.Tags); err != nil {
+			return err
+		}
 		return tx.Commit()
 	}
 
@@ -1488,6 +1645,9 @@ func (s *SQLiteStore) UpsertNodeBySummary(n Node) error {
 			if err != nil {
 				return fmt.Errorf("update similar node: %w", err)
 			}
+			if err := insertNodeTags(tx, similarID, n.Tags); err != nil {
+				return err
+			}
 			return tx.Commit()
 		}
 	}
@@ -1556,6 +1716,9 @@ func (s *SQLiteStore) UpsertNodeBySummary(n Node) error {
 				if err != nil {
 					return fmt.Errorf("update embedding-matched node: %w", err)
 				}
+				if err := insertNodeTags(tx, bestID, n.Tags); err != nil {
+					return err
+				}
 				return tx.Commit()
 			}
 		}
@@ -1574,6 +1737,9 @@ func (s *SQLiteStore) UpsertNodeBySummary(n Node) error {
 	if err != nil {
 		return fmt.Errorf("insert node: %w", err)
 	}
+	if err := insertNodeTags(tx, n.ID, n.Tags); err != nil {
+		return err
+	}
 
 	return tx.Commit()
 }
@@ -1595,6 +1761,21 @@ func (s *SQLiteStore) UpdateNodeEmbedding(id string, embedding []float32) error
 	return nil
 }
 
+// UpdateNodeVerification sets the verification status of a node.
+func (s *SQLiteStore) UpdateNodeVerification(id, status string) error {
+	result, err := s.db.Exec("UPDATE nodes SET verification_status = ? WHERE id = ?", status, id)
+	if err != nil {
+		return fmt.Errorf("update verification status: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("node not found: %s", id)
+	}
+
+	return nil
+}
+
 // UpdateNodeWorkspace updates the workspace field for a node.
 func (s *SQLiteStore) UpdateNodeWorkspace(id, workspace string) error {
 	result, err := s.db.Exec("UPDATE nodes SET workspace = ? WHERE id = ?", workspace, id)
@@ -1610,6 +1791,199 @@ func (s *SQLiteStore) UpdateNodeWorkspace(id, workspace string) error {
 	return nil
 }
 
+// === Node Access ===
+
+// RecordNodeAccess adds one to the access count of each node in ids.
+// Unknown IDs are ignored.
+func (s *SQLiteStore) RecordNodeAccess(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, id := range ids {
+		if _, err := tx.Exec("UPDATE nodes SET access_count = COALESCE(access_count, 0) + 1 WHERE id = ?", id); err != nil {
+			return fmt.Errorf("record access for %s: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetNodeAccessCounts returns the access counts of the nodes in ids that
+// have been accessed at least once.
+func (s *SQLiteStore) GetNodeAccessCounts(ids []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := s.db.Query("SELECT id, access_count FROM nodes WHERE access_count > 0 AND id IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, fmt.Errorf("query access counts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("scan access count: %w", err)
+		}
+		counts[id] = count
+	}
+	if err := checkRowsErr(rows); err != nil {
+		return nil, fmt.Errorf("list access counts: %w", err)
+	}
+	return counts, nil
+}
+
+// ListNodesByAccess returns up to limit nodes that have been accessed, most
+// accessed first; ties go to the newest node.
+func (s *SQLiteStore) ListNodesByAccess(limit int) ([]Node, error) {
+	rows, err := s.db.Query(`
+		SELECT id FROM nodes
+		WHERE access_count > 0
+		ORDER BY access_count DESC, created_at DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query nodes by access: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan node id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := checkRowsErr(rows); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("list nodes by access: %w", err)
+	}
+	_ = rows.Close()
+
+	nodes := make([]Node, 0, len(ids))
+	for _, id := range ids {
+		n, err := s.GetNode(id)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, *n)
+	}
+	return nodes, nil
+}
+
+// === Node Tags ===
+
+// NormalizeTag trims and lowercases a tag so "Security " and "security" match.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// insertNodeTags adds tags to a node, ignoring blanks and tags it already has.
+func insertNodeTags(exec txExecutor, nodeID string, tags []string) error {
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		if _, err := exec.Exec(`INSERT OR IGNORE INTO node_tags (node_id, tag) VALUES (?, ?)`, nodeID, tag); err != nil {
+			return fmt.Errorf("tag node %s: %w", nodeID, err)
+		}
+	}
+	return nil
+}
+
+// AddNodeTag tags a node. Adding a tag the node already has is a no-op.
+func (s *SQLiteStore) AddNodeTag(nodeID, tag string) error {
+	if NormalizeTag(tag) == "" {
+		return fmt.Errorf("tag cannot be empty")
+	}
+	var exists int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM nodes WHERE id = ?", nodeID).Scan(&exists); err != nil {
+		return fmt.Errorf("check node: %w", err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("node not found: %s", nodeID)
+	}
+	return insertNodeTags(s.db, nodeID, []string{tag})
+}
+
+// RemoveNodeTag removes a tag from a node. Removing an absent tag is a no-op.
+func (s *SQLiteStore) RemoveNodeTag(nodeID, tag string) error {
+	if _, err := s.db.Exec("DELETE FROM node_tags WHERE node_id = ? AND tag = ?", nodeID, NormalizeTag(tag)); err != nil {
+		return fmt.Errorf("untag node %s: %w", nodeID, err)
+	}
+	return nil
+}
+
+// GetNodeTags returns a node's tags in alphabetical order.
+func (s *SQLiteStore) GetNodeTags(nodeID string) ([]string, error) {
+	rows, err := s.db.Query("SELECT tag FROM node_tags WHERE node_id = ? ORDER BY tag", nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("query node tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("scan node tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := checkRowsErr(rows); err != nil {
+		return nil, fmt.Errorf("list node tags: %w", err)
+	}
+	return tags, nil
+}
+
+// ListNodesByTag returns the nodes carrying a tag, newest first.
+func (s *SQLiteStore) ListNodesByTag(tag string) ([]Node, error) {
+	rows, err := s.db.Query(`
+		SELECT n.id FROM nodes n
+		JOIN node_tags t ON t.node_id = n.id
+		WHERE t.tag = ?
+		ORDER BY n.created_at DESC
+	`, NormalizeTag(tag))
+	if err != nil {
+		return nil, fmt.Errorf("query nodes by tag: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan node id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := checkRowsErr(rows); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("list nodes by tag: %w", err)
+	}
+	_ = rows.Close()
+
+	nodes := make([]Node, 0, len(ids))
+	for _, id := range ids {
+		n, err := s.GetNode(id)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, *n)
+	}
+	return nodes, nil
+}
+
 // LinkNodes creates a relationship between two nodes.
 func (s *SQLiteStore) LinkNodes(from, to, relation string, confidence float64, properties map[string]any) error {
 	if confidence <= 0 {
@@ -1725,6 +2099,18 @@ func (s *SQLiteStore) GetAllNodeEdges() ([]NodeEdge, error) {
 	return edges, nil
 }
 
+// DeleteNodeEdge removes a single edge by ID.
+func (s *SQLiteStore) DeleteNodeEdge(id int64) error {
+	result, err := s.db.Exec("DELETE FROM node_edges WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete edge: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("edge not found: %d", id)
+	}
+	return nil
+}
+
 // === FTS5 Search Methods ===
 
 // FTSResult represents a full-text search result with relevance rank
@@ -1970,7 +2356,7 @@ func (s *SQLiteStore) SearchFTS(query string, limit int) ([]FTSResult, error) {
 
 	rows, err := s.db.Query(`
 		SELECT n.id, n.content, n.type, n.summary, n.source_agent, n.workspace, n.embedding, n.created_at,
-		       bm25(nodes_fts) as rank
+		       n.verification_status, n.confidence_score, bm25(nodes_fts) as rank
 		FROM nodes_fts f
 		JOIN nodes n ON f.id = n.id
 		WHERE nodes_fts MATCH ?
@@ -1990,12 +2376,17 @@ func (s *SQLiteStore) SearchFTS(query string, limit int) ([]FTSResult, error) {
 		var createdAt string
 		var nodeType, summary, sourceAgent, workspace sql.NullString
 		var embeddingBytes []byte
+		var verificationStatus sql.NullString
+		var confidenceScore sql.NullFloat64
 		var rank float64
 
-		if err := rows.Scan(&n.ID, &n.Content, &nodeType, &summary, &sourceAgent, &workspace, &embeddingBytes, &createdAt, &rank); err != nil {
+		if err := rows.Scan(&n.ID, &n.Content, &nodeType, &summary, &sourceAgent, &workspace, &embeddingBytes, &createdAt,
+			&verificationStatus, &confidenceScore, &rank); err != nil {
 			continue
 		}
 		populateNodeFromScan(&n, nodeType, summary, sourceAgent, workspace, createdAt, embeddingBytes)
+		n.VerificationStatus = verificationStatus.String
+		n.ConfidenceScore = confidenceScore.Float64
 		results = append(results, FTSResult{Node: n, Rank: rank})
 	}
 	if err := checkRowsErr(rows); err != nil {
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNodeTags_RoundTrip(t *testing.T) {
+	store, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	repo := NewRepository(store, nil)
+
+	node := &Node{Summary: "Tokens signed with HS256", Type: NodeTypeDecision, Content: "JWTs are signed with HS256", Tags: []string{"Security", " auth "}}
+	if err := repo.CreateNode(node); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+	if err := repo.AddNodeTag(node.ID, "perf"); err != nil {
+		t.Fatalf("AddNodeTag: %v", err)
+	}
+	if err := repo.AddNodeTag(node.ID, "PERF"); err != nil {
+		t.Fatalf("AddNodeTag duplicate: %v", err)
+	}
+
+	got, err := repo.GetNode(node.ID)
+	if err != nil {
+		t.Fatalf("GetNode: %v", err)
+	}
+	if want := []string{"auth", "perf", "security"}; !slices.Equal(got.Tags, want) {
+		t.Errorf("tags = %v, want %v", got.Tags, want)
+	}
+
+	if err := repo.RemoveNodeTag(node.ID, "Perf"); err != nil {
+		t.Fatalf("RemoveNodeTag: %v", err)
+	}
+	got, err = repo.GetNode(node.ID)
+	if err != nil {
+		t.Fatalf("GetNode: %v", err)
+	}
+	if want := []string{"auth", "security"}; !slices.Equal(got.Tags, want) {
+		t.Errorf("tags after remove = %v, want %v", got.Tags, want)
+	}
+
+	if err := repo.AddNodeTag("n-missing", "security"); err == nil {
+		t.Error("expected error tagging a missing node")
+	}
+
+	// Tags go with the node when it is deleted
+	if err := repo.DeleteNode(node.ID); err != nil {
+		t.Fatalf("DeleteNode: %v", err)
+	}
+	if tags, err := store.GetNodeTags(node.ID); err != nil || len(tags) != 0 {
+		t.Errorf("tags after delete = %v, err %v; want none", tags, err)
+	}
+}
@@ -14,9 +14,36 @@ import (
 	"github.com/josephgoksu/TaskWing/internal/task"
 )
 
+// taskStore holds the plan, phase, task and clarify session queries. They are
+// written in SQLite syntax and translated for the backend's dialect, so every
+// TaskBackend shares them.
+type taskStore struct {
+	db *sqlDB
+}
+
+func newTaskStore(db *sql.DB, dialect Dialect) *taskStore {
+	return &taskStore{db: &sqlDB{db: db, dialect: dialect}}
+}
+
+// TaskBackend is a database that plans, phases, tasks and clarify sessions
+// can be stored in: SQLiteStore or PostgresStore.
+type TaskBackend interface {
+	task.Repository
+	taskBackend() *taskStore
+}
+
+func (s *taskStore) taskBackend() *taskStore {
+	return s
+}
+
+// Dialect returns the SQL dialect of the backend.
+func (s *taskStore) Dialect() Dialect {
+	return s.db.dialect
+}
+
 // rollbackWithLog attempts rollback and logs non-ErrTxDone errors at warn level.
 // This ensures transaction cleanup failures are visible without masking the original error.
-func rollbackWithLog(tx *sql.Tx, context string) {
+func rollbackWithLog(tx interface{ Rollback() error }, context string) {
 	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
 		log.Printf("[WARN] rollback failed (%s): %v", context, err)
 	}
@@ -44,7 +71,7 @@ type txExecutor interface {
 
 // insertTaskTx inserts a task and its relations within a transaction.
 // This is the SINGLE source of truth for task insertion logic.
-func insertTaskTx(tx txExecutor, t *task.Task) error {
+func insertTaskTx(tx *sqlTx, t *task.Task) error {
 	acJSON, err := json.Marshal(t.AcceptanceCriteria)
 	if err != nil {
 		return fmt.Errorf("marshal acceptance_criteria for task %s: %w", t.ID, err)
@@ -85,28 +112,28 @@ func insertTaskTx(tx txExecutor, t *task.Task) error {
 			id, plan_id, phase_id, title, description,
 			acceptance_criteria, validation_steps,
 			status, priority, complexity, assigned_agent, parent_task_id, context_summary,
-			scope, keywords, suggested_ask_queries,
+			scope, keywords, suggested_ask_queries, enrichment_errors,
 			claimed_by, claimed_at, completed_at, completion_summary, files_modified, expected_files,
-			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			skip_audit, sort_order, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, t.ID, t.PlanID, phaseID, t.Title, t.Description,
 		string(acJSON), string(vsJSON),
 		t.Status, t.Priority, t.Complexity, t.AssignedAgent, parentID, t.ContextSummary,
-		t.Scope, string(keywordsJSON), string(queriesJSON),
+		t.Scope, string(keywordsJSON), string(queriesJSON), t.EnrichmentErrors,
 		t.ClaimedBy, nullTimeString(t.ClaimedAt), nullTimeString(t.CompletedAt), t.CompletionSummary, string(filesJSON), string(expectedFilesJSON),
-		t.CreatedAt.Format(time.RFC3339), t.UpdatedAt.Format(time.RFC3339))
+		boolToInt(t.SkipAudit), t.SortOrder, t.CreatedAt.Format(time.RFC3339), t.UpdatedAt.Format(time.RFC3339))
 	if err != nil {
 		return fmt.Errorf("insert task %s: %w", t.Title, err)
 	}
 
 	for _, depID := range t.Dependencies {
-		if _, err := tx.Exec(`INSERT OR IGNORE INTO task_dependencies (task_id, depends_on) VALUES (?, ?)`, t.ID, depID); err != nil {
+		if _, err := tx.Exec(tx.dialect.insertIgnore(`task_dependencies (task_id, depends_on) VALUES (?, ?)`), t.ID, depID); err != nil {
 			return fmt.Errorf("insert dependency %s: %w", depID, err)
 		}
 	}
 
 	for _, nodeID := range t.ContextNodes {
-		if _, err := tx.Exec(`INSERT OR IGNORE INTO task_node_links (task_id, node_id, link_type) VALUES (?, ?, 'context')`, t.ID, nodeID); err != nil {
+		if _, err := tx.Exec(tx.dialect.insertIgnore(`task_node_links (task_id, node_id, link_type) VALUES (?, ?, 'context')`), t.ID, nodeID); err != nil {
 			return fmt.Errorf("insert node link %s: %w", nodeID, err)
 		}
 	}
@@ -132,7 +159,7 @@ func prepareTask(t *task.Task, planID string, now time.Time) {
 // === Plan CRUD ===
 
 // CreatePlan creates a new plan in the database along with its tasks (atomically).
-func (s *SQLiteStore) CreatePlan(p *task.Plan) error {
+func (s *taskStore) CreatePlan(p *task.Plan) error {
 	if p.ID == "" {
 		p.ID = "plan-" + uuid.New().String()[:8]
 	}
@@ -183,7 +210,7 @@ func (s *SQLiteStore) CreatePlan(p *task.Plan) error {
 }
 
 // GetPlan retrieves a plan by ID, including its tasks.
-func (s *SQLiteStore) GetPlan(id string) (*task.Plan, error) {
+func (s *taskStore) GetPlan(id string) (*task.Plan, error) {
 	var p task.Plan
 	var createdAt, updatedAt string
 	var lastAuditReport, draftStateJSON, generationMode sql.NullString
@@ -227,21 +254,58 @@ func (s *SQLiteStore) GetPlan(id string) (*task.Plan, error) {
 	return &p, nil
 }
 
-// ListPlans returns all plans with task counts (but not full task data).
-func (s *SQLiteStore) ListPlans() ([]task.Plan, error) {
-	// Use a subquery to get task counts efficiently without loading all task data
-	rows, err := s.db.Query(`
+// ListPlans returns one page of plans matching filter, with task counts (but
+// not full task data). Archived plans are excluded unless filter.Status is
+// archived or filter.IncludeArchived is set.
+func (s *taskStore) ListPlans(filter task.PlanFilter) ([]task.Plan, error) {
+	var where []string
+	var args []any
+	switch {
+	case filter.Status != "":
+		where = append(where, "p.status = ?")
+		args = append(args, filter.Status)
+	case !filter.IncludeArchived:
+		where = append(where, "p.status != ?")
+		args = append(args, task.PlanStatusArchived)
+	}
+
+	query := `
 		SELECT p.id, p.goal, p.enriched_goal, p.status, p.draft_state, p.generation_mode,
 		       p.created_at, p.updated_at, p.last_audit_report,
 		       (SELECT COUNT(*) FROM tasks t WHERE t.plan_id = p.id) as task_count
-		FROM plans p
-		ORDER BY p.created_at DESC
-	`)
+		FROM plans p`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	switch filter.OrderBy {
+	case "", task.PlanOrderCreated:
+		query += " ORDER BY p.created_at DESC, p.id"
+	case task.PlanOrderUpdated:
+		query += " ORDER BY p.updated_at DESC, p.id"
+	default:
+		return nil, fmt.Errorf("invalid plan order %q: must be %s or %s", filter.OrderBy, task.PlanOrderCreated, task.PlanOrderUpdated)
+	}
+	if filter.Limit > 0 || filter.Offset > 0 {
+		// OFFSET needs a LIMIT; the dialect's no-limit value returns every row
+		var limit any = filter.Limit
+		if filter.Limit <= 0 {
+			limit = s.db.dialect.noLimit
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, max(filter.Offset, 0))
+	}
+
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query plans: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
+	return scanPlanRows(rows)
+}
+
+// scanPlanRows scans plan list rows (plan columns plus task_count).
+func scanPlanRows(rows *sql.Rows) ([]task.Plan, error) {
 	var plans []task.Plan
 	for rows.Next() {
 		var p task.Plan
@@ -283,7 +347,7 @@ func (s *SQLiteStore) ListPlans() ([]task.Plan, error) {
 }
 
 // UpdatePlan updates mutable plan fields.
-func (s *SQLiteStore) UpdatePlan(id string, goal, enrichedGoal string, status task.PlanStatus) error {
+func (s *taskStore) UpdatePlan(id string, goal, enrichedGoal string, status task.PlanStatus) error {
 	if id == "" {
 		return fmt.Errorf("plan id is required")
 	}
@@ -334,7 +398,7 @@ func (s *SQLiteStore) UpdatePlan(id string, goal, enrichedGoal string, status ta
 // This is called by the audit agent after verification completes.
 // UpdatePlanAuditReport updates the audit report and status for a plan.
 // Also records the audit in history.
-func (s *SQLiteStore) UpdatePlanAuditReport(id string, status task.PlanStatus, auditReportJSON string) error {
+func (s *taskStore) UpdatePlanAuditReport(id string, status task.PlanStatus, auditReportJSON string) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -372,7 +436,7 @@ func (s *SQLiteStore) UpdatePlanAuditReport(id string, status task.PlanStatus, a
 }
 
 // DeletePlan removes a plan and its tasks (via FK cascade).
-func (s *SQLiteStore) DeletePlan(id string) error {
+func (s *taskStore) DeletePlan(id string) error {
 	res, err := s.db.Exec(`DELETE FROM plans WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("delete plan: %w", err)
@@ -387,10 +451,73 @@ func (s *SQLiteStore) DeletePlan(id string) error {
 	return nil
 }
 
+// MergePlans moves every phase and task of the source plan into the target plan,
+// then deletes the source, all in one transaction. Task and phase IDs are unique
+// across plans and are kept, so dependencies, knowledge links, and claims carry over
+// unchanged. Source phases are ordered after the target's phases. If the source was
+// the active plan, the target becomes active.
+func (s *taskStore) MergePlans(targetID, sourceID string) error {
+	if targetID == "" || sourceID == "" {
+		return fmt.Errorf("target and source plan ids are required")
+	}
+	if targetID == sourceID {
+		return fmt.Errorf("cannot merge plan %s into itself", targetID)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { rollbackWithLog(tx, "task_store") }()
+
+	var targetStatus, sourceStatus task.PlanStatus
+	if err := tx.QueryRow(`SELECT status FROM plans WHERE id = ?`, targetID).Scan(&targetStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("plan not found: %s", targetID)
+		}
+		return fmt.Errorf("query target plan: %w", err)
+	}
+	if err := tx.QueryRow(`SELECT status FROM plans WHERE id = ?`, sourceID).Scan(&sourceStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("plan not found: %s", sourceID)
+		}
+		return fmt.Errorf("query source plan: %w", err)
+	}
+
+	var phaseOffset int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(order_index) + 1, 0) FROM phases WHERE plan_id = ?`, targetID).Scan(&phaseOffset); err != nil {
+		return fmt.Errorf("query target phases: %w", err)
+	}
+
+	nowStr := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec(`
+		UPDATE phases SET plan_id = ?, order_index = order_index + ?, updated_at = ?
+		WHERE plan_id = ?
+	`, targetID, phaseOffset, nowStr, sourceID); err != nil {
+		return fmt.Errorf("move phases: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE tasks SET plan_id = ?, updated_at = ? WHERE plan_id = ?`, targetID, nowStr, sourceID); err != nil {
+		return fmt.Errorf("move tasks: %w", err)
+	}
+
+	newStatus := targetStatus
+	if sourceStatus == task.PlanStatusActive {
+		newStatus = task.PlanStatusActive
+	}
+	if _, err := tx.Exec(`UPDATE plans SET status = ?, updated_at = ? WHERE id = ?`, newStatus, nowStr, targetID); err != nil {
+		return fmt.Errorf("update target plan: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM plans WHERE id = ?`, sourceID); err != nil {
+		return fmt.Errorf("delete source plan: %w", err)
+	}
+
+	return tx.Commit()
+}
+
 // === Clarify Session Persistence ===
 
 // CreateClarifySession creates a new persisted clarify session.
-func (s *SQLiteStore) CreateClarifySession(session *task.ClarifySession) error {
+func (s *taskStore) CreateClarifySession(session *task.ClarifySession) error {
 	if session == nil {
 		return fmt.Errorf("clarify session is required")
 	}
@@ -438,7 +565,7 @@ func (s *SQLiteStore) CreateClarifySession(session *task.ClarifySession) error {
 }
 
 // GetClarifySession retrieves a clarify session by ID.
-func (s *SQLiteStore) GetClarifySession(id string) (*task.ClarifySession, error) {
+func (s *taskStore) GetClarifySession(id string) (*task.ClarifySession, error) {
 	var session task.ClarifySession
 	var createdAt, updatedAt string
 	var currentQuestionsJSON sql.NullString
@@ -475,7 +602,7 @@ func (s *SQLiteStore) GetClarifySession(id string) (*task.ClarifySession, error)
 }
 
 // UpdateClarifySession updates mutable fields for a clarify session.
-func (s *SQLiteStore) UpdateClarifySession(session *task.ClarifySession) error {
+func (s *taskStore) UpdateClarifySession(session *task.ClarifySession) error {
 	if session == nil {
 		return fmt.Errorf("clarify session is required")
 	}
@@ -511,7 +638,7 @@ func (s *SQLiteStore) UpdateClarifySession(session *task.ClarifySession) error {
 }
 
 // CreateClarifyTurn persists one round of clarify output.
-func (s *SQLiteStore) CreateClarifyTurn(turn *task.ClarifyTurn) error {
+func (s *taskStore) CreateClarifyTurn(turn *task.ClarifyTurn) error {
 	if turn == nil {
 		return fmt.Errorf("clarify turn is required")
 	}
@@ -550,7 +677,7 @@ func (s *SQLiteStore) CreateClarifyTurn(turn *task.ClarifyTurn) error {
 }
 
 // ListClarifyTurns returns all clarify turns for a session in round order.
-func (s *SQLiteStore) ListClarifyTurns(sessionID string) ([]task.ClarifyTurn, error) {
+func (s *taskStore) ListClarifyTurns(sessionID string) ([]task.ClarifyTurn, error) {
 	rows, err := s.db.Query(`
 		SELECT id, session_id, round_index, questions, answers, goal_summary, enriched_goal,
 		       is_ready_to_plan, auto_answered, max_rounds_reached, context_summary, created_at
@@ -601,7 +728,7 @@ func (s *SQLiteStore) ListClarifyTurns(sessionID string) ([]task.ClarifyTurn, er
 // === Task CRUD ===
 
 // CreateTask adds a new task to a plan.
-func (s *SQLiteStore) CreateTask(t *task.Task) error {
+func (s *taskStore) CreateTask(t *task.Task) error {
 	prepareTask(t, t.PlanID, time.Now().UTC())
 
 	tx, err := s.db.Begin()
@@ -630,6 +757,8 @@ func scanTaskRow(row taskRowScanner) (task.Task, error) {
 	var parentID sql.NullString
 	var scope, keywordsJSON, queriesJSON, complexity sql.NullString
 	var claimedBy, claimedAt, completedAt, completionSummary, filesJSON, expectedFilesJSON, gitBaselineJSON sql.NullString
+	var enrichmentErrors, skipAudit, sortOrder sql.NullInt64
+	var criteriaJSON sql.NullString
 	var createdAt, updatedAt string
 
 	err := row.Scan(
@@ -637,7 +766,7 @@ func scanTaskRow(row taskRowScanner) (task.Task, error) {
 		&t.Status, &t.Priority, &complexity, &t.AssignedAgent, &parentID, &t.ContextSummary,
 		&scope, &keywordsJSON, &queriesJSON,
 		&claimedBy, &claimedAt, &completedAt, &completionSummary, &filesJSON, &expectedFilesJSON, &gitBaselineJSON,
-		&createdAt, &updatedAt,
+		&enrichmentErrors, &criteriaJSON, &skipAudit, &sortOrder, &createdAt, &updatedAt,
 	)
 	if err != nil {
 		return t, err
@@ -650,6 +779,9 @@ func scanTaskRow(row taskRowScanner) (task.Task, error) {
 	t.Scope = scope.String
 	t.ClaimedBy = claimedBy.String
 	t.CompletionSummary = completionSummary.String
+	t.EnrichmentErrors = int(enrichmentErrors.Int64)
+	t.SkipAudit = skipAudit.Int64 != 0
+	t.SortOrder = int(sortOrder.Int64)
 	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
@@ -695,6 +827,11 @@ func scanTaskRow(row taskRowScanner) (task.Task, error) {
 			slog.Warn("corrupt git_baseline JSON", "task", t.ID, "error", err)
 		}
 	}
+	if criteriaJSON.Valid && criteriaJSON.String != "" {
+		if err := json.Unmarshal([]byte(criteriaJSON.String), &t.CriteriaVerification); err != nil {
+			slog.Warn("corrupt criteria_verification JSON", "task", t.ID, "error", err)
+		}
+	}
 
 	return t, nil
 }
@@ -703,10 +840,10 @@ const taskSelectColumns = `id, plan_id, phase_id, title, description, acceptance
        status, priority, complexity, assigned_agent, parent_task_id, context_summary,
        scope, keywords, suggested_ask_queries,
        claimed_by, claimed_at, completed_at, completion_summary, files_modified, expected_files, git_baseline,
-       created_at, updated_at`
+       enrichment_errors, criteria_verification, skip_audit, sort_order, created_at, updated_at`
 
 // GetTask retrieves a task by ID.
-func (s *SQLiteStore) GetTask(id string) (*task.Task, error) {
+func (s *taskStore) GetTask(id string) (*task.Task, error) {
 	row := s.db.QueryRow(`SELECT `+taskSelectColumns+` FROM tasks WHERE id = ?`, id)
 
 	t, err := scanTaskRow(row)
@@ -735,7 +872,7 @@ func (s *SQLiteStore) GetTask(id string) (*task.Task, error) {
 }
 
 // ListTasks returns all tasks for a plan.
-func (s *SQLiteStore) ListTasks(planID string) ([]task.Task, error) {
+func (s *taskStore) ListTasks(planID string) ([]task.Task, error) {
 	rows, err := s.db.Query(`SELECT `+taskSelectColumns+` FROM tasks WHERE plan_id = ? ORDER BY created_at`, planID)
 	if err != nil {
 		return nil, fmt.Errorf("query tasks: %w", err)
@@ -770,7 +907,7 @@ func (s *SQLiteStore) ListTasks(planID string) ([]task.Task, error) {
 }
 
 // UpdateTaskStatus updates a task's status and updated_at timestamp.
-func (s *SQLiteStore) UpdateTaskStatus(id string, status task.TaskStatus) error {
+func (s *taskStore) UpdateTaskStatus(id string, status task.TaskStatus) error {
 	if id == "" {
 		return fmt.Errorf("task id is required")
 	}
@@ -792,8 +929,55 @@ func (s *SQLiteStore) UpdateTaskStatus(id string, status task.TaskStatus) error
 	return nil
 }
 
+// UpdateTaskContextSummary replaces a task's early-bound context summary and
+// the number of enrichment queries that failed producing it.
+func (s *taskStore) UpdateTaskContextSummary(id, summary string, enrichmentErrors int) error {
+	if id == "" {
+		return fmt.Errorf("task id is required")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	res, err := s.db.Exec(`UPDATE tasks SET context_summary = ?, enrichment_errors = ?, updated_at = ? WHERE id = ?`,
+		summary, enrichmentErrors, now, id)
+	if err != nil {
+		return fmt.Errorf("update task context summary: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update task context summary rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("task not found: %s", id)
+	}
+	return nil
+}
+
+// UpdateTaskCriteriaVerification stores the per-criterion verification results of a task.
+func (s *taskStore) UpdateTaskCriteriaVerification(id string, results []task.CriterionVerification) error {
+	if id == "" {
+		return fmt.Errorf("task id is required")
+	}
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("marshal criteria verification: %w", err)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	res, err := s.db.Exec(`UPDATE tasks SET criteria_verification = ?, updated_at = ? WHERE id = ?`,
+		string(resultsJSON), now, id)
+	if err != nil {
+		return fmt.Errorf("update task criteria verification: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update task criteria verification rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("task not found: %s", id)
+	}
+	return nil
+}
+
 // DeleteTask removes a task and its links.
-func (s *SQLiteStore) DeleteTask(id string) error {
+func (s *taskStore) DeleteTask(id string) error {
 	res, err := s.db.Exec(`DELETE FROM tasks WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("delete task: %w", err)
@@ -808,21 +992,59 @@ func (s *SQLiteStore) DeleteTask(id string) error {
 	return nil
 }
 
+// SplitTask creates subtasks of a pending task and makes the parent depend on
+// each of them, atomically: either every subtask is created and linked or
+// none is. The subtasks' ParentTaskID is set to parentID.
+func (s *taskStore) SplitTask(parentID string, subtasks []task.Task) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { rollbackWithLog(tx, "task_store") }()
+
+	var status task.TaskStatus
+	err = tx.QueryRow(`SELECT status FROM tasks WHERE id = ?`, parentID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("task not found: %s", parentID)
+	}
+	if err != nil {
+		return fmt.Errorf("get task status: %w", err)
+	}
+	if status != task.StatusPending {
+		return fmt.Errorf("task %s is %s; only pending tasks can be split", parentID, status)
+	}
+
+	now := time.Now().UTC()
+	for i := range subtasks {
+		sub := &subtasks[i]
+		sub.ParentTaskID = parentID
+		prepareTask(sub, sub.PlanID, now)
+		if err := insertTaskTx(tx, sub); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(tx.dialect.insertIgnore(`task_dependencies (task_id, depends_on) VALUES (?, ?)`), parentID, sub.ID); err != nil {
+			return fmt.Errorf("link subtask %s: %w", sub.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // AddDependency adds a dependency relationship between two tasks.
-func (s *SQLiteStore) AddDependency(taskID, dependsOn string) error {
-	_, err := s.db.Exec(`INSERT OR IGNORE INTO task_dependencies (task_id, depends_on) VALUES (?, ?)`, taskID, dependsOn)
+func (s *taskStore) AddDependency(taskID, dependsOn string) error {
+	_, err := s.db.Exec(s.db.dialect.insertIgnore(`task_dependencies (task_id, depends_on) VALUES (?, ?)`), taskID, dependsOn)
 	return err
 }
 
 // RemoveDependency removes a dependency relationship between two tasks.
-func (s *SQLiteStore) RemoveDependency(taskID, dependsOn string) error {
+func (s *taskStore) RemoveDependency(taskID, dependsOn string) error {
 	_, err := s.db.Exec(`DELETE FROM task_dependencies WHERE task_id = ? AND depends_on = ?`, taskID, dependsOn)
 	return err
 }
 
 // === Task Helpers ===
 
-func (s *SQLiteStore) GetTaskDependencies(taskID string) ([]string, error) {
+func (s *taskStore) GetTaskDependencies(taskID string) ([]string, error) {
 	rows, err := s.db.Query(`SELECT depends_on FROM task_dependencies WHERE task_id = ?`, taskID)
 	if err != nil {
 		return nil, fmt.Errorf("query deps: %w", err)
@@ -845,7 +1067,7 @@ func (s *SQLiteStore) GetTaskDependencies(taskID string) ([]string, error) {
 
 // batchGetTaskDependencies fetches dependencies for multiple tasks in a single query.
 // Returns a map of task_id -> []depends_on. Fixes the N+1 query pattern in ListTasks.
-func (s *SQLiteStore) batchGetTaskDependencies(taskIDs []string) (map[string][]string, error) {
+func (s *taskStore) batchGetTaskDependencies(taskIDs []string) (map[string][]string, error) {
 	if len(taskIDs) == 0 {
 		return make(map[string][]string), nil
 	}
@@ -879,7 +1101,7 @@ func (s *SQLiteStore) batchGetTaskDependencies(taskIDs []string) (map[string][]s
 	return result, nil
 }
 
-func (s *SQLiteStore) GetTaskContextNodes(taskID string) ([]string, error) {
+func (s *taskStore) GetTaskContextNodes(taskID string) ([]string, error) {
 	rows, err := s.db.Query(`SELECT node_id FROM task_node_links WHERE task_id = ? AND link_type='context'`, taskID)
 	if err != nil {
 		return nil, fmt.Errorf("query context nodes: %w", err)
@@ -900,10 +1122,9 @@ func (s *SQLiteStore) GetTaskContextNodes(taskID string) ([]string, error) {
 	return nodes, nil
 }
 
-func (s *SQLiteStore) LinkTaskToNode(taskID, nodeID, linkType string) error {
-	_, err := s.db.Exec(`
-		INSERT OR IGNORE INTO task_node_links (task_id, node_id, link_type) VALUES (?, ?, ?)
-	`, taskID, nodeID, linkType)
+func (s *taskStore) LinkTaskToNode(taskID, nodeID, linkType string) error {
+	_, err := s.db.Exec(s.db.dialect.insertIgnore(`task_node_links (task_id, node_id, link_type) VALUES (?, ?, ?)`),
+		taskID, nodeID, linkType)
 	return err
 }
 
@@ -912,23 +1133,40 @@ func (s *SQLiteStore) LinkTaskToNode(taskID, nodeID, linkType string) error {
 // GetNextTask returns the highest-urgency pending task from a plan whose dependencies are all completed.
 // Lower numeric values indicate higher urgency (e.g., 10 before 90).
 // Returns nil if no pending tasks exist or all pending tasks have incomplete dependencies.
-func (s *SQLiteStore) GetNextTask(planID string) (*task.Task, error) {
+func (s *taskStore) GetNextTask(planID string) (*task.Task, error) {
+	return s.GetNextTaskForAgent(planID, "")
+}
+
+// GetNextTaskForAgent is GetNextTask restricted to tasks assigned to agent
+// (case-insensitive). task.AgentUnassigned selects tasks with no assigned
+// agent; an empty agent matches every task.
+func (s *taskStore) GetNextTaskForAgent(planID, agent string) (*task.Task, error) {
 	// Find pending tasks that have NO incomplete dependencies
 	// A task is ready if:
 	// 1. It has no dependencies, OR
 	// 2. All its dependencies have status = 'completed'
-	row := s.db.QueryRow(`
-		SELECT `+taskSelectColumns+`
+	query := `
+		SELECT ` + taskSelectColumns + `
 		FROM tasks t
 		WHERE t.plan_id = ? AND t.status = ?
 		AND NOT EXISTS (
 			SELECT 1 FROM task_dependencies td
 			JOIN tasks dep ON dep.id = td.depends_on
 			WHERE td.task_id = t.id AND dep.status NOT IN (?, ?)
-		)
-		ORDER BY t.priority ASC, t.created_at ASC
-		LIMIT 1
-	`, planID, task.StatusPending, task.StatusCompleted, task.StatusSkipped)
+		)`
+	args := []any{planID, task.StatusPending, task.StatusCompleted, task.StatusSkipped}
+	switch agent = strings.TrimSpace(agent); {
+	case agent == "":
+	case strings.EqualFold(agent, task.AgentUnassigned):
+		query += ` AND COALESCE(t.assigned_agent, '') = ''`
+	default:
+		query += ` AND LOWER(t.assigned_agent) = LOWER(?)`
+		args = append(args, agent)
+	}
+	query += `
+		ORDER BY t.priority ASC, t.sort_order ASC, t.created_at ASC
+		LIMIT 1`
+	row := s.db.QueryRow(query, args...)
 
 	t, err := scanTaskRow(row)
 	if err == sql.ErrNoRows {
@@ -957,7 +1195,7 @@ func (s *SQLiteStore) GetNextTask(planID string) (*task.Task, error) {
 
 // GetCurrentTask returns the in_progress task claimed by a session.
 // Returns nil if no task is currently claimed by this session.
-func (s *SQLiteStore) GetCurrentTask(sessionID string) (*task.Task, error) {
+func (s *taskStore) GetCurrentTask(sessionID string) (*task.Task, error) {
 	row := s.db.QueryRow(`
 		SELECT `+taskSelectColumns+`
 		FROM tasks
@@ -991,7 +1229,7 @@ func (s *SQLiteStore) GetCurrentTask(sessionID string) (*task.Task, error) {
 
 // GetAnyInProgressTask returns any in_progress task from a plan (regardless of session).
 // Useful for resuming work or detecting stuck tasks.
-func (s *SQLiteStore) GetAnyInProgressTask(planID string) (*task.Task, error) {
+func (s *taskStore) GetAnyInProgressTask(planID string) (*task.Task, error) {
 	row := s.db.QueryRow(`
 		SELECT `+taskSelectColumns+`
 		FROM tasks
@@ -1019,7 +1257,7 @@ func (s *SQLiteStore) GetAnyInProgressTask(planID string) (*task.Task, error) {
 
 // ClaimTask marks a task as in_progress and assigns it to a session.
 // Fails if task is not in pending status.
-func (s *SQLiteStore) ClaimTask(taskID, sessionID string) error {
+func (s *taskStore) ClaimTask(taskID, sessionID string) error {
 	if taskID == "" {
 		return fmt.Errorf("task id is required")
 	}
@@ -1060,7 +1298,7 @@ func (s *SQLiteStore) ClaimTask(taskID, sessionID string) error {
 
 // SetGitBaseline records the git state when a task was claimed.
 // This allows accurate comparison of what changed during task execution.
-func (s *SQLiteStore) SetGitBaseline(taskID string, baseline []string) error {
+func (s *taskStore) SetGitBaseline(taskID string, baseline []string) error {
 	if taskID == "" {
 		return fmt.Errorf("task id is required")
 	}
@@ -1086,7 +1324,7 @@ func (s *SQLiteStore) SetGitBaseline(taskID string, baseline []string) error {
 }
 
 // CompleteTask marks a task as completed with summary and files modified.
-func (s *SQLiteStore) CompleteTask(taskID, summary string, filesModified []string) error {
+func (s *taskStore) CompleteTask(taskID, summary string, filesModified []string) error {
 	if taskID == "" {
 		return fmt.Errorf("task id is required")
 	}
@@ -1128,7 +1366,7 @@ func (s *SQLiteStore) CompleteTask(taskID, summary string, filesModified []strin
 
 // SkipTask marks a task as skipped with an optional reason.
 // Allows skipping from pending or in_progress status.
-func (s *SQLiteStore) SkipTask(taskID, reason string) error {
+func (s *taskStore) SkipTask(taskID, reason string) error {
 	if taskID == "" {
 		return fmt.Errorf("task id is required")
 	}
@@ -1161,9 +1399,68 @@ func (s *SQLiteStore) SkipTask(taskID, reason string) error {
 	return nil
 }
 
+// ReorderTasks reassigns priorities so that GetNextTask follows orderedIDs.
+// orderedIDs must contain every task of the plan exactly once. Priorities are
+// spaced evenly within the valid 0-100 range (see task.RankPriority) and
+// sort_order records each task's position, so plans with more than 100 tasks
+// keep their order where priorities tie.
+func (s *taskStore) ReorderTasks(planID string, orderedIDs []string) error {
+	if planID == "" {
+		return fmt.Errorf("plan id is required")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { rollbackWithLog(tx, "task_store") }()
+
+	rows, err := tx.Query(`SELECT id FROM tasks WHERE plan_id = ?`, planID)
+	if err != nil {
+		return fmt.Errorf("query plan tasks: %w", err)
+	}
+	planTasks := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan task id: %w", err)
+		}
+		planTasks[id] = true
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate plan tasks: %w", err)
+	}
+
+	if len(orderedIDs) != len(planTasks) {
+		return fmt.Errorf("reorder must list all %d tasks of plan %s, got %d", len(planTasks), planID, len(orderedIDs))
+	}
+	seen := make(map[string]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if !planTasks[id] {
+			return fmt.Errorf("task %s does not belong to plan %s", id, planID)
+		}
+		if seen[id] {
+			return fmt.Errorf("task %s listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	nowStr := time.Now().UTC().Format(time.RFC3339)
+	for i, id := range orderedIDs {
+		priority := task.RankPriority(i, len(orderedIDs))
+		if _, err := tx.Exec(`UPDATE tasks SET priority = ?, sort_order = ?, updated_at = ? WHERE id = ?`, priority, i, nowStr, id); err != nil {
+			return fmt.Errorf("update priority for %s: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // SearchPlans returns plans matching the query and status (with task counts).
 // Query searches in goal and enriched_goal.
-func (s *SQLiteStore) SearchPlans(query string, status task.PlanStatus) ([]task.Plan, error) {
+func (s *taskStore) SearchPlans(query string, status task.PlanStatus) ([]task.Plan, error) {
 	q := `SELECT p.id, p.goal, p.enriched_goal, p.status, p.draft_state, p.generation_mode,
 	             p.created_at, p.updated_at,
 	             (SELECT COUNT(*) FROM tasks t WHERE t.plan_id = p.id) as task_count
@@ -1176,7 +1473,7 @@ func (s *SQLiteStore) SearchPlans(query string, status task.PlanStatus) ([]task.
 	}
 
 	if query != "" {
-		q += " AND (p.goal LIKE ? OR p.enriched_goal LIKE ?)"
+		q += fmt.Sprintf(" AND (p.goal %[1]s ? OR p.enriched_goal %[1]s ?)", s.db.dialect.likeOp)
 		wildcard := "%" + query + "%"
 		args = append(args, wildcard, wildcard)
 	}
@@ -1225,7 +1522,7 @@ func (s *SQLiteStore) SearchPlans(query string, status task.PlanStatus) ([]task.
 
 // GetActivePlan returns the currently active plan (status = active).
 // Returns nil if no active plan exists.
-func (s *SQLiteStore) GetActivePlan() (*task.Plan, error) {
+func (s *taskStore) GetActivePlan() (*task.Plan, error) {
 	var p task.Plan
 	var createdAt, updatedAt string
 	var lastAuditReport, draftStateJSON, generationMode sql.NullString
@@ -1272,7 +1569,7 @@ func (s *SQLiteStore) GetActivePlan() (*task.Plan, error) {
 }
 
 // SetActivePlan atomically sets the given plan as active and deactivates others.
-func (s *SQLiteStore) SetActivePlan(id string) error {
+func (s *taskStore) SetActivePlan(id string) error {
 	if id == "" {
 		return fmt.Errorf("plan id is required")
 	}
@@ -1283,6 +1580,18 @@ func (s *SQLiteStore) SetActivePlan(id string) error {
 	}
 	defer func() { rollbackWithLog(tx, "task_store") }()
 
+	// Archived plans are hidden from default listings and must be unarchived first
+	var status task.PlanStatus
+	if err := tx.QueryRow(`SELECT status FROM plans WHERE id = ?`, id).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("plan not found: %s", id)
+		}
+		return fmt.Errorf("query plan status: %w", err)
+	}
+	if status == task.PlanStatusArchived {
+		return fmt.Errorf("plan %s is archived; unarchive it before activating", id)
+	}
+
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	// 1. Demote any currently active plans to 'draft'
@@ -1320,7 +1629,7 @@ func (s *SQLiteStore) SetActivePlan(id string) error {
 
 // FindTaskIDsByPrefix returns all task IDs that start with the given prefix.
 // Results are ordered by ID for consistent output.
-func (s *SQLiteStore) FindTaskIDsByPrefix(prefix string) ([]string, error) {
+func (s *taskStore) FindTaskIDsByPrefix(prefix string) ([]string, error) {
 	rows, err := s.db.Query(`SELECT id FROM tasks WHERE id LIKE ? ORDER BY id`, prefix+"%")
 	if err != nil {
 		return nil, fmt.Errorf("find task IDs by prefix: %w", err)
@@ -1344,7 +1653,7 @@ func (s *SQLiteStore) FindTaskIDsByPrefix(prefix string) ([]string, error) {
 
 // FindPlanIDsByPrefix returns all plan IDs that start with the given prefix.
 // Results are ordered by ID for consistent output.
-func (s *SQLiteStore) FindPlanIDsByPrefix(prefix string) ([]string, error) {
+func (s *taskStore) FindPlanIDsByPrefix(prefix string) ([]string, error) {
 	rows, err := s.db.Query(`SELECT id FROM plans WHERE id LIKE ? ORDER BY id`, prefix+"%")
 	if err != nil {
 		return nil, fmt.Errorf("find plan IDs by prefix: %w", err)
@@ -1369,18 +1678,18 @@ func (s *SQLiteStore) FindPlanIDsByPrefix(prefix string) ([]string, error) {
 // === Phase CRUD ===
 
 // phaseSelectColumns defines the columns to select for phase queries.
-const phaseSelectColumns = `id, plan_id, title, description, rationale, order_index, status, expected_tasks, created_at, updated_at`
+const phaseSelectColumns = `id, plan_id, title, description, rationale, order_index, status, expected_tasks, created_at, updated_at, expand_attempts, depends_on`
 
 // scanPhaseRow scans a phase row into a Phase struct.
 func scanPhaseRow(row taskRowScanner) (task.Phase, error) {
 	var p task.Phase
-	var desc, rationale sql.NullString
+	var desc, rationale, attemptsJSON, dependsJSON sql.NullString
 	var createdAt, updatedAt string
 
 	err := row.Scan(
 		&p.ID, &p.PlanID, &p.Title, &desc, &rationale,
 		&p.OrderIndex, &p.Status, &p.ExpectedTasks,
-		&createdAt, &updatedAt,
+		&createdAt, &updatedAt, &attemptsJSON, &dependsJSON,
 	)
 	if err != nil {
 		return p, err
@@ -1390,12 +1699,35 @@ func scanPhaseRow(row taskRowScanner) (task.Phase, error) {
 	p.Rationale = rationale.String
 	p.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	p.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	if attemptsJSON.Valid && attemptsJSON.String != "" {
+		if err := json.Unmarshal([]byte(attemptsJSON.String), &p.ExpandAttempts); err != nil {
+			slog.Warn("failed to parse phase expand attempts", "phase_id", p.ID, "error", err)
+		}
+	}
+	if dependsJSON.Valid && dependsJSON.String != "" {
+		if err := json.Unmarshal([]byte(dependsJSON.String), &p.DependsOn); err != nil {
+			slog.Warn("failed to parse phase dependencies", "phase_id", p.ID, "error", err)
+		}
+	}
 
 	return p, nil
 }
 
+// phaseDependsOnValue returns the depends_on column value for p: a JSON array,
+// or nil when the phase has no dependencies.
+func phaseDependsOnValue(p *task.Phase) (any, error) {
+	if len(p.DependsOn) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(p.DependsOn)
+	if err != nil {
+		return nil, fmt.Errorf("marshal depends_on for phase %s: %w", p.ID, err)
+	}
+	return string(data), nil
+}
+
 // CreatePhase adds a new phase to a plan.
-func (s *SQLiteStore) CreatePhase(p *task.Phase) error {
+func (s *taskStore) CreatePhase(p *task.Phase) error {
 	if p.ID == "" {
 		p.ID = "phase-" + uuid.New().String()[:8]
 	}
@@ -1407,12 +1739,16 @@ func (s *SQLiteStore) CreatePhase(p *task.Phase) error {
 		p.CreatedAt = now
 	}
 	p.UpdatedAt = now
+	dependsOn, err := phaseDependsOnValue(p)
+	if err != nil {
+		return err
+	}
 
-	_, err := s.db.Exec(`
-		INSERT INTO phases (id, plan_id, title, description, rationale, order_index, status, expected_tasks, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	_, err = s.db.Exec(`
+		INSERT INTO phases (id, plan_id, title, description, rationale, order_index, status, expected_tasks, created_at, updated_at, depends_on)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, p.ID, p.PlanID, p.Title, p.Description, p.Rationale, p.OrderIndex, p.Status, p.ExpectedTasks,
-		p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339))
+		p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339), dependsOn)
 
 	if err != nil {
 		return fmt.Errorf("insert phase: %w", err)
@@ -1421,7 +1757,7 @@ func (s *SQLiteStore) CreatePhase(p *task.Phase) error {
 }
 
 // GetPhase retrieves a phase by ID.
-func (s *SQLiteStore) GetPhase(id string) (*task.Phase, error) {
+func (s *taskStore) GetPhase(id string) (*task.Phase, error) {
 	row := s.db.QueryRow(`SELECT `+phaseSelectColumns+` FROM phases WHERE id = ?`, id)
 
 	p, err := scanPhaseRow(row)
@@ -1436,7 +1772,7 @@ func (s *SQLiteStore) GetPhase(id string) (*task.Phase, error) {
 }
 
 // ListPhases returns all phases for a plan, ordered by order_index.
-func (s *SQLiteStore) ListPhases(planID string) ([]task.Phase, error) {
+func (s *taskStore) ListPhases(planID string) ([]task.Phase, error) {
 	rows, err := s.db.Query(`SELECT `+phaseSelectColumns+` FROM phases WHERE plan_id = ? ORDER BY order_index`, planID)
 	if err != nil {
 		return nil, fmt.Errorf("query phases: %w", err)
@@ -1459,17 +1795,21 @@ func (s *SQLiteStore) ListPhases(planID string) ([]task.Phase, error) {
 }
 
 // UpdatePhase updates mutable phase fields.
-func (s *SQLiteStore) UpdatePhase(p *task.Phase) error {
+func (s *taskStore) UpdatePhase(p *task.Phase) error {
 	if p.ID == "" {
 		return fmt.Errorf("phase id is required")
 	}
 	now := time.Now().UTC().Format(time.RFC3339)
+	dependsOn, err := phaseDependsOnValue(p)
+	if err != nil {
+		return err
+	}
 
 	res, err := s.db.Exec(`
 		UPDATE phases
-		SET title = ?, description = ?, rationale = ?, order_index = ?, status = ?, expected_tasks = ?, updated_at = ?
+		SET title = ?, description = ?, rationale = ?, order_index = ?, status = ?, expected_tasks = ?, depends_on = ?, updated_at = ?
 		WHERE id = ?
-	`, p.Title, p.Description, p.Rationale, p.OrderIndex, p.Status, p.ExpectedTasks, now, p.ID)
+	`, p.Title, p.Description, p.Rationale, p.OrderIndex, p.Status, p.ExpectedTasks, dependsOn, now, p.ID)
 
 	if err != nil {
 		return fmt.Errorf("update phase: %w", err)
@@ -1487,7 +1827,7 @@ func (s *SQLiteStore) UpdatePhase(p *task.Phase) error {
 }
 
 // UpdatePhaseStatus updates only the status of a phase.
-func (s *SQLiteStore) UpdatePhaseStatus(id string, status task.PhaseStatus) error {
+func (s *taskStore) UpdatePhaseStatus(id string, status task.PhaseStatus) error {
 	if id == "" {
 		return fmt.Errorf("phase id is required")
 	}
@@ -1510,7 +1850,7 @@ func (s *SQLiteStore) UpdatePhaseStatus(id string, status task.PhaseStatus) erro
 }
 
 // DeletePhase removes a phase. Tasks linked to this phase will have their phase_id set to NULL.
-func (s *SQLiteStore) DeletePhase(id string) error {
+func (s *taskStore) DeletePhase(id string) error {
 	res, err := s.db.Exec(`DELETE FROM phases WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("delete phase: %w", err)
@@ -1525,9 +1865,48 @@ func (s *SQLiteStore) DeletePhase(id string) error {
 	return nil
 }
 
+// RecordPhaseExpandAttempt appends an expansion attempt to a phase's history.
+func (s *taskStore) RecordPhaseExpandAttempt(phaseID string, attempt task.ExpandAttempt) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { rollbackWithLog(tx, "record_expand_attempt") }()
+
+	var attemptsJSON sql.NullString
+	err = tx.QueryRow(`SELECT expand_attempts FROM phases WHERE id = ?`, phaseID).Scan(&attemptsJSON)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("phase not found: %s", phaseID)
+	}
+	if err != nil {
+		return fmt.Errorf("query expand attempts: %w", err)
+	}
+
+	var attempts []task.ExpandAttempt
+	if attemptsJSON.Valid && attemptsJSON.String != "" {
+		if err := json.Unmarshal([]byte(attemptsJSON.String), &attempts); err != nil {
+			return fmt.Errorf("parse expand attempts: %w", err)
+		}
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = time.Now().UTC()
+	}
+	attempts = append(attempts, attempt)
+
+	data, err := json.Marshal(attempts)
+	if err != nil {
+		return fmt.Errorf("marshal expand attempts: %w", err)
+	}
+	nowStr := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec(`UPDATE phases SET expand_attempts = ?, updated_at = ? WHERE id = ?`, string(data), nowStr, phaseID); err != nil {
+		return fmt.Errorf("update expand attempts: %w", err)
+	}
+	return tx.Commit()
+}
+
 // ListTasksByPhase returns all tasks for a specific phase.
-func (s *SQLiteStore) ListTasksByPhase(phaseID string) ([]task.Task, error) {
-	rows, err := s.db.Query(`SELECT `+taskSelectColumns+` FROM tasks WHERE phase_id = ? ORDER BY priority ASC, created_at`, phaseID)
+func (s *taskStore) ListTasksByPhase(phaseID string) ([]task.Task, error) {
+	rows, err := s.db.Query(`SELECT `+taskSelectColumns+` FROM tasks WHERE phase_id = ? ORDER BY priority ASC, sort_order ASC, created_at`, phaseID)
 	if err != nil {
 		return nil, fmt.Errorf("query tasks by phase: %w", err)
 	}
@@ -1549,7 +1928,7 @@ func (s *SQLiteStore) ListTasksByPhase(phaseID string) ([]task.Task, error) {
 }
 
 // CreatePhasesForPlan creates multiple phases for a plan atomically.
-func (s *SQLiteStore) CreatePhasesForPlan(planID string, phases []task.Phase) error {
+func (s *taskStore) CreatePhasesForPlan(planID string, phases []task.Phase) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -1571,12 +1950,16 @@ func (s *SQLiteStore) CreatePhasesForPlan(planID string, phases []task.Phase) er
 			p.CreatedAt = now
 		}
 		p.UpdatedAt = now
+		dependsOn, err := phaseDependsOnValue(p)
+		if err != nil {
+			return err
+		}
 
-		_, err := tx.Exec(`
-			INSERT INTO phases (id, plan_id, title, description, rationale, order_index, status, expected_tasks, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		_, err = tx.Exec(`
+			INSERT INTO phases (id, plan_id, title, description, rationale, order_index, status, expected_tasks, created_at, updated_at, depends_on)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`, p.ID, p.PlanID, p.Title, p.Description, p.Rationale, p.OrderIndex, p.Status, p.ExpectedTasks,
-			p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339))
+			p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339), dependsOn)
 
 		if err != nil {
 			return fmt.Errorf("insert phase %s: %w", p.Title, err)
@@ -1586,8 +1969,150 @@ func (s *SQLiteStore) CreatePhasesForPlan(planID string, phases []task.Phase) er
 	return tx.Commit()
 }
 
+// ReorderPhases rewrites a plan's phase order_index values to follow orderedIDs.
+// orderedIDs must list every phase of the plan exactly once, so a phase that
+// already holds expanded tasks can never be dropped and leave them orphaned.
+func (s *taskStore) ReorderPhases(planID string, orderedIDs []string) error {
+	if planID == "" {
+		return fmt.Errorf("plan id is required")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { rollbackWithLog(tx, "reorder_phases") }()
+
+	current, taskCounts, err := planPhaseOrder(tx, planID)
+	if err != nil {
+		return err
+	}
+
+	listed := make(map[string]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if _, ok := taskCounts[id]; !ok {
+			return fmt.Errorf("phase %s does not belong to plan %s", id, planID)
+		}
+		if listed[id] {
+			return fmt.Errorf("phase %s listed more than once", id)
+		}
+		listed[id] = true
+	}
+	for _, id := range current {
+		if listed[id] {
+			continue
+		}
+		if n := taskCounts[id]; n > 0 {
+			return fmt.Errorf("phase %s has %d expanded tasks and must be included in the order", id, n)
+		}
+		return fmt.Errorf("reorder must list all %d phases of plan %s, got %d", len(current), planID, len(orderedIDs))
+	}
+
+	if err := setPhaseOrder(tx, orderedIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// InsertPhase adds p to a plan at position index (0-based) and shifts the
+// phases at and after that position down by one. index may equal the number
+// of phases to append.
+func (s *taskStore) InsertPhase(planID string, index int, p *task.Phase) error {
+	if planID == "" {
+		return fmt.Errorf("plan id is required")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { rollbackWithLog(tx, "insert_phase") }()
+
+	current, _, err := planPhaseOrder(tx, planID)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index > len(current) {
+		return fmt.Errorf("phase index %d out of range (0-%d)", index, len(current))
+	}
+
+	if p.ID == "" {
+		p.ID = "phase-" + uuid.New().String()[:8]
+	}
+	p.PlanID = planID
+	p.OrderIndex = index
+	if p.Status == "" {
+		p.Status = task.PhaseStatusPending
+	}
+	now := time.Now().UTC()
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	p.UpdatedAt = now
+	dependsOn, err := phaseDependsOnValue(p)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO phases (id, plan_id, title, description, rationale, order_index, status, expected_tasks, created_at, updated_at, depends_on)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, p.ID, p.PlanID, p.Title, p.Description, p.Rationale, p.OrderIndex, p.Status, p.ExpectedTasks,
+		p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339), dependsOn); err != nil {
+		return fmt.Errorf("insert phase: %w", err)
+	}
+
+	order := make([]string, 0, len(current)+1)
+	order = append(order, current[:index]...)
+	order = append(order, p.ID)
+	order = append(order, current[index:]...)
+	if err := setPhaseOrder(tx, order); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// planPhaseOrder returns a plan's phase IDs in their current order and the
+// number of tasks linked to each phase.
+func planPhaseOrder(tx *sqlTx, planID string) ([]string, map[string]int, error) {
+	rows, err := tx.Query(`
+		SELECT ph.id, (SELECT COUNT(*) FROM tasks t WHERE t.phase_id = ph.id)
+		FROM phases ph WHERE ph.plan_id = ? ORDER BY ph.order_index, ph.created_at`, planID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query plan phases: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	taskCounts := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, nil, fmt.Errorf("scan phase: %w", err)
+		}
+		ids = append(ids, id)
+		taskCounts[id] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate plan phases: %w", err)
+	}
+	return ids, taskCounts, nil
+}
+
+// setPhaseOrder renumbers phases 0..n-1 following orderedIDs.
+func setPhaseOrder(tx *sqlTx, orderedIDs []string) error {
+	nowStr := time.Now().UTC().Format(time.RFC3339)
+	for i, id := range orderedIDs {
+		if _, err := tx.Exec(`UPDATE phases SET order_index = ?, updated_at = ? WHERE id = ?`, i, nowStr, id); err != nil {
+			return fmt.Errorf("update order for phase %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
 // UpdatePlanDraftState updates the draft state JSON for a plan.
-func (s *SQLiteStore) UpdatePlanDraftState(planID string, draftStateJSON string) error {
+func (s *taskStore) UpdatePlanDraftState(planID string, draftStateJSON string) error {
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	res, err := s.db.Exec(`UPDATE plans SET draft_state = ?, updated_at = ? WHERE id = ?`,
@@ -1608,7 +2133,7 @@ func (s *SQLiteStore) UpdatePlanDraftState(planID string, draftStateJSON string)
 }
 
 // UpdatePlanGenerationMode updates the generation mode for a plan.
-func (s *SQLiteStore) UpdatePlanGenerationMode(planID string, mode task.GenerationMode) error {
+func (s *taskStore) UpdatePlanGenerationMode(planID string, mode task.GenerationMode) error {
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	res, err := s.db.Exec(`UPDATE plans SET generation_mode = ?, updated_at = ? WHERE id = ?`,
@@ -1630,7 +2155,7 @@ func (s *SQLiteStore) UpdatePlanGenerationMode(planID string, mode task.Generati
 
 // GetPlanWithPhases retrieves a plan with its phases and tasks.
 // This is the complete plan loader for interactive workflow operations.
-func (s *SQLiteStore) GetPlanWithPhases(id string) (*task.Plan, error) {
+func (s *taskStore) GetPlanWithPhases(id string) (*task.Plan, error) {
 	plan, err := s.GetPlan(id)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,323 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/josephgoksu/TaskWing/internal/task"
+)
+
+// forEachTaskBackend runs fn against an in-memory SQLite store and, when
+// TASKWING_TEST_POSTGRES_DSN is set, a PostgreSQL database. The PostgreSQL
+// driver (TASKWING_TEST_POSTGRES_DRIVER, default "pgx") must be linked into
+// the test binary.
+func forEachTaskBackend(t *testing.T, fn func(t *testing.T, backend TaskBackend)) {
+	t.Run("sqlite", func(t *testing.T) {
+		store, err := NewSQLiteStore(":memory:")
+		if err != nil {
+			t.Fatalf("NewSQLiteStore: %v", err)
+		}
+		defer func() { _ = store.Close() }()
+		fn(t, store)
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv("TASKWING_TEST_POSTGRES_DSN")
+		if dsn == "" {
+			t.Skip("TASKWING_TEST_POSTGRES_DSN not set")
+		}
+		driver := os.Getenv("TASKWING_TEST_POSTGRES_DRIVER")
+		if driver == "" {
+			driver = "pgx"
+		}
+		if !slices.Contains(sql.Drivers(), driver) {
+			t.Skipf("sql driver %q not registered", driver)
+		}
+		db, err := sql.Open(driver, dsn)
+		if err != nil {
+			t.Fatalf("open postgres: %v", err)
+		}
+		store, err := NewPostgresStore(db)
+		if err != nil {
+			_ = db.Close()
+			t.Fatalf("NewPostgresStore: %v", err)
+		}
+		defer func() { _ = store.Close() }()
+		fn(t, store)
+	})
+}
+
+func TestTaskBackends_PlanLifecycle(t *testing.T) {
+	forEachTaskBackend(t, func(t *testing.T, backend TaskBackend) {
+		store := backend.taskBackend()
+		// Unique goal so a shared database's existing plans never match
+		marker := "backend-" + uuid.New().String()[:8]
+
+		plan := &task.Plan{
+			Goal: "Add Auth " + marker,
+			Tasks: []task.Task{
+				{ID: "task-" + uuid.New().String()[:8], Title: "Write token tests", Priority: 10, SkipAudit: true, ContextNodes: []string{"n-1"}},
+				{Title: "Add JWT middleware", Priority: 20},
+			},
+		}
+		plan.Tasks[1].Dependencies = []string{plan.Tasks[0].ID}
+		if err := store.CreatePlan(plan); err != nil {
+			t.Fatalf("CreatePlan: %v", err)
+		}
+		defer func() { _ = store.DeletePlan(plan.ID) }()
+		first, second := plan.Tasks[0].ID, plan.Tasks[1].ID
+
+		// Adding an existing dependency is a no-op
+		if err := store.AddDependency(second, first); err != nil {
+			t.Fatalf("AddDependency: %v", err)
+		}
+		got, err := store.GetPlan(plan.ID)
+		if err != nil {
+			t.Fatalf("GetPlan: %v", err)
+		}
+		if len(got.Tasks) != 2 {
+			t.Fatalf("got %d tasks, want 2", len(got.Tasks))
+		}
+		for _, tk := range got.Tasks {
+			switch tk.ID {
+			case first:
+				if !tk.SkipAudit {
+					t.Errorf("first task SkipAudit = false, want true")
+				}
+			case second:
+				if !slices.Equal(tk.Dependencies, []string{first}) {
+					t.Errorf("dependencies = %v, want [%s]", tk.Dependencies, first)
+				}
+			}
+		}
+
+		if nodes, err := store.GetTaskContextNodes(first); err != nil || !slices.Equal(nodes, []string{"n-1"}) {
+			t.Errorf("context nodes = %v, err %v; want [n-1]", nodes, err)
+		}
+
+		// Search is case-insensitive on every backend
+		found, err := store.SearchPlans("add auth "+marker, "")
+		if err != nil {
+			t.Fatalf("SearchPlans: %v", err)
+		}
+		if len(found) != 1 || found[0].ID != plan.ID {
+			t.Errorf("SearchPlans = %+v, want only %s", found, plan.ID)
+		}
+
+		// An offset without a limit returns the rest of the plans
+		all, err := store.ListPlans(task.PlanFilter{})
+		if err != nil {
+			t.Fatalf("ListPlans: %v", err)
+		}
+		rest, err := store.ListPlans(task.PlanFilter{Offset: 1})
+		if err != nil {
+			t.Fatalf("ListPlans offset: %v", err)
+		}
+		if len(rest) != len(all)-1 {
+			t.Errorf("offset 1 returned %d plans, want %d", len(rest), len(all)-1)
+		}
+
+		if err := store.ReorderTasks(plan.ID, []string{second, first}); err != nil {
+			t.Fatalf("ReorderTasks: %v", err)
+		}
+		if err := store.ReorderTasks(plan.ID, []string{second}); err == nil {
+			t.Error("ReorderTasks should reject a partial order")
+		}
+		reordered, err := store.ListTasks(plan.ID)
+		if err != nil {
+			t.Fatalf("ListTasks: %v", err)
+		}
+		priorities := make(map[string]int, len(reordered))
+		for _, tk := range reordered {
+			priorities[tk.ID] = tk.Priority
+		}
+		if priorities[second] >= priorities[first] {
+			t.Errorf("priorities = %v, want %s before %s", priorities, second, first)
+		}
+
+		if err := store.ClaimTask(first, "session-1"); err != nil {
+			t.Fatalf("ClaimTask: %v", err)
+		}
+		if err := store.CompleteTask(first, "done", []string{"auth.go"}); err != nil {
+			t.Fatalf("CompleteTask: %v", err)
+		}
+		next, err := store.GetNextTask(plan.ID)
+		if err != nil {
+			t.Fatalf("GetNextTask: %v", err)
+		}
+		if next == nil || next.ID != second {
+			t.Errorf("next task = %+v, want %s once its dependency is complete", next, second)
+		}
+
+		// A split is all or nothing: a duplicate subtask id inserts no subtasks
+		failing := []task.Task{{PlanID: plan.ID, Title: "Fresh"}, {ID: first, PlanID: plan.ID, Title: "Duplicate id"}}
+		if err := store.SplitTask(second, failing); err == nil {
+			t.Fatal("SplitTask should fail on a duplicate task id")
+		}
+		if tasks, err := store.ListTasks(plan.ID); err != nil || len(tasks) != 2 {
+			t.Errorf("failed split left %d tasks (err %v), want 2", len(tasks), err)
+		}
+		subtasks := []task.Task{{PlanID: plan.ID, Title: "Sign tokens"}, {PlanID: plan.ID, Title: "Verify tokens"}}
+		if err := store.SplitTask(second, subtasks); err != nil {
+			t.Fatalf("SplitTask: %v", err)
+		}
+		parent, err := store.GetTask(second)
+		if err != nil {
+			t.Fatalf("GetTask: %v", err)
+		}
+		for _, sub := range subtasks {
+			if !slices.Contains(parent.Dependencies, sub.ID) {
+				t.Errorf("parent should depend on subtask %s, got %v", sub.ID, parent.Dependencies)
+			}
+		}
+		if err := store.SplitTask(first, subtasks); err == nil {
+			t.Error("SplitTask should reject a completed task")
+		}
+	})
+}
+
+func TestTaskBackends_PhasesAndMerge(t *testing.T) {
+	forEachTaskBackend(t, func(t *testing.T, backend TaskBackend) {
+		store := backend.taskBackend()
+		target := &task.Plan{Goal: "Ship billing", Status: task.PlanStatusDraft}
+		source := &task.Plan{Goal: "Ship invoices", Status: task.PlanStatusDraft}
+		for _, p := range []*task.Plan{target, source} {
+			if err := store.CreatePlan(p); err != nil {
+				t.Fatalf("CreatePlan: %v", err)
+			}
+		}
+		defer func() { _ = store.DeletePlan(target.ID); _ = store.DeletePlan(source.ID) }()
+
+		phases := []task.Phase{{Title: "Schema", OrderIndex: 0}, {Title: "API", OrderIndex: 1}}
+		phases[0].ID = "phase-" + uuid.New().String()[:8]
+		phases[1].DependsOn = []string{phases[0].ID}
+		if err := store.CreatePhasesForPlan(target.ID, phases); err != nil {
+			t.Fatalf("CreatePhasesForPlan: %v", err)
+		}
+		if err := store.RecordPhaseExpandAttempt(phases[1].ID, task.ExpandAttempt{Feedback: "smaller tasks"}); err != nil {
+			t.Fatalf("RecordPhaseExpandAttempt: %v", err)
+		}
+		api, err := store.GetPhase(phases[1].ID)
+		if err != nil {
+			t.Fatalf("GetPhase: %v", err)
+		}
+		if !slices.Equal(api.DependsOn, []string{phases[0].ID}) || len(api.ExpandAttempts) != 1 {
+			t.Errorf("API phase depends on %v with %d attempts, want [%s] and 1", api.DependsOn, len(api.ExpandAttempts), phases[0].ID)
+		}
+
+		sourcePhase := &task.Phase{PlanID: source.ID, Title: "Templates"}
+		if err := store.CreatePhase(sourcePhase); err != nil {
+			t.Fatalf("CreatePhase: %v", err)
+		}
+		if err := store.MergePlans(target.ID, source.ID); err != nil {
+			t.Fatalf("MergePlans: %v", err)
+		}
+		merged, err := store.ListPhases(target.ID)
+		if err != nil {
+			t.Fatalf("ListPhases: %v", err)
+		}
+		var titles []string
+		for _, p := range merged {
+			titles = append(titles, p.Title)
+		}
+		if !slices.Equal(titles, []string{"Schema", "API", "Templates"}) {
+			t.Errorf("merged phases = %v, want source phases after the target's", titles)
+		}
+		if p, err := store.GetPlan(source.ID); err == nil && p != nil {
+			t.Errorf("source plan %s should be deleted after the merge", source.ID)
+		}
+	})
+}
+
+func TestTaskBackends_ClarifySession(t *testing.T) {
+	forEachTaskBackend(t, func(t *testing.T, backend TaskBackend) {
+		store := backend.taskBackend()
+		session := &task.ClarifySession{
+			ID: "clarify-" + uuid.New().String()[:8], Goal: "Add auth", State: task.ClarifySessionStateAwaitingAnswers,
+			MaxRounds: 5, MaxQuestionsPerRound: 3, CurrentQuestions: []string{"Which provider?"},
+		}
+		if err := store.CreateClarifySession(session); err != nil {
+			t.Fatalf("CreateClarifySession: %v", err)
+		}
+		turn := &task.ClarifyTurn{SessionID: session.ID, Questions: []string{"Which provider?"}, Answers: []string{"OIDC"}, AutoAnswered: true}
+		if err := store.CreateClarifyTurn(turn); err != nil {
+			t.Fatalf("CreateClarifyTurn: %v", err)
+		}
+		session.RoundIndex, session.IsReadyToPlan, session.EnrichedGoal = 1, true, "Add OIDC auth"
+		if err := store.UpdateClarifySession(session); err != nil {
+			t.Fatalf("UpdateClarifySession: %v", err)
+		}
+
+		got, err := store.GetClarifySession(session.ID)
+		if err != nil {
+			t.Fatalf("GetClarifySession: %v", err)
+		}
+		if got.RoundIndex != 1 || !got.IsReadyToPlan || got.EnrichedGoal != "Add OIDC auth" {
+			t.Errorf("session = %+v, want round 1, ready, enriched goal saved", got)
+		}
+		turns, err := store.ListClarifyTurns(session.ID)
+		if err != nil {
+			t.Fatalf("ListClarifyTurns: %v", err)
+		}
+		if len(turns) != 1 || !turns[0].AutoAnswered || !slices.Equal(turns[0].Answers, []string{"OIDC"}) {
+			t.Errorf("turns = %+v, want one auto-answered turn", turns)
+		}
+	})
+}
+
+func TestTaskBackends_ReorderKeepsOrderBeyond100Tasks(t *testing.T) {
+	forEachTaskBackend(t, func(t *testing.T, backend TaskBackend) {
+		store := backend.taskBackend()
+		plan := &task.Plan{Goal: "Large plan " + uuid.New().String()[:8]}
+		for i := range 120 {
+			plan.Tasks = append(plan.Tasks, task.Task{Title: fmt.Sprintf("Step %d", i), Priority: 50})
+		}
+		if err := store.CreatePlan(plan); err != nil {
+			t.Fatalf("CreatePlan: %v", err)
+		}
+		defer func() { _ = store.DeletePlan(plan.ID) }()
+
+		// Reverse creation order, so only sort_order separates tied priorities
+		ordered := make([]string, len(plan.Tasks))
+		for i, tk := range plan.Tasks {
+			ordered[len(ordered)-1-i] = tk.ID
+		}
+		if err := store.ReorderTasks(plan.ID, ordered); err != nil {
+			t.Fatalf("ReorderTasks: %v", err)
+		}
+
+		for _, want := range ordered[:3] {
+			next, err := store.GetNextTask(plan.ID)
+			if err != nil {
+				t.Fatalf("GetNextTask: %v", err)
+			}
+			if next == nil || next.ID != want {
+				t.Fatalf("next task = %+v, want %s", next, want)
+			}
+			if next.Priority < 0 || next.Priority > 100 {
+				t.Errorf("%s priority %d out of range", next.ID, next.Priority)
+			}
+			if err := store.UpdateTaskStatus(want, task.StatusCompleted); err != nil {
+				t.Fatalf("UpdateTaskStatus: %v", err)
+			}
+		}
+	})
+}
+
+func TestDialect_Rebind(t *testing.T) {
+	query := `SELECT id FROM tasks WHERE plan_id = ? AND title <> '?' AND status IN (?, ?)`
+	if got := SQLiteDialect.rebind(query); got != query {
+		t.Errorf("sqlite rebind changed the query: %s", got)
+	}
+	want := `SELECT id FROM tasks WHERE plan_id = $1 AND title <> '?' AND status IN ($2, $3)`
+	if got := PostgresDialect.rebind(query); got != want {
+		t.Errorf("postgres rebind = %s, want %s", got, want)
+	}
+	if got := PostgresDialect.insertIgnore("task_dependencies (task_id, depends_on) VALUES (?, ?)"); got != "INSERT INTO task_dependencies (task_id, depends_on) VALUES (?, ?) ON CONFLICT DO NOTHING" {
+		t.Errorf("postgres insertIgnore = %s", got)
+	}
+}
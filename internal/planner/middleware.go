@@ -55,6 +55,7 @@ type SemanticValidationStats struct {
 	PathsRecovered    int `json:"paths_recovered"`
 	CommandsValidated int `json:"commands_validated"`
 	CommandsInvalid   int `json:"commands_invalid"`
+	CommandsDenied    int `json:"commands_denied"`
 }
 
 // MiddlewareConfig configures semantic validation behavior.
@@ -67,6 +68,28 @@ type MiddlewareConfig struct {
 	SkipCommandValidation bool
 	// AllowMissingFiles treats missing files as warnings, not errors
 	AllowMissingFiles bool
+	// StrictMode promotes path and command issues to blocking errors.
+	// Overrides AllowMissingFiles; intended for CI where plans must be verifiable.
+	StrictMode bool
+	// DeniedCommands are regular expressions for forbidden validation steps.
+	// nil uses DefaultDeniedCommands; an empty slice disables the denylist.
+	DeniedCommands []string
+	// AllowedCommands restricts validation steps to these programs (allowlist mode).
+	// Every command in a pipeline or chain must start with an allowed program.
+	// Empty allows any program not denied.
+	AllowedCommands []string
+}
+
+// DefaultDeniedCommands blocks destructive or remote-execution commands in validation steps.
+var DefaultDeniedCommands = []string{
+	`\brm\s+(-[a-zA-Z]*r[a-zA-Z]*f|-[a-zA-Z]*f[a-zA-Z]*r|-r\s+-f|-f\s+-r)\b`, // rm -rf
+	`\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z|da)?sh\b`,                      // curl | sh
+	`\bmkfs(\.[a-z0-9]+)?\b`,
+	`\bdd\s+if=`,
+	`>\s*/dev/(sd|nvme|disk)`,
+	`:\(\)\s*\{\s*:\|:&\s*\}\s*;\s*:`, // fork bomb
+	`\bchmod\s+-R\s+777\s+/(\s|$)`,
+	`\bgit\s+push\b.*(--force\b|\s-f\b)`,
 }
 
 // SemanticMiddleware validates plans for semantic correctness.
@@ -74,6 +97,8 @@ type SemanticMiddleware struct {
 	cfg            MiddlewareConfig
 	shellAvailable bool
 	shellChecked   bool
+	denied         []*regexp.Regexp
+	allowed        map[string]bool
 }
 
 // NewSemanticMiddleware creates a new semantic validation middleware.
@@ -82,6 +107,25 @@ func NewSemanticMiddleware(cfg MiddlewareConfig) *SemanticMiddleware {
 		cfg.BasePath, _ = os.Getwd()
 	}
 	m := &SemanticMiddleware{cfg: cfg}
+
+	denied := cfg.DeniedCommands
+	if denied == nil {
+		denied = DefaultDeniedCommands
+	}
+	for _, pattern := range denied {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue // Invalid user patterns are ignored rather than failing validation
+		}
+		m.denied = append(m.denied, re)
+	}
+	if len(cfg.AllowedCommands) > 0 {
+		m.allowed = make(map[string]bool, len(cfg.AllowedCommands))
+		for _, name := range cfg.AllowedCommands {
+			m.allowed[name] = true
+		}
+	}
+
 	if !cfg.SkipCommandValidation {
 		if _, err := exec.LookPath("bash"); err == nil {
 			m.shellAvailable = true
@@ -171,7 +215,7 @@ func (m *SemanticMiddleware) validateFilePaths(result *SemanticValidationResult,
 			}
 
 			// No recovery possible - report as warning or error
-			if m.cfg.AllowMissingFiles {
+			if m.cfg.AllowMissingFiles && !m.cfg.StrictMode {
 				result.Warnings = append(result.Warnings, SemanticWarning{
 					TaskIndex: taskIdx,
 					TaskTitle: task.Title,
@@ -192,8 +236,25 @@ func (m *SemanticMiddleware) validateFilePaths(result *SemanticValidationResult,
 	}
 }
 
-// validateCommands checks shell commands for syntax validity.
+// validateCommands checks shell commands against the command policy and for syntax validity.
 func (m *SemanticMiddleware) validateCommands(result *SemanticValidationResult, taskIdx int, task *LLMTaskSchema) {
+	for _, step := range task.ValidationSteps {
+		step = strings.TrimSpace(step)
+		if step == "" {
+			continue
+		}
+		if msg := m.checkCommandPolicy(step); msg != "" {
+			result.Stats.CommandsDenied++
+			result.Errors = append(result.Errors, SemanticError{
+				TaskIndex: taskIdx,
+				TaskTitle: task.Title,
+				Type:      "denied_command",
+				Message:   msg,
+				Command:   step,
+			})
+		}
+	}
+
 	if !m.shellChecked {
 		if _, err := exec.LookPath("bash"); err == nil {
 			m.shellAvailable = true
@@ -201,7 +262,14 @@ func (m *SemanticMiddleware) validateCommands(result *SemanticValidationResult,
 		m.shellChecked = true
 	}
 	if !m.shellAvailable {
-		if len(task.ValidationSteps) > 0 {
+		if len(task.ValidationSteps) > 0 && m.cfg.StrictMode {
+			result.Errors = append(result.Errors, SemanticError{
+				TaskIndex: taskIdx,
+				TaskTitle: task.Title,
+				Type:      "command_validation_skipped",
+				Message:   "bash not available; cannot validate shell syntax in strict mode",
+			})
+		} else if len(task.ValidationSteps) > 0 {
 			result.Warnings = append(result.Warnings, SemanticWarning{
 				TaskIndex: taskIdx,
 				TaskTitle: task.Title,
@@ -232,6 +300,36 @@ func (m *SemanticMiddleware) validateCommands(result *SemanticValidationResult,
 	}
 }
 
+// checkCommandPolicy returns a non-empty reason if the command is denied or not allowlisted.
+func (m *SemanticMiddleware) checkCommandPolicy(command string) string {
+	for _, re := range m.denied {
+		if re.MatchString(command) {
+			return fmt.Sprintf("Command matches denylist pattern %q", re.String())
+		}
+	}
+	if m.allowed == nil {
+		return ""
+	}
+	for _, segment := range commandSegmentRegex.Split(command, -1) {
+		fields := strings.Fields(segment)
+		// Skip leading environment assignments (FOO=bar go test)
+		for len(fields) > 0 && strings.Contains(fields[0], "=") {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		program := filepath.Base(fields[0])
+		if !m.allowed[program] {
+			return fmt.Sprintf("Command %q is not in the allowlist", program)
+		}
+	}
+	return ""
+}
+
+// commandSegmentRegex splits chained and piped commands into individual invocations.
+var commandSegmentRegex = regexp.MustCompile(`\|\||&&|[;|]`)
+
 // Regular expressions for extracting file paths
 var (
 	// Match common file path patterns
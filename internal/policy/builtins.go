@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/afero"
 
 	"github.com/josephgoksu/TaskWing/internal/codeintel"
+	"github.com/josephgoksu/TaskWing/internal/config"
 	"github.com/josephgoksu/TaskWing/internal/utils"
 )
 
@@ -26,22 +27,27 @@ type BuiltinContext struct {
 	// CodeIntel is the optional code intelligence repository for symbol queries.
 	// Can be nil if code intelligence is not available.
 	CodeIntel codeintel.Repository
+	// ExcludePatterns are glob patterns for files whose imports are ignored
+	// (generated code by default).
+	ExcludePatterns []string
 }
 
 // NewBuiltinContext creates a new context with the OS filesystem.
 func NewBuiltinContext(workDir string) *BuiltinContext {
 	return &BuiltinContext{
-		WorkDir: workDir,
-		Fs:      afero.NewOsFs(),
+		WorkDir:         workDir,
+		Fs:              afero.NewOsFs(),
+		ExcludePatterns: config.LoadExcludeFilePatterns(),
 	}
 }
 
 // NewBuiltinContextWithCodeIntel creates a context with code intelligence support.
 func NewBuiltinContextWithCodeIntel(workDir string, repo codeintel.Repository) *BuiltinContext {
 	return &BuiltinContext{
-		WorkDir:   workDir,
-		Fs:        afero.NewOsFs(),
-		CodeIntel: repo,
+		WorkDir:         workDir,
+		Fs:              afero.NewOsFs(),
+		CodeIntel:       repo,
+		ExcludePatterns: config.LoadExcludeFilePatterns(),
 	}
 }
 
@@ -221,8 +227,13 @@ func hasPatternImpl(ctx *BuiltinContext, path, pattern string) bool {
 }
 
 // fileImportsImpl extracts imports from a file.
-// Currently supports Go import statements.
+// Currently supports Go import statements. Files matching ExcludePatterns
+// (generated code by default) report no imports.
 func fileImportsImpl(ctx *BuiltinContext, path string) []string {
+	if utils.MatchesFilePattern(path, ctx.ExcludePatterns) {
+		return []string{}
+	}
+
 	fullPath, pathErr := ctx.resolvePath(path)
 	if pathErr != nil {
 		return nil
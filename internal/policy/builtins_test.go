@@ -0,0 +1,33 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/josephgoksu/TaskWing/internal/utils"
+)
+
+func TestFileImports_SkipsGeneratedFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	src := "package types\n\nimport (\n\t\"encoding/json\"\n\t\"github.com/example/generated/runtime\"\n)\n"
+	if err := afero.WriteFile(fs, "/repo/internal/types/types.gen.go", []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fs, "/repo/internal/types/types.go", []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &BuiltinContext{
+		WorkDir:         "/repo",
+		Fs:              fs,
+		ExcludePatterns: utils.GeneratedFilePatterns,
+	}
+
+	if imports := fileImportsImpl(ctx, "internal/types/types.gen.go"); len(imports) != 0 {
+		t.Errorf("generated file imports should not appear in the graph, got %v", imports)
+	}
+	if imports := fileImportsImpl(ctx, "internal/types/types.go"); len(imports) != 2 {
+		t.Errorf("expected 2 imports from regular file, got %v", imports)
+	}
+}
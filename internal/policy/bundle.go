@@ -0,0 +1,145 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/bundle"
+)
+
+// Policy sources reported by PolicyFile.Source.
+const (
+	PolicySourceLocal  = "local"
+	PolicySourceBundle = "bundle"
+)
+
+const (
+	// bundleFetchTimeout bounds how long a remote bundle download may take.
+	bundleFetchTimeout = 30 * time.Second
+	// bundleCacheTTL is how long a downloaded bundle is reused before it is
+	// fetched again, so creating an engine per hook or tool call stays cheap.
+	bundleCacheTTL = 5 * time.Minute
+)
+
+// remoteBundle is a downloaded bundle kept in remoteBundles.
+type remoteBundle struct {
+	policies  []*PolicyFile
+	fetchedAt time.Time
+}
+
+var (
+	remoteBundlesMu sync.Mutex
+	remoteBundles   = make(map[string]remoteBundle) // URL -> last successful download
+)
+
+// LoadBundle loads the Rego modules of an OPA bundle (.tar.gz).
+// The location may be a local path (read through the loader's filesystem)
+// or an http(s) URL. Data documents in the bundle are ignored.
+//
+// Remote bundles are cached for bundleCacheTTL. When a refresh fails, the
+// last successful download is used instead.
+func (l *Loader) LoadBundle(ctx context.Context, location string) ([]*PolicyFile, error) {
+	if !isRemoteBundle(location) {
+		f, err := l.fs.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("open bundle: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		return readBundle(f, location)
+	}
+
+	remoteBundlesMu.Lock()
+	cached, ok := remoteBundles[location]
+	remoteBundlesMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < bundleCacheTTL {
+		return cached.policies, nil
+	}
+
+	policies, err := downloadBundle(ctx, location)
+	if err != nil {
+		if ok {
+			slog.Warn("policy bundle refresh failed; using cached copy", "bundle", location, "error", err)
+			return cached.policies, nil
+		}
+		return nil, err
+	}
+	remoteBundlesMu.Lock()
+	remoteBundles[location] = remoteBundle{policies: policies, fetchedAt: time.Now()}
+	remoteBundlesMu.Unlock()
+	return policies, nil
+}
+
+// downloadBundle fetches and reads a remote bundle.
+func downloadBundle(ctx context.Context, url string) ([]*PolicyFile, error) {
+	body, err := fetchBundle(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+	return readBundle(body, url)
+}
+
+// readBundle reads the Rego modules of the bundle in r.
+func readBundle(r io.Reader, location string) ([]*PolicyFile, error) {
+	b, err := bundle.NewReader(r).Read()
+	if err != nil {
+		return nil, fmt.Errorf("read bundle %s: %w", location, err)
+	}
+
+	policies := make([]*PolicyFile, 0, len(b.Modules))
+	for _, m := range b.Modules {
+		policies = append(policies, &PolicyFile{
+			// Prefix with the bundle location so module paths stay unique across sources
+			Path:     location + "#" + strings.TrimPrefix(m.Path, "/"),
+			Name:     strings.TrimSuffix(path.Base(m.Path), ".rego"),
+			Content:  string(m.Raw),
+			Source:   PolicySourceBundle,
+			Location: location,
+		})
+	}
+	return policies, nil
+}
+
+// isRemoteBundle reports whether the bundle location is a URL.
+func isRemoteBundle(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// fetchBundle downloads a bundle and returns its body.
+func fetchBundle(ctx context.Context, url string) (io.ReadCloser, error) {
+	ctx, cancel := context.WithTimeout(ctx, bundleFetchTimeout)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("create bundle request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("fetch bundle %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("fetch bundle %s: unexpected status %s", url, resp.Status)
+	}
+	return &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}, nil
+}
+
+// cancelOnClose releases the request context once the body has been read.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
@@ -0,0 +1,159 @@
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+const bundleDenyPolicy = `package taskwing.policy
+
+import rego.v1
+
+deny contains msg if {
+    some file in input.task.files_modified
+    startswith(file, "vendor/")
+    msg := sprintf("BLOCKED: Vendored file '%s' is managed by the org bundle", [file])
+}
+`
+
+const localDenyPolicy = `package taskwing.policy
+
+import rego.v1
+
+deny contains msg if {
+    some file in input.task.files_modified
+    file == "go.sum"
+    msg := "BLOCKED: go.sum is generated"
+}
+`
+
+func writeTestBundle(t *testing.T, fs afero.Fs, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fs, path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewEngine_MergesLocalBundle(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/store/policies/local.rego", []byte(localDenyPolicy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeTestBundle(t, fs, "/bundles/org.tar.gz", map[string]string{
+		"/org/vendor.rego": bundleDenyPolicy,
+	})
+
+	engine, err := NewEngine(context.Background(), EngineConfig{
+		WorkDir:     "/repo",
+		PoliciesDir: "/store/policies",
+		Fs:          fs,
+		Bundles:     []string{"/bundles/org.tar.gz"},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	if engine.PolicyCount() != 2 {
+		t.Fatalf("expected local + bundle policies, got %d", engine.PolicyCount())
+	}
+
+	sources := map[string]string{}
+	for _, p := range engine.GetPolicies() {
+		sources[p.Name] = p.Source
+	}
+	if sources["local"] != PolicySourceLocal || sources["vendor"] != PolicySourceBundle {
+		t.Errorf("unexpected policy sources: %v", sources)
+	}
+
+	decision, err := engine.EvaluateFiles(context.Background(), []string{"vendor/lib/a.go", "go.sum"}, nil)
+	if err != nil {
+		t.Fatalf("EvaluateFiles: %v", err)
+	}
+	if !decision.IsDenied() || len(decision.Violations) != 2 {
+		t.Errorf("expected bundle and local rules to both deny, got %v", decision.Violations)
+	}
+}
+
+func TestNewEngine_UnreachableBundleKeepsLocalPolicies(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/store/policies/local.rego", []byte(localDenyPolicy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	engine, err := NewEngine(context.Background(), EngineConfig{
+		WorkDir:     "/repo",
+		PoliciesDir: "/store/policies",
+		Fs:          fs,
+		Bundles:     []string{server.URL + "/unreachable.tar.gz", "/bundles/missing.tar.gz"},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	if engine.PolicyCount() != 1 {
+		t.Fatalf("expected the local policy to stay loaded, got %d policies", engine.PolicyCount())
+	}
+	decision, err := engine.EvaluateFiles(context.Background(), []string{"go.sum"}, nil)
+	if err != nil {
+		t.Fatalf("EvaluateFiles: %v", err)
+	}
+	if !decision.IsDenied() {
+		t.Error("local policy should still deny go.sum when a bundle is unavailable")
+	}
+}
+
+func TestLoader_CachesRemoteBundle(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestBundle(t, fs, "/bundle.tar.gz", map[string]string{"/org/vendor.rego": bundleDenyPolicy})
+	data, err := afero.ReadFile(fs, "/bundle.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	loader := NewLoader(fs, "/policies")
+	for range 3 {
+		policies, err := loader.LoadBundle(context.Background(), server.URL+"/org.tar.gz")
+		if err != nil {
+			t.Fatalf("LoadBundle: %v", err)
+		}
+		if len(policies) != 1 {
+			t.Fatalf("got %d policies, want 1", len(policies))
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("bundle fetched %d times, want once", n)
+	}
+}
@@ -3,6 +3,7 @@ package policy
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -11,14 +12,15 @@ import (
 	"github.com/spf13/afero"
 
 	"github.com/josephgoksu/TaskWing/internal/codeintel"
+	"github.com/josephgoksu/TaskWing/internal/config"
 )
 
 // DefaultPolicyPackage is the default Rego package path for TaskWing policies.
 const DefaultPolicyPackage = "taskwing.policy"
 
 // Engine wraps OPA for policy evaluation.
-// It loads policies from .rego files and evaluates them against input data.
-// All evaluation happens locally without external network calls.
+// It loads policies from .rego files and OPA bundles and evaluates them against input data.
+// All evaluation happens locally; remote bundles are only fetched when the engine is created.
 type Engine struct {
 	// policies contains loaded policy files
 	policies []*PolicyFile
@@ -31,6 +33,9 @@ type Engine struct {
 
 	// compiled indicates whether the engine has been prepared
 	compiled bool
+
+	// bundles are the OPA bundle locations merged into policies
+	bundles []string
 }
 
 // EngineConfig holds configuration for creating an Engine.
@@ -53,11 +58,18 @@ type EngineConfig struct {
 	// CodeIntel is the optional code intelligence repository.
 	// If provided, enables symbol-aware built-in functions.
 	CodeIntel codeintel.Repository
+
+	// Bundles are OPA bundle locations (local .tar.gz paths or http(s) URLs)
+	// whose policies are merged with the policies directory.
+	// If nil, defaults to the policy.bundles config value.
+	Bundles []string
 }
 
 // NewEngine creates a new policy engine with the given configuration.
 // It loads policies from the configured directory and prepares built-in functions.
-func NewEngine(cfg EngineConfig) (*Engine, error) {
+// A bundle that cannot be loaded is logged and skipped, so local policies are
+// still enforced when a remote bundle is unreachable.
+func NewEngine(ctx context.Context, cfg EngineConfig) (*Engine, error) {
 	// Set defaults
 	if cfg.Fs == nil {
 		cfg.Fs = afero.NewOsFs()
@@ -68,12 +80,16 @@ func NewEngine(cfg EngineConfig) (*Engine, error) {
 	if cfg.PolicyPackage == "" {
 		cfg.PolicyPackage = DefaultPolicyPackage
 	}
+	if cfg.Bundles == nil {
+		cfg.Bundles = config.LoadPolicyBundles()
+	}
 
 	// Create builtin context
 	builtinCtx := &BuiltinContext{
-		WorkDir:   cfg.WorkDir,
-		Fs:        cfg.Fs,
-		CodeIntel: cfg.CodeIntel,
+		WorkDir:         cfg.WorkDir,
+		Fs:              cfg.Fs,
+		CodeIntel:       cfg.CodeIntel,
+		ExcludePatterns: config.LoadExcludeFilePatterns(),
 	}
 
 	// Register custom built-ins (this registers globally with OPA)
@@ -85,12 +101,21 @@ func NewEngine(cfg EngineConfig) (*Engine, error) {
 	if err != nil {
 		return nil, fmt.Errorf("load policies: %w", err)
 	}
+	for _, location := range cfg.Bundles {
+		bundlePolicies, err := loader.LoadBundle(ctx, location)
+		if err != nil {
+			slog.Warn("policy bundle skipped; its policies are not enforced", "bundle", location, "error", err)
+			continue
+		}
+		policies = append(policies, bundlePolicies...)
+	}
 
 	return &Engine{
 		policies:      policies,
 		builtinCtx:    builtinCtx,
 		policyPackage: cfg.PolicyPackage,
 		compiled:      true,
+		bundles:       cfg.Bundles,
 	}, nil
 }
 
@@ -98,8 +123,9 @@ func NewEngine(cfg EngineConfig) (*Engine, error) {
 // This is useful for testing or when policies come from sources other than files.
 func NewEngineWithPolicies(workDir string, policies []*PolicyFile) *Engine {
 	builtinCtx := &BuiltinContext{
-		WorkDir: workDir,
-		Fs:      afero.NewOsFs(),
+		WorkDir:         workDir,
+		Fs:              afero.NewOsFs(),
+		ExcludePatterns: config.LoadExcludeFilePatterns(),
 	}
 	RegisterBuiltins(builtinCtx)
 
@@ -278,7 +304,7 @@ func (e *Engine) GetPolicies() []*PolicyFile {
 	return e.policies
 }
 
-// ReloadPolicies reloads policies from disk.
+// ReloadPolicies reloads policies from disk and re-reads configured bundles.
 // This is useful if policies have been modified while the engine is running.
 func (e *Engine) ReloadPolicies(fs afero.Fs, policiesDir string) error {
 	loader := NewLoader(fs, policiesDir)
@@ -286,6 +312,13 @@ func (e *Engine) ReloadPolicies(fs afero.Fs, policiesDir string) error {
 	if err != nil {
 		return fmt.Errorf("reload policies: %w", err)
 	}
+	for _, location := range e.bundles {
+		bundlePolicies, err := loader.LoadBundle(context.Background(), location)
+		if err != nil {
+			return fmt.Errorf("reload policy bundle: %w", err)
+		}
+		policies = append(policies, bundlePolicies...)
+	}
 	e.policies = policies
 	return nil
 }
@@ -297,6 +330,7 @@ func (e *Engine) AddPolicy(name, content string) {
 		Name:    name,
 		Path:    name + ".rego",
 		Content: content,
+		Source:  PolicySourceLocal,
 	})
 }
 
@@ -0,0 +1,146 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+// DenyExplanation describes one deny rule that fired for an input.
+type DenyExplanation struct {
+	Policy      string   `json:"policy"`      // Policy file name (without .rego)
+	Rule        string   `json:"rule"`        // Human-readable rule name
+	Line        int      `json:"line"`        // Line of the rule in the policy file
+	Expression  string   `json:"expression"`  // Rego source of the rule
+	Messages    []string `json:"messages"`    // Deny messages produced by this rule
+	Remediation string   `json:"remediation"` // Suggested way to resolve the denial
+}
+
+// explainRulePrefix is the name deny rules are renamed to while explaining,
+// so each rule can be queried on its own.
+const explainRulePrefix = "__taskwing_explain_deny_"
+
+// ExplainFile evaluates the loaded policies against a single file and returns
+// every deny rule that would block modifying or creating it.
+func (e *Engine) ExplainFile(ctx context.Context, file string) ([]DenyExplanation, error) {
+	file = NormalizePath(file)
+	return e.Explain(ctx, BuildForFiles([]string{file}, []string{file}))
+}
+
+// Explain evaluates each deny rule in isolation and reports the ones that fire.
+// Unlike Evaluate, the result is attributed to individual rules.
+func (e *Engine) Explain(ctx context.Context, input any) ([]DenyExplanation, error) {
+	type denyRule struct {
+		policy  *PolicyFile
+		module  *ast.Module
+		rule    *ast.Rule
+		queryID string
+	}
+
+	var rules []denyRule
+	opts := []func(*rego.Rego){rego.Input(input)}
+	for _, p := range e.policies {
+		module, err := ast.ParseModuleWithOpts(p.Path, p.Content, ast.ParserOptions{ProcessAnnotation: true})
+		if err != nil {
+			return nil, fmt.Errorf("parse policy %s: %w", p.Name, err)
+		}
+		for _, r := range module.Rules {
+			if r.Head.Ref().String() != "deny" {
+				continue
+			}
+			// Rename before compiling so the rule can be queried on its own
+			queryID := fmt.Sprintf("%s%d", explainRulePrefix, len(rules))
+			rules = append(rules, denyRule{policy: p, module: module, rule: r, queryID: queryID})
+			r.Head.Name = ast.Var(queryID)
+			r.Head.SetRef(ast.Ref{ast.VarTerm(queryID)})
+		}
+		opts = append(opts, rego.ParsedModule(module))
+	}
+
+	var explanations []DenyExplanation
+	for _, dr := range rules {
+		query := fmt.Sprintf("%s.%s", dr.module.Package.Path.String(), dr.queryID)
+		rs, err := rego.New(append([]func(*rego.Rego){rego.Query(query)}, opts...)...).Eval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("evaluate %s: %w", dr.policy.Name, err)
+		}
+
+		var messages []string
+		for _, result := range rs {
+			for _, expr := range result.Expressions {
+				if set, ok := expr.Value.([]any); ok {
+					for _, item := range set {
+						if s, ok := item.(string); ok {
+							messages = append(messages, s)
+						}
+					}
+				}
+			}
+		}
+		if len(messages) == 0 {
+			continue
+		}
+		sort.Strings(messages)
+
+		explanations = append(explanations, DenyExplanation{
+			Policy:      dr.policy.Name,
+			Rule:        ruleTitle(dr.module, dr.rule),
+			Line:        dr.rule.Location.Row,
+			Expression:  strings.TrimSpace(string(dr.rule.Location.Text)), // captured before renaming
+			Messages:    messages,
+			Remediation: ruleRemediation(dr.policy, dr.rule),
+		})
+	}
+
+	return explanations, nil
+}
+
+// ruleTitle names a rule from its METADATA title, else the comment directly above it.
+func ruleTitle(module *ast.Module, rule *ast.Rule) string {
+	for _, a := range rule.Annotations {
+		if a.Title != "" {
+			return a.Title
+		}
+	}
+
+	// Collect the contiguous comment block ending on the line before the rule
+	row := rule.Location.Row - 1
+	var lines []string
+	for i := len(module.Comments) - 1; i >= 0; i-- {
+		c := module.Comments[i]
+		if c.Location.Row > row {
+			continue
+		}
+		if c.Location.Row < row {
+			break
+		}
+		text := strings.TrimSpace(string(c.Text))
+		if text == "" || strings.Trim(text, "═─-=") == "" {
+			break
+		}
+		lines = append([]string{text}, lines...)
+		row--
+	}
+	if len(lines) > 0 {
+		return strings.Join(lines, " ")
+	}
+
+	return fmt.Sprintf("deny (line %d)", rule.Location.Row)
+}
+
+// ruleRemediation returns the rule's custom "remediation" annotation, or a generic hint.
+func ruleRemediation(policy *PolicyFile, rule *ast.Rule) string {
+	for _, a := range rule.Annotations {
+		if r, ok := a.Custom["remediation"].(string); ok && r != "" {
+			return r
+		}
+		if a.Description != "" {
+			return a.Description
+		}
+	}
+	return fmt.Sprintf("Leave this file unchanged, or update policy %q if the change is intentional.", policy.Name)
+}
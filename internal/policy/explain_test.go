@@ -0,0 +1,79 @@
+package policy
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const protectedPathsPolicy = `package taskwing.policy
+
+import rego.v1
+
+# Deny changes to database migrations
+deny contains msg if {
+    some file in input.task.files_modified
+    startswith(file, "db/migrations/")
+    msg := sprintf("BLOCKED: Migration '%s' is immutable", [file])
+}
+
+# METADATA
+# title: Protect CI configuration
+# custom:
+#   remediation: Ask the platform team to change CI workflows.
+deny contains msg if {
+    some file in input.task.files_modified
+    startswith(file, ".github/workflows/")
+    msg := sprintf("BLOCKED: CI workflow '%s' is protected", [file])
+}
+`
+
+func TestEngine_ExplainFile(t *testing.T) {
+	engine := NewEngineWithPolicies(t.TempDir(), []*PolicyFile{
+		{Name: "protected", Path: "protected.rego", Content: protectedPathsPolicy},
+	})
+	ctx := context.Background()
+
+	explanations, err := engine.ExplainFile(ctx, "db/migrations/001_init.sql")
+	if err != nil {
+		t.Fatalf("ExplainFile: %v", err)
+	}
+	if len(explanations) != 1 {
+		t.Fatalf("expected 1 triggering rule, got %d: %+v", len(explanations), explanations)
+	}
+	got := explanations[0]
+	if got.Rule != "Deny changes to database migrations" {
+		t.Errorf("Rule = %q, want the comment above the rule", got.Rule)
+	}
+	if got.Policy != "protected" || got.Line != 6 {
+		t.Errorf("Policy/Line = %s:%d, want protected:6", got.Policy, got.Line)
+	}
+	if !strings.Contains(got.Expression, `startswith(file, "db/migrations/")`) {
+		t.Errorf("Expression should contain the rule body, got %q", got.Expression)
+	}
+	if len(got.Messages) != 1 || !strings.Contains(got.Messages[0], "immutable") {
+		t.Errorf("Messages = %v", got.Messages)
+	}
+	if got.Remediation == "" {
+		t.Error("expected a default remediation")
+	}
+
+	explanations, err = engine.ExplainFile(ctx, ".github/workflows/ci.yml")
+	if err != nil {
+		t.Fatalf("ExplainFile: %v", err)
+	}
+	if len(explanations) != 1 || explanations[0].Rule != "Protect CI configuration" {
+		t.Fatalf("expected the annotated CI rule, got %+v", explanations)
+	}
+	if explanations[0].Remediation != "Ask the platform team to change CI workflows." {
+		t.Errorf("Remediation = %q, want the annotation", explanations[0].Remediation)
+	}
+
+	explanations, err = engine.ExplainFile(ctx, "internal/app/plan.go")
+	if err != nil {
+		t.Fatalf("ExplainFile: %v", err)
+	}
+	if len(explanations) != 0 {
+		t.Errorf("expected no denials for an unprotected file, got %+v", explanations)
+	}
+}
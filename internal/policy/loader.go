@@ -22,6 +22,10 @@ type PolicyFile struct {
 	Name string `json:"name"`
 	// Content is the raw Rego source code.
 	Content string `json:"content"`
+	// Source is where the policy came from: PolicySourceLocal or PolicySourceBundle.
+	Source string `json:"source,omitempty"`
+	// Location is the bundle path or URL for bundle policies.
+	Location string `json:"location,omitempty"`
 }
 
 // Loader scans and loads .rego policy files from the configured directory.
@@ -121,6 +125,7 @@ func (l *Loader) loadFile(path string) (*PolicyFile, error) {
 		Path:    path,
 		Name:    name,
 		Content: string(content),
+		Source:  PolicySourceLocal,
 	}, nil
 }
 
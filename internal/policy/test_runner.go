@@ -13,6 +13,8 @@ import (
 	"github.com/open-policy-agent/opa/v1/tester"
 	"github.com/open-policy-agent/opa/v1/topdown"
 	"github.com/spf13/afero"
+
+	"github.com/josephgoksu/TaskWing/internal/config"
 )
 
 // TestResult represents the result of running a single OPA test.
@@ -97,8 +99,9 @@ func (r *TestRunner) Run(ctx context.Context) (*TestSummary, error) {
 
 	// Ensure custom built-ins are registered
 	builtinCtx := &BuiltinContext{
-		WorkDir: r.workDir,
-		Fs:      r.fs,
+		WorkDir:         r.workDir,
+		Fs:              r.fs,
+		ExcludePatterns: config.LoadExcludeFilePatterns(),
 	}
 	RegisterBuiltins(builtinCtx)
 
@@ -244,8 +247,9 @@ func (r *TestRunner) RunBundle(ctx context.Context, b *bundle.Bundle) (*TestSumm
 
 	// Ensure custom built-ins are registered
 	builtinCtx := &BuiltinContext{
-		WorkDir: r.workDir,
-		Fs:      r.fs,
+		WorkDir:         r.workDir,
+		Fs:              r.fs,
+		ExcludePatterns: config.LoadExcludeFilePatterns(),
 	}
 	RegisterBuiltins(builtinCtx)
 
@@ -302,9 +302,9 @@ func (s *Server) handleClearActivity(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleListPlans
+// handleListPlans lists plans; archived plans require ?include_archived=true.
 func (s *Server) handleListPlans(w http.ResponseWriter, r *http.Request) {
-	plans, err := s.repo.ListPlans()
+	plans, err := s.repo.ListPlans(task.PlanFilter{IncludeArchived: r.URL.Query().Get("include_archived") == "true"})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -59,9 +59,27 @@ func VerifyDAG(tasks []Task) error {
 	return nil
 }
 
+// ErrMissingDependency is returned by a strict sort when a task depends on an
+// ID that matches no task, e.g. a task deleted after the plan was created.
+var ErrMissingDependency = errors.New("missing dependency")
+
+// SortOptions configures TopologicalSortWithOptions.
+type SortOptions struct {
+	// Strict fails with ErrMissingDependency on dangling dependency IDs
+	// instead of ignoring them.
+	Strict bool
+}
+
 // TopologicalSort returns tasks in dependency order (dependencies first).
+// Dependency IDs that match no task are ignored.
 // Returns error if cycle detected.
 func TopologicalSort(tasks []Task) ([]Task, error) {
+	return TopologicalSortWithOptions(tasks, SortOptions{})
+}
+
+// TopologicalSortWithOptions is TopologicalSort with configurable handling of
+// dependency IDs that match no task in the set.
+func TopologicalSortWithOptions(tasks []Task, opts SortOptions) ([]Task, error) {
 	if err := VerifyDAG(tasks); err != nil {
 		return nil, err
 	}
@@ -71,6 +89,16 @@ func TopologicalSort(tasks []Task) ([]Task, error) {
 		taskMap[t.ID] = t
 	}
 
+	if opts.Strict {
+		for _, t := range tasks {
+			for _, depID := range t.Dependencies {
+				if _, exists := taskMap[depID]; !exists {
+					return nil, fmt.Errorf("task %s depends on %s: %w", t.ID, depID, ErrMissingDependency)
+				}
+			}
+		}
+	}
+
 	var sorted []Task
 	visited := make(map[string]bool)
 
@@ -83,6 +111,7 @@ func TopologicalSort(tasks []Task) ([]Task, error) {
 
 		t, exists := taskMap[taskID]
 		if !exists {
+			// Dangling dependency: nothing to order
 			return
 		}
 
@@ -98,3 +127,55 @@ func TopologicalSort(tasks []Task) ([]Task, error) {
 
 	return sorted, nil
 }
+
+// VerifyPhaseDAG checks that every phase dependency names another phase in
+// the set and that the dependencies form no cycle.
+func VerifyPhaseDAG(phases []Phase) error {
+	phaseMap := make(map[string]Phase, len(phases))
+	for _, p := range phases {
+		if p.ID == "" {
+			return errors.New("phase ID cannot be empty")
+		}
+		phaseMap[p.ID] = p
+	}
+	for _, p := range phases {
+		for _, depID := range p.DependsOn {
+			if _, exists := phaseMap[depID]; !exists {
+				return fmt.Errorf("phase %q depends on %s: %w", p.Title, depID, ErrMissingDependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(phases))
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		state[id] = inProgress
+		for _, depID := range phaseMap[id].DependsOn {
+			switch state[depID] {
+			case inProgress:
+				return fmt.Errorf("cycle detected involving phase %q -> %q", phaseMap[id].Title, phaseMap[depID].Title)
+			case unvisited:
+				if err := visit(depID); err != nil {
+					return err
+				}
+			}
+		}
+		state[id] = done
+		return nil
+	}
+
+	for _, p := range phases {
+		if state[p.ID] == unvisited {
+			if err := visit(p.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
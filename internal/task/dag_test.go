@@ -0,0 +1,29 @@
+package task
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTopologicalSort_MissingDependency(t *testing.T) {
+	tasks := []Task{
+		{ID: "task-b", Dependencies: []string{"task-a", "task-deleted"}},
+		{ID: "task-a"},
+	}
+
+	sorted, err := TopologicalSort(tasks)
+	if err != nil {
+		t.Fatalf("TopologicalSort: %v", err)
+	}
+	if len(sorted) != 2 || sorted[0].ID != "task-a" || sorted[1].ID != "task-b" {
+		t.Fatalf("expected [task-a task-b] ignoring the dangling id, got %v", sorted)
+	}
+
+	_, err = TopologicalSortWithOptions(tasks, SortOptions{Strict: true})
+	if !errors.Is(err, ErrMissingDependency) {
+		t.Fatalf("strict sort error = %v, want ErrMissingDependency", err)
+	}
+	if want := "task task-b depends on task-deleted: missing dependency"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
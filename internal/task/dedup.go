@@ -0,0 +1,181 @@
+package task
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultDuplicateThreshold is the word overlap (Jaccard similarity of the
+// normalized title and description) above which two tasks are duplicates.
+const DefaultDuplicateThreshold = 0.8
+
+// DuplicateTask records a task that repeats an earlier one in the same plan.
+type DuplicateTask struct {
+	Index       int     // The later, redundant task
+	DuplicateOf int     // The earlier task it repeats; never itself a duplicate
+	Similarity  float64 // Word overlap in [threshold, 1]
+}
+
+var (
+	dedupNonWord   = regexp.MustCompile(`[^a-z0-9]+`)
+	dedupStopwords = map[string]bool{
+		"a": true, "an": true, "the": true, "and": true, "or": true, "of": true,
+		"to": true, "for": true, "in": true, "on": true, "with": true, "is": true,
+	}
+)
+
+// FindDuplicateTasks compares every pair of tasks by their normalized title
+// and description and reports each later task whose overlap with an earlier
+// one reaches threshold (DefaultDuplicateThreshold when <= 0). A task matching
+// several earlier tasks is reported once, against the most similar.
+func FindDuplicateTasks(tasks []Task, threshold float64) []DuplicateTask {
+	if threshold <= 0 {
+		threshold = DefaultDuplicateThreshold
+	}
+
+	words := make([]map[string]bool, len(tasks))
+	for i, t := range tasks {
+		words[i] = dedupWords(t.Title + " " + t.Description)
+	}
+
+	var dups []DuplicateTask
+	duplicate := make([]bool, len(tasks))
+	for i := range tasks {
+		best := DuplicateTask{Index: i, DuplicateOf: -1}
+		for j := range i {
+			if duplicate[j] {
+				continue // Compare against the task it repeats instead
+			}
+			if sim := jaccard(words[i], words[j]); sim >= threshold && sim > best.Similarity {
+				best.DuplicateOf, best.Similarity = j, sim
+			}
+		}
+		if best.DuplicateOf >= 0 {
+			duplicate[i] = true
+			dups = append(dups, best)
+		}
+	}
+	return dups
+}
+
+// MergeDuplicateTasks removes the duplicates found by FindDuplicateTasks.
+// Each kept task takes on its duplicates' acceptance criteria, expected
+// files, dependencies and the more urgent priority, and dependencies on a
+// removed task are redirected to the task it duplicated. An inherited
+// dependency that would make the kept task depend on itself, directly or
+// through other tasks, is dropped. Tasks must have IDs; the input slice is
+// not modified.
+func MergeDuplicateTasks(tasks []Task, dups []DuplicateTask) []Task {
+	if len(dups) == 0 {
+		return tasks
+	}
+	tasks = slices.Clone(tasks) // Leave the caller's tasks unmerged
+
+	removed := make(map[int]bool, len(dups))
+	replacement := make(map[string]string, len(dups)) // removed ID -> kept ID
+	inherited := make(map[string][]string, len(dups)) // kept ID -> duplicates' dependencies
+	for _, d := range dups {
+		kept, dup := &tasks[d.DuplicateOf], tasks[d.Index]
+		removed[d.Index] = true
+		replacement[dup.ID] = kept.ID
+		inherited[kept.ID] = append(inherited[kept.ID], dup.Dependencies...)
+
+		kept.AcceptanceCriteria = appendMissing(kept.AcceptanceCriteria, dup.AcceptanceCriteria...)
+		kept.ExpectedFiles = appendMissing(kept.ExpectedFiles, dup.ExpectedFiles...)
+		kept.Priority = min(kept.Priority, dup.Priority)
+	}
+	redirect := func(id string) string {
+		if kept, ok := replacement[id]; ok {
+			return kept
+		}
+		return id
+	}
+
+	merged := make([]Task, 0, len(tasks)-len(removed))
+	deps := make(map[string][]string, len(tasks))
+	for i, t := range tasks {
+		if removed[i] {
+			continue
+		}
+		var own []string
+		for _, dep := range t.Dependencies {
+			if dep = redirect(dep); dep != t.ID && !slices.Contains(own, dep) {
+				own = append(own, dep)
+			}
+		}
+		t.Dependencies = own
+		deps[t.ID] = own
+		merged = append(merged, t)
+	}
+
+	// Add inherited dependencies one at a time so each cycle check sees the
+	// edges added before it
+	for i := range merged {
+		t := &merged[i]
+		for _, dep := range inherited[t.ID] {
+			dep = redirect(dep)
+			if dep == t.ID || slices.Contains(t.Dependencies, dep) || dependsOn(deps, dep, t.ID) {
+				continue
+			}
+			t.Dependencies = append(t.Dependencies, dep)
+			deps[t.ID] = t.Dependencies
+		}
+	}
+	return merged
+}
+
+// dependsOn reports whether from reaches to through the dependency edges.
+func dependsOn(deps map[string][]string, from, to string) bool {
+	seen := map[string]bool{from: true}
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, dep := range deps[id] {
+			if dep == to {
+				return true
+			}
+			if !seen[dep] {
+				seen[dep] = true
+				stack = append(stack, dep)
+			}
+		}
+	}
+	return false
+}
+
+// dedupWords returns the lowercase words of s without punctuation or stopwords.
+func dedupWords(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(dedupNonWord.ReplaceAllString(strings.ToLower(s), " ")) {
+		if !dedupStopwords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// jaccard returns |a ∩ b| / |a ∪ b|, or 0 when either set is empty.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// appendMissing appends the values of extra not already in list.
+func appendMissing(list []string, extra ...string) []string {
+	for _, v := range extra {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,33 @@
+package task
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergeDuplicateTasks_DropsInheritedCycle(t *testing.T) {
+	// c repeats a but depends on b, which depends on a
+	tasks := []Task{
+		{ID: "a", Title: "Add JWT middleware", Description: "Validate tokens on every request"},
+		{ID: "b", Title: "Protect admin routes", Description: "Require an admin role", Dependencies: []string{"a"}},
+		{ID: "c", Title: "Add JWT auth middleware", Description: "Validate tokens on every request", Dependencies: []string{"b"}},
+	}
+	dups := FindDuplicateTasks(tasks, DefaultDuplicateThreshold)
+	if len(dups) != 1 || dups[0].Index != 2 || dups[0].DuplicateOf != 0 {
+		t.Fatalf("duplicates = %+v, want c as a duplicate of a", dups)
+	}
+
+	merged := MergeDuplicateTasks(tasks, dups)
+	if len(merged) != 2 {
+		t.Fatalf("got %d tasks, want 2", len(merged))
+	}
+	if err := VerifyDAG(merged); err != nil {
+		t.Fatalf("merged plan is not a DAG: %v", err)
+	}
+	if len(merged[0].Dependencies) != 0 {
+		t.Errorf("a dependencies = %v, want the cyclic b dropped", merged[0].Dependencies)
+	}
+	if !slices.Equal(merged[1].Dependencies, []string{"a"}) {
+		t.Errorf("b dependencies = %v, want [a]", merged[1].Dependencies)
+	}
+}
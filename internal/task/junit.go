@@ -0,0 +1,74 @@
+package task
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+type junitSuite struct {
+	XMLName   xml.Name    `xml:"testsuite"`
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// ToJUnit renders the audit as a JUnit XML test suite for CI systems.
+// Build, test, lint (when run) and semantic checks each become a testcase.
+// A failed audit that no step accounts for is reported as an "audit" error,
+// so a failing report never renders as green.
+func (r AuditReport) ToJUnit() []byte {
+	suite := junitSuite{Name: "taskwing-audit"}
+	if !r.CompletedAt.IsZero() {
+		suite.Timestamp = r.CompletedAt.UTC().Format(time.RFC3339)
+	}
+
+	step := func(name string, failed bool, output string) {
+		c := junitCase{ClassName: "audit", Name: name}
+		if failed {
+			c.Failure = &junitMessage{Message: name + " failed", Body: output}
+			suite.Failures++
+		} else {
+			c.SystemOut = output
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	step("build", r.BuildFailed, r.BuildOutput)
+	step("test", r.TestsFailed, r.TestOutput)
+	if r.LintOutput != "" || r.LintFailed {
+		step("lint", r.LintFailed, r.LintOutput)
+	}
+	// Semantic issues on a passing audit are advisory, not failures
+	semanticFailed := len(r.SemanticIssues) > 0 && !r.Succeeded()
+	step("semantic", semanticFailed, strings.Join(r.SemanticIssues, "\n"))
+
+	if !r.Succeeded() && (suite.Failures == 0 || r.ErrorMessage != "") {
+		msg := r.ErrorMessage
+		if msg == "" {
+			msg = "audit " + r.Status
+		}
+		suite.Cases = append(suite.Cases, junitCase{ClassName: "audit", Name: "audit", Error: &junitMessage{Message: msg}})
+		suite.Errors++
+	}
+	suite.Tests = len(suite.Cases)
+
+	// Marshalling plain string/int fields cannot fail
+	out, _ := xml.MarshalIndent(suite, "", "  ")
+	return append([]byte(xml.Header), append(out, '\n')...)
+}
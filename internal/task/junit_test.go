@@ -0,0 +1,42 @@
+package task
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestAuditReport_ToJUnit(t *testing.T) {
+	report := AuditReport{
+		Status:      "failed",
+		BuildOutput: "main.go:3: undefined: foo",
+		BuildFailed: true,
+		TestOutput:  "ok  example.com/app",
+	}
+
+	var suite junitSuite
+	if err := xml.Unmarshal(report.ToJUnit(), &suite); err != nil {
+		t.Fatalf("ToJUnit produced invalid XML: %v", err)
+	}
+	if suite.Failures != 1 || suite.Tests != len(suite.Cases) {
+		t.Errorf("suite counts = tests %d failures %d, want 1 failure", suite.Tests, suite.Failures)
+	}
+
+	cases := make(map[string]junitCase)
+	for _, c := range suite.Cases {
+		cases[c.Name] = c
+	}
+	build, ok := cases["build"]
+	if !ok || build.Failure == nil {
+		t.Fatalf("build testcase should fail, got %+v", build)
+	}
+	if build.Failure.Body != report.BuildOutput {
+		t.Errorf("build failure body = %q, want build output", build.Failure.Body)
+	}
+	test, ok := cases["test"]
+	if !ok || test.Failure != nil || test.Error != nil {
+		t.Errorf("test testcase should pass, got %+v", test)
+	}
+	if _, ok := cases["lint"]; ok {
+		t.Error("lint testcase should be omitted when no lint ran")
+	}
+}
@@ -23,15 +23,19 @@ const (
 	StatusReady      TaskStatus = "ready"       // Dependencies met, ready for execution
 )
 
+// AgentUnassigned is the agent filter value that selects tasks with no AssignedAgent.
+const AgentUnassigned = "unassigned"
+
 // TaskInput is a caller-provided task definition used to bypass LLM generation.
 // Shared between MCP handlers and the plan app layer.
 type TaskInput struct {
 	Title              string   `json:"title"`
 	Description        string   `json:"description,omitempty"`
 	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
-	ValidationSteps    []string `json:"validation_steps,omitempty"`
+	ValidationSteps    []string `json:"validation_steps,omitempty"` // Commands
 	Priority           int      `json:"priority,omitempty"`
 	Complexity         string   `json:"complexity,omitempty"`
+	SkipAudit          bool     `json:"skip_audit,omitempty"` // Exclude from plan audits (e.g. documentation-only)
 }
 
 // PhaseStatus represents the lifecycle state of a phase
@@ -63,6 +67,21 @@ const (
 	PlanStatusArchived      PlanStatus = "archived"       // No longer active
 )
 
+// Plan list orderings, newest first.
+const (
+	PlanOrderCreated = "created" // By creation time (default)
+	PlanOrderUpdated = "updated" // By last update time
+)
+
+// PlanFilter selects and pages plans for listing.
+type PlanFilter struct {
+	Status          PlanStatus // Only plans with this status (empty = any)
+	IncludeArchived bool       // Include archived plans when Status is empty
+	OrderBy         string     // PlanOrderCreated (default) or PlanOrderUpdated
+	Limit           int        // Maximum plans to return (0 = no limit)
+	Offset          int        // Plans to skip before the page starts
+}
+
 // Phase represents a high-level work chunk in an interactive plan.
 // Phases are created during the "decompose" stage and expanded into tasks during "expand".
 type Phase struct {
@@ -77,10 +96,26 @@ type Phase struct {
 	CreatedAt     time.Time   `json:"created_at"`
 	UpdatedAt     time.Time   `json:"updated_at"`
 
+	// DependsOn lists the IDs of phases in the same plan that must be expanded
+	// first. Phases with no path between them can be expanded in parallel.
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// ExpandAttempts records each expansion of this phase, oldest first.
+	// Re-expanding with feedback replaces the tasks; the history keeps the agent
+	// from repeating rejected attempts.
+	ExpandAttempts []ExpandAttempt `json:"expand_attempts,omitempty"`
+
 	// Computed/joined fields (not stored directly)
 	Tasks []Task `json:"tasks,omitempty"` // Tasks belonging to this phase (when loaded)
 }
 
+// ExpandAttempt is one expansion of a phase into tasks.
+type ExpandAttempt struct {
+	Feedback  string    `json:"feedback,omitempty"` // Feedback the attempt was generated with
+	Tasks     []string  `json:"tasks"`              // Titles of the generated tasks
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // Validate checks if the phase has all required fields and valid data.
 func (p *Phase) Validate() error {
 	if strings.TrimSpace(p.Title) == "" {
@@ -154,24 +189,27 @@ type ClarifyTurn struct {
 
 // Task represents a discrete unit of work to be executed by an agent
 type Task struct {
-	ID                 string     `json:"id"`
-	PlanID             string     `json:"plan_id"`
-	PhaseID            string     `json:"phase_id,omitempty"` // Optional: links task to a phase (interactive mode)
-	Title              string     `json:"title"`
-	Description        string     `json:"description"`
-	Status             TaskStatus `json:"status"`
-	Priority           int        `json:"priority"`   // 0-100 (High to Low)
-	Complexity         string     `json:"complexity"` // "low", "medium", "high"
-	AssignedAgent      string     `json:"assignedAgent"`
-	ParentTaskID       string     `json:"parentTaskId,omitempty"`
-	ContextSummary     string     `json:"contextSummary"` // AI-generated summary of linked nodes
-	AcceptanceCriteria []string   `json:"acceptanceCriteria"`
-	ValidationSteps    []string   `json:"validationSteps"` // CLI commands
+	ID                 string           `json:"id"`
+	PlanID             string           `json:"plan_id"`
+	PhaseID            string           `json:"phase_id,omitempty"` // Optional: links task to a phase (interactive mode)
+	Title              string           `json:"title"`
+	Description        string           `json:"description"`
+	Status             TaskStatus       `json:"status"`
+	Priority           int              `json:"priority"`   // 0-100 (High to Low)
+	Complexity         string           `json:"complexity"` // "low", "medium", "high"
+	AssignedAgent      string           `json:"assignedAgent"`
+	ParentTaskID       string           `json:"parentTaskId,omitempty"`
+	ContextSummary     string           `json:"contextSummary"` // AI-generated summary of linked nodes
+	AcceptanceCriteria []string         `json:"acceptanceCriteria"`
+	ValidationSteps    []ValidationStep `json:"validationSteps"`     // CLI commands or manual checks
+	SkipAudit          bool             `json:"skipAudit,omitempty"` // Excluded from plan audits (e.g. documentation-only tasks)
+	SortOrder          int              `json:"sortOrder,omitempty"` // Orders tasks of equal priority, lowest first
 
 	// AI integration fields - for MCP tool context fetching
 	Scope               string   `json:"scope,omitempty"`               // e.g., "auth", "api", "vectorsearch"
 	Keywords            []string `json:"keywords,omitempty"`            // Extracted from title/description
 	SuggestedAskQueries []string `json:"suggestedAskQueries,omitempty"` // Pre-computed queries for ask tool
+	EnrichmentErrors    int      `json:"enrichmentErrors,omitempty"`    // Ask queries that failed while building ContextSummary
 
 	// Session tracking - for AI tool state management
 	ClaimedBy   string    `json:"claimedBy,omitempty"`   // Session ID that claimed this task
@@ -182,6 +220,10 @@ type Task struct {
 	CompletionSummary string   `json:"completionSummary,omitempty"` // AI-generated summary on completion
 	FilesModified     []string `json:"filesModified,omitempty"`     // Files touched during task (actual)
 
+	// CriteriaVerification holds one result per acceptance criterion when the
+	// task was completed with criteria verification.
+	CriteriaVerification []CriterionVerification `json:"criteriaVerification,omitempty"`
+
 	// Sentinel tracking - for deviation detection
 	ExpectedFiles []string `json:"expectedFiles,omitempty"` // Files plan says should be modified (predicted)
 	GitBaseline   []string `json:"gitBaseline,omitempty"`   // Files already modified when task started (for accurate diff)
@@ -194,6 +236,22 @@ type Task struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// CriterionStatus is the verification outcome for one acceptance criterion.
+type CriterionStatus string
+
+const (
+	CriterionPass      CriterionStatus = "pass"      // The changes show the criterion is met
+	CriterionUnknown   CriterionStatus = "unknown"   // The changes neither confirm nor refute it
+	CriterionUnmatched CriterionStatus = "unmatched" // The verifier returned no verdict naming this criterion
+)
+
+// CriterionVerification records whether a completed task met one acceptance criterion.
+type CriterionVerification struct {
+	Criterion string          `json:"criterion"`
+	Status    CriterionStatus `json:"status"`
+	Evidence  string          `json:"evidence,omitempty"` // Where in the changes the verdict comes from
+}
+
 // Validate checks if the task has all required fields and valid data.
 func (t *Task) Validate() error {
 	if strings.TrimSpace(t.Title) == "" {
@@ -231,6 +289,38 @@ func (t *Task) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// SkipAuditKeyword is the planner keyword that marks a task as excluded from
+// plan audits, for work such as documentation that cannot break the build.
+const SkipAuditKeyword = "skip-audit"
+
+// HasSkipAuditKeyword reports whether keywords contain SkipAuditKeyword.
+func HasSkipAuditKeyword(keywords []string) bool {
+	return hasKeyword(keywords, SkipAuditKeyword)
+}
+
+// hasKeyword reports whether keywords contain keyword, ignoring case and
+// surrounding whitespace.
+func hasKeyword(keywords []string, keyword string) bool {
+	for _, k := range keywords {
+		if strings.EqualFold(strings.TrimSpace(k), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditableTasks returns the tasks that plan audits check, leaving out those
+// marked SkipAudit.
+func AuditableTasks(tasks []Task) []Task {
+	auditable := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		if !t.SkipAudit {
+			auditable = append(auditable, t)
+		}
+	}
+	return auditable
+}
+
 // AuditReport contains the results of an audit run
 type AuditReport struct {
 	Status         string    `json:"status"`         // "passed", "failed", "fixed"
@@ -241,6 +331,31 @@ type AuditReport struct {
 	RetryCount     int       `json:"retryCount"`     // Number of fix attempts made
 	CompletedAt    time.Time `json:"completedAt"`    // When the audit finished
 	ErrorMessage   string    `json:"errorMessage"`   // Error if audit failed to run
+
+	// Per-step outcomes, so reports (e.g. JUnit) can say which check failed.
+	LintOutput  string `json:"lintOutput,omitempty"` // stdout/stderr from lint command, if one ran
+	BuildFailed bool   `json:"buildFailed,omitempty"`
+	TestsFailed bool   `json:"testsFailed,omitempty"`
+	LintFailed  bool   `json:"lintFailed,omitempty"`
+
+	// TrackedFilesHash fingerprints the tracked files when the audit succeeded,
+	// so an unchanged tree can reuse the result instead of re-auditing.
+	TrackedFilesHash string `json:"trackedFilesHash,omitempty"`
+}
+
+// Succeeded reports whether the audit passed, possibly after fixes.
+func (r AuditReport) Succeeded() bool {
+	return r.Status == "passed" || r.Status == "fixed"
+}
+
+// BuildPassed reports whether the audit ran and its build step did not fail.
+func (r AuditReport) BuildPassed() bool {
+	return r.ErrorMessage == "" && !r.BuildFailed
+}
+
+// TestsPassed reports whether the audit ran and its test step did not fail.
+func (r AuditReport) TestsPassed() bool {
+	return r.ErrorMessage == "" && !r.TestsFailed
 }
 
 // Plan represents a collection of tasks to achieve a high-level goal
@@ -96,10 +96,7 @@ Plan Progress: %d%% (%d/%d tasks completed)
 
 	// Render validation steps if present
 	if len(t.ValidationSteps) > 0 {
-		contextStr += "\n### Validation Steps\n"
-		for _, vs := range t.ValidationSteps {
-			contextStr += fmt.Sprintf("- [ ] %s\n", vs)
-		}
+		contextStr += "\n### Validation Steps\n" + FormatValidationSteps(t.ValidationSteps)
 	}
 
 	if askContext != "" {
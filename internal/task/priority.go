@@ -0,0 +1,98 @@
+package task
+
+import "sort"
+
+// priorityStep is the gap between normalized priorities, matching the
+// sequential priorities auto-assigned to explicit plan tasks.
+const priorityStep = 10
+
+// RankPriority returns the priority of the task at 0-based rank among n
+// ordered tasks: 10, 20, 30, ... while that fits in 0-100, otherwise spread
+// evenly up to 100. Beyond 100 tasks neighbours share a priority, so callers
+// also set SortOrder to the rank.
+func RankPriority(rank, n int) int {
+	if n*priorityStep <= 100 {
+		return (rank + 1) * priorityStep
+	}
+	return max((rank+1)*100/n, 1)
+}
+
+// NormalizePriorities reassigns task priorities in place to evenly spaced
+// values (see RankPriority) and sets SortOrder to each task's rank, so the
+// order survives even where priorities tie.
+//
+// Tasks keep their relative order, ties broken by position in the slice, except
+// that a task always ranks after the tasks it depends on. Dependency IDs that
+// match no task are ignored; tasks caught in a cycle are ordered by their
+// original priority.
+func NormalizePriorities(tasks []Task) {
+	n := len(tasks)
+	if n == 0 {
+		return
+	}
+
+	indexByID := make(map[string]int, n)
+	for i, t := range tasks {
+		if t.ID != "" {
+			indexByID[t.ID] = i
+		}
+	}
+	pending := make([]int, n) // unplaced dependencies per task
+	dependents := make([][]int, n)
+	for i, t := range tasks {
+		for _, depID := range t.Dependencies {
+			if j, ok := indexByID[depID]; ok && j != i {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	before := func(a, b int) bool {
+		if tasks[a].Priority != tasks[b].Priority {
+			return tasks[a].Priority < tasks[b].Priority
+		}
+		if tasks[a].SortOrder != tasks[b].SortOrder {
+			return tasks[a].SortOrder < tasks[b].SortOrder
+		}
+		return a < b
+	}
+
+	// Kahn's algorithm, always taking the most urgent ready task
+	var ready []int
+	for i := range tasks {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	placed := make([]bool, n)
+	order := make([]int, 0, n)
+	for len(order) < n {
+		if len(ready) == 0 {
+			// Cycle: release the most urgent unplaced task
+			next := -1
+			for i := range tasks {
+				if !placed[i] && (next < 0 || before(i, next)) {
+					next = i
+				}
+			}
+			ready = append(ready, next)
+		}
+		sort.Slice(ready, func(a, b int) bool { return before(ready[a], ready[b]) })
+		next := ready[0]
+		ready = ready[1:]
+		placed[next] = true
+		order = append(order, next)
+		for _, d := range dependents[next] {
+			pending[d]--
+			if pending[d] == 0 && !placed[d] {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	for rank, i := range order {
+		tasks[i].Priority = RankPriority(rank, n)
+		tasks[i].SortOrder = rank
+	}
+}
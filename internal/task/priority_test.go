@@ -0,0 +1,60 @@
+package task
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNormalizePriorities(t *testing.T) {
+	tasks := []Task{
+		{ID: "task-deploy", Priority: 5, Dependencies: []string{"task-build"}},
+		{ID: "task-build", Priority: 40},
+		{ID: "task-docs", Priority: 40},
+		{ID: "task-lint", Priority: 250},
+		{ID: "task-test", Priority: 40, Dependencies: []string{"task-build", "task-gone"}},
+	}
+
+	NormalizePriorities(tasks)
+
+	// task-deploy would sort first but must follow task-build; the three 40s
+	// keep slice order; the out-of-range 250 lands last.
+	want := map[string]int{"task-build": 10, "task-deploy": 20, "task-docs": 30, "task-test": 40, "task-lint": 50}
+	for _, tk := range tasks {
+		if tk.Priority != want[tk.ID] {
+			t.Errorf("%s priority = %d, want %d", tk.ID, tk.Priority, want[tk.ID])
+		}
+	}
+
+	// Normalizing again is stable
+	NormalizePriorities(tasks)
+	for _, tk := range tasks {
+		if tk.Priority != want[tk.ID] {
+			t.Errorf("second pass: %s priority = %d, want %d", tk.ID, tk.Priority, want[tk.ID])
+		}
+	}
+}
+
+func TestNormalizePriorities_ManyTasksKeepOrder(t *testing.T) {
+	// Listed in reverse so normalizing must reorder them
+	const n = 150
+	tasks := make([]Task, n)
+	for i := range tasks {
+		tasks[i] = Task{ID: fmt.Sprintf("task-%03d", i), Priority: n - i}
+	}
+
+	NormalizePriorities(tasks)
+
+	for i := 1; i < n; i++ {
+		later, earlier := tasks[i-1], tasks[i] // Listed with the higher priority, so ranks after
+		if later.Priority < 1 || later.Priority > 100 {
+			t.Fatalf("%s priority %d out of range", later.ID, later.Priority)
+		}
+		if later.Priority < earlier.Priority || later.SortOrder <= earlier.SortOrder {
+			t.Fatalf("%s (priority %d, order %d) should rank after %s (priority %d, order %d)",
+				later.ID, later.Priority, later.SortOrder, earlier.ID, earlier.Priority, earlier.SortOrder)
+		}
+	}
+	if first := tasks[n-1]; first.SortOrder != 0 {
+		t.Errorf("most urgent task %s has sort order %d, want 0", first.ID, first.SortOrder)
+	}
+}
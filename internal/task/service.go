@@ -13,14 +13,16 @@ import (
 // This interface allows the service to be decoupled from the concrete memory implementation.
 type Repository interface {
 	GetPlan(id string) (*Plan, error)
-	ListPlans() ([]Plan, error)
+	ListPlans(filter PlanFilter) ([]Plan, error)
 	CreatePlan(p *Plan) error
 	UpdatePlan(id, goal, enrichedGoal string, status PlanStatus) error
 	DeletePlan(id string) error
+	MergePlans(targetID, sourceID string) error
 
 	ListTasks(planID string) ([]Task, error)
 	GetTask(id string) (*Task, error)
 	CreateTask(t *Task) error
+	DeleteTask(id string) error
 	UpdateTaskStatus(id string, status TaskStatus) error
 	AddDependency(taskID, dependsOn string) error
 	RemoveDependency(taskID, dependsOn string) error
@@ -43,6 +45,9 @@ type Repository interface {
 	UpdatePhaseStatus(id string, status PhaseStatus) error
 	DeletePhase(id string) error
 	CreatePhasesForPlan(planID string, phases []Phase) error
+	ReorderPhases(planID string, orderedIDs []string) error
+	InsertPhase(planID string, index int, p *Phase) error
+	RecordPhaseExpandAttempt(phaseID string, attempt ExpandAttempt) error
 	ListTasksByPhase(phaseID string) ([]Task, error)
 	GetPlanWithPhases(id string) (*Plan, error)
 	UpdatePlanDraftState(planID string, draftStateJSON string) error
@@ -64,9 +69,9 @@ func NewService(repo Repository, stateDir string) *Service {
 	}
 }
 
-// ResolveLatestPlanID finds the ID of the most recently created plan.
+// ResolveLatestPlanID finds the ID of the most recently created, non-archived plan.
 func (s *Service) ResolveLatestPlanID() (string, error) {
-	plans, err := s.repo.ListPlans()
+	plans, err := s.repo.ListPlans(PlanFilter{})
 	if err != nil {
 		return "", fmt.Errorf("list plans: %w", err)
 	}
@@ -90,9 +95,9 @@ func (s *Service) ResolvePlanID(id string) (string, error) {
 	return id, nil
 }
 
-// ListPlans returns all plans.
+// ListPlans returns all plans, including archived ones.
 func (s *Service) ListPlans() ([]Plan, error) {
-	return s.repo.ListPlans()
+	return s.repo.ListPlans(PlanFilter{IncludeArchived: true})
 }
 
 // SearchPlans filters plans by query and status.
@@ -165,14 +170,27 @@ func (s *Service) RenamePlan(id, newGoal string) error {
 	return s.UpdatePlan(id, newGoal, "", "")
 }
 
-// ArchivePlan sets the status to Archived.
+// ArchivePlan sets the status to Archived. An archived plan keeps its tasks but
+// is hidden from default listings and can no longer be the active plan.
 func (s *Service) ArchivePlan(id string) error {
 	return s.UpdatePlan(id, "", "", PlanStatusArchived)
 }
 
-// UnarchivePlan sets the status to Active.
+// UnarchivePlan restores an archived plan as a draft.
+// It is not made active; use SetActivePlan so that only one plan is ever active.
 func (s *Service) UnarchivePlan(id string) error {
-	return s.UpdatePlan(id, "", "", PlanStatusActive)
+	realID, err := s.ResolvePlanID(id)
+	if err != nil {
+		return err
+	}
+	plan, err := s.repo.GetPlan(realID)
+	if err != nil {
+		return err
+	}
+	if plan.Status != PlanStatusArchived {
+		return fmt.Errorf("plan %s is not archived", realID)
+	}
+	return s.repo.UpdatePlan(realID, "", "", PlanStatusDraft)
 }
 
 // --- Active Plan State Management ---
@@ -236,11 +254,8 @@ func (s *Service) FormatPlanMarkdown(plan *Plan) string {
 
 		if len(t.ValidationSteps) > 0 {
 			buf.WriteString("### Validation\n")
-			buf.WriteString("```bash\n")
-			for _, vs := range t.ValidationSteps {
-				buf.WriteString(fmt.Sprintf("%s\n", vs))
-			}
-			buf.WriteString("```\n\n")
+			buf.WriteString(FormatValidationSteps(t.ValidationSteps))
+			buf.WriteString("\n")
 		}
 	}
 
@@ -0,0 +1,32 @@
+package task
+
+import "slices"
+
+// TestFirstKeyword is the planner keyword that marks a task as writing the
+// failing tests for the implementation task that follows it.
+const TestFirstKeyword = "test-first"
+
+// HasTestFirstKeyword reports whether keywords contain TestFirstKeyword.
+func HasTestFirstKeyword(keywords []string) bool {
+	return hasKeyword(keywords, TestFirstKeyword)
+}
+
+// WireTestFirstDependencies makes the first non-test task after each
+// test-first task depend on it, so implementation never starts before its
+// failing tests exist. Existing dependencies are kept and never duplicated.
+func WireTestFirstDependencies(tasks []Task) {
+	for i := range tasks {
+		if !HasTestFirstKeyword(tasks[i].Keywords) {
+			continue
+		}
+		for j := i + 1; j < len(tasks); j++ {
+			if HasTestFirstKeyword(tasks[j].Keywords) {
+				continue
+			}
+			if !slices.Contains(tasks[j].Dependencies, tasks[i].ID) {
+				tasks[j].Dependencies = append(tasks[j].Dependencies, tasks[i].ID)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ValidationKind distinguishes runnable validation commands from manual checks.
+type ValidationKind string
+
+const (
+	ValidationCommand ValidationKind = "command" // Shell command to run, e.g. "go test ./..."
+	ValidationManual  ValidationKind = "manual"  // Human check, e.g. "verify the UI renders"
+)
+
+// ValidationStep is one way to verify a task.
+// In JSON a command step is a plain string, so plans and tasks stored before
+// steps were typed still load; other kinds are objects {"kind", "text"}.
+type ValidationStep struct {
+	Kind ValidationKind `json:"kind"`
+	Text string         `json:"text"`
+}
+
+// CommandStep returns a command validation step.
+func CommandStep(text string) ValidationStep {
+	return ValidationStep{Kind: ValidationCommand, Text: text}
+}
+
+// ManualStep returns a manual validation step.
+func ManualStep(text string) ValidationStep {
+	return ValidationStep{Kind: ValidationManual, Text: text}
+}
+
+// IsManual reports whether the step is a human check rather than a command.
+func (s ValidationStep) IsManual() bool {
+	return s.Kind == ValidationManual
+}
+
+// String returns the step text.
+func (s ValidationStep) String() string {
+	return s.Text
+}
+
+// MarshalJSON encodes command steps as plain strings and other kinds as objects.
+func (s ValidationStep) MarshalJSON() ([]byte, error) {
+	if !s.IsManual() {
+		return json.Marshal(s.Text)
+	}
+	type plain ValidationStep
+	return json.Marshal(plain(s))
+}
+
+// UnmarshalJSON accepts a plain string (a command) or a {"kind", "text"} object.
+func (s *ValidationStep) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*s = CommandStep(text)
+		return nil
+	}
+	var obj struct {
+		Kind string `json:"kind"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("validation step must be a string or {kind, text} object: %w", err)
+	}
+	*s = ValidationStep{Kind: parseValidationKind(obj.Kind), Text: obj.Text}
+	return nil
+}
+
+// ParseValidationStep converts a decoded JSON value (string or map) into a step.
+// Returns false for values that are neither or have no text.
+func ParseValidationStep(v any) (ValidationStep, bool) {
+	switch val := v.(type) {
+	case string:
+		return CommandStep(val), val != ""
+	case map[string]any:
+		text, _ := val["text"].(string)
+		kind, _ := val["kind"].(string)
+		return ValidationStep{Kind: parseValidationKind(kind), Text: text}, text != ""
+	default:
+		return ValidationStep{}, false
+	}
+}
+
+// parseValidationKind maps a kind name to a ValidationKind; anything other
+// than "manual" is treated as a command.
+func parseValidationKind(kind string) ValidationKind {
+	if strings.EqualFold(strings.TrimSpace(kind), string(ValidationManual)) {
+		return ValidationManual
+	}
+	return ValidationCommand
+}
+
+// CommandValidationSteps wraps each command in a command step.
+func CommandValidationSteps(commands []string) []ValidationStep {
+	if commands == nil {
+		return nil
+	}
+	steps := make([]ValidationStep, len(commands))
+	for i, c := range commands {
+		steps[i] = CommandStep(c)
+	}
+	return steps
+}
+
+// CommandSteps returns the text of each command step, in order.
+func CommandSteps(steps []ValidationStep) []string {
+	var commands []string
+	for _, s := range steps {
+		if !s.IsManual() {
+			commands = append(commands, s.Text)
+		}
+	}
+	return commands
+}
+
+// ReplaceCommandSteps returns steps with the command steps' text replaced, in
+// order, by commands. Manual steps are kept as-is. Used to apply corrections
+// made on the command-only view returned by CommandSteps.
+func ReplaceCommandSteps(steps []ValidationStep, commands []string) []ValidationStep {
+	out := make([]ValidationStep, 0, len(steps))
+	next := 0
+	for _, s := range steps {
+		if !s.IsManual() && next < len(commands) {
+			s.Text = commands[next]
+			next++
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// FormatValidationSteps renders steps as Markdown: commands in a bash code
+// block, manual steps as a checklist.
+func FormatValidationSteps(steps []ValidationStep) string {
+	var sb strings.Builder
+	if commands := CommandSteps(steps); len(commands) > 0 {
+		sb.WriteString("```bash\n")
+		for _, c := range commands {
+			sb.WriteString(c + "\n")
+		}
+		sb.WriteString("```\n")
+	}
+	for _, s := range steps {
+		if s.IsManual() {
+			sb.WriteString("- [ ] " + s.Text + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -10,7 +11,7 @@ import (
 
 // RenderBootstrapResults displays the bootstrap coverage report using the same
 // visual language as tw knowledge (bordered header, dim stats, grouped sections).
-func RenderBootstrapResults(report *agentcore.BootstrapReport) {
+func RenderBootstrapResults(w io.Writer, report *agentcore.BootstrapReport) {
 	// Header box - matches knowledge command style
 	headerBox := lipgloss.NewStyle().
 		Bold(true).
@@ -19,8 +20,8 @@ func RenderBootstrapResults(report *agentcore.BootstrapReport) {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(ColorSecondary)
 
-	fmt.Println()
-	fmt.Println(headerBox.Render(fmt.Sprintf("Bootstrap Results (%d findings)", report.TotalFindings)))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, headerBox.Render(fmt.Sprintf("Bootstrap Results (%d findings)", report.TotalFindings)))
 
 	// Stats line - dim, below header, matches knowledge style
 	var statParts []string
@@ -37,9 +38,9 @@ func RenderBootstrapResults(report *agentcore.BootstrapReport) {
 		statParts = append(statParts, fileStat)
 	}
 	if len(statParts) > 0 {
-		fmt.Printf("  %s\n", StyleSubtle.Render(strings.Join(statParts, "  ")))
+		fmt.Fprintf(w, "  %s\n", StyleSubtle.Render(strings.Join(statParts, "  ")))
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// Agent results - grouped with badges like knowledge sections
 	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorText)
@@ -59,7 +60,7 @@ func RenderBootstrapResults(report *agentcore.BootstrapReport) {
 	// Active agents with findings
 	if len(active) > 0 {
 		label := fmt.Sprintf("Agents with findings (%d)", len(active))
-		fmt.Printf("  %s %s\n", StyleSuccess.Render("✓"), sectionStyle.Render(label))
+		fmt.Fprintf(w, "  %s %s\n", StyleSuccess.Render("✓"), sectionStyle.Render(label))
 		for i, a := range active {
 			findingWord := "findings"
 			if a.report.FindingCount == 1 {
@@ -67,23 +68,23 @@ func RenderBootstrapResults(report *agentcore.BootstrapReport) {
 			}
 			idx := indexStyle.Render(fmt.Sprintf("%d.", i+1))
 			text := fmt.Sprintf("%s: %d %s", a.name, a.report.FindingCount, findingWord)
-			fmt.Printf("    %s %s\n", idx, itemStyle.Render(text))
+			fmt.Fprintf(w, "    %s %s\n", idx, itemStyle.Render(text))
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Skipped agents
 	if len(skipped) > 0 {
 		label := fmt.Sprintf("Skipped (%d)", len(skipped))
-		fmt.Printf("  %s %s\n", skipStyle.Render("-"), skipStyle.Render(label))
+		fmt.Fprintf(w, "  %s %s\n", skipStyle.Render("-"), skipStyle.Render(label))
 		for _, a := range skipped {
 			reason := summarizeSkipReason(a.report.Error)
-			fmt.Printf("    %s\n", skipStyle.Render(fmt.Sprintf("%s: %s", a.name, reason)))
+			fmt.Fprintf(w, "    %s\n", skipStyle.Render(fmt.Sprintf("%s: %s", a.name, reason)))
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
-	fmt.Printf("  %s\n", StyleSubtle.Render("Full report: .taskwing/last-bootstrap-report.json"))
+	fmt.Fprintf(w, "  %s\n", StyleSubtle.Render("Full report: .taskwing/last-bootstrap-report.json"))
 }
 
 type agentEntry struct {
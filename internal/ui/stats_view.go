@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/josephgoksu/TaskWing/internal/app"
+	"github.com/josephgoksu/TaskWing/internal/memory"
+)
+
+// RenderMemoryStats renders knowledge and code index counts as tables.
+func RenderMemoryStats(stats *app.MemoryStatsResult) {
+	knowledgeTable := Table{Headers: []string{"Knowledge", "Count"}}
+	typeOrder := append(memory.AllNodeTypes(), memory.NodeTypeUnknown)
+	var others []string
+	for t := range stats.NodesByType {
+		if !slices.Contains(typeOrder, t) {
+			others = append(others, t)
+		}
+	}
+	slices.Sort(others)
+	for _, t := range append(typeOrder, others...) {
+		if count := stats.NodesByType[t]; count > 0 {
+			knowledgeTable.Rows = append(knowledgeTable.Rows, []string{typePlural(t, count), fmt.Sprintf("%d", count)})
+		}
+	}
+	knowledgeTable.Rows = append(knowledgeTable.Rows, []string{"total", fmt.Sprintf("%d", stats.TotalNodes)})
+	fmt.Println(knowledgeTable.Render())
+
+	codeTable := Table{
+		Headers: []string{"Code Index", "Count"},
+		Rows: [][]string{
+			{"symbols", fmt.Sprintf("%d", stats.SymbolsFound)},
+			{"relations", fmt.Sprintf("%d", stats.RelationsFound)},
+			{"files", fmt.Sprintf("%d", stats.FilesIndexed)},
+		},
+	}
+	fmt.Println(codeTable.Render())
+
+	fmt.Printf("  %s\n", StyleSubtle.Render("Database size: "+formatByteSize(stats.DBSizeBytes)))
+}
+
+// formatByteSize renders a byte count with a binary unit (KiB, MiB, ...).
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
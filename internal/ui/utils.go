@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -17,6 +18,11 @@ func IsInteractive() bool {
 
 // RenderPageHeader displays a consistent styled header for commands
 func RenderPageHeader(title, subtitle string) {
+	RenderPageHeaderTo(os.Stdout, title, subtitle)
+}
+
+// RenderPageHeaderTo writes the RenderPageHeader header to w.
+func RenderPageHeaderTo(w io.Writer, title, subtitle string) {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(ColorPrimary).
@@ -25,9 +31,9 @@ func RenderPageHeader(title, subtitle string) {
 		BorderForeground(ColorSecondary).
 		MarginBottom(1)
 
-	fmt.Println(titleStyle.Render(fmt.Sprintf("🤖 %s", title)))
+	fmt.Fprintln(w, titleStyle.Render(fmt.Sprintf("🤖 %s", title)))
 	if subtitle != "" {
-		fmt.Printf("  ⚡  %s\n", subtitle)
+		fmt.Fprintf(w, "  ⚡  %s\n", subtitle)
 	}
 }
 
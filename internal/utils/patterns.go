@@ -4,7 +4,10 @@ This is the SINGLE source of truth for ignore lists, file extensions, and path p
 */
 package utils
 
-import "strings"
+import (
+	"path/filepath"
+	"strings"
+)
 
 // IgnoredDirs contains directories that should be skipped during traversal.
 var IgnoredDirs = map[string]bool{
@@ -23,6 +26,19 @@ var IgnoredDirs = map[string]bool{
 	".cache":       true, // Various build caches
 }
 
+// GeneratedFilePatterns contains glob patterns for generated source files.
+// Generated code pollutes import graphs and feature analysis, so it is excluded by default.
+var GeneratedFilePatterns = []string{
+	"*.gen.go",
+	"*.pb.go",
+	"*.pb.gw.go",
+	"*_generated.go",
+	"zz_generated*.go",
+	"*.generated.ts",
+	"*_pb2.py",
+	"*_pb2_grpc.py",
+}
+
 // AllowedDotDirs contains dot-directories that SHOULD be analyzed (exceptions to the dot-skip rule).
 // These often contain important architectural decisions (CI/CD, IDE configs, etc.)
 var AllowedDotDirs = map[string]bool{
@@ -61,6 +77,23 @@ func ShouldIgnoreDir(name string) bool {
 	return IgnoredDirs[name]
 }
 
+// MatchesFilePattern returns true if the path matches any glob pattern.
+// Patterns without a separator match the base name; others match the slash-separated path.
+func MatchesFilePattern(path string, patterns []string) bool {
+	slashPath := filepath.ToSlash(path)
+	base := filepath.Base(path)
+	for _, pattern := range patterns {
+		target := base
+		if strings.Contains(pattern, "/") {
+			target = slashPath
+		}
+		if matched, _ := filepath.Match(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // ShouldSkipDotEntry returns true if a dot-prefixed entry should be skipped.
 // Returns false for allowed dot-directories and important dotfiles.
 func ShouldSkipDotEntry(name string, isDir bool) bool {
@@ -119,6 +152,13 @@ func IsConfigFile(name, ext string) bool {
 	return ConfigExtensions[ext] || ConfigFiles[name]
 }
 
+// IsDocFile returns true if the project-relative path is documentation: a
+// markdown file or any file under docs/.
+func IsDocFile(path string) bool {
+	path = filepath.ToSlash(path)
+	return strings.EqualFold(filepath.Ext(path), ".md") || strings.HasPrefix(path, "docs/")
+}
+
 // IsDependencyFile returns true if the filename is a dependency manifest.
 func IsDependencyFile(name string) bool {
 	return DependencyFiles[name]